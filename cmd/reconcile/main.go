@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"github.com/Asif-Faizal/SpiceLedger-Backend/market"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+)
+
+// reconcile replays the market ledger and reports positions and lot remainders that
+// no longer match it. Exits 1 when drift is found and -rebuild was not given.
+func main() {
+	config := util.LoadConfig()
+	userID := flag.String("user", "", "limit to one account id")
+	gradeID := flag.String("grade", "", "limit to one spice grade id")
+	rebuild := flag.Bool("rebuild", false, "overwrite drifting positions and lot remainders in one transaction")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	logger := util.NewLogger(config.LogLevel)
+
+	repo, err := market.NewMysqlRepository(config.DSN(), logger)
+	if err != nil {
+		log.Fatalf("open database: %v", err)
+	}
+	defer repo.Close()
+
+	valuation, err := market.NewValuationPolicy(config.PriceValuation, config.PriceMaxAgeDays)
+	if err != nil {
+		log.Fatalf("price valuation: %v", err)
+	}
+	service := market.NewMarketService(repo, nil, valuation, logger)
+	report, err := service.ReconcileLedger(context.Background(), *userID, *gradeID, *rebuild)
+	if err != nil {
+		log.Fatalf("reconcile: %v", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatalf("encode report: %v", err)
+		}
+	} else {
+		printReport(report)
+	}
+
+	if !report.Rebuilt && (len(report.Positions) > 0 || len(report.Lots) > 0) {
+		os.Exit(1)
+	}
+}
+
+func printReport(report *market.ReconciliationReport) {
+	fmt.Printf("checked %d positions, %d lots: %d position drifts, %d lot drifts\n",
+		report.PositionsChecked, report.LotsChecked, len(report.Positions), len(report.Lots))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if len(report.Positions) > 0 {
+		fmt.Fprintln(w, "\nUSER\tGRADE\tCURRENCY stored/expected\tQTY stored/expected\tCOST stored/expected\tREALIZED stored/expected\t")
+		for _, d := range report.Positions {
+			stored := d.Stored.TotalQty.String()
+			if d.Missing {
+				stored = "missing"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s / %s\t%s / %s\t%s / %s\t%s / %s\t\n",
+				d.UserID, d.SpiceGradeID,
+				d.Stored.Currency, d.Expected.Currency,
+				stored, d.Expected.TotalQty,
+				d.Stored.TotalCost, d.Expected.TotalCost,
+				d.Stored.RealizedPnL, d.Expected.RealizedPnL)
+		}
+	}
+	if len(report.Lots) > 0 {
+		fmt.Fprintln(w, "\nLOT\tKIND\tUSER\tGRADE\tREMAINING stored/expected\t")
+		for _, d := range report.Lots {
+			kind := "buy"
+			if d.Short {
+				kind = "short"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s / %s\t\n",
+				d.LotID, kind, d.UserID, d.SpiceGradeID, d.StoredRemaining, d.ExpectedRemaining)
+		}
+	}
+	w.Flush()
+
+	if report.Rebuilt {
+		fmt.Println("rebuilt: drifting rows now match the ledger")
+	}
+}
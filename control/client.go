@@ -4,6 +4,8 @@ import (
 	"context"
 
 	pb "github.com/Asif-Faizal/SpiceLedger-Backend/control/pb"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+	"github.com/shopspring/decimal"
 	"google.golang.org/grpc"
 )
 
@@ -27,6 +29,39 @@ func (client *ControlClient) Close() {
 	client.connection.Close()
 }
 
+// SigningKeys fetches the public keys tokens are verified against. It is the fetch function
+// of a util.KeySet.
+func (client *ControlClient) SigningKeys(ctx context.Context) ([]util.JWK, error) {
+	response, err := client.client.GetJWKS(ctx, &pb.GetJWKSRequest{})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]util.JWK, 0, len(response.Keys))
+	for _, key := range response.Keys {
+		keys = append(keys, util.JWK{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+	return keys, nil
+}
+
+// CheckSession reports whether an access token's session is still signed in. It is the check
+// function of a util.SessionCache.
+func (client *ControlClient) CheckSession(ctx context.Context, accessToken string) (bool, error) {
+	response, err := client.client.CheckSession(ctx, &pb.CheckSessionRequest{AccessToken: accessToken})
+	if err != nil {
+		return false, err
+	}
+	return response.Active, nil
+}
+
 func (client *ControlClient) CheckEmailExists(ctx context.Context, email string) (*pb.CheckEmailExistsResponse, error) {
 	response, err := client.client.CheckEmailExists(ctx, &pb.CheckEmailExistsRequest{
 		Email: email,
@@ -38,13 +73,15 @@ func (client *ControlClient) CheckEmailExists(ctx context.Context, email string)
 }
 
 // CreateOrUpdate Account
-func (client *ControlClient) CreateOrUpdateAccount(ctx context.Context, id, name, userType, email, password string) (*pb.CreateOrUpdateAccountResponse, error) {
+func (client *ControlClient) CreateOrUpdateAccount(ctx context.Context, id, name, userType, email, password, currency, reportingCurrency string) (*pb.CreateOrUpdateAccountResponse, error) {
 	response, err := client.client.CreateOrUpdateAccount(ctx, &pb.CreateOrUpdateAccountRequest{
-		Id:       id,
-		Name:     name,
-		Usertype: userType,
-		Email:    email,
-		Password: password,
+		Id:                id,
+		Name:              name,
+		Usertype:          userType,
+		Email:             email,
+		Password:          password,
+		Currency:          currency,
+		ReportingCurrency: reportingCurrency,
 	})
 	if err != nil {
 		return nil, err
@@ -83,11 +120,12 @@ func (client *ControlClient) ListAccounts(ctx context.Context, skip uint32, take
 	return response, nil
 }
 
-func (client *ControlClient) Login(ctx context.Context, email, password, deviceID string) (*pb.LoginResponse, error) {
+func (client *ControlClient) Login(ctx context.Context, email, password, deviceID, deviceName string) (*pb.LoginResponse, error) {
 	response, err := client.client.Login(ctx, &pb.LoginRequest{
-		Email:    email,
-		Password: password,
-		DeviceId: deviceID,
+		Email:      email,
+		Password:   password,
+		DeviceId:   deviceID,
+		DeviceName: deviceName,
 	})
 	if err != nil {
 		return nil, err
@@ -117,6 +155,131 @@ func (client *ControlClient) RefreshToken(ctx context.Context, refreshToken, dev
 	return response, nil
 }
 
+func (client *ControlClient) ListSessions(ctx context.Context) (*pb.ListSessionsResponse, error) {
+	response, err := client.client.ListSessions(ctx, &pb.ListSessionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) RevokeSession(ctx context.Context, sessionID string) (*pb.RevokeSessionResponse, error) {
+	response, err := client.client.RevokeSession(ctx, &pb.RevokeSessionRequest{SessionId: sessionID})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) RevokeAllOtherSessions(ctx context.Context) (*pb.RevokeSessionsResponse, error) {
+	response, err := client.client.RevokeAllOtherSessions(ctx, &pb.RevokeAllOtherSessionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) ForceLogout(ctx context.Context, accountID string) (*pb.RevokeSessionsResponse, error) {
+	response, err := client.client.ForceLogout(ctx, &pb.ForceLogoutRequest{AccountId: accountID})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) RequestPasswordReset(ctx context.Context, email string) (*pb.RequestPasswordResetResponse, error) {
+	response, err := client.client.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: email})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) ConfirmPasswordReset(ctx context.Context, token string, newPassword string, revokeSessions bool) (*pb.ConfirmPasswordResetResponse, error) {
+	response, err := client.client.ConfirmPasswordReset(ctx, &pb.ConfirmPasswordResetRequest{
+		Token:          token,
+		NewPassword:    newPassword,
+		RevokeSessions: revokeSessions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) SendVerificationEmail(ctx context.Context) (*pb.SendVerificationEmailResponse, error) {
+	response, err := client.client.SendVerificationEmail(ctx, &pb.SendVerificationEmailRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) VerifyEmail(ctx context.Context, token string) (*pb.VerifyEmailResponse, error) {
+	response, err := client.client.VerifyEmail(ctx, &pb.VerifyEmailRequest{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) EnrollTOTP(ctx context.Context, challengeToken string, deviceID string) (*pb.EnrollTOTPResponse, error) {
+	response, err := client.client.EnrollTOTP(ctx, &pb.EnrollTOTPRequest{
+		ChallengeToken: challengeToken,
+		DeviceId:       deviceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) ConfirmTOTP(ctx context.Context, code string, challengeToken string, deviceID string, deviceName string) (*pb.ConfirmTOTPResponse, error) {
+	response, err := client.client.ConfirmTOTP(ctx, &pb.ConfirmTOTPRequest{
+		Code:           code,
+		ChallengeToken: challengeToken,
+		DeviceId:       deviceID,
+		DeviceName:     deviceName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) VerifyLoginChallenge(ctx context.Context, challengeToken string, deviceID string, deviceName string, code string, recoveryCode string) (*pb.LoginResponse, error) {
+	response, err := client.client.VerifyLoginChallenge(ctx, &pb.VerifyLoginChallengeRequest{
+		ChallengeToken: challengeToken,
+		DeviceId:       deviceID,
+		DeviceName:     deviceName,
+		Code:           code,
+		RecoveryCode:   recoveryCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) DisableTOTP(ctx context.Context, code string, recoveryCode string) (*pb.DisableTOTPResponse, error) {
+	response, err := client.client.DisableTOTP(ctx, &pb.DisableTOTPRequest{
+		Code:         code,
+		RecoveryCode: recoveryCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) RegenerateRecoveryCodes(ctx context.Context, code string) (*pb.RegenerateRecoveryCodesResponse, error) {
+	response, err := client.client.RegenerateRecoveryCodes(ctx, &pb.RegenerateRecoveryCodesRequest{Code: code})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 func (client *ControlClient) CreateOrUpdateMerchantDetails(ctx context.Context, id, accountID, phone, address, city, state, pincode string) (*pb.CreateOrUpdateMerchantDetailsResponse, error) {
 	response, err := client.client.CreateOrUpdateMerchantDetails(ctx, &pb.CreateOrUpdateMerchantDetailsRequest{
 		Id:          id,
@@ -184,13 +347,16 @@ func (client *ControlClient) ListProducts(ctx context.Context, skip uint32, take
 	return response, nil
 }
 
-func (client *ControlClient) CreateOrUpdateGrade(ctx context.Context, id, productID, name, description, status string) (*pb.CreateOrUpdateGradeResponse, error) {
+func (client *ControlClient) CreateOrUpdateGrade(ctx context.Context, id, productID, name, description, status string, shelfLifeDays uint32, unit string, kgPerUnit decimal.Decimal) (*pb.CreateOrUpdateGradeResponse, error) {
 	response, err := client.client.CreateOrUpdateGrade(ctx, &pb.CreateOrUpdateGradeRequest{
-		Id:          id,
-		ProductId:   productID,
-		Name:        name,
-		Description: description,
-		Status:      status,
+		Id:            id,
+		ProductId:     productID,
+		Name:          name,
+		Description:   description,
+		Status:        status,
+		ShelfLifeDays: shelfLifeDays,
+		Unit:          unit,
+		KgPerUnit:     kgPerUnit.String(),
 	})
 	if err != nil {
 		return nil, err
@@ -210,14 +376,51 @@ func (client *ControlClient) ListGradesByProductId(ctx context.Context, productI
 	return response, nil
 }
 
-func (client *ControlClient) CreateOrUpdateDailyPrice(ctx context.Context, id, productID, gradeID string, price float64, date, time string) (*pb.CreateOrUpdateDailyPriceResponse, error) {
+func (client *ControlClient) CreateOrUpdateDailyPrice(ctx context.Context, id, productID, gradeID string, price decimal.Decimal, currency, date, time, source string, draft bool) (*pb.CreateOrUpdateDailyPriceResponse, error) {
 	response, err := client.client.CreateOrUpdateDailyPrice(ctx, &pb.CreateOrUpdateDailyPriceRequest{
 		Id:        id,
 		ProductId: productID,
 		GradeId:   gradeID,
-		Price:     price,
+		Price:     price.String(),
+		Currency:  currency,
 		Date:      date,
 		Time:      time,
+		Source:    source,
+		Draft:     draft,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) SubmitPriceTick(ctx context.Context, id string) (*pb.SubmitPriceTickResponse, error) {
+	response, err := client.client.SubmitPriceTick(ctx, &pb.SubmitPriceTickRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) ReviewPriceTicks(ctx context.Context, ids []string, decision string, note string) (*pb.ReviewPriceTicksResponse, error) {
+	response, err := client.client.ReviewPriceTicks(ctx, &pb.ReviewPriceTicksRequest{
+		Ids:      ids,
+		Decision: decision,
+		Note:     note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// CreateOrUpdateDailyPrices imports a sheet of prices in one transaction and returns the
+// row-by-row report. An empty maxMovePercent uses the server's outlier limit.
+func (client *ControlClient) CreateOrUpdateDailyPrices(ctx context.Context, prices []*pb.CreateOrUpdateDailyPriceRequest, maxMovePercent string, dryRun bool) (*pb.CreateOrUpdateDailyPricesResponse, error) {
+	response, err := client.client.CreateOrUpdateDailyPrices(ctx, &pb.CreateOrUpdateDailyPricesRequest{
+		Prices:         prices,
+		MaxMovePercent: maxMovePercent,
+		DryRun:         dryRun,
 	})
 	if err != nil {
 		return nil, err
@@ -237,10 +440,35 @@ func (client *ControlClient) ListDailyPrices(ctx context.Context, gradeID string
 	return response, nil
 }
 
-func (client *ControlClient) GetTodaysPrice(ctx context.Context, gradeID string, date string) (*pb.GetTodaysPriceResponse, error) {
+func (client *ControlClient) GetTodaysPrice(ctx context.Context, gradeID string, date string, priceBasis string) (*pb.GetTodaysPriceResponse, error) {
 	response, err := client.client.GetTodaysPrice(ctx, &pb.GetTodaysPriceRequest{
+		GradeId:    gradeID,
+		Date:       date,
+		PriceBasis: priceBasis,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) GetTodaysByProductId(ctx context.Context, productID string, date string, priceBasis string) (*pb.GetTodaysByProductIdResponse, error) {
+	response, err := client.client.GetTodaysByProductId(ctx, &pb.GetTodaysByProductIdRequest{
+		ProductId:  productID,
+		Date:       date,
+		PriceBasis: priceBasis,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) ListPriceTicks(ctx context.Context, gradeID string, date string, status string) (*pb.ListPriceTicksResponse, error) {
+	response, err := client.client.ListPriceTicks(ctx, &pb.ListPriceTicksRequest{
 		GradeId: gradeID,
 		Date:    date,
+		Status:  status,
 	})
 	if err != nil {
 		return nil, err
@@ -248,10 +476,42 @@ func (client *ControlClient) GetTodaysPrice(ctx context.Context, gradeID string,
 	return response, nil
 }
 
-func (client *ControlClient) GetTodaysByProductId(ctx context.Context, productID string, date string) (*pb.GetTodaysByProductIdResponse, error) {
-	response, err := client.client.GetTodaysByProductId(ctx, &pb.GetTodaysByProductIdRequest{
-		ProductId: productID,
-		Date:      date,
+func (client *ControlClient) SetFxRate(ctx context.Context, baseCurrency, quoteCurrency string, rate decimal.Decimal, effectiveDate string) (*pb.SetFxRateResponse, error) {
+	response, err := client.client.SetFxRate(ctx, &pb.SetFxRateRequest{
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+		Rate:          rate.String(),
+		EffectiveDate: effectiveDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) ListFxRates(ctx context.Context, baseCurrency, quoteCurrency string) (*pb.ListFxRatesResponse, error) {
+	response, err := client.client.ListFxRates(ctx, &pb.ListFxRatesRequest{
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) SetFeeSchedule(ctx context.Context, request *pb.SetFeeScheduleRequest) (*pb.SetFeeScheduleResponse, error) {
+	response, err := client.client.SetFeeSchedule(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (client *ControlClient) ListFeeSchedules(ctx context.Context, gradeID, category string) (*pb.ListFeeSchedulesResponse, error) {
+	response, err := client.client.ListFeeSchedules(ctx, &pb.ListFeeSchedulesRequest{
+		GradeId:  gradeID,
+		Category: category,
 	})
 	if err != nil {
 		return nil, err
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/Asif-Faizal/SpiceLedger-Backend/control"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/internal/platform"
 	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/shopspring/decimal"
 )
 
 func main() {
@@ -22,16 +25,46 @@ func main() {
 	}
 	defer repo.Close()
 
-	// 4. Initialize Service
+	// 4. Open the JWT signing keys and rotate them on schedule
+	keys, err := control.NewKeyRing(config.JWTKeyDir, config.JWTAlgorithm, config.JWTKeyRotation, config.JWTKeyGrace, logger)
+	if err != nil {
+		log.Fatalf("could not open signing keys: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go keys.Run(ctx, control.KeyRingCheckInterval)
+
+	// 5. Initialize Service
+	mailer, err := platform.NewMailer(config, logger)
+	if err != nil {
+		log.Fatalf("could not set up mailer: %v", err)
+	}
+	totpKey, err := util.ParseEncryptionKey(config.TOTPEncryptionKey)
+	if err != nil {
+		log.Fatalf("TOTP_ENCRYPTION_KEY: %v", err)
+	}
 	accountService := control.NewAccountService(
 		repo,
-		config.JWTSecret,
+		keys,
 		config.AccessTokenDuration,
 		config.RefreshTokenDuration,
+		platform.NewEventBus(config.EventRetention),
+		decimal.NewFromFloat(config.PriceMaxMovePercent),
+		mailer,
+		config.AppURL,
+		config.PasswordResetTTL,
+		config.EmailVerificationTTL,
+		control.TwoFactorPolicy{
+			Issuer:            config.TOTPIssuer,
+			EncryptionKey:     totpKey,
+			RequiredForAdmins: config.TOTPRequiredForAdmin,
+			ChallengeTTL:      config.LoginChallengeTTL,
+		},
+		logger,
 	)
 
-	// 5. Start gRPC Server
-	if err := control.ListenGrpcServer(accountService, logger, config); err != nil {
+	// 6. Start gRPC Server
+	if err := control.ListenGrpcServer(accountService, keys, logger, config); err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 }
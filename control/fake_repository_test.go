@@ -0,0 +1,165 @@
+package control
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"time"
+)
+
+// nopDriver hands out transactions that do nothing, so BeginTx can return a real *sql.Tx.
+// The fake repository does not undo writes on rollback.
+type nopDriver struct{}
+
+func (nopDriver) Open(name string) (driver.Conn, error) { return nopConn{}, nil }
+
+type nopConn struct{}
+
+func (nopConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("nop driver runs no statements")
+}
+func (nopConn) Close() error              { return nil }
+func (nopConn) Begin() (driver.Tx, error) { return nopTx{}, nil }
+
+type nopTx struct{}
+
+func (nopTx) Commit() error   { return nil }
+func (nopTx) Rollback() error { return nil }
+
+var nopDB = func() *sql.DB {
+	sql.Register("control-nop", nopDriver{})
+	db, err := sql.Open("control-nop", "")
+	if err != nil {
+		panic(err)
+	}
+	return db
+}()
+
+// fakeRepository keeps accounts, sessions and refresh tokens in memory for the session paths.
+// Any other Repository method panics through the nil embedded interface.
+type fakeRepository struct {
+	Repository
+
+	mu       sync.Mutex
+	accounts map[string]*Account
+	sessions map[string]*Session // by ID
+	tokens   map[string]*RefreshToken
+	// beforeRotate runs inside RotateRefreshToken, to let a test win the race for a token.
+	beforeRotate func(token *RefreshToken)
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		accounts: map[string]*Account{},
+		sessions: map[string]*Session{},
+		tokens:   map[string]*RefreshToken{},
+	}
+}
+
+func (f *fakeRepository) BeginTx(ctx context.Context) (context.Context, *sql.Tx, error) {
+	tx, err := nopDB.BeginTx(ctx, nil)
+	return ctx, tx, err
+}
+
+func (f *fakeRepository) GetAccountById(ctx context.Context, id string) (*Account, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	account, ok := f.accounts[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *account
+	return &copied, nil
+}
+
+// CreateOrUpdateSession upserts by ID, like the MySQL ON DUPLICATE KEY UPDATE.
+func (f *fakeRepository) CreateOrUpdateSession(ctx context.Context, session *Session) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := *session
+	f.sessions[session.ID] = &stored
+	return nil
+}
+
+func (f *fakeRepository) GetSessionByFamily(ctx context.Context, familyID string) (*Session, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range f.sessions {
+		if s.FamilyID == familyID && !s.IsRevoked {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeRepository) RevokeDeviceSessions(ctx context.Context, accountID string, deviceID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range f.sessions {
+		if s.AccountID == accountID && s.DeviceID == deviceID {
+			s.IsRevoked = true
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepository) CreateRefreshToken(ctx context.Context, token *RefreshToken) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := *token
+	f.tokens[token.ID] = &stored
+	return nil
+}
+
+func (f *fakeRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range f.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// RotateRefreshToken reports false when the token was already rotated or revoked, like the
+// guarded UPDATE in MySQL.
+func (f *fakeRepository) RotateRefreshToken(ctx context.Context, id string, at time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := f.tokens[id]
+	if f.beforeRotate != nil {
+		f.beforeRotate(t)
+	}
+	if !t.RotatedAt.IsZero() || !t.RevokedAt.IsZero() {
+		return false, nil
+	}
+	t.RotatedAt = at
+	return true, nil
+}
+
+func (f *fakeRepository) RevokeRefreshFamily(ctx context.Context, familyID string, reason string, at time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range f.tokens {
+		if t.FamilyID == familyID && t.RevokedAt.IsZero() {
+			t.RevokedAt, t.RevokeReason = at, reason
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepository) RevokeDeviceRefreshTokens(ctx context.Context, accountID string, deviceID string, reason string, at time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range f.tokens {
+		if t.AccountID == accountID && t.DeviceID == deviceID && t.RevokedAt.IsZero() {
+			t.RevokedAt, t.RevokeReason = at, reason
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package control
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+)
+
+// KeyRingCheckInterval is how often the key ring rereads its directory and rotates when due.
+const KeyRingCheckInterval = time.Minute
+
+// KeyRing holds the JWT signing keys. The private keys live only in its directory, which
+// only the control service mounts; the other services verify tokens against the public keys
+// it publishes through GetJWKS.
+type KeyRing struct {
+	dir       string
+	algorithm string
+	rotation  time.Duration
+	grace     time.Duration
+	logger    util.Logger
+
+	mu        sync.RWMutex
+	signer    *util.SigningKey
+	published map[string]publishedKey
+	jwks      []util.JWK
+}
+
+type publishedKey struct {
+	key       crypto.PublicKey
+	algorithm string
+}
+
+// NewKeyRing opens the key directory, creating it and a first key if needed.
+func NewKeyRing(dir string, algorithm string, rotation time.Duration, grace time.Duration, logger util.Logger) (*KeyRing, error) {
+	if algorithm != util.AlgorithmRS256 && algorithm != util.AlgorithmEdDSA {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	ring := &KeyRing{
+		dir:       dir,
+		algorithm: algorithm,
+		rotation:  rotation,
+		grace:     grace,
+		logger:    logger,
+	}
+	if err := ring.Rotate(time.Now()); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+// Run rotates the keys on schedule until ctx is done.
+func (ring *KeyRing) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := ring.Rotate(now); err != nil {
+				ring.logger.Security().Error().Err(err).Msg("Signing key rotation failed")
+			}
+		}
+	}
+}
+
+// Rotate rereads the directory and starts a new signing key once the current one is older
+// than the rotation period or uses another algorithm. Keys older than the signing key are
+// retired with the grace period and deleted once it has passed. Rereading also picks up a
+// key started by another replica sharing the directory.
+func (ring *KeyRing) Rotate(now time.Time) error {
+	keys, err := ring.load()
+	if err != nil {
+		return err
+	}
+
+	active := activeSigningKey(keys)
+	if active == nil || !active.CreatedAt.Add(ring.rotation).After(now) || active.Algorithm != ring.algorithm {
+		generated, err := util.GenerateSigningKey(ring.algorithm)
+		if err != nil {
+			return err
+		}
+		encoded, err := util.EncodePrivateKey(generated.PrivateKey)
+		if err != nil {
+			return err
+		}
+		active = &SigningKey{
+			KID:        generated.KID,
+			Algorithm:  generated.Algorithm,
+			PrivateKey: string(encoded),
+			CreatedAt:  now.UTC(),
+		}
+		if err := ring.save(active); err != nil {
+			return err
+		}
+		keys = append(keys, active)
+		ring.logger.Security().Info().Str("event", "signing_key_rotated").Str("kid", active.KID).Str("alg", active.Algorithm).Msg("Started a new JWT signing key")
+	}
+
+	var signer *util.SigningKey
+	published := map[string]publishedKey{}
+	var jwks []util.JWK
+	for _, key := range keys {
+		if key != active && key.RetiredAt.IsZero() && key.CreatedAt.Before(active.CreatedAt) {
+			key.RetiredAt = now.UTC()
+			key.ExpiresAt = now.Add(ring.grace).UTC()
+			if err := ring.save(key); err != nil {
+				return err
+			}
+		}
+		if !key.RetiredAt.IsZero() && !key.ExpiresAt.After(now) {
+			if err := os.Remove(ring.path(key.KID)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			ring.logger.Security().Info().Str("event", "signing_key_expired").Str("kid", key.KID).Msg("Dropped an expired JWT signing key")
+			continue
+		}
+
+		privateKey, err := util.DecodePrivateKey([]byte(key.PrivateKey))
+		if err != nil {
+			return fmt.Errorf("signing key %s: %w", key.KID, err)
+		}
+		jwk, err := util.NewJWK(key.KID, key.Algorithm, privateKey.Public())
+		if err != nil {
+			return err
+		}
+		published[key.KID] = publishedKey{key: privateKey.Public(), algorithm: key.Algorithm}
+		jwks = append(jwks, jwk)
+		if key == active {
+			signer = &util.SigningKey{KID: key.KID, Algorithm: key.Algorithm, PrivateKey: privateKey}
+		}
+	}
+
+	ring.mu.Lock()
+	ring.signer = signer
+	ring.published = published
+	ring.jwks = jwks
+	ring.mu.Unlock()
+	return nil
+}
+
+// Signer returns the key new tokens are signed with.
+func (ring *KeyRing) Signer() *util.SigningKey {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+	return ring.signer
+}
+
+// PublicKey implements util.KeyResolver.
+func (ring *KeyRing) PublicKey(ctx context.Context, kid string) (crypto.PublicKey, string, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+	published, ok := ring.published[kid]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s", util.ErrUnknownKey, kid)
+	}
+	return published.key, published.algorithm, nil
+}
+
+// JWKS returns the public half of every key that is signing or within its grace period.
+func (ring *KeyRing) JWKS() []util.JWK {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+	return ring.jwks
+}
+
+// activeSigningKey is the newest key that has not been retired.
+func activeSigningKey(keys []*SigningKey) *SigningKey {
+	var active *SigningKey
+	for _, key := range keys {
+		if key.RetiredAt.IsZero() && (active == nil || key.CreatedAt.After(active.CreatedAt)) {
+			active = key
+		}
+	}
+	return active
+}
+
+func (ring *KeyRing) path(kid string) string {
+	return filepath.Join(ring.dir, kid+".json")
+}
+
+func (ring *KeyRing) load() ([]*SigningKey, error) {
+	entries, err := os.ReadDir(ring.dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []*SigningKey
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(ring.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var key SigningKey
+		if err := json.Unmarshal(data, &key); err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", entry.Name(), err)
+		}
+		keys = append(keys, &key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+// save writes the key through a temporary file so a reader never sees it half written.
+func (ring *KeyRing) save(key *SigningKey) error {
+	data, err := json.MarshalIndent(key, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(ring.dir, ".key-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), ring.path(key.KID))
+}
@@ -0,0 +1,111 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+)
+
+func publishedKIDs(ring *KeyRing) []string {
+	var kids []string
+	for _, jwk := range ring.JWKS() {
+		kids = append(kids, jwk.Kid)
+	}
+	sort.Strings(kids)
+	return kids
+}
+
+func sameKIDs(got []string, want ...string) bool {
+	sort.Strings(want)
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestKeyRingRotationAndGrace(t *testing.T) {
+	const (
+		rotation = 24 * time.Hour
+		grace    = 48 * time.Hour
+	)
+	ctx := context.Background()
+	dir := t.TempDir()
+	ring, err := NewKeyRing(dir, util.AlgorithmEdDSA, rotation, grace, util.NewLogger("error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	first := ring.Signer().KID
+	oldToken, err := util.GenerateToken("acc-1", util.UserTypeMerchant, "m@example.com", util.TokenTypeAccess, ring.Signer(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifies := func() error {
+		_, err := util.ValidateToken(ctx, oldToken, util.TokenTypeAccess, ring)
+		return err
+	}
+
+	if err := ring.Rotate(start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if ring.Signer().KID != first || !sameKIDs(publishedKIDs(ring), first) {
+		t.Fatalf("rotated before the rotation period: signer %s, published %v", ring.Signer().KID, publishedKIDs(ring))
+	}
+
+	rotatedAt := start.Add(rotation + time.Minute)
+	if err := ring.Rotate(rotatedAt); err != nil {
+		t.Fatal(err)
+	}
+	second := ring.Signer().KID
+	if second == first {
+		t.Fatal("signing key not rotated after the rotation period")
+	}
+	if !sameKIDs(publishedKIDs(ring), first, second) {
+		t.Errorf("published %v, want the retired and the new key", publishedKIDs(ring))
+	}
+	if err := verifies(); err != nil {
+		t.Errorf("token from the retired key rejected during grace: %v", err)
+	}
+
+	// A replica sharing the directory signs with the newest key.
+	replica, err := NewKeyRing(dir, util.AlgorithmEdDSA, rotation, grace, util.NewLogger("error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replica.Signer().KID != second {
+		t.Errorf("replica signs with %s, want %s", replica.Signer().KID, second)
+	}
+
+	if err := ring.Rotate(rotatedAt.Add(grace - time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifies(); err != nil {
+		t.Errorf("token from the retired key rejected before the grace period ended: %v", err)
+	}
+
+	if err := ring.Rotate(rotatedAt.Add(grace)); err != nil {
+		t.Fatal(err)
+	}
+	// The second key has itself been rotated out by now, so it is in its own grace period.
+	third := ring.Signer().KID
+	if !sameKIDs(publishedKIDs(ring), second, third) {
+		t.Errorf("published %v, want %s and %s without the expired %s", publishedKIDs(ring), second, third, first)
+	}
+	if _, err := os.Stat(filepath.Join(dir, first+".json")); !os.IsNotExist(err) {
+		t.Errorf("expired key file not removed: %v", err)
+	}
+	if err := verifies(); !errors.Is(err, util.ErrUnknownKey) {
+		t.Errorf("token from the expired key: err = %v, want ErrUnknownKey", err)
+	}
+}
@@ -1,30 +1,182 @@
 package control
 
-import "time"
+import (
+	"time"
 
+	"github.com/shopspring/decimal"
+)
+
+// Account is a login. Currency is the currency the account trades in and ReportingCurrency
+// the one its positions and dashboards are converted into. A zero EmailVerifiedAt means the
+// account has not proved it owns its email; changing the email clears it.
 type Account struct {
-	ID       string `json:"id" validate:"required,uuid4"`
-	Name     string `json:"name" validate:"omitempty,min=3,max=50"`
-	UserType string `json:"user_type" validate:"required,oneof=admin merchant"`
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"-" validate:"required,min=8,max=50"`
+	ID                string    `json:"id" validate:"required,uuid4"`
+	Name              string    `json:"name" validate:"omitempty,min=3,max=50"`
+	UserType          string    `json:"user_type" validate:"required,oneof=admin merchant"`
+	Email             string    `json:"email" validate:"required,email"`
+	EmailVerifiedAt   time.Time `json:"email_verified_at"`
+	Password          string    `json:"-" validate:"required,min=8,max=50"`
+	Currency          string    `json:"currency"`
+	ReportingCurrency string    `json:"reporting_currency"`
 }
 
+// Session is the sign-in of an account on one device. FamilyID names the refresh token family
+// the session was signed in with; the refresh token itself is only stored hashed. DeviceName,
+// IPAddress and UserAgent describe the client as last seen.
 type Session struct {
+	ID          string    `json:"id"`
+	AccountID   string    `json:"account_id"`
+	DeviceID    string    `json:"device_id"`
+	DeviceName  string    `json:"device_name"`
+	FamilyID    string    `json:"family_id"`
+	AccessToken string    `json:"access_token"`
+	IPAddress   string    `json:"ip_address"`
+	UserAgent   string    `json:"user_agent"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	IsRevoked   bool      `json:"is_revoked"`
+}
+
+// SessionClient is what the gateway reports about the client making a call. An empty
+// DeviceName keeps the one the session has.
+type SessionClient struct {
+	DeviceName string
+	IPAddress  string
+	UserAgent  string
+}
+
+// SessionSeenInterval is how stale last_seen_at may get before a call moves it, so an active
+// session is not written on every request.
+const SessionSeenInterval = time.Minute
+
+// RefreshToken is one issued refresh token, kept as the SHA-256 hash of the JWT. A login starts
+// a family; each refresh marks the presented token rotated and issues its child in the same
+// family. Zero RotatedAt and RevokedAt mean not yet.
+type RefreshToken struct {
 	ID           string    `json:"id"`
+	FamilyID     string    `json:"family_id"`
+	ParentID     string    `json:"parent_id"`
 	AccountID    string    `json:"account_id"`
 	DeviceID     string    `json:"device_id"`
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
+	TokenHash    string    `json:"-"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
-	IsRevoked    bool      `json:"is_revoked"`
+	RotatedAt    time.Time `json:"rotated_at"`
+	RevokedAt    time.Time `json:"revoked_at"`
+	RevokeReason string    `json:"revoke_reason"`
+}
+
+// Why a refresh token was revoked. REUSE is a security incident: a rotated token was presented
+// again, so it has been copied.
+const (
+	RevokeLogout     = "LOGOUT"
+	RevokeSuperseded = "SUPERSEDED"
+	RevokeExpired    = "EXPIRED"
+	RevokeReuse      = "REUSE"
+	RevokeSignedOut  = "SIGNED_OUT" // by the account, from another session
+	RevokeForced     = "FORCED"     // by an admin
+	RevokePassword   = "PASSWORD"   // the password was reset
+	RevokeTwoFactor  = "TWO_FACTOR" // two-factor authentication was turned on or off
+)
+
+// AccountToken is a single-use token mailed to an account, kept as the SHA-256 hash of the
+// token. Email is the address it was sent to. Zero UsedAt means not yet used; a newer token
+// of the same purpose marks the older ones used.
+type AccountToken struct {
+	ID        string    `json:"id"`
+	AccountID string    `json:"account_id"`
+	Purpose   string    `json:"purpose"`
+	Email     string    `json:"email"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UsedAt    time.Time `json:"used_at"`
+}
+
+// TOTPEnrollment is an account's TOTP secret, AES-GCM sealed. It takes effect once confirmed
+// with a code; LastUsedStep is the time step of the last accepted code.
+type TOTPEnrollment struct {
+	AccountID    string    `json:"account_id"`
+	Secret       string    `json:"-"`
+	ConfirmedAt  time.Time `json:"confirmed_at"`
+	LastUsedStep int64     `json:"last_used_step"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// RecoveryCode is a one-time code that stands in for a TOTP code, kept as its SHA-256 hash.
+type RecoveryCode struct {
+	ID        string    `json:"id"`
+	AccountID string    `json:"account_id"`
+	CodeHash  string    `json:"-"`
+	UsedAt    time.Time `json:"used_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecoveryCodeCount is how many recovery codes an enrolment issues.
+const RecoveryCodeCount = 10
+
+// LoginChallenge is a login that passed the password check and waits for its second factor.
+// The token is kept as its SHA-256 hash; Attempts counts wrong codes.
+type LoginChallenge struct {
+	ID         string    `json:"id"`
+	AccountID  string    `json:"account_id"`
+	DeviceID   string    `json:"device_id"`
+	DeviceName string    `json:"device_name"`
+	Purpose    string    `json:"purpose"`
+	TokenHash  string    `json:"-"`
+	Attempts   int       `json:"attempts"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UsedAt     time.Time `json:"used_at"`
+}
+
+// What a LoginChallenge waits for: a code from an enrolled account, or the enrolment of an
+// admin that must use 2FA.
+const (
+	ChallengeVerify = "VERIFY"
+	ChallengeEnroll = "ENROLL"
+)
+
+// MaxChallengeAttempts is how many wrong codes a challenge takes before it is used up.
+const MaxChallengeAttempts = 5
+
+// TwoFactorPolicy configures TOTP. EncryptionKey seals stored secrets; RequiredForAdmins makes
+// admins enrol before they can sign in.
+type TwoFactorPolicy struct {
+	Issuer            string
+	EncryptionKey     []byte
+	RequiredForAdmins bool
+	ChallengeTTL      time.Duration
+}
+
+// What an AccountToken is for.
+const (
+	TokenPasswordReset     = "PASSWORD_RESET"
+	TokenEmailVerification = "EMAIL_VERIFICATION"
+)
+
+// SigningKey is one JWT signing key as kept in JWT_KEY_DIR, one JSON file per kid. The newest
+// key with zero RetiredAt signs new tokens; a retired key stays published until ExpiresAt so
+// the tokens it signed keep verifying through the grace period.
+type SigningKey struct {
+	KID        string    `json:"kid"`
+	Algorithm  string    `json:"alg"`
+	PrivateKey string    `json:"private_key"` // PKCS #8 PEM
+	CreatedAt  time.Time `json:"created_at"`
+	RetiredAt  time.Time `json:"retired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
 }
 
+// AuthenticatedResponse is the result of a login step. When a second factor is needed the
+// tokens are empty and ChallengeToken carries the login on to VerifyLoginChallenge, or, with
+// EnrollmentRequired, to TOTP enrolment.
 type AuthenticatedResponse struct {
-	Account      *Account `json:"account"`
-	AccessToken  string   `json:"access_token"`
-	RefreshToken string   `json:"refresh_token"`
+	Account            *Account `json:"account"`
+	AccessToken        string   `json:"access_token"`
+	RefreshToken       string   `json:"refresh_token"`
+	ChallengeToken     string   `json:"challenge_token"`
+	EnrollmentRequired bool     `json:"enrollment_required"`
 }
 
 type MerchantDetails struct {
@@ -51,24 +203,226 @@ type Grade struct {
 	Name        string `json:"name" validate:"required,min=3,max=255"`
 	Description string `json:"description" validate:"omitempty,min=3,max=255"`
 	Status      string `json:"status" validate:"required,oneof=active inactive"`
+	// ShelfLifeDays is how long a lot of this grade keeps its quality; 0 = not perishable.
+	ShelfLifeDays int `json:"shelf_life_days" validate:"gte=0"`
+	// Unit is what the grade trades in (KG, QUINTAL, TONNE or BAG) and KgPerUnit how many
+	// kilograms, the ledger's base unit, one unit holds.
+	Unit      string          `json:"unit"`
+	KgPerUnit decimal.Decimal `json:"kg_per_unit"`
 }
 
+// MaxShelfLifeDays caps the configurable shelf life of a grade (ten years).
+const MaxShelfLifeDays = 3650
+
+// TopicPrices is the event-bus topic for published daily prices; the payload is a *DailyPrice.
+const TopicPrices = "control.prices"
+
+// DailyPrice is the rollup of a grade's ticks on one date. Price is the price of the requested
+// basis (the last tick unless CLOSE was asked for) and Time is the time of the last tick.
 type DailyPrice struct {
-	ID        string    `json:"id" validate:"required,uuid4"`
-	ProductID string    `json:"product_id" validate:"required,uuid4"`
-	GradeID   string    `json:"grade_id" validate:"required,uuid4"`
-	Price     float64   `json:"price" validate:"required"`
-	Date      time.Time `json:"date" validate:"required"`
-	Time      time.Time `json:"time" validate:"required"`
+	ID        string          `json:"id" validate:"required,uuid4"`
+	ProductID string          `json:"product_id" validate:"required,uuid4"`
+	GradeID   string          `json:"grade_id" validate:"required,uuid4"`
+	Price     decimal.Decimal `json:"price" validate:"required"`
+	Currency  string          `json:"currency"`
+	Date      time.Time       `json:"date" validate:"required"`
+	Time      time.Time       `json:"time" validate:"required"`
+	Open      decimal.Decimal `json:"open"`
+	High      decimal.Decimal `json:"high"`
+	Low       decimal.Decimal `json:"low"`
+	Last      decimal.Decimal `json:"last"`
+	// Close is the last tick of a day that has ended; it is not set while the day is open.
+	Close     decimal.NullDecimal `json:"close"`
+	TickCount int                 `json:"tick_count"`
+}
+
+// PriceTick is one published price. Ticks are kept as published; daily_price rolls up the
+// approved ones. PublishedBy is the admin who proposed the price and ReviewedBy the one who
+// approved or rejected it; zero times are unset.
+type PriceTick struct {
+	ID          string          `json:"id"`
+	ProductID   string          `json:"product_id"`
+	GradeID     string          `json:"grade_id"`
+	Price       decimal.Decimal `json:"price"`
+	Currency    string          `json:"currency"`
+	Source      string          `json:"source"`
+	PublishedBy string          `json:"published_by"`
+	Status      string          `json:"status"`
+	SubmittedAt time.Time       `json:"submitted_at"`
+	ReviewedBy  string          `json:"reviewed_by"`
+	ReviewedAt  time.Time       `json:"reviewed_at"`
+	ReviewNote  string          `json:"review_note"`
+	TickedAt    time.Time       `json:"ticked_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Price tick statuses. A proposal starts as DRAFT or SUBMITTED; a second admin approves or
+// rejects a SUBMITTED tick. Only APPROVED ticks are visible in daily prices.
+const (
+	PriceDraft     = "DRAFT"
+	PriceSubmitted = "SUBMITTED"
+	PriceApproved  = "APPROVED"
+	PriceRejected  = "REJECTED"
+)
+
+// Review decisions on submitted price ticks.
+const (
+	PriceReviewApprove = "APPROVE"
+	PriceReviewReject  = "REJECT"
+)
+
+// Price bases a daily price can be read at. LAST is the newest tick so far; CLOSE is the last
+// tick of a finished day.
+const (
+	PriceBasisLast  = "LAST"
+	PriceBasisClose = "CLOSE"
+)
+
+// FxRate is an exchange rate admins maintain: 1 BaseCurrency buys Rate QuoteCurrency from
+// EffectiveDate until a later rate for the pair takes over.
+type FxRate struct {
+	ID            string          `json:"id"`
+	BaseCurrency  string          `json:"base_currency"`
+	QuoteCurrency string          `json:"quote_currency"`
+	Rate          decimal.Decimal `json:"rate"`
+	EffectiveDate time.Time       `json:"effective_date"`
+	UpdatedBy     string          `json:"updated_by"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// FxRateScale is the decimal places an FX rate keeps (fx_rates.rate is DECIMAL(20,8)).
+const FxRateScale = 8
+
+// FeeSchedule is a fee or tax charged on trades of one grade (GradeID) or of every grade in a
+// product category (Category). It applies from EffectiveDate until a later schedule with the
+// same scope, Code and Side; a grade schedule overrides a category schedule of the same Code.
+// Rate is a percentage of the trade value (PERCENT), or an amount in Currency per kilogram
+// (PER_KG) or per trade (FLAT). A zero Rate stops charging it.
+type FeeSchedule struct {
+	ID            string          `json:"id"`
+	GradeID       string          `json:"grade_id"`
+	Category      string          `json:"category"`
+	Code          string          `json:"code"`
+	Kind          string          `json:"kind"`
+	Side          string          `json:"side"`
+	Basis         string          `json:"basis"`
+	Rate          decimal.Decimal `json:"rate"`
+	Currency      string          `json:"currency"`
+	EffectiveDate time.Time       `json:"effective_date"`
+	UpdatedBy     string          `json:"updated_by"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// Fee kinds, the trade sides a schedule applies to and how its rate is read.
+const (
+	FeeKindFee = "FEE"
+	FeeKindTax = "TAX"
+
+	FeeSideBuy  = "BUY"
+	FeeSideSell = "SELL"
+	FeeSideBoth = "BOTH"
+
+	FeeBasisPercent = "PERCENT"
+	FeeBasisPerKg   = "PER_KG"
+	FeeBasisFlat    = "FLAT"
+)
+
+// PriceSourceManual is the source of ticks published without one.
+const PriceSourceManual = "MANUAL"
+
+// Row statuses of a price import report.
+const (
+	PriceImportOK    = "OK"
+	PriceImportError = "ERROR"
+)
+
+// PriceImportRow is one line of a bulk price sheet and its outcome. Row is the 1-based line of
+// the sheet. A transport that cannot parse a line sets Status and Error itself; the import
+// then reports it without validating it further.
+type PriceImportRow struct {
+	Row    int       `json:"row"`
+	Tick   PriceTick `json:"tick"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+	// MovePercent is the change from the grade's previous approved price, when it has one.
+	MovePercent decimal.NullDecimal `json:"move_percent"`
+}
+
+// PriceImportOptions tune a bulk import. A null MaxMovePercent uses the configured limit and
+// zero turns the outlier check off. A dry run validates and reports without saving anything.
+type PriceImportOptions struct {
+	MaxMovePercent decimal.NullDecimal
+	DryRun         bool
+}
+
+// PriceImportReport is the row-by-row result of a bulk import. Rows are applied together or
+// not at all: Applied is false when any row was rejected or on a dry run. Applied rows are
+// submitted for approval, not yet visible.
+type PriceImportReport struct {
+	Applied        bool              `json:"applied"`
+	DryRun         bool              `json:"dry_run"`
+	MaxMovePercent decimal.Decimal   `json:"max_move_percent"`
+	Accepted       int               `json:"accepted"`
+	Rejected       int               `json:"rejected"`
+	Rows           []*PriceImportRow `json:"rows"`
+}
+
+// Candle intervals. Weeks start on Monday; months are calendar months.
+const (
+	CandleDay   = "DAY"
+	CandleWeek  = "WEEK"
+	CandleMonth = "MONTH"
+)
+
+// CandleQuery selects the price series GetPriceCandles builds: one grade, or every grade of a
+// product. Zero dates default to a recent window ending today.
+type CandleQuery struct {
+	GradeID   string
+	ProductID string
+	Interval  string
+	From      time.Time
+	To        time.Time
+	FillGaps  bool
+}
+
+// Candle is the OHLC of one bucket. Close is the bucket's last price so far, so the current
+// bucket's close moves until it ends. ChangePercent compares Close with the close before the
+// bucket; the moving averages are of daily closes up to PeriodEnd (or today).
+type Candle struct {
+	PeriodStart   time.Time           `json:"period_start"`
+	PeriodEnd     time.Time           `json:"period_end"`
+	Open          decimal.Decimal     `json:"open"`
+	High          decimal.Decimal     `json:"high"`
+	Low           decimal.Decimal     `json:"low"`
+	Close         decimal.Decimal     `json:"close"`
+	TickCount     int                 `json:"tick_count"`
+	ChangePercent decimal.NullDecimal `json:"change_percent"`
+	SMA7          decimal.NullDecimal `json:"sma_7"`
+	SMA30         decimal.NullDecimal `json:"sma_30"`
+	// Filled marks a bucket with no publication, carried at the previous close.
+	Filled bool `json:"filled"`
+}
+
+// PriceSeries is the candles of one grade.
+type PriceSeries struct {
+	GradeID   string    `json:"grade_id"`
+	ProductID string    `json:"product_id"`
+	Interval  string    `json:"interval"`
+	Candles   []*Candle `json:"candles"`
 }
 
 type GradeWithPrice struct {
-	ID          string  `json:"id" validate:"required,uuid4"`
-	ProductID   string  `json:"product_id" validate:"required,uuid4"`
-	Name        string  `json:"name" validate:"required,min=3,max=255"`
-	Price       float64 `json:"price" validate:"required"`
-	Description string  `json:"description" validate:"omitempty,min=3,max=255"`
-	Status      string  `json:"status" validate:"required,oneof=active inactive"`
+	ID          string          `json:"id" validate:"required,uuid4"`
+	ProductID   string          `json:"product_id" validate:"required,uuid4"`
+	Name        string          `json:"name" validate:"required,min=3,max=255"`
+	Price       decimal.Decimal `json:"price" validate:"required"`
+	Currency    string          `json:"currency"`
+	Description string          `json:"description" validate:"omitempty,min=3,max=255"`
+	Status      string          `json:"status" validate:"required,oneof=active inactive"`
+	// ShelfLifeDays is 0 when the grade is not perishable.
+	ShelfLifeDays int             `json:"shelf_life_days"`
+	Unit          string          `json:"unit"`
+	KgPerUnit     decimal.Decimal `json:"kg_per_unit"`
 }
 
 type ProductWithGrades struct {
@@ -2,7 +2,7 @@
 
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.11
+// 	protoc-gen-go v1.36.12
 // 	protoc        v7.34.1
 // source: control.proto
 
@@ -24,14 +24,17 @@ const (
 )
 
 type Account struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Usertype      string                 `protobuf:"bytes,3,opt,name=usertype,proto3" json:"usertype,omitempty"`
-	Email         string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Usertype          string                 `protobuf:"bytes,3,opt,name=usertype,proto3" json:"usertype,omitempty"`
+	Email             string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Password          string                 `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	Currency          string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`                                            // ISO 4217 code the account trades in
+	ReportingCurrency string                 `protobuf:"bytes,7,opt,name=reporting_currency,json=reportingCurrency,proto3" json:"reporting_currency,omitempty"` // positions and dashboards are converted into it
+	EmailVerified     bool                   `protobuf:"varint,8,opt,name=email_verified,json=emailVerified,proto3" json:"email_verified,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Account) Reset() {
@@ -99,6 +102,27 @@ func (x *Account) GetPassword() string {
 	return ""
 }
 
+func (x *Account) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Account) GetReportingCurrency() string {
+	if x != nil {
+		return x.ReportingCurrency
+	}
+	return ""
+}
+
+func (x *Account) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
 type MerchantDetails struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -274,6 +298,9 @@ type Grade struct {
 	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
 	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
 	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	ShelfLifeDays uint32                 `protobuf:"varint,6,opt,name=shelf_life_days,json=shelfLifeDays,proto3" json:"shelf_life_days,omitempty"` // 0 = not perishable
+	Unit          string                 `protobuf:"bytes,7,opt,name=unit,proto3" json:"unit,omitempty"`                                           // KG, QUINTAL, TONNE or BAG
+	KgPerUnit     string                 `protobuf:"bytes,8,opt,name=kg_per_unit,json=kgPerUnit,proto3" json:"kg_per_unit,omitempty"`              // decimal string; kilograms (the base unit) in one unit
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -343,6 +370,27 @@ func (x *Grade) GetStatus() string {
 	return ""
 }
 
+func (x *Grade) GetShelfLifeDays() uint32 {
+	if x != nil {
+		return x.ShelfLifeDays
+	}
+	return 0
+}
+
+func (x *Grade) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Grade) GetKgPerUnit() string {
+	if x != nil {
+		return x.KgPerUnit
+	}
+	return ""
+}
+
 type GradeWithPrice struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -350,7 +398,11 @@ type GradeWithPrice struct {
 	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
 	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
 	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	Price         float64                `protobuf:"fixed64,6,opt,name=price,proto3" json:"price,omitempty"`
+	Price         string                 `protobuf:"bytes,6,opt,name=price,proto3" json:"price,omitempty"`                                         // decimal string, 4 dp; "0" when no price is published
+	ShelfLifeDays uint32                 `protobuf:"varint,7,opt,name=shelf_life_days,json=shelfLifeDays,proto3" json:"shelf_life_days,omitempty"` // 0 = not perishable
+	Currency      string                 `protobuf:"bytes,8,opt,name=currency,proto3" json:"currency,omitempty"`                                   // empty when no price is published
+	Unit          string                 `protobuf:"bytes,9,opt,name=unit,proto3" json:"unit,omitempty"`                                           // KG, QUINTAL, TONNE or BAG
+	KgPerUnit     string                 `protobuf:"bytes,10,opt,name=kg_per_unit,json=kgPerUnit,proto3" json:"kg_per_unit,omitempty"`             // decimal string; kilograms (the base unit) in one unit
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -420,13 +472,41 @@ func (x *GradeWithPrice) GetStatus() string {
 	return ""
 }
 
-func (x *GradeWithPrice) GetPrice() float64 {
+func (x *GradeWithPrice) GetPrice() string {
 	if x != nil {
 		return x.Price
 	}
+	return ""
+}
+
+func (x *GradeWithPrice) GetShelfLifeDays() uint32 {
+	if x != nil {
+		return x.ShelfLifeDays
+	}
 	return 0
 }
 
+func (x *GradeWithPrice) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GradeWithPrice) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *GradeWithPrice) GetKgPerUnit() string {
+	if x != nil {
+		return x.KgPerUnit
+	}
+	return ""
+}
+
 type ProductWithGrades struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -511,14 +591,22 @@ func (x *ProductWithGrades) GetGrades() []*GradeWithPrice {
 	return nil
 }
 
+// The rollup of a grade's price ticks on one date.
 type DailyPrice struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
 	GradeId       string                 `protobuf:"bytes,3,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Date          string                 `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD
-	Time          string                 `protobuf:"bytes,6,opt,name=time,proto3" json:"time,omitempty"` // HH:MM:SS
+	Price         string                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"` // decimal string, 4 dp; the requested price basis (last tick by default)
+	Date          string                 `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`   // YYYY-MM-DD
+	Time          string                 `protobuf:"bytes,6,opt,name=time,proto3" json:"time,omitempty"`   // HH:MM:SS of the last tick
+	Open          string                 `protobuf:"bytes,7,opt,name=open,proto3" json:"open,omitempty"`
+	High          string                 `protobuf:"bytes,8,opt,name=high,proto3" json:"high,omitempty"`
+	Low           string                 `protobuf:"bytes,9,opt,name=low,proto3" json:"low,omitempty"`
+	Last          string                 `protobuf:"bytes,10,opt,name=last,proto3" json:"last,omitempty"`
+	Close         string                 `protobuf:"bytes,11,opt,name=close,proto3" json:"close,omitempty"` // empty until the day has ended
+	TickCount     int32                  `protobuf:"varint,12,opt,name=tick_count,json=tickCount,proto3" json:"tick_count,omitempty"`
+	Currency      string                 `protobuf:"bytes,13,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -574,11 +662,11 @@ func (x *DailyPrice) GetGradeId() string {
 	return ""
 }
 
-func (x *DailyPrice) GetPrice() float64 {
+func (x *DailyPrice) GetPrice() string {
 	if x != nil {
 		return x.Price
 	}
-	return 0
+	return ""
 }
 
 func (x *DailyPrice) GetDate() string {
@@ -595,6 +683,196 @@ func (x *DailyPrice) GetTime() string {
 	return ""
 }
 
+func (x *DailyPrice) GetOpen() string {
+	if x != nil {
+		return x.Open
+	}
+	return ""
+}
+
+func (x *DailyPrice) GetHigh() string {
+	if x != nil {
+		return x.High
+	}
+	return ""
+}
+
+func (x *DailyPrice) GetLow() string {
+	if x != nil {
+		return x.Low
+	}
+	return ""
+}
+
+func (x *DailyPrice) GetLast() string {
+	if x != nil {
+		return x.Last
+	}
+	return ""
+}
+
+func (x *DailyPrice) GetClose() string {
+	if x != nil {
+		return x.Close
+	}
+	return ""
+}
+
+func (x *DailyPrice) GetTickCount() int32 {
+	if x != nil {
+		return x.TickCount
+	}
+	return 0
+}
+
+func (x *DailyPrice) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// One published price. Only APPROVED ticks count towards daily prices.
+type PriceTick struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	GradeId       string                 `protobuf:"bytes,3,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
+	Price         string                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Source        string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`                              // MANUAL, FEED, ...
+	PublishedBy   string                 `protobuf:"bytes,6,opt,name=published_by,json=publishedBy,proto3" json:"published_by,omitempty"` // account id of the proposer, if known
+	TickedAt      string                 `protobuf:"bytes,7,opt,name=ticked_at,json=tickedAt,proto3" json:"ticked_at,omitempty"`          // YYYY-MM-DD HH:MM:SS.ffffff
+	Status        string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`                              // DRAFT | SUBMITTED | APPROVED | REJECTED
+	SubmittedAt   string                 `protobuf:"bytes,9,opt,name=submitted_at,json=submittedAt,proto3" json:"submitted_at,omitempty"` // empty while a draft
+	ReviewedBy    string                 `protobuf:"bytes,10,opt,name=reviewed_by,json=reviewedBy,proto3" json:"reviewed_by,omitempty"`   // account id of the approver or rejecter
+	ReviewedAt    string                 `protobuf:"bytes,11,opt,name=reviewed_at,json=reviewedAt,proto3" json:"reviewed_at,omitempty"`
+	ReviewNote    string                 `protobuf:"bytes,12,opt,name=review_note,json=reviewNote,proto3" json:"review_note,omitempty"`
+	Currency      string                 `protobuf:"bytes,13,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceTick) Reset() {
+	*x = PriceTick{}
+	mi := &file_control_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceTick) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceTick) ProtoMessage() {}
+
+func (x *PriceTick) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceTick.ProtoReflect.Descriptor instead.
+func (*PriceTick) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PriceTick) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PriceTick) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PriceTick) GetGradeId() string {
+	if x != nil {
+		return x.GradeId
+	}
+	return ""
+}
+
+func (x *PriceTick) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *PriceTick) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *PriceTick) GetPublishedBy() string {
+	if x != nil {
+		return x.PublishedBy
+	}
+	return ""
+}
+
+func (x *PriceTick) GetTickedAt() string {
+	if x != nil {
+		return x.TickedAt
+	}
+	return ""
+}
+
+func (x *PriceTick) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PriceTick) GetSubmittedAt() string {
+	if x != nil {
+		return x.SubmittedAt
+	}
+	return ""
+}
+
+func (x *PriceTick) GetReviewedBy() string {
+	if x != nil {
+		return x.ReviewedBy
+	}
+	return ""
+}
+
+func (x *PriceTick) GetReviewedAt() string {
+	if x != nil {
+		return x.ReviewedAt
+	}
+	return ""
+}
+
+func (x *PriceTick) GetReviewNote() string {
+	if x != nil {
+		return x.ReviewNote
+	}
+	return ""
+}
+
+func (x *PriceTick) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type CheckEmailExistsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
@@ -604,7 +882,7 @@ type CheckEmailExistsRequest struct {
 
 func (x *CheckEmailExistsRequest) Reset() {
 	*x = CheckEmailExistsRequest{}
-	mi := &file_control_proto_msgTypes[7]
+	mi := &file_control_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -616,7 +894,7 @@ func (x *CheckEmailExistsRequest) String() string {
 func (*CheckEmailExistsRequest) ProtoMessage() {}
 
 func (x *CheckEmailExistsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[7]
+	mi := &file_control_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -629,7 +907,7 @@ func (x *CheckEmailExistsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckEmailExistsRequest.ProtoReflect.Descriptor instead.
 func (*CheckEmailExistsRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{7}
+	return file_control_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CheckEmailExistsRequest) GetEmail() string {
@@ -648,7 +926,7 @@ type CheckEmailExistsResponse struct {
 
 func (x *CheckEmailExistsResponse) Reset() {
 	*x = CheckEmailExistsResponse{}
-	mi := &file_control_proto_msgTypes[8]
+	mi := &file_control_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -660,7 +938,7 @@ func (x *CheckEmailExistsResponse) String() string {
 func (*CheckEmailExistsResponse) ProtoMessage() {}
 
 func (x *CheckEmailExistsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[8]
+	mi := &file_control_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -673,7 +951,7 @@ func (x *CheckEmailExistsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckEmailExistsResponse.ProtoReflect.Descriptor instead.
 func (*CheckEmailExistsResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{8}
+	return file_control_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *CheckEmailExistsResponse) GetExists() bool {
@@ -684,19 +962,21 @@ func (x *CheckEmailExistsResponse) GetExists() bool {
 }
 
 type CreateOrUpdateAccountRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Usertype      string                 `protobuf:"bytes,3,opt,name=usertype,proto3" json:"usertype,omitempty"`
-	Email         string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Usertype          string                 `protobuf:"bytes,3,opt,name=usertype,proto3" json:"usertype,omitempty"`
+	Email             string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Password          string                 `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	Currency          string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`                                            // defaults to INR for a new account; empty keeps the stored one
+	ReportingCurrency string                 `protobuf:"bytes,7,opt,name=reporting_currency,json=reportingCurrency,proto3" json:"reporting_currency,omitempty"` // defaults to currency for a new account; empty keeps the stored one
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *CreateOrUpdateAccountRequest) Reset() {
 	*x = CreateOrUpdateAccountRequest{}
-	mi := &file_control_proto_msgTypes[9]
+	mi := &file_control_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -708,7 +988,7 @@ func (x *CreateOrUpdateAccountRequest) String() string {
 func (*CreateOrUpdateAccountRequest) ProtoMessage() {}
 
 func (x *CreateOrUpdateAccountRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[9]
+	mi := &file_control_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -721,7 +1001,7 @@ func (x *CreateOrUpdateAccountRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrUpdateAccountRequest.ProtoReflect.Descriptor instead.
 func (*CreateOrUpdateAccountRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{9}
+	return file_control_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *CreateOrUpdateAccountRequest) GetId() string {
@@ -759,6 +1039,20 @@ func (x *CreateOrUpdateAccountRequest) GetPassword() string {
 	return ""
 }
 
+func (x *CreateOrUpdateAccountRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateAccountRequest) GetReportingCurrency() string {
+	if x != nil {
+		return x.ReportingCurrency
+	}
+	return ""
+}
+
 type CreateOrUpdateAccountResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
@@ -768,7 +1062,7 @@ type CreateOrUpdateAccountResponse struct {
 
 func (x *CreateOrUpdateAccountResponse) Reset() {
 	*x = CreateOrUpdateAccountResponse{}
-	mi := &file_control_proto_msgTypes[10]
+	mi := &file_control_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -780,7 +1074,7 @@ func (x *CreateOrUpdateAccountResponse) String() string {
 func (*CreateOrUpdateAccountResponse) ProtoMessage() {}
 
 func (x *CreateOrUpdateAccountResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[10]
+	mi := &file_control_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -793,7 +1087,7 @@ func (x *CreateOrUpdateAccountResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrUpdateAccountResponse.ProtoReflect.Descriptor instead.
 func (*CreateOrUpdateAccountResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{10}
+	return file_control_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *CreateOrUpdateAccountResponse) GetAccount() *Account {
@@ -812,7 +1106,7 @@ type GetAccountByIDRequest struct {
 
 func (x *GetAccountByIDRequest) Reset() {
 	*x = GetAccountByIDRequest{}
-	mi := &file_control_proto_msgTypes[11]
+	mi := &file_control_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -824,7 +1118,7 @@ func (x *GetAccountByIDRequest) String() string {
 func (*GetAccountByIDRequest) ProtoMessage() {}
 
 func (x *GetAccountByIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[11]
+	mi := &file_control_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -837,7 +1131,7 @@ func (x *GetAccountByIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAccountByIDRequest.ProtoReflect.Descriptor instead.
 func (*GetAccountByIDRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{11}
+	return file_control_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetAccountByIDRequest) GetId() string {
@@ -856,7 +1150,7 @@ type GetAccountByIDResponse struct {
 
 func (x *GetAccountByIDResponse) Reset() {
 	*x = GetAccountByIDResponse{}
-	mi := &file_control_proto_msgTypes[12]
+	mi := &file_control_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -868,7 +1162,7 @@ func (x *GetAccountByIDResponse) String() string {
 func (*GetAccountByIDResponse) ProtoMessage() {}
 
 func (x *GetAccountByIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[12]
+	mi := &file_control_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -881,7 +1175,7 @@ func (x *GetAccountByIDResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAccountByIDResponse.ProtoReflect.Descriptor instead.
 func (*GetAccountByIDResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{12}
+	return file_control_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GetAccountByIDResponse) GetAccount() *Account {
@@ -901,7 +1195,7 @@ type ListAccountsRequest struct {
 
 func (x *ListAccountsRequest) Reset() {
 	*x = ListAccountsRequest{}
-	mi := &file_control_proto_msgTypes[13]
+	mi := &file_control_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -913,7 +1207,7 @@ func (x *ListAccountsRequest) String() string {
 func (*ListAccountsRequest) ProtoMessage() {}
 
 func (x *ListAccountsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[13]
+	mi := &file_control_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -926,7 +1220,7 @@ func (x *ListAccountsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListAccountsRequest.ProtoReflect.Descriptor instead.
 func (*ListAccountsRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{13}
+	return file_control_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *ListAccountsRequest) GetSkip() uint32 {
@@ -952,7 +1246,7 @@ type ListAccountsResponse struct {
 
 func (x *ListAccountsResponse) Reset() {
 	*x = ListAccountsResponse{}
-	mi := &file_control_proto_msgTypes[14]
+	mi := &file_control_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -964,7 +1258,7 @@ func (x *ListAccountsResponse) String() string {
 func (*ListAccountsResponse) ProtoMessage() {}
 
 func (x *ListAccountsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[14]
+	mi := &file_control_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -977,7 +1271,7 @@ func (x *ListAccountsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListAccountsResponse.ProtoReflect.Descriptor instead.
 func (*ListAccountsResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{14}
+	return file_control_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *ListAccountsResponse) GetAccounts() []*Account {
@@ -992,13 +1286,14 @@ type LoginRequest struct {
 	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
 	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	DeviceId      string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceName    string                 `protobuf:"bytes,4,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"` // optional, e.g. "Pixel 8" or "Office laptop"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LoginRequest) Reset() {
 	*x = LoginRequest{}
-	mi := &file_control_proto_msgTypes[15]
+	mi := &file_control_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1010,7 +1305,7 @@ func (x *LoginRequest) String() string {
 func (*LoginRequest) ProtoMessage() {}
 
 func (x *LoginRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[15]
+	mi := &file_control_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1023,7 +1318,7 @@ func (x *LoginRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
 func (*LoginRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{15}
+	return file_control_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *LoginRequest) GetEmail() string {
@@ -1047,18 +1342,31 @@ func (x *LoginRequest) GetDeviceId() string {
 	return ""
 }
 
+func (x *LoginRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+// When the account uses 2FA (or is an admin that must enrol) the tokens and account are empty
+// and challenge_token carries the login on to VerifyLoginChallenge, or, with
+// enrollment_required, to EnrollTOTP and ConfirmTOTP.
 type LoginResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
-	AccessToken   string                 `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	RefreshToken  string                 `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Account            *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	AccessToken        string                 `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken       string                 `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	TwoFactorRequired  bool                   `protobuf:"varint,4,opt,name=two_factor_required,json=twoFactorRequired,proto3" json:"two_factor_required,omitempty"`
+	ChallengeToken     string                 `protobuf:"bytes,5,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	EnrollmentRequired bool                   `protobuf:"varint,6,opt,name=enrollment_required,json=enrollmentRequired,proto3" json:"enrollment_required,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *LoginResponse) Reset() {
 	*x = LoginResponse{}
-	mi := &file_control_proto_msgTypes[16]
+	mi := &file_control_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1070,7 +1378,7 @@ func (x *LoginResponse) String() string {
 func (*LoginResponse) ProtoMessage() {}
 
 func (x *LoginResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[16]
+	mi := &file_control_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1083,7 +1391,7 @@ func (x *LoginResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
 func (*LoginResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{16}
+	return file_control_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *LoginResponse) GetAccount() *Account {
@@ -1107,6 +1415,27 @@ func (x *LoginResponse) GetRefreshToken() string {
 	return ""
 }
 
+func (x *LoginResponse) GetTwoFactorRequired() bool {
+	if x != nil {
+		return x.TwoFactorRequired
+	}
+	return false
+}
+
+func (x *LoginResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetEnrollmentRequired() bool {
+	if x != nil {
+		return x.EnrollmentRequired
+	}
+	return false
+}
+
 type LogoutRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
@@ -1117,7 +1446,7 @@ type LogoutRequest struct {
 
 func (x *LogoutRequest) Reset() {
 	*x = LogoutRequest{}
-	mi := &file_control_proto_msgTypes[17]
+	mi := &file_control_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1129,7 +1458,7 @@ func (x *LogoutRequest) String() string {
 func (*LogoutRequest) ProtoMessage() {}
 
 func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[17]
+	mi := &file_control_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1142,7 +1471,7 @@ func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
 func (*LogoutRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{17}
+	return file_control_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *LogoutRequest) GetAccessToken() string {
@@ -1168,7 +1497,7 @@ type LogoutResponse struct {
 
 func (x *LogoutResponse) Reset() {
 	*x = LogoutResponse{}
-	mi := &file_control_proto_msgTypes[18]
+	mi := &file_control_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1180,7 +1509,7 @@ func (x *LogoutResponse) String() string {
 func (*LogoutResponse) ProtoMessage() {}
 
 func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[18]
+	mi := &file_control_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1193,7 +1522,7 @@ func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
 func (*LogoutResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{18}
+	return file_control_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *LogoutResponse) GetSuccess() bool {
@@ -1213,7 +1542,7 @@ type RefreshTokenRequest struct {
 
 func (x *RefreshTokenRequest) Reset() {
 	*x = RefreshTokenRequest{}
-	mi := &file_control_proto_msgTypes[19]
+	mi := &file_control_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1225,7 +1554,7 @@ func (x *RefreshTokenRequest) String() string {
 func (*RefreshTokenRequest) ProtoMessage() {}
 
 func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[19]
+	mi := &file_control_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1238,7 +1567,7 @@ func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
 func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{19}
+	return file_control_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *RefreshTokenRequest) GetRefreshToken() string {
@@ -1266,7 +1595,7 @@ type RefreshTokenResponse struct {
 
 func (x *RefreshTokenResponse) Reset() {
 	*x = RefreshTokenResponse{}
-	mi := &file_control_proto_msgTypes[20]
+	mi := &file_control_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1278,7 +1607,7 @@ func (x *RefreshTokenResponse) String() string {
 func (*RefreshTokenResponse) ProtoMessage() {}
 
 func (x *RefreshTokenResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[20]
+	mi := &file_control_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1291,7 +1620,7 @@ func (x *RefreshTokenResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RefreshTokenResponse.ProtoReflect.Descriptor instead.
 func (*RefreshTokenResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{20}
+	return file_control_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *RefreshTokenResponse) GetAccount() *Account {
@@ -1315,34 +1644,30 @@ func (x *RefreshTokenResponse) GetRefreshToken() string {
 	return ""
 }
 
-type CreateOrUpdateMerchantDetailsRequest struct {
+// Password reset and email verification. Tokens are single use and expire; they are mailed,
+// never returned by the API.
+type RequestPasswordResetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	PhoneNumber   string                 `protobuf:"bytes,3,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
-	Address       string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
-	City          string                 `protobuf:"bytes,5,opt,name=city,proto3" json:"city,omitempty"`
-	State         string                 `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
-	Pincode       string                 `protobuf:"bytes,7,opt,name=pincode,proto3" json:"pincode,omitempty"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrUpdateMerchantDetailsRequest) Reset() {
-	*x = CreateOrUpdateMerchantDetailsRequest{}
-	mi := &file_control_proto_msgTypes[21]
+func (x *RequestPasswordResetRequest) Reset() {
+	*x = RequestPasswordResetRequest{}
+	mi := &file_control_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrUpdateMerchantDetailsRequest) String() string {
+func (x *RequestPasswordResetRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrUpdateMerchantDetailsRequest) ProtoMessage() {}
+func (*RequestPasswordResetRequest) ProtoMessage() {}
 
-func (x *CreateOrUpdateMerchantDetailsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[21]
+func (x *RequestPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1353,87 +1678,87 @@ func (x *CreateOrUpdateMerchantDetailsRequest) ProtoReflect() protoreflect.Messa
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrUpdateMerchantDetailsRequest.ProtoReflect.Descriptor instead.
-func (*CreateOrUpdateMerchantDetailsRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use RequestPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *CreateOrUpdateMerchantDetailsRequest) GetId() string {
+func (x *RequestPasswordResetRequest) GetEmail() string {
 	if x != nil {
-		return x.Id
+		return x.Email
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateMerchantDetailsRequest) GetAccountId() string {
-	if x != nil {
-		return x.AccountId
-	}
-	return ""
+// Succeeds whether or not the email has an account.
+type RequestPasswordResetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrUpdateMerchantDetailsRequest) GetPhoneNumber() string {
-	if x != nil {
-		return x.PhoneNumber
-	}
-	return ""
+func (x *RequestPasswordResetResponse) Reset() {
+	*x = RequestPasswordResetResponse{}
+	mi := &file_control_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrUpdateMerchantDetailsRequest) GetAddress() string {
-	if x != nil {
-		return x.Address
-	}
-	return ""
+func (x *RequestPasswordResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *CreateOrUpdateMerchantDetailsRequest) GetCity() string {
+func (*RequestPasswordResetResponse) ProtoMessage() {}
+
+func (x *RequestPasswordResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[23]
 	if x != nil {
-		return x.City
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *CreateOrUpdateMerchantDetailsRequest) GetState() string {
-	if x != nil {
-		return x.State
-	}
-	return ""
+// Deprecated: Use RequestPasswordResetResponse.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *CreateOrUpdateMerchantDetailsRequest) GetPincode() string {
+func (x *RequestPasswordResetResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Pincode
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-type CreateOrUpdateMerchantInfoRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	PhoneNumber   string                 `protobuf:"bytes,2,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
-	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
-	City          string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
-	State         string                 `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
-	Pincode       string                 `protobuf:"bytes,6,opt,name=pincode,proto3" json:"pincode,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ConfirmPasswordResetRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Token          string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	NewPassword    string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	RevokeSessions bool                   `protobuf:"varint,3,opt,name=revoke_sessions,json=revokeSessions,proto3" json:"revoke_sessions,omitempty"` // also sign the account out everywhere
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *CreateOrUpdateMerchantInfoRequest) Reset() {
-	*x = CreateOrUpdateMerchantInfoRequest{}
-	mi := &file_control_proto_msgTypes[22]
+func (x *ConfirmPasswordResetRequest) Reset() {
+	*x = ConfirmPasswordResetRequest{}
+	mi := &file_control_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrUpdateMerchantInfoRequest) String() string {
+func (x *ConfirmPasswordResetRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrUpdateMerchantInfoRequest) ProtoMessage() {}
+func (*ConfirmPasswordResetRequest) ProtoMessage() {}
 
-func (x *CreateOrUpdateMerchantInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[22]
+func (x *ConfirmPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1444,75 +1769,55 @@ func (x *CreateOrUpdateMerchantInfoRequest) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrUpdateMerchantInfoRequest.ProtoReflect.Descriptor instead.
-func (*CreateOrUpdateMerchantInfoRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{22}
-}
-
-func (x *CreateOrUpdateMerchantInfoRequest) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *CreateOrUpdateMerchantInfoRequest) GetPhoneNumber() string {
-	if x != nil {
-		return x.PhoneNumber
-	}
-	return ""
-}
-
-func (x *CreateOrUpdateMerchantInfoRequest) GetAddress() string {
-	if x != nil {
-		return x.Address
-	}
-	return ""
+// Deprecated: Use ConfirmPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *CreateOrUpdateMerchantInfoRequest) GetCity() string {
+func (x *ConfirmPasswordResetRequest) GetToken() string {
 	if x != nil {
-		return x.City
+		return x.Token
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateMerchantInfoRequest) GetState() string {
+func (x *ConfirmPasswordResetRequest) GetNewPassword() string {
 	if x != nil {
-		return x.State
+		return x.NewPassword
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateMerchantInfoRequest) GetPincode() string {
+func (x *ConfirmPasswordResetRequest) GetRevokeSessions() bool {
 	if x != nil {
-		return x.Pincode
+		return x.RevokeSessions
 	}
-	return ""
+	return false
 }
 
-type CreateOrUpdateMerchantDetailsResponse struct {
+type ConfirmPasswordResetResponse struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
-	MerchantDetails *MerchantDetails       `protobuf:"bytes,1,opt,name=merchant_details,json=merchantDetails,proto3" json:"merchant_details,omitempty"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	SessionsRevoked uint32                 `protobuf:"varint,2,opt,name=sessions_revoked,json=sessionsRevoked,proto3" json:"sessions_revoked,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
-func (x *CreateOrUpdateMerchantDetailsResponse) Reset() {
-	*x = CreateOrUpdateMerchantDetailsResponse{}
-	mi := &file_control_proto_msgTypes[23]
+func (x *ConfirmPasswordResetResponse) Reset() {
+	*x = ConfirmPasswordResetResponse{}
+	mi := &file_control_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrUpdateMerchantDetailsResponse) String() string {
+func (x *ConfirmPasswordResetResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrUpdateMerchantDetailsResponse) ProtoMessage() {}
+func (*ConfirmPasswordResetResponse) ProtoMessage() {}
 
-func (x *CreateOrUpdateMerchantDetailsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[23]
+func (x *ConfirmPasswordResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1523,40 +1828,46 @@ func (x *CreateOrUpdateMerchantDetailsResponse) ProtoReflect() protoreflect.Mess
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrUpdateMerchantDetailsResponse.ProtoReflect.Descriptor instead.
-func (*CreateOrUpdateMerchantDetailsResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use ConfirmPasswordResetResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmPasswordResetResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *CreateOrUpdateMerchantDetailsResponse) GetMerchantDetails() *MerchantDetails {
+func (x *ConfirmPasswordResetResponse) GetSuccess() bool {
 	if x != nil {
-		return x.MerchantDetails
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-type GetMerchantDetailsRequest struct {
+func (x *ConfirmPasswordResetResponse) GetSessionsRevoked() uint32 {
+	if x != nil {
+		return x.SessionsRevoked
+	}
+	return 0
+}
+
+type SendVerificationEmailRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMerchantDetailsRequest) Reset() {
-	*x = GetMerchantDetailsRequest{}
-	mi := &file_control_proto_msgTypes[24]
+func (x *SendVerificationEmailRequest) Reset() {
+	*x = SendVerificationEmailRequest{}
+	mi := &file_control_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMerchantDetailsRequest) String() string {
+func (x *SendVerificationEmailRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMerchantDetailsRequest) ProtoMessage() {}
+func (*SendVerificationEmailRequest) ProtoMessage() {}
 
-func (x *GetMerchantDetailsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[24]
+func (x *SendVerificationEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1567,40 +1878,33 @@ func (x *GetMerchantDetailsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMerchantDetailsRequest.ProtoReflect.Descriptor instead.
-func (*GetMerchantDetailsRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{24}
-}
-
-func (x *GetMerchantDetailsRequest) GetAccountId() string {
-	if x != nil {
-		return x.AccountId
-	}
-	return ""
+// Deprecated: Use SendVerificationEmailRequest.ProtoReflect.Descriptor instead.
+func (*SendVerificationEmailRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{26}
 }
 
-type GetMerchantDetailsResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	MerchantDetails *MerchantDetails       `protobuf:"bytes,1,opt,name=merchant_details,json=merchantDetails,proto3" json:"merchant_details,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+type SendVerificationEmailResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMerchantDetailsResponse) Reset() {
-	*x = GetMerchantDetailsResponse{}
-	mi := &file_control_proto_msgTypes[25]
+func (x *SendVerificationEmailResponse) Reset() {
+	*x = SendVerificationEmailResponse{}
+	mi := &file_control_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMerchantDetailsResponse) String() string {
+func (x *SendVerificationEmailResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMerchantDetailsResponse) ProtoMessage() {}
+func (*SendVerificationEmailResponse) ProtoMessage() {}
 
-func (x *GetMerchantDetailsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[25]
+func (x *SendVerificationEmailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1611,45 +1915,40 @@ func (x *GetMerchantDetailsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMerchantDetailsResponse.ProtoReflect.Descriptor instead.
-func (*GetMerchantDetailsResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use SendVerificationEmailResponse.ProtoReflect.Descriptor instead.
+func (*SendVerificationEmailResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *GetMerchantDetailsResponse) GetMerchantDetails() *MerchantDetails {
+func (x *SendVerificationEmailResponse) GetSuccess() bool {
 	if x != nil {
-		return x.MerchantDetails
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-// Products
-type CreateOrUpdateProductRequest struct {
+type VerifyEmailRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrUpdateProductRequest) Reset() {
-	*x = CreateOrUpdateProductRequest{}
-	mi := &file_control_proto_msgTypes[26]
+func (x *VerifyEmailRequest) Reset() {
+	*x = VerifyEmailRequest{}
+	mi := &file_control_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrUpdateProductRequest) String() string {
+func (x *VerifyEmailRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrUpdateProductRequest) ProtoMessage() {}
+func (*VerifyEmailRequest) ProtoMessage() {}
 
-func (x *CreateOrUpdateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[26]
+func (x *VerifyEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1660,68 +1959,40 @@ func (x *CreateOrUpdateProductRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrUpdateProductRequest.ProtoReflect.Descriptor instead.
-func (*CreateOrUpdateProductRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{26}
-}
-
-func (x *CreateOrUpdateProductRequest) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *CreateOrUpdateProductRequest) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *CreateOrUpdateProductRequest) GetCategory() string {
-	if x != nil {
-		return x.Category
-	}
-	return ""
-}
-
-func (x *CreateOrUpdateProductRequest) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
+// Deprecated: Use VerifyEmailRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEmailRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *CreateOrUpdateProductRequest) GetStatus() string {
+func (x *VerifyEmailRequest) GetToken() string {
 	if x != nil {
-		return x.Status
+		return x.Token
 	}
 	return ""
 }
 
-type CreateOrUpdateProductResponse struct {
+type VerifyEmailResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrUpdateProductResponse) Reset() {
-	*x = CreateOrUpdateProductResponse{}
-	mi := &file_control_proto_msgTypes[27]
+func (x *VerifyEmailResponse) Reset() {
+	*x = VerifyEmailResponse{}
+	mi := &file_control_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrUpdateProductResponse) String() string {
+func (x *VerifyEmailResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrUpdateProductResponse) ProtoMessage() {}
+func (*VerifyEmailResponse) ProtoMessage() {}
 
-func (x *CreateOrUpdateProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[27]
+func (x *VerifyEmailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1732,41 +2003,42 @@ func (x *CreateOrUpdateProductResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrUpdateProductResponse.ProtoReflect.Descriptor instead.
-func (*CreateOrUpdateProductResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use VerifyEmailResponse.ProtoReflect.Descriptor instead.
+func (*VerifyEmailResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *CreateOrUpdateProductResponse) GetProduct() *Product {
+func (x *VerifyEmailResponse) GetAccount() *Account {
 	if x != nil {
-		return x.Product
+		return x.Account
 	}
 	return nil
 }
 
-type ListProductsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Skip          uint32                 `protobuf:"varint,1,opt,name=skip,proto3" json:"skip,omitempty"`
-	Take          uint32                 `protobuf:"varint,2,opt,name=take,proto3" json:"take,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Signed in, or with the challenge_token of an admin that must enrol.
+type EnrollTOTPRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeToken string                 `protobuf:"bytes,1,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	DeviceId       string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"` // required with challenge_token
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ListProductsRequest) Reset() {
-	*x = ListProductsRequest{}
-	mi := &file_control_proto_msgTypes[28]
+func (x *EnrollTOTPRequest) Reset() {
+	*x = EnrollTOTPRequest{}
+	mi := &file_control_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListProductsRequest) String() string {
+func (x *EnrollTOTPRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProductsRequest) ProtoMessage() {}
+func (*EnrollTOTPRequest) ProtoMessage() {}
 
-func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[28]
+func (x *EnrollTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1777,47 +2049,48 @@ func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
-func (*ListProductsRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use EnrollTOTPRequest.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *ListProductsRequest) GetSkip() uint32 {
+func (x *EnrollTOTPRequest) GetChallengeToken() string {
 	if x != nil {
-		return x.Skip
+		return x.ChallengeToken
 	}
-	return 0
+	return ""
 }
 
-func (x *ListProductsRequest) GetTake() uint32 {
+func (x *EnrollTOTPRequest) GetDeviceId() string {
 	if x != nil {
-		return x.Take
+		return x.DeviceId
 	}
-	return 0
+	return ""
 }
 
-type ListProductsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type EnrollTOTPResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Secret          string                 `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`                                          // base32, for typing into an authenticator app
+	ProvisioningUri string                 `protobuf:"bytes,2,opt,name=provisioning_uri,json=provisioningUri,proto3" json:"provisioning_uri,omitempty"` // otpauth:// URI, for a QR code
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *ListProductsResponse) Reset() {
-	*x = ListProductsResponse{}
-	mi := &file_control_proto_msgTypes[29]
+func (x *EnrollTOTPResponse) Reset() {
+	*x = EnrollTOTPResponse{}
+	mi := &file_control_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListProductsResponse) String() string {
+func (x *EnrollTOTPResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProductsResponse) ProtoMessage() {}
+func (*EnrollTOTPResponse) ProtoMessage() {}
 
-func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[29]
+func (x *EnrollTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1828,39 +2101,50 @@ func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
-func (*ListProductsResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use EnrollTOTPResponse.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *ListProductsResponse) GetProducts() []*Product {
+func (x *EnrollTOTPResponse) GetSecret() string {
 	if x != nil {
-		return x.Products
+		return x.Secret
 	}
-	return nil
+	return ""
 }
 
-type GetSystemMetricsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *EnrollTOTPResponse) GetProvisioningUri() string {
+	if x != nil {
+		return x.ProvisioningUri
+	}
+	return ""
 }
 
-func (x *GetSystemMetricsRequest) Reset() {
-	*x = GetSystemMetricsRequest{}
-	mi := &file_control_proto_msgTypes[30]
+type ConfirmTOTPRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Code           string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	ChallengeToken string                 `protobuf:"bytes,2,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	DeviceId       string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceName     string                 `protobuf:"bytes,4,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConfirmTOTPRequest) Reset() {
+	*x = ConfirmTOTPRequest{}
+	mi := &file_control_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSystemMetricsRequest) String() string {
+func (x *ConfirmTOTPRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSystemMetricsRequest) ProtoMessage() {}
+func (*ConfirmTOTPRequest) ProtoMessage() {}
 
-func (x *GetSystemMetricsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[30]
+func (x *ConfirmTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1871,34 +2155,63 @@ func (x *GetSystemMetricsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSystemMetricsRequest.ProtoReflect.Descriptor instead.
-func (*GetSystemMetricsRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use ConfirmTOTPRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{32}
 }
 
-type GetSystemMetricsResponse struct {
+func (x *ConfirmTOTPRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ConfirmTOTPRequest) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *ConfirmTOTPRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ConfirmTOTPRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+// login is set when confirmed with a challenge_token.
+type ConfirmTOTPResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TotalUsers    uint32                 `protobuf:"varint,1,opt,name=total_users,json=totalUsers,proto3" json:"total_users,omitempty"`
-	TotalProducts uint32                 `protobuf:"varint,2,opt,name=total_products,json=totalProducts,proto3" json:"total_products,omitempty"`
+	RecoveryCodes []string               `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	Login         *LoginResponse         `protobuf:"bytes,2,opt,name=login,proto3" json:"login,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSystemMetricsResponse) Reset() {
-	*x = GetSystemMetricsResponse{}
-	mi := &file_control_proto_msgTypes[31]
+func (x *ConfirmTOTPResponse) Reset() {
+	*x = ConfirmTOTPResponse{}
+	mi := &file_control_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSystemMetricsResponse) String() string {
+func (x *ConfirmTOTPResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSystemMetricsResponse) ProtoMessage() {}
+func (*ConfirmTOTPResponse) ProtoMessage() {}
 
-func (x *GetSystemMetricsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[31]
+func (x *ConfirmTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1909,52 +2222,52 @@ func (x *GetSystemMetricsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSystemMetricsResponse.ProtoReflect.Descriptor instead.
-func (*GetSystemMetricsResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use ConfirmTOTPResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *GetSystemMetricsResponse) GetTotalUsers() uint32 {
+func (x *ConfirmTOTPResponse) GetRecoveryCodes() []string {
 	if x != nil {
-		return x.TotalUsers
+		return x.RecoveryCodes
 	}
-	return 0
+	return nil
 }
 
-func (x *GetSystemMetricsResponse) GetTotalProducts() uint32 {
+func (x *ConfirmTOTPResponse) GetLogin() *LoginResponse {
 	if x != nil {
-		return x.TotalProducts
+		return x.Login
 	}
-	return 0
+	return nil
 }
 
-// Grades
-type CreateOrUpdateGradeRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Either code or recovery_code.
+type VerifyLoginChallengeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeToken string                 `protobuf:"bytes,1,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	DeviceId       string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceName     string                 `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	Code           string                 `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`
+	RecoveryCode   string                 `protobuf:"bytes,5,opt,name=recovery_code,json=recoveryCode,proto3" json:"recovery_code,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *CreateOrUpdateGradeRequest) Reset() {
-	*x = CreateOrUpdateGradeRequest{}
-	mi := &file_control_proto_msgTypes[32]
+func (x *VerifyLoginChallengeRequest) Reset() {
+	*x = VerifyLoginChallengeRequest{}
+	mi := &file_control_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrUpdateGradeRequest) String() string {
+func (x *VerifyLoginChallengeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrUpdateGradeRequest) ProtoMessage() {}
+func (*VerifyLoginChallengeRequest) ProtoMessage() {}
 
-func (x *CreateOrUpdateGradeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[32]
+func (x *VerifyLoginChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1965,68 +2278,69 @@ func (x *CreateOrUpdateGradeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrUpdateGradeRequest.ProtoReflect.Descriptor instead.
-func (*CreateOrUpdateGradeRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use VerifyLoginChallengeRequest.ProtoReflect.Descriptor instead.
+func (*VerifyLoginChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *CreateOrUpdateGradeRequest) GetId() string {
+func (x *VerifyLoginChallengeRequest) GetChallengeToken() string {
 	if x != nil {
-		return x.Id
+		return x.ChallengeToken
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateGradeRequest) GetProductId() string {
+func (x *VerifyLoginChallengeRequest) GetDeviceId() string {
 	if x != nil {
-		return x.ProductId
+		return x.DeviceId
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateGradeRequest) GetName() string {
+func (x *VerifyLoginChallengeRequest) GetDeviceName() string {
 	if x != nil {
-		return x.Name
+		return x.DeviceName
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateGradeRequest) GetDescription() string {
+func (x *VerifyLoginChallengeRequest) GetCode() string {
 	if x != nil {
-		return x.Description
+		return x.Code
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateGradeRequest) GetStatus() string {
+func (x *VerifyLoginChallengeRequest) GetRecoveryCode() string {
 	if x != nil {
-		return x.Status
+		return x.RecoveryCode
 	}
 	return ""
 }
 
-type CreateOrUpdateGradeResponse struct {
+type DisableTOTPRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Grade         *Grade                 `protobuf:"bytes,1,opt,name=grade,proto3" json:"grade,omitempty"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	RecoveryCode  string                 `protobuf:"bytes,2,opt,name=recovery_code,json=recoveryCode,proto3" json:"recovery_code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrUpdateGradeResponse) Reset() {
-	*x = CreateOrUpdateGradeResponse{}
-	mi := &file_control_proto_msgTypes[33]
+func (x *DisableTOTPRequest) Reset() {
+	*x = DisableTOTPRequest{}
+	mi := &file_control_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrUpdateGradeResponse) String() string {
+func (x *DisableTOTPRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrUpdateGradeResponse) ProtoMessage() {}
+func (*DisableTOTPRequest) ProtoMessage() {}
 
-func (x *CreateOrUpdateGradeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[33]
+func (x *DisableTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2037,42 +2351,3039 @@ func (x *CreateOrUpdateGradeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrUpdateGradeResponse.ProtoReflect.Descriptor instead.
-func (*CreateOrUpdateGradeResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use DisableTOTPRequest.ProtoReflect.Descriptor instead.
+func (*DisableTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *CreateOrUpdateGradeResponse) GetGrade() *Grade {
+func (x *DisableTOTPRequest) GetCode() string {
 	if x != nil {
-		return x.Grade
+		return x.Code
 	}
-	return nil
+	return ""
 }
 
-type ListGradesByProductIdRequest struct {
+func (x *DisableTOTPRequest) GetRecoveryCode() string {
+	if x != nil {
+		return x.RecoveryCode
+	}
+	return ""
+}
+
+type DisableTOTPResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Skip          uint32                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
-	Take          uint32                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListGradesByProductIdRequest) Reset() {
-	*x = ListGradesByProductIdRequest{}
-	mi := &file_control_proto_msgTypes[34]
+func (x *DisableTOTPResponse) Reset() {
+	*x = DisableTOTPResponse{}
+	mi := &file_control_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableTOTPResponse) ProtoMessage() {}
+
+func (x *DisableTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableTOTPResponse.ProtoReflect.Descriptor instead.
+func (*DisableTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DisableTOTPResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegenerateRecoveryCodesRequest) Reset() {
+	*x = RegenerateRecoveryCodesRequest{}
+	mi := &file_control_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegenerateRecoveryCodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegenerateRecoveryCodesRequest) ProtoMessage() {}
+
+func (x *RegenerateRecoveryCodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegenerateRecoveryCodesRequest.ProtoReflect.Descriptor instead.
+func (*RegenerateRecoveryCodesRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RegenerateRecoveryCodesRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type RegenerateRecoveryCodesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecoveryCodes []string               `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegenerateRecoveryCodesResponse) Reset() {
+	*x = RegenerateRecoveryCodesResponse{}
+	mi := &file_control_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegenerateRecoveryCodesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegenerateRecoveryCodesResponse) ProtoMessage() {}
+
+func (x *RegenerateRecoveryCodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegenerateRecoveryCodesResponse.ProtoReflect.Descriptor instead.
+func (*RegenerateRecoveryCodesResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RegenerateRecoveryCodesResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+// JSONWebKey is the public half of a JWT signing key in JWK form. RSA keys set n and e,
+// Ed25519 keys set crv and x.
+type JSONWebKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kty           string                 `protobuf:"bytes,1,opt,name=kty,proto3" json:"kty,omitempty"`
+	Kid           string                 `protobuf:"bytes,2,opt,name=kid,proto3" json:"kid,omitempty"`
+	Use           string                 `protobuf:"bytes,3,opt,name=use,proto3" json:"use,omitempty"`
+	Alg           string                 `protobuf:"bytes,4,opt,name=alg,proto3" json:"alg,omitempty"`
+	N             string                 `protobuf:"bytes,5,opt,name=n,proto3" json:"n,omitempty"`
+	E             string                 `protobuf:"bytes,6,opt,name=e,proto3" json:"e,omitempty"`
+	Crv           string                 `protobuf:"bytes,7,opt,name=crv,proto3" json:"crv,omitempty"`
+	X             string                 `protobuf:"bytes,8,opt,name=x,proto3" json:"x,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JSONWebKey) Reset() {
+	*x = JSONWebKey{}
+	mi := &file_control_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JSONWebKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JSONWebKey) ProtoMessage() {}
+
+func (x *JSONWebKey) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JSONWebKey.ProtoReflect.Descriptor instead.
+func (*JSONWebKey) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *JSONWebKey) GetKty() string {
+	if x != nil {
+		return x.Kty
+	}
+	return ""
+}
+
+func (x *JSONWebKey) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+func (x *JSONWebKey) GetUse() string {
+	if x != nil {
+		return x.Use
+	}
+	return ""
+}
+
+func (x *JSONWebKey) GetAlg() string {
+	if x != nil {
+		return x.Alg
+	}
+	return ""
+}
+
+func (x *JSONWebKey) GetN() string {
+	if x != nil {
+		return x.N
+	}
+	return ""
+}
+
+func (x *JSONWebKey) GetE() string {
+	if x != nil {
+		return x.E
+	}
+	return ""
+}
+
+func (x *JSONWebKey) GetCrv() string {
+	if x != nil {
+		return x.Crv
+	}
+	return ""
+}
+
+func (x *JSONWebKey) GetX() string {
+	if x != nil {
+		return x.X
+	}
+	return ""
+}
+
+type GetJWKSRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJWKSRequest) Reset() {
+	*x = GetJWKSRequest{}
+	mi := &file_control_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJWKSRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJWKSRequest) ProtoMessage() {}
+
+func (x *GetJWKSRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJWKSRequest.ProtoReflect.Descriptor instead.
+func (*GetJWKSRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{40}
+}
+
+type GetJWKSResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*JSONWebKey          `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJWKSResponse) Reset() {
+	*x = GetJWKSResponse{}
+	mi := &file_control_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJWKSResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJWKSResponse) ProtoMessage() {}
+
+func (x *GetJWKSResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJWKSResponse.ProtoReflect.Descriptor instead.
+func (*GetJWKSResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetJWKSResponse) GetKeys() []*JSONWebKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// CheckSessionRequest asks whether an access token's session is still signed in. Market
+// calls it so revoked sessions stop trading before their tokens expire.
+type CheckSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckSessionRequest) Reset() {
+	*x = CheckSessionRequest{}
+	mi := &file_control_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckSessionRequest) ProtoMessage() {}
+
+func (x *CheckSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckSessionRequest.ProtoReflect.Descriptor instead.
+func (*CheckSessionRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CheckSessionRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+type CheckSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Active        bool                   `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckSessionResponse) Reset() {
+	*x = CheckSessionResponse{}
+	mi := &file_control_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckSessionResponse) ProtoMessage() {}
+
+func (x *CheckSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckSessionResponse.ProtoReflect.Descriptor instead.
+func (*CheckSessionResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *CheckSessionResponse) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+type CreateOrUpdateMerchantDetailsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	PhoneNumber   string                 `protobuf:"bytes,3,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Address       string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	City          string                 `protobuf:"bytes,5,opt,name=city,proto3" json:"city,omitempty"`
+	State         string                 `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	Pincode       string                 `protobuf:"bytes,7,opt,name=pincode,proto3" json:"pincode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateMerchantDetailsRequest) Reset() {
+	*x = CreateOrUpdateMerchantDetailsRequest{}
+	mi := &file_control_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateMerchantDetailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateMerchantDetailsRequest) ProtoMessage() {}
+
+func (x *CreateOrUpdateMerchantDetailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateMerchantDetailsRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateMerchantDetailsRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *CreateOrUpdateMerchantDetailsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantDetailsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantDetailsRequest) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantDetailsRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantDetailsRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantDetailsRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantDetailsRequest) GetPincode() string {
+	if x != nil {
+		return x.Pincode
+	}
+	return ""
+}
+
+type CreateOrUpdateMerchantInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PhoneNumber   string                 `protobuf:"bytes,2,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	City          string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	State         string                 `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	Pincode       string                 `protobuf:"bytes,6,opt,name=pincode,proto3" json:"pincode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateMerchantInfoRequest) Reset() {
+	*x = CreateOrUpdateMerchantInfoRequest{}
+	mi := &file_control_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateMerchantInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateMerchantInfoRequest) ProtoMessage() {}
+
+func (x *CreateOrUpdateMerchantInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateMerchantInfoRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateMerchantInfoRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *CreateOrUpdateMerchantInfoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantInfoRequest) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantInfoRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantInfoRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantInfoRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateMerchantInfoRequest) GetPincode() string {
+	if x != nil {
+		return x.Pincode
+	}
+	return ""
+}
+
+type CreateOrUpdateMerchantDetailsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MerchantDetails *MerchantDetails       `protobuf:"bytes,1,opt,name=merchant_details,json=merchantDetails,proto3" json:"merchant_details,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateMerchantDetailsResponse) Reset() {
+	*x = CreateOrUpdateMerchantDetailsResponse{}
+	mi := &file_control_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateMerchantDetailsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateMerchantDetailsResponse) ProtoMessage() {}
+
+func (x *CreateOrUpdateMerchantDetailsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateMerchantDetailsResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateMerchantDetailsResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CreateOrUpdateMerchantDetailsResponse) GetMerchantDetails() *MerchantDetails {
+	if x != nil {
+		return x.MerchantDetails
+	}
+	return nil
+}
+
+type GetMerchantDetailsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMerchantDetailsRequest) Reset() {
+	*x = GetMerchantDetailsRequest{}
+	mi := &file_control_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMerchantDetailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMerchantDetailsRequest) ProtoMessage() {}
+
+func (x *GetMerchantDetailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMerchantDetailsRequest.ProtoReflect.Descriptor instead.
+func (*GetMerchantDetailsRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetMerchantDetailsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type GetMerchantDetailsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MerchantDetails *MerchantDetails       `protobuf:"bytes,1,opt,name=merchant_details,json=merchantDetails,proto3" json:"merchant_details,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetMerchantDetailsResponse) Reset() {
+	*x = GetMerchantDetailsResponse{}
+	mi := &file_control_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMerchantDetailsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMerchantDetailsResponse) ProtoMessage() {}
+
+func (x *GetMerchantDetailsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMerchantDetailsResponse.ProtoReflect.Descriptor instead.
+func (*GetMerchantDetailsResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *GetMerchantDetailsResponse) GetMerchantDetails() *MerchantDetails {
+	if x != nil {
+		return x.MerchantDetails
+	}
+	return nil
+}
+
+// Products
+type CreateOrUpdateProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateProductRequest) Reset() {
+	*x = CreateOrUpdateProductRequest{}
+	mi := &file_control_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateProductRequest) ProtoMessage() {}
+
+func (x *CreateOrUpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *CreateOrUpdateProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateProductRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateProductRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateProductRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateProductRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type CreateOrUpdateProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateProductResponse) Reset() {
+	*x = CreateOrUpdateProductResponse{}
+	mi := &file_control_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateProductResponse) ProtoMessage() {}
+
+func (x *CreateOrUpdateProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateProductResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateProductResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *CreateOrUpdateProductResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type ListProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Skip          uint32                 `protobuf:"varint,1,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint32                 `protobuf:"varint,2,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsRequest) Reset() {
+	*x = ListProductsRequest{}
+	mi := &file_control_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsRequest) ProtoMessage() {}
+
+func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
+func (*ListProductsRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ListProductsRequest) GetSkip() uint32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *ListProductsRequest) GetTake() uint32 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type ListProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsResponse) Reset() {
+	*x = ListProductsResponse{}
+	mi := &file_control_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsResponse) ProtoMessage() {}
+
+func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
+func (*ListProductsResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListProductsResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type GetSystemMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSystemMetricsRequest) Reset() {
+	*x = GetSystemMetricsRequest{}
+	mi := &file_control_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSystemMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSystemMetricsRequest) ProtoMessage() {}
+
+func (x *GetSystemMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSystemMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetSystemMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{53}
+}
+
+type GetSystemMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalUsers    uint32                 `protobuf:"varint,1,opt,name=total_users,json=totalUsers,proto3" json:"total_users,omitempty"`
+	TotalProducts uint32                 `protobuf:"varint,2,opt,name=total_products,json=totalProducts,proto3" json:"total_products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSystemMetricsResponse) Reset() {
+	*x = GetSystemMetricsResponse{}
+	mi := &file_control_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSystemMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSystemMetricsResponse) ProtoMessage() {}
+
+func (x *GetSystemMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSystemMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetSystemMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *GetSystemMetricsResponse) GetTotalUsers() uint32 {
+	if x != nil {
+		return x.TotalUsers
+	}
+	return 0
+}
+
+func (x *GetSystemMetricsResponse) GetTotalProducts() uint32 {
+	if x != nil {
+		return x.TotalProducts
+	}
+	return 0
+}
+
+// Grades
+type CreateOrUpdateGradeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	ShelfLifeDays uint32                 `protobuf:"varint,6,opt,name=shelf_life_days,json=shelfLifeDays,proto3" json:"shelf_life_days,omitempty"` // optional; 0 = not perishable
+	Unit          string                 `protobuf:"bytes,7,opt,name=unit,proto3" json:"unit,omitempty"`                                           // optional; KG (default), QUINTAL, TONNE or BAG
+	KgPerUnit     string                 `protobuf:"bytes,8,opt,name=kg_per_unit,json=kgPerUnit,proto3" json:"kg_per_unit,omitempty"`              // decimal string; required for BAG, fixed for the other units
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateGradeRequest) Reset() {
+	*x = CreateOrUpdateGradeRequest{}
+	mi := &file_control_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateGradeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateGradeRequest) ProtoMessage() {}
+
+func (x *CreateOrUpdateGradeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateGradeRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateGradeRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *CreateOrUpdateGradeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateGradeRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateGradeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateGradeRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateGradeRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateGradeRequest) GetShelfLifeDays() uint32 {
+	if x != nil {
+		return x.ShelfLifeDays
+	}
+	return 0
+}
+
+func (x *CreateOrUpdateGradeRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateGradeRequest) GetKgPerUnit() string {
+	if x != nil {
+		return x.KgPerUnit
+	}
+	return ""
+}
+
+type CreateOrUpdateGradeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Grade         *Grade                 `protobuf:"bytes,1,opt,name=grade,proto3" json:"grade,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateGradeResponse) Reset() {
+	*x = CreateOrUpdateGradeResponse{}
+	mi := &file_control_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateGradeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateGradeResponse) ProtoMessage() {}
+
+func (x *CreateOrUpdateGradeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateGradeResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateGradeResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CreateOrUpdateGradeResponse) GetGrade() *Grade {
+	if x != nil {
+		return x.Grade
+	}
+	return nil
+}
+
+type ListGradesByProductIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Skip          uint32                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint32                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGradesByProductIdRequest) Reset() {
+	*x = ListGradesByProductIdRequest{}
+	mi := &file_control_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGradesByProductIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGradesByProductIdRequest) ProtoMessage() {}
+
+func (x *ListGradesByProductIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGradesByProductIdRequest.ProtoReflect.Descriptor instead.
+func (*ListGradesByProductIdRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListGradesByProductIdRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ListGradesByProductIdRequest) GetSkip() uint32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *ListGradesByProductIdRequest) GetTake() uint32 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type ListGradesByProductIdResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Grades        []*Grade               `protobuf:"bytes,1,rep,name=grades,proto3" json:"grades,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGradesByProductIdResponse) Reset() {
+	*x = ListGradesByProductIdResponse{}
+	mi := &file_control_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGradesByProductIdResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGradesByProductIdResponse) ProtoMessage() {}
+
+func (x *ListGradesByProductIdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGradesByProductIdResponse.ProtoReflect.Descriptor instead.
+func (*ListGradesByProductIdResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ListGradesByProductIdResponse) GetGrades() []*Grade {
+	if x != nil {
+		return x.Grades
+	}
+	return nil
+}
+
+// Daily Prices
+// Proposes a price tick. It is SUBMITTED for approval (or kept as a DRAFT) and changes no daily
+// price until a second admin approves it with ReviewPriceTicks.
+type CreateOrUpdateDailyPriceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // optional tick id
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	GradeId       string                 `protobuf:"bytes,3,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
+	Price         string                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"` // decimal string, 4 dp
+	Date          string                 `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
+	Time          string                 `protobuf:"bytes,6,opt,name=time,proto3" json:"time,omitempty"`
+	Source        string                 `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`     // defaults to MANUAL
+	Draft         bool                   `protobuf:"varint,8,opt,name=draft,proto3" json:"draft,omitempty"`      // keep as DRAFT instead of submitting
+	Currency      string                 `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"` // defaults to INR; a day's approved prices share one currency
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) Reset() {
+	*x = CreateOrUpdateDailyPriceRequest{}
+	mi := &file_control_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateDailyPriceRequest) ProtoMessage() {}
+
+func (x *CreateOrUpdateDailyPriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateDailyPriceRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateDailyPriceRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) GetGradeId() string {
+	if x != nil {
+		return x.GradeId
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) GetDraft() bool {
+	if x != nil {
+		return x.Draft
+	}
+	return false
+}
+
+func (x *CreateOrUpdateDailyPriceRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CreateOrUpdateDailyPriceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tick          *PriceTick             `protobuf:"bytes,2,opt,name=tick,proto3" json:"tick,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateDailyPriceResponse) Reset() {
+	*x = CreateOrUpdateDailyPriceResponse{}
+	mi := &file_control_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateDailyPriceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateDailyPriceResponse) ProtoMessage() {}
+
+func (x *CreateOrUpdateDailyPriceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateDailyPriceResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateDailyPriceResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *CreateOrUpdateDailyPriceResponse) GetTick() *PriceTick {
+	if x != nil {
+		return x.Tick
+	}
+	return nil
+}
+
+type SubmitPriceTickRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitPriceTickRequest) Reset() {
+	*x = SubmitPriceTickRequest{}
+	mi := &file_control_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitPriceTickRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitPriceTickRequest) ProtoMessage() {}
+
+func (x *SubmitPriceTickRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitPriceTickRequest.ProtoReflect.Descriptor instead.
+func (*SubmitPriceTickRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *SubmitPriceTickRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type SubmitPriceTickResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tick          *PriceTick             `protobuf:"bytes,1,opt,name=tick,proto3" json:"tick,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitPriceTickResponse) Reset() {
+	*x = SubmitPriceTickResponse{}
+	mi := &file_control_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitPriceTickResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitPriceTickResponse) ProtoMessage() {}
+
+func (x *SubmitPriceTickResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitPriceTickResponse.ProtoReflect.Descriptor instead.
+func (*SubmitPriceTickResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *SubmitPriceTickResponse) GetTick() *PriceTick {
+	if x != nil {
+		return x.Tick
+	}
+	return nil
+}
+
+// Approves or rejects SUBMITTED ticks, all or none. The reviewer must not be the proposer.
+type ReviewPriceTicksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	Decision      string                 `protobuf:"bytes,2,opt,name=decision,proto3" json:"decision,omitempty"` // APPROVE | REJECT
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewPriceTicksRequest) Reset() {
+	*x = ReviewPriceTicksRequest{}
+	mi := &file_control_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewPriceTicksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewPriceTicksRequest) ProtoMessage() {}
+
+func (x *ReviewPriceTicksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewPriceTicksRequest.ProtoReflect.Descriptor instead.
+func (*ReviewPriceTicksRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *ReviewPriceTicksRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *ReviewPriceTicksRequest) GetDecision() string {
+	if x != nil {
+		return x.Decision
+	}
+	return ""
+}
+
+func (x *ReviewPriceTicksRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type ReviewPriceTicksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticks         []*PriceTick           `protobuf:"bytes,1,rep,name=ticks,proto3" json:"ticks,omitempty"`
+	DailyPrices   []*DailyPrice          `protobuf:"bytes,2,rep,name=daily_prices,json=dailyPrices,proto3" json:"daily_prices,omitempty"` // rollups changed by an approval
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewPriceTicksResponse) Reset() {
+	*x = ReviewPriceTicksResponse{}
+	mi := &file_control_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewPriceTicksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewPriceTicksResponse) ProtoMessage() {}
+
+func (x *ReviewPriceTicksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewPriceTicksResponse.ProtoReflect.Descriptor instead.
+func (*ReviewPriceTicksResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ReviewPriceTicksResponse) GetTicks() []*PriceTick {
+	if x != nil {
+		return x.Ticks
+	}
+	return nil
+}
+
+func (x *ReviewPriceTicksResponse) GetDailyPrices() []*DailyPrice {
+	if x != nil {
+		return x.DailyPrices
+	}
+	return nil
+}
+
+// Submits a sheet of price ticks for approval in one transaction: all rows are applied or none are.
+type CreateOrUpdateDailyPricesRequest struct {
+	state          protoimpl.MessageState             `protogen:"open.v1"`
+	Prices         []*CreateOrUpdateDailyPriceRequest `protobuf:"bytes,1,rep,name=prices,proto3" json:"prices,omitempty"`
+	MaxMovePercent string                             `protobuf:"bytes,2,opt,name=max_move_percent,json=maxMovePercent,proto3" json:"max_move_percent,omitempty"` // outlier limit vs the previous price; empty = server default, "0" = off
+	DryRun         bool                               `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                          // validate and report without saving
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateDailyPricesRequest) Reset() {
+	*x = CreateOrUpdateDailyPricesRequest{}
+	mi := &file_control_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateDailyPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateDailyPricesRequest) ProtoMessage() {}
+
+func (x *CreateOrUpdateDailyPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateDailyPricesRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateDailyPricesRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *CreateOrUpdateDailyPricesRequest) GetPrices() []*CreateOrUpdateDailyPriceRequest {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+func (x *CreateOrUpdateDailyPricesRequest) GetMaxMovePercent() string {
+	if x != nil {
+		return x.MaxMovePercent
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateDailyPricesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type PriceImportRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"` // 1-based position among the prices; a CSV header is not counted
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	GradeId       string                 `protobuf:"bytes,3,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
+	Price         string                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // OK | ERROR
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	MovePercent   string                 `protobuf:"bytes,7,opt,name=move_percent,json=movePercent,proto3" json:"move_percent,omitempty"` // vs the grade's previous price; empty when it has none
+	Tick          *PriceTick             `protobuf:"bytes,8,opt,name=tick,proto3" json:"tick,omitempty"`                                  // the validated tick, for OK rows; saved only when applied
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceImportRow) Reset() {
+	*x = PriceImportRow{}
+	mi := &file_control_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceImportRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceImportRow) ProtoMessage() {}
+
+func (x *PriceImportRow) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceImportRow.ProtoReflect.Descriptor instead.
+func (*PriceImportRow) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *PriceImportRow) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *PriceImportRow) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PriceImportRow) GetGradeId() string {
+	if x != nil {
+		return x.GradeId
+	}
+	return ""
+}
+
+func (x *PriceImportRow) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *PriceImportRow) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PriceImportRow) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PriceImportRow) GetMovePercent() string {
+	if x != nil {
+		return x.MovePercent
+	}
+	return ""
+}
+
+func (x *PriceImportRow) GetTick() *PriceTick {
+	if x != nil {
+		return x.Tick
+	}
+	return nil
+}
+
+type CreateOrUpdateDailyPricesResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Applied        bool                   `protobuf:"varint,1,opt,name=applied,proto3" json:"applied,omitempty"`
+	DryRun         bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	MaxMovePercent string                 `protobuf:"bytes,3,opt,name=max_move_percent,json=maxMovePercent,proto3" json:"max_move_percent,omitempty"`
+	Accepted       int32                  `protobuf:"varint,4,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejected       int32                  `protobuf:"varint,5,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	Rows           []*PriceImportRow      `protobuf:"bytes,6,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateOrUpdateDailyPricesResponse) Reset() {
+	*x = CreateOrUpdateDailyPricesResponse{}
+	mi := &file_control_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrUpdateDailyPricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrUpdateDailyPricesResponse) ProtoMessage() {}
+
+func (x *CreateOrUpdateDailyPricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrUpdateDailyPricesResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrUpdateDailyPricesResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *CreateOrUpdateDailyPricesResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *CreateOrUpdateDailyPricesResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *CreateOrUpdateDailyPricesResponse) GetMaxMovePercent() string {
+	if x != nil {
+		return x.MaxMovePercent
+	}
+	return ""
+}
+
+func (x *CreateOrUpdateDailyPricesResponse) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *CreateOrUpdateDailyPricesResponse) GetRejected() int32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *CreateOrUpdateDailyPricesResponse) GetRows() []*PriceImportRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type ListDailyPricesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GradeId       string                 `protobuf:"bytes,1,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
+	Today         string                 `protobuf:"bytes,2,opt,name=today,proto3" json:"today,omitempty"`
+	Duration      int32                  `protobuf:"varint,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDailyPricesRequest) Reset() {
+	*x = ListDailyPricesRequest{}
+	mi := &file_control_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDailyPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDailyPricesRequest) ProtoMessage() {}
+
+func (x *ListDailyPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDailyPricesRequest.ProtoReflect.Descriptor instead.
+func (*ListDailyPricesRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ListDailyPricesRequest) GetGradeId() string {
+	if x != nil {
+		return x.GradeId
+	}
+	return ""
+}
+
+func (x *ListDailyPricesRequest) GetToday() string {
+	if x != nil {
+		return x.Today
+	}
+	return ""
+}
+
+func (x *ListDailyPricesRequest) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+type ListDailyPricesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DailyPrices   []*DailyPrice          `protobuf:"bytes,1,rep,name=daily_prices,json=dailyPrices,proto3" json:"daily_prices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDailyPricesResponse) Reset() {
+	*x = ListDailyPricesResponse{}
+	mi := &file_control_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDailyPricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDailyPricesResponse) ProtoMessage() {}
+
+func (x *ListDailyPricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDailyPricesResponse.ProtoReflect.Descriptor instead.
+func (*ListDailyPricesResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ListDailyPricesResponse) GetDailyPrices() []*DailyPrice {
+	if x != nil {
+		return x.DailyPrices
+	}
+	return nil
+}
+
+type GetTodaysPriceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GradeId       string                 `protobuf:"bytes,1,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
+	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	PriceBasis    string                 `protobuf:"bytes,3,opt,name=price_basis,json=priceBasis,proto3" json:"price_basis,omitempty"` // LAST (default) | CLOSE
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodaysPriceRequest) Reset() {
+	*x = GetTodaysPriceRequest{}
+	mi := &file_control_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodaysPriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodaysPriceRequest) ProtoMessage() {}
+
+func (x *GetTodaysPriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodaysPriceRequest.ProtoReflect.Descriptor instead.
+func (*GetTodaysPriceRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *GetTodaysPriceRequest) GetGradeId() string {
+	if x != nil {
+		return x.GradeId
+	}
+	return ""
+}
+
+func (x *GetTodaysPriceRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *GetTodaysPriceRequest) GetPriceBasis() string {
+	if x != nil {
+		return x.PriceBasis
+	}
+	return ""
+}
+
+type GetTodaysPriceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DailyPrices   []*DailyPrice          `protobuf:"bytes,1,rep,name=daily_prices,json=dailyPrices,proto3" json:"daily_prices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodaysPriceResponse) Reset() {
+	*x = GetTodaysPriceResponse{}
+	mi := &file_control_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodaysPriceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodaysPriceResponse) ProtoMessage() {}
+
+func (x *GetTodaysPriceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodaysPriceResponse.ProtoReflect.Descriptor instead.
+func (*GetTodaysPriceResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *GetTodaysPriceResponse) GetDailyPrices() []*DailyPrice {
+	if x != nil {
+		return x.DailyPrices
+	}
+	return nil
+}
+
+type GetTodaysByProductIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	PriceBasis    string                 `protobuf:"bytes,3,opt,name=price_basis,json=priceBasis,proto3" json:"price_basis,omitempty"` // LAST (default) | CLOSE
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodaysByProductIdRequest) Reset() {
+	*x = GetTodaysByProductIdRequest{}
+	mi := &file_control_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodaysByProductIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodaysByProductIdRequest) ProtoMessage() {}
+
+func (x *GetTodaysByProductIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodaysByProductIdRequest.ProtoReflect.Descriptor instead.
+func (*GetTodaysByProductIdRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *GetTodaysByProductIdRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetTodaysByProductIdRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *GetTodaysByProductIdRequest) GetPriceBasis() string {
+	if x != nil {
+		return x.PriceBasis
+	}
+	return ""
+}
+
+type GetTodaysByProductIdResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DailyPrices   []*DailyPrice          `protobuf:"bytes,1,rep,name=daily_prices,json=dailyPrices,proto3" json:"daily_prices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodaysByProductIdResponse) Reset() {
+	*x = GetTodaysByProductIdResponse{}
+	mi := &file_control_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodaysByProductIdResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodaysByProductIdResponse) ProtoMessage() {}
+
+func (x *GetTodaysByProductIdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodaysByProductIdResponse.ProtoReflect.Descriptor instead.
+func (*GetTodaysByProductIdResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *GetTodaysByProductIdResponse) GetDailyPrices() []*DailyPrice {
+	if x != nil {
+		return x.DailyPrices
+	}
+	return nil
+}
+
+// A grade's ticks on one date, or with only a status, every tick in that status.
+type ListPriceTicksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GradeId       string                 `protobuf:"bytes,1,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
+	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`     // YYYY-MM-DD, defaults to today when grade_id is set
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // optional: DRAFT | SUBMITTED | APPROVED | REJECTED
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPriceTicksRequest) Reset() {
+	*x = ListPriceTicksRequest{}
+	mi := &file_control_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPriceTicksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPriceTicksRequest) ProtoMessage() {}
+
+func (x *ListPriceTicksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPriceTicksRequest.ProtoReflect.Descriptor instead.
+func (*ListPriceTicksRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ListPriceTicksRequest) GetGradeId() string {
+	if x != nil {
+		return x.GradeId
+	}
+	return ""
+}
+
+func (x *ListPriceTicksRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ListPriceTicksRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListPriceTicksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticks         []*PriceTick           `protobuf:"bytes,1,rep,name=ticks,proto3" json:"ticks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPriceTicksResponse) Reset() {
+	*x = ListPriceTicksResponse{}
+	mi := &file_control_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPriceTicksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPriceTicksResponse) ProtoMessage() {}
+
+func (x *ListPriceTicksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPriceTicksResponse.ProtoReflect.Descriptor instead.
+func (*ListPriceTicksResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *ListPriceTicksResponse) GetTicks() []*PriceTick {
+	if x != nil {
+		return x.Ticks
+	}
+	return nil
+}
+
+// One grade (grade_id) or every grade of a product (product_id).
+type GetPriceCandlesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GradeId       string                 `protobuf:"bytes,1,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Interval      string                 `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`                  // DAY (default) | WEEK | MONTH
+	DateFrom      string                 `protobuf:"bytes,4,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`  // YYYY-MM-DD; defaults to 30 days, 12 weeks or 12 months back
+	DateTo        string                 `protobuf:"bytes,5,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`        // YYYY-MM-DD; defaults to today
+	FillGaps      bool                   `protobuf:"varint,6,opt,name=fill_gaps,json=fillGaps,proto3" json:"fill_gaps,omitempty"` // return flat candles at the previous close for buckets with no price
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPriceCandlesRequest) Reset() {
+	*x = GetPriceCandlesRequest{}
+	mi := &file_control_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceCandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceCandlesRequest) ProtoMessage() {}
+
+func (x *GetPriceCandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceCandlesRequest.ProtoReflect.Descriptor instead.
+func (*GetPriceCandlesRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *GetPriceCandlesRequest) GetGradeId() string {
+	if x != nil {
+		return x.GradeId
+	}
+	return ""
+}
+
+func (x *GetPriceCandlesRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetPriceCandlesRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetPriceCandlesRequest) GetDateFrom() string {
+	if x != nil {
+		return x.DateFrom
+	}
+	return ""
+}
+
+func (x *GetPriceCandlesRequest) GetDateTo() string {
+	if x != nil {
+		return x.DateTo
+	}
+	return ""
+}
+
+func (x *GetPriceCandlesRequest) GetFillGaps() bool {
+	if x != nil {
+		return x.FillGaps
+	}
+	return false
+}
+
+type Candle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PeriodStart   string                 `protobuf:"bytes,1,opt,name=period_start,json=periodStart,proto3" json:"period_start,omitempty"` // YYYY-MM-DD
+	PeriodEnd     string                 `protobuf:"bytes,2,opt,name=period_end,json=periodEnd,proto3" json:"period_end,omitempty"`
+	Open          string                 `protobuf:"bytes,3,opt,name=open,proto3" json:"open,omitempty"`
+	High          string                 `protobuf:"bytes,4,opt,name=high,proto3" json:"high,omitempty"`
+	Low           string                 `protobuf:"bytes,5,opt,name=low,proto3" json:"low,omitempty"`
+	Close         string                 `protobuf:"bytes,6,opt,name=close,proto3" json:"close,omitempty"`
+	TickCount     int32                  `protobuf:"varint,7,opt,name=tick_count,json=tickCount,proto3" json:"tick_count,omitempty"`
+	ChangePercent string                 `protobuf:"bytes,8,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"` // vs the previous close; empty when there is none
+	Sma_7         string                 `protobuf:"bytes,9,opt,name=sma_7,json=sma7,proto3" json:"sma_7,omitempty"`                            // 7-day moving average of daily closes; empty until 7 days are known
+	Sma_30        string                 `protobuf:"bytes,10,opt,name=sma_30,json=sma30,proto3" json:"sma_30,omitempty"`
+	Filled        bool                   `protobuf:"varint,11,opt,name=filled,proto3" json:"filled,omitempty"` // no price was published in this bucket
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	mi := &file_control_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *Candle) GetPeriodStart() string {
+	if x != nil {
+		return x.PeriodStart
+	}
+	return ""
+}
+
+func (x *Candle) GetPeriodEnd() string {
+	if x != nil {
+		return x.PeriodEnd
+	}
+	return ""
+}
+
+func (x *Candle) GetOpen() string {
+	if x != nil {
+		return x.Open
+	}
+	return ""
+}
+
+func (x *Candle) GetHigh() string {
+	if x != nil {
+		return x.High
+	}
+	return ""
+}
+
+func (x *Candle) GetLow() string {
+	if x != nil {
+		return x.Low
+	}
+	return ""
+}
+
+func (x *Candle) GetClose() string {
+	if x != nil {
+		return x.Close
+	}
+	return ""
+}
+
+func (x *Candle) GetTickCount() int32 {
+	if x != nil {
+		return x.TickCount
+	}
+	return 0
+}
+
+func (x *Candle) GetChangePercent() string {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return ""
+}
+
+func (x *Candle) GetSma_7() string {
+	if x != nil {
+		return x.Sma_7
+	}
+	return ""
+}
+
+func (x *Candle) GetSma_30() string {
+	if x != nil {
+		return x.Sma_30
+	}
+	return ""
+}
+
+func (x *Candle) GetFilled() bool {
+	if x != nil {
+		return x.Filled
+	}
+	return false
+}
+
+type PriceSeries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GradeId       string                 `protobuf:"bytes,1,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Interval      string                 `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	Candles       []*Candle              `protobuf:"bytes,4,rep,name=candles,proto3" json:"candles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceSeries) Reset() {
+	*x = PriceSeries{}
+	mi := &file_control_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceSeries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceSeries) ProtoMessage() {}
+
+func (x *PriceSeries) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceSeries.ProtoReflect.Descriptor instead.
+func (*PriceSeries) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *PriceSeries) GetGradeId() string {
+	if x != nil {
+		return x.GradeId
+	}
+	return ""
+}
+
+func (x *PriceSeries) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PriceSeries) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *PriceSeries) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+type GetPriceCandlesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Series        []*PriceSeries         `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPriceCandlesResponse) Reset() {
+	*x = GetPriceCandlesResponse{}
+	mi := &file_control_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceCandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceCandlesResponse) ProtoMessage() {}
+
+func (x *GetPriceCandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceCandlesResponse.ProtoReflect.Descriptor instead.
+func (*GetPriceCandlesResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *GetPriceCandlesResponse) GetSeries() []*PriceSeries {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+// Leave epoch and after_sequence empty for a fresh subscription. To resume after a disconnect,
+// send the epoch and sequence of the last event received.
+type SubscribePricesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GradeId       string                 `protobuf:"bytes,1,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`       // optional grade filter
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"` // optional product filter
+	Epoch         string                 `protobuf:"bytes,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	AfterSequence uint64                 `protobuf:"varint,4,opt,name=after_sequence,json=afterSequence,proto3" json:"after_sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribePricesRequest) Reset() {
+	*x = SubscribePricesRequest{}
+	mi := &file_control_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribePricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribePricesRequest) ProtoMessage() {}
+
+func (x *SubscribePricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribePricesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribePricesRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *SubscribePricesRequest) GetGradeId() string {
+	if x != nil {
+		return x.GradeId
+	}
+	return ""
+}
+
+func (x *SubscribePricesRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *SubscribePricesRequest) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *SubscribePricesRequest) GetAfterSequence() uint64 {
+	if x != nil {
+		return x.AfterSequence
+	}
+	return 0
+}
+
+type PriceEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Epoch         string                 `protobuf:"bytes,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	DailyPrice    *DailyPrice            `protobuf:"bytes,3,opt,name=daily_price,json=dailyPrice,proto3" json:"daily_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceEvent) Reset() {
+	*x = PriceEvent{}
+	mi := &file_control_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceEvent) ProtoMessage() {}
+
+func (x *PriceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceEvent.ProtoReflect.Descriptor instead.
+func (*PriceEvent) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *PriceEvent) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *PriceEvent) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *PriceEvent) GetDailyPrice() *DailyPrice {
+	if x != nil {
+		return x.DailyPrice
+	}
+	return nil
+}
+
+type GetProductsWithGradesAndPricesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Search        string                 `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsWithGradesAndPricesRequest) Reset() {
+	*x = GetProductsWithGradesAndPricesRequest{}
+	mi := &file_control_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsWithGradesAndPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsWithGradesAndPricesRequest) ProtoMessage() {}
+
+func (x *GetProductsWithGradesAndPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsWithGradesAndPricesRequest.ProtoReflect.Descriptor instead.
+func (*GetProductsWithGradesAndPricesRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *GetProductsWithGradesAndPricesRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *GetProductsWithGradesAndPricesRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+type GetProductsWithGradesAndPricesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*ProductWithGrades   `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsWithGradesAndPricesResponse) Reset() {
+	*x = GetProductsWithGradesAndPricesResponse{}
+	mi := &file_control_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsWithGradesAndPricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsWithGradesAndPricesResponse) ProtoMessage() {}
+
+func (x *GetProductsWithGradesAndPricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsWithGradesAndPricesResponse.ProtoReflect.Descriptor instead.
+func (*GetProductsWithGradesAndPricesResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *GetProductsWithGradesAndPricesResponse) GetProducts() []*ProductWithGrades {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+// FX rates: 1 base_currency = rate quote_currency from effective_date on.
+type FxRate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BaseCurrency  string                 `protobuf:"bytes,2,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	QuoteCurrency string                 `protobuf:"bytes,3,opt,name=quote_currency,json=quoteCurrency,proto3" json:"quote_currency,omitempty"`
+	Rate          string                 `protobuf:"bytes,4,opt,name=rate,proto3" json:"rate,omitempty"`                                        // decimal string, up to 8 dp
+	EffectiveDate string                 `protobuf:"bytes,5,opt,name=effective_date,json=effectiveDate,proto3" json:"effective_date,omitempty"` // YYYY-MM-DD
+	UpdatedBy     string                 `protobuf:"bytes,6,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FxRate) Reset() {
+	*x = FxRate{}
+	mi := &file_control_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FxRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FxRate) ProtoMessage() {}
+
+func (x *FxRate) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FxRate.ProtoReflect.Descriptor instead.
+func (*FxRate) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *FxRate) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FxRate) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *FxRate) GetQuoteCurrency() string {
+	if x != nil {
+		return x.QuoteCurrency
+	}
+	return ""
+}
+
+func (x *FxRate) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *FxRate) GetEffectiveDate() string {
+	if x != nil {
+		return x.EffectiveDate
+	}
+	return ""
+}
+
+func (x *FxRate) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+func (x *FxRate) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type SetFxRateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseCurrency  string                 `protobuf:"bytes,1,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	QuoteCurrency string                 `protobuf:"bytes,2,opt,name=quote_currency,json=quoteCurrency,proto3" json:"quote_currency,omitempty"`
+	Rate          string                 `protobuf:"bytes,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	EffectiveDate string                 `protobuf:"bytes,4,opt,name=effective_date,json=effectiveDate,proto3" json:"effective_date,omitempty"` // defaults to today
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetFxRateRequest) Reset() {
+	*x = SetFxRateRequest{}
+	mi := &file_control_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetFxRateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFxRateRequest) ProtoMessage() {}
+
+func (x *SetFxRateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFxRateRequest.ProtoReflect.Descriptor instead.
+func (*SetFxRateRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *SetFxRateRequest) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *SetFxRateRequest) GetQuoteCurrency() string {
+	if x != nil {
+		return x.QuoteCurrency
+	}
+	return ""
+}
+
+func (x *SetFxRateRequest) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *SetFxRateRequest) GetEffectiveDate() string {
+	if x != nil {
+		return x.EffectiveDate
+	}
+	return ""
+}
+
+type SetFxRateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rate          *FxRate                `protobuf:"bytes,1,opt,name=rate,proto3" json:"rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetFxRateResponse) Reset() {
+	*x = SetFxRateResponse{}
+	mi := &file_control_proto_msgTypes[86]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListGradesByProductIdRequest) String() string {
+func (x *SetFxRateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListGradesByProductIdRequest) ProtoMessage() {}
+func (*SetFxRateResponse) ProtoMessage() {}
 
-func (x *ListGradesByProductIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[34]
+func (x *SetFxRateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[86]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2083,54 +5394,92 @@ func (x *ListGradesByProductIdRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListGradesByProductIdRequest.ProtoReflect.Descriptor instead.
-func (*ListGradesByProductIdRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{34}
+// Deprecated: Use SetFxRateResponse.ProtoReflect.Descriptor instead.
+func (*SetFxRateResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{86}
 }
 
-func (x *ListGradesByProductIdRequest) GetProductId() string {
+func (x *SetFxRateResponse) GetRate() *FxRate {
 	if x != nil {
-		return x.ProductId
+		return x.Rate
 	}
-	return ""
+	return nil
 }
 
-func (x *ListGradesByProductIdRequest) GetSkip() uint32 {
+type ListFxRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseCurrency  string                 `protobuf:"bytes,1,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`    // optional
+	QuoteCurrency string                 `protobuf:"bytes,2,opt,name=quote_currency,json=quoteCurrency,proto3" json:"quote_currency,omitempty"` // optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFxRatesRequest) Reset() {
+	*x = ListFxRatesRequest{}
+	mi := &file_control_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFxRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFxRatesRequest) ProtoMessage() {}
+
+func (x *ListFxRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[87]
 	if x != nil {
-		return x.Skip
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ListGradesByProductIdRequest) GetTake() uint32 {
+// Deprecated: Use ListFxRatesRequest.ProtoReflect.Descriptor instead.
+func (*ListFxRatesRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *ListFxRatesRequest) GetBaseCurrency() string {
 	if x != nil {
-		return x.Take
+		return x.BaseCurrency
 	}
-	return 0
+	return ""
 }
 
-type ListGradesByProductIdResponse struct {
+func (x *ListFxRatesRequest) GetQuoteCurrency() string {
+	if x != nil {
+		return x.QuoteCurrency
+	}
+	return ""
+}
+
+type ListFxRatesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Grades        []*Grade               `protobuf:"bytes,1,rep,name=grades,proto3" json:"grades,omitempty"`
+	Rates         []*FxRate              `protobuf:"bytes,1,rep,name=rates,proto3" json:"rates,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListGradesByProductIdResponse) Reset() {
-	*x = ListGradesByProductIdResponse{}
-	mi := &file_control_proto_msgTypes[35]
+func (x *ListFxRatesResponse) Reset() {
+	*x = ListFxRatesResponse{}
+	mi := &file_control_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListGradesByProductIdResponse) String() string {
+func (x *ListFxRatesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListGradesByProductIdResponse) ProtoMessage() {}
+func (*ListFxRatesResponse) ProtoMessage() {}
 
-func (x *ListGradesByProductIdResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[35]
+func (x *ListFxRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2141,46 +5490,52 @@ func (x *ListGradesByProductIdResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListGradesByProductIdResponse.ProtoReflect.Descriptor instead.
-func (*ListGradesByProductIdResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{35}
+// Deprecated: Use ListFxRatesResponse.ProtoReflect.Descriptor instead.
+func (*ListFxRatesResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *ListGradesByProductIdResponse) GetGrades() []*Grade {
+func (x *ListFxRatesResponse) GetRates() []*FxRate {
 	if x != nil {
-		return x.Grades
+		return x.Rates
 	}
 	return nil
 }
 
-// Daily Prices
-type CreateOrUpdateDailyPriceRequest struct {
+// Fee and tax schedules for trades of one grade or of a product category.
+type FeeSchedule struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	GradeId       string                 `protobuf:"bytes,3,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Date          string                 `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
-	Time          string                 `protobuf:"bytes,6,opt,name=time,proto3" json:"time,omitempty"`
+	GradeId       string                 `protobuf:"bytes,2,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`                    // set for a grade schedule
+	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`                                 // set for a category schedule
+	Code          string                 `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`                                         // e.g. COMMISSION, MARKET_CESS, GST
+	Kind          string                 `protobuf:"bytes,5,opt,name=kind,proto3" json:"kind,omitempty"`                                         // FEE | TAX
+	Side          string                 `protobuf:"bytes,6,opt,name=side,proto3" json:"side,omitempty"`                                         // BUY | SELL | BOTH
+	Basis         string                 `protobuf:"bytes,7,opt,name=basis,proto3" json:"basis,omitempty"`                                       // PERCENT (of trade value) | PER_KG | FLAT (per trade)
+	Rate          string                 `protobuf:"bytes,8,opt,name=rate,proto3" json:"rate,omitempty"`                                         // decimal string, 4 dp; 0 stops charging it
+	Currency      string                 `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`                                 // of PER_KG and FLAT amounts
+	EffectiveDate string                 `protobuf:"bytes,10,opt,name=effective_date,json=effectiveDate,proto3" json:"effective_date,omitempty"` // YYYY-MM-DD
+	UpdatedBy     string                 `protobuf:"bytes,11,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrUpdateDailyPriceRequest) Reset() {
-	*x = CreateOrUpdateDailyPriceRequest{}
-	mi := &file_control_proto_msgTypes[36]
+func (x *FeeSchedule) Reset() {
+	*x = FeeSchedule{}
+	mi := &file_control_proto_msgTypes[89]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrUpdateDailyPriceRequest) String() string {
+func (x *FeeSchedule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrUpdateDailyPriceRequest) ProtoMessage() {}
+func (*FeeSchedule) ProtoMessage() {}
 
-func (x *CreateOrUpdateDailyPriceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[36]
+func (x *FeeSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[89]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2191,75 +5546,321 @@ func (x *CreateOrUpdateDailyPriceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrUpdateDailyPriceRequest.ProtoReflect.Descriptor instead.
-func (*CreateOrUpdateDailyPriceRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{36}
+// Deprecated: Use FeeSchedule.ProtoReflect.Descriptor instead.
+func (*FeeSchedule) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{89}
 }
 
-func (x *CreateOrUpdateDailyPriceRequest) GetId() string {
+func (x *FeeSchedule) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateDailyPriceRequest) GetProductId() string {
+func (x *FeeSchedule) GetGradeId() string {
 	if x != nil {
-		return x.ProductId
+		return x.GradeId
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateDailyPriceRequest) GetGradeId() string {
+func (x *FeeSchedule) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetBasis() string {
+	if x != nil {
+		return x.Basis
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetEffectiveDate() string {
+	if x != nil {
+		return x.EffectiveDate
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type SetFeeScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GradeId       string                 `protobuf:"bytes,1,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"` // exactly one of grade_id and category
+	Category      string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	Code          string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Kind          string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	Side          string                 `protobuf:"bytes,5,opt,name=side,proto3" json:"side,omitempty"` // defaults to BOTH
+	Basis         string                 `protobuf:"bytes,6,opt,name=basis,proto3" json:"basis,omitempty"`
+	Rate          string                 `protobuf:"bytes,7,opt,name=rate,proto3" json:"rate,omitempty"`
+	Currency      string                 `protobuf:"bytes,8,opt,name=currency,proto3" json:"currency,omitempty"`                                // defaults to INR
+	EffectiveDate string                 `protobuf:"bytes,9,opt,name=effective_date,json=effectiveDate,proto3" json:"effective_date,omitempty"` // defaults to today
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetFeeScheduleRequest) Reset() {
+	*x = SetFeeScheduleRequest{}
+	mi := &file_control_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetFeeScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFeeScheduleRequest) ProtoMessage() {}
+
+func (x *SetFeeScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFeeScheduleRequest.ProtoReflect.Descriptor instead.
+func (*SetFeeScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *SetFeeScheduleRequest) GetGradeId() string {
 	if x != nil {
 		return x.GradeId
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateDailyPriceRequest) GetPrice() float64 {
+func (x *SetFeeScheduleRequest) GetCategory() string {
 	if x != nil {
-		return x.Price
+		return x.Category
 	}
-	return 0
+	return ""
+}
+
+func (x *SetFeeScheduleRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *SetFeeScheduleRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *SetFeeScheduleRequest) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *SetFeeScheduleRequest) GetBasis() string {
+	if x != nil {
+		return x.Basis
+	}
+	return ""
+}
+
+func (x *SetFeeScheduleRequest) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *SetFeeScheduleRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SetFeeScheduleRequest) GetEffectiveDate() string {
+	if x != nil {
+		return x.EffectiveDate
+	}
+	return ""
+}
+
+type SetFeeScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedule      *FeeSchedule           `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetFeeScheduleResponse) Reset() {
+	*x = SetFeeScheduleResponse{}
+	mi := &file_control_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetFeeScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFeeScheduleResponse) ProtoMessage() {}
+
+func (x *SetFeeScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFeeScheduleResponse.ProtoReflect.Descriptor instead.
+func (*SetFeeScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *SetFeeScheduleResponse) GetSchedule() *FeeSchedule {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+type ListFeeSchedulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GradeId       string                 `protobuf:"bytes,1,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"` // optional
+	Category      string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`              // optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeeSchedulesRequest) Reset() {
+	*x = ListFeeSchedulesRequest{}
+	mi := &file_control_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeeSchedulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeeSchedulesRequest) ProtoMessage() {}
+
+func (x *ListFeeSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (x *CreateOrUpdateDailyPriceRequest) GetDate() string {
+// Deprecated: Use ListFeeSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListFeeSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *ListFeeSchedulesRequest) GetGradeId() string {
 	if x != nil {
-		return x.Date
+		return x.GradeId
 	}
 	return ""
 }
 
-func (x *CreateOrUpdateDailyPriceRequest) GetTime() string {
+func (x *ListFeeSchedulesRequest) GetCategory() string {
 	if x != nil {
-		return x.Time
+		return x.Category
 	}
 	return ""
 }
 
-type CreateOrUpdateDailyPriceResponse struct {
+type ListFeeSchedulesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DailyPrice    *DailyPrice            `protobuf:"bytes,1,opt,name=daily_price,json=dailyPrice,proto3" json:"daily_price,omitempty"`
+	Schedules     []*FeeSchedule         `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrUpdateDailyPriceResponse) Reset() {
-	*x = CreateOrUpdateDailyPriceResponse{}
-	mi := &file_control_proto_msgTypes[37]
+func (x *ListFeeSchedulesResponse) Reset() {
+	*x = ListFeeSchedulesResponse{}
+	mi := &file_control_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrUpdateDailyPriceResponse) String() string {
+func (x *ListFeeSchedulesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrUpdateDailyPriceResponse) ProtoMessage() {}
+func (*ListFeeSchedulesResponse) ProtoMessage() {}
 
-func (x *CreateOrUpdateDailyPriceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[37]
+func (x *ListFeeSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[93]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2270,42 +5871,49 @@ func (x *CreateOrUpdateDailyPriceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrUpdateDailyPriceResponse.ProtoReflect.Descriptor instead.
-func (*CreateOrUpdateDailyPriceResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use ListFeeSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListFeeSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{93}
 }
 
-func (x *CreateOrUpdateDailyPriceResponse) GetDailyPrice() *DailyPrice {
+func (x *ListFeeSchedulesResponse) GetSchedules() []*FeeSchedule {
 	if x != nil {
-		return x.DailyPrice
+		return x.Schedules
 	}
 	return nil
 }
 
-type ListDailyPricesRequest struct {
+// A signed-in device of the caller. The client IP and user agent are reported by the gateway.
+type Session struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	GradeId       string                 `protobuf:"bytes,1,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
-	Today         string                 `protobuf:"bytes,2,opt,name=today,proto3" json:"today,omitempty"`
-	Duration      int32                  `protobuf:"varint,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceName    string                 `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	IpAddress     string                 `protobuf:"bytes,4,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,5,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastSeenAt    string                 `protobuf:"bytes,7,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Current       bool                   `protobuf:"varint,9,opt,name=current,proto3" json:"current,omitempty"` // the session making this call
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDailyPricesRequest) Reset() {
-	*x = ListDailyPricesRequest{}
-	mi := &file_control_proto_msgTypes[38]
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_control_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDailyPricesRequest) String() string {
+func (x *Session) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDailyPricesRequest) ProtoMessage() {}
+func (*Session) ProtoMessage() {}
 
-func (x *ListDailyPricesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[38]
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2316,54 +5924,95 @@ func (x *ListDailyPricesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDailyPricesRequest.ProtoReflect.Descriptor instead.
-func (*ListDailyPricesRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{94}
 }
 
-func (x *ListDailyPricesRequest) GetGradeId() string {
+func (x *Session) GetId() string {
 	if x != nil {
-		return x.GradeId
+		return x.Id
 	}
 	return ""
 }
 
-func (x *ListDailyPricesRequest) GetToday() string {
+func (x *Session) GetDeviceId() string {
 	if x != nil {
-		return x.Today
+		return x.DeviceId
 	}
 	return ""
 }
 
-func (x *ListDailyPricesRequest) GetDuration() int32 {
+func (x *Session) GetDeviceName() string {
 	if x != nil {
-		return x.Duration
+		return x.DeviceName
 	}
-	return 0
+	return ""
 }
 
-type ListDailyPricesResponse struct {
+func (x *Session) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *Session) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Session) GetLastSeenAt() string {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return ""
+}
+
+func (x *Session) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *Session) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+type ListSessionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DailyPrices   []*DailyPrice          `protobuf:"bytes,1,rep,name=daily_prices,json=dailyPrices,proto3" json:"daily_prices,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDailyPricesResponse) Reset() {
-	*x = ListDailyPricesResponse{}
-	mi := &file_control_proto_msgTypes[39]
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_control_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDailyPricesResponse) String() string {
+func (x *ListSessionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDailyPricesResponse) ProtoMessage() {}
+func (*ListSessionsRequest) ProtoMessage() {}
 
-func (x *ListDailyPricesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[39]
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2374,41 +6023,33 @@ func (x *ListDailyPricesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDailyPricesResponse.ProtoReflect.Descriptor instead.
-func (*ListDailyPricesResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{39}
-}
-
-func (x *ListDailyPricesResponse) GetDailyPrices() []*DailyPrice {
-	if x != nil {
-		return x.DailyPrices
-	}
-	return nil
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{95}
 }
 
-type GetTodaysPriceRequest struct {
+type ListSessionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	GradeId       string                 `protobuf:"bytes,1,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
-	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTodaysPriceRequest) Reset() {
-	*x = GetTodaysPriceRequest{}
-	mi := &file_control_proto_msgTypes[40]
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_control_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTodaysPriceRequest) String() string {
+func (x *ListSessionsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTodaysPriceRequest) ProtoMessage() {}
+func (*ListSessionsResponse) ProtoMessage() {}
 
-func (x *GetTodaysPriceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[40]
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2419,47 +6060,40 @@ func (x *GetTodaysPriceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTodaysPriceRequest.ProtoReflect.Descriptor instead.
-func (*GetTodaysPriceRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{40}
-}
-
-func (x *GetTodaysPriceRequest) GetGradeId() string {
-	if x != nil {
-		return x.GradeId
-	}
-	return ""
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{96}
 }
 
-func (x *GetTodaysPriceRequest) GetDate() string {
+func (x *ListSessionsResponse) GetSessions() []*Session {
 	if x != nil {
-		return x.Date
+		return x.Sessions
 	}
-	return ""
+	return nil
 }
 
-type GetTodaysPriceResponse struct {
+type RevokeSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DailyPrices   []*DailyPrice          `protobuf:"bytes,1,rep,name=daily_prices,json=dailyPrices,proto3" json:"daily_prices,omitempty"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTodaysPriceResponse) Reset() {
-	*x = GetTodaysPriceResponse{}
-	mi := &file_control_proto_msgTypes[41]
+func (x *RevokeSessionRequest) Reset() {
+	*x = RevokeSessionRequest{}
+	mi := &file_control_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTodaysPriceResponse) String() string {
+func (x *RevokeSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTodaysPriceResponse) ProtoMessage() {}
+func (*RevokeSessionRequest) ProtoMessage() {}
 
-func (x *GetTodaysPriceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[41]
+func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2470,41 +6104,40 @@ func (x *GetTodaysPriceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTodaysPriceResponse.ProtoReflect.Descriptor instead.
-func (*GetTodaysPriceResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{41}
+// Deprecated: Use RevokeSessionRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{97}
 }
 
-func (x *GetTodaysPriceResponse) GetDailyPrices() []*DailyPrice {
+func (x *RevokeSessionRequest) GetSessionId() string {
 	if x != nil {
-		return x.DailyPrices
+		return x.SessionId
 	}
-	return nil
+	return ""
 }
 
-type GetTodaysByProductIdRequest struct {
+type RevokeSessionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTodaysByProductIdRequest) Reset() {
-	*x = GetTodaysByProductIdRequest{}
-	mi := &file_control_proto_msgTypes[42]
+func (x *RevokeSessionResponse) Reset() {
+	*x = RevokeSessionResponse{}
+	mi := &file_control_proto_msgTypes[98]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTodaysByProductIdRequest) String() string {
+func (x *RevokeSessionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTodaysByProductIdRequest) ProtoMessage() {}
+func (*RevokeSessionResponse) ProtoMessage() {}
 
-func (x *GetTodaysByProductIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[42]
+func (x *RevokeSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[98]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2515,47 +6148,39 @@ func (x *GetTodaysByProductIdRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTodaysByProductIdRequest.ProtoReflect.Descriptor instead.
-func (*GetTodaysByProductIdRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{42}
-}
-
-func (x *GetTodaysByProductIdRequest) GetProductId() string {
-	if x != nil {
-		return x.ProductId
-	}
-	return ""
+// Deprecated: Use RevokeSessionResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{98}
 }
 
-func (x *GetTodaysByProductIdRequest) GetDate() string {
+func (x *RevokeSessionResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Date
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-type GetTodaysByProductIdResponse struct {
+type RevokeAllOtherSessionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DailyPrices   []*DailyPrice          `protobuf:"bytes,1,rep,name=daily_prices,json=dailyPrices,proto3" json:"daily_prices,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTodaysByProductIdResponse) Reset() {
-	*x = GetTodaysByProductIdResponse{}
-	mi := &file_control_proto_msgTypes[43]
+func (x *RevokeAllOtherSessionsRequest) Reset() {
+	*x = RevokeAllOtherSessionsRequest{}
+	mi := &file_control_proto_msgTypes[99]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTodaysByProductIdResponse) String() string {
+func (x *RevokeAllOtherSessionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTodaysByProductIdResponse) ProtoMessage() {}
+func (*RevokeAllOtherSessionsRequest) ProtoMessage() {}
 
-func (x *GetTodaysByProductIdResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[43]
+func (x *RevokeAllOtherSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[99]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2566,41 +6191,33 @@ func (x *GetTodaysByProductIdResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTodaysByProductIdResponse.ProtoReflect.Descriptor instead.
-func (*GetTodaysByProductIdResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{43}
-}
-
-func (x *GetTodaysByProductIdResponse) GetDailyPrices() []*DailyPrice {
-	if x != nil {
-		return x.DailyPrices
-	}
-	return nil
+// Deprecated: Use RevokeAllOtherSessionsRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAllOtherSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{99}
 }
 
-type GetProductsWithGradesAndPricesRequest struct {
+type RevokeSessionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
-	Search        string                 `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
+	Revoked       uint32                 `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"` // sessions signed out
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProductsWithGradesAndPricesRequest) Reset() {
-	*x = GetProductsWithGradesAndPricesRequest{}
-	mi := &file_control_proto_msgTypes[44]
+func (x *RevokeSessionsResponse) Reset() {
+	*x = RevokeSessionsResponse{}
+	mi := &file_control_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProductsWithGradesAndPricesRequest) String() string {
+func (x *RevokeSessionsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProductsWithGradesAndPricesRequest) ProtoMessage() {}
+func (*RevokeSessionsResponse) ProtoMessage() {}
 
-func (x *GetProductsWithGradesAndPricesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[44]
+func (x *RevokeSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2611,47 +6228,40 @@ func (x *GetProductsWithGradesAndPricesRequest) ProtoReflect() protoreflect.Mess
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProductsWithGradesAndPricesRequest.ProtoReflect.Descriptor instead.
-func (*GetProductsWithGradesAndPricesRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{44}
-}
-
-func (x *GetProductsWithGradesAndPricesRequest) GetDate() string {
-	if x != nil {
-		return x.Date
-	}
-	return ""
+// Deprecated: Use RevokeSessionsResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{100}
 }
 
-func (x *GetProductsWithGradesAndPricesRequest) GetSearch() string {
+func (x *RevokeSessionsResponse) GetRevoked() uint32 {
 	if x != nil {
-		return x.Search
+		return x.Revoked
 	}
-	return ""
+	return 0
 }
 
-type GetProductsWithGradesAndPricesResponse struct {
+type ForceLogoutRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*ProductWithGrades   `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProductsWithGradesAndPricesResponse) Reset() {
-	*x = GetProductsWithGradesAndPricesResponse{}
-	mi := &file_control_proto_msgTypes[45]
+func (x *ForceLogoutRequest) Reset() {
+	*x = ForceLogoutRequest{}
+	mi := &file_control_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProductsWithGradesAndPricesResponse) String() string {
+func (x *ForceLogoutRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProductsWithGradesAndPricesResponse) ProtoMessage() {}
+func (*ForceLogoutRequest) ProtoMessage() {}
 
-func (x *GetProductsWithGradesAndPricesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[45]
+func (x *ForceLogoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2662,16 +6272,16 @@ func (x *GetProductsWithGradesAndPricesResponse) ProtoReflect() protoreflect.Mes
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProductsWithGradesAndPricesResponse.ProtoReflect.Descriptor instead.
-func (*GetProductsWithGradesAndPricesResponse) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{45}
+// Deprecated: Use ForceLogoutRequest.ProtoReflect.Descriptor instead.
+func (*ForceLogoutRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{101}
 }
 
-func (x *GetProductsWithGradesAndPricesResponse) GetProducts() []*ProductWithGrades {
+func (x *ForceLogoutRequest) GetAccountId() string {
 	if x != nil {
-		return x.Products
+		return x.AccountId
 	}
-	return nil
+	return ""
 }
 
 type GetAccountInfoRequest struct {
@@ -2682,7 +6292,7 @@ type GetAccountInfoRequest struct {
 
 func (x *GetAccountInfoRequest) Reset() {
 	*x = GetAccountInfoRequest{}
-	mi := &file_control_proto_msgTypes[46]
+	mi := &file_control_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2694,7 +6304,7 @@ func (x *GetAccountInfoRequest) String() string {
 func (*GetAccountInfoRequest) ProtoMessage() {}
 
 func (x *GetAccountInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[46]
+	mi := &file_control_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2707,7 +6317,7 @@ func (x *GetAccountInfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAccountInfoRequest.ProtoReflect.Descriptor instead.
 func (*GetAccountInfoRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{46}
+	return file_control_proto_rawDescGZIP(), []int{102}
 }
 
 type GetMerchantInfoRequest struct {
@@ -2718,7 +6328,7 @@ type GetMerchantInfoRequest struct {
 
 func (x *GetMerchantInfoRequest) Reset() {
 	*x = GetMerchantInfoRequest{}
-	mi := &file_control_proto_msgTypes[47]
+	mi := &file_control_proto_msgTypes[103]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2730,7 +6340,7 @@ func (x *GetMerchantInfoRequest) String() string {
 func (*GetMerchantInfoRequest) ProtoMessage() {}
 
 func (x *GetMerchantInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_proto_msgTypes[47]
+	mi := &file_control_proto_msgTypes[103]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2743,20 +6353,23 @@ func (x *GetMerchantInfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMerchantInfoRequest.ProtoReflect.Descriptor instead.
 func (*GetMerchantInfoRequest) Descriptor() ([]byte, []int) {
-	return file_control_proto_rawDescGZIP(), []int{47}
+	return file_control_proto_rawDescGZIP(), []int{103}
 }
 
 var File_control_proto protoreflect.FileDescriptor
 
 const file_control_proto_rawDesc = "" +
 	"\n" +
-	"\rcontrol.proto\x12\x02pb\"{\n" +
+	"\rcontrol.proto\x12\x02pb\"\xed\x01\n" +
 	"\aAccount\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
 	"\busertype\x18\x03 \x01(\tR\busertype\x12\x14\n" +
 	"\x05email\x18\x04 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x05 \x01(\tR\bpassword\"\xc1\x01\n" +
+	"\bpassword\x18\x05 \x01(\tR\bpassword\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12-\n" +
+	"\x12reporting_currency\x18\a \x01(\tR\x11reportingCurrency\x12%\n" +
+	"\x0eemail_verified\x18\b \x01(\bR\remailVerified\"\xc1\x01\n" +
 	"\x0fMerchantDetails\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -2771,14 +6384,17 @@ const file_control_proto_rawDesc = "" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
 	"\bcategory\x18\x03 \x01(\tR\bcategory\x12 \n" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status\"\x84\x01\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\"\xe0\x01\n" +
 	"\x05Grade\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x02 \x01(\tR\tproductId\x12\x12\n" +
 	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status\"\xa3\x01\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12&\n" +
+	"\x0fshelf_life_days\x18\x06 \x01(\rR\rshelfLifeDays\x12\x12\n" +
+	"\x04unit\x18\a \x01(\tR\x04unit\x12\x1e\n" +
+	"\vkg_per_unit\x18\b \x01(\tR\tkgPerUnit\"\x9b\x02\n" +
 	"\x0eGradeWithPrice\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -2786,33 +6402,68 @@ const file_control_proto_rawDesc = "" +
 	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x16\n" +
 	"\x06status\x18\x05 \x01(\tR\x06status\x12\x14\n" +
-	"\x05price\x18\x06 \x01(\x01R\x05price\"\xb9\x01\n" +
+	"\x05price\x18\x06 \x01(\tR\x05price\x12&\n" +
+	"\x0fshelf_life_days\x18\a \x01(\rR\rshelfLifeDays\x12\x1a\n" +
+	"\bcurrency\x18\b \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04unit\x18\t \x01(\tR\x04unit\x12\x1e\n" +
+	"\vkg_per_unit\x18\n" +
+	" \x01(\tR\tkgPerUnit\"\xb9\x01\n" +
 	"\x11ProductWithGrades\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
 	"\bcategory\x18\x03 \x01(\tR\bcategory\x12 \n" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x16\n" +
 	"\x06status\x18\x05 \x01(\tR\x06status\x12*\n" +
-	"\x06grades\x18\x06 \x03(\v2\x12.pb.GradeWithPriceR\x06grades\"\x94\x01\n" +
+	"\x06grades\x18\x06 \x03(\v2\x12.pb.GradeWithPriceR\x06grades\"\xb3\x02\n" +
 	"\n" +
 	"DailyPrice\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x02 \x01(\tR\tproductId\x12\x19\n" +
 	"\bgrade_id\x18\x03 \x01(\tR\agradeId\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x12\n" +
+	"\x05price\x18\x04 \x01(\tR\x05price\x12\x12\n" +
 	"\x04date\x18\x05 \x01(\tR\x04date\x12\x12\n" +
-	"\x04time\x18\x06 \x01(\tR\x04time\"/\n" +
+	"\x04time\x18\x06 \x01(\tR\x04time\x12\x12\n" +
+	"\x04open\x18\a \x01(\tR\x04open\x12\x12\n" +
+	"\x04high\x18\b \x01(\tR\x04high\x12\x10\n" +
+	"\x03low\x18\t \x01(\tR\x03low\x12\x12\n" +
+	"\x04last\x18\n" +
+	" \x01(\tR\x04last\x12\x14\n" +
+	"\x05close\x18\v \x01(\tR\x05close\x12\x1d\n" +
+	"\n" +
+	"tick_count\x18\f \x01(\x05R\ttickCount\x12\x1a\n" +
+	"\bcurrency\x18\r \x01(\tR\bcurrency\"\xfd\x02\n" +
+	"\tPriceTick\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x19\n" +
+	"\bgrade_id\x18\x03 \x01(\tR\agradeId\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\tR\x05price\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12!\n" +
+	"\fpublished_by\x18\x06 \x01(\tR\vpublishedBy\x12\x1b\n" +
+	"\tticked_at\x18\a \x01(\tR\btickedAt\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12!\n" +
+	"\fsubmitted_at\x18\t \x01(\tR\vsubmittedAt\x12\x1f\n" +
+	"\vreviewed_by\x18\n" +
+	" \x01(\tR\n" +
+	"reviewedBy\x12\x1f\n" +
+	"\vreviewed_at\x18\v \x01(\tR\n" +
+	"reviewedAt\x12\x1f\n" +
+	"\vreview_note\x18\f \x01(\tR\n" +
+	"reviewNote\x12\x1a\n" +
+	"\bcurrency\x18\r \x01(\tR\bcurrency\"/\n" +
 	"\x17CheckEmailExistsRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\"2\n" +
 	"\x18CheckEmailExistsResponse\x12\x16\n" +
-	"\x06exists\x18\x01 \x01(\bR\x06exists\"\x90\x01\n" +
+	"\x06exists\x18\x01 \x01(\bR\x06exists\"\xdb\x01\n" +
 	"\x1cCreateOrUpdateAccountRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
 	"\busertype\x18\x03 \x01(\tR\busertype\x12\x14\n" +
 	"\x05email\x18\x04 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x05 \x01(\tR\bpassword\"F\n" +
+	"\bpassword\x18\x05 \x01(\tR\bpassword\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12-\n" +
+	"\x12reporting_currency\x18\a \x01(\tR\x11reportingCurrency\"F\n" +
 	"\x1dCreateOrUpdateAccountResponse\x12%\n" +
 	"\aaccount\x18\x01 \x01(\v2\v.pb.AccountR\aaccount\"'\n" +
 	"\x15GetAccountByIDRequest\x12\x0e\n" +
@@ -2823,15 +6474,20 @@ const file_control_proto_rawDesc = "" +
 	"\x04skip\x18\x01 \x01(\rR\x04skip\x12\x12\n" +
 	"\x04take\x18\x02 \x01(\rR\x04take\"?\n" +
 	"\x14ListAccountsResponse\x12'\n" +
-	"\baccounts\x18\x01 \x03(\v2\v.pb.AccountR\baccounts\"]\n" +
+	"\baccounts\x18\x01 \x03(\v2\v.pb.AccountR\baccounts\"~\n" +
 	"\fLoginRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x1b\n" +
-	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\"~\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_name\x18\x04 \x01(\tR\n" +
+	"deviceName\"\x88\x02\n" +
 	"\rLoginResponse\x12%\n" +
 	"\aaccount\x18\x01 \x01(\v2\v.pb.AccountR\aaccount\x12!\n" +
 	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\x12#\n" +
-	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\"O\n" +
+	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\x12.\n" +
+	"\x13two_factor_required\x18\x04 \x01(\bR\x11twoFactorRequired\x12'\n" +
+	"\x0fchallenge_token\x18\x05 \x01(\tR\x0echallengeToken\x12/\n" +
+	"\x13enrollment_required\x18\x06 \x01(\bR\x12enrollmentRequired\"O\n" +
 	"\rLogoutRequest\x12!\n" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12\x1b\n" +
 	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\"*\n" +
@@ -2843,7 +6499,73 @@ const file_control_proto_rawDesc = "" +
 	"\x14RefreshTokenResponse\x12%\n" +
 	"\aaccount\x18\x01 \x01(\v2\v.pb.AccountR\aaccount\x12!\n" +
 	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\x12#\n" +
-	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\"\xd6\x01\n" +
+	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\"3\n" +
+	"\x1bRequestPasswordResetRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"8\n" +
+	"\x1cRequestPasswordResetResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x7f\n" +
+	"\x1bConfirmPasswordResetRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\x12'\n" +
+	"\x0frevoke_sessions\x18\x03 \x01(\bR\x0erevokeSessions\"c\n" +
+	"\x1cConfirmPasswordResetResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12)\n" +
+	"\x10sessions_revoked\x18\x02 \x01(\rR\x0fsessionsRevoked\"\x1e\n" +
+	"\x1cSendVerificationEmailRequest\"9\n" +
+	"\x1dSendVerificationEmailResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"*\n" +
+	"\x12VerifyEmailRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"<\n" +
+	"\x13VerifyEmailResponse\x12%\n" +
+	"\aaccount\x18\x01 \x01(\v2\v.pb.AccountR\aaccount\"Y\n" +
+	"\x11EnrollTOTPRequest\x12'\n" +
+	"\x0fchallenge_token\x18\x01 \x01(\tR\x0echallengeToken\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\"W\n" +
+	"\x12EnrollTOTPResponse\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12)\n" +
+	"\x10provisioning_uri\x18\x02 \x01(\tR\x0fprovisioningUri\"\x8f\x01\n" +
+	"\x12ConfirmTOTPRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12'\n" +
+	"\x0fchallenge_token\x18\x02 \x01(\tR\x0echallengeToken\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_name\x18\x04 \x01(\tR\n" +
+	"deviceName\"e\n" +
+	"\x13ConfirmTOTPResponse\x12%\n" +
+	"\x0erecovery_codes\x18\x01 \x03(\tR\rrecoveryCodes\x12'\n" +
+	"\x05login\x18\x02 \x01(\v2\x11.pb.LoginResponseR\x05login\"\xbd\x01\n" +
+	"\x1bVerifyLoginChallengeRequest\x12'\n" +
+	"\x0fchallenge_token\x18\x01 \x01(\tR\x0echallengeToken\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_name\x18\x03 \x01(\tR\n" +
+	"deviceName\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\tR\x04code\x12#\n" +
+	"\rrecovery_code\x18\x05 \x01(\tR\frecoveryCode\"M\n" +
+	"\x12DisableTOTPRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12#\n" +
+	"\rrecovery_code\x18\x02 \x01(\tR\frecoveryCode\"/\n" +
+	"\x13DisableTOTPResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"4\n" +
+	"\x1eRegenerateRecoveryCodesRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"H\n" +
+	"\x1fRegenerateRecoveryCodesResponse\x12%\n" +
+	"\x0erecovery_codes\x18\x01 \x03(\tR\rrecoveryCodes\"\x90\x01\n" +
+	"\n" +
+	"JSONWebKey\x12\x10\n" +
+	"\x03kty\x18\x01 \x01(\tR\x03kty\x12\x10\n" +
+	"\x03kid\x18\x02 \x01(\tR\x03kid\x12\x10\n" +
+	"\x03use\x18\x03 \x01(\tR\x03use\x12\x10\n" +
+	"\x03alg\x18\x04 \x01(\tR\x03alg\x12\f\n" +
+	"\x01n\x18\x05 \x01(\tR\x01n\x12\f\n" +
+	"\x01e\x18\x06 \x01(\tR\x01e\x12\x10\n" +
+	"\x03crv\x18\a \x01(\tR\x03crv\x12\f\n" +
+	"\x01x\x18\b \x01(\tR\x01x\"\x10\n" +
+	"\x0eGetJWKSRequest\"5\n" +
+	"\x0fGetJWKSResponse\x12\"\n" +
+	"\x04keys\x18\x01 \x03(\v2\x0e.pb.JSONWebKeyR\x04keys\"8\n" +
+	"\x13CheckSessionRequest\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\".\n" +
+	"\x14CheckSessionResponse\x12\x16\n" +
+	"\x06active\x18\x01 \x01(\bR\x06active\"\xd6\x01\n" +
 	"$CreateOrUpdateMerchantDetailsRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -2884,14 +6606,17 @@ const file_control_proto_rawDesc = "" +
 	"\x18GetSystemMetricsResponse\x12\x1f\n" +
 	"\vtotal_users\x18\x01 \x01(\rR\n" +
 	"totalUsers\x12%\n" +
-	"\x0etotal_products\x18\x02 \x01(\rR\rtotalProducts\"\x99\x01\n" +
+	"\x0etotal_products\x18\x02 \x01(\rR\rtotalProducts\"\xf5\x01\n" +
 	"\x1aCreateOrUpdateGradeRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x02 \x01(\tR\tproductId\x12\x12\n" +
 	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status\">\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12&\n" +
+	"\x0fshelf_life_days\x18\x06 \x01(\rR\rshelfLifeDays\x12\x12\n" +
+	"\x04unit\x18\a \x01(\tR\x04unit\x12\x1e\n" +
+	"\vkg_per_unit\x18\b \x01(\tR\tkgPerUnit\">\n" +
 	"\x1bCreateOrUpdateGradeResponse\x12\x1f\n" +
 	"\x05grade\x18\x01 \x01(\v2\t.pb.GradeR\x05grade\"e\n" +
 	"\x1cListGradesByProductIdRequest\x12\x1d\n" +
@@ -2900,42 +6625,218 @@ const file_control_proto_rawDesc = "" +
 	"\x04skip\x18\x02 \x01(\rR\x04skip\x12\x12\n" +
 	"\x04take\x18\x03 \x01(\rR\x04take\"B\n" +
 	"\x1dListGradesByProductIdResponse\x12!\n" +
-	"\x06grades\x18\x01 \x03(\v2\t.pb.GradeR\x06grades\"\xa9\x01\n" +
+	"\x06grades\x18\x01 \x03(\v2\t.pb.GradeR\x06grades\"\xf3\x01\n" +
 	"\x1fCreateOrUpdateDailyPriceRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x02 \x01(\tR\tproductId\x12\x19\n" +
 	"\bgrade_id\x18\x03 \x01(\tR\agradeId\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x12\n" +
+	"\x05price\x18\x04 \x01(\tR\x05price\x12\x12\n" +
 	"\x04date\x18\x05 \x01(\tR\x04date\x12\x12\n" +
-	"\x04time\x18\x06 \x01(\tR\x04time\"S\n" +
-	" CreateOrUpdateDailyPriceResponse\x12/\n" +
-	"\vdaily_price\x18\x01 \x01(\v2\x0e.pb.DailyPriceR\n" +
-	"dailyPrice\"e\n" +
+	"\x04time\x18\x06 \x01(\tR\x04time\x12\x16\n" +
+	"\x06source\x18\a \x01(\tR\x06source\x12\x14\n" +
+	"\x05draft\x18\b \x01(\bR\x05draft\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\"K\n" +
+	" CreateOrUpdateDailyPriceResponse\x12!\n" +
+	"\x04tick\x18\x02 \x01(\v2\r.pb.PriceTickR\x04tickJ\x04\b\x01\x10\x02\"(\n" +
+	"\x16SubmitPriceTickRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"<\n" +
+	"\x17SubmitPriceTickResponse\x12!\n" +
+	"\x04tick\x18\x01 \x01(\v2\r.pb.PriceTickR\x04tick\"[\n" +
+	"\x17ReviewPriceTicksRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x1a\n" +
+	"\bdecision\x18\x02 \x01(\tR\bdecision\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\"r\n" +
+	"\x18ReviewPriceTicksResponse\x12#\n" +
+	"\x05ticks\x18\x01 \x03(\v2\r.pb.PriceTickR\x05ticks\x121\n" +
+	"\fdaily_prices\x18\x02 \x03(\v2\x0e.pb.DailyPriceR\vdailyPrices\"\xa2\x01\n" +
+	" CreateOrUpdateDailyPricesRequest\x12;\n" +
+	"\x06prices\x18\x01 \x03(\v2#.pb.CreateOrUpdateDailyPriceRequestR\x06prices\x12(\n" +
+	"\x10max_move_percent\x18\x02 \x01(\tR\x0emaxMovePercent\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"\xec\x01\n" +
+	"\x0ePriceImportRow\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x19\n" +
+	"\bgrade_id\x18\x03 \x01(\tR\agradeId\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\tR\x05price\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12!\n" +
+	"\fmove_percent\x18\a \x01(\tR\vmovePercent\x12!\n" +
+	"\x04tick\x18\b \x01(\v2\r.pb.PriceTickR\x04tickJ\x04\b\t\x10\n" +
+	"\"\xe0\x01\n" +
+	"!CreateOrUpdateDailyPricesResponse\x12\x18\n" +
+	"\aapplied\x18\x01 \x01(\bR\aapplied\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12(\n" +
+	"\x10max_move_percent\x18\x03 \x01(\tR\x0emaxMovePercent\x12\x1a\n" +
+	"\baccepted\x18\x04 \x01(\x05R\baccepted\x12\x1a\n" +
+	"\brejected\x18\x05 \x01(\x05R\brejected\x12&\n" +
+	"\x04rows\x18\x06 \x03(\v2\x12.pb.PriceImportRowR\x04rows\"e\n" +
 	"\x16ListDailyPricesRequest\x12\x19\n" +
 	"\bgrade_id\x18\x01 \x01(\tR\agradeId\x12\x14\n" +
 	"\x05today\x18\x02 \x01(\tR\x05today\x12\x1a\n" +
 	"\bduration\x18\x03 \x01(\x05R\bduration\"L\n" +
 	"\x17ListDailyPricesResponse\x121\n" +
-	"\fdaily_prices\x18\x01 \x03(\v2\x0e.pb.DailyPriceR\vdailyPrices\"F\n" +
+	"\fdaily_prices\x18\x01 \x03(\v2\x0e.pb.DailyPriceR\vdailyPrices\"g\n" +
 	"\x15GetTodaysPriceRequest\x12\x19\n" +
 	"\bgrade_id\x18\x01 \x01(\tR\agradeId\x12\x12\n" +
-	"\x04date\x18\x02 \x01(\tR\x04date\"K\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12\x1f\n" +
+	"\vprice_basis\x18\x03 \x01(\tR\n" +
+	"priceBasis\"K\n" +
 	"\x16GetTodaysPriceResponse\x121\n" +
-	"\fdaily_prices\x18\x01 \x03(\v2\x0e.pb.DailyPriceR\vdailyPrices\"P\n" +
+	"\fdaily_prices\x18\x01 \x03(\v2\x0e.pb.DailyPriceR\vdailyPrices\"q\n" +
 	"\x1bGetTodaysByProductIdRequest\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
-	"\x04date\x18\x02 \x01(\tR\x04date\"Q\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12\x1f\n" +
+	"\vprice_basis\x18\x03 \x01(\tR\n" +
+	"priceBasis\"Q\n" +
 	"\x1cGetTodaysByProductIdResponse\x121\n" +
-	"\fdaily_prices\x18\x01 \x03(\v2\x0e.pb.DailyPriceR\vdailyPrices\"S\n" +
+	"\fdaily_prices\x18\x01 \x03(\v2\x0e.pb.DailyPriceR\vdailyPrices\"^\n" +
+	"\x15ListPriceTicksRequest\x12\x19\n" +
+	"\bgrade_id\x18\x01 \x01(\tR\agradeId\x12\x12\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\"=\n" +
+	"\x16ListPriceTicksResponse\x12#\n" +
+	"\x05ticks\x18\x01 \x03(\v2\r.pb.PriceTickR\x05ticks\"\xc1\x01\n" +
+	"\x16GetPriceCandlesRequest\x12\x19\n" +
+	"\bgrade_id\x18\x01 \x01(\tR\agradeId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x1a\n" +
+	"\binterval\x18\x03 \x01(\tR\binterval\x12\x1b\n" +
+	"\tdate_from\x18\x04 \x01(\tR\bdateFrom\x12\x17\n" +
+	"\adate_to\x18\x05 \x01(\tR\x06dateTo\x12\x1b\n" +
+	"\tfill_gaps\x18\x06 \x01(\bR\bfillGaps\"\xa4\x02\n" +
+	"\x06Candle\x12!\n" +
+	"\fperiod_start\x18\x01 \x01(\tR\vperiodStart\x12\x1d\n" +
+	"\n" +
+	"period_end\x18\x02 \x01(\tR\tperiodEnd\x12\x12\n" +
+	"\x04open\x18\x03 \x01(\tR\x04open\x12\x12\n" +
+	"\x04high\x18\x04 \x01(\tR\x04high\x12\x10\n" +
+	"\x03low\x18\x05 \x01(\tR\x03low\x12\x14\n" +
+	"\x05close\x18\x06 \x01(\tR\x05close\x12\x1d\n" +
+	"\n" +
+	"tick_count\x18\a \x01(\x05R\ttickCount\x12%\n" +
+	"\x0echange_percent\x18\b \x01(\tR\rchangePercent\x12\x13\n" +
+	"\x05sma_7\x18\t \x01(\tR\x04sma7\x12\x15\n" +
+	"\x06sma_30\x18\n" +
+	" \x01(\tR\x05sma30\x12\x16\n" +
+	"\x06filled\x18\v \x01(\bR\x06filled\"\x89\x01\n" +
+	"\vPriceSeries\x12\x19\n" +
+	"\bgrade_id\x18\x01 \x01(\tR\agradeId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x1a\n" +
+	"\binterval\x18\x03 \x01(\tR\binterval\x12$\n" +
+	"\acandles\x18\x04 \x03(\v2\n" +
+	".pb.CandleR\acandles\"B\n" +
+	"\x17GetPriceCandlesResponse\x12'\n" +
+	"\x06series\x18\x01 \x03(\v2\x0f.pb.PriceSeriesR\x06series\"\x8f\x01\n" +
+	"\x16SubscribePricesRequest\x12\x19\n" +
+	"\bgrade_id\x18\x01 \x01(\tR\agradeId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\tR\x05epoch\x12%\n" +
+	"\x0eafter_sequence\x18\x04 \x01(\x04R\rafterSequence\"o\n" +
+	"\n" +
+	"PriceEvent\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x14\n" +
+	"\x05epoch\x18\x02 \x01(\tR\x05epoch\x12/\n" +
+	"\vdaily_price\x18\x03 \x01(\v2\x0e.pb.DailyPriceR\n" +
+	"dailyPrice\"S\n" +
 	"%GetProductsWithGradesAndPricesRequest\x12\x12\n" +
 	"\x04date\x18\x01 \x01(\tR\x04date\x12\x16\n" +
 	"\x06search\x18\x02 \x01(\tR\x06search\"[\n" +
 	"&GetProductsWithGradesAndPricesResponse\x121\n" +
-	"\bproducts\x18\x01 \x03(\v2\x15.pb.ProductWithGradesR\bproducts\"\x17\n" +
+	"\bproducts\x18\x01 \x03(\v2\x15.pb.ProductWithGradesR\bproducts\"\xdd\x01\n" +
+	"\x06FxRate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rbase_currency\x18\x02 \x01(\tR\fbaseCurrency\x12%\n" +
+	"\x0equote_currency\x18\x03 \x01(\tR\rquoteCurrency\x12\x12\n" +
+	"\x04rate\x18\x04 \x01(\tR\x04rate\x12%\n" +
+	"\x0eeffective_date\x18\x05 \x01(\tR\reffectiveDate\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\x06 \x01(\tR\tupdatedBy\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\"\x99\x01\n" +
+	"\x10SetFxRateRequest\x12#\n" +
+	"\rbase_currency\x18\x01 \x01(\tR\fbaseCurrency\x12%\n" +
+	"\x0equote_currency\x18\x02 \x01(\tR\rquoteCurrency\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\tR\x04rate\x12%\n" +
+	"\x0eeffective_date\x18\x04 \x01(\tR\reffectiveDate\"3\n" +
+	"\x11SetFxRateResponse\x12\x1e\n" +
+	"\x04rate\x18\x01 \x01(\v2\n" +
+	".pb.FxRateR\x04rate\"`\n" +
+	"\x12ListFxRatesRequest\x12#\n" +
+	"\rbase_currency\x18\x01 \x01(\tR\fbaseCurrency\x12%\n" +
+	"\x0equote_currency\x18\x02 \x01(\tR\rquoteCurrency\"7\n" +
+	"\x13ListFxRatesResponse\x12 \n" +
+	"\x05rates\x18\x01 \x03(\v2\n" +
+	".pb.FxRateR\x05rates\"\xbb\x02\n" +
+	"\vFeeSchedule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bgrade_id\x18\x02 \x01(\tR\agradeId\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\tR\x04code\x12\x12\n" +
+	"\x04kind\x18\x05 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04side\x18\x06 \x01(\tR\x04side\x12\x14\n" +
+	"\x05basis\x18\a \x01(\tR\x05basis\x12\x12\n" +
+	"\x04rate\x18\b \x01(\tR\x04rate\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\x12%\n" +
+	"\x0eeffective_date\x18\n" +
+	" \x01(\tR\reffectiveDate\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\v \x01(\tR\tupdatedBy\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\tR\tupdatedAt\"\xf7\x01\n" +
+	"\x15SetFeeScheduleRequest\x12\x19\n" +
+	"\bgrade_id\x18\x01 \x01(\tR\agradeId\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04side\x18\x05 \x01(\tR\x04side\x12\x14\n" +
+	"\x05basis\x18\x06 \x01(\tR\x05basis\x12\x12\n" +
+	"\x04rate\x18\a \x01(\tR\x04rate\x12\x1a\n" +
+	"\bcurrency\x18\b \x01(\tR\bcurrency\x12%\n" +
+	"\x0eeffective_date\x18\t \x01(\tR\reffectiveDate\"E\n" +
+	"\x16SetFeeScheduleResponse\x12+\n" +
+	"\bschedule\x18\x01 \x01(\v2\x0f.pb.FeeScheduleR\bschedule\"P\n" +
+	"\x17ListFeeSchedulesRequest\x12\x19\n" +
+	"\bgrade_id\x18\x01 \x01(\tR\agradeId\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\"I\n" +
+	"\x18ListFeeSchedulesResponse\x12-\n" +
+	"\tschedules\x18\x01 \x03(\v2\x0f.pb.FeeScheduleR\tschedules\"\x8f\x02\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_name\x18\x03 \x01(\tR\n" +
+	"deviceName\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x04 \x01(\tR\tipAddress\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x05 \x01(\tR\tuserAgent\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12 \n" +
+	"\flast_seen_at\x18\a \x01(\tR\n" +
+	"lastSeenAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\tR\texpiresAt\x12\x18\n" +
+	"\acurrent\x18\t \x01(\bR\acurrent\"\x15\n" +
+	"\x13ListSessionsRequest\"?\n" +
+	"\x14ListSessionsResponse\x12'\n" +
+	"\bsessions\x18\x01 \x03(\v2\v.pb.SessionR\bsessions\"5\n" +
+	"\x14RevokeSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"1\n" +
+	"\x15RevokeSessionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x1f\n" +
+	"\x1dRevokeAllOtherSessionsRequest\"2\n" +
+	"\x16RevokeSessionsResponse\x12\x18\n" +
+	"\arevoked\x18\x01 \x01(\rR\arevoked\"3\n" +
+	"\x12ForceLogoutRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"\x17\n" +
 	"\x15GetAccountInfoRequest\"\x18\n" +
-	"\x16GetMerchantInfoRequest2\xb4\x0e\n" +
+	"\x16GetMerchantInfoRequest2\xfe\x1c\n" +
 	"\x0eControlService\x12M\n" +
 	"\x10CheckEmailExists\x12\x1b.pb.CheckEmailExistsRequest\x1a\x1c.pb.CheckEmailExistsResponse\x12\\\n" +
 	"\x15CreateOrUpdateAccount\x12 .pb.CreateOrUpdateAccountRequest\x1a!.pb.CreateOrUpdateAccountResponse\x12G\n" +
@@ -2944,7 +6845,23 @@ const file_control_proto_rawDesc = "" +
 	"\fListAccounts\x12\x17.pb.ListAccountsRequest\x1a\x18.pb.ListAccountsResponse\x12,\n" +
 	"\x05Login\x12\x10.pb.LoginRequest\x1a\x11.pb.LoginResponse\x12/\n" +
 	"\x06Logout\x12\x11.pb.LogoutRequest\x1a\x12.pb.LogoutResponse\x12A\n" +
-	"\fRefreshToken\x12\x17.pb.RefreshTokenRequest\x1a\x18.pb.RefreshTokenResponse\x12t\n" +
+	"\fRefreshToken\x12\x17.pb.RefreshTokenRequest\x1a\x18.pb.RefreshTokenResponse\x12A\n" +
+	"\fListSessions\x12\x17.pb.ListSessionsRequest\x1a\x18.pb.ListSessionsResponse\x12D\n" +
+	"\rRevokeSession\x12\x18.pb.RevokeSessionRequest\x1a\x19.pb.RevokeSessionResponse\x12W\n" +
+	"\x16RevokeAllOtherSessions\x12!.pb.RevokeAllOtherSessionsRequest\x1a\x1a.pb.RevokeSessionsResponse\x12A\n" +
+	"\vForceLogout\x12\x16.pb.ForceLogoutRequest\x1a\x1a.pb.RevokeSessionsResponse\x122\n" +
+	"\aGetJWKS\x12\x12.pb.GetJWKSRequest\x1a\x13.pb.GetJWKSResponse\x12A\n" +
+	"\fCheckSession\x12\x17.pb.CheckSessionRequest\x1a\x18.pb.CheckSessionResponse\x12Y\n" +
+	"\x14RequestPasswordReset\x12\x1f.pb.RequestPasswordResetRequest\x1a .pb.RequestPasswordResetResponse\x12Y\n" +
+	"\x14ConfirmPasswordReset\x12\x1f.pb.ConfirmPasswordResetRequest\x1a .pb.ConfirmPasswordResetResponse\x12\\\n" +
+	"\x15SendVerificationEmail\x12 .pb.SendVerificationEmailRequest\x1a!.pb.SendVerificationEmailResponse\x12>\n" +
+	"\vVerifyEmail\x12\x16.pb.VerifyEmailRequest\x1a\x17.pb.VerifyEmailResponse\x12;\n" +
+	"\n" +
+	"EnrollTOTP\x12\x15.pb.EnrollTOTPRequest\x1a\x16.pb.EnrollTOTPResponse\x12>\n" +
+	"\vConfirmTOTP\x12\x16.pb.ConfirmTOTPRequest\x1a\x17.pb.ConfirmTOTPResponse\x12J\n" +
+	"\x14VerifyLoginChallenge\x12\x1f.pb.VerifyLoginChallengeRequest\x1a\x11.pb.LoginResponse\x12>\n" +
+	"\vDisableTOTP\x12\x16.pb.DisableTOTPRequest\x1a\x17.pb.DisableTOTPResponse\x12b\n" +
+	"\x17RegenerateRecoveryCodes\x12\".pb.RegenerateRecoveryCodesRequest\x1a#.pb.RegenerateRecoveryCodesResponse\x12t\n" +
 	"\x1dCreateOrUpdateMerchantDetails\x12(.pb.CreateOrUpdateMerchantDetailsRequest\x1a).pb.CreateOrUpdateMerchantDetailsResponse\x12S\n" +
 	"\x12GetMerchantDetails\x12\x1d.pb.GetMerchantDetailsRequest\x1a\x1e.pb.GetMerchantDetailsResponse\x12M\n" +
 	"\x0fGetMerchantInfo\x12\x1a.pb.GetMerchantInfoRequest\x1a\x1e.pb.GetMerchantDetailsResponse\x12n\n" +
@@ -2953,12 +6870,22 @@ const file_control_proto_rawDesc = "" +
 	"\fListProducts\x12\x17.pb.ListProductsRequest\x1a\x18.pb.ListProductsResponse\x12V\n" +
 	"\x13CreateOrUpdateGrade\x12\x1e.pb.CreateOrUpdateGradeRequest\x1a\x1f.pb.CreateOrUpdateGradeResponse\x12\\\n" +
 	"\x15ListGradesByProductId\x12 .pb.ListGradesByProductIdRequest\x1a!.pb.ListGradesByProductIdResponse\x12e\n" +
-	"\x18CreateOrUpdateDailyPrice\x12#.pb.CreateOrUpdateDailyPriceRequest\x1a$.pb.CreateOrUpdateDailyPriceResponse\x12J\n" +
+	"\x18CreateOrUpdateDailyPrice\x12#.pb.CreateOrUpdateDailyPriceRequest\x1a$.pb.CreateOrUpdateDailyPriceResponse\x12h\n" +
+	"\x19CreateOrUpdateDailyPrices\x12$.pb.CreateOrUpdateDailyPricesRequest\x1a%.pb.CreateOrUpdateDailyPricesResponse\x12J\n" +
+	"\x0fSubmitPriceTick\x12\x1a.pb.SubmitPriceTickRequest\x1a\x1b.pb.SubmitPriceTickResponse\x12M\n" +
+	"\x10ReviewPriceTicks\x12\x1b.pb.ReviewPriceTicksRequest\x1a\x1c.pb.ReviewPriceTicksResponse\x12J\n" +
 	"\x0fListDailyPrices\x12\x1a.pb.ListDailyPricesRequest\x1a\x1b.pb.ListDailyPricesResponse\x12G\n" +
 	"\x0eGetTodaysPrice\x12\x19.pb.GetTodaysPriceRequest\x1a\x1a.pb.GetTodaysPriceResponse\x12Y\n" +
-	"\x14GetTodaysByProductId\x12\x1f.pb.GetTodaysByProductIdRequest\x1a .pb.GetTodaysByProductIdResponse\x12w\n" +
-	"\x1eGetProductsWithGradesAndPrices\x12).pb.GetProductsWithGradesAndPricesRequest\x1a*.pb.GetProductsWithGradesAndPricesResponse\x12M\n" +
-	"\x10GetSystemMetrics\x12\x1b.pb.GetSystemMetricsRequest\x1a\x1c.pb.GetSystemMetricsResponseB\x06Z\x04./pbb\x06proto3"
+	"\x14GetTodaysByProductId\x12\x1f.pb.GetTodaysByProductIdRequest\x1a .pb.GetTodaysByProductIdResponse\x12G\n" +
+	"\x0eListPriceTicks\x12\x19.pb.ListPriceTicksRequest\x1a\x1a.pb.ListPriceTicksResponse\x12J\n" +
+	"\x0fGetPriceCandles\x12\x1a.pb.GetPriceCandlesRequest\x1a\x1b.pb.GetPriceCandlesResponse\x12w\n" +
+	"\x1eGetProductsWithGradesAndPrices\x12).pb.GetProductsWithGradesAndPricesRequest\x1a*.pb.GetProductsWithGradesAndPricesResponse\x12?\n" +
+	"\x0fSubscribePrices\x12\x1a.pb.SubscribePricesRequest\x1a\x0e.pb.PriceEvent0\x01\x12M\n" +
+	"\x10GetSystemMetrics\x12\x1b.pb.GetSystemMetricsRequest\x1a\x1c.pb.GetSystemMetricsResponse\x128\n" +
+	"\tSetFxRate\x12\x14.pb.SetFxRateRequest\x1a\x15.pb.SetFxRateResponse\x12>\n" +
+	"\vListFxRates\x12\x16.pb.ListFxRatesRequest\x1a\x17.pb.ListFxRatesResponse\x12G\n" +
+	"\x0eSetFeeSchedule\x12\x19.pb.SetFeeScheduleRequest\x1a\x1a.pb.SetFeeScheduleResponse\x12M\n" +
+	"\x10ListFeeSchedules\x12\x1b.pb.ListFeeSchedulesRequest\x1a\x1c.pb.ListFeeSchedulesResponseB\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_control_proto_rawDescOnce sync.Once
@@ -2972,7 +6899,7 @@ func file_control_proto_rawDescGZIP() []byte {
 	return file_control_proto_rawDescData
 }
 
-var file_control_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
+var file_control_proto_msgTypes = make([]protoimpl.MessageInfo, 104)
 var file_control_proto_goTypes = []any{
 	(*Account)(nil),                                // 0: pb.Account
 	(*MerchantDetails)(nil),                        // 1: pb.MerchantDetails
@@ -2981,115 +6908,239 @@ var file_control_proto_goTypes = []any{
 	(*GradeWithPrice)(nil),                         // 4: pb.GradeWithPrice
 	(*ProductWithGrades)(nil),                      // 5: pb.ProductWithGrades
 	(*DailyPrice)(nil),                             // 6: pb.DailyPrice
-	(*CheckEmailExistsRequest)(nil),                // 7: pb.CheckEmailExistsRequest
-	(*CheckEmailExistsResponse)(nil),               // 8: pb.CheckEmailExistsResponse
-	(*CreateOrUpdateAccountRequest)(nil),           // 9: pb.CreateOrUpdateAccountRequest
-	(*CreateOrUpdateAccountResponse)(nil),          // 10: pb.CreateOrUpdateAccountResponse
-	(*GetAccountByIDRequest)(nil),                  // 11: pb.GetAccountByIDRequest
-	(*GetAccountByIDResponse)(nil),                 // 12: pb.GetAccountByIDResponse
-	(*ListAccountsRequest)(nil),                    // 13: pb.ListAccountsRequest
-	(*ListAccountsResponse)(nil),                   // 14: pb.ListAccountsResponse
-	(*LoginRequest)(nil),                           // 15: pb.LoginRequest
-	(*LoginResponse)(nil),                          // 16: pb.LoginResponse
-	(*LogoutRequest)(nil),                          // 17: pb.LogoutRequest
-	(*LogoutResponse)(nil),                         // 18: pb.LogoutResponse
-	(*RefreshTokenRequest)(nil),                    // 19: pb.RefreshTokenRequest
-	(*RefreshTokenResponse)(nil),                   // 20: pb.RefreshTokenResponse
-	(*CreateOrUpdateMerchantDetailsRequest)(nil),   // 21: pb.CreateOrUpdateMerchantDetailsRequest
-	(*CreateOrUpdateMerchantInfoRequest)(nil),      // 22: pb.CreateOrUpdateMerchantInfoRequest
-	(*CreateOrUpdateMerchantDetailsResponse)(nil),  // 23: pb.CreateOrUpdateMerchantDetailsResponse
-	(*GetMerchantDetailsRequest)(nil),              // 24: pb.GetMerchantDetailsRequest
-	(*GetMerchantDetailsResponse)(nil),             // 25: pb.GetMerchantDetailsResponse
-	(*CreateOrUpdateProductRequest)(nil),           // 26: pb.CreateOrUpdateProductRequest
-	(*CreateOrUpdateProductResponse)(nil),          // 27: pb.CreateOrUpdateProductResponse
-	(*ListProductsRequest)(nil),                    // 28: pb.ListProductsRequest
-	(*ListProductsResponse)(nil),                   // 29: pb.ListProductsResponse
-	(*GetSystemMetricsRequest)(nil),                // 30: pb.GetSystemMetricsRequest
-	(*GetSystemMetricsResponse)(nil),               // 31: pb.GetSystemMetricsResponse
-	(*CreateOrUpdateGradeRequest)(nil),             // 32: pb.CreateOrUpdateGradeRequest
-	(*CreateOrUpdateGradeResponse)(nil),            // 33: pb.CreateOrUpdateGradeResponse
-	(*ListGradesByProductIdRequest)(nil),           // 34: pb.ListGradesByProductIdRequest
-	(*ListGradesByProductIdResponse)(nil),          // 35: pb.ListGradesByProductIdResponse
-	(*CreateOrUpdateDailyPriceRequest)(nil),        // 36: pb.CreateOrUpdateDailyPriceRequest
-	(*CreateOrUpdateDailyPriceResponse)(nil),       // 37: pb.CreateOrUpdateDailyPriceResponse
-	(*ListDailyPricesRequest)(nil),                 // 38: pb.ListDailyPricesRequest
-	(*ListDailyPricesResponse)(nil),                // 39: pb.ListDailyPricesResponse
-	(*GetTodaysPriceRequest)(nil),                  // 40: pb.GetTodaysPriceRequest
-	(*GetTodaysPriceResponse)(nil),                 // 41: pb.GetTodaysPriceResponse
-	(*GetTodaysByProductIdRequest)(nil),            // 42: pb.GetTodaysByProductIdRequest
-	(*GetTodaysByProductIdResponse)(nil),           // 43: pb.GetTodaysByProductIdResponse
-	(*GetProductsWithGradesAndPricesRequest)(nil),  // 44: pb.GetProductsWithGradesAndPricesRequest
-	(*GetProductsWithGradesAndPricesResponse)(nil), // 45: pb.GetProductsWithGradesAndPricesResponse
-	(*GetAccountInfoRequest)(nil),                  // 46: pb.GetAccountInfoRequest
-	(*GetMerchantInfoRequest)(nil),                 // 47: pb.GetMerchantInfoRequest
+	(*PriceTick)(nil),                              // 7: pb.PriceTick
+	(*CheckEmailExistsRequest)(nil),                // 8: pb.CheckEmailExistsRequest
+	(*CheckEmailExistsResponse)(nil),               // 9: pb.CheckEmailExistsResponse
+	(*CreateOrUpdateAccountRequest)(nil),           // 10: pb.CreateOrUpdateAccountRequest
+	(*CreateOrUpdateAccountResponse)(nil),          // 11: pb.CreateOrUpdateAccountResponse
+	(*GetAccountByIDRequest)(nil),                  // 12: pb.GetAccountByIDRequest
+	(*GetAccountByIDResponse)(nil),                 // 13: pb.GetAccountByIDResponse
+	(*ListAccountsRequest)(nil),                    // 14: pb.ListAccountsRequest
+	(*ListAccountsResponse)(nil),                   // 15: pb.ListAccountsResponse
+	(*LoginRequest)(nil),                           // 16: pb.LoginRequest
+	(*LoginResponse)(nil),                          // 17: pb.LoginResponse
+	(*LogoutRequest)(nil),                          // 18: pb.LogoutRequest
+	(*LogoutResponse)(nil),                         // 19: pb.LogoutResponse
+	(*RefreshTokenRequest)(nil),                    // 20: pb.RefreshTokenRequest
+	(*RefreshTokenResponse)(nil),                   // 21: pb.RefreshTokenResponse
+	(*RequestPasswordResetRequest)(nil),            // 22: pb.RequestPasswordResetRequest
+	(*RequestPasswordResetResponse)(nil),           // 23: pb.RequestPasswordResetResponse
+	(*ConfirmPasswordResetRequest)(nil),            // 24: pb.ConfirmPasswordResetRequest
+	(*ConfirmPasswordResetResponse)(nil),           // 25: pb.ConfirmPasswordResetResponse
+	(*SendVerificationEmailRequest)(nil),           // 26: pb.SendVerificationEmailRequest
+	(*SendVerificationEmailResponse)(nil),          // 27: pb.SendVerificationEmailResponse
+	(*VerifyEmailRequest)(nil),                     // 28: pb.VerifyEmailRequest
+	(*VerifyEmailResponse)(nil),                    // 29: pb.VerifyEmailResponse
+	(*EnrollTOTPRequest)(nil),                      // 30: pb.EnrollTOTPRequest
+	(*EnrollTOTPResponse)(nil),                     // 31: pb.EnrollTOTPResponse
+	(*ConfirmTOTPRequest)(nil),                     // 32: pb.ConfirmTOTPRequest
+	(*ConfirmTOTPResponse)(nil),                    // 33: pb.ConfirmTOTPResponse
+	(*VerifyLoginChallengeRequest)(nil),            // 34: pb.VerifyLoginChallengeRequest
+	(*DisableTOTPRequest)(nil),                     // 35: pb.DisableTOTPRequest
+	(*DisableTOTPResponse)(nil),                    // 36: pb.DisableTOTPResponse
+	(*RegenerateRecoveryCodesRequest)(nil),         // 37: pb.RegenerateRecoveryCodesRequest
+	(*RegenerateRecoveryCodesResponse)(nil),        // 38: pb.RegenerateRecoveryCodesResponse
+	(*JSONWebKey)(nil),                             // 39: pb.JSONWebKey
+	(*GetJWKSRequest)(nil),                         // 40: pb.GetJWKSRequest
+	(*GetJWKSResponse)(nil),                        // 41: pb.GetJWKSResponse
+	(*CheckSessionRequest)(nil),                    // 42: pb.CheckSessionRequest
+	(*CheckSessionResponse)(nil),                   // 43: pb.CheckSessionResponse
+	(*CreateOrUpdateMerchantDetailsRequest)(nil),   // 44: pb.CreateOrUpdateMerchantDetailsRequest
+	(*CreateOrUpdateMerchantInfoRequest)(nil),      // 45: pb.CreateOrUpdateMerchantInfoRequest
+	(*CreateOrUpdateMerchantDetailsResponse)(nil),  // 46: pb.CreateOrUpdateMerchantDetailsResponse
+	(*GetMerchantDetailsRequest)(nil),              // 47: pb.GetMerchantDetailsRequest
+	(*GetMerchantDetailsResponse)(nil),             // 48: pb.GetMerchantDetailsResponse
+	(*CreateOrUpdateProductRequest)(nil),           // 49: pb.CreateOrUpdateProductRequest
+	(*CreateOrUpdateProductResponse)(nil),          // 50: pb.CreateOrUpdateProductResponse
+	(*ListProductsRequest)(nil),                    // 51: pb.ListProductsRequest
+	(*ListProductsResponse)(nil),                   // 52: pb.ListProductsResponse
+	(*GetSystemMetricsRequest)(nil),                // 53: pb.GetSystemMetricsRequest
+	(*GetSystemMetricsResponse)(nil),               // 54: pb.GetSystemMetricsResponse
+	(*CreateOrUpdateGradeRequest)(nil),             // 55: pb.CreateOrUpdateGradeRequest
+	(*CreateOrUpdateGradeResponse)(nil),            // 56: pb.CreateOrUpdateGradeResponse
+	(*ListGradesByProductIdRequest)(nil),           // 57: pb.ListGradesByProductIdRequest
+	(*ListGradesByProductIdResponse)(nil),          // 58: pb.ListGradesByProductIdResponse
+	(*CreateOrUpdateDailyPriceRequest)(nil),        // 59: pb.CreateOrUpdateDailyPriceRequest
+	(*CreateOrUpdateDailyPriceResponse)(nil),       // 60: pb.CreateOrUpdateDailyPriceResponse
+	(*SubmitPriceTickRequest)(nil),                 // 61: pb.SubmitPriceTickRequest
+	(*SubmitPriceTickResponse)(nil),                // 62: pb.SubmitPriceTickResponse
+	(*ReviewPriceTicksRequest)(nil),                // 63: pb.ReviewPriceTicksRequest
+	(*ReviewPriceTicksResponse)(nil),               // 64: pb.ReviewPriceTicksResponse
+	(*CreateOrUpdateDailyPricesRequest)(nil),       // 65: pb.CreateOrUpdateDailyPricesRequest
+	(*PriceImportRow)(nil),                         // 66: pb.PriceImportRow
+	(*CreateOrUpdateDailyPricesResponse)(nil),      // 67: pb.CreateOrUpdateDailyPricesResponse
+	(*ListDailyPricesRequest)(nil),                 // 68: pb.ListDailyPricesRequest
+	(*ListDailyPricesResponse)(nil),                // 69: pb.ListDailyPricesResponse
+	(*GetTodaysPriceRequest)(nil),                  // 70: pb.GetTodaysPriceRequest
+	(*GetTodaysPriceResponse)(nil),                 // 71: pb.GetTodaysPriceResponse
+	(*GetTodaysByProductIdRequest)(nil),            // 72: pb.GetTodaysByProductIdRequest
+	(*GetTodaysByProductIdResponse)(nil),           // 73: pb.GetTodaysByProductIdResponse
+	(*ListPriceTicksRequest)(nil),                  // 74: pb.ListPriceTicksRequest
+	(*ListPriceTicksResponse)(nil),                 // 75: pb.ListPriceTicksResponse
+	(*GetPriceCandlesRequest)(nil),                 // 76: pb.GetPriceCandlesRequest
+	(*Candle)(nil),                                 // 77: pb.Candle
+	(*PriceSeries)(nil),                            // 78: pb.PriceSeries
+	(*GetPriceCandlesResponse)(nil),                // 79: pb.GetPriceCandlesResponse
+	(*SubscribePricesRequest)(nil),                 // 80: pb.SubscribePricesRequest
+	(*PriceEvent)(nil),                             // 81: pb.PriceEvent
+	(*GetProductsWithGradesAndPricesRequest)(nil),  // 82: pb.GetProductsWithGradesAndPricesRequest
+	(*GetProductsWithGradesAndPricesResponse)(nil), // 83: pb.GetProductsWithGradesAndPricesResponse
+	(*FxRate)(nil),                                 // 84: pb.FxRate
+	(*SetFxRateRequest)(nil),                       // 85: pb.SetFxRateRequest
+	(*SetFxRateResponse)(nil),                      // 86: pb.SetFxRateResponse
+	(*ListFxRatesRequest)(nil),                     // 87: pb.ListFxRatesRequest
+	(*ListFxRatesResponse)(nil),                    // 88: pb.ListFxRatesResponse
+	(*FeeSchedule)(nil),                            // 89: pb.FeeSchedule
+	(*SetFeeScheduleRequest)(nil),                  // 90: pb.SetFeeScheduleRequest
+	(*SetFeeScheduleResponse)(nil),                 // 91: pb.SetFeeScheduleResponse
+	(*ListFeeSchedulesRequest)(nil),                // 92: pb.ListFeeSchedulesRequest
+	(*ListFeeSchedulesResponse)(nil),               // 93: pb.ListFeeSchedulesResponse
+	(*Session)(nil),                                // 94: pb.Session
+	(*ListSessionsRequest)(nil),                    // 95: pb.ListSessionsRequest
+	(*ListSessionsResponse)(nil),                   // 96: pb.ListSessionsResponse
+	(*RevokeSessionRequest)(nil),                   // 97: pb.RevokeSessionRequest
+	(*RevokeSessionResponse)(nil),                  // 98: pb.RevokeSessionResponse
+	(*RevokeAllOtherSessionsRequest)(nil),          // 99: pb.RevokeAllOtherSessionsRequest
+	(*RevokeSessionsResponse)(nil),                 // 100: pb.RevokeSessionsResponse
+	(*ForceLogoutRequest)(nil),                     // 101: pb.ForceLogoutRequest
+	(*GetAccountInfoRequest)(nil),                  // 102: pb.GetAccountInfoRequest
+	(*GetMerchantInfoRequest)(nil),                 // 103: pb.GetMerchantInfoRequest
 }
 var file_control_proto_depIdxs = []int32{
-	4,  // 0: pb.ProductWithGrades.grades:type_name -> pb.GradeWithPrice
-	0,  // 1: pb.CreateOrUpdateAccountResponse.account:type_name -> pb.Account
-	0,  // 2: pb.GetAccountByIDResponse.account:type_name -> pb.Account
-	0,  // 3: pb.ListAccountsResponse.accounts:type_name -> pb.Account
-	0,  // 4: pb.LoginResponse.account:type_name -> pb.Account
-	0,  // 5: pb.RefreshTokenResponse.account:type_name -> pb.Account
-	1,  // 6: pb.CreateOrUpdateMerchantDetailsResponse.merchant_details:type_name -> pb.MerchantDetails
-	1,  // 7: pb.GetMerchantDetailsResponse.merchant_details:type_name -> pb.MerchantDetails
-	2,  // 8: pb.CreateOrUpdateProductResponse.product:type_name -> pb.Product
-	2,  // 9: pb.ListProductsResponse.products:type_name -> pb.Product
-	3,  // 10: pb.CreateOrUpdateGradeResponse.grade:type_name -> pb.Grade
-	3,  // 11: pb.ListGradesByProductIdResponse.grades:type_name -> pb.Grade
-	6,  // 12: pb.CreateOrUpdateDailyPriceResponse.daily_price:type_name -> pb.DailyPrice
-	6,  // 13: pb.ListDailyPricesResponse.daily_prices:type_name -> pb.DailyPrice
-	6,  // 14: pb.GetTodaysPriceResponse.daily_prices:type_name -> pb.DailyPrice
-	6,  // 15: pb.GetTodaysByProductIdResponse.daily_prices:type_name -> pb.DailyPrice
-	5,  // 16: pb.GetProductsWithGradesAndPricesResponse.products:type_name -> pb.ProductWithGrades
-	7,  // 17: pb.ControlService.CheckEmailExists:input_type -> pb.CheckEmailExistsRequest
-	9,  // 18: pb.ControlService.CreateOrUpdateAccount:input_type -> pb.CreateOrUpdateAccountRequest
-	11, // 19: pb.ControlService.GetAccountByID:input_type -> pb.GetAccountByIDRequest
-	46, // 20: pb.ControlService.GetAccountInfo:input_type -> pb.GetAccountInfoRequest
-	13, // 21: pb.ControlService.ListAccounts:input_type -> pb.ListAccountsRequest
-	15, // 22: pb.ControlService.Login:input_type -> pb.LoginRequest
-	17, // 23: pb.ControlService.Logout:input_type -> pb.LogoutRequest
-	19, // 24: pb.ControlService.RefreshToken:input_type -> pb.RefreshTokenRequest
-	21, // 25: pb.ControlService.CreateOrUpdateMerchantDetails:input_type -> pb.CreateOrUpdateMerchantDetailsRequest
-	24, // 26: pb.ControlService.GetMerchantDetails:input_type -> pb.GetMerchantDetailsRequest
-	47, // 27: pb.ControlService.GetMerchantInfo:input_type -> pb.GetMerchantInfoRequest
-	22, // 28: pb.ControlService.CreateOrUpdateMerchantInfo:input_type -> pb.CreateOrUpdateMerchantInfoRequest
-	26, // 29: pb.ControlService.CreateOrUpdateProduct:input_type -> pb.CreateOrUpdateProductRequest
-	28, // 30: pb.ControlService.ListProducts:input_type -> pb.ListProductsRequest
-	32, // 31: pb.ControlService.CreateOrUpdateGrade:input_type -> pb.CreateOrUpdateGradeRequest
-	34, // 32: pb.ControlService.ListGradesByProductId:input_type -> pb.ListGradesByProductIdRequest
-	36, // 33: pb.ControlService.CreateOrUpdateDailyPrice:input_type -> pb.CreateOrUpdateDailyPriceRequest
-	38, // 34: pb.ControlService.ListDailyPrices:input_type -> pb.ListDailyPricesRequest
-	40, // 35: pb.ControlService.GetTodaysPrice:input_type -> pb.GetTodaysPriceRequest
-	42, // 36: pb.ControlService.GetTodaysByProductId:input_type -> pb.GetTodaysByProductIdRequest
-	44, // 37: pb.ControlService.GetProductsWithGradesAndPrices:input_type -> pb.GetProductsWithGradesAndPricesRequest
-	30, // 38: pb.ControlService.GetSystemMetrics:input_type -> pb.GetSystemMetricsRequest
-	8,  // 39: pb.ControlService.CheckEmailExists:output_type -> pb.CheckEmailExistsResponse
-	10, // 40: pb.ControlService.CreateOrUpdateAccount:output_type -> pb.CreateOrUpdateAccountResponse
-	12, // 41: pb.ControlService.GetAccountByID:output_type -> pb.GetAccountByIDResponse
-	12, // 42: pb.ControlService.GetAccountInfo:output_type -> pb.GetAccountByIDResponse
-	14, // 43: pb.ControlService.ListAccounts:output_type -> pb.ListAccountsResponse
-	16, // 44: pb.ControlService.Login:output_type -> pb.LoginResponse
-	18, // 45: pb.ControlService.Logout:output_type -> pb.LogoutResponse
-	20, // 46: pb.ControlService.RefreshToken:output_type -> pb.RefreshTokenResponse
-	23, // 47: pb.ControlService.CreateOrUpdateMerchantDetails:output_type -> pb.CreateOrUpdateMerchantDetailsResponse
-	25, // 48: pb.ControlService.GetMerchantDetails:output_type -> pb.GetMerchantDetailsResponse
-	25, // 49: pb.ControlService.GetMerchantInfo:output_type -> pb.GetMerchantDetailsResponse
-	23, // 50: pb.ControlService.CreateOrUpdateMerchantInfo:output_type -> pb.CreateOrUpdateMerchantDetailsResponse
-	27, // 51: pb.ControlService.CreateOrUpdateProduct:output_type -> pb.CreateOrUpdateProductResponse
-	29, // 52: pb.ControlService.ListProducts:output_type -> pb.ListProductsResponse
-	33, // 53: pb.ControlService.CreateOrUpdateGrade:output_type -> pb.CreateOrUpdateGradeResponse
-	35, // 54: pb.ControlService.ListGradesByProductId:output_type -> pb.ListGradesByProductIdResponse
-	37, // 55: pb.ControlService.CreateOrUpdateDailyPrice:output_type -> pb.CreateOrUpdateDailyPriceResponse
-	39, // 56: pb.ControlService.ListDailyPrices:output_type -> pb.ListDailyPricesResponse
-	41, // 57: pb.ControlService.GetTodaysPrice:output_type -> pb.GetTodaysPriceResponse
-	43, // 58: pb.ControlService.GetTodaysByProductId:output_type -> pb.GetTodaysByProductIdResponse
-	45, // 59: pb.ControlService.GetProductsWithGradesAndPrices:output_type -> pb.GetProductsWithGradesAndPricesResponse
-	31, // 60: pb.ControlService.GetSystemMetrics:output_type -> pb.GetSystemMetricsResponse
-	39, // [39:61] is the sub-list for method output_type
-	17, // [17:39] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	4,   // 0: pb.ProductWithGrades.grades:type_name -> pb.GradeWithPrice
+	0,   // 1: pb.CreateOrUpdateAccountResponse.account:type_name -> pb.Account
+	0,   // 2: pb.GetAccountByIDResponse.account:type_name -> pb.Account
+	0,   // 3: pb.ListAccountsResponse.accounts:type_name -> pb.Account
+	0,   // 4: pb.LoginResponse.account:type_name -> pb.Account
+	0,   // 5: pb.RefreshTokenResponse.account:type_name -> pb.Account
+	0,   // 6: pb.VerifyEmailResponse.account:type_name -> pb.Account
+	17,  // 7: pb.ConfirmTOTPResponse.login:type_name -> pb.LoginResponse
+	39,  // 8: pb.GetJWKSResponse.keys:type_name -> pb.JSONWebKey
+	1,   // 9: pb.CreateOrUpdateMerchantDetailsResponse.merchant_details:type_name -> pb.MerchantDetails
+	1,   // 10: pb.GetMerchantDetailsResponse.merchant_details:type_name -> pb.MerchantDetails
+	2,   // 11: pb.CreateOrUpdateProductResponse.product:type_name -> pb.Product
+	2,   // 12: pb.ListProductsResponse.products:type_name -> pb.Product
+	3,   // 13: pb.CreateOrUpdateGradeResponse.grade:type_name -> pb.Grade
+	3,   // 14: pb.ListGradesByProductIdResponse.grades:type_name -> pb.Grade
+	7,   // 15: pb.CreateOrUpdateDailyPriceResponse.tick:type_name -> pb.PriceTick
+	7,   // 16: pb.SubmitPriceTickResponse.tick:type_name -> pb.PriceTick
+	7,   // 17: pb.ReviewPriceTicksResponse.ticks:type_name -> pb.PriceTick
+	6,   // 18: pb.ReviewPriceTicksResponse.daily_prices:type_name -> pb.DailyPrice
+	59,  // 19: pb.CreateOrUpdateDailyPricesRequest.prices:type_name -> pb.CreateOrUpdateDailyPriceRequest
+	7,   // 20: pb.PriceImportRow.tick:type_name -> pb.PriceTick
+	66,  // 21: pb.CreateOrUpdateDailyPricesResponse.rows:type_name -> pb.PriceImportRow
+	6,   // 22: pb.ListDailyPricesResponse.daily_prices:type_name -> pb.DailyPrice
+	6,   // 23: pb.GetTodaysPriceResponse.daily_prices:type_name -> pb.DailyPrice
+	6,   // 24: pb.GetTodaysByProductIdResponse.daily_prices:type_name -> pb.DailyPrice
+	7,   // 25: pb.ListPriceTicksResponse.ticks:type_name -> pb.PriceTick
+	77,  // 26: pb.PriceSeries.candles:type_name -> pb.Candle
+	78,  // 27: pb.GetPriceCandlesResponse.series:type_name -> pb.PriceSeries
+	6,   // 28: pb.PriceEvent.daily_price:type_name -> pb.DailyPrice
+	5,   // 29: pb.GetProductsWithGradesAndPricesResponse.products:type_name -> pb.ProductWithGrades
+	84,  // 30: pb.SetFxRateResponse.rate:type_name -> pb.FxRate
+	84,  // 31: pb.ListFxRatesResponse.rates:type_name -> pb.FxRate
+	89,  // 32: pb.SetFeeScheduleResponse.schedule:type_name -> pb.FeeSchedule
+	89,  // 33: pb.ListFeeSchedulesResponse.schedules:type_name -> pb.FeeSchedule
+	94,  // 34: pb.ListSessionsResponse.sessions:type_name -> pb.Session
+	8,   // 35: pb.ControlService.CheckEmailExists:input_type -> pb.CheckEmailExistsRequest
+	10,  // 36: pb.ControlService.CreateOrUpdateAccount:input_type -> pb.CreateOrUpdateAccountRequest
+	12,  // 37: pb.ControlService.GetAccountByID:input_type -> pb.GetAccountByIDRequest
+	102, // 38: pb.ControlService.GetAccountInfo:input_type -> pb.GetAccountInfoRequest
+	14,  // 39: pb.ControlService.ListAccounts:input_type -> pb.ListAccountsRequest
+	16,  // 40: pb.ControlService.Login:input_type -> pb.LoginRequest
+	18,  // 41: pb.ControlService.Logout:input_type -> pb.LogoutRequest
+	20,  // 42: pb.ControlService.RefreshToken:input_type -> pb.RefreshTokenRequest
+	95,  // 43: pb.ControlService.ListSessions:input_type -> pb.ListSessionsRequest
+	97,  // 44: pb.ControlService.RevokeSession:input_type -> pb.RevokeSessionRequest
+	99,  // 45: pb.ControlService.RevokeAllOtherSessions:input_type -> pb.RevokeAllOtherSessionsRequest
+	101, // 46: pb.ControlService.ForceLogout:input_type -> pb.ForceLogoutRequest
+	40,  // 47: pb.ControlService.GetJWKS:input_type -> pb.GetJWKSRequest
+	42,  // 48: pb.ControlService.CheckSession:input_type -> pb.CheckSessionRequest
+	22,  // 49: pb.ControlService.RequestPasswordReset:input_type -> pb.RequestPasswordResetRequest
+	24,  // 50: pb.ControlService.ConfirmPasswordReset:input_type -> pb.ConfirmPasswordResetRequest
+	26,  // 51: pb.ControlService.SendVerificationEmail:input_type -> pb.SendVerificationEmailRequest
+	28,  // 52: pb.ControlService.VerifyEmail:input_type -> pb.VerifyEmailRequest
+	30,  // 53: pb.ControlService.EnrollTOTP:input_type -> pb.EnrollTOTPRequest
+	32,  // 54: pb.ControlService.ConfirmTOTP:input_type -> pb.ConfirmTOTPRequest
+	34,  // 55: pb.ControlService.VerifyLoginChallenge:input_type -> pb.VerifyLoginChallengeRequest
+	35,  // 56: pb.ControlService.DisableTOTP:input_type -> pb.DisableTOTPRequest
+	37,  // 57: pb.ControlService.RegenerateRecoveryCodes:input_type -> pb.RegenerateRecoveryCodesRequest
+	44,  // 58: pb.ControlService.CreateOrUpdateMerchantDetails:input_type -> pb.CreateOrUpdateMerchantDetailsRequest
+	47,  // 59: pb.ControlService.GetMerchantDetails:input_type -> pb.GetMerchantDetailsRequest
+	103, // 60: pb.ControlService.GetMerchantInfo:input_type -> pb.GetMerchantInfoRequest
+	45,  // 61: pb.ControlService.CreateOrUpdateMerchantInfo:input_type -> pb.CreateOrUpdateMerchantInfoRequest
+	49,  // 62: pb.ControlService.CreateOrUpdateProduct:input_type -> pb.CreateOrUpdateProductRequest
+	51,  // 63: pb.ControlService.ListProducts:input_type -> pb.ListProductsRequest
+	55,  // 64: pb.ControlService.CreateOrUpdateGrade:input_type -> pb.CreateOrUpdateGradeRequest
+	57,  // 65: pb.ControlService.ListGradesByProductId:input_type -> pb.ListGradesByProductIdRequest
+	59,  // 66: pb.ControlService.CreateOrUpdateDailyPrice:input_type -> pb.CreateOrUpdateDailyPriceRequest
+	65,  // 67: pb.ControlService.CreateOrUpdateDailyPrices:input_type -> pb.CreateOrUpdateDailyPricesRequest
+	61,  // 68: pb.ControlService.SubmitPriceTick:input_type -> pb.SubmitPriceTickRequest
+	63,  // 69: pb.ControlService.ReviewPriceTicks:input_type -> pb.ReviewPriceTicksRequest
+	68,  // 70: pb.ControlService.ListDailyPrices:input_type -> pb.ListDailyPricesRequest
+	70,  // 71: pb.ControlService.GetTodaysPrice:input_type -> pb.GetTodaysPriceRequest
+	72,  // 72: pb.ControlService.GetTodaysByProductId:input_type -> pb.GetTodaysByProductIdRequest
+	74,  // 73: pb.ControlService.ListPriceTicks:input_type -> pb.ListPriceTicksRequest
+	76,  // 74: pb.ControlService.GetPriceCandles:input_type -> pb.GetPriceCandlesRequest
+	82,  // 75: pb.ControlService.GetProductsWithGradesAndPrices:input_type -> pb.GetProductsWithGradesAndPricesRequest
+	80,  // 76: pb.ControlService.SubscribePrices:input_type -> pb.SubscribePricesRequest
+	53,  // 77: pb.ControlService.GetSystemMetrics:input_type -> pb.GetSystemMetricsRequest
+	85,  // 78: pb.ControlService.SetFxRate:input_type -> pb.SetFxRateRequest
+	87,  // 79: pb.ControlService.ListFxRates:input_type -> pb.ListFxRatesRequest
+	90,  // 80: pb.ControlService.SetFeeSchedule:input_type -> pb.SetFeeScheduleRequest
+	92,  // 81: pb.ControlService.ListFeeSchedules:input_type -> pb.ListFeeSchedulesRequest
+	9,   // 82: pb.ControlService.CheckEmailExists:output_type -> pb.CheckEmailExistsResponse
+	11,  // 83: pb.ControlService.CreateOrUpdateAccount:output_type -> pb.CreateOrUpdateAccountResponse
+	13,  // 84: pb.ControlService.GetAccountByID:output_type -> pb.GetAccountByIDResponse
+	13,  // 85: pb.ControlService.GetAccountInfo:output_type -> pb.GetAccountByIDResponse
+	15,  // 86: pb.ControlService.ListAccounts:output_type -> pb.ListAccountsResponse
+	17,  // 87: pb.ControlService.Login:output_type -> pb.LoginResponse
+	19,  // 88: pb.ControlService.Logout:output_type -> pb.LogoutResponse
+	21,  // 89: pb.ControlService.RefreshToken:output_type -> pb.RefreshTokenResponse
+	96,  // 90: pb.ControlService.ListSessions:output_type -> pb.ListSessionsResponse
+	98,  // 91: pb.ControlService.RevokeSession:output_type -> pb.RevokeSessionResponse
+	100, // 92: pb.ControlService.RevokeAllOtherSessions:output_type -> pb.RevokeSessionsResponse
+	100, // 93: pb.ControlService.ForceLogout:output_type -> pb.RevokeSessionsResponse
+	41,  // 94: pb.ControlService.GetJWKS:output_type -> pb.GetJWKSResponse
+	43,  // 95: pb.ControlService.CheckSession:output_type -> pb.CheckSessionResponse
+	23,  // 96: pb.ControlService.RequestPasswordReset:output_type -> pb.RequestPasswordResetResponse
+	25,  // 97: pb.ControlService.ConfirmPasswordReset:output_type -> pb.ConfirmPasswordResetResponse
+	27,  // 98: pb.ControlService.SendVerificationEmail:output_type -> pb.SendVerificationEmailResponse
+	29,  // 99: pb.ControlService.VerifyEmail:output_type -> pb.VerifyEmailResponse
+	31,  // 100: pb.ControlService.EnrollTOTP:output_type -> pb.EnrollTOTPResponse
+	33,  // 101: pb.ControlService.ConfirmTOTP:output_type -> pb.ConfirmTOTPResponse
+	17,  // 102: pb.ControlService.VerifyLoginChallenge:output_type -> pb.LoginResponse
+	36,  // 103: pb.ControlService.DisableTOTP:output_type -> pb.DisableTOTPResponse
+	38,  // 104: pb.ControlService.RegenerateRecoveryCodes:output_type -> pb.RegenerateRecoveryCodesResponse
+	46,  // 105: pb.ControlService.CreateOrUpdateMerchantDetails:output_type -> pb.CreateOrUpdateMerchantDetailsResponse
+	48,  // 106: pb.ControlService.GetMerchantDetails:output_type -> pb.GetMerchantDetailsResponse
+	48,  // 107: pb.ControlService.GetMerchantInfo:output_type -> pb.GetMerchantDetailsResponse
+	46,  // 108: pb.ControlService.CreateOrUpdateMerchantInfo:output_type -> pb.CreateOrUpdateMerchantDetailsResponse
+	50,  // 109: pb.ControlService.CreateOrUpdateProduct:output_type -> pb.CreateOrUpdateProductResponse
+	52,  // 110: pb.ControlService.ListProducts:output_type -> pb.ListProductsResponse
+	56,  // 111: pb.ControlService.CreateOrUpdateGrade:output_type -> pb.CreateOrUpdateGradeResponse
+	58,  // 112: pb.ControlService.ListGradesByProductId:output_type -> pb.ListGradesByProductIdResponse
+	60,  // 113: pb.ControlService.CreateOrUpdateDailyPrice:output_type -> pb.CreateOrUpdateDailyPriceResponse
+	67,  // 114: pb.ControlService.CreateOrUpdateDailyPrices:output_type -> pb.CreateOrUpdateDailyPricesResponse
+	62,  // 115: pb.ControlService.SubmitPriceTick:output_type -> pb.SubmitPriceTickResponse
+	64,  // 116: pb.ControlService.ReviewPriceTicks:output_type -> pb.ReviewPriceTicksResponse
+	69,  // 117: pb.ControlService.ListDailyPrices:output_type -> pb.ListDailyPricesResponse
+	71,  // 118: pb.ControlService.GetTodaysPrice:output_type -> pb.GetTodaysPriceResponse
+	73,  // 119: pb.ControlService.GetTodaysByProductId:output_type -> pb.GetTodaysByProductIdResponse
+	75,  // 120: pb.ControlService.ListPriceTicks:output_type -> pb.ListPriceTicksResponse
+	79,  // 121: pb.ControlService.GetPriceCandles:output_type -> pb.GetPriceCandlesResponse
+	83,  // 122: pb.ControlService.GetProductsWithGradesAndPrices:output_type -> pb.GetProductsWithGradesAndPricesResponse
+	81,  // 123: pb.ControlService.SubscribePrices:output_type -> pb.PriceEvent
+	54,  // 124: pb.ControlService.GetSystemMetrics:output_type -> pb.GetSystemMetricsResponse
+	86,  // 125: pb.ControlService.SetFxRate:output_type -> pb.SetFxRateResponse
+	88,  // 126: pb.ControlService.ListFxRates:output_type -> pb.ListFxRatesResponse
+	91,  // 127: pb.ControlService.SetFeeSchedule:output_type -> pb.SetFeeScheduleResponse
+	93,  // 128: pb.ControlService.ListFeeSchedules:output_type -> pb.ListFeeSchedulesResponse
+	82,  // [82:129] is the sub-list for method output_type
+	35,  // [35:82] is the sub-list for method input_type
+	35,  // [35:35] is the sub-list for extension type_name
+	35,  // [35:35] is the sub-list for extension extendee
+	0,   // [0:35] is the sub-list for field type_name
 }
 
 func init() { file_control_proto_init() }
@@ -3103,7 +7154,7 @@ func file_control_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_control_proto_rawDesc), len(file_control_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   48,
+			NumMessages:   104,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -2,7 +2,7 @@
 
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
-// - protoc-gen-go-grpc v1.6.1
+// - protoc-gen-go-grpc v1.6.2
 // - protoc             v7.34.1
 // source: control.proto
 
@@ -29,6 +29,21 @@ const (
 	ControlService_Login_FullMethodName                          = "/pb.ControlService/Login"
 	ControlService_Logout_FullMethodName                         = "/pb.ControlService/Logout"
 	ControlService_RefreshToken_FullMethodName                   = "/pb.ControlService/RefreshToken"
+	ControlService_ListSessions_FullMethodName                   = "/pb.ControlService/ListSessions"
+	ControlService_RevokeSession_FullMethodName                  = "/pb.ControlService/RevokeSession"
+	ControlService_RevokeAllOtherSessions_FullMethodName         = "/pb.ControlService/RevokeAllOtherSessions"
+	ControlService_ForceLogout_FullMethodName                    = "/pb.ControlService/ForceLogout"
+	ControlService_GetJWKS_FullMethodName                        = "/pb.ControlService/GetJWKS"
+	ControlService_CheckSession_FullMethodName                   = "/pb.ControlService/CheckSession"
+	ControlService_RequestPasswordReset_FullMethodName           = "/pb.ControlService/RequestPasswordReset"
+	ControlService_ConfirmPasswordReset_FullMethodName           = "/pb.ControlService/ConfirmPasswordReset"
+	ControlService_SendVerificationEmail_FullMethodName          = "/pb.ControlService/SendVerificationEmail"
+	ControlService_VerifyEmail_FullMethodName                    = "/pb.ControlService/VerifyEmail"
+	ControlService_EnrollTOTP_FullMethodName                     = "/pb.ControlService/EnrollTOTP"
+	ControlService_ConfirmTOTP_FullMethodName                    = "/pb.ControlService/ConfirmTOTP"
+	ControlService_VerifyLoginChallenge_FullMethodName           = "/pb.ControlService/VerifyLoginChallenge"
+	ControlService_DisableTOTP_FullMethodName                    = "/pb.ControlService/DisableTOTP"
+	ControlService_RegenerateRecoveryCodes_FullMethodName        = "/pb.ControlService/RegenerateRecoveryCodes"
 	ControlService_CreateOrUpdateMerchantDetails_FullMethodName  = "/pb.ControlService/CreateOrUpdateMerchantDetails"
 	ControlService_GetMerchantDetails_FullMethodName             = "/pb.ControlService/GetMerchantDetails"
 	ControlService_GetMerchantInfo_FullMethodName                = "/pb.ControlService/GetMerchantInfo"
@@ -38,11 +53,21 @@ const (
 	ControlService_CreateOrUpdateGrade_FullMethodName            = "/pb.ControlService/CreateOrUpdateGrade"
 	ControlService_ListGradesByProductId_FullMethodName          = "/pb.ControlService/ListGradesByProductId"
 	ControlService_CreateOrUpdateDailyPrice_FullMethodName       = "/pb.ControlService/CreateOrUpdateDailyPrice"
+	ControlService_CreateOrUpdateDailyPrices_FullMethodName      = "/pb.ControlService/CreateOrUpdateDailyPrices"
+	ControlService_SubmitPriceTick_FullMethodName                = "/pb.ControlService/SubmitPriceTick"
+	ControlService_ReviewPriceTicks_FullMethodName               = "/pb.ControlService/ReviewPriceTicks"
 	ControlService_ListDailyPrices_FullMethodName                = "/pb.ControlService/ListDailyPrices"
 	ControlService_GetTodaysPrice_FullMethodName                 = "/pb.ControlService/GetTodaysPrice"
 	ControlService_GetTodaysByProductId_FullMethodName           = "/pb.ControlService/GetTodaysByProductId"
+	ControlService_ListPriceTicks_FullMethodName                 = "/pb.ControlService/ListPriceTicks"
+	ControlService_GetPriceCandles_FullMethodName                = "/pb.ControlService/GetPriceCandles"
 	ControlService_GetProductsWithGradesAndPrices_FullMethodName = "/pb.ControlService/GetProductsWithGradesAndPrices"
+	ControlService_SubscribePrices_FullMethodName                = "/pb.ControlService/SubscribePrices"
 	ControlService_GetSystemMetrics_FullMethodName               = "/pb.ControlService/GetSystemMetrics"
+	ControlService_SetFxRate_FullMethodName                      = "/pb.ControlService/SetFxRate"
+	ControlService_ListFxRates_FullMethodName                    = "/pb.ControlService/ListFxRates"
+	ControlService_SetFeeSchedule_FullMethodName                 = "/pb.ControlService/SetFeeSchedule"
+	ControlService_ListFeeSchedules_FullMethodName               = "/pb.ControlService/ListFeeSchedules"
 )
 
 // ControlServiceClient is the client API for ControlService service.
@@ -57,6 +82,21 @@ type ControlServiceClient interface {
 	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
 	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
 	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error)
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
+	RevokeAllOtherSessions(ctx context.Context, in *RevokeAllOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeSessionsResponse, error)
+	ForceLogout(ctx context.Context, in *ForceLogoutRequest, opts ...grpc.CallOption) (*RevokeSessionsResponse, error)
+	GetJWKS(ctx context.Context, in *GetJWKSRequest, opts ...grpc.CallOption) (*GetJWKSResponse, error)
+	CheckSession(ctx context.Context, in *CheckSessionRequest, opts ...grpc.CallOption) (*CheckSessionResponse, error)
+	RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest, opts ...grpc.CallOption) (*RequestPasswordResetResponse, error)
+	ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest, opts ...grpc.CallOption) (*ConfirmPasswordResetResponse, error)
+	SendVerificationEmail(ctx context.Context, in *SendVerificationEmailRequest, opts ...grpc.CallOption) (*SendVerificationEmailResponse, error)
+	VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*VerifyEmailResponse, error)
+	EnrollTOTP(ctx context.Context, in *EnrollTOTPRequest, opts ...grpc.CallOption) (*EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, in *ConfirmTOTPRequest, opts ...grpc.CallOption) (*ConfirmTOTPResponse, error)
+	VerifyLoginChallenge(ctx context.Context, in *VerifyLoginChallengeRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	DisableTOTP(ctx context.Context, in *DisableTOTPRequest, opts ...grpc.CallOption) (*DisableTOTPResponse, error)
+	RegenerateRecoveryCodes(ctx context.Context, in *RegenerateRecoveryCodesRequest, opts ...grpc.CallOption) (*RegenerateRecoveryCodesResponse, error)
 	CreateOrUpdateMerchantDetails(ctx context.Context, in *CreateOrUpdateMerchantDetailsRequest, opts ...grpc.CallOption) (*CreateOrUpdateMerchantDetailsResponse, error)
 	GetMerchantDetails(ctx context.Context, in *GetMerchantDetailsRequest, opts ...grpc.CallOption) (*GetMerchantDetailsResponse, error)
 	GetMerchantInfo(ctx context.Context, in *GetMerchantInfoRequest, opts ...grpc.CallOption) (*GetMerchantDetailsResponse, error)
@@ -69,11 +109,23 @@ type ControlServiceClient interface {
 	ListGradesByProductId(ctx context.Context, in *ListGradesByProductIdRequest, opts ...grpc.CallOption) (*ListGradesByProductIdResponse, error)
 	// Daily Price Management
 	CreateOrUpdateDailyPrice(ctx context.Context, in *CreateOrUpdateDailyPriceRequest, opts ...grpc.CallOption) (*CreateOrUpdateDailyPriceResponse, error)
+	CreateOrUpdateDailyPrices(ctx context.Context, in *CreateOrUpdateDailyPricesRequest, opts ...grpc.CallOption) (*CreateOrUpdateDailyPricesResponse, error)
+	SubmitPriceTick(ctx context.Context, in *SubmitPriceTickRequest, opts ...grpc.CallOption) (*SubmitPriceTickResponse, error)
+	ReviewPriceTicks(ctx context.Context, in *ReviewPriceTicksRequest, opts ...grpc.CallOption) (*ReviewPriceTicksResponse, error)
 	ListDailyPrices(ctx context.Context, in *ListDailyPricesRequest, opts ...grpc.CallOption) (*ListDailyPricesResponse, error)
 	GetTodaysPrice(ctx context.Context, in *GetTodaysPriceRequest, opts ...grpc.CallOption) (*GetTodaysPriceResponse, error)
 	GetTodaysByProductId(ctx context.Context, in *GetTodaysByProductIdRequest, opts ...grpc.CallOption) (*GetTodaysByProductIdResponse, error)
+	ListPriceTicks(ctx context.Context, in *ListPriceTicksRequest, opts ...grpc.CallOption) (*ListPriceTicksResponse, error)
+	GetPriceCandles(ctx context.Context, in *GetPriceCandlesRequest, opts ...grpc.CallOption) (*GetPriceCandlesResponse, error)
 	GetProductsWithGradesAndPrices(ctx context.Context, in *GetProductsWithGradesAndPricesRequest, opts ...grpc.CallOption) (*GetProductsWithGradesAndPricesResponse, error)
+	SubscribePrices(ctx context.Context, in *SubscribePricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PriceEvent], error)
 	GetSystemMetrics(ctx context.Context, in *GetSystemMetricsRequest, opts ...grpc.CallOption) (*GetSystemMetricsResponse, error)
+	// FX Rates
+	SetFxRate(ctx context.Context, in *SetFxRateRequest, opts ...grpc.CallOption) (*SetFxRateResponse, error)
+	ListFxRates(ctx context.Context, in *ListFxRatesRequest, opts ...grpc.CallOption) (*ListFxRatesResponse, error)
+	// Fee Schedules
+	SetFeeSchedule(ctx context.Context, in *SetFeeScheduleRequest, opts ...grpc.CallOption) (*SetFeeScheduleResponse, error)
+	ListFeeSchedules(ctx context.Context, in *ListFeeSchedulesRequest, opts ...grpc.CallOption) (*ListFeeSchedulesResponse, error)
 }
 
 type controlServiceClient struct {
@@ -164,6 +216,156 @@ func (c *controlServiceClient) RefreshToken(ctx context.Context, in *RefreshToke
 	return out, nil
 }
 
+func (c *controlServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, ControlService_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, ControlService_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) RevokeAllOtherSessions(ctx context.Context, in *RevokeAllOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionsResponse)
+	err := c.cc.Invoke(ctx, ControlService_RevokeAllOtherSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ForceLogout(ctx context.Context, in *ForceLogoutRequest, opts ...grpc.CallOption) (*RevokeSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionsResponse)
+	err := c.cc.Invoke(ctx, ControlService_ForceLogout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) GetJWKS(ctx context.Context, in *GetJWKSRequest, opts ...grpc.CallOption) (*GetJWKSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJWKSResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetJWKS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) CheckSession(ctx context.Context, in *CheckSessionRequest, opts ...grpc.CallOption) (*CheckSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckSessionResponse)
+	err := c.cc.Invoke(ctx, ControlService_CheckSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest, opts ...grpc.CallOption) (*RequestPasswordResetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestPasswordResetResponse)
+	err := c.cc.Invoke(ctx, ControlService_RequestPasswordReset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest, opts ...grpc.CallOption) (*ConfirmPasswordResetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmPasswordResetResponse)
+	err := c.cc.Invoke(ctx, ControlService_ConfirmPasswordReset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) SendVerificationEmail(ctx context.Context, in *SendVerificationEmailRequest, opts ...grpc.CallOption) (*SendVerificationEmailResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendVerificationEmailResponse)
+	err := c.cc.Invoke(ctx, ControlService_SendVerificationEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*VerifyEmailResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyEmailResponse)
+	err := c.cc.Invoke(ctx, ControlService_VerifyEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) EnrollTOTP(ctx context.Context, in *EnrollTOTPRequest, opts ...grpc.CallOption) (*EnrollTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollTOTPResponse)
+	err := c.cc.Invoke(ctx, ControlService_EnrollTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ConfirmTOTP(ctx context.Context, in *ConfirmTOTPRequest, opts ...grpc.CallOption) (*ConfirmTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmTOTPResponse)
+	err := c.cc.Invoke(ctx, ControlService_ConfirmTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) VerifyLoginChallenge(ctx context.Context, in *VerifyLoginChallengeRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, ControlService_VerifyLoginChallenge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) DisableTOTP(ctx context.Context, in *DisableTOTPRequest, opts ...grpc.CallOption) (*DisableTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableTOTPResponse)
+	err := c.cc.Invoke(ctx, ControlService_DisableTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) RegenerateRecoveryCodes(ctx context.Context, in *RegenerateRecoveryCodesRequest, opts ...grpc.CallOption) (*RegenerateRecoveryCodesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegenerateRecoveryCodesResponse)
+	err := c.cc.Invoke(ctx, ControlService_RegenerateRecoveryCodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlServiceClient) CreateOrUpdateMerchantDetails(ctx context.Context, in *CreateOrUpdateMerchantDetailsRequest, opts ...grpc.CallOption) (*CreateOrUpdateMerchantDetailsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CreateOrUpdateMerchantDetailsResponse)
@@ -254,6 +456,36 @@ func (c *controlServiceClient) CreateOrUpdateDailyPrice(ctx context.Context, in
 	return out, nil
 }
 
+func (c *controlServiceClient) CreateOrUpdateDailyPrices(ctx context.Context, in *CreateOrUpdateDailyPricesRequest, opts ...grpc.CallOption) (*CreateOrUpdateDailyPricesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateOrUpdateDailyPricesResponse)
+	err := c.cc.Invoke(ctx, ControlService_CreateOrUpdateDailyPrices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) SubmitPriceTick(ctx context.Context, in *SubmitPriceTickRequest, opts ...grpc.CallOption) (*SubmitPriceTickResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitPriceTickResponse)
+	err := c.cc.Invoke(ctx, ControlService_SubmitPriceTick_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ReviewPriceTicks(ctx context.Context, in *ReviewPriceTicksRequest, opts ...grpc.CallOption) (*ReviewPriceTicksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewPriceTicksResponse)
+	err := c.cc.Invoke(ctx, ControlService_ReviewPriceTicks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlServiceClient) ListDailyPrices(ctx context.Context, in *ListDailyPricesRequest, opts ...grpc.CallOption) (*ListDailyPricesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListDailyPricesResponse)
@@ -284,6 +516,26 @@ func (c *controlServiceClient) GetTodaysByProductId(ctx context.Context, in *Get
 	return out, nil
 }
 
+func (c *controlServiceClient) ListPriceTicks(ctx context.Context, in *ListPriceTicksRequest, opts ...grpc.CallOption) (*ListPriceTicksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPriceTicksResponse)
+	err := c.cc.Invoke(ctx, ControlService_ListPriceTicks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) GetPriceCandles(ctx context.Context, in *GetPriceCandlesRequest, opts ...grpc.CallOption) (*GetPriceCandlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPriceCandlesResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetPriceCandles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlServiceClient) GetProductsWithGradesAndPrices(ctx context.Context, in *GetProductsWithGradesAndPricesRequest, opts ...grpc.CallOption) (*GetProductsWithGradesAndPricesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetProductsWithGradesAndPricesResponse)
@@ -294,6 +546,25 @@ func (c *controlServiceClient) GetProductsWithGradesAndPrices(ctx context.Contex
 	return out, nil
 }
 
+func (c *controlServiceClient) SubscribePrices(ctx context.Context, in *SubscribePricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PriceEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ControlService_ServiceDesc.Streams[0], ControlService_SubscribePrices_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribePricesRequest, PriceEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ControlService_SubscribePricesClient = grpc.ServerStreamingClient[PriceEvent]
+
 func (c *controlServiceClient) GetSystemMetrics(ctx context.Context, in *GetSystemMetricsRequest, opts ...grpc.CallOption) (*GetSystemMetricsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetSystemMetricsResponse)
@@ -304,6 +575,46 @@ func (c *controlServiceClient) GetSystemMetrics(ctx context.Context, in *GetSyst
 	return out, nil
 }
 
+func (c *controlServiceClient) SetFxRate(ctx context.Context, in *SetFxRateRequest, opts ...grpc.CallOption) (*SetFxRateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetFxRateResponse)
+	err := c.cc.Invoke(ctx, ControlService_SetFxRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ListFxRates(ctx context.Context, in *ListFxRatesRequest, opts ...grpc.CallOption) (*ListFxRatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFxRatesResponse)
+	err := c.cc.Invoke(ctx, ControlService_ListFxRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) SetFeeSchedule(ctx context.Context, in *SetFeeScheduleRequest, opts ...grpc.CallOption) (*SetFeeScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetFeeScheduleResponse)
+	err := c.cc.Invoke(ctx, ControlService_SetFeeSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ListFeeSchedules(ctx context.Context, in *ListFeeSchedulesRequest, opts ...grpc.CallOption) (*ListFeeSchedulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFeeSchedulesResponse)
+	err := c.cc.Invoke(ctx, ControlService_ListFeeSchedules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ControlServiceServer is the server API for ControlService service.
 // All implementations must embed UnimplementedControlServiceServer
 // for forward compatibility.
@@ -316,6 +627,21 @@ type ControlServiceServer interface {
 	Login(context.Context, *LoginRequest) (*LoginResponse, error)
 	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
 	RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error)
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
+	RevokeAllOtherSessions(context.Context, *RevokeAllOtherSessionsRequest) (*RevokeSessionsResponse, error)
+	ForceLogout(context.Context, *ForceLogoutRequest) (*RevokeSessionsResponse, error)
+	GetJWKS(context.Context, *GetJWKSRequest) (*GetJWKSResponse, error)
+	CheckSession(context.Context, *CheckSessionRequest) (*CheckSessionResponse, error)
+	RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error)
+	ConfirmPasswordReset(context.Context, *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error)
+	SendVerificationEmail(context.Context, *SendVerificationEmailRequest) (*SendVerificationEmailResponse, error)
+	VerifyEmail(context.Context, *VerifyEmailRequest) (*VerifyEmailResponse, error)
+	EnrollTOTP(context.Context, *EnrollTOTPRequest) (*EnrollTOTPResponse, error)
+	ConfirmTOTP(context.Context, *ConfirmTOTPRequest) (*ConfirmTOTPResponse, error)
+	VerifyLoginChallenge(context.Context, *VerifyLoginChallengeRequest) (*LoginResponse, error)
+	DisableTOTP(context.Context, *DisableTOTPRequest) (*DisableTOTPResponse, error)
+	RegenerateRecoveryCodes(context.Context, *RegenerateRecoveryCodesRequest) (*RegenerateRecoveryCodesResponse, error)
 	CreateOrUpdateMerchantDetails(context.Context, *CreateOrUpdateMerchantDetailsRequest) (*CreateOrUpdateMerchantDetailsResponse, error)
 	GetMerchantDetails(context.Context, *GetMerchantDetailsRequest) (*GetMerchantDetailsResponse, error)
 	GetMerchantInfo(context.Context, *GetMerchantInfoRequest) (*GetMerchantDetailsResponse, error)
@@ -328,11 +654,23 @@ type ControlServiceServer interface {
 	ListGradesByProductId(context.Context, *ListGradesByProductIdRequest) (*ListGradesByProductIdResponse, error)
 	// Daily Price Management
 	CreateOrUpdateDailyPrice(context.Context, *CreateOrUpdateDailyPriceRequest) (*CreateOrUpdateDailyPriceResponse, error)
+	CreateOrUpdateDailyPrices(context.Context, *CreateOrUpdateDailyPricesRequest) (*CreateOrUpdateDailyPricesResponse, error)
+	SubmitPriceTick(context.Context, *SubmitPriceTickRequest) (*SubmitPriceTickResponse, error)
+	ReviewPriceTicks(context.Context, *ReviewPriceTicksRequest) (*ReviewPriceTicksResponse, error)
 	ListDailyPrices(context.Context, *ListDailyPricesRequest) (*ListDailyPricesResponse, error)
 	GetTodaysPrice(context.Context, *GetTodaysPriceRequest) (*GetTodaysPriceResponse, error)
 	GetTodaysByProductId(context.Context, *GetTodaysByProductIdRequest) (*GetTodaysByProductIdResponse, error)
+	ListPriceTicks(context.Context, *ListPriceTicksRequest) (*ListPriceTicksResponse, error)
+	GetPriceCandles(context.Context, *GetPriceCandlesRequest) (*GetPriceCandlesResponse, error)
 	GetProductsWithGradesAndPrices(context.Context, *GetProductsWithGradesAndPricesRequest) (*GetProductsWithGradesAndPricesResponse, error)
+	SubscribePrices(*SubscribePricesRequest, grpc.ServerStreamingServer[PriceEvent]) error
 	GetSystemMetrics(context.Context, *GetSystemMetricsRequest) (*GetSystemMetricsResponse, error)
+	// FX Rates
+	SetFxRate(context.Context, *SetFxRateRequest) (*SetFxRateResponse, error)
+	ListFxRates(context.Context, *ListFxRatesRequest) (*ListFxRatesResponse, error)
+	// Fee Schedules
+	SetFeeSchedule(context.Context, *SetFeeScheduleRequest) (*SetFeeScheduleResponse, error)
+	ListFeeSchedules(context.Context, *ListFeeSchedulesRequest) (*ListFeeSchedulesResponse, error)
 	mustEmbedUnimplementedControlServiceServer()
 }
 
@@ -367,6 +705,51 @@ func (UnimplementedControlServiceServer) Logout(context.Context, *LogoutRequest)
 func (UnimplementedControlServiceServer) RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RefreshToken not implemented")
 }
+func (UnimplementedControlServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedControlServiceServer) RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedControlServiceServer) RevokeAllOtherSessions(context.Context, *RevokeAllOtherSessionsRequest) (*RevokeSessionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeAllOtherSessions not implemented")
+}
+func (UnimplementedControlServiceServer) ForceLogout(context.Context, *ForceLogoutRequest) (*RevokeSessionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ForceLogout not implemented")
+}
+func (UnimplementedControlServiceServer) GetJWKS(context.Context, *GetJWKSRequest) (*GetJWKSResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetJWKS not implemented")
+}
+func (UnimplementedControlServiceServer) CheckSession(context.Context, *CheckSessionRequest) (*CheckSessionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CheckSession not implemented")
+}
+func (UnimplementedControlServiceServer) RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RequestPasswordReset not implemented")
+}
+func (UnimplementedControlServiceServer) ConfirmPasswordReset(context.Context, *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ConfirmPasswordReset not implemented")
+}
+func (UnimplementedControlServiceServer) SendVerificationEmail(context.Context, *SendVerificationEmailRequest) (*SendVerificationEmailResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SendVerificationEmail not implemented")
+}
+func (UnimplementedControlServiceServer) VerifyEmail(context.Context, *VerifyEmailRequest) (*VerifyEmailResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method VerifyEmail not implemented")
+}
+func (UnimplementedControlServiceServer) EnrollTOTP(context.Context, *EnrollTOTPRequest) (*EnrollTOTPResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EnrollTOTP not implemented")
+}
+func (UnimplementedControlServiceServer) ConfirmTOTP(context.Context, *ConfirmTOTPRequest) (*ConfirmTOTPResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ConfirmTOTP not implemented")
+}
+func (UnimplementedControlServiceServer) VerifyLoginChallenge(context.Context, *VerifyLoginChallengeRequest) (*LoginResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method VerifyLoginChallenge not implemented")
+}
+func (UnimplementedControlServiceServer) DisableTOTP(context.Context, *DisableTOTPRequest) (*DisableTOTPResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DisableTOTP not implemented")
+}
+func (UnimplementedControlServiceServer) RegenerateRecoveryCodes(context.Context, *RegenerateRecoveryCodesRequest) (*RegenerateRecoveryCodesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RegenerateRecoveryCodes not implemented")
+}
 func (UnimplementedControlServiceServer) CreateOrUpdateMerchantDetails(context.Context, *CreateOrUpdateMerchantDetailsRequest) (*CreateOrUpdateMerchantDetailsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateOrUpdateMerchantDetails not implemented")
 }
@@ -394,6 +777,15 @@ func (UnimplementedControlServiceServer) ListGradesByProductId(context.Context,
 func (UnimplementedControlServiceServer) CreateOrUpdateDailyPrice(context.Context, *CreateOrUpdateDailyPriceRequest) (*CreateOrUpdateDailyPriceResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateOrUpdateDailyPrice not implemented")
 }
+func (UnimplementedControlServiceServer) CreateOrUpdateDailyPrices(context.Context, *CreateOrUpdateDailyPricesRequest) (*CreateOrUpdateDailyPricesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateOrUpdateDailyPrices not implemented")
+}
+func (UnimplementedControlServiceServer) SubmitPriceTick(context.Context, *SubmitPriceTickRequest) (*SubmitPriceTickResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SubmitPriceTick not implemented")
+}
+func (UnimplementedControlServiceServer) ReviewPriceTicks(context.Context, *ReviewPriceTicksRequest) (*ReviewPriceTicksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReviewPriceTicks not implemented")
+}
 func (UnimplementedControlServiceServer) ListDailyPrices(context.Context, *ListDailyPricesRequest) (*ListDailyPricesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListDailyPrices not implemented")
 }
@@ -403,12 +795,33 @@ func (UnimplementedControlServiceServer) GetTodaysPrice(context.Context, *GetTod
 func (UnimplementedControlServiceServer) GetTodaysByProductId(context.Context, *GetTodaysByProductIdRequest) (*GetTodaysByProductIdResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetTodaysByProductId not implemented")
 }
+func (UnimplementedControlServiceServer) ListPriceTicks(context.Context, *ListPriceTicksRequest) (*ListPriceTicksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPriceTicks not implemented")
+}
+func (UnimplementedControlServiceServer) GetPriceCandles(context.Context, *GetPriceCandlesRequest) (*GetPriceCandlesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPriceCandles not implemented")
+}
 func (UnimplementedControlServiceServer) GetProductsWithGradesAndPrices(context.Context, *GetProductsWithGradesAndPricesRequest) (*GetProductsWithGradesAndPricesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetProductsWithGradesAndPrices not implemented")
 }
+func (UnimplementedControlServiceServer) SubscribePrices(*SubscribePricesRequest, grpc.ServerStreamingServer[PriceEvent]) error {
+	return status.Error(codes.Unimplemented, "method SubscribePrices not implemented")
+}
 func (UnimplementedControlServiceServer) GetSystemMetrics(context.Context, *GetSystemMetricsRequest) (*GetSystemMetricsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetSystemMetrics not implemented")
 }
+func (UnimplementedControlServiceServer) SetFxRate(context.Context, *SetFxRateRequest) (*SetFxRateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetFxRate not implemented")
+}
+func (UnimplementedControlServiceServer) ListFxRates(context.Context, *ListFxRatesRequest) (*ListFxRatesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListFxRates not implemented")
+}
+func (UnimplementedControlServiceServer) SetFeeSchedule(context.Context, *SetFeeScheduleRequest) (*SetFeeScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetFeeSchedule not implemented")
+}
+func (UnimplementedControlServiceServer) ListFeeSchedules(context.Context, *ListFeeSchedulesRequest) (*ListFeeSchedulesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListFeeSchedules not implemented")
+}
 func (UnimplementedControlServiceServer) mustEmbedUnimplementedControlServiceServer() {}
 func (UnimplementedControlServiceServer) testEmbeddedByValue()                        {}
 
@@ -574,187 +987,511 @@ func _ControlService_RefreshToken_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_CreateOrUpdateMerchantDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateOrUpdateMerchantDetailsRequest)
+func _ControlService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).CreateOrUpdateMerchantDetails(ctx, in)
+		return srv.(ControlServiceServer).ListSessions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_CreateOrUpdateMerchantDetails_FullMethodName,
+		FullMethod: ControlService_ListSessions_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).CreateOrUpdateMerchantDetails(ctx, req.(*CreateOrUpdateMerchantDetailsRequest))
+		return srv.(ControlServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_GetMerchantDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetMerchantDetailsRequest)
+func _ControlService_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).GetMerchantDetails(ctx, in)
+		return srv.(ControlServiceServer).RevokeSession(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_GetMerchantDetails_FullMethodName,
+		FullMethod: ControlService_RevokeSession_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).GetMerchantDetails(ctx, req.(*GetMerchantDetailsRequest))
+		return srv.(ControlServiceServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_GetMerchantInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetMerchantInfoRequest)
+func _ControlService_RevokeAllOtherSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAllOtherSessionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).GetMerchantInfo(ctx, in)
+		return srv.(ControlServiceServer).RevokeAllOtherSessions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_GetMerchantInfo_FullMethodName,
+		FullMethod: ControlService_RevokeAllOtherSessions_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).GetMerchantInfo(ctx, req.(*GetMerchantInfoRequest))
+		return srv.(ControlServiceServer).RevokeAllOtherSessions(ctx, req.(*RevokeAllOtherSessionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_CreateOrUpdateMerchantInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateOrUpdateMerchantInfoRequest)
+func _ControlService_ForceLogout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceLogoutRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).CreateOrUpdateMerchantInfo(ctx, in)
+		return srv.(ControlServiceServer).ForceLogout(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_CreateOrUpdateMerchantInfo_FullMethodName,
+		FullMethod: ControlService_ForceLogout_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).CreateOrUpdateMerchantInfo(ctx, req.(*CreateOrUpdateMerchantInfoRequest))
+		return srv.(ControlServiceServer).ForceLogout(ctx, req.(*ForceLogoutRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_CreateOrUpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateOrUpdateProductRequest)
+func _ControlService_GetJWKS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJWKSRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).CreateOrUpdateProduct(ctx, in)
+		return srv.(ControlServiceServer).GetJWKS(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_CreateOrUpdateProduct_FullMethodName,
+		FullMethod: ControlService_GetJWKS_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).CreateOrUpdateProduct(ctx, req.(*CreateOrUpdateProductRequest))
+		return srv.(ControlServiceServer).GetJWKS(ctx, req.(*GetJWKSRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListProductsRequest)
+func _ControlService_CheckSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckSessionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).ListProducts(ctx, in)
+		return srv.(ControlServiceServer).CheckSession(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_ListProducts_FullMethodName,
+		FullMethod: ControlService_CheckSession_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).ListProducts(ctx, req.(*ListProductsRequest))
+		return srv.(ControlServiceServer).CheckSession(ctx, req.(*CheckSessionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_CreateOrUpdateGrade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateOrUpdateGradeRequest)
+func _ControlService_RequestPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestPasswordResetRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).CreateOrUpdateGrade(ctx, in)
+		return srv.(ControlServiceServer).RequestPasswordReset(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_CreateOrUpdateGrade_FullMethodName,
+		FullMethod: ControlService_RequestPasswordReset_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).CreateOrUpdateGrade(ctx, req.(*CreateOrUpdateGradeRequest))
+		return srv.(ControlServiceServer).RequestPasswordReset(ctx, req.(*RequestPasswordResetRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_ListGradesByProductId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListGradesByProductIdRequest)
+func _ControlService_ConfirmPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmPasswordResetRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).ListGradesByProductId(ctx, in)
+		return srv.(ControlServiceServer).ConfirmPasswordReset(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_ListGradesByProductId_FullMethodName,
+		FullMethod: ControlService_ConfirmPasswordReset_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).ListGradesByProductId(ctx, req.(*ListGradesByProductIdRequest))
+		return srv.(ControlServiceServer).ConfirmPasswordReset(ctx, req.(*ConfirmPasswordResetRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_CreateOrUpdateDailyPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateOrUpdateDailyPriceRequest)
+func _ControlService_SendVerificationEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendVerificationEmailRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).CreateOrUpdateDailyPrice(ctx, in)
+		return srv.(ControlServiceServer).SendVerificationEmail(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_CreateOrUpdateDailyPrice_FullMethodName,
+		FullMethod: ControlService_SendVerificationEmail_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).CreateOrUpdateDailyPrice(ctx, req.(*CreateOrUpdateDailyPriceRequest))
+		return srv.(ControlServiceServer).SendVerificationEmail(ctx, req.(*SendVerificationEmailRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_ListDailyPrices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListDailyPricesRequest)
+func _ControlService_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEmailRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).ListDailyPrices(ctx, in)
+		return srv.(ControlServiceServer).VerifyEmail(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_ListDailyPrices_FullMethodName,
+		FullMethod: ControlService_VerifyEmail_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).ListDailyPrices(ctx, req.(*ListDailyPricesRequest))
+		return srv.(ControlServiceServer).VerifyEmail(ctx, req.(*VerifyEmailRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_GetTodaysPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _ControlService_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_EnrollTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).EnrollTOTP(ctx, req.(*EnrollTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ConfirmTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ConfirmTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ConfirmTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ConfirmTOTP(ctx, req.(*ConfirmTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_VerifyLoginChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyLoginChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).VerifyLoginChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_VerifyLoginChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).VerifyLoginChallenge(ctx, req.(*VerifyLoginChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_DisableTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).DisableTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_DisableTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).DisableTOTP(ctx, req.(*DisableTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_RegenerateRecoveryCodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegenerateRecoveryCodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).RegenerateRecoveryCodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_RegenerateRecoveryCodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).RegenerateRecoveryCodes(ctx, req.(*RegenerateRecoveryCodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateOrUpdateMerchantDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrUpdateMerchantDetailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateOrUpdateMerchantDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_CreateOrUpdateMerchantDetails_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateOrUpdateMerchantDetails(ctx, req.(*CreateOrUpdateMerchantDetailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetMerchantDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMerchantDetailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetMerchantDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetMerchantDetails_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetMerchantDetails(ctx, req.(*GetMerchantDetailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetMerchantInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMerchantInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetMerchantInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetMerchantInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetMerchantInfo(ctx, req.(*GetMerchantInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateOrUpdateMerchantInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrUpdateMerchantInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateOrUpdateMerchantInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_CreateOrUpdateMerchantInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateOrUpdateMerchantInfo(ctx, req.(*CreateOrUpdateMerchantInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateOrUpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrUpdateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateOrUpdateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_CreateOrUpdateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateOrUpdateProduct(ctx, req.(*CreateOrUpdateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ListProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListProducts(ctx, req.(*ListProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateOrUpdateGrade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrUpdateGradeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateOrUpdateGrade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_CreateOrUpdateGrade_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateOrUpdateGrade(ctx, req.(*CreateOrUpdateGradeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ListGradesByProductId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGradesByProductIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListGradesByProductId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ListGradesByProductId_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListGradesByProductId(ctx, req.(*ListGradesByProductIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateOrUpdateDailyPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrUpdateDailyPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateOrUpdateDailyPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_CreateOrUpdateDailyPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateOrUpdateDailyPrice(ctx, req.(*CreateOrUpdateDailyPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateOrUpdateDailyPrices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrUpdateDailyPricesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateOrUpdateDailyPrices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_CreateOrUpdateDailyPrices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateOrUpdateDailyPrices(ctx, req.(*CreateOrUpdateDailyPricesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_SubmitPriceTick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitPriceTickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).SubmitPriceTick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_SubmitPriceTick_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).SubmitPriceTick(ctx, req.(*SubmitPriceTickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ReviewPriceTicks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewPriceTicksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ReviewPriceTicks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ReviewPriceTicks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ReviewPriceTicks(ctx, req.(*ReviewPriceTicksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ListDailyPrices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDailyPricesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListDailyPrices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ListDailyPrices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListDailyPrices(ctx, req.(*ListDailyPricesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetTodaysPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetTodaysPriceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
@@ -790,6 +1527,42 @@ func _ControlService_GetTodaysByProductId_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ControlService_ListPriceTicks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPriceTicksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListPriceTicks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ListPriceTicks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListPriceTicks(ctx, req.(*ListPriceTicksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetPriceCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPriceCandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetPriceCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetPriceCandles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetPriceCandles(ctx, req.(*GetPriceCandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ControlService_GetProductsWithGradesAndPrices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetProductsWithGradesAndPricesRequest)
 	if err := dec(in); err != nil {
@@ -808,6 +1581,17 @@ func _ControlService_GetProductsWithGradesAndPrices_Handler(srv interface{}, ctx
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ControlService_SubscribePrices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribePricesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServiceServer).SubscribePrices(m, &grpc.GenericServerStream[SubscribePricesRequest, PriceEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ControlService_SubscribePricesServer = grpc.ServerStreamingServer[PriceEvent]
+
 func _ControlService_GetSystemMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetSystemMetricsRequest)
 	if err := dec(in); err != nil {
@@ -826,6 +1610,78 @@ func _ControlService_GetSystemMetrics_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ControlService_SetFxRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFxRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).SetFxRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_SetFxRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).SetFxRate(ctx, req.(*SetFxRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ListFxRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFxRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListFxRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ListFxRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListFxRates(ctx, req.(*ListFxRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_SetFeeSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFeeScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).SetFeeSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_SetFeeSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).SetFeeSchedule(ctx, req.(*SetFeeScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ListFeeSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFeeSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListFeeSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ListFeeSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListFeeSchedules(ctx, req.(*ListFeeSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ControlService_ServiceDesc is the grpc.ServiceDesc for ControlService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -865,6 +1721,66 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RefreshToken",
 			Handler:    _ControlService_RefreshToken_Handler,
 		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _ControlService_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _ControlService_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeAllOtherSessions",
+			Handler:    _ControlService_RevokeAllOtherSessions_Handler,
+		},
+		{
+			MethodName: "ForceLogout",
+			Handler:    _ControlService_ForceLogout_Handler,
+		},
+		{
+			MethodName: "GetJWKS",
+			Handler:    _ControlService_GetJWKS_Handler,
+		},
+		{
+			MethodName: "CheckSession",
+			Handler:    _ControlService_CheckSession_Handler,
+		},
+		{
+			MethodName: "RequestPasswordReset",
+			Handler:    _ControlService_RequestPasswordReset_Handler,
+		},
+		{
+			MethodName: "ConfirmPasswordReset",
+			Handler:    _ControlService_ConfirmPasswordReset_Handler,
+		},
+		{
+			MethodName: "SendVerificationEmail",
+			Handler:    _ControlService_SendVerificationEmail_Handler,
+		},
+		{
+			MethodName: "VerifyEmail",
+			Handler:    _ControlService_VerifyEmail_Handler,
+		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _ControlService_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "ConfirmTOTP",
+			Handler:    _ControlService_ConfirmTOTP_Handler,
+		},
+		{
+			MethodName: "VerifyLoginChallenge",
+			Handler:    _ControlService_VerifyLoginChallenge_Handler,
+		},
+		{
+			MethodName: "DisableTOTP",
+			Handler:    _ControlService_DisableTOTP_Handler,
+		},
+		{
+			MethodName: "RegenerateRecoveryCodes",
+			Handler:    _ControlService_RegenerateRecoveryCodes_Handler,
+		},
 		{
 			MethodName: "CreateOrUpdateMerchantDetails",
 			Handler:    _ControlService_CreateOrUpdateMerchantDetails_Handler,
@@ -901,6 +1817,18 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateOrUpdateDailyPrice",
 			Handler:    _ControlService_CreateOrUpdateDailyPrice_Handler,
 		},
+		{
+			MethodName: "CreateOrUpdateDailyPrices",
+			Handler:    _ControlService_CreateOrUpdateDailyPrices_Handler,
+		},
+		{
+			MethodName: "SubmitPriceTick",
+			Handler:    _ControlService_SubmitPriceTick_Handler,
+		},
+		{
+			MethodName: "ReviewPriceTicks",
+			Handler:    _ControlService_ReviewPriceTicks_Handler,
+		},
 		{
 			MethodName: "ListDailyPrices",
 			Handler:    _ControlService_ListDailyPrices_Handler,
@@ -913,6 +1841,14 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTodaysByProductId",
 			Handler:    _ControlService_GetTodaysByProductId_Handler,
 		},
+		{
+			MethodName: "ListPriceTicks",
+			Handler:    _ControlService_ListPriceTicks_Handler,
+		},
+		{
+			MethodName: "GetPriceCandles",
+			Handler:    _ControlService_GetPriceCandles_Handler,
+		},
 		{
 			MethodName: "GetProductsWithGradesAndPrices",
 			Handler:    _ControlService_GetProductsWithGradesAndPrices_Handler,
@@ -921,7 +1857,29 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSystemMetrics",
 			Handler:    _ControlService_GetSystemMetrics_Handler,
 		},
+		{
+			MethodName: "SetFxRate",
+			Handler:    _ControlService_SetFxRate_Handler,
+		},
+		{
+			MethodName: "ListFxRates",
+			Handler:    _ControlService_ListFxRates_Handler,
+		},
+		{
+			MethodName: "SetFeeSchedule",
+			Handler:    _ControlService_SetFeeSchedule_Handler,
+		},
+		{
+			MethodName: "ListFeeSchedules",
+			Handler:    _ControlService_ListFeeSchedules_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribePrices",
+			Handler:       _ControlService_SubscribePrices_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "control.proto",
 }
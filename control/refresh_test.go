@@ -0,0 +1,134 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+	"github.com/shopspring/decimal"
+)
+
+func newTestAccountService(t *testing.T) (*AccountService, *fakeRepository) {
+	t.Helper()
+	ring, err := NewKeyRing(t.TempDir(), util.AlgorithmEdDSA, 24*time.Hour, 48*time.Hour, util.NewLogger("error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newFakeRepository()
+	repo.accounts["acc-1"] = &Account{ID: "acc-1", UserType: util.UserTypeMerchant, Email: "m@example.com"}
+	service := NewAccountService(repo, ring, 15*time.Minute, time.Hour, nil, decimal.Zero, nil, "", 0, 0, TwoFactorPolicy{}, util.NewLogger("error"))
+	return service, repo
+}
+
+// deviceState reports whether any of the device's sessions or refresh tokens is still live,
+// and whether every token was revoked for reuse.
+func deviceState(repo *fakeRepository, deviceID string) (live bool, revokedForReuse bool) {
+	revokedForReuse = true
+	for _, s := range repo.sessions {
+		if s.DeviceID == deviceID && !s.IsRevoked {
+			live = true
+		}
+	}
+	for _, t := range repo.tokens {
+		if t.DeviceID != deviceID {
+			continue
+		}
+		if t.RevokedAt.IsZero() {
+			live = true
+		}
+		if t.RevokeReason != RevokeReuse {
+			revokedForReuse = false
+		}
+	}
+	return live, revokedForReuse
+}
+
+func TestRefreshTokenReuse(t *testing.T) {
+	tests := []struct {
+		name        string
+		present     string // "current", "rotated" or "access"
+		device      string
+		race        bool // another refresh rotates the token first
+		wantErr     error
+		wantErrText string
+		wantRevoked bool // dev-1 signed out for reuse
+	}{
+		{name: "current token rotates", present: "current", device: "dev-1"},
+		{name: "rotated token signs the device out", present: "rotated", device: "dev-1", wantErr: ErrRefreshTokenReused, wantRevoked: true},
+		{name: "rotated token from another device signs out the token's device", present: "rotated", device: "dev-3", wantErr: ErrRefreshTokenReused, wantRevoked: true},
+		{name: "token rotated by a concurrent refresh", present: "current", device: "dev-1", race: true, wantErr: ErrRefreshTokenReused, wantRevoked: true},
+		{name: "current token on another device", present: "current", device: "dev-2", wantErrText: "device mismatch"},
+		{name: "access token is not a refresh token", present: "access", device: "dev-1", wantErrText: "invalid or expired refresh token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, repo := newTestAccountService(t)
+			account := repo.accounts["acc-1"]
+			first, err := service.createSession(ctx, account, "dev-1", SessionClient{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := service.createSession(ctx, account, "dev-2", SessionClient{}); err != nil {
+				t.Fatal(err)
+			}
+			second, err := service.RefreshToken(ctx, first.RefreshToken, "dev-1", SessionClient{})
+			if err != nil {
+				t.Fatalf("first rotation: %v", err)
+			}
+
+			presented := map[string]string{
+				"current": second.RefreshToken,
+				"rotated": first.RefreshToken,
+				"access":  second.AccessToken,
+			}[tt.present]
+			if tt.race {
+				repo.beforeRotate = func(token *RefreshToken) { token.RotatedAt = time.Now() }
+			}
+
+			third, err := service.RefreshToken(ctx, presented, tt.device, SessionClient{})
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantErrText != "":
+				if err == nil || err.Error() != tt.wantErrText {
+					t.Fatalf("err = %v, want %q", err, tt.wantErrText)
+				}
+			case err != nil:
+				t.Fatal(err)
+			default:
+				current, _ := repo.GetRefreshTokenByHash(ctx, util.HashToken(second.RefreshToken))
+				next, err := repo.GetRefreshTokenByHash(ctx, util.HashToken(third.RefreshToken))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if current.RotatedAt.IsZero() || next.ParentID != current.ID || next.FamilyID != current.FamilyID {
+					t.Errorf("rotation did not chain: %+v -> %+v", current, next)
+				}
+				if session, _ := repo.GetSessionByFamily(ctx, current.FamilyID); session.AccessToken != third.AccessToken {
+					t.Error("session keeps the old access token")
+				}
+			}
+
+			live, forReuse := deviceState(repo, "dev-1")
+			if tt.wantRevoked && (live || !forReuse) {
+				t.Errorf("dev-1 not signed out for reuse: live %v, reuse reason %v", live, forReuse)
+			}
+			if !tt.wantRevoked && !live {
+				t.Error("dev-1 signed out")
+			}
+			if live, _ := deviceState(repo, "dev-2"); !live {
+				t.Error("dev-2 signed out by dev-1's reuse")
+			}
+			if tt.wantRevoked {
+				if _, err := service.RefreshToken(ctx, second.RefreshToken, "dev-1", SessionClient{}); err == nil {
+					t.Error("newest token still refreshes after reuse")
+				}
+			}
+		})
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+	"github.com/shopspring/decimal"
 )
 
 type Repository interface {
@@ -16,13 +19,45 @@ type Repository interface {
 	GetAccountById(ctx context.Context, id string) (*Account, error)
 	GetAccountByEmail(ctx context.Context, email string) (*Account, error)
 	ListAccounts(ctx context.Context, skip uint, take uint) ([]*Account, error)
+	UpdatePassword(ctx context.Context, accountID string, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, accountID string, email string, at time.Time) (bool, error)
+
+	// Account tokens (password reset, email verification)
+	CreateAccountToken(ctx context.Context, token *AccountToken) error
+	GetAccountTokenByHash(ctx context.Context, tokenHash string) (*AccountToken, error)
+	UseAccountToken(ctx context.Context, id string, at time.Time) (bool, error)
+	ExpireAccountTokens(ctx context.Context, accountID string, purpose string, at time.Time) error
+
+	// Two-factor authentication
+	GetTOTP(ctx context.Context, accountID string) (*TOTPEnrollment, error)
+	UpsertTOTP(ctx context.Context, enrollment *TOTPEnrollment) (bool, error)
+	ConfirmTOTP(ctx context.Context, accountID string, step int64, at time.Time) error
+	UseTOTPStep(ctx context.Context, accountID string, step int64) (bool, error)
+	DeleteTOTP(ctx context.Context, accountID string) error
+	ReplaceRecoveryCodes(ctx context.Context, accountID string, codes []*RecoveryCode) error
+	UseRecoveryCode(ctx context.Context, accountID string, codeHash string, at time.Time) (bool, error)
+	CreateLoginChallenge(ctx context.Context, challenge *LoginChallenge) error
+	GetLoginChallengeByHash(ctx context.Context, tokenHash string) (*LoginChallenge, error)
+	RecordChallengeAttempt(ctx context.Context, id string, maxAttempts int, at time.Time) error
+	UseLoginChallenge(ctx context.Context, id string, at time.Time) (bool, error)
 
 	// Session Management
 	CreateOrUpdateSession(ctx context.Context, session *Session) error
 	GetSession(ctx context.Context, id string) (*Session, error)
-	GetSessionByRefreshToken(ctx context.Context, refreshToken string) (*Session, error)
+	GetSessionByFamily(ctx context.Context, familyID string) (*Session, error)
 	GetSessionByAccessToken(ctx context.Context, accessToken string) (*Session, error)
 	RevokeSessionByAccessToken(ctx context.Context, accessToken string) error
+	RevokeDeviceSessions(ctx context.Context, accountID string, deviceID string) error
+	ListSessions(ctx context.Context, accountID string, now time.Time) ([]*Session, error)
+	TouchSession(ctx context.Context, id string, ipAddress string, userAgent string, at time.Time) error
+	RevokeSessions(ctx context.Context, accountID string, sessionID string, exceptSessionID string, reason string, at time.Time) (uint32, error)
+
+	// Refresh tokens
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, id string, at time.Time) (bool, error)
+	RevokeRefreshFamily(ctx context.Context, familyID string, reason string, at time.Time) error
+	RevokeDeviceRefreshTokens(ctx context.Context, accountID string, deviceID string, reason string, at time.Time) error
 
 	// Merchant Details
 	CreateOrUpdateMerchantDetails(ctx context.Context, merchantDetails *MerchantDetails) (*MerchantDetails, error)
@@ -35,14 +70,32 @@ type Repository interface {
 
 	// Grades
 	CreateOrUpdateGrade(ctx context.Context, grade *Grade) (*Grade, error)
+	GetGradeById(ctx context.Context, id string) (*Grade, error)
 	ListGradesByProductId(ctx context.Context, productId string, skip uint, take uint) ([]*Grade, error)
 
 	// Daily Price
-	CreateOrUpdateDailyPrice(ctx context.Context, dailyPrice *DailyPrice) (*DailyPrice, error)
+	InsertPriceTick(ctx context.Context, tick *PriceTick) error
+	GetPriceTick(ctx context.Context, id string) (*PriceTick, error)
+	UpdatePriceTickStatus(ctx context.Context, tick *PriceTick, from string) (bool, error)
+	RollupDailyPrice(ctx context.Context, rollupID string, gradeId string, date time.Time) (*DailyPrice, error)
+	ListPriceTicks(ctx context.Context, gradeId string, date time.Time, status string) ([]*PriceTick, error)
+	GetLatestPriceTick(ctx context.Context, gradeId string, at time.Time) (*PriceTick, error)
 	GetTodaysByProductId(ctx context.Context, productId string, date time.Time) ([]*DailyPrice, error)
 	ListDailyPricesByGradeId(ctx context.Context, gradeId string, date time.Time, duration int) ([]*DailyPrice, error)
 	GetTodaysByGradeId(ctx context.Context, gradeId string, date time.Time) ([]*DailyPrice, error)
+	ListDailyPricesInRange(ctx context.Context, gradeId string, productId string, from time.Time, to time.Time) ([]*DailyPrice, error)
 	GetCounts(ctx context.Context) (uint32, uint32, error)
+
+	// FX Rates
+	UpsertFxRate(ctx context.Context, rate *FxRate) (*FxRate, error)
+	ListFxRates(ctx context.Context, baseCurrency string, quoteCurrency string) ([]*FxRate, error)
+
+	// Fee schedules
+	UpsertFeeSchedule(ctx context.Context, schedule *FeeSchedule) (*FeeSchedule, error)
+	ListFeeSchedules(ctx context.Context, gradeID string, category string) ([]*FeeSchedule, error)
+
+	// Transactions
+	BeginTx(ctx context.Context) (context.Context, *sql.Tx, error)
 }
 
 type MysqlRepository struct {
@@ -50,36 +103,6 @@ type MysqlRepository struct {
 	logger util.Logger
 }
 
-func (repository *MysqlRepository) GetTodaysByProductId(ctx context.Context, productId string, date time.Time) ([]*DailyPrice, error) {
-	start := time.Now()
-	query := "SELECT id, product_id, grade_id, price, date, time FROM daily_price WHERE product_id = ? AND date = ? ORDER BY time DESC"
-
-	rows, err := repository.db.QueryContext(ctx, query, productId, date.Format("2006-01-02"))
-
-	repository.logger.Database().Debug().
-		Str("query", query).
-		Str("duration", time.Since(start).String()).
-		Bool("success", err == nil).
-		Msg("Query Rows")
-
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	dailyPrices := []*DailyPrice{}
-	for rows.Next() {
-		dailyPrice := &DailyPrice{}
-		var timeBytes []byte
-		if err := rows.Scan(&dailyPrice.ID, &dailyPrice.ProductID, &dailyPrice.GradeID, &dailyPrice.Price, &dailyPrice.Date, &timeBytes); err != nil {
-			return nil, err
-		}
-		dailyPrice.Time, _ = time.Parse("15:04:05", string(timeBytes))
-		dailyPrices = append(dailyPrices, dailyPrice)
-	}
-	return dailyPrices, nil
-}
-
 func NewMysqlRepository(url string, logger util.Logger) (Repository, error) {
 	db, err := sql.Open("mysql", url)
 	if err != nil {
@@ -99,6 +122,30 @@ func (repository *MysqlRepository) Close() {
 	repository.db.Close()
 }
 
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+func (repository *MysqlRepository) dbFromContext(ctx context.Context) execer {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok && tx != nil {
+		return tx
+	}
+	return repository.db
+}
+
+func (repository *MysqlRepository) BeginTx(ctx context.Context) (context.Context, *sql.Tx, error) {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return context.WithValue(ctx, txKey{}, tx), tx, nil
+}
+
 func (repository *MysqlRepository) GetCounts(ctx context.Context) (uint32, uint32, error) {
 	var userCount, productCount uint32
 	err := repository.db.QueryRowContext(ctx, "SELECT (SELECT COUNT(*) FROM accounts), (SELECT COUNT(*) FROM products)").Scan(&userCount, &productCount)
@@ -127,11 +174,11 @@ func (repository *MysqlRepository) CheckEmailExists(ctx context.Context, email s
 
 func (repository *MysqlRepository) CreateOrUpdateAccount(ctx context.Context, account *Account) (*Account, error) {
 	start := time.Now()
-	query := "INSERT INTO accounts (id, name, user_type, email, password) VALUES (?, NULLIF(?,''), ?, ?, ?) ON DUPLICATE KEY UPDATE name = NULLIF(?,''), user_type = ?, email = ?, password = IF(VALUES(password) = '', password, VALUES(password))"
+	query := "INSERT INTO accounts (id, name, user_type, email, password, currency, reporting_currency) VALUES (?, NULLIF(?,''), ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE name = NULLIF(?,''), user_type = ?, email_verified_at = IF(email = VALUES(email), email_verified_at, NULL), email = ?, password = IF(VALUES(password) = '', password, VALUES(password)), currency = ?, reporting_currency = ?"
 
 	_, err := repository.db.ExecContext(ctx, query,
-		account.ID, account.Name, account.UserType, account.Email, account.Password,
-		account.Name, account.UserType, account.Email,
+		account.ID, account.Name, account.UserType, account.Email, account.Password, account.Currency, account.ReportingCurrency,
+		account.Name, account.UserType, account.Email, account.Currency, account.ReportingCurrency,
 	)
 
 	repository.logger.Database().Info().
@@ -148,12 +195,13 @@ func (repository *MysqlRepository) CreateOrUpdateAccount(ctx context.Context, ac
 
 func (repository *MysqlRepository) GetAccountById(ctx context.Context, id string) (*Account, error) {
 	start := time.Now()
-	query := "SELECT id, name, user_type, email FROM accounts WHERE id = ?"
+	query := "SELECT id, name, user_type, email, email_verified_at, currency, reporting_currency FROM accounts WHERE id = ?"
 
 	row := repository.db.QueryRowContext(ctx, query, id)
 	account := &Account{}
 	var name sql.NullString
-	err := row.Scan(&account.ID, &name, &account.UserType, &account.Email)
+	var verifiedAt sql.NullTime
+	err := row.Scan(&account.ID, &name, &account.UserType, &account.Email, &verifiedAt, &account.Currency, &account.ReportingCurrency)
 
 	repository.logger.Database().Debug().
 		Str("query", query).
@@ -165,17 +213,19 @@ func (repository *MysqlRepository) GetAccountById(ctx context.Context, id string
 		return nil, err
 	}
 	account.Name = name.String
+	account.EmailVerifiedAt = verifiedAt.Time
 	return account, nil
 }
 
 func (repository *MysqlRepository) GetAccountByEmail(ctx context.Context, email string) (*Account, error) {
 	start := time.Now()
-	query := "SELECT id, name, user_type, email, password FROM accounts WHERE email = ?"
+	query := "SELECT id, name, user_type, email, email_verified_at, password, currency, reporting_currency FROM accounts WHERE email = ?"
 
 	row := repository.db.QueryRowContext(ctx, query, email)
 	account := &Account{}
 	var name sql.NullString
-	err := row.Scan(&account.ID, &name, &account.UserType, &account.Email, &account.Password)
+	var verifiedAt sql.NullTime
+	err := row.Scan(&account.ID, &name, &account.UserType, &account.Email, &verifiedAt, &account.Password, &account.Currency, &account.ReportingCurrency)
 
 	repository.logger.Database().Info().
 		Str("query", query+" ("+email+")").
@@ -187,12 +237,13 @@ func (repository *MysqlRepository) GetAccountByEmail(ctx context.Context, email
 		return nil, err
 	}
 	account.Name = name.String
+	account.EmailVerifiedAt = verifiedAt.Time
 	return account, nil
 }
 
 func (repository *MysqlRepository) ListAccounts(ctx context.Context, skip uint, take uint) ([]*Account, error) {
 	start := time.Now()
-	query := "SELECT id, name, user_type, email FROM accounts ORDER by id DESC LIMIT ? OFFSET ?"
+	query := "SELECT id, name, user_type, email, email_verified_at, currency, reporting_currency FROM accounts ORDER by id DESC LIMIT ? OFFSET ?"
 
 	rows, err := repository.db.QueryContext(ctx, query, take, skip)
 
@@ -203,47 +254,671 @@ func (repository *MysqlRepository) ListAccounts(ctx context.Context, skip uint,
 		Msg("DB")
 
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	accounts := []*Account{}
-	for rows.Next() {
-		account := &Account{}
-		var name sql.NullString
-		if err := rows.Scan(&account.ID, &name, &account.UserType, &account.Email); err != nil {
-			return nil, err
-		}
-		account.Name = name.String
-		accounts = append(accounts, account)
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []*Account{}
+	for rows.Next() {
+		account := &Account{}
+		var name sql.NullString
+		var verifiedAt sql.NullTime
+		if err := rows.Scan(&account.ID, &name, &account.UserType, &account.Email, &verifiedAt, &account.Currency, &account.ReportingCurrency); err != nil {
+			return nil, err
+		}
+		account.Name = name.String
+		account.EmailVerifiedAt = verifiedAt.Time
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// UpdatePassword stores a new password hash.
+func (repository *MysqlRepository) UpdatePassword(ctx context.Context, accountID string, passwordHash string) error {
+	start := time.Now()
+	query := "UPDATE accounts SET password = ? WHERE id = ?"
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, passwordHash, accountID)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+// MarkEmailVerified records that the account owns email. It reports false when the account's
+// email is no longer that address.
+func (repository *MysqlRepository) MarkEmailVerified(ctx context.Context, accountID string, email string, at time.Time) (bool, error) {
+	start := time.Now()
+	query := "UPDATE accounts SET email_verified_at = COALESCE(email_verified_at, ?) WHERE id = ? AND email = ?"
+
+	result, err := repository.dbFromContext(ctx).ExecContext(ctx, query, at, accountID, email)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (repository *MysqlRepository) CreateAccountToken(ctx context.Context, token *AccountToken) error {
+	start := time.Now()
+	query := `INSERT INTO account_tokens (id, account_id, purpose, email, token_hash, expires_at, created_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query,
+		token.ID,
+		token.AccountID,
+		token.Purpose,
+		token.Email,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+func (repository *MysqlRepository) GetAccountTokenByHash(ctx context.Context, tokenHash string) (*AccountToken, error) {
+	start := time.Now()
+	query := `SELECT id, account_id, purpose, email, token_hash, expires_at, created_at, used_at
+	          FROM account_tokens WHERE token_hash = ?`
+
+	token := &AccountToken{}
+	var usedAt sql.NullTime
+	err := repository.dbFromContext(ctx).QueryRowContext(ctx, query, tokenHash).Scan(
+		&token.ID, &token.AccountID, &token.Purpose, &token.Email, &token.TokenHash,
+		&token.ExpiresAt, &token.CreatedAt, &usedAt)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Row")
+
+	if err != nil {
+		return nil, err
+	}
+	token.UsedAt = usedAt.Time
+	return token, nil
+}
+
+// UseAccountToken marks an unused token used. It reports false when the token was already
+// used, so two requests racing with one token cannot both succeed.
+func (repository *MysqlRepository) UseAccountToken(ctx context.Context, id string, at time.Time) (bool, error) {
+	start := time.Now()
+	query := "UPDATE account_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL"
+
+	result, err := repository.dbFromContext(ctx).ExecContext(ctx, query, at, id)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ExpireAccountTokens marks every unused token of an account and purpose used.
+func (repository *MysqlRepository) ExpireAccountTokens(ctx context.Context, accountID string, purpose string, at time.Time) error {
+	start := time.Now()
+	query := "UPDATE account_tokens SET used_at = ? WHERE account_id = ? AND purpose = ? AND used_at IS NULL"
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, at, accountID, purpose)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+func (repository *MysqlRepository) GetTOTP(ctx context.Context, accountID string) (*TOTPEnrollment, error) {
+	start := time.Now()
+	query := "SELECT account_id, secret, confirmed_at, COALESCE(last_used_step, 0), created_at FROM account_totp WHERE account_id = ?"
+
+	enrollment := &TOTPEnrollment{}
+	var confirmedAt sql.NullTime
+	err := repository.dbFromContext(ctx).QueryRowContext(ctx, query, accountID).Scan(
+		&enrollment.AccountID, &enrollment.Secret, &confirmedAt, &enrollment.LastUsedStep, &enrollment.CreatedAt)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Row")
+
+	if err != nil {
+		return nil, err
+	}
+	enrollment.ConfirmedAt = confirmedAt.Time
+	return enrollment, nil
+}
+
+// UpsertTOTP stores a new, unconfirmed secret, replacing an earlier unconfirmed one. It reports
+// false, and changes nothing, when the account's enrolment is already confirmed.
+func (repository *MysqlRepository) UpsertTOTP(ctx context.Context, enrollment *TOTPEnrollment) (bool, error) {
+	start := time.Now()
+	query := `INSERT INTO account_totp (account_id, secret, confirmed_at, last_used_step, created_at) VALUES (?, ?, NULL, NULL, ?)
+	          ON DUPLICATE KEY UPDATE
+	            secret = IF(confirmed_at IS NULL, VALUES(secret), secret),
+	            last_used_step = IF(confirmed_at IS NULL, NULL, last_used_step),
+	            created_at = IF(confirmed_at IS NULL, VALUES(created_at), created_at)`
+
+	result, err := repository.dbFromContext(ctx).ExecContext(ctx, query, enrollment.AccountID, enrollment.Secret, enrollment.CreatedAt)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	// 1 for an insert, 2 for a replaced row, 0 when the confirmed row was left alone.
+	return affected > 0, nil
+}
+
+// ConfirmTOTP turns an enrolment on, recording the step of the code that confirmed it.
+func (repository *MysqlRepository) ConfirmTOTP(ctx context.Context, accountID string, step int64, at time.Time) error {
+	start := time.Now()
+	query := "UPDATE account_totp SET confirmed_at = ?, last_used_step = ? WHERE account_id = ?"
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, at, step, accountID)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+// UseTOTPStep records an accepted code's step. It reports false when that step or a later one
+// was already used, so a code cannot be replayed, even by two requests racing.
+func (repository *MysqlRepository) UseTOTPStep(ctx context.Context, accountID string, step int64) (bool, error) {
+	start := time.Now()
+	query := "UPDATE account_totp SET last_used_step = ? WHERE account_id = ? AND (last_used_step IS NULL OR last_used_step < ?)"
+
+	result, err := repository.dbFromContext(ctx).ExecContext(ctx, query, step, accountID, step)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// DeleteTOTP removes an account's enrolment and its recovery codes.
+func (repository *MysqlRepository) DeleteTOTP(ctx context.Context, accountID string) error {
+	db := repository.dbFromContext(ctx)
+	for _, query := range []string{
+		"DELETE FROM recovery_codes WHERE account_id = ?",
+		"DELETE FROM account_totp WHERE account_id = ?",
+	} {
+		start := time.Now()
+		_, err := db.ExecContext(ctx, query, accountID)
+
+		repository.logger.Database().Debug().
+			Str("query", query).
+			Str("duration", time.Since(start).String()).
+			Bool("success", err == nil).
+			Msg("Execute Query")
+
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes drops an account's recovery codes, used or not, and stores new ones.
+func (repository *MysqlRepository) ReplaceRecoveryCodes(ctx context.Context, accountID string, codes []*RecoveryCode) error {
+	db := repository.dbFromContext(ctx)
+	start := time.Now()
+	query := "DELETE FROM recovery_codes WHERE account_id = ?"
+
+	_, err := db.ExecContext(ctx, query, accountID)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return err
+	}
+
+	query = "INSERT INTO recovery_codes (id, account_id, code_hash, created_at) VALUES (?, ?, ?, ?)"
+	for _, code := range codes {
+		start = time.Now()
+		_, err = db.ExecContext(ctx, query, code.ID, accountID, code.CodeHash, code.CreatedAt)
+
+		repository.logger.Database().Debug().
+			Str("query", query).
+			Str("duration", time.Since(start).String()).
+			Bool("success", err == nil).
+			Msg("Execute Query")
+
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code used. It reports false when the account has
+// no such unused code.
+func (repository *MysqlRepository) UseRecoveryCode(ctx context.Context, accountID string, codeHash string, at time.Time) (bool, error) {
+	start := time.Now()
+	query := "UPDATE recovery_codes SET used_at = ? WHERE account_id = ? AND code_hash = ? AND used_at IS NULL"
+
+	result, err := repository.dbFromContext(ctx).ExecContext(ctx, query, at, accountID, codeHash)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (repository *MysqlRepository) CreateLoginChallenge(ctx context.Context, challenge *LoginChallenge) error {
+	start := time.Now()
+	query := `INSERT INTO login_challenges (id, account_id, device_id, device_name, purpose, token_hash, expires_at, created_at)
+	          VALUES (?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?)`
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query,
+		challenge.ID,
+		challenge.AccountID,
+		challenge.DeviceID,
+		challenge.DeviceName,
+		challenge.Purpose,
+		challenge.TokenHash,
+		challenge.ExpiresAt,
+		challenge.CreatedAt,
+	)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+func (repository *MysqlRepository) GetLoginChallengeByHash(ctx context.Context, tokenHash string) (*LoginChallenge, error) {
+	start := time.Now()
+	query := `SELECT id, account_id, device_id, COALESCE(device_name, ''), purpose, token_hash, attempts, expires_at, created_at, used_at
+	          FROM login_challenges WHERE token_hash = ?`
+
+	challenge := &LoginChallenge{}
+	var usedAt sql.NullTime
+	err := repository.dbFromContext(ctx).QueryRowContext(ctx, query, tokenHash).Scan(
+		&challenge.ID, &challenge.AccountID, &challenge.DeviceID, &challenge.DeviceName, &challenge.Purpose,
+		&challenge.TokenHash, &challenge.Attempts, &challenge.ExpiresAt, &challenge.CreatedAt, &usedAt)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Row")
+
+	if err != nil {
+		return nil, err
+	}
+	challenge.UsedAt = usedAt.Time
+	return challenge, nil
+}
+
+// RecordChallengeAttempt counts a wrong code, using the challenge up at maxAttempts.
+func (repository *MysqlRepository) RecordChallengeAttempt(ctx context.Context, id string, maxAttempts int, at time.Time) error {
+	start := time.Now()
+	query := `UPDATE login_challenges SET attempts = attempts + 1,
+	          used_at = IF(attempts >= ?, COALESCE(used_at, ?), used_at) WHERE id = ?`
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, maxAttempts, at, id)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+// UseLoginChallenge marks an unused challenge used. It reports false when it already was, so a
+// challenge signs in once.
+func (repository *MysqlRepository) UseLoginChallenge(ctx context.Context, id string, at time.Time) (bool, error) {
+	start := time.Now()
+	query := "UPDATE login_challenges SET used_at = ? WHERE id = ? AND used_at IS NULL"
+
+	result, err := repository.dbFromContext(ctx).ExecContext(ctx, query, at, id)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+const sessionColumns = `id, account_id, device_id, COALESCE(device_name, ''), family_id, access_token,
+	COALESCE(ip_address, ''), COALESCE(user_agent, ''), expires_at, created_at, COALESCE(last_seen_at, created_at), is_revoked`
+
+func scanSession(row rowScanner) (*Session, error) {
+	session := &Session{}
+	if err := row.Scan(&session.ID, &session.AccountID, &session.DeviceID, &session.DeviceName, &session.FamilyID, &session.AccessToken,
+		&session.IPAddress, &session.UserAgent, &session.ExpiresAt, &session.CreatedAt, &session.LastSeenAt, &session.IsRevoked); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (repository *MysqlRepository) CreateOrUpdateSession(ctx context.Context, session *Session) error {
+	start := time.Now()
+	query := `
+		INSERT INTO sessions (id, account_id, device_id, device_name, family_id, access_token, ip_address, user_agent,
+			expires_at, created_at, last_seen_at, is_revoked)
+		VALUES (?, ?, ?, NULLIF(?, ''), ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE 
+			device_name = VALUES(device_name),
+			family_id = VALUES(family_id),
+			access_token = VALUES(access_token),
+			ip_address = VALUES(ip_address),
+			user_agent = VALUES(user_agent),
+			created_at = VALUES(created_at),
+			last_seen_at = VALUES(last_seen_at),
+			expires_at = VALUES(expires_at),
+			is_revoked = VALUES(is_revoked)
+	`
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query,
+		session.ID,
+		session.AccountID,
+		session.DeviceID,
+		session.DeviceName,
+		session.FamilyID,
+		session.AccessToken,
+		session.IPAddress,
+		session.UserAgent,
+		session.ExpiresAt,
+		session.CreatedAt,
+		session.LastSeenAt,
+		session.IsRevoked,
+	)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+func (repository *MysqlRepository) GetSession(ctx context.Context, id string) (*Session, error) {
+	start := time.Now()
+	query := "SELECT " + sessionColumns + " FROM sessions WHERE id = ?"
+
+	row := repository.db.QueryRowContext(ctx, query, id)
+	session, err := scanSession(row)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Row")
+
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetSessionByFamily returns the open session signed in with a refresh token family.
+func (repository *MysqlRepository) GetSessionByFamily(ctx context.Context, familyID string) (*Session, error) {
+	start := time.Now()
+	query := "SELECT " + sessionColumns + " FROM sessions WHERE family_id = ? AND is_revoked = 0"
+
+	row := repository.dbFromContext(ctx).QueryRowContext(ctx, query, familyID)
+	session, err := scanSession(row)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Row")
+
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (repository *MysqlRepository) GetSessionByAccessToken(ctx context.Context, accessToken string) (*Session, error) {
+	start := time.Now()
+	query := "SELECT " + sessionColumns + " FROM sessions WHERE access_token = ?"
+
+	row := repository.db.QueryRowContext(ctx, query, accessToken)
+	session, err := scanSession(row)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Row")
+
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (repository *MysqlRepository) RevokeSessionByAccessToken(ctx context.Context, accessToken string) error {
+	start := time.Now()
+	query := "UPDATE sessions SET is_revoked = true WHERE access_token = ?"
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, accessToken)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+// RevokeDeviceSessions revokes every session of an account on one device.
+func (repository *MysqlRepository) RevokeDeviceSessions(ctx context.Context, accountID string, deviceID string) error {
+	start := time.Now()
+	query := "UPDATE sessions SET is_revoked = true WHERE account_id = ? AND device_id = ?"
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, accountID, deviceID)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+// ListSessions returns an account's sessions that are neither revoked nor expired, most
+// recently seen first.
+func (repository *MysqlRepository) ListSessions(ctx context.Context, accountID string, now time.Time) ([]*Session, error) {
+	start := time.Now()
+	query := "SELECT " + sessionColumns + " FROM sessions WHERE account_id = ? AND is_revoked = 0 AND expires_at > ? ORDER BY COALESCE(last_seen_at, created_at) DESC, id"
+
+	rows, err := repository.dbFromContext(ctx).QueryContext(ctx, query, accountID, now)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Rows")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// TouchSession records a call from a session. An empty IP address or user agent keeps the
+// stored one.
+func (repository *MysqlRepository) TouchSession(ctx context.Context, id string, ipAddress string, userAgent string, at time.Time) error {
+	start := time.Now()
+	query := `UPDATE sessions
+	          SET last_seen_at = ?, ip_address = COALESCE(NULLIF(?, ''), ip_address), user_agent = COALESCE(NULLIF(?, ''), user_agent)
+	          WHERE id = ?`
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, at, ipAddress, userAgent, id)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+// RevokeSessions signs out an account's open sessions together with their refresh token
+// families: only sessionID when it is set, and never exceptSessionID. It returns how many
+// sessions were signed out.
+func (repository *MysqlRepository) RevokeSessions(ctx context.Context, accountID string, sessionID string, exceptSessionID string, reason string, at time.Time) (uint32, error) {
+	start := time.Now()
+	scope := "account_id = ? AND is_revoked = 0 AND (? = '' OR id = ?) AND id <> ?"
+	args := []any{accountID, sessionID, sessionID, exceptSessionID}
+
+	query := `UPDATE refresh_tokens SET revoked_at = ?, revoke_reason = ?
+	          WHERE revoked_at IS NULL AND family_id IN (SELECT family_id FROM sessions WHERE ` + scope + `)`
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, append([]any{at, reason}, args...)...)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return 0, err
+	}
+
+	start = time.Now()
+	query = "UPDATE sessions SET is_revoked = true WHERE " + scope
+	result, err := repository.dbFromContext(ctx).ExecContext(ctx, query, args...)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return 0, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
 	}
-	return accounts, nil
+	return uint32(affected), nil
 }
 
-func (repository *MysqlRepository) CreateOrUpdateSession(ctx context.Context, session *Session) error {
+func (repository *MysqlRepository) CreateRefreshToken(ctx context.Context, token *RefreshToken) error {
 	start := time.Now()
-	query := `
-		INSERT INTO sessions (id, account_id, device_id, access_token, refresh_token, expires_at, created_at, is_revoked)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-		ON DUPLICATE KEY UPDATE 
-			access_token = VALUES(access_token),
-			refresh_token = VALUES(refresh_token),
-			expires_at = VALUES(expires_at),
-			is_revoked = VALUES(is_revoked)
-	`
-
-	_, err := repository.db.ExecContext(ctx, query,
-		session.ID,
-		session.AccountID,
-		session.DeviceID,
-		session.AccessToken,
-		session.RefreshToken,
-		session.ExpiresAt,
-		session.CreatedAt,
-		session.IsRevoked,
+	query := `INSERT INTO refresh_tokens (id, family_id, parent_id, account_id, device_id, token_hash, expires_at, created_at)
+	          VALUES (?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?)`
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query,
+		token.ID,
+		token.FamilyID,
+		token.ParentID,
+		token.AccountID,
+		token.DeviceID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
 	)
 
 	repository.logger.Database().Debug().
@@ -255,13 +930,19 @@ func (repository *MysqlRepository) CreateOrUpdateSession(ctx context.Context, se
 	return err
 }
 
-func (repository *MysqlRepository) GetSession(ctx context.Context, id string) (*Session, error) {
+// GetRefreshTokenByHash returns a refresh token whatever its state, so a rotated or revoked
+// token can be told apart from one that was never issued.
+func (repository *MysqlRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, error) {
 	start := time.Now()
-	query := "SELECT id, account_id, device_id, access_token, refresh_token, expires_at, created_at, is_revoked FROM sessions WHERE id = ?"
+	query := `SELECT id, family_id, COALESCE(parent_id, ''), account_id, device_id, token_hash, expires_at, created_at,
+	                 rotated_at, revoked_at, COALESCE(revoke_reason, '')
+	          FROM refresh_tokens WHERE token_hash = ?`
 
-	row := repository.db.QueryRowContext(ctx, query, id)
-	session := &Session{}
-	err := row.Scan(&session.ID, &session.AccountID, &session.DeviceID, &session.AccessToken, &session.RefreshToken, &session.ExpiresAt, &session.CreatedAt, &session.IsRevoked)
+	token := &RefreshToken{}
+	var rotatedAt, revokedAt sql.NullTime
+	err := repository.dbFromContext(ctx).QueryRowContext(ctx, query, tokenHash).Scan(
+		&token.ID, &token.FamilyID, &token.ParentID, &token.AccountID, &token.DeviceID, &token.TokenHash,
+		&token.ExpiresAt, &token.CreatedAt, &rotatedAt, &revokedAt, &token.RevokeReason)
 
 	repository.logger.Database().Debug().
 		Str("query", query).
@@ -272,54 +953,57 @@ func (repository *MysqlRepository) GetSession(ctx context.Context, id string) (*
 	if err != nil {
 		return nil, err
 	}
-	return session, nil
+	token.RotatedAt = rotatedAt.Time
+	token.RevokedAt = revokedAt.Time
+	return token, nil
 }
 
-func (repository *MysqlRepository) GetSessionByRefreshToken(ctx context.Context, refreshToken string) (*Session, error) {
+// RotateRefreshToken marks a live token rotated. It reports false when the token was already
+// rotated or revoked, so two refreshes racing with one token cannot both succeed.
+func (repository *MysqlRepository) RotateRefreshToken(ctx context.Context, id string, at time.Time) (bool, error) {
 	start := time.Now()
-	query := "SELECT id, account_id, device_id, access_token, refresh_token, expires_at, created_at, is_revoked FROM sessions WHERE refresh_token = ? AND is_revoked = 0"
+	query := "UPDATE refresh_tokens SET rotated_at = ? WHERE id = ? AND rotated_at IS NULL AND revoked_at IS NULL"
 
-	row := repository.db.QueryRowContext(ctx, query, refreshToken)
-	session := &Session{}
-	err := row.Scan(&session.ID, &session.AccountID, &session.DeviceID, &session.AccessToken, &session.RefreshToken, &session.ExpiresAt, &session.CreatedAt, &session.IsRevoked)
+	result, err := repository.dbFromContext(ctx).ExecContext(ctx, query, at, id)
 
 	repository.logger.Database().Debug().
 		Str("query", query).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("Query Row")
+		Msg("Execute Query")
 
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	return session, nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
 }
 
-func (repository *MysqlRepository) GetSessionByAccessToken(ctx context.Context, accessToken string) (*Session, error) {
+// RevokeRefreshFamily revokes every token of a family not revoked yet.
+func (repository *MysqlRepository) RevokeRefreshFamily(ctx context.Context, familyID string, reason string, at time.Time) error {
 	start := time.Now()
-	query := "SELECT id, account_id, device_id, access_token, refresh_token, expires_at, created_at, is_revoked FROM sessions WHERE access_token = ?"
+	query := "UPDATE refresh_tokens SET revoked_at = ?, revoke_reason = ? WHERE family_id = ? AND revoked_at IS NULL"
 
-	row := repository.db.QueryRowContext(ctx, query, accessToken)
-	session := &Session{}
-	err := row.Scan(&session.ID, &session.AccountID, &session.DeviceID, &session.AccessToken, &session.RefreshToken, &session.ExpiresAt, &session.CreatedAt, &session.IsRevoked)
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, at, reason, familyID)
 
 	repository.logger.Database().Debug().
 		Str("query", query).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("Query Row")
+		Msg("Execute Query")
 
-	if err != nil {
-		return nil, err
-	}
-	return session, nil
+	return err
 }
 
-func (repository *MysqlRepository) RevokeSessionByAccessToken(ctx context.Context, accessToken string) error {
+// RevokeDeviceRefreshTokens revokes every token, of every family, an account holds on one device.
+func (repository *MysqlRepository) RevokeDeviceRefreshTokens(ctx context.Context, accountID string, deviceID string, reason string, at time.Time) error {
 	start := time.Now()
-	query := "UPDATE sessions SET is_revoked = true WHERE access_token = ?"
+	query := "UPDATE refresh_tokens SET revoked_at = ?, revoke_reason = ? WHERE account_id = ? AND device_id = ? AND revoked_at IS NULL"
 
-	_, err := repository.db.ExecContext(ctx, query, accessToken)
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, at, reason, accountID, deviceID)
 
 	repository.logger.Database().Debug().
 		Str("query", query).
@@ -443,7 +1127,7 @@ func (repository *MysqlRepository) ListProducts(ctx context.Context, skip uint,
 
 func (repository *MysqlRepository) CreateOrUpdateGrade(ctx context.Context, grade *Grade) (*Grade, error) {
 	start := time.Now()
-	query := "INSERT INTO grade (id, product_id, name, description, status) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE product_id = ?, name = ?, description = ?, status = ?"
+	query := "INSERT INTO grade (id, product_id, name, description, status, shelf_life_days, unit, kg_per_unit) VALUES (?, ?, ?, ?, ?, NULLIF(?, 0), ?, ?) ON DUPLICATE KEY UPDATE product_id = ?, name = ?, description = ?, status = ?, shelf_life_days = NULLIF(?, 0), unit = ?, kg_per_unit = ?"
 
 	_, err := repository.db.ExecContext(ctx, query,
 		grade.ID,
@@ -451,10 +1135,16 @@ func (repository *MysqlRepository) CreateOrUpdateGrade(ctx context.Context, grad
 		grade.Name,
 		grade.Description,
 		grade.Status,
+		grade.ShelfLifeDays,
+		grade.Unit,
+		grade.KgPerUnit,
 		grade.ProductID,
 		grade.Name,
 		grade.Description,
 		grade.Status,
+		grade.ShelfLifeDays,
+		grade.Unit,
+		grade.KgPerUnit,
 	)
 
 	repository.logger.Database().Debug().
@@ -469,9 +1159,29 @@ func (repository *MysqlRepository) CreateOrUpdateGrade(ctx context.Context, grad
 	return grade, nil
 }
 
+func (repository *MysqlRepository) GetGradeById(ctx context.Context, id string) (*Grade, error) {
+	start := time.Now()
+	query := "SELECT id, product_id, name, description, status, COALESCE(shelf_life_days, 0), unit, kg_per_unit FROM grade WHERE id = ?"
+
+	grade := &Grade{}
+	err := repository.dbFromContext(ctx).QueryRowContext(ctx, query, id).
+		Scan(&grade.ID, &grade.ProductID, &grade.Name, &grade.Description, &grade.Status, &grade.ShelfLifeDays, &grade.Unit, &grade.KgPerUnit)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Row")
+
+	if err != nil {
+		return nil, err
+	}
+	return grade, nil
+}
+
 func (repository *MysqlRepository) ListGradesByProductId(ctx context.Context, productId string, skip uint, take uint) ([]*Grade, error) {
 	start := time.Now()
-	query := "SELECT id, product_id, name, description, status FROM grade WHERE product_id = ? ORDER BY id DESC LIMIT ? OFFSET ?"
+	query := "SELECT id, product_id, name, description, status, COALESCE(shelf_life_days, 0), unit, kg_per_unit FROM grade WHERE product_id = ? ORDER BY id DESC LIMIT ? OFFSET ?"
 
 	rows, err := repository.db.QueryContext(ctx, query, productId, take, skip)
 
@@ -489,7 +1199,7 @@ func (repository *MysqlRepository) ListGradesByProductId(ctx context.Context, pr
 	grades := []*Grade{}
 	for rows.Next() {
 		grade := &Grade{}
-		if err := rows.Scan(&grade.ID, &grade.ProductID, &grade.Name, &grade.Description, &grade.Status); err != nil {
+		if err := rows.Scan(&grade.ID, &grade.ProductID, &grade.Name, &grade.Description, &grade.Status, &grade.ShelfLifeDays, &grade.Unit, &grade.KgPerUnit); err != nil {
 			return nil, err
 		}
 		grades = append(grades, grade)
@@ -500,19 +1210,98 @@ func (repository *MysqlRepository) ListGradesByProductId(ctx context.Context, pr
 	return grades, nil
 }
 
-func (repository *MysqlRepository) CreateOrUpdateDailyPrice(ctx context.Context, dailyPrice *DailyPrice) (*DailyPrice, error) {
+// priceTickColumns is the SELECT list read by scanPriceTick.
+const priceTickColumns = `id, product_id, grade_id, price, currency, source, COALESCE(published_by, ''), status,
+	          submitted_at, COALESCE(reviewed_by, ''), reviewed_at, COALESCE(review_note, ''), ticked_at, created_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPriceTick(row rowScanner) (*PriceTick, error) {
+	tick := &PriceTick{}
+	var submittedAt, reviewedAt sql.NullTime
+	if err := row.Scan(&tick.ID, &tick.ProductID, &tick.GradeID, &tick.Price, &tick.Currency, &tick.Source, &tick.PublishedBy, &tick.Status,
+		&submittedAt, &tick.ReviewedBy, &reviewedAt, &tick.ReviewNote, &tick.TickedAt, &tick.CreatedAt); err != nil {
+		return nil, err
+	}
+	tick.SubmittedAt = submittedAt.Time
+	tick.ReviewedAt = reviewedAt.Time
+	return tick, nil
+}
+
+// nullTimeArg formats an optional timestamp for a DATETIME(6) column; zero is NULL.
+func nullTimeArg(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format("2006-01-02 15:04:05.000000")
+}
+
+func (repository *MysqlRepository) InsertPriceTick(ctx context.Context, tick *PriceTick) error {
 	start := time.Now()
-	query := "INSERT INTO daily_price (id, product_id, grade_id, price, date, time) VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE price = ?, time = ?"
+	query := `INSERT INTO price_ticks (id, product_id, grade_id, price, currency, source, published_by, status, submitted_at, tick_date, ticked_at)
+	          VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?)`
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query,
+		tick.ID,
+		tick.ProductID,
+		tick.GradeID,
+		tick.Price,
+		tick.Currency,
+		tick.Source,
+		tick.PublishedBy,
+		tick.Status,
+		nullTimeArg(tick.SubmittedAt),
+		tick.TickedAt.Format("2006-01-02"),
+		tick.TickedAt.Format("2006-01-02 15:04:05.000000"),
+	)
 
-	_, err := repository.db.ExecContext(ctx, query,
-		dailyPrice.ID,
-		dailyPrice.ProductID,
-		dailyPrice.GradeID,
-		dailyPrice.Price,
-		dailyPrice.Date.Format("2006-01-02"),
-		dailyPrice.Time.Format("15:04:05"),
-		dailyPrice.Price,
-		dailyPrice.Time.Format("15:04:05"),
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	return err
+}
+
+func (repository *MysqlRepository) GetPriceTick(ctx context.Context, id string) (*PriceTick, error) {
+	start := time.Now()
+	query := `SELECT ` + priceTickColumns + ` FROM price_ticks WHERE id = ?`
+
+	tick, err := scanPriceTick(repository.dbFromContext(ctx).QueryRowContext(ctx, query, id))
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Row")
+
+	if err != nil {
+		return nil, err
+	}
+	return tick, nil
+}
+
+// UpdatePriceTickStatus moves a tick from one status to tick.Status and stores its submission
+// and review fields. It reports false when the tick was no longer in the from status, so two
+// reviewers cannot both act on the same tick.
+func (repository *MysqlRepository) UpdatePriceTickStatus(ctx context.Context, tick *PriceTick, from string) (bool, error) {
+	start := time.Now()
+	query := `UPDATE price_ticks
+	          SET status = ?, submitted_at = ?, reviewed_by = NULLIF(?, ''), reviewed_at = ?, review_note = NULLIF(?, '')
+	          WHERE id = ? AND status = ?`
+
+	result, err := repository.dbFromContext(ctx).ExecContext(ctx, query,
+		tick.Status,
+		nullTimeArg(tick.SubmittedAt),
+		tick.ReviewedBy,
+		nullTimeArg(tick.ReviewedAt),
+		tick.ReviewNote,
+		tick.ID,
+		from,
 	)
 
 	repository.logger.Database().Debug().
@@ -521,18 +1310,98 @@ func (repository *MysqlRepository) CreateOrUpdateDailyPrice(ctx context.Context,
 		Bool("success", err == nil).
 		Msg("Execute Query")
 
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// RollupDailyPrice rebuilds a grade's daily_price row for one date from that day's approved
+// ticks and returns it. rollupID is used only when the day has no row yet. Rebuilding from the
+// ticks keeps the row right when ticks arrive out of order or concurrently. It returns
+// sql.ErrNoRows when the day has no approved tick. The currency is the last tick's; the service
+// keeps a day's approved ticks in one currency.
+func (repository *MysqlRepository) RollupDailyPrice(ctx context.Context, rollupID string, gradeId string, date time.Time) (*DailyPrice, error) {
+	start := time.Now()
+	day := date.Format("2006-01-02")
+	query := `INSERT INTO daily_price (id, product_id, grade_id, price, currency, open_price, high_price, low_price, tick_count, date, time)
+	          SELECT ?, t.product_id, t.grade_id,
+	                 (SELECT l.price FROM price_ticks l
+	                  WHERE l.grade_id = t.grade_id AND l.tick_date = t.tick_date AND l.status = 'APPROVED'
+	                  ORDER BY l.ticked_at DESC, l.id DESC LIMIT 1),
+	                 (SELECT c.currency FROM price_ticks c
+	                  WHERE c.grade_id = t.grade_id AND c.tick_date = t.tick_date AND c.status = 'APPROVED'
+	                  ORDER BY c.ticked_at DESC, c.id DESC LIMIT 1),
+	                 (SELECT o.price FROM price_ticks o
+	                  WHERE o.grade_id = t.grade_id AND o.tick_date = t.tick_date AND o.status = 'APPROVED'
+	                  ORDER BY o.ticked_at ASC, o.id ASC LIMIT 1),
+	                 MAX(t.price), MIN(t.price), COUNT(*), t.tick_date, TIME(MAX(t.ticked_at))
+	          FROM price_ticks t
+	          WHERE t.grade_id = ? AND t.tick_date = ? AND t.status = 'APPROVED'
+	          GROUP BY t.product_id, t.grade_id, t.tick_date
+	          ON DUPLICATE KEY UPDATE
+	            price      = VALUES(price),
+	            currency   = VALUES(currency),
+	            open_price = VALUES(open_price),
+	            high_price = VALUES(high_price),
+	            low_price  = VALUES(low_price),
+	            tick_count = VALUES(tick_count),
+	            time       = VALUES(time)`
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query, rollupID, gradeId, day)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := repository.queryDailyPrices(ctx, "WHERE grade_id = ? AND date = ?", gradeId, day)
 	if err != nil {
 		return nil, err
 	}
-	return dailyPrice, nil
+	if len(prices) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return prices[0], nil
 }
 
-func (repository *MysqlRepository) ListDailyPricesByGradeId(ctx context.Context, gradeId string, date time.Time, duration int) ([]*DailyPrice, error) {
+// maxListedPriceTicks bounds one ListPriceTicks result.
+const maxListedPriceTicks = 1000
+
+// ListPriceTicks returns ticks oldest first, filtered by whichever of grade, date and status
+// are set.
+func (repository *MysqlRepository) ListPriceTicks(ctx context.Context, gradeId string, date time.Time, status string) ([]*PriceTick, error) {
 	start := time.Now()
-	startDate := date.AddDate(0, 0, -duration)
-	query := "SELECT id, product_id, grade_id, price, date, time FROM daily_price WHERE grade_id = ? AND date BETWEEN ? AND ? ORDER BY date DESC, time DESC"
+	where := []string{"1 = 1"}
+	args := []any{}
+	if gradeId != "" {
+		where = append(where, "grade_id = ?")
+		args = append(args, gradeId)
+	}
+	if !date.IsZero() {
+		where = append(where, "tick_date = ?")
+		args = append(args, date.Format("2006-01-02"))
+	}
+	if status != "" {
+		where = append(where, "status = ?")
+		args = append(args, status)
+	}
+	query := `SELECT ` + priceTickColumns + `
+	          FROM price_ticks
+	          WHERE ` + strings.Join(where, " AND ") + `
+	          ORDER BY ticked_at ASC, id ASC
+	          LIMIT ` + strconv.Itoa(maxListedPriceTicks)
 
-	rows, err := repository.db.QueryContext(ctx, query, gradeId, startDate.Format("2006-01-02"), date.Format("2006-01-02"))
+	rows, err := repository.dbFromContext(ctx).QueryContext(ctx, query, args...)
 
 	repository.logger.Database().Debug().
 		Str("query", query).
@@ -545,24 +1414,75 @@ func (repository *MysqlRepository) ListDailyPricesByGradeId(ctx context.Context,
 	}
 	defer rows.Close()
 
-	dailyPrices := []*DailyPrice{}
+	ticks := []*PriceTick{}
 	for rows.Next() {
-		dailyPrice := &DailyPrice{}
-		var timeBytes []byte
-		if err := rows.Scan(&dailyPrice.ID, &dailyPrice.ProductID, &dailyPrice.GradeID, &dailyPrice.Price, &dailyPrice.Date, &timeBytes); err != nil {
+		tick, err := scanPriceTick(rows)
+		if err != nil {
 			return nil, err
 		}
-		dailyPrice.Time, _ = time.Parse("15:04:05", string(timeBytes))
-		dailyPrices = append(dailyPrices, dailyPrice)
+		ticks = append(ticks, tick)
+	}
+	return ticks, rows.Err()
+}
+
+// GetLatestPriceTick returns the newest approved tick of a grade published at or before at,
+// or sql.ErrNoRows when the grade has none.
+func (repository *MysqlRepository) GetLatestPriceTick(ctx context.Context, gradeId string, at time.Time) (*PriceTick, error) {
+	start := time.Now()
+	query := `SELECT ` + priceTickColumns + `
+	          FROM price_ticks
+	          WHERE grade_id = ? AND ticked_at <= ? AND status = 'APPROVED'
+	          ORDER BY ticked_at DESC, id DESC
+	          LIMIT 1`
+
+	row := repository.dbFromContext(ctx).QueryRowContext(ctx, query, gradeId, at.Format("2006-01-02 15:04:05.000000"))
+	tick, err := scanPriceTick(row)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Row")
+
+	if err != nil {
+		return nil, err
 	}
-	return dailyPrices, nil
+	return tick, nil
+}
+
+func (repository *MysqlRepository) ListDailyPricesByGradeId(ctx context.Context, gradeId string, date time.Time, duration int) ([]*DailyPrice, error) {
+	startDate := date.AddDate(0, 0, -duration)
+	return repository.queryDailyPrices(ctx, "WHERE grade_id = ? AND date BETWEEN ? AND ? ORDER BY date DESC, time DESC",
+		gradeId, startDate.Format("2006-01-02"), date.Format("2006-01-02"))
 }
 
 func (repository *MysqlRepository) GetTodaysByGradeId(ctx context.Context, gradeId string, date time.Time) ([]*DailyPrice, error) {
+	return repository.queryDailyPrices(ctx, "WHERE grade_id = ? AND date = ? ORDER BY time DESC", gradeId, date.Format("2006-01-02"))
+}
+
+func (repository *MysqlRepository) GetTodaysByProductId(ctx context.Context, productId string, date time.Time) ([]*DailyPrice, error) {
+	return repository.queryDailyPrices(ctx, "WHERE product_id = ? AND date = ? ORDER BY time DESC", productId, date.Format("2006-01-02"))
+}
+
+// ListDailyPricesInRange returns the rollups of one grade, or of every grade of a product when
+// gradeId is empty, between two dates inclusive, ordered by grade and date.
+func (repository *MysqlRepository) ListDailyPricesInRange(ctx context.Context, gradeId string, productId string, from time.Time, to time.Time) ([]*DailyPrice, error) {
+	if gradeId != "" {
+		return repository.queryDailyPrices(ctx, "WHERE grade_id = ? AND date BETWEEN ? AND ? ORDER BY grade_id, date",
+			gradeId, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	return repository.queryDailyPrices(ctx, "WHERE product_id = ? AND date BETWEEN ? AND ? ORDER BY grade_id, date",
+		productId, from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
+
+// queryDailyPrices reads daily_price rollups; Price and Last are both the last tick.
+func (repository *MysqlRepository) queryDailyPrices(ctx context.Context, where string, args ...interface{}) ([]*DailyPrice, error) {
 	start := time.Now()
-	query := "SELECT id, product_id, grade_id, price, date, time FROM daily_price WHERE grade_id = ? AND date = ? ORDER BY time DESC"
+	query := `SELECT id, product_id, grade_id, price, currency, COALESCE(open_price, price), COALESCE(high_price, price),
+	                 COALESCE(low_price, price), tick_count, date, time
+	          FROM daily_price ` + where
 
-	rows, err := repository.db.QueryContext(ctx, query, gradeId, date.Format("2006-01-02"))
+	rows, err := repository.dbFromContext(ctx).QueryContext(ctx, query, args...)
 
 	repository.logger.Database().Debug().
 		Str("query", query).
@@ -579,13 +1499,15 @@ func (repository *MysqlRepository) GetTodaysByGradeId(ctx context.Context, grade
 	for rows.Next() {
 		dailyPrice := &DailyPrice{}
 		var timeBytes []byte
-		if err := rows.Scan(&dailyPrice.ID, &dailyPrice.ProductID, &dailyPrice.GradeID, &dailyPrice.Price, &dailyPrice.Date, &timeBytes); err != nil {
+		if err := rows.Scan(&dailyPrice.ID, &dailyPrice.ProductID, &dailyPrice.GradeID, &dailyPrice.Price, &dailyPrice.Currency,
+			&dailyPrice.Open, &dailyPrice.High, &dailyPrice.Low, &dailyPrice.TickCount, &dailyPrice.Date, &timeBytes); err != nil {
 			return nil, err
 		}
 		dailyPrice.Time, _ = time.Parse("15:04:05", string(timeBytes))
+		dailyPrice.Last = dailyPrice.Price
 		dailyPrices = append(dailyPrices, dailyPrice)
 	}
-	return dailyPrices, nil
+	return dailyPrices, rows.Err()
 }
 
 func (repository *MysqlRepository) GetProductsWithGradesAndPrices(ctx context.Context, date time.Time, search string) ([]*ProductWithGrades, error) {
@@ -601,7 +1523,11 @@ func (repository *MysqlRepository) GetProductsWithGradesAndPrices(ctx context.Co
 			g.name as grade_name,
 			g.description as grade_description,
 			g.status as grade_status,
-			dp.price
+			COALESCE(g.shelf_life_days, 0) as grade_shelf_life_days,
+			COALESCE(g.unit, '') as grade_unit,
+			COALESCE(g.kg_per_unit, 0) as grade_kg_per_unit,
+			dp.price,
+			dp.currency
 		FROM products p
 		LEFT JOIN grade g ON g.product_id = p.id
 		LEFT JOIN daily_price dp 
@@ -625,12 +1551,16 @@ func (repository *MysqlRepository) GetProductsWithGradesAndPrices(ctx context.Co
 	for rows.Next() {
 		var pID, pName, pCategory, pDescription, pStatus string
 		var gID, gName, gDescription, gStatus sql.NullString
-		var dpPrice sql.NullFloat64
+		var gShelfLifeDays int
+		var gUnit string
+		var gKgPerUnit decimal.Decimal
+		var dpPrice decimal.NullDecimal
+		var dpCurrency sql.NullString
 
 		err := rows.Scan(
 			&pID, &pName, &pCategory, &pDescription, &pStatus,
 			&gID, &gName, &gDescription, &gStatus,
-			&dpPrice,
+			&gShelfLifeDays, &gUnit, &gKgPerUnit, &dpPrice, &dpCurrency,
 		)
 		if err != nil {
 			return nil, err
@@ -652,12 +1582,16 @@ func (repository *MysqlRepository) GetProductsWithGradesAndPrices(ctx context.Co
 
 		if gID.Valid {
 			product.Grades = append(product.Grades, &GradeWithPrice{
-				ID:          gID.String,
-				ProductID:   pID,
-				Name:        gName.String,
-				Description: gDescription.String,
-				Status:      gStatus.String,
-				Price:       dpPrice.Float64,
+				ID:            gID.String,
+				ProductID:     pID,
+				Name:          gName.String,
+				Description:   gDescription.String,
+				Status:        gStatus.String,
+				Price:         dpPrice.Decimal,
+				Currency:      dpCurrency.String,
+				ShelfLifeDays: gShelfLifeDays,
+				Unit:          gUnit,
+				KgPerUnit:     gKgPerUnit,
 			})
 		}
 	}
@@ -669,3 +1603,188 @@ func (repository *MysqlRepository) GetProductsWithGradesAndPrices(ctx context.Co
 
 	return products, nil
 }
+
+// UpsertFxRate stores the rate of a pair from its effective date, replacing a rate already set
+// for that date, and returns it as stored.
+func (repository *MysqlRepository) UpsertFxRate(ctx context.Context, rate *FxRate) (*FxRate, error) {
+	start := time.Now()
+	query := `INSERT INTO fx_rates (id, base_currency, quote_currency, rate, effective_date, updated_by)
+	          VALUES (?, ?, ?, ?, ?, NULLIF(?, ''))
+	          ON DUPLICATE KEY UPDATE rate = VALUES(rate), updated_by = VALUES(updated_by)`
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query,
+		rate.ID,
+		rate.BaseCurrency,
+		rate.QuoteCurrency,
+		rate.Rate,
+		rate.EffectiveDate.Format("2006-01-02"),
+		rate.UpdatedBy,
+	)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := repository.queryFxRates(ctx, "WHERE base_currency = ? AND quote_currency = ? AND effective_date = ?",
+		rate.BaseCurrency, rate.QuoteCurrency, rate.EffectiveDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return rates[0], nil
+}
+
+// maxListedFxRates bounds one ListFxRates result.
+const maxListedFxRates = 500
+
+// ListFxRates returns rates newest first, filtered by whichever of the two currencies are set.
+func (repository *MysqlRepository) ListFxRates(ctx context.Context, baseCurrency string, quoteCurrency string) ([]*FxRate, error) {
+	where := []string{"1 = 1"}
+	args := []any{}
+	if baseCurrency != "" {
+		where = append(where, "base_currency = ?")
+		args = append(args, baseCurrency)
+	}
+	if quoteCurrency != "" {
+		where = append(where, "quote_currency = ?")
+		args = append(args, quoteCurrency)
+	}
+	return repository.queryFxRates(ctx, "WHERE "+strings.Join(where, " AND ")+`
+	          ORDER BY effective_date DESC, base_currency, quote_currency
+	          LIMIT `+strconv.Itoa(maxListedFxRates), args...)
+}
+
+func (repository *MysqlRepository) queryFxRates(ctx context.Context, where string, args ...any) ([]*FxRate, error) {
+	start := time.Now()
+	query := `SELECT id, base_currency, quote_currency, rate, effective_date, COALESCE(updated_by, ''), updated_at
+	          FROM fx_rates ` + where
+
+	rows, err := repository.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Rows")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []*FxRate{}
+	for rows.Next() {
+		rate := &FxRate{}
+		if err := rows.Scan(&rate.ID, &rate.BaseCurrency, &rate.QuoteCurrency, &rate.Rate,
+			&rate.EffectiveDate, &rate.UpdatedBy, &rate.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+// UpsertFeeSchedule stores a schedule from its effective date, replacing one already set for
+// that scope, code, side and date, and returns it as stored.
+func (repository *MysqlRepository) UpsertFeeSchedule(ctx context.Context, schedule *FeeSchedule) (*FeeSchedule, error) {
+	start := time.Now()
+	query := `INSERT INTO fee_schedules (id, grade_id, category, code, kind, side, basis, rate, currency, effective_date, updated_by)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))
+	          ON DUPLICATE KEY UPDATE kind = VALUES(kind), basis = VALUES(basis), rate = VALUES(rate),
+	                                  currency = VALUES(currency), updated_by = VALUES(updated_by)`
+
+	_, err := repository.dbFromContext(ctx).ExecContext(ctx, query,
+		schedule.ID,
+		schedule.GradeID,
+		schedule.Category,
+		schedule.Code,
+		schedule.Kind,
+		schedule.Side,
+		schedule.Basis,
+		schedule.Rate,
+		schedule.Currency,
+		schedule.EffectiveDate.Format("2006-01-02"),
+		schedule.UpdatedBy,
+	)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Execute Query")
+
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := repository.queryFeeSchedules(ctx, "WHERE grade_id = ? AND category = ? AND code = ? AND side = ? AND effective_date = ?",
+		schedule.GradeID, schedule.Category, schedule.Code, schedule.Side, schedule.EffectiveDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return schedules[0], nil
+}
+
+// maxListedFeeSchedules bounds one ListFeeSchedules result.
+const maxListedFeeSchedules = 500
+
+// ListFeeSchedules returns schedules newest first, filtered by whichever of grade and category
+// are set.
+func (repository *MysqlRepository) ListFeeSchedules(ctx context.Context, gradeID string, category string) ([]*FeeSchedule, error) {
+	where := []string{"1 = 1"}
+	args := []any{}
+	if gradeID != "" {
+		where = append(where, "grade_id = ?")
+		args = append(args, gradeID)
+	}
+	if category != "" {
+		where = append(where, "category = ?")
+		args = append(args, category)
+	}
+	return repository.queryFeeSchedules(ctx, "WHERE "+strings.Join(where, " AND ")+`
+	          ORDER BY effective_date DESC, grade_id, category, code, side
+	          LIMIT `+strconv.Itoa(maxListedFeeSchedules), args...)
+}
+
+func (repository *MysqlRepository) queryFeeSchedules(ctx context.Context, where string, args ...any) ([]*FeeSchedule, error) {
+	start := time.Now()
+	query := `SELECT id, grade_id, category, code, kind, side, basis, rate, currency, effective_date,
+	                 COALESCE(updated_by, ''), updated_at
+	          FROM fee_schedules ` + where
+
+	rows, err := repository.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	repository.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("Query Rows")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []*FeeSchedule{}
+	for rows.Next() {
+		schedule := &FeeSchedule{}
+		if err := rows.Scan(&schedule.ID, &schedule.GradeID, &schedule.Category, &schedule.Code,
+			&schedule.Kind, &schedule.Side, &schedule.Basis, &schedule.Rate, &schedule.Currency,
+			&schedule.EffectiveDate, &schedule.UpdatedBy, &schedule.UpdatedAt); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
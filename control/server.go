@@ -3,8 +3,10 @@ package control
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
@@ -16,6 +18,7 @@ import (
 	pb "github.com/Asif-Faizal/SpiceLedger-Backend/control/pb"
 	"github.com/Asif-Faizal/SpiceLedger-Backend/internal/platform"
 	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+	"github.com/shopspring/decimal"
 )
 
 type GrpcServer struct {
@@ -25,7 +28,7 @@ type GrpcServer struct {
 	pb.UnimplementedControlServiceServer
 }
 
-func ListenGrpcServer(service Service, logger util.Logger, config *util.Config) error {
+func ListenGrpcServer(service Service, keys util.KeyResolver, logger util.Logger, config *util.Config) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", config.ControlGrpcPort))
 	if err != nil {
 		return err
@@ -34,9 +37,14 @@ func ListenGrpcServer(service Service, logger util.Logger, config *util.Config)
 	grpcServer := grpc.NewServer(
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			util.UnaryServerInterceptor(logger),
-			util.AuthInterceptor(config.JWTSecret, config.BasicAuthUser, config.BasicAuthPass),
+			util.AuthInterceptor(keys, nil, config.BasicAuthUser, config.BasicAuthPass),
 			SessionInterceptor(service, logger),
 		)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
+			util.StreamServerInterceptor(logger),
+			util.StreamAuthInterceptor(keys, nil, config.BasicAuthUser, config.BasicAuthPass),
+			StreamSessionInterceptor(service, logger),
+		)),
 	)
 
 	server := &GrpcServer{
@@ -63,15 +71,42 @@ func SessionInterceptor(service Service, logger util.Logger) grpc.UnaryServerInt
 			accountService := service.(*AccountService)
 			session, err := accountService.repository.GetSessionByAccessToken(ctx, accessToken)
 			if err != nil || session == nil || session.IsRevoked {
-				logger.Transport().Warn().Str("token", accessToken).Msg("Rejected revoked or missing session")
+				logger.Transport().Warn().Str("token_hash", util.HashToken(accessToken)).Msg("Rejected revoked or missing session")
 				return nil, status.Error(codes.Unauthenticated, "session revoked or invalid")
 			}
+			ip, userAgent := util.ClientFromContext(ctx)
+			accountService.touchSession(ctx, session, SessionClient{IPAddress: ip, UserAgent: userAgent})
 		}
 
 		return handler(ctx, req)
 	}
 }
 
+// StreamSessionInterceptor applies the SessionInterceptor check to streaming calls.
+func StreamSessionInterceptor(service Service, logger util.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx := ss.Context()
+		accessToken, _ := ctx.Value(util.AccessTokenKey).(string)
+		if accessToken != "" {
+			accountService := service.(*AccountService)
+			session, err := accountService.repository.GetSessionByAccessToken(ctx, accessToken)
+			if err != nil || session == nil || session.IsRevoked {
+				logger.Transport().Warn().Str("token_hash", util.HashToken(accessToken)).Msg("Rejected revoked or missing session")
+				return status.Error(codes.Unauthenticated, "session revoked or invalid")
+			}
+			ip, userAgent := util.ClientFromContext(ctx)
+			accountService.touchSession(ctx, session, SessionClient{IPAddress: ip, UserAgent: userAgent})
+		}
+
+		return handler(srv, ss)
+	}
+}
+
 func (server *GrpcServer) checkAdmin(ctx context.Context) error {
 	isAdmin, ok := ctx.Value(util.IsAdminKey).(bool)
 	if !ok || !isAdmin {
@@ -133,21 +168,25 @@ func (server *GrpcServer) CreateOrUpdateAccount(ctx context.Context, request *pb
 	}
 
 	account, err := server.accountService.CreateOrUpdateAccount(ctx, &Account{
-		ID:       request.Id,
-		Name:     request.Name,
-		UserType: request.Usertype,
-		Email:    request.Email,
-		Password: request.Password,
+		ID:                request.Id,
+		Name:              request.Name,
+		UserType:          request.Usertype,
+		Email:             request.Email,
+		Password:          request.Password,
+		Currency:          request.Currency,
+		ReportingCurrency: request.ReportingCurrency,
 	})
 	if err != nil {
 		return nil, err
 	}
 	return &pb.CreateOrUpdateAccountResponse{
 		Account: &pb.Account{
-			Id:       account.ID,
-			Name:     account.Name,
-			Usertype: account.UserType,
-			Email:    account.Email,
+			Id:                account.ID,
+			Name:              account.Name,
+			Usertype:          account.UserType,
+			Email:             account.Email,
+			Currency:          account.Currency,
+			ReportingCurrency: account.ReportingCurrency,
 		},
 	}, nil
 }
@@ -162,10 +201,13 @@ func (server *GrpcServer) GetAccountByID(ctx context.Context, request *pb.GetAcc
 	}
 	return &pb.GetAccountByIDResponse{
 		Account: &pb.Account{
-			Id:       account.ID,
-			Name:     account.Name,
-			Usertype: account.UserType,
-			Email:    account.Email,
+			Id:                account.ID,
+			Name:              account.Name,
+			Usertype:          account.UserType,
+			Email:             account.Email,
+			Currency:          account.Currency,
+			ReportingCurrency: account.ReportingCurrency,
+			EmailVerified:     !account.EmailVerifiedAt.IsZero(),
 		},
 	}, nil
 }
@@ -184,10 +226,13 @@ func (server *GrpcServer) GetAccountInfo(ctx context.Context, request *pb.GetAcc
 	}
 	return &pb.GetAccountByIDResponse{
 		Account: &pb.Account{
-			Id:       account.ID,
-			Name:     account.Name,
-			Usertype: account.UserType,
-			Email:    account.Email,
+			Id:                account.ID,
+			Name:              account.Name,
+			Usertype:          account.UserType,
+			Email:             account.Email,
+			Currency:          account.Currency,
+			ReportingCurrency: account.ReportingCurrency,
+			EmailVerified:     !account.EmailVerifiedAt.IsZero(),
 		},
 	}, nil
 }
@@ -203,10 +248,13 @@ func (server *GrpcServer) ListAccounts(ctx context.Context, request *pb.ListAcco
 	accounts := []*pb.Account{}
 	for _, account := range domainAccounts {
 		accounts = append(accounts, &pb.Account{
-			Id:       account.ID,
-			Name:     account.Name,
-			Usertype: account.UserType,
-			Email:    account.Email,
+			Id:                account.ID,
+			Name:              account.Name,
+			Usertype:          account.UserType,
+			Email:             account.Email,
+			Currency:          account.Currency,
+			ReportingCurrency: account.ReportingCurrency,
+			EmailVerified:     !account.EmailVerifiedAt.IsZero(),
 		})
 	}
 	return &pb.ListAccountsResponse{Accounts: accounts}, nil
@@ -216,26 +264,40 @@ func (server *GrpcServer) Login(ctx context.Context, request *pb.LoginRequest) (
 	if err := server.checkAuthenticated(ctx); err != nil {
 		return nil, err
 	}
-	resp, err := server.accountService.Login(ctx, request.Email, request.Password, request.DeviceId)
+	ip, userAgent := util.ClientFromContext(ctx)
+	resp, err := server.accountService.Login(ctx, request.Email, request.Password, request.DeviceId, SessionClient{
+		DeviceName: request.DeviceName,
+		IPAddress:  ip,
+		UserAgent:  userAgent,
+	})
 	if err != nil {
 		return nil, err
 	}
+	return toLoginResponse(resp), nil
+}
 
+func toLoginResponse(resp *AuthenticatedResponse) *pb.LoginResponse {
 	var account *pb.Account
 	if resp.Account != nil {
 		account = &pb.Account{
-			Id:       resp.Account.ID,
-			Name:     resp.Account.Name,
-			Usertype: resp.Account.UserType,
-			Email:    resp.Account.Email,
+			Id:                resp.Account.ID,
+			Name:              resp.Account.Name,
+			Usertype:          resp.Account.UserType,
+			Email:             resp.Account.Email,
+			Currency:          resp.Account.Currency,
+			ReportingCurrency: resp.Account.ReportingCurrency,
+			EmailVerified:     !resp.Account.EmailVerifiedAt.IsZero(),
 		}
 	}
 
 	return &pb.LoginResponse{
-		Account:      account,
-		AccessToken:  resp.AccessToken,
-		RefreshToken: resp.RefreshToken,
-	}, nil
+		Account:            account,
+		AccessToken:        resp.AccessToken,
+		RefreshToken:       resp.RefreshToken,
+		TwoFactorRequired:  resp.ChallengeToken != "",
+		ChallengeToken:     resp.ChallengeToken,
+		EnrollmentRequired: resp.EnrollmentRequired,
+	}
 }
 
 func (server *GrpcServer) Logout(ctx context.Context, request *pb.LogoutRequest) (*pb.LogoutResponse, error) {
@@ -252,7 +314,14 @@ func (server *GrpcServer) RefreshToken(ctx context.Context, request *pb.RefreshT
 	if err := server.checkAuthenticated(ctx); err != nil {
 		return nil, err
 	}
-	resp, err := server.accountService.RefreshToken(ctx, request.RefreshToken, request.DeviceId)
+	ip, userAgent := util.ClientFromContext(ctx)
+	resp, err := server.accountService.RefreshToken(ctx, request.RefreshToken, request.DeviceId, SessionClient{
+		IPAddress: ip,
+		UserAgent: userAgent,
+	})
+	if errors.Is(err, ErrRefreshTokenReused) || errors.Is(err, ErrTwoFactorRequired) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -260,10 +329,13 @@ func (server *GrpcServer) RefreshToken(ctx context.Context, request *pb.RefreshT
 	var account *pb.Account
 	if resp.Account != nil {
 		account = &pb.Account{
-			Id:       resp.Account.ID,
-			Name:     resp.Account.Name,
-			Usertype: resp.Account.UserType,
-			Email:    resp.Account.Email,
+			Id:                resp.Account.ID,
+			Name:              resp.Account.Name,
+			Usertype:          resp.Account.UserType,
+			Email:             resp.Account.Email,
+			Currency:          resp.Account.Currency,
+			ReportingCurrency: resp.Account.ReportingCurrency,
+			EmailVerified:     !resp.Account.EmailVerifiedAt.IsZero(),
 		}
 	}
 
@@ -274,6 +346,257 @@ func (server *GrpcServer) RefreshToken(ctx context.Context, request *pb.RefreshT
 	}, nil
 }
 
+// callerSession returns the signed-in account and access token of a Bearer call.
+func (server *GrpcServer) callerSession(ctx context.Context) (string, string, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return "", "", err
+	}
+	accountID, _ := ctx.Value(util.AccountIDKey).(string)
+	accessToken, _ := ctx.Value(util.AccessTokenKey).(string)
+	if accountID == "" || accessToken == "" {
+		return "", "", status.Error(codes.Unauthenticated, "sessions are managed with a signed-in account")
+	}
+	return accountID, accessToken, nil
+}
+
+func (server *GrpcServer) ListSessions(ctx context.Context, request *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	accountID, accessToken, err := server.callerSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := server.accountService.ListSessions(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	protoSessions := make([]*pb.Session, len(sessions))
+	for i, session := range sessions {
+		protoSessions[i] = &pb.Session{
+			Id:         session.ID,
+			DeviceId:   session.DeviceID,
+			DeviceName: session.DeviceName,
+			IpAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.CreatedAt.Format("2006-01-02 15:04:05"),
+			LastSeenAt: session.LastSeenAt.Format("2006-01-02 15:04:05"),
+			ExpiresAt:  session.ExpiresAt.Format("2006-01-02 15:04:05"),
+			Current:    session.AccessToken == accessToken,
+		}
+	}
+	return &pb.ListSessionsResponse{Sessions: protoSessions}, nil
+}
+
+func (server *GrpcServer) RevokeSession(ctx context.Context, request *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	accountID, _, err := server.callerSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := server.accountService.RevokeSession(ctx, accountID, request.SessionId); err != nil {
+		return nil, err
+	}
+	return &pb.RevokeSessionResponse{Success: true}, nil
+}
+
+func (server *GrpcServer) RevokeAllOtherSessions(ctx context.Context, request *pb.RevokeAllOtherSessionsRequest) (*pb.RevokeSessionsResponse, error) {
+	accountID, accessToken, err := server.callerSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := server.accountService.RevokeAllOtherSessions(ctx, accountID, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RevokeSessionsResponse{Revoked: revoked}, nil
+}
+
+func (server *GrpcServer) ForceLogout(ctx context.Context, request *pb.ForceLogoutRequest) (*pb.RevokeSessionsResponse, error) {
+	if err := server.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	adminID, _ := ctx.Value(util.AccountIDKey).(string)
+	revoked, err := server.accountService.ForceLogout(ctx, request.AccountId, adminID)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RevokeSessionsResponse{Revoked: revoked}, nil
+}
+
+func (server *GrpcServer) RequestPasswordReset(ctx context.Context, request *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	if err := server.accountService.RequestPasswordReset(ctx, request.Email); err != nil {
+		return nil, err
+	}
+	return &pb.RequestPasswordResetResponse{Success: true}, nil
+}
+
+func (server *GrpcServer) ConfirmPasswordReset(ctx context.Context, request *pb.ConfirmPasswordResetRequest) (*pb.ConfirmPasswordResetResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	revoked, err := server.accountService.ConfirmPasswordReset(ctx, request.Token, request.NewPassword, request.RevokeSessions)
+	if errors.Is(err, ErrInvalidAccountToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ConfirmPasswordResetResponse{Success: true, SessionsRevoked: revoked}, nil
+}
+
+func (server *GrpcServer) SendVerificationEmail(ctx context.Context, request *pb.SendVerificationEmailRequest) (*pb.SendVerificationEmailResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	accountID, ok := ctx.Value(util.AccountIDKey).(string)
+	if !ok || accountID == "" {
+		return nil, status.Error(codes.Unauthenticated, "account id not found in context")
+	}
+	if err := server.accountService.SendVerificationEmail(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return &pb.SendVerificationEmailResponse{Success: true}, nil
+}
+
+func (server *GrpcServer) VerifyEmail(ctx context.Context, request *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	account, err := server.accountService.VerifyEmail(ctx, request.Token)
+	if errors.Is(err, ErrInvalidAccountToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.VerifyEmailResponse{
+		Account: &pb.Account{
+			Id:                account.ID,
+			Name:              account.Name,
+			Usertype:          account.UserType,
+			Email:             account.Email,
+			Currency:          account.Currency,
+			ReportingCurrency: account.ReportingCurrency,
+			EmailVerified:     !account.EmailVerifiedAt.IsZero(),
+		},
+	}, nil
+}
+
+// twoFactorStatus maps the login challenge and code errors to Unauthenticated.
+func twoFactorStatus(err error) error {
+	if errors.Is(err, ErrInvalidLoginChallenge) || errors.Is(err, ErrInvalidTwoFactorCode) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return err
+}
+
+// EnrollTOTP and ConfirmTOTP take either a signed-in account or the challenge token of an
+// admin that must enrol before signing in.
+func (server *GrpcServer) EnrollTOTP(ctx context.Context, request *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	accountID, _ := ctx.Value(util.AccountIDKey).(string)
+	secret, uri, err := server.accountService.EnrollTOTP(ctx, accountID, request.ChallengeToken, request.DeviceId)
+	if err != nil {
+		return nil, twoFactorStatus(err)
+	}
+	return &pb.EnrollTOTPResponse{Secret: secret, ProvisioningUri: uri}, nil
+}
+
+func (server *GrpcServer) ConfirmTOTP(ctx context.Context, request *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	accountID, _ := ctx.Value(util.AccountIDKey).(string)
+	accessToken, _ := ctx.Value(util.AccessTokenKey).(string)
+	ip, userAgent := util.ClientFromContext(ctx)
+	recoveryCodes, resp, err := server.accountService.ConfirmTOTP(ctx, accountID, accessToken, request.ChallengeToken, request.DeviceId, request.Code, SessionClient{
+		DeviceName: request.DeviceName,
+		IPAddress:  ip,
+		UserAgent:  userAgent,
+	})
+	if err != nil {
+		return nil, twoFactorStatus(err)
+	}
+	response := &pb.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}
+	if resp != nil {
+		response.Login = toLoginResponse(resp)
+	}
+	return response, nil
+}
+
+func (server *GrpcServer) VerifyLoginChallenge(ctx context.Context, request *pb.VerifyLoginChallengeRequest) (*pb.LoginResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	ip, userAgent := util.ClientFromContext(ctx)
+	resp, err := server.accountService.VerifyLoginChallenge(ctx, request.ChallengeToken, request.DeviceId, request.Code, request.RecoveryCode, SessionClient{
+		DeviceName: request.DeviceName,
+		IPAddress:  ip,
+		UserAgent:  userAgent,
+	})
+	if err != nil {
+		return nil, twoFactorStatus(err)
+	}
+	return toLoginResponse(resp), nil
+}
+
+func (server *GrpcServer) DisableTOTP(ctx context.Context, request *pb.DisableTOTPRequest) (*pb.DisableTOTPResponse, error) {
+	accountID, accessToken, err := server.callerSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := server.accountService.DisableTOTP(ctx, accountID, accessToken, request.Code, request.RecoveryCode); err != nil {
+		return nil, twoFactorStatus(err)
+	}
+	return &pb.DisableTOTPResponse{Success: true}, nil
+}
+
+func (server *GrpcServer) RegenerateRecoveryCodes(ctx context.Context, request *pb.RegenerateRecoveryCodesRequest) (*pb.RegenerateRecoveryCodesResponse, error) {
+	accountID, _, err := server.callerSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recoveryCodes, err := server.accountService.RegenerateRecoveryCodes(ctx, accountID, request.Code)
+	if err != nil {
+		return nil, twoFactorStatus(err)
+	}
+	return &pb.RegenerateRecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// GetJWKS is public: it returns only public keys, and the other services call it before they
+// can verify anyone.
+func (server *GrpcServer) GetJWKS(ctx context.Context, request *pb.GetJWKSRequest) (*pb.GetJWKSResponse, error) {
+	keys := server.accountService.SigningKeys(ctx)
+	response := &pb.GetJWKSResponse{Keys: make([]*pb.JSONWebKey, 0, len(keys))}
+	for _, key := range keys {
+		response.Keys = append(response.Keys, &pb.JSONWebKey{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+	return response, nil
+}
+
+// CheckSession needs no caller auth: the token it is asked about is the credential.
+func (server *GrpcServer) CheckSession(ctx context.Context, request *pb.CheckSessionRequest) (*pb.CheckSessionResponse, error) {
+	if request.AccessToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "access_token is required")
+	}
+	active, err := server.accountService.CheckSession(ctx, request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CheckSessionResponse{Active: active}, nil
+}
+
 func (server *GrpcServer) CreateOrUpdateMerchantDetails(ctx context.Context, request *pb.CreateOrUpdateMerchantDetailsRequest) (*pb.CreateOrUpdateMerchantDetailsResponse, error) {
 	if err := server.checkMerchant(ctx); err != nil {
 		return nil, err
@@ -442,23 +765,33 @@ func (server *GrpcServer) CreateOrUpdateGrade(ctx context.Context, request *pb.C
 	if err := server.checkAdmin(ctx); err != nil {
 		return nil, err
 	}
+	kgPerUnit, err := util.ParseDecimal("kg_per_unit", request.KgPerUnit)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	grade, err := server.accountService.CreateOrUpdateGrade(ctx, &Grade{
-		ID:          request.Id,
-		ProductID:   request.ProductId,
-		Name:        request.Name,
-		Description: request.Description,
-		Status:      request.Status,
+		ID:            request.Id,
+		ProductID:     request.ProductId,
+		Name:          request.Name,
+		Description:   request.Description,
+		Status:        request.Status,
+		ShelfLifeDays: int(request.ShelfLifeDays),
+		Unit:          request.Unit,
+		KgPerUnit:     kgPerUnit,
 	})
 	if err != nil {
 		return nil, err
 	}
 	return &pb.CreateOrUpdateGradeResponse{
 		Grade: &pb.Grade{
-			Id:          grade.ID,
-			ProductId:   grade.ProductID,
-			Name:        grade.Name,
-			Description: grade.Description,
-			Status:      grade.Status,
+			Id:            grade.ID,
+			ProductId:     grade.ProductID,
+			Name:          grade.Name,
+			Description:   grade.Description,
+			Status:        grade.Status,
+			ShelfLifeDays: uint32(grade.ShelfLifeDays),
+			Unit:          grade.Unit,
+			KgPerUnit:     grade.KgPerUnit.String(),
 		},
 	}, nil
 }
@@ -474,11 +807,14 @@ func (server *GrpcServer) ListGradesByProductId(ctx context.Context, request *pb
 	protoGrades := make([]*pb.Grade, len(grades))
 	for i, g := range grades {
 		protoGrades[i] = &pb.Grade{
-			Id:          g.ID,
-			ProductId:   g.ProductID,
-			Name:        g.Name,
-			Description: g.Description,
-			Status:      g.Status,
+			Id:            g.ID,
+			ProductId:     g.ProductID,
+			Name:          g.Name,
+			Description:   g.Description,
+			Status:        g.Status,
+			ShelfLifeDays: uint32(g.ShelfLifeDays),
+			Unit:          g.Unit,
+			KgPerUnit:     g.KgPerUnit.String(),
 		}
 	}
 	return &pb.ListGradesByProductIdResponse{
@@ -500,29 +836,150 @@ func (server *GrpcServer) CreateOrUpdateDailyPrice(ctx context.Context, request
 		t = time.Now()
 	}
 
-	dailyPrice, err := server.accountService.CreateOrUpdateDailyPrice(ctx, &DailyPrice{
-		ID:        request.Id,
-		ProductID: request.ProductId,
-		GradeID:   request.GradeId,
-		Price:     request.Price,
-		Date:      date,
-		Time:      t,
-	})
+	price, err := util.ParseDecimal("price", request.Price)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	publishedBy, _ := ctx.Value(util.AccountIDKey).(string)
+	tick, err := server.accountService.ProposePriceTick(ctx, &PriceTick{
+		ID:          request.Id,
+		ProductID:   request.ProductId,
+		GradeID:     request.GradeId,
+		Price:       price,
+		Currency:    request.Currency,
+		Source:      request.Source,
+		PublishedBy: publishedBy,
+		TickedAt:    time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local),
+	}, request.Draft)
 	if err != nil {
 		return nil, err
 	}
 	return &pb.CreateOrUpdateDailyPriceResponse{
-		DailyPrice: &pb.DailyPrice{
-			Id:        dailyPrice.ID,
-			ProductId: dailyPrice.ProductID,
-			GradeId:   dailyPrice.GradeID,
-			Price:     dailyPrice.Price,
-			Date:      dailyPrice.Date.Format("2006-01-02"),
-			Time:      dailyPrice.Time.Format("15:04:05"),
-		},
+		Tick: priceTickToProto(tick),
 	}, nil
 }
 
+func (server *GrpcServer) SubmitPriceTick(ctx context.Context, request *pb.SubmitPriceTickRequest) (*pb.SubmitPriceTickResponse, error) {
+	if err := server.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	accountID, _ := ctx.Value(util.AccountIDKey).(string)
+	tick, err := server.accountService.SubmitPriceTick(ctx, request.Id, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SubmitPriceTickResponse{Tick: priceTickToProto(tick)}, nil
+}
+
+func (server *GrpcServer) ReviewPriceTicks(ctx context.Context, request *pb.ReviewPriceTicksRequest) (*pb.ReviewPriceTicksResponse, error) {
+	if err := server.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	reviewerID, _ := ctx.Value(util.AccountIDKey).(string)
+	ticks, dailyPrices, err := server.accountService.ReviewPriceTicks(ctx, request.Ids, request.Decision, reviewerID, request.Note)
+	if err != nil {
+		return nil, err
+	}
+	protoTicks := make([]*pb.PriceTick, len(ticks))
+	for i, t := range ticks {
+		protoTicks[i] = priceTickToProto(t)
+	}
+	return &pb.ReviewPriceTicksResponse{
+		Ticks:       protoTicks,
+		DailyPrices: dailyPricesToProto(dailyPrices),
+	}, nil
+}
+
+func (server *GrpcServer) CreateOrUpdateDailyPrices(ctx context.Context, request *pb.CreateOrUpdateDailyPricesRequest) (*pb.CreateOrUpdateDailyPricesResponse, error) {
+	if err := server.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	var options PriceImportOptions
+	options.DryRun = request.DryRun
+	if request.MaxMovePercent != "" {
+		maxMove, err := decimal.NewFromString(request.MaxMovePercent)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "max_move_percent must be a decimal number")
+		}
+		options.MaxMovePercent = decimal.NewNullDecimal(maxMove)
+	}
+
+	// Unlike the single-price call, a bad date or time rejects the row instead of
+	// defaulting to now; only empty values default.
+	publishedBy, _ := ctx.Value(util.AccountIDKey).(string)
+	now := time.Now()
+	rows := make([]*PriceImportRow, len(request.Prices))
+	for i, p := range request.Prices {
+		row := &PriceImportRow{
+			Row: i + 1,
+			Tick: PriceTick{
+				ID:          p.Id,
+				ProductID:   p.ProductId,
+				GradeID:     p.GradeId,
+				Currency:    p.Currency,
+				Source:      p.Source,
+				PublishedBy: publishedBy,
+			},
+		}
+		rows[i] = row
+		reject := func(err error) {
+			row.Status = PriceImportError
+			row.Error = err.Error()
+		}
+
+		price, err := util.ParseDecimal("price", p.Price)
+		if err != nil {
+			reject(err)
+			continue
+		}
+		row.Tick.Price = price
+		date, t := now, now
+		if p.Date != "" {
+			if date, err = time.Parse("2006-01-02", p.Date); err != nil {
+				reject(errors.New("date must be YYYY-MM-DD"))
+				continue
+			}
+		}
+		if p.Time != "" {
+			if t, err = time.Parse("15:04:05", p.Time); err != nil {
+				reject(errors.New("time must be HH:MM:SS"))
+				continue
+			}
+		}
+		row.Tick.TickedAt = time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+	}
+
+	report, err := server.accountService.ImportPriceTicks(ctx, rows, options)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.CreateOrUpdateDailyPricesResponse{
+		Applied:        report.Applied,
+		DryRun:         report.DryRun,
+		MaxMovePercent: report.MaxMovePercent.String(),
+		Accepted:       int32(report.Accepted),
+		Rejected:       int32(report.Rejected),
+		Rows:           make([]*pb.PriceImportRow, len(report.Rows)),
+	}
+	for i, row := range report.Rows {
+		out := &pb.PriceImportRow{
+			Row:         int32(row.Row),
+			ProductId:   row.Tick.ProductID,
+			GradeId:     row.Tick.GradeID,
+			Price:       request.Prices[i].Price,
+			Status:      row.Status,
+			Error:       row.Error,
+			MovePercent: nullDecimalString(row.MovePercent),
+		}
+		if row.Status == PriceImportOK {
+			out.Tick = priceTickToProto(&row.Tick)
+		}
+		response.Rows[i] = out
+	}
+	return response, nil
+}
+
 func (server *GrpcServer) ListDailyPrices(ctx context.Context, request *pb.ListDailyPricesRequest) (*pb.ListDailyPricesResponse, error) {
 	if err := server.checkAuthenticated(ctx); err != nil {
 		return nil, err
@@ -532,19 +989,8 @@ func (server *GrpcServer) ListDailyPrices(ctx context.Context, request *pb.ListD
 	if err != nil {
 		return nil, err
 	}
-	protoPrices := make([]*pb.DailyPrice, len(prices))
-	for i, p := range prices {
-		protoPrices[i] = &pb.DailyPrice{
-			Id:        p.ID,
-			ProductId: p.ProductID,
-			GradeId:   p.GradeID,
-			Price:     p.Price,
-			Date:      p.Date.Format("2006-01-02"),
-			Time:      p.Time.Format("15:04:05"),
-		}
-	}
 	return &pb.ListDailyPricesResponse{
-		DailyPrices: protoPrices,
+		DailyPrices: dailyPricesToProto(prices),
 	}, nil
 }
 
@@ -553,23 +999,12 @@ func (server *GrpcServer) GetTodaysPrice(ctx context.Context, request *pb.GetTod
 		return nil, err
 	}
 	date, _ := time.Parse("2006-01-02", request.Date)
-	prices, err := server.accountService.GetTodaysByGradeId(ctx, request.GradeId, date)
+	prices, err := server.accountService.GetTodaysByGradeId(ctx, request.GradeId, date, request.PriceBasis)
 	if err != nil {
 		return nil, err
 	}
-	protoPrices := make([]*pb.DailyPrice, len(prices))
-	for i, p := range prices {
-		protoPrices[i] = &pb.DailyPrice{
-			Id:        p.ID,
-			ProductId: p.ProductID,
-			GradeId:   p.GradeID,
-			Price:     p.Price,
-			Date:      p.Date.Format("2006-01-02"),
-			Time:      p.Time.Format("15:04:05"),
-		}
-	}
 	return &pb.GetTodaysPriceResponse{
-		DailyPrices: protoPrices,
+		DailyPrices: dailyPricesToProto(prices),
 	}, nil
 }
 
@@ -578,24 +1013,186 @@ func (server *GrpcServer) GetTodaysByProductId(ctx context.Context, request *pb.
 		return nil, err
 	}
 	date, _ := time.Parse("2006-01-02", request.Date)
-	prices, err := server.accountService.GetTodaysByProductId(ctx, request.ProductId, date)
+	prices, err := server.accountService.GetTodaysByProductId(ctx, request.ProductId, date, request.PriceBasis)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetTodaysByProductIdResponse{
+		DailyPrices: dailyPricesToProto(prices),
+	}, nil
+}
+
+func (server *GrpcServer) ListPriceTicks(ctx context.Context, request *pb.ListPriceTicksRequest) (*pb.ListPriceTicksResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	// Prices awaiting or refused approval are for admins only.
+	priceStatus := request.Status
+	if server.checkAdmin(ctx) != nil {
+		if priceStatus != "" && !strings.EqualFold(priceStatus, PriceApproved) {
+			return nil, status.Error(codes.PermissionDenied, "admin access required")
+		}
+		priceStatus = PriceApproved
+	}
+	date, _ := time.Parse("2006-01-02", request.Date)
+	ticks, err := server.accountService.ListPriceTicks(ctx, request.GradeId, date, priceStatus)
+	if err != nil {
+		return nil, err
+	}
+	protoTicks := make([]*pb.PriceTick, len(ticks))
+	for i, t := range ticks {
+		protoTicks[i] = priceTickToProto(t)
+	}
+	return &pb.ListPriceTicksResponse{Ticks: protoTicks}, nil
+}
+
+func priceTickToProto(t *PriceTick) *pb.PriceTick {
+	return &pb.PriceTick{
+		Id:          t.ID,
+		ProductId:   t.ProductID,
+		GradeId:     t.GradeID,
+		Price:       t.Price.String(),
+		Currency:    t.Currency,
+		Source:      t.Source,
+		PublishedBy: t.PublishedBy,
+		TickedAt:    t.TickedAt.Format("2006-01-02 15:04:05.000000"),
+		Status:      t.Status,
+		SubmittedAt: formatOptionalTime(t.SubmittedAt),
+		ReviewedBy:  t.ReviewedBy,
+		ReviewedAt:  formatOptionalTime(t.ReviewedAt),
+		ReviewNote:  t.ReviewNote,
+	}
+}
+
+// formatOptionalTime formats a tick timestamp; unset (zero) times are empty.
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05.000000")
+}
+
+func (server *GrpcServer) GetPriceCandles(ctx context.Context, request *pb.GetPriceCandlesRequest) (*pb.GetPriceCandlesResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	query := CandleQuery{
+		GradeID:   request.GradeId,
+		ProductID: request.ProductId,
+		Interval:  request.Interval,
+		FillGaps:  request.FillGaps,
+	}
+	var err error
+	if request.DateFrom != "" {
+		if query.From, err = time.Parse("2006-01-02", request.DateFrom); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "date_from must be YYYY-MM-DD")
+		}
+	}
+	if request.DateTo != "" {
+		if query.To, err = time.Parse("2006-01-02", request.DateTo); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "date_to must be YYYY-MM-DD")
+		}
+	}
+
+	series, err := server.accountService.GetPriceCandles(ctx, query)
 	if err != nil {
 		return nil, err
 	}
+	protoSeries := make([]*pb.PriceSeries, len(series))
+	for i, ps := range series {
+		candles := make([]*pb.Candle, len(ps.Candles))
+		for j, c := range ps.Candles {
+			candles[j] = &pb.Candle{
+				PeriodStart:   c.PeriodStart.Format("2006-01-02"),
+				PeriodEnd:     c.PeriodEnd.Format("2006-01-02"),
+				Open:          c.Open.String(),
+				High:          c.High.String(),
+				Low:           c.Low.String(),
+				Close:         c.Close.String(),
+				TickCount:     int32(c.TickCount),
+				ChangePercent: nullDecimalString(c.ChangePercent),
+				Sma_7:         nullDecimalString(c.SMA7),
+				Sma_30:        nullDecimalString(c.SMA30),
+				Filled:        c.Filled,
+			}
+		}
+		protoSeries[i] = &pb.PriceSeries{
+			GradeId:   ps.GradeID,
+			ProductId: ps.ProductID,
+			Interval:  ps.Interval,
+			Candles:   candles,
+		}
+	}
+	return &pb.GetPriceCandlesResponse{Series: protoSeries}, nil
+}
+
+// nullDecimalString writes an unset decimal as an empty string.
+func nullDecimalString(d decimal.NullDecimal) string {
+	if !d.Valid {
+		return ""
+	}
+	return d.Decimal.String()
+}
+
+func dailyPriceToProto(p *DailyPrice) *pb.DailyPrice {
+	return &pb.DailyPrice{
+		Id:        p.ID,
+		ProductId: p.ProductID,
+		GradeId:   p.GradeID,
+		Price:     p.Price.String(),
+		Date:      p.Date.Format("2006-01-02"),
+		Time:      p.Time.Format("15:04:05"),
+		Open:      p.Open.String(),
+		High:      p.High.String(),
+		Low:       p.Low.String(),
+		Last:      p.Last.String(),
+		Close:     nullDecimalString(p.Close),
+		TickCount: int32(p.TickCount),
+		Currency:  p.Currency,
+	}
+}
+
+func dailyPricesToProto(prices []*DailyPrice) []*pb.DailyPrice {
 	protoPrices := make([]*pb.DailyPrice, len(prices))
 	for i, p := range prices {
-		protoPrices[i] = &pb.DailyPrice{
-			Id:        p.ID,
-			ProductId: p.ProductID,
-			GradeId:   p.GradeID,
-			Price:     p.Price,
-			Date:      p.Date.Format("2006-01-02"),
-			Time:      p.Time.Format("15:04:05"),
+		protoPrices[i] = dailyPriceToProto(p)
+	}
+	return protoPrices
+}
+
+// SubscribePrices streams daily prices as they are published, until the client disconnects.
+func (server *GrpcServer) SubscribePrices(request *pb.SubscribePricesRequest, stream pb.ControlService_SubscribePricesServer) error {
+	ctx := stream.Context()
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return err
+	}
+
+	sub, err := server.accountService.SubscribePrices(request.GradeId, request.ProductId, request.Epoch, request.AfterSequence)
+	if errors.Is(err, platform.ErrCursorExpired) {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, sub.Err().Error())
+			}
+			if err := stream.Send(&pb.PriceEvent{
+				Sequence:   ev.Seq,
+				Epoch:      ev.Epoch,
+				DailyPrice: dailyPriceToProto(ev.Payload.(*DailyPrice)),
+			}); err != nil {
+				return err
+			}
 		}
 	}
-	return &pb.GetTodaysByProductIdResponse{
-		DailyPrices: protoPrices,
-	}, nil
 }
 
 func (s *GrpcServer) GetProductsWithGradesAndPrices(ctx context.Context, req *pb.GetProductsWithGradesAndPricesRequest) (*pb.GetProductsWithGradesAndPricesResponse, error) {
@@ -624,12 +1221,16 @@ func (s *GrpcServer) GetProductsWithGradesAndPrices(ctx context.Context, req *pb
 		pbGrades := make([]*pb.GradeWithPrice, len(p.Grades))
 		for j, g := range p.Grades {
 			pbGrades[j] = &pb.GradeWithPrice{
-				Id:          g.ID,
-				ProductId:   g.ProductID,
-				Name:        g.Name,
-				Description: g.Description,
-				Status:      g.Status,
-				Price:       g.Price,
+				Id:            g.ID,
+				ProductId:     g.ProductID,
+				Name:          g.Name,
+				Description:   g.Description,
+				Status:        g.Status,
+				Price:         g.Price.String(),
+				ShelfLifeDays: uint32(g.ShelfLifeDays),
+				Currency:      g.Currency,
+				Unit:          g.Unit,
+				KgPerUnit:     g.KgPerUnit.String(),
 			}
 		}
 		pbProducts[i] = &pb.ProductWithGrades{
@@ -646,3 +1247,125 @@ func (s *GrpcServer) GetProductsWithGradesAndPrices(ctx context.Context, req *pb
 		Products: pbProducts,
 	}, nil
 }
+
+// FX Rates
+func (server *GrpcServer) SetFxRate(ctx context.Context, request *pb.SetFxRateRequest) (*pb.SetFxRateResponse, error) {
+	if err := server.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	rate, err := util.ParseDecimal("rate", request.Rate)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var effectiveDate time.Time
+	if request.EffectiveDate != "" {
+		if effectiveDate, err = time.Parse("2006-01-02", request.EffectiveDate); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "effective_date must be YYYY-MM-DD")
+		}
+	}
+	updatedBy, _ := ctx.Value(util.AccountIDKey).(string)
+	stored, err := server.accountService.SetFxRate(ctx, &FxRate{
+		BaseCurrency:  request.BaseCurrency,
+		QuoteCurrency: request.QuoteCurrency,
+		Rate:          rate,
+		EffectiveDate: effectiveDate,
+		UpdatedBy:     updatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetFxRateResponse{Rate: fxRateToProto(stored)}, nil
+}
+
+func (server *GrpcServer) ListFxRates(ctx context.Context, request *pb.ListFxRatesRequest) (*pb.ListFxRatesResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	rates, err := server.accountService.ListFxRates(ctx, request.BaseCurrency, request.QuoteCurrency)
+	if err != nil {
+		return nil, err
+	}
+	protoRates := make([]*pb.FxRate, len(rates))
+	for i, r := range rates {
+		protoRates[i] = fxRateToProto(r)
+	}
+	return &pb.ListFxRatesResponse{Rates: protoRates}, nil
+}
+
+func fxRateToProto(r *FxRate) *pb.FxRate {
+	return &pb.FxRate{
+		Id:            r.ID,
+		BaseCurrency:  r.BaseCurrency,
+		QuoteCurrency: r.QuoteCurrency,
+		Rate:          r.Rate.String(),
+		EffectiveDate: r.EffectiveDate.Format("2006-01-02"),
+		UpdatedBy:     r.UpdatedBy,
+		UpdatedAt:     r.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// Fee Schedules
+func (server *GrpcServer) SetFeeSchedule(ctx context.Context, request *pb.SetFeeScheduleRequest) (*pb.SetFeeScheduleResponse, error) {
+	if err := server.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	rate, err := util.ParseDecimal("rate", request.Rate)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var effectiveDate time.Time
+	if request.EffectiveDate != "" {
+		if effectiveDate, err = time.Parse("2006-01-02", request.EffectiveDate); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "effective_date must be YYYY-MM-DD")
+		}
+	}
+	updatedBy, _ := ctx.Value(util.AccountIDKey).(string)
+	stored, err := server.accountService.SetFeeSchedule(ctx, &FeeSchedule{
+		GradeID:       request.GradeId,
+		Category:      request.Category,
+		Code:          request.Code,
+		Kind:          request.Kind,
+		Side:          request.Side,
+		Basis:         request.Basis,
+		Rate:          rate,
+		Currency:      request.Currency,
+		EffectiveDate: effectiveDate,
+		UpdatedBy:     updatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetFeeScheduleResponse{Schedule: feeScheduleToProto(stored)}, nil
+}
+
+func (server *GrpcServer) ListFeeSchedules(ctx context.Context, request *pb.ListFeeSchedulesRequest) (*pb.ListFeeSchedulesResponse, error) {
+	if err := server.checkAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+	schedules, err := server.accountService.ListFeeSchedules(ctx, request.GradeId, request.Category)
+	if err != nil {
+		return nil, err
+	}
+	protoSchedules := make([]*pb.FeeSchedule, len(schedules))
+	for i, schedule := range schedules {
+		protoSchedules[i] = feeScheduleToProto(schedule)
+	}
+	return &pb.ListFeeSchedulesResponse{Schedules: protoSchedules}, nil
+}
+
+func feeScheduleToProto(s *FeeSchedule) *pb.FeeSchedule {
+	return &pb.FeeSchedule{
+		Id:            s.ID,
+		GradeId:       s.GradeID,
+		Category:      s.Category,
+		Code:          s.Code,
+		Kind:          s.Kind,
+		Side:          s.Side,
+		Basis:         s.Basis,
+		Rate:          s.Rate.String(),
+		Currency:      s.Currency,
+		EffectiveDate: s.EffectiveDate.Format("2006-01-02"),
+		UpdatedBy:     s.UpdatedBy,
+		UpdatedAt:     s.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
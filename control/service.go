@@ -2,11 +2,16 @@ package control
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/Asif-Faizal/SpiceLedger-Backend/internal/platform"
 	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
 	"github.com/segmentio/ksuid"
+	"github.com/shopspring/decimal"
 )
 
 type Service interface {
@@ -14,9 +19,23 @@ type Service interface {
 	GetAccountByID(ctx context.Context, id string) (*Account, error)
 	ListAccounts(ctx context.Context, skip uint, take uint) ([]*Account, error)
 	CheckEmailExists(ctx context.Context, email string) (bool, error)
-	Login(ctx context.Context, email string, password string, deviceID string) (*AuthenticatedResponse, error)
+	Login(ctx context.Context, email string, password string, deviceID string, client SessionClient) (*AuthenticatedResponse, error)
 	Logout(ctx context.Context, accessToken string, deviceID string) error
-	RefreshToken(ctx context.Context, refreshToken string, deviceID string) (*AuthenticatedResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string, deviceID string, client SessionClient) (*AuthenticatedResponse, error)
+	ListSessions(ctx context.Context, accountID string) ([]*Session, error)
+	RevokeSession(ctx context.Context, accountID string, sessionID string) error
+	RevokeAllOtherSessions(ctx context.Context, accountID string, accessToken string) (uint32, error)
+	ForceLogout(ctx context.Context, accountID string, adminID string) (uint32, error)
+	CheckSession(ctx context.Context, accessToken string) (bool, error)
+	RequestPasswordReset(ctx context.Context, email string) error
+	ConfirmPasswordReset(ctx context.Context, token string, password string, revokeSessions bool) (uint32, error)
+	SendVerificationEmail(ctx context.Context, accountID string) error
+	VerifyEmail(ctx context.Context, token string) (*Account, error)
+	EnrollTOTP(ctx context.Context, accountID string, challengeToken string, deviceID string) (string, string, error)
+	ConfirmTOTP(ctx context.Context, accountID string, accessToken string, challengeToken string, deviceID string, code string, client SessionClient) ([]string, *AuthenticatedResponse, error)
+	VerifyLoginChallenge(ctx context.Context, challengeToken string, deviceID string, code string, recoveryCode string, client SessionClient) (*AuthenticatedResponse, error)
+	DisableTOTP(ctx context.Context, accountID string, accessToken string, code string, recoveryCode string) error
+	RegenerateRecoveryCodes(ctx context.Context, accountID string, code string) ([]string, error)
 	CreateOrUpdateMerchantDetails(ctx context.Context, merchantDetails *MerchantDetails) (*MerchantDetails, error)
 	GetMerchantDetails(ctx context.Context, accountID string) (*MerchantDetails, error)
 
@@ -29,32 +48,90 @@ type Service interface {
 	ListGradesByProductId(ctx context.Context, productId string, skip uint, take uint) ([]*Grade, error)
 
 	// Daily Price
-	CreateOrUpdateDailyPrice(ctx context.Context, dailyPrice *DailyPrice) (*DailyPrice, error)
+	ProposePriceTick(ctx context.Context, tick *PriceTick, draft bool) (*PriceTick, error)
+	SubmitPriceTick(ctx context.Context, id string, accountID string) (*PriceTick, error)
+	ReviewPriceTicks(ctx context.Context, ids []string, decision string, reviewerID string, note string) ([]*PriceTick, []*DailyPrice, error)
+	ImportPriceTicks(ctx context.Context, rows []*PriceImportRow, options PriceImportOptions) (*PriceImportReport, error)
+	ListPriceTicks(ctx context.Context, gradeId string, date time.Time, status string) ([]*PriceTick, error)
 	ListDailyPricesByGradeId(ctx context.Context, gradeId string, today time.Time, duration int) ([]*DailyPrice, error)
-	GetTodaysByGradeId(ctx context.Context, gradeId string, date time.Time) ([]*DailyPrice, error)
-	GetTodaysByProductId(ctx context.Context, productId string, date time.Time) ([]*DailyPrice, error)
+	GetTodaysByGradeId(ctx context.Context, gradeId string, date time.Time, basis string) ([]*DailyPrice, error)
+	GetTodaysByProductId(ctx context.Context, productId string, date time.Time, basis string) ([]*DailyPrice, error)
+	GetPriceCandles(ctx context.Context, query CandleQuery) ([]*PriceSeries, error)
 	GetProductsWithGradesAndPrices(ctx context.Context, date time.Time, search string) ([]*ProductWithGrades, error)
+	SubscribePrices(gradeId string, productId string, epoch string, afterSequence uint64) (*platform.Subscription, error)
 	GetSystemMetrics(ctx context.Context) (uint32, uint32, error)
+	SigningKeys(ctx context.Context) []util.JWK
+
+	// FX Rates
+	SetFxRate(ctx context.Context, rate *FxRate) (*FxRate, error)
+	ListFxRates(ctx context.Context, baseCurrency string, quoteCurrency string) ([]*FxRate, error)
+
+	// Fee schedules
+	SetFeeSchedule(ctx context.Context, schedule *FeeSchedule) (*FeeSchedule, error)
+	ListFeeSchedules(ctx context.Context, gradeID string, category string) ([]*FeeSchedule, error)
 }
 
+// ErrInvalidAccountToken is returned for a password reset or verification token that is
+// unknown, used, expired or for another purpose. The cases are not told apart.
+var ErrInvalidAccountToken = errors.New("invalid or expired token")
+
+// ErrRefreshTokenReused is returned when a rotated refresh token is presented again. The device
+// has been signed out and must log in.
+var ErrRefreshTokenReused = errors.New("refresh token was already used; the device has been signed out")
+
+// ErrInvalidLoginChallenge is returned for a challenge token that is unknown, used, expired,
+// out of attempts, for another device or for the other purpose.
+var ErrInvalidLoginChallenge = errors.New("invalid or expired login challenge")
+
+// ErrInvalidTwoFactorCode is returned for a wrong, reused or missing TOTP or recovery code.
+var ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+// ErrTwoFactorRequired is returned when an admin without TOTP refreshes a session while 2FA is
+// mandatory for admins. Signing in again leads them through enrolment.
+var ErrTwoFactorRequired = errors.New("two-factor authentication is required: sign in again to enrol")
+
 type AccountService struct {
 	repository         Repository
-	jwtSecret          string
+	keys               *KeyRing
 	accessTokenExpiry  time.Duration
 	refreshTokenExpiry time.Duration
+	events             *platform.EventBus
+	maxPriceMove       decimal.Decimal
+	mailer             platform.Mailer
+	appURL             string
+	passwordResetTTL   time.Duration
+	verificationTTL    time.Duration
+	twoFactor          TwoFactorPolicy
+	logger             util.Logger
 }
 
 func NewAccountService(
 	repository Repository,
-	jwtSecret string,
+	keys *KeyRing,
 	accessTokenExpiry time.Duration,
 	refreshTokenExpiry time.Duration,
+	events *platform.EventBus,
+	maxPriceMove decimal.Decimal,
+	mailer platform.Mailer,
+	appURL string,
+	passwordResetTTL time.Duration,
+	verificationTTL time.Duration,
+	twoFactor TwoFactorPolicy,
+	logger util.Logger,
 ) *AccountService {
 	return &AccountService{
 		repository:         repository,
-		jwtSecret:          jwtSecret,
+		keys:               keys,
 		accessTokenExpiry:  accessTokenExpiry,
 		refreshTokenExpiry: refreshTokenExpiry,
+		events:             events,
+		maxPriceMove:       maxPriceMove,
+		mailer:             mailer,
+		appURL:             strings.TrimRight(appURL, "/"),
+		passwordResetTTL:   passwordResetTTL,
+		verificationTTL:    verificationTTL,
+		twoFactor:          twoFactor,
+		logger:             logger,
 	}
 }
 
@@ -62,6 +139,11 @@ func (service *AccountService) GetSystemMetrics(ctx context.Context) (uint32, ui
 	return service.repository.GetCounts(ctx)
 }
 
+// SigningKeys returns the public keys tokens are verified against.
+func (service *AccountService) SigningKeys(ctx context.Context) []util.JWK {
+	return service.keys.JWKS()
+}
+
 func (service *AccountService) CheckEmailExists(ctx context.Context, email string) (bool, error) {
 	exists, err := service.repository.CheckEmailExists(ctx, email)
 	if err != nil {
@@ -88,11 +170,34 @@ func (service *AccountService) CreateOrUpdateAccount(ctx context.Context, accoun
 		}
 	}
 
+	currency, err := util.ParseCurrency(account.Currency)
+	if err != nil {
+		return nil, err
+	}
+	reportingCurrency, err := util.ParseCurrency(account.ReportingCurrency)
+	if err != nil {
+		return nil, err
+	}
+
 	if id == "" {
 		if account.Password == "" {
 			return nil, errors.New("password is required for new accounts")
 		}
 		id = ksuid.New().String()
+	} else if stored, err := service.repository.GetAccountById(ctx, id); err == nil {
+		// An update that leaves a currency out keeps the stored one.
+		if currency == "" {
+			currency = stored.Currency
+		}
+		if reportingCurrency == "" {
+			reportingCurrency = stored.ReportingCurrency
+		}
+	}
+	if currency == "" {
+		currency = util.DefaultCurrency
+	}
+	if reportingCurrency == "" {
+		reportingCurrency = currency
 	}
 	hashed := ""
 	if account.Password != "" {
@@ -103,11 +208,13 @@ func (service *AccountService) CreateOrUpdateAccount(ctx context.Context, accoun
 		hashed = hash
 	}
 	newAccount := &Account{
-		ID:       id,
-		Name:     account.Name,
-		UserType: account.UserType,
-		Email:    account.Email,
-		Password: hashed,
+		ID:                id,
+		Name:              account.Name,
+		UserType:          account.UserType,
+		Email:             account.Email,
+		Password:          hashed,
+		Currency:          currency,
+		ReportingCurrency: reportingCurrency,
 	}
 	if _, err := service.repository.CreateOrUpdateAccount(ctx, newAccount); err != nil {
 		return nil, err
@@ -134,7 +241,7 @@ func (service *AccountService) ListAccounts(ctx context.Context, skip uint, take
 	return accounts, nil
 }
 
-func (service *AccountService) Login(ctx context.Context, email string, password string, deviceID string) (*AuthenticatedResponse, error) {
+func (service *AccountService) Login(ctx context.Context, email string, password string, deviceID string, client SessionClient) (*AuthenticatedResponse, error) {
 	account, err := service.repository.GetAccountByEmail(ctx, email)
 	if err != nil {
 		return nil, errors.New("invalid email or password")
@@ -144,28 +251,85 @@ func (service *AccountService) Login(ctx context.Context, email string, password
 		return nil, errors.New("invalid email or password")
 	}
 
-	accessToken, err := util.GenerateToken(account.ID, account.UserType, account.Email, service.jwtSecret, service.accessTokenExpiry)
+	// With 2FA the password only earns a challenge; the session is started once it is met.
+	enrollment, err := service.repository.GetTOTP(ctx, account.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if enrollment != nil && !enrollment.ConfirmedAt.IsZero() {
+		return service.startLoginChallenge(ctx, account, deviceID, client, ChallengeVerify)
+	}
+	if account.UserType == util.UserTypeAdmin && service.twoFactor.RequiredForAdmins {
+		return service.startLoginChallenge(ctx, account, deviceID, client, ChallengeEnroll)
+	}
+
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	resp, err := service.createSession(txCtx, account, deviceID, client)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// createSession signs the account in on the device: it issues an access and a refresh token
+// and stores the session and a new token family. It runs in the caller's transaction.
+func (service *AccountService) createSession(txCtx context.Context, account *Account, deviceID string, client SessionClient) (*AuthenticatedResponse, error) {
+	accessToken, err := util.GenerateToken(account.ID, account.UserType, account.Email, util.TokenTypeAccess, service.keys.Signer(), service.accessTokenExpiry)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := util.GenerateToken(account.ID, account.UserType, account.Email, service.jwtSecret, service.refreshTokenExpiry)
+	refreshToken, err := util.GenerateToken(account.ID, account.UserType, account.Email, util.TokenTypeRefresh, service.keys.Signer(), service.refreshTokenExpiry)
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
+	client = normalizeSessionClient(client)
 	session := &Session{
-		ID:           ksuid.New().String(),
-		AccountID:    account.ID,
-		DeviceID:     deviceID,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresAt:    time.Now().Add(service.refreshTokenExpiry),
-		CreatedAt:    time.Now(),
-		IsRevoked:    false,
+		ID:          ksuid.New().String(),
+		AccountID:   account.ID,
+		DeviceID:    deviceID,
+		DeviceName:  client.DeviceName,
+		FamilyID:    ksuid.New().String(),
+		AccessToken: accessToken,
+		IPAddress:   client.IPAddress,
+		UserAgent:   client.UserAgent,
+		ExpiresAt:   now.Add(service.refreshTokenExpiry),
+		CreatedAt:   now,
+		LastSeenAt:  now,
+		IsRevoked:   false,
 	}
 
-	if err := service.repository.CreateOrUpdateSession(ctx, session); err != nil {
+	// A new login replaces the device's session, so refresh tokens from earlier logins on the
+	// device stop working.
+	if err = service.repository.RevokeDeviceRefreshTokens(txCtx, account.ID, deviceID, RevokeSuperseded, now); err != nil {
+		return nil, err
+	}
+	if err = service.repository.CreateOrUpdateSession(txCtx, session); err != nil {
+		return nil, err
+	}
+	if err = service.repository.CreateRefreshToken(txCtx, &RefreshToken{
+		ID:        ksuid.New().String(),
+		FamilyID:  session.FamilyID,
+		AccountID: account.ID,
+		DeviceID:  deviceID,
+		TokenHash: util.HashToken(refreshToken),
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: now,
+	}); err != nil {
 		return nil, err
 	}
 
@@ -178,7 +342,7 @@ func (service *AccountService) Login(ctx context.Context, email string, password
 
 func (service *AccountService) Logout(ctx context.Context, accessToken string, deviceID string) error {
 	// 1. Validate Access Token
-	_, err := util.ValidateToken(accessToken, service.jwtSecret)
+	_, err := util.ValidateToken(ctx, accessToken, util.TokenTypeAccess, service.keys)
 	if err != nil {
 		return errors.New("invalid or expired access token")
 	}
@@ -193,27 +357,45 @@ func (service *AccountService) Logout(ctx context.Context, accessToken string, d
 		return errors.New("device mismatch")
 	}
 
+	if err := service.repository.RevokeRefreshFamily(ctx, session.FamilyID, RevokeLogout, time.Now()); err != nil {
+		return err
+	}
 	return service.repository.RevokeSessionByAccessToken(ctx, accessToken)
 }
 
-func (service *AccountService) RefreshToken(ctx context.Context, refreshToken string, deviceID string) (*AuthenticatedResponse, error) {
+// RefreshToken rotates a refresh token: the presented token is marked rotated and a new one in
+// the same family is issued. Presenting a token that was already rotated means it was copied,
+// so the device's token families and sessions are revoked and the incident logged.
+func (service *AccountService) RefreshToken(ctx context.Context, refreshToken string, deviceID string, client SessionClient) (*AuthenticatedResponse, error) {
 	// 1. Validate Refresh Token
-	_, err := util.ValidateToken(refreshToken, service.jwtSecret)
+	_, err := util.ValidateToken(ctx, refreshToken, util.TokenTypeRefresh, service.keys)
 	if err != nil {
 		return nil, errors.New("invalid or expired refresh token")
 	}
 
-	// 2. Fetch session and check device
-	session, err := service.repository.GetSessionByRefreshToken(ctx, refreshToken)
+	// 2. Fetch the token and check its state and device
+	token, err := service.repository.GetRefreshTokenByHash(ctx, util.HashToken(refreshToken))
 	if err != nil {
 		return nil, errors.New("invalid or expired refresh token")
 	}
+	if !token.RotatedAt.IsZero() {
+		return nil, service.revokeReusedRefreshToken(ctx, token, deviceID)
+	}
+	if !token.RevokedAt.IsZero() {
+		return nil, errors.New("invalid or expired refresh token")
+	}
 
-	if session.DeviceID != deviceID {
+	if token.DeviceID != deviceID {
 		return nil, errors.New("device mismatch")
 	}
 
-	if session.ExpiresAt.Before(time.Now()) {
+	now := time.Now()
+	session, err := service.repository.GetSessionByFamily(ctx, token.FamilyID)
+	if err != nil {
+		return nil, errors.New("invalid or expired refresh token")
+	}
+	if token.ExpiresAt.Before(now) {
+		_ = service.repository.RevokeRefreshFamily(ctx, token.FamilyID, RevokeExpired, now)
 		session.IsRevoked = true
 		_ = service.repository.CreateOrUpdateSession(ctx, session)
 		return nil, errors.New("refresh token expired")
@@ -223,22 +405,77 @@ func (service *AccountService) RefreshToken(ctx context.Context, refreshToken st
 	if err != nil {
 		return nil, err
 	}
+	// Mandatory 2FA also covers sessions started before it was switched on.
+	if account.UserType == util.UserTypeAdmin && service.twoFactor.RequiredForAdmins {
+		enrollment, err := service.repository.GetTOTP(ctx, account.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if enrollment == nil || enrollment.ConfirmedAt.IsZero() {
+			return nil, ErrTwoFactorRequired
+		}
+	}
+
+	newAccessToken, err := util.GenerateToken(account.ID, account.UserType, account.Email, util.TokenTypeAccess, service.keys.Signer(), service.accessTokenExpiry)
+	if err != nil {
+		return nil, err
+	}
 
-	newAccessToken, err := util.GenerateToken(account.ID, account.UserType, account.Email, service.jwtSecret, service.accessTokenExpiry)
+	newRefreshToken, err := util.GenerateToken(account.ID, account.UserType, account.Email, util.TokenTypeRefresh, service.keys.Signer(), service.refreshTokenExpiry)
 	if err != nil {
 		return nil, err
 	}
 
-	newRefreshToken, err := util.GenerateToken(account.ID, account.UserType, account.Email, service.jwtSecret, service.refreshTokenExpiry)
+	// 3. Rotate within the family
+	txCtx, tx, err := service.repository.BeginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
 
-	session.AccessToken = newAccessToken
-	session.RefreshToken = newRefreshToken
-	session.ExpiresAt = time.Now().Add(service.refreshTokenExpiry)
+	rotated, err := service.repository.RotateRefreshToken(txCtx, token.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another refresh used the token first: one of the two holds a copy.
+		tx.Rollback()
+		return nil, service.revokeReusedRefreshToken(ctx, token, deviceID)
+	}
 
-	if err := service.repository.CreateOrUpdateSession(ctx, session); err != nil {
+	client = normalizeSessionClient(client)
+	session.AccessToken = newAccessToken
+	session.ExpiresAt = now.Add(service.refreshTokenExpiry)
+	session.LastSeenAt = now
+	if client.DeviceName != "" {
+		session.DeviceName = client.DeviceName
+	}
+	if client.IPAddress != "" {
+		session.IPAddress = client.IPAddress
+	}
+	if client.UserAgent != "" {
+		session.UserAgent = client.UserAgent
+	}
+	if err = service.repository.CreateOrUpdateSession(txCtx, session); err != nil {
+		return nil, err
+	}
+	if err = service.repository.CreateRefreshToken(txCtx, &RefreshToken{
+		ID:        ksuid.New().String(),
+		FamilyID:  token.FamilyID,
+		ParentID:  token.ID,
+		AccountID: token.AccountID,
+		DeviceID:  token.DeviceID,
+		TokenHash: util.HashToken(newRefreshToken),
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
 
@@ -249,178 +486,1772 @@ func (service *AccountService) RefreshToken(ctx context.Context, refreshToken st
 	}, nil
 }
 
-func (service *AccountService) CreateOrUpdateMerchantDetails(ctx context.Context, merchantDetails *MerchantDetails) (*MerchantDetails, error) {
-	if merchantDetails.AccountID == "" {
-		return nil, errors.New("account_id is required")
+// revokeReusedRefreshToken handles a rotated refresh token presented again. Either the client
+// or someone holding a copy is replaying it, and there is no telling which, so every token
+// family and session of the account on that device is revoked and both have to sign in again.
+func (service *AccountService) revokeReusedRefreshToken(ctx context.Context, token *RefreshToken, deviceID string) error {
+	service.logger.Security().Warn().
+		Str("event", "refresh_token_reuse").
+		Str("account_id", token.AccountID).
+		Str("device_id", token.DeviceID).
+		Str("presented_device_id", deviceID).
+		Str("family_id", token.FamilyID).
+		Str("token_id", token.ID).
+		Time("rotated_at", token.RotatedAt).
+		Msg("Refresh token reused; revoking the device's token families and sessions")
+
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return err
 	}
-	if merchantDetails.Phone == "" {
-		return nil, errors.New("phone_number is required")
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = service.repository.RevokeDeviceRefreshTokens(txCtx, token.AccountID, token.DeviceID, RevokeReuse, time.Now()); err != nil {
+		return err
 	}
-	if merchantDetails.Address == "" {
-		return nil, errors.New("address is required")
+	if err = service.repository.RevokeDeviceSessions(txCtx, token.AccountID, token.DeviceID); err != nil {
+		return err
 	}
-	if merchantDetails.City == "" {
-		return nil, errors.New("city is required")
+	if err = tx.Commit(); err != nil {
+		return err
 	}
-	if merchantDetails.State == "" {
-		return nil, errors.New("state is required")
+	return ErrRefreshTokenReused
+}
+
+// Column widths of the session's client fields.
+const (
+	maxDeviceNameLen = 100
+	maxIPAddressLen  = 45
+	maxUserAgentLen  = 255
+)
+
+// normalizeSessionClient trims the client fields and cuts them to their column widths.
+func normalizeSessionClient(client SessionClient) SessionClient {
+	return SessionClient{
+		DeviceName: truncateRunes(strings.TrimSpace(client.DeviceName), maxDeviceNameLen),
+		IPAddress:  truncateRunes(strings.TrimSpace(client.IPAddress), maxIPAddressLen),
+		UserAgent:  truncateRunes(strings.TrimSpace(client.UserAgent), maxUserAgentLen),
 	}
-	if merchantDetails.Pincode == "" {
-		return nil, errors.New("pincode is required")
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
 	}
-	id := merchantDetails.ID
+	return string(runes[:n])
+}
 
-	// Check if merchant details already exists for this account
-	existingMerchantDetails, err := service.repository.GetMerchantDetails(ctx, merchantDetails.AccountID)
-	if err == nil && existingMerchantDetails != nil {
-		// Use the existing ID to ensure we update the correct record
-		id = existingMerchantDetails.ID
+// ListSessions returns the account's signed-in devices, most recently seen first.
+func (service *AccountService) ListSessions(ctx context.Context, accountID string) ([]*Session, error) {
+	if accountID == "" {
+		return nil, errors.New("listing sessions requires a signed-in account")
 	}
+	return service.repository.ListSessions(ctx, accountID, time.Now())
+}
 
-	if id == "" {
-		id = ksuid.New().String()
+// RevokeSession signs out one of the account's sessions, which may be the calling one. Its
+// refresh token family is revoked. Control rejects its access token from then on; market
+// does once its session cache expires (see CheckSession).
+func (service *AccountService) RevokeSession(ctx context.Context, accountID string, sessionID string) error {
+	if accountID == "" {
+		return errors.New("revoking a session requires a signed-in account")
 	}
-	newMerchantDetails := &MerchantDetails{
-		ID:        id,
-		AccountID: merchantDetails.AccountID,
-		Phone:     merchantDetails.Phone,
-		Address:   merchantDetails.Address,
-		City:      merchantDetails.City,
-		State:     merchantDetails.State,
-		Pincode:   merchantDetails.Pincode,
+	if sessionID == "" {
+		return errors.New("session_id is required")
 	}
-	if _, err := service.repository.CreateOrUpdateMerchantDetails(ctx, newMerchantDetails); err != nil {
-		return nil, err
+	revoked, err := service.revokeSessions(ctx, accountID, sessionID, "", RevokeSignedOut)
+	if err != nil {
+		return err
 	}
-	return newMerchantDetails, nil
+	if revoked == 0 {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	return nil
 }
 
-func (service *AccountService) GetMerchantDetails(ctx context.Context, accountID string) (*MerchantDetails, error) {
-	merchantDetails, err := service.repository.GetMerchantDetails(ctx, accountID)
+// RevokeAllOtherSessions signs out every session of the account except the one whose access
+// token makes the call.
+func (service *AccountService) RevokeAllOtherSessions(ctx context.Context, accountID string, accessToken string) (uint32, error) {
+	if accountID == "" || accessToken == "" {
+		return 0, errors.New("revoking other sessions requires a signed-in account")
+	}
+	currentID, err := service.currentSessionID(ctx, accountID, accessToken)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return merchantDetails, nil
+	return service.revokeSessions(ctx, accountID, "", currentID, RevokeSignedOut)
 }
 
-// Products
-func (service *AccountService) CreateOrUpdateProduct(ctx context.Context, product *Product) (*Product, error) {
-	id := product.ID
-	if id == "" {
-		id = ksuid.New().String()
+// currentSessionID returns the id of the account's session that accessToken belongs to.
+func (service *AccountService) currentSessionID(ctx context.Context, accountID string, accessToken string) (string, error) {
+	current, err := service.repository.GetSessionByAccessToken(ctx, accessToken)
+	if err != nil || current.AccountID != accountID {
+		return "", errors.New("session not found")
 	}
-	newProduct := &Product{
-		ID:          id,
-		Name:        product.Name,
-		Description: product.Description,
-		Category:    product.Category,
-		Status:      product.Status,
+	return current.ID, nil
+}
+
+// ForceLogout signs out every session of an account, for an admin responding to a lost device
+// or a compromised account. The account can sign in again.
+func (service *AccountService) ForceLogout(ctx context.Context, accountID string, adminID string) (uint32, error) {
+	if accountID == "" {
+		return 0, errors.New("account_id is required")
 	}
-	if _, err := service.repository.CreateOrUpdateProduct(ctx, newProduct); err != nil {
-		return nil, err
+	if _, err := service.repository.GetAccountById(ctx, accountID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("account %s does not exist", accountID)
+		}
+		return 0, err
 	}
-	return newProduct, nil
+	revoked, err := service.revokeSessions(ctx, accountID, "", "", RevokeForced)
+	if err != nil {
+		return 0, err
+	}
+	service.logger.Security().Info().
+		Str("event", "force_logout").
+		Str("account_id", accountID).
+		Str("admin_id", adminID).
+		Uint32("sessions", revoked).
+		Msg("Admin signed out every session of an account")
+	return revoked, nil
 }
 
-func (service *AccountService) ListProducts(ctx context.Context, skip uint, take uint) ([]*Product, error) {
-	if take > 100 || (skip == 0 && take == 0) {
-		take = 100
+// CheckSession reports whether the session an access token belongs to is still signed in.
+// Market verifies tokens locally and asks here, through a short-lived cache, so a revoked
+// session stops trading before its access token expires.
+func (service *AccountService) CheckSession(ctx context.Context, accessToken string) (bool, error) {
+	if _, err := util.ValidateToken(ctx, accessToken, util.TokenTypeAccess, service.keys); err != nil {
+		return false, nil
+	}
+	session, err := service.repository.GetSessionByAccessToken(ctx, accessToken)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
 	}
-	products, err := service.repository.ListProducts(ctx, skip, take)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	return products, nil
+	return !session.IsRevoked, nil
 }
 
-// Grades
-func (service *AccountService) CreateOrUpdateGrade(ctx context.Context, grade *Grade) (*Grade, error) {
-	id := grade.ID
-	if id == "" {
-		id = ksuid.New().String()
+func (service *AccountService) revokeSessions(ctx context.Context, accountID string, sessionID string, exceptSessionID string, reason string) (uint32, error) {
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return 0, err
 	}
-	newGrade := &Grade{
-		ID:          id,
-		ProductID:   grade.ProductID,
-		Name:        grade.Name,
-		Description: grade.Description,
-		Status:      grade.Status,
+	revoked, err := service.repository.RevokeSessions(txCtx, accountID, sessionID, exceptSessionID, reason, time.Now())
+	if err != nil {
+		tx.Rollback()
+		return 0, err
 	}
-	if _, err := service.repository.CreateOrUpdateGrade(ctx, newGrade); err != nil {
-		return nil, err
+	if err := tx.Commit(); err != nil {
+		return 0, err
 	}
-	return newGrade, nil
+	return revoked, nil
 }
 
-func (service *AccountService) ListGradesByProductId(ctx context.Context, productId string, skip uint, take uint) ([]*Grade, error) {
-	if take > 100 || (skip == 0 && take == 0) {
-		take = 100
+// RequestPasswordReset mails a reset link to the account with this email. It succeeds whether
+// or not the email has an account, so it cannot be used to find out which emails do; a failed
+// send is only logged for the same reason.
+func (service *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return errors.New("email is required")
 	}
-	grades, err := service.repository.ListGradesByProductId(ctx, productId, skip, take)
+	account, err := service.repository.GetAccountByEmail(ctx, email)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
 	}
-	return grades, nil
+
+	token, err := service.issueAccountToken(ctx, account, TokenPasswordReset, service.passwordResetTTL)
+	if err != nil {
+		return err
+	}
+	err = service.mailer.Send(ctx, platform.Mail{
+		To:      account.Email,
+		Subject: "Reset your SpiceLedger password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your SpiceLedger account.\n\n"+
+			"To choose a new password, open this link within %s:\n\n%s/reset-password?token=%s\n\n"+
+			"If it was not you, ignore this email; your password has not changed.\n",
+			service.passwordResetTTL, service.appURL, token),
+	})
+	if err != nil {
+		service.logger.Service().Error().Err(err).Str("account_id", account.ID).Msg("Could not send password reset email")
+	}
+	return nil
 }
 
-// Daily Price
-func (service *AccountService) CreateOrUpdateDailyPrice(ctx context.Context, dailyPrice *DailyPrice) (*DailyPrice, error) {
-	id := dailyPrice.ID
-	if id == "" {
-		id = ksuid.New().String()
+// ConfirmPasswordReset sets a new password with a reset token. The token and any other reset
+// tokens of the account are used up. With revokeSessions every session of the account is
+// signed out too, and the count is returned.
+func (service *AccountService) ConfirmPasswordReset(ctx context.Context, token string, password string, revokeSessions bool) (uint32, error) {
+	if len(password) < 8 || len(password) > 50 {
+		return 0, errors.New("password must be 8 to 50 characters")
 	}
-	newDailyPrice := &DailyPrice{
-		ID:        id,
-		ProductID: dailyPrice.ProductID,
-		GradeID:   dailyPrice.GradeID,
-		Price:     dailyPrice.Price,
-		Date:      dailyPrice.Date,
-		Time:      dailyPrice.Time,
+	hashed, err := util.HashPassword(password)
+	if err != nil {
+		return 0, err
 	}
-	if _, err := service.repository.CreateOrUpdateDailyPrice(ctx, newDailyPrice); err != nil {
-		return nil, err
+
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	stored, err := service.useAccountToken(txCtx, token, TokenPasswordReset)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	if err = service.repository.UpdatePassword(txCtx, stored.AccountID, hashed); err != nil {
+		return 0, err
+	}
+	if err = service.repository.ExpireAccountTokens(txCtx, stored.AccountID, TokenPasswordReset, now); err != nil {
+		return 0, err
 	}
-	return newDailyPrice, nil
+	var revoked uint32
+	if revokeSessions {
+		if revoked, err = service.repository.RevokeSessions(txCtx, stored.AccountID, "", "", RevokePassword, now); err != nil {
+			return 0, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	service.logger.Security().Info().
+		Str("event", "password_reset").
+		Str("account_id", stored.AccountID).
+		Uint32("sessions_revoked", revoked).
+		Msg("Password reset")
+	return revoked, nil
 }
 
-func (service *AccountService) ListDailyPricesByGradeId(ctx context.Context, gradeId string, today time.Time, duration int) ([]*DailyPrice, error) {
-	if today.IsZero() {
-		today = time.Now()
+// SendVerificationEmail mails a verification link to the account's current email. Earlier
+// links stop working.
+func (service *AccountService) SendVerificationEmail(ctx context.Context, accountID string) error {
+	account, err := service.repository.GetAccountById(ctx, accountID)
+	if err != nil {
+		return err
 	}
-	if duration > 100 || duration == 0 {
-		duration = 100
+	if !account.EmailVerifiedAt.IsZero() {
+		return errors.New("email is already verified")
 	}
-	dailyPrices, err := service.repository.ListDailyPricesByGradeId(ctx, gradeId, today, duration)
+
+	token, err := service.issueAccountToken(ctx, account, TokenEmailVerification, service.verificationTTL)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return dailyPrices, nil
+	return service.mailer.Send(ctx, platform.Mail{
+		To:      account.Email,
+		Subject: "Verify your SpiceLedger email",
+		Body: fmt.Sprintf("Confirm that %s is the email of your SpiceLedger account by opening this link within %s:\n\n"+
+			"%s/verify-email?token=%s\n\nIf you did not sign up, ignore this email.\n",
+			account.Email, service.verificationTTL, service.appURL, token),
+	})
 }
 
-func (service *AccountService) GetTodaysByGradeId(ctx context.Context, gradeId string, date time.Time) ([]*DailyPrice, error) {
-	if date.IsZero() {
-		date = time.Now()
+// VerifyEmail marks the account's email verified with a verification token. The token only
+// works while the account still has the email it was sent to.
+func (service *AccountService) VerifyEmail(ctx context.Context, token string) (*Account, error) {
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
 	}
-	dailyPrice, err := service.repository.GetTodaysByGradeId(ctx, gradeId, date)
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	stored, err := service.useAccountToken(txCtx, token, TokenEmailVerification)
+	if err != nil {
+		return nil, err
+	}
+	verified, err := service.repository.MarkEmailVerified(txCtx, stored.AccountID, stored.Email, time.Now())
 	if err != nil {
 		return nil, err
 	}
-	return dailyPrice, nil
+	if !verified {
+		err = ErrInvalidAccountToken
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return service.repository.GetAccountById(ctx, stored.AccountID)
 }
 
-func (service *AccountService) GetTodaysByProductId(ctx context.Context, productId string, date time.Time) ([]*DailyPrice, error) {
-	if date.IsZero() {
-		date = time.Now()
+// issueAccountToken stores a new token for the account and returns it in plaintext, the only
+// time it is available. Unused tokens of the same purpose are used up.
+func (service *AccountService) issueAccountToken(ctx context.Context, account *Account, purpose string, ttl time.Duration) (string, error) {
+	token, err := util.RandomToken()
+	if err != nil {
+		return "", err
 	}
-	dailyPrices, err := service.repository.GetTodaysByProductId(ctx, productId, date)
+
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	if err = service.repository.ExpireAccountTokens(txCtx, account.ID, purpose, now); err != nil {
+		return "", err
+	}
+	if err = service.repository.CreateAccountToken(txCtx, &AccountToken{
+		ID:        ksuid.New().String(),
+		AccountID: account.ID,
+		Purpose:   purpose,
+		Email:     account.Email,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}); err != nil {
+		return "", err
+	}
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// useAccountToken looks a token up and marks it used. A token that is unknown, of another
+// purpose, expired or already used is rejected; marking it used is conditional, so one token
+// cannot succeed twice.
+func (service *AccountService) useAccountToken(ctx context.Context, token string, purpose string) (*AccountToken, error) {
+	if token == "" {
+		return nil, ErrInvalidAccountToken
+	}
+	stored, err := service.repository.GetAccountTokenByHash(ctx, util.HashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidAccountToken
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if stored.Purpose != purpose || !stored.UsedAt.IsZero() || !stored.ExpiresAt.After(now) {
+		return nil, ErrInvalidAccountToken
+	}
+	used, err := service.repository.UseAccountToken(ctx, stored.ID, now)
 	if err != nil {
 		return nil, err
 	}
-	return dailyPrices, nil
+	if !used {
+		return nil, ErrInvalidAccountToken
+	}
+	return stored, nil
 }
-func (service *AccountService) GetProductsWithGradesAndPrices(ctx context.Context, date time.Time, search string) ([]*ProductWithGrades, error) {
-	if date.IsZero() {
-		date = time.Now()
+
+// startLoginChallenge stores a challenge for a login that passed the password check and
+// returns its token in place of session tokens. The account is left out until the second
+// factor is met.
+func (service *AccountService) startLoginChallenge(ctx context.Context, account *Account, deviceID string, client SessionClient, purpose string) (*AuthenticatedResponse, error) {
+	token, err := util.RandomToken()
+	if err != nil {
+		return nil, err
 	}
-	return service.repository.GetProductsWithGradesAndPrices(ctx, date, search)
+	now := time.Now()
+	client = normalizeSessionClient(client)
+	if err := service.repository.CreateLoginChallenge(ctx, &LoginChallenge{
+		ID:         ksuid.New().String(),
+		AccountID:  account.ID,
+		DeviceID:   deviceID,
+		DeviceName: client.DeviceName,
+		Purpose:    purpose,
+		TokenHash:  util.HashToken(token),
+		ExpiresAt:  now.Add(service.twoFactor.ChallengeTTL),
+		CreatedAt:  now,
+	}); err != nil {
+		return nil, err
+	}
+	return &AuthenticatedResponse{
+		ChallengeToken:     token,
+		EnrollmentRequired: purpose == ChallengeEnroll,
+	}, nil
+}
+
+// loginChallenge looks up a challenge that is still open for the device.
+func (service *AccountService) loginChallenge(ctx context.Context, token string, purpose string, deviceID string) (*LoginChallenge, error) {
+	if token == "" {
+		return nil, ErrInvalidLoginChallenge
+	}
+	challenge, err := service.repository.GetLoginChallengeByHash(ctx, util.HashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidLoginChallenge
+		}
+		return nil, err
+	}
+	if challenge.Purpose != purpose || challenge.DeviceID != deviceID || !challenge.UsedAt.IsZero() || !challenge.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidLoginChallenge
+	}
+	return challenge, nil
+}
+
+// completeLoginChallenge uses the challenge up and starts the session it was waiting for, in
+// the caller's transaction.
+func (service *AccountService) completeLoginChallenge(txCtx context.Context, challenge *LoginChallenge, client SessionClient) (*AuthenticatedResponse, error) {
+	used, err := service.repository.UseLoginChallenge(txCtx, challenge.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, ErrInvalidLoginChallenge
+	}
+	account, err := service.repository.GetAccountById(txCtx, challenge.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if client.DeviceName == "" {
+		client.DeviceName = challenge.DeviceName
+	}
+	return service.createSession(txCtx, account, challenge.DeviceID, client)
+}
+
+// recordChallengeFailure counts a wrong code against the challenge.
+func (service *AccountService) recordChallengeFailure(ctx context.Context, challenge *LoginChallenge) {
+	if err := service.repository.RecordChallengeAttempt(ctx, challenge.ID, MaxChallengeAttempts, time.Now()); err != nil {
+		service.logger.Service().Error().Err(err).Str("challenge_id", challenge.ID).Msg("Could not record a login challenge attempt")
+	}
+	service.logger.Security().Warn().
+		Str("event", "two_factor_failed").
+		Str("account_id", challenge.AccountID).
+		Str("device_id", challenge.DeviceID).
+		Int("attempts", challenge.Attempts+1).
+		Msg("Wrong two-factor code")
+}
+
+// EnrollTOTP starts TOTP enrolment and returns the new secret and its otpauth:// provisioning
+// URI, for the user to add to an authenticator app. It is called signed in, or with the
+// challenge of an admin who must enrol before signing in. Starting again replaces a secret
+// that was not confirmed yet.
+func (service *AccountService) EnrollTOTP(ctx context.Context, accountID string, challengeToken string, deviceID string) (string, string, error) {
+	if challengeToken != "" {
+		challenge, err := service.loginChallenge(ctx, challengeToken, ChallengeEnroll, deviceID)
+		if err != nil {
+			return "", "", err
+		}
+		accountID = challenge.AccountID
+	}
+	if accountID == "" {
+		return "", "", errors.New("enrolling requires a signed-in account or a login challenge")
+	}
+	account, err := service.repository.GetAccountById(ctx, accountID)
+	if err != nil {
+		return "", "", err
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+	sealed, err := util.EncryptSecret(service.twoFactor.EncryptionKey, secret)
+	if err != nil {
+		return "", "", err
+	}
+	stored, err := service.repository.UpsertTOTP(ctx, &TOTPEnrollment{
+		AccountID: account.ID,
+		Secret:    sealed,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if !stored {
+		return "", "", errors.New("two-factor authentication is already enabled")
+	}
+	return secret, util.TOTPProvisioningURI(service.twoFactor.Issuer, account.Email, secret), nil
+}
+
+// ConfirmTOTP turns TOTP on with a code from the authenticator app and returns the recovery
+// codes, the only time they are shown. Every other session of the account is signed out, so
+// none outlives the change without the second factor. Confirming with an enrolment challenge
+// also signs the device in.
+func (service *AccountService) ConfirmTOTP(ctx context.Context, accountID string, accessToken string, challengeToken string, deviceID string, code string, client SessionClient) ([]string, *AuthenticatedResponse, error) {
+	var challenge *LoginChallenge
+	if challengeToken != "" {
+		var err error
+		if challenge, err = service.loginChallenge(ctx, challengeToken, ChallengeEnroll, deviceID); err != nil {
+			return nil, nil, err
+		}
+		accountID = challenge.AccountID
+	}
+	if accountID == "" {
+		return nil, nil, errors.New("enrolling requires a signed-in account or a login challenge")
+	}
+	enrollment, err := service.repository.GetTOTP(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, errors.New("two-factor enrolment has not been started")
+		}
+		return nil, nil, err
+	}
+	if !enrollment.ConfirmedAt.IsZero() {
+		return nil, nil, errors.New("two-factor authentication is already enabled")
+	}
+	// Signed in, the calling session stays; with a challenge, the session it starts.
+	var currentID string
+	if challenge == nil && accessToken != "" {
+		if currentID, err = service.currentSessionID(ctx, accountID, accessToken); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	now := time.Now()
+	step, err := service.matchTOTP(enrollment, code, now)
+	if err != nil {
+		if challenge != nil && errors.Is(err, ErrInvalidTwoFactorCode) {
+			service.recordChallengeFailure(ctx, challenge)
+		}
+		return nil, nil, err
+	}
+	codes, recoveryCodes, err := newRecoveryCodes(accountID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = service.repository.ConfirmTOTP(txCtx, accountID, step, now); err != nil {
+		return nil, nil, err
+	}
+	if err = service.repository.ReplaceRecoveryCodes(txCtx, accountID, recoveryCodes); err != nil {
+		return nil, nil, err
+	}
+	revoked, err := service.repository.RevokeSessions(txCtx, accountID, "", currentID, RevokeTwoFactor, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	var resp *AuthenticatedResponse
+	if challenge != nil {
+		if resp, err = service.completeLoginChallenge(txCtx, challenge, client); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	service.logger.Security().Info().
+		Str("event", "totp_enabled").
+		Str("account_id", accountID).
+		Uint32("sessions_revoked", revoked).
+		Msg("Two-factor authentication enabled")
+	return codes, resp, nil
+}
+
+// VerifyLoginChallenge meets a login's second factor with a TOTP code or a recovery code and
+// signs the device in. After MaxChallengeAttempts wrong codes the challenge is used up and the
+// login has to start over.
+func (service *AccountService) VerifyLoginChallenge(ctx context.Context, challengeToken string, deviceID string, code string, recoveryCode string, client SessionClient) (*AuthenticatedResponse, error) {
+	challenge, err := service.loginChallenge(ctx, challengeToken, ChallengeVerify, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	enrollment, err := service.repository.GetTOTP(ctx, challenge.AccountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Disabled since the password step.
+			return nil, ErrInvalidLoginChallenge
+		}
+		return nil, err
+	}
+
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = service.useSecondFactor(txCtx, enrollment, code, recoveryCode); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			tx.Rollback()
+			service.recordChallengeFailure(ctx, challenge)
+		}
+		return nil, err
+	}
+	resp, err := service.completeLoginChallenge(txCtx, challenge, client)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DisableTOTP turns TOTP off after checking a TOTP or recovery code, drops the recovery codes
+// and signs out every other session of the account. Admins cannot while 2FA is mandatory for
+// them.
+func (service *AccountService) DisableTOTP(ctx context.Context, accountID string, accessToken string, code string, recoveryCode string) error {
+	account, err := service.repository.GetAccountById(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if account.UserType == util.UserTypeAdmin && service.twoFactor.RequiredForAdmins {
+		return errors.New("two-factor authentication is required for admins")
+	}
+	enrollment, err := service.confirmedTOTP(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	currentID, err := service.currentSessionID(ctx, accountID, accessToken)
+	if err != nil {
+		return err
+	}
+
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = service.useSecondFactor(txCtx, enrollment, code, recoveryCode); err != nil {
+		return err
+	}
+	if err = service.repository.DeleteTOTP(txCtx, accountID); err != nil {
+		return err
+	}
+	revoked, err := service.repository.RevokeSessions(txCtx, accountID, "", currentID, RevokeTwoFactor, time.Now())
+	if err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	service.logger.Security().Info().
+		Str("event", "totp_disabled").
+		Str("account_id", accountID).
+		Uint32("sessions_revoked", revoked).
+		Msg("Two-factor authentication disabled")
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the account's recovery codes after checking a TOTP code.
+// The old codes stop working.
+func (service *AccountService) RegenerateRecoveryCodes(ctx context.Context, accountID string, code string) ([]string, error) {
+	enrollment, err := service.confirmedTOTP(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	codes, recoveryCodes, err := newRecoveryCodes(accountID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = service.useSecondFactor(txCtx, enrollment, code, ""); err != nil {
+		return nil, err
+	}
+	if err = service.repository.ReplaceRecoveryCodes(txCtx, accountID, recoveryCodes); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	service.logger.Security().Info().
+		Str("event", "recovery_codes_regenerated").
+		Str("account_id", accountID).
+		Msg("Recovery codes regenerated")
+	return codes, nil
+}
+
+func (service *AccountService) confirmedTOTP(ctx context.Context, accountID string) (*TOTPEnrollment, error) {
+	if accountID == "" {
+		return nil, errors.New("two-factor settings require a signed-in account")
+	}
+	enrollment, err := service.repository.GetTOTP(ctx, accountID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if enrollment == nil || enrollment.ConfirmedAt.IsZero() {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	return enrollment, nil
+}
+
+// matchTOTP returns the time step a code matches, refusing steps already used.
+func (service *AccountService) matchTOTP(enrollment *TOTPEnrollment, code string, at time.Time) (int64, error) {
+	secret, err := util.DecryptSecret(service.twoFactor.EncryptionKey, enrollment.Secret)
+	if err != nil {
+		return 0, err
+	}
+	step, ok := util.ValidateTOTP(secret, code, at, enrollment.LastUsedStep)
+	if !ok {
+		return 0, ErrInvalidTwoFactorCode
+	}
+	return step, nil
+}
+
+// useSecondFactor checks a TOTP code, or failing that a recovery code, and uses it up.
+func (service *AccountService) useSecondFactor(ctx context.Context, enrollment *TOTPEnrollment, code string, recoveryCode string) error {
+	now := time.Now()
+	if code != "" {
+		step, err := service.matchTOTP(enrollment, code, now)
+		if err != nil {
+			return err
+		}
+		used, err := service.repository.UseTOTPStep(ctx, enrollment.AccountID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+	if recoveryCode != "" {
+		used, err := service.repository.UseRecoveryCode(ctx, enrollment.AccountID, util.HashToken(util.NormalizeRecoveryCode(recoveryCode)), now)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidTwoFactorCode
+		}
+		service.logger.Security().Info().
+			Str("event", "recovery_code_used").
+			Str("account_id", enrollment.AccountID).
+			Msg("Recovery code used")
+		return nil
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+// newRecoveryCodes returns RecoveryCodeCount new codes, in plaintext for the user and hashed
+// for storage.
+func newRecoveryCodes(accountID string, now time.Time) ([]string, []*RecoveryCode, error) {
+	codes := make([]string, RecoveryCodeCount)
+	recoveryCodes := make([]*RecoveryCode, RecoveryCodeCount)
+	for i := range codes {
+		code, err := util.GenerateRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code
+		recoveryCodes[i] = &RecoveryCode{
+			ID:        ksuid.New().String(),
+			AccountID: accountID,
+			CodeHash:  util.HashToken(util.NormalizeRecoveryCode(code)),
+			CreatedAt: now,
+		}
+	}
+	return codes, recoveryCodes, nil
+}
+
+// touchSession records a call from session, at most once per SessionSeenInterval.
+func (service *AccountService) touchSession(ctx context.Context, session *Session, client SessionClient) {
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) < SessionSeenInterval {
+		return
+	}
+	client = normalizeSessionClient(client)
+	_ = service.repository.TouchSession(ctx, session.ID, client.IPAddress, client.UserAgent, now)
+}
+
+func (service *AccountService) CreateOrUpdateMerchantDetails(ctx context.Context, merchantDetails *MerchantDetails) (*MerchantDetails, error) {
+	if merchantDetails.AccountID == "" {
+		return nil, errors.New("account_id is required")
+	}
+	if merchantDetails.Phone == "" {
+		return nil, errors.New("phone_number is required")
+	}
+	if merchantDetails.Address == "" {
+		return nil, errors.New("address is required")
+	}
+	if merchantDetails.City == "" {
+		return nil, errors.New("city is required")
+	}
+	if merchantDetails.State == "" {
+		return nil, errors.New("state is required")
+	}
+	if merchantDetails.Pincode == "" {
+		return nil, errors.New("pincode is required")
+	}
+	id := merchantDetails.ID
+
+	// Check if merchant details already exists for this account
+	existingMerchantDetails, err := service.repository.GetMerchantDetails(ctx, merchantDetails.AccountID)
+	if err == nil && existingMerchantDetails != nil {
+		// Use the existing ID to ensure we update the correct record
+		id = existingMerchantDetails.ID
+	}
+
+	if id == "" {
+		id = ksuid.New().String()
+	}
+	newMerchantDetails := &MerchantDetails{
+		ID:        id,
+		AccountID: merchantDetails.AccountID,
+		Phone:     merchantDetails.Phone,
+		Address:   merchantDetails.Address,
+		City:      merchantDetails.City,
+		State:     merchantDetails.State,
+		Pincode:   merchantDetails.Pincode,
+	}
+	if _, err := service.repository.CreateOrUpdateMerchantDetails(ctx, newMerchantDetails); err != nil {
+		return nil, err
+	}
+	return newMerchantDetails, nil
+}
+
+func (service *AccountService) GetMerchantDetails(ctx context.Context, accountID string) (*MerchantDetails, error) {
+	merchantDetails, err := service.repository.GetMerchantDetails(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return merchantDetails, nil
+}
+
+// Products
+func (service *AccountService) CreateOrUpdateProduct(ctx context.Context, product *Product) (*Product, error) {
+	id := product.ID
+	if id == "" {
+		id = ksuid.New().String()
+	}
+	newProduct := &Product{
+		ID:          id,
+		Name:        product.Name,
+		Description: product.Description,
+		Category:    product.Category,
+		Status:      product.Status,
+	}
+	if _, err := service.repository.CreateOrUpdateProduct(ctx, newProduct); err != nil {
+		return nil, err
+	}
+	return newProduct, nil
+}
+
+func (service *AccountService) ListProducts(ctx context.Context, skip uint, take uint) ([]*Product, error) {
+	if take > 100 || (skip == 0 && take == 0) {
+		take = 100
+	}
+	products, err := service.repository.ListProducts(ctx, skip, take)
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// Grades
+func (service *AccountService) CreateOrUpdateGrade(ctx context.Context, grade *Grade) (*Grade, error) {
+	id := grade.ID
+	if id == "" {
+		id = ksuid.New().String()
+	}
+	if grade.ShelfLifeDays < 0 || grade.ShelfLifeDays > MaxShelfLifeDays {
+		return nil, fmt.Errorf("shelf_life_days must be between 0 and %d", MaxShelfLifeDays)
+	}
+	unit, kgPerUnit, err := gradeUnit(grade.Unit, grade.KgPerUnit)
+	if err != nil {
+		return nil, err
+	}
+	newGrade := &Grade{
+		ID:            id,
+		ProductID:     grade.ProductID,
+		Name:          grade.Name,
+		Description:   grade.Description,
+		Status:        grade.Status,
+		ShelfLifeDays: grade.ShelfLifeDays,
+		Unit:          unit,
+		KgPerUnit:     kgPerUnit,
+	}
+	if _, err := service.repository.CreateOrUpdateGrade(ctx, newGrade); err != nil {
+		return nil, err
+	}
+	return newGrade, nil
+}
+
+// gradeUnit defaults a grade's unit to kilograms and fixes kg_per_unit for the fixed-size
+// units. A BAG has no standard size, so the grade must give one.
+func gradeUnit(unit string, kgPerUnit decimal.Decimal) (string, decimal.Decimal, error) {
+	unit, err := util.ParseUnit(unit)
+	if err != nil {
+		return "", decimal.Zero, err
+	}
+	if unit == "" {
+		unit = util.BaseUnit
+	}
+	if kg, ok := util.UnitKg(unit); ok {
+		if !kgPerUnit.IsZero() && !kgPerUnit.Equal(kg) {
+			return "", decimal.Zero, fmt.Errorf("kg_per_unit of a %s is %s", unit, kg)
+		}
+		return unit, kg, nil
+	}
+	kgPerUnit = util.RoundQuantity(kgPerUnit)
+	if !kgPerUnit.IsPositive() {
+		return "", decimal.Zero, fmt.Errorf("kg_per_unit is required for a %s and must be positive", unit)
+	}
+	return unit, kgPerUnit, nil
+}
+
+func (service *AccountService) ListGradesByProductId(ctx context.Context, productId string, skip uint, take uint) ([]*Grade, error) {
+	if take > 100 || (skip == 0 && take == 0) {
+		take = 100
+	}
+	grades, err := service.repository.ListGradesByProductId(ctx, productId, skip, take)
+	if err != nil {
+		return nil, err
+	}
+	return grades, nil
+}
+
+// Daily Price
+
+// maxPriceSourceLen matches price_ticks.source.
+const maxPriceSourceLen = 32
+
+// ProposePriceTick stores a published price as a new tick awaiting approval: SUBMITTED, or
+// DRAFT when draft is set. It does not change any daily price until another admin approves it.
+func (service *AccountService) ProposePriceTick(ctx context.Context, tick *PriceTick, draft bool) (*PriceTick, error) {
+	newTick, err := newPriceTick(tick, draft)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.repository.InsertPriceTick(ctx, newTick); err != nil {
+		return nil, err
+	}
+	return newTick, nil
+}
+
+// SubmitPriceTick sends a DRAFT tick for approval. Only its proposer may submit it.
+func (service *AccountService) SubmitPriceTick(ctx context.Context, id string, accountID string) (*PriceTick, error) {
+	tick, err := service.repository.GetPriceTick(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("price %s does not exist", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tick.Status != PriceDraft {
+		return nil, fmt.Errorf("price %s is %s; only a DRAFT can be submitted", id, tick.Status)
+	}
+	if tick.PublishedBy != "" && tick.PublishedBy != accountID {
+		return nil, fmt.Errorf("price %s can only be submitted by the admin who proposed it", id)
+	}
+	tick.Status = PriceSubmitted
+	tick.SubmittedAt = time.Now()
+	ok, err := service.repository.UpdatePriceTickStatus(ctx, tick, PriceDraft)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("price %s changed while it was being submitted", id)
+	}
+	return tick, nil
+}
+
+// maxReviewNoteLen matches price_ticks.review_note.
+const maxReviewNoteLen = 255
+
+// ReviewPriceTicks approves or rejects SUBMITTED ticks in one transaction; if any of them
+// cannot be reviewed, none are. The reviewer must be a different admin from each tick's
+// proposer. Approving rebuilds the daily prices the ticks belong to, which are returned and
+// published once committed.
+func (service *AccountService) ReviewPriceTicks(ctx context.Context, ids []string, decision string, reviewerID string, note string) ([]*PriceTick, []*DailyPrice, error) {
+	if len(ids) == 0 {
+		return nil, nil, errors.New("at least one price id is required")
+	}
+	if len(ids) > MaxPriceImportRows {
+		return nil, nil, fmt.Errorf("a review covers at most %d prices", MaxPriceImportRows)
+	}
+	decision = strings.ToUpper(strings.TrimSpace(decision))
+	if decision != PriceReviewApprove && decision != PriceReviewReject {
+		return nil, nil, fmt.Errorf("unknown decision %q: use APPROVE or REJECT", decision)
+	}
+	if reviewerID == "" {
+		return nil, nil, errors.New("reviewing a price requires a signed-in admin")
+	}
+	note = strings.TrimSpace(note)
+	if len(note) > maxReviewNoteLen {
+		return nil, nil, fmt.Errorf("note must be at most %d characters", maxReviewNoteLen)
+	}
+	status := PriceApproved
+	if decision == PriceReviewReject {
+		status = PriceRejected
+	}
+
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	seen := map[string]bool{}
+	ticks := make([]*PriceTick, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		var tick *PriceTick
+		tick, err = service.repository.GetPriceTick(txCtx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("price %s does not exist", id)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if tick.Status != PriceSubmitted {
+			err = fmt.Errorf("price %s is %s; only SUBMITTED prices can be reviewed", id, tick.Status)
+			return nil, nil, err
+		}
+		if tick.PublishedBy == reviewerID {
+			err = fmt.Errorf("price %s was proposed by you; another admin must review it", id)
+			return nil, nil, err
+		}
+		tick.Status = status
+		tick.ReviewedBy = reviewerID
+		tick.ReviewedAt = now
+		tick.ReviewNote = note
+		var ok bool
+		if ok, err = service.repository.UpdatePriceTickStatus(txCtx, tick, PriceSubmitted); err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			err = fmt.Errorf("price %s was reviewed by someone else", id)
+			return nil, nil, err
+		}
+		ticks = append(ticks, tick)
+	}
+
+	// Roll up each grade and day once, after all of its approvals are in.
+	dailyPrices := []*DailyPrice{}
+	if status == PriceApproved {
+		rolledUp := map[string]bool{}
+		for _, tick := range ticks {
+			key := tick.GradeID + "|" + tick.TickedAt.Format("2006-01-02")
+			if rolledUp[key] {
+				continue
+			}
+			rolledUp[key] = true
+			if err = service.checkDayCurrency(txCtx, tick.GradeID, tick.TickedAt); err != nil {
+				return nil, nil, err
+			}
+			var dailyPrice *DailyPrice
+			dailyPrice, err = service.repository.RollupDailyPrice(txCtx, ksuid.New().String(), tick.GradeID, tick.TickedAt)
+			if err != nil {
+				return nil, nil, err
+			}
+			setClose(dailyPrice, now)
+			dailyPrices = append(dailyPrices, dailyPrice)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	if service.events != nil {
+		for _, dailyPrice := range dailyPrices {
+			service.events.Publish(TopicPrices, dailyPrice)
+		}
+	}
+	return ticks, dailyPrices, nil
+}
+
+// checkDayCurrency refuses a day whose approved ticks of a grade are quoted in more than one
+// currency, since its rollup could not be a single price.
+func (service *AccountService) checkDayCurrency(txCtx context.Context, gradeId string, date time.Time) error {
+	ticks, err := service.repository.ListPriceTicks(txCtx, gradeId, date, PriceApproved)
+	if err != nil {
+		return err
+	}
+	for _, tick := range ticks {
+		if tick.Currency != ticks[0].Currency {
+			return fmt.Errorf("grade %s would have approved prices in %s and %s on %s; a day's prices must share one currency",
+				gradeId, ticks[0].Currency, tick.Currency, date.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
+
+// newPriceTick validates a proposed price and fills in its defaults: a new id, the default
+// currency, the MANUAL source, the current time and the DRAFT or SUBMITTED status.
+func newPriceTick(tick *PriceTick, draft bool) (*PriceTick, error) {
+	if !tick.Price.IsPositive() {
+		return nil, errors.New("price must be greater than zero")
+	}
+	if !util.RoundPrice(tick.Price).Equal(tick.Price) {
+		return nil, fmt.Errorf("price supports at most %d decimal places", util.PriceScale)
+	}
+	if tick.GradeID == "" || tick.ProductID == "" {
+		return nil, errors.New("product_id and grade_id are required")
+	}
+	currency, err := util.ParseCurrency(tick.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		currency = util.DefaultCurrency
+	}
+	source := strings.ToUpper(strings.TrimSpace(tick.Source))
+	if source == "" {
+		source = PriceSourceManual
+	}
+	if len(source) > maxPriceSourceLen {
+		return nil, fmt.Errorf("source must be at most %d characters", maxPriceSourceLen)
+	}
+	id := tick.ID
+	if id == "" {
+		id = ksuid.New().String()
+	}
+	now := time.Now()
+	tickedAt := tick.TickedAt
+	if tickedAt.IsZero() {
+		tickedAt = now
+	}
+	newTick := &PriceTick{
+		ID:          id,
+		ProductID:   tick.ProductID,
+		GradeID:     tick.GradeID,
+		Price:       tick.Price,
+		Currency:    currency,
+		Source:      source,
+		PublishedBy: tick.PublishedBy,
+		Status:      PriceDraft,
+		TickedAt:    tickedAt,
+	}
+	if !draft {
+		newTick.Status = PriceSubmitted
+		newTick.SubmittedAt = now
+	}
+	return newTick, nil
+}
+
+// MaxPriceImportRows caps the lines of one bulk price import.
+const MaxPriceImportRows = 5000
+
+// ImportPriceTicks validates a sheet of published prices and submits them for approval in one
+// database transaction. Every grade must exist and belong to the row's product, and a price may
+// not move further from the grade's previous price than the outlier limit. Rows are checked in
+// sheet order, so the previous price is the newer of the grade's approved price and the accepted
+// rows above it. If any row is rejected nothing is saved, and the report says why for each row.
+func (service *AccountService) ImportPriceTicks(ctx context.Context, rows []*PriceImportRow, options PriceImportOptions) (*PriceImportReport, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("no prices to import")
+	}
+	if len(rows) > MaxPriceImportRows {
+		return nil, fmt.Errorf("an import holds at most %d prices", MaxPriceImportRows)
+	}
+	maxMove := service.maxPriceMove
+	if options.MaxMovePercent.Valid {
+		maxMove = options.MaxMovePercent.Decimal
+	}
+	if maxMove.IsNegative() {
+		return nil, errors.New("max_move_percent cannot be negative")
+	}
+
+	txCtx, tx, err := service.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &PriceImportReport{DryRun: options.DryRun, MaxMovePercent: maxMove, Rows: rows}
+	defer func() {
+		if !report.Applied {
+			tx.Rollback()
+		}
+	}()
+
+	sheet := &priceSheet{grades: map[string]*Grade{}, ids: map[string]int{}, accepted: map[string]*PriceTick{}}
+	for _, row := range rows {
+		if row.Status == PriceImportError {
+			continue
+		}
+		if err := service.importPriceRow(txCtx, row, maxMove, sheet); err != nil {
+			return nil, err
+		}
+	}
+	for _, row := range rows {
+		if row.Status == PriceImportOK {
+			report.Accepted++
+		} else {
+			report.Rejected++
+		}
+	}
+	if report.Rejected > 0 {
+		return report, nil
+	}
+
+	if options.DryRun {
+		return report, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	report.Applied = true
+	return report, nil
+}
+
+// priceSheet is what an import has learned from the rows so far: catalog lookups, tick ids
+// with their rows, and the newest accepted tick of each grade.
+type priceSheet struct {
+	grades   map[string]*Grade
+	ids      map[string]int
+	accepted map[string]*PriceTick
+}
+
+// importPriceRow validates one import row and, when it passes, inserts its tick inside the
+// import's transaction. Rejections are recorded on the row; only database failures are
+// returned.
+func (service *AccountService) importPriceRow(txCtx context.Context, row *PriceImportRow, maxMove decimal.Decimal, sheet *priceSheet) error {
+	reject := func(format string, args ...any) error {
+		row.Status = PriceImportError
+		row.Error = fmt.Sprintf(format, args...)
+		return nil
+	}
+
+	tick, err := newPriceTick(&row.Tick, false)
+	if err != nil {
+		return reject("%s", err.Error())
+	}
+	if first, ok := sheet.ids[tick.ID]; ok {
+		return reject("id %s is already used by row %d", tick.ID, first)
+	}
+	sheet.ids[tick.ID] = row.Row
+
+	grade, ok := sheet.grades[tick.GradeID]
+	if !ok {
+		grade, err = service.repository.GetGradeById(txCtx, tick.GradeID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		sheet.grades[tick.GradeID] = grade
+	}
+	if grade == nil {
+		return reject("grade %s does not exist", tick.GradeID)
+	}
+	if grade.ProductID != tick.ProductID {
+		return reject("grade %s belongs to product %s, not %s", grade.ID, grade.ProductID, tick.ProductID)
+	}
+
+	previous, err := service.repository.GetLatestPriceTick(txCtx, tick.GradeID, tick.TickedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if above := sheet.accepted[tick.GradeID]; above != nil && !above.TickedAt.After(tick.TickedAt) &&
+		(previous == nil || above.TickedAt.After(previous.TickedAt)) {
+		previous = above
+	}
+	if previous != nil && previous.Currency == tick.Currency {
+		move := tick.Price.Sub(previous.Price).Div(previous.Price).Mul(decimal.NewFromInt(100)).Round(2)
+		row.MovePercent = decimal.NewNullDecimal(move)
+		if maxMove.IsPositive() && move.Abs().GreaterThan(maxMove) {
+			return reject("price %s moves %s%% from the previous price %s, beyond the %s%% limit",
+				tick.Price, move, previous.Price, maxMove)
+		}
+	}
+
+	if err := service.repository.InsertPriceTick(txCtx, tick); err != nil {
+		return err
+	}
+	if above := sheet.accepted[tick.GradeID]; above == nil || !above.TickedAt.After(tick.TickedAt) {
+		sheet.accepted[tick.GradeID] = tick
+	}
+	row.Tick = *tick
+	row.Status = PriceImportOK
+	return nil
+}
+
+// ListPriceTicks returns ticks oldest first: every tick of a grade on one date (today by
+// default), or with only a status, the ticks in that status on any date, such as the queue of
+// SUBMITTED prices awaiting review.
+func (service *AccountService) ListPriceTicks(ctx context.Context, gradeId string, date time.Time, status string) ([]*PriceTick, error) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	switch status {
+	case "", PriceDraft, PriceSubmitted, PriceApproved, PriceRejected:
+	default:
+		return nil, fmt.Errorf("unknown price status %q", status)
+	}
+	if gradeId == "" && status == "" {
+		return nil, errors.New("grade_id or status is required")
+	}
+	if gradeId != "" && date.IsZero() {
+		date = time.Now()
+	}
+	return service.repository.ListPriceTicks(ctx, gradeId, date, status)
+}
+
+// normalizePriceBasis defaults an empty basis to LAST.
+func normalizePriceBasis(basis string) (string, error) {
+	switch b := strings.ToUpper(strings.TrimSpace(basis)); b {
+	case "", PriceBasisLast:
+		return PriceBasisLast, nil
+	case PriceBasisClose:
+		return b, nil
+	default:
+		return "", fmt.Errorf("unknown price basis %q: use LAST or CLOSE", basis)
+	}
+}
+
+// setClose fills Close once the price's day has ended; the last tick of the day is its close.
+func setClose(dailyPrice *DailyPrice, now time.Time) {
+	if dailyPrice.Date.Format("2006-01-02") < now.Format("2006-01-02") {
+		dailyPrice.Close = decimal.NewNullDecimal(dailyPrice.Last)
+	}
+}
+
+// applyPriceBasis sets Close on each rollup and Price to the requested basis. For CLOSE, days
+// that have not ended yet are left out.
+func applyPriceBasis(dailyPrices []*DailyPrice, basis string) []*DailyPrice {
+	now := time.Now()
+	out := make([]*DailyPrice, 0, len(dailyPrices))
+	for _, dp := range dailyPrices {
+		setClose(dp, now)
+		if basis == PriceBasisClose {
+			if !dp.Close.Valid {
+				continue
+			}
+			dp.Price = dp.Close.Decimal
+		}
+		out = append(out, dp)
+	}
+	return out
+}
+
+// SubscribePrices streams daily prices as they are published. Empty gradeId and productId
+// match every grade; a non-empty epoch resumes after afterSequence.
+func (service *AccountService) SubscribePrices(gradeId string, productId string, epoch string, afterSequence uint64) (*platform.Subscription, error) {
+	if service.events == nil {
+		return nil, errors.New("price events are not enabled")
+	}
+	return service.events.Subscribe(epoch, afterSequence, func(ev platform.Event) bool {
+		price, ok := ev.Payload.(*DailyPrice)
+		return ev.Topic == TopicPrices && ok &&
+			(gradeId == "" || price.GradeID == gradeId) &&
+			(productId == "" || price.ProductID == productId)
+	})
+}
+
+func (service *AccountService) ListDailyPricesByGradeId(ctx context.Context, gradeId string, today time.Time, duration int) ([]*DailyPrice, error) {
+	if today.IsZero() {
+		today = time.Now()
+	}
+	if duration > 100 || duration == 0 {
+		duration = 100
+	}
+	dailyPrices, err := service.repository.ListDailyPricesByGradeId(ctx, gradeId, today, duration)
+	if err != nil {
+		return nil, err
+	}
+	return applyPriceBasis(dailyPrices, PriceBasisLast), nil
+}
+
+// GetTodaysByGradeId returns a grade's rollup for a date, priced at basis (LAST by default).
+func (service *AccountService) GetTodaysByGradeId(ctx context.Context, gradeId string, date time.Time, basis string) ([]*DailyPrice, error) {
+	basis, err := normalizePriceBasis(basis)
+	if err != nil {
+		return nil, err
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	dailyPrice, err := service.repository.GetTodaysByGradeId(ctx, gradeId, date)
+	if err != nil {
+		return nil, err
+	}
+	return applyPriceBasis(dailyPrice, basis), nil
+}
+
+// GetTodaysByProductId returns the rollups of a product's grades for a date, priced at basis.
+func (service *AccountService) GetTodaysByProductId(ctx context.Context, productId string, date time.Time, basis string) ([]*DailyPrice, error) {
+	basis, err := normalizePriceBasis(basis)
+	if err != nil {
+		return nil, err
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	dailyPrices, err := service.repository.GetTodaysByProductId(ctx, productId, date)
+	if err != nil {
+		return nil, err
+	}
+	return applyPriceBasis(dailyPrices, basis), nil
+}
+
+// maxCandleRangeDays caps the span GetPriceCandles reads, about three years.
+const maxCandleRangeDays = 1100
+
+// Moving-average windows in days.
+const (
+	smaShortDays = 7
+	smaLongDays  = 30
+)
+
+// GetPriceCandles buckets daily rollups into OHLC candles per grade. With FillGaps, buckets
+// without a publication are returned as flat candles at the previous close; otherwise they are
+// left out. Days are read from smaLongDays before From so the first candles have their
+// previous close and moving averages.
+func (service *AccountService) GetPriceCandles(ctx context.Context, query CandleQuery) ([]*PriceSeries, error) {
+	if query.GradeID == "" && query.ProductID == "" {
+		return nil, errors.New("grade_id or product_id is required")
+	}
+	interval := strings.ToUpper(strings.TrimSpace(query.Interval))
+	if interval == "" {
+		interval = CandleDay
+	}
+	if interval != CandleDay && interval != CandleWeek && interval != CandleMonth {
+		return nil, fmt.Errorf("unknown interval %q: use DAY, WEEK or MONTH", query.Interval)
+	}
+
+	to := query.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	to = calendarDay(to)
+	from := query.From
+	if from.IsZero() {
+		switch interval {
+		case CandleWeek:
+			from = to.AddDate(0, 0, -7*12)
+		case CandleMonth:
+			from = to.AddDate(-1, 0, 0)
+		default:
+			from = to.AddDate(0, 0, -29)
+		}
+	}
+	from = candleBucketStart(calendarDay(from), interval)
+	if from.After(to) {
+		return nil, errors.New("date_from must not be after date_to")
+	}
+	if to.Sub(from) > maxCandleRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("date range must not exceed %d days", maxCandleRangeDays)
+	}
+
+	warmFrom := from.AddDate(0, 0, -smaLongDays)
+	rows, err := service.repository.ListDailyPricesInRange(ctx, query.GradeID, query.ProductID, warmFrom, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var series []*PriceSeries
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && rows[end].GradeID == rows[start].GradeID {
+			end++
+		}
+		series = append(series, &PriceSeries{
+			GradeID:   rows[start].GradeID,
+			ProductID: rows[start].ProductID,
+			Interval:  interval,
+			Candles:   buildCandles(rows[start:end], interval, warmFrom, from, to, query.FillGaps),
+		})
+		start = end
+	}
+	return series, nil
+}
+
+// candleDay is one calendar day of a grade's series; filled days carry the previous close.
+type candleDay struct {
+	date   time.Time
+	price  *DailyPrice
+	close  decimal.Decimal
+	sma7   decimal.NullDecimal
+	sma30  decimal.NullDecimal
+	filled bool
+}
+
+// buildCandles turns one grade's rollups (ordered by date) into candles for [from, to].
+func buildCandles(rows []*DailyPrice, interval string, warmFrom, from, to time.Time, fillGaps bool) []*Candle {
+	byDate := make(map[string]*DailyPrice, len(rows))
+	for _, dp := range rows {
+		byDate[dp.Date.Format("2006-01-02")] = dp
+	}
+
+	// One entry per calendar day from the first publication on, gaps carried forward, so the
+	// moving averages are over calendar days.
+	var days []*candleDay
+	for d := warmFrom; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if dp, ok := byDate[d.Format("2006-01-02")]; ok {
+			days = append(days, &candleDay{date: d, price: dp, close: dp.Last})
+		} else if len(days) > 0 {
+			days = append(days, &candleDay{date: d, close: days[len(days)-1].close, filled: true})
+		}
+		n := len(days)
+		if n == 0 {
+			continue
+		}
+		days[n-1].sma7 = movingAverage(days, smaShortDays)
+		days[n-1].sma30 = movingAverage(days, smaLongDays)
+	}
+
+	var candles []*Candle
+	var prevClose decimal.NullDecimal
+	for i := 0; i < len(days); {
+		bucket := candleBucketStart(days[i].date, interval)
+		j := i
+		for j < len(days) && candleBucketStart(days[j].date, interval).Equal(bucket) {
+			j++
+		}
+		inRange := !bucket.Before(from)
+		if inRange {
+			if c := bucketCandle(days[i:j], bucket, interval, prevClose); c != nil && (fillGaps || !c.Filled) {
+				candles = append(candles, c)
+			}
+		}
+		prevClose = decimal.NewNullDecimal(days[j-1].close)
+		i = j
+	}
+	return candles
+}
+
+// bucketCandle folds the days of one bucket into a candle.
+func bucketCandle(days []*candleDay, start time.Time, interval string, prevClose decimal.NullDecimal) *Candle {
+	last := days[len(days)-1]
+	c := &Candle{
+		PeriodStart: start,
+		PeriodEnd:   candleBucketEnd(start, interval),
+		Close:       last.close,
+		SMA7:        last.sma7,
+		SMA30:       last.sma30,
+		Filled:      true,
+	}
+	for _, d := range days {
+		if d.filled {
+			continue
+		}
+		if c.Filled {
+			c.Open, c.High, c.Low = d.price.Open, d.price.High, d.price.Low
+			c.Filled = false
+		}
+		c.High = decimal.Max(c.High, d.price.High)
+		c.Low = decimal.Min(c.Low, d.price.Low)
+		c.TickCount += d.price.TickCount
+	}
+	if c.Filled {
+		c.Open, c.High, c.Low = last.close, last.close, last.close
+	}
+	if prevClose.Valid && prevClose.Decimal.IsPositive() {
+		change := c.Close.Sub(prevClose.Decimal).Div(prevClose.Decimal).Mul(decimal.NewFromInt(100))
+		c.ChangePercent = decimal.NewNullDecimal(change.Round(2))
+	}
+	return c
+}
+
+// movingAverage is the mean close of the last n days, or null when fewer than n are known.
+func movingAverage(days []*candleDay, n int) decimal.NullDecimal {
+	if len(days) < n {
+		return decimal.NullDecimal{}
+	}
+	sum := decimal.Zero
+	for _, d := range days[len(days)-n:] {
+		sum = sum.Add(d.close)
+	}
+	return decimal.NewNullDecimal(util.RoundPrice(sum.Div(decimal.NewFromInt(int64(n)))))
+}
+
+// calendarDay drops the time of day, keeping the date as UTC midnight like DATE columns scan.
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func candleBucketStart(day time.Time, interval string) time.Time {
+	switch interval {
+	case CandleWeek:
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		return day.AddDate(0, 0, -offset)
+	case CandleMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func candleBucketEnd(start time.Time, interval string) time.Time {
+	switch interval {
+	case CandleWeek:
+		return start.AddDate(0, 0, 6)
+	case CandleMonth:
+		return start.AddDate(0, 1, -1)
+	default:
+		return start
+	}
+}
+
+func (service *AccountService) GetProductsWithGradesAndPrices(ctx context.Context, date time.Time, search string) ([]*ProductWithGrades, error) {
+	if date.IsZero() {
+		date = time.Now()
+	}
+	return service.repository.GetProductsWithGradesAndPrices(ctx, date, search)
+}
+
+// SetFxRate stores the rate of a currency pair from its effective date (today by default).
+// Setting a pair again for the same date replaces the rate.
+func (service *AccountService) SetFxRate(ctx context.Context, rate *FxRate) (*FxRate, error) {
+	base, err := util.ParseCurrency(rate.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := util.ParseCurrency(rate.QuoteCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if base == "" || quote == "" {
+		return nil, errors.New("base_currency and quote_currency are required")
+	}
+	if base == quote {
+		return nil, errors.New("base_currency and quote_currency must differ")
+	}
+	if !rate.Rate.IsPositive() {
+		return nil, errors.New("rate must be greater than zero")
+	}
+	if !rate.Rate.Round(FxRateScale).Equal(rate.Rate) {
+		return nil, fmt.Errorf("rate supports at most %d decimal places", FxRateScale)
+	}
+	effectiveDate := rate.EffectiveDate
+	if effectiveDate.IsZero() {
+		effectiveDate = time.Now()
+	}
+	return service.repository.UpsertFxRate(ctx, &FxRate{
+		ID:            ksuid.New().String(),
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          rate.Rate,
+		EffectiveDate: effectiveDate,
+		UpdatedBy:     rate.UpdatedBy,
+	})
+}
+
+// ListFxRates returns the stored rates newest first, optionally for one base and/or quote
+// currency.
+func (service *AccountService) ListFxRates(ctx context.Context, baseCurrency string, quoteCurrency string) ([]*FxRate, error) {
+	base, err := util.ParseCurrency(baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := util.ParseCurrency(quoteCurrency)
+	if err != nil {
+		return nil, err
+	}
+	return service.repository.ListFxRates(ctx, base, quote)
+}
+
+// SetFeeSchedule stores a fee or tax schedule for a grade or a product category from its
+// effective date (today by default). Setting the same scope, code and side again for the same
+// date replaces it.
+func (service *AccountService) SetFeeSchedule(ctx context.Context, schedule *FeeSchedule) (*FeeSchedule, error) {
+	if (schedule.GradeID == "") == (schedule.Category == "") {
+		return nil, errors.New("exactly one of grade_id and category is required")
+	}
+	if schedule.GradeID != "" {
+		if _, err := service.repository.GetGradeById(ctx, schedule.GradeID); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("grade %s not found", schedule.GradeID)
+			}
+			return nil, err
+		}
+	}
+	category := strings.ToLower(strings.TrimSpace(schedule.Category))
+	if category != "" && category != "spice" && category != "others" {
+		return nil, fmt.Errorf("unsupported category %q: must be spice or others", schedule.Category)
+	}
+	code := strings.ToUpper(strings.TrimSpace(schedule.Code))
+	if code == "" || len(code) > 32 {
+		return nil, errors.New("code is required and at most 32 characters")
+	}
+	kind := strings.ToUpper(schedule.Kind)
+	if kind != FeeKindFee && kind != FeeKindTax {
+		return nil, fmt.Errorf("kind must be %s or %s", FeeKindFee, FeeKindTax)
+	}
+	side := strings.ToUpper(schedule.Side)
+	if side == "" {
+		side = FeeSideBoth
+	}
+	if side != FeeSideBuy && side != FeeSideSell && side != FeeSideBoth {
+		return nil, fmt.Errorf("side must be %s, %s or %s", FeeSideBuy, FeeSideSell, FeeSideBoth)
+	}
+	basis := strings.ToUpper(schedule.Basis)
+	if basis != FeeBasisPercent && basis != FeeBasisPerKg && basis != FeeBasisFlat {
+		return nil, fmt.Errorf("basis must be %s, %s or %s", FeeBasisPercent, FeeBasisPerKg, FeeBasisFlat)
+	}
+	if schedule.Rate.IsNegative() {
+		return nil, errors.New("rate must not be negative")
+	}
+	if !util.RoundPrice(schedule.Rate).Equal(schedule.Rate) {
+		return nil, fmt.Errorf("rate supports at most %d decimal places", util.PriceScale)
+	}
+	if basis == FeeBasisPercent && schedule.Rate.GreaterThan(decimal.NewFromInt(100)) {
+		return nil, errors.New("a PERCENT rate must be at most 100")
+	}
+	currency, err := util.ParseCurrency(schedule.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		currency = util.DefaultCurrency
+	}
+	effectiveDate := schedule.EffectiveDate
+	if effectiveDate.IsZero() {
+		effectiveDate = time.Now()
+	}
+	return service.repository.UpsertFeeSchedule(ctx, &FeeSchedule{
+		ID:            ksuid.New().String(),
+		GradeID:       schedule.GradeID,
+		Category:      category,
+		Code:          code,
+		Kind:          kind,
+		Side:          side,
+		Basis:         basis,
+		Rate:          schedule.Rate,
+		Currency:      currency,
+		EffectiveDate: effectiveDate,
+		UpdatedBy:     schedule.UpdatedBy,
+	})
+}
+
+// ListFeeSchedules returns the stored schedules newest first, optionally for one grade and/or
+// category.
+func (service *AccountService) ListFeeSchedules(ctx context.Context, gradeID string, category string) ([]*FeeSchedule, error) {
+	return service.repository.ListFeeSchedules(ctx, gradeID, strings.ToLower(strings.TrimSpace(category)))
 }
@@ -1,23 +1,32 @@
 package gateway
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/control"
 	"github.com/Asif-Faizal/SpiceLedger-Backend/graphql"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/reports"
 	"github.com/Asif-Faizal/SpiceLedger-Backend/rest"
 	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
 )
 
 // Dependencies holds live connections owned by the API gateway process.
 type Dependencies struct {
-	REST     *rest.Server
-	GraphQL  *graphql.Server
-	closers  []func() error
+	REST    *rest.Server
+	GraphQL *graphql.Server
+	Reports *reports.Server
+	Keys    *util.KeySet
+	closers []func() error
 }
 
+// jwksMaxAge is how long clients may cache /.well-known/jwks.json. A rotated key is signing
+// tokens before it is in their copy, so clients should refetch on an unknown kid.
+const jwksMaxAge = 300
+
 // Close releases outbound gRPC connections.
 func (d *Dependencies) Close() error {
 	var first error
@@ -51,20 +60,41 @@ func NewDependencies(cfg *util.Config, logger util.Logger) (*Dependencies, error
 		return nil, fmt.Errorf("graphql gateway: %w", err)
 	}
 
+	reportsServer, err := reports.NewServer(cfg.ResolveMarketGrpcURL(), logger)
+	if err != nil {
+		_ = restServer.Close()
+		_ = gqlServer.Close()
+		return nil, fmt.Errorf("reports gateway: %w", err)
+	}
+
+	controlClient, err := control.NewControlClient(cfg.ResolveAccountGrpcURL())
+	if err != nil {
+		_ = restServer.Close()
+		_ = gqlServer.Close()
+		_ = reportsServer.Close()
+		return nil, fmt.Errorf("signing keys: %w", err)
+	}
+
 	return &Dependencies{
 		REST:    restServer,
 		GraphQL: gqlServer,
-		closers: []func() error{restServer.Close, gqlServer.Close},
+		Reports: reportsServer,
+		Keys:    util.NewKeySet(controlClient.SigningKeys, cfg.JWKSCacheTTL, logger),
+		closers: []func() error{restServer.Close, gqlServer.Close, reportsServer.Close, func() error {
+			controlClient.Close()
+			return nil
+		}},
 	}, nil
 }
 
-// NewHandler returns the unified edge HTTP handler for REST + GraphQL on one port.
+// NewHandler returns the unified edge HTTP handler for REST, GraphQL and report downloads on one port.
 func NewHandler(deps *Dependencies) http.Handler {
 	mux := http.NewServeMux()
 
 	restHandler := rest.NewHandler(deps.REST)
 	mux.Handle("/rest/", http.StripPrefix("/rest", restHandler))
 	mux.Handle("/graphql", graphql.NewHandler(deps.GraphQL))
+	mux.Handle("/reports/", http.StripPrefix("/reports", reports.NewHandler(deps.Reports)))
 	mux.Handle("/playground", playground.Handler("SpiceLedger GraphQL", "/graphql"))
 
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -73,6 +103,22 @@ func NewHandler(deps *Dependencies) http.Handler {
 		_, _ = w.Write([]byte(`{"success":true,"message":"","data":{"service":"gateway","status":"operational"}}`))
 	})
 
+	// The JWKS is the standard document, not wrapped in the response envelope.
+	mux.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			util.WriteJSONResponse(w, http.StatusMethodNotAllowed, false, "method not allowed", nil)
+			return
+		}
+		keys, err := deps.Keys.Keys(r.Context())
+		if err != nil {
+			util.WriteJSONResponse(w, http.StatusServiceUnavailable, false, err.Error(), nil)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", jwksMaxAge))
+		_ = json.NewEncoder(w).Encode(util.JWKS{Keys: keys})
+	})
+
 	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -86,9 +132,11 @@ func NewHandler(deps *Dependencies) http.Handler {
 		}
 		if strings.HasPrefix(r.URL.Path, "/rest") ||
 			strings.HasPrefix(r.URL.Path, "/graphql") ||
+			strings.HasPrefix(r.URL.Path, "/reports/") ||
 			r.URL.Path == "/playground" ||
 			r.URL.Path == "/health" ||
-			r.URL.Path == "/ready" {
+			r.URL.Path == "/ready" ||
+			r.URL.Path == "/.well-known/jwks.json" {
 			mux.ServeHTTP(w, r)
 			return
 		}
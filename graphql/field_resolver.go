@@ -0,0 +1,75 @@
+package graphql
+
+import (
+	"context"
+
+	marketpb "github.com/Asif-Faizal/SpiceLedger-Backend/market/pb"
+)
+
+// Allocations is the resolver for the allocations field on Transaction.
+func (r *transactionResolver) Allocations(ctx context.Context, obj *Transaction, includeReversed *bool) ([]*SellAllocation, error) {
+	if obj.Type != "SELL" {
+		return []*SellAllocation{}, nil
+	}
+	req := &marketpb.GetSellAllocationsRequest{
+		UserId:            obj.UserID,
+		SellTransactionId: obj.ID,
+		// A cancelled sell's allocations are all reversed; show them unless told otherwise.
+		IncludeReversed: obj.Status != "ACTIVE",
+	}
+	if includeReversed != nil {
+		req.IncludeReversed = *includeReversed
+	}
+	resp, err := r.server.marketClient.GetSellAllocations(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return sellAllocationsFromProto(resp.Allocations)
+}
+
+// Fees is the resolver for the fees field on Transaction.
+func (r *transactionResolver) Fees(ctx context.Context, obj *Transaction) ([]*TransactionFee, error) {
+	if !obj.FeeTotal.IsPositive() {
+		return []*TransactionFee{}, nil
+	}
+	resp, err := r.server.marketClient.ListTransactionFees(ctx, &marketpb.ListTransactionFeesRequest{
+		UserId:        obj.UserID,
+		TransactionId: obj.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var decimals decimalReader
+	fees := make([]*TransactionFee, len(resp.Fees))
+	for i, f := range resp.Fees {
+		fees[i] = &TransactionFee{
+			ID:            f.Id,
+			FeeScheduleID: f.FeeScheduleId,
+			Code:          f.Code,
+			Kind:          f.Kind,
+			Basis:         f.Basis,
+			Rate:          decimals.read(f.Rate),
+			Amount:        decimals.read(f.Amount),
+			Currency:      f.Currency,
+		}
+	}
+	if decimals.err != nil {
+		return nil, decimals.err
+	}
+	return fees, nil
+}
+
+// OpenLots is the resolver for the openLots field on PositionView.
+func (r *positionViewResolver) OpenLots(ctx context.Context, obj *PositionView, skip *int, take *int, sort *string) ([]*BuyLot, error) {
+	resp, err := r.server.marketClient.ListOpenLots(ctx, &marketpb.ListOpenLotsRequest{
+		UserId:       obj.UserID,
+		SpiceGradeId: obj.SpiceGradeID,
+		Skip:         uint32Value(skip),
+		Take:         uint32Value(take),
+		Sort:         stringValue(sort),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buyLotsFromProto(resp.Lots)
+}
@@ -86,6 +86,14 @@ func (s *Server) Query() QueryResolver {
 	return &queryResolver{server: s}
 }
 
+func (s *Server) Transaction() TransactionResolver {
+	return &transactionResolver{server: s}
+}
+
+func (s *Server) PositionView() PositionViewResolver {
+	return &positionViewResolver{server: s}
+}
+
 func (s *Server) __InputValue() __InputValueResolver {
 	return nil
 }
@@ -100,3 +108,5 @@ func (s *Server) ToExecutableSchema() graphql.ExecutableSchema {
 
 type mutationResolver struct{ server *Server }
 type queryResolver struct{ server *Server }
+type transactionResolver struct{ server *Server }
+type positionViewResolver struct{ server *Server }
@@ -37,7 +37,15 @@ func NewHandler(s *Server) http.Handler {
 		return gqlErr
 	})
 
-	return restResponseEnvelopeMiddleware(authMiddleware(srv))
+	return restResponseEnvelopeMiddleware(authMiddleware(clientMiddleware(idempotencyKeyMiddleware(srv))))
+}
+
+// clientMiddleware forwards the client's address and user agent to control, which records them
+// on the session.
+func clientMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(util.ForwardClient(r.Context(), r)))
+	})
 }
 
 func authMiddleware(next http.Handler) http.Handler {
@@ -53,3 +61,17 @@ func authMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// idempotencyKeyMiddleware exposes the Idempotency-Key header to the buy/sell resolvers.
+func idempotencyKeyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get(util.IdempotencyKeyHeader))
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), util.IdempotencyKeyKey, key)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
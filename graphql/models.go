@@ -1,5 +1,11 @@
 package graphql
 
+import (
+	controlpb "github.com/Asif-Faizal/SpiceLedger-Backend/control/pb"
+	marketpb "github.com/Asif-Faizal/SpiceLedger-Backend/market/pb"
+	"github.com/shopspring/decimal"
+)
+
 type ProductWithGradesAndPrice struct {
 	ID          string            `json:"id" validate:"required,uuid4"`
 	Name        string            `json:"name" validate:"required,min=3,max=255"`
@@ -10,33 +16,359 @@ type ProductWithGradesAndPrice struct {
 }
 
 type GradeWithPrice struct {
-	ID          string  `json:"id" validate:"required,uuid4"`
-	ProductID   string  `json:"product_id" validate:"required,uuid4"`
-	Name        string  `json:"name" validate:"required,min=3,max=255"`
-	Price       float64 `json:"price" validate:"required"`
-	Description string  `json:"description" validate:"omitempty,min=3,max=255"`
-	Status      string  `json:"status" validate:"required,oneof=active inactive"`
+	ID          string          `json:"id" validate:"required,uuid4"`
+	ProductID   string          `json:"product_id" validate:"required,uuid4"`
+	Name        string          `json:"name" validate:"required,min=3,max=255"`
+	Price       decimal.Decimal `json:"price" validate:"required"`
+	Currency    string          `json:"currency"`
+	Description string          `json:"description" validate:"omitempty,min=3,max=255"`
+	Status      string          `json:"status" validate:"required,oneof=active inactive"`
+	// ShelfLifeDays is 0 when the grade is not perishable.
+	ShelfLifeDays int             `json:"shelf_life_days"`
+	Unit          string          `json:"unit"`
+	KgPerUnit     decimal.Decimal `json:"kg_per_unit"`
 }
 
 type Transaction struct {
-	ID           string  `json:"id"`
-	UserID       string  `json:"user_id"`
-	SpiceGradeID string  `json:"spice_grade_id"`
-	Type         string  `json:"type"`
-	Quantity     float64 `json:"quantity"`
-	Price        float64 `json:"price"`
-	TradeDate    string  `json:"trade_date"`
-	CreatedAt    string  `json:"created_at"`
+	ID           string          `json:"id"`
+	UserID       string          `json:"user_id"`
+	SpiceGradeID string          `json:"spice_grade_id"`
+	Type         string          `json:"type"`
+	Quantity     decimal.Decimal `json:"quantity"`
+	Price        decimal.Decimal `json:"price"`
+	Currency     string          `json:"currency"`
+	TradeDate    string          `json:"trade_date"`
+	CreatedAt    string          `json:"created_at"`
+	// CostBasisMethod is set on SELL transactions only.
+	CostBasisMethod       *string         `json:"cost_basis_method,omitempty"`
+	Status                string          `json:"status"`
+	ReversesTransactionID *string         `json:"reverses_transaction_id,omitempty"`
+	AmendsTransactionID   *string         `json:"amends_transaction_id,omitempty"`
+	Note                  *string         `json:"note,omitempty"`
+	IdempotencyKey        *string         `json:"idempotency_key,omitempty"`
+	FeeTotal              decimal.Decimal `json:"fee_total"`
 }
 
 type PositionView struct {
-	UserID        string  `json:"user_id"`
-	SpiceGradeID  string  `json:"spice_grade_id"`
-	TotalQty      float64 `json:"total_qty"`
-	TotalCost     float64 `json:"total_cost"`
-	AvgCost       float64 `json:"avg_cost"`
-	TodayPrice    float64 `json:"today_price"`
-	RealizedPnL   float64 `json:"realized_pnl"`
-	UnrealizedPnL float64 `json:"unrealized_pnl"`
-	UpdatedAt     string  `json:"updated_at"`
+	UserID        string          `json:"user_id"`
+	SpiceGradeID  string          `json:"spice_grade_id"`
+	TotalQty      decimal.Decimal `json:"total_qty"`
+	TotalCost     decimal.Decimal `json:"total_cost"`
+	AvgCost       decimal.Decimal `json:"avg_cost"`
+	TodayPrice    decimal.Decimal `json:"today_price"`
+	RealizedPnL   decimal.Decimal `json:"realized_pnl"`
+	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl"`
+	UpdatedAt     string          `json:"updated_at"`
+	PriceDate     *string         `json:"price_date"`
+	PriceSource   *string         `json:"price_source"`
+	Stale         bool            `json:"stale"`
+	// Currency holds the amounts above; the reporting amounts are nil when no FX rate converted them.
+	Currency               string           `json:"currency"`
+	ReportingCurrency      string           `json:"reporting_currency"`
+	FxRate                 *decimal.Decimal `json:"fx_rate"`
+	ReportingTotalCost     *decimal.Decimal `json:"reporting_total_cost"`
+	ReportingRealizedPnL   *decimal.Decimal `json:"reporting_realized_pnl"`
+	ReportingUnrealizedPnL *decimal.Decimal `json:"reporting_unrealized_pnl"`
+	// Unit is the grade's; TotalQty is in kilograms and UnitQuantity in Unit.
+	Unit         string          `json:"unit"`
+	KgPerUnit    decimal.Decimal `json:"kg_per_unit"`
+	UnitQuantity decimal.Decimal `json:"unit_quantity"`
+}
+
+func transactionFromProto(t *marketpb.Transaction) (*Transaction, error) {
+	var decimals decimalReader
+	txn := &Transaction{
+		ID:           t.Id,
+		UserID:       t.UserId,
+		SpiceGradeID: t.SpiceGradeId,
+		Type:         t.Type,
+		Quantity:     decimals.read(t.Quantity),
+		Price:        decimals.read(t.Price),
+		Currency:     t.Currency,
+		TradeDate:    t.TradeDate,
+		CreatedAt:    t.CreatedAt,
+		Status:       t.Status,
+		FeeTotal:     decimals.read(t.FeeTotal),
+	}
+	txn.CostBasisMethod = optionalString(t.CostBasisMethod)
+	txn.ReversesTransactionID = optionalString(t.ReversesTransactionId)
+	txn.AmendsTransactionID = optionalString(t.AmendsTransactionId)
+	txn.Note = optionalString(t.Note)
+	txn.IdempotencyKey = optionalString(t.IdempotencyKey)
+	return txn, decimals.err
+}
+
+func transactionsFromProto(txns []*marketpb.Transaction) ([]*Transaction, error) {
+	out := make([]*Transaction, len(txns))
+	for i, t := range txns {
+		txn, err := transactionFromProto(t)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = txn
+	}
+	return out, nil
+}
+
+func positionViewFromProto(p *marketpb.PositionView) (*PositionView, error) {
+	var decimals decimalReader
+	view := &PositionView{
+		UserID:                 p.UserId,
+		SpiceGradeID:           p.SpiceGradeId,
+		TotalQty:               decimals.read(p.TotalQty),
+		TotalCost:              decimals.read(p.TotalCost),
+		AvgCost:                decimals.read(p.AvgCost),
+		TodayPrice:             decimals.read(p.TodayPrice),
+		RealizedPnL:            decimals.read(p.RealizedPnl),
+		UnrealizedPnL:          decimals.read(p.UnrealizedPnl),
+		UpdatedAt:              p.UpdatedAt,
+		PriceDate:              optionalString(p.PriceDate),
+		PriceSource:            optionalString(p.PriceSource),
+		Stale:                  p.Stale,
+		Currency:               p.Currency,
+		ReportingCurrency:      p.ReportingCurrency,
+		FxRate:                 decimals.optional(p.FxRate),
+		ReportingTotalCost:     decimals.optional(p.ReportingTotalCost),
+		ReportingRealizedPnL:   decimals.optional(p.ReportingRealizedPnl),
+		ReportingUnrealizedPnL: decimals.optional(p.ReportingUnrealizedPnl),
+		Unit:                   p.Unit,
+		KgPerUnit:              decimals.read(p.KgPerUnit),
+		UnitQuantity:           decimals.read(p.UnitQty),
+	}
+	return view, decimals.err
+}
+
+func buyLotFromProto(l *marketpb.BuyLot) (*BuyLot, error) {
+	var decimals decimalReader
+	lot := &BuyLot{
+		ID:            l.Id,
+		TransactionID: l.TransactionId,
+		UserID:        l.UserId,
+		SpiceGradeID:  l.SpiceGradeId,
+		OriginalQty:   decimals.read(l.OriginalQty),
+		RemainingQty:  decimals.read(l.RemainingQty),
+		Price:         decimals.read(l.Price),
+		TradeDate:     l.TradeDate,
+		CreatedAt:     l.CreatedAt,
+	}
+	return lot, decimals.err
+}
+
+func buyLotsFromProto(lots []*marketpb.BuyLot) ([]*BuyLot, error) {
+	out := make([]*BuyLot, len(lots))
+	for i, l := range lots {
+		lot, err := buyLotFromProto(l)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = lot
+	}
+	return out, nil
+}
+
+func sellAllocationsFromProto(allocs []*marketpb.SellAllocation) ([]*SellAllocation, error) {
+	var decimals decimalReader
+	out := make([]*SellAllocation, len(allocs))
+	for i, a := range allocs {
+		out[i] = &SellAllocation{
+			ID:                      a.Id,
+			SellTransactionID:       a.SellTransactionId,
+			BuyLotID:                a.BuyLotId,
+			UserID:                  a.UserId,
+			SpiceGradeID:            a.SpiceGradeId,
+			Quantity:                decimals.read(a.Quantity),
+			BuyPrice:                decimals.read(a.BuyPrice),
+			SellPrice:               decimals.read(a.SellPrice),
+			RealizedPnL:             decimals.read(a.RealizedPnl),
+			CostBasisMethod:         a.CostBasisMethod,
+			SellTradeDate:           a.SellTradeDate,
+			ReversedByTransactionID: optionalString(a.ReversedByTransactionId),
+			CreatedAt:               a.CreatedAt,
+		}
+	}
+	return out, decimals.err
+}
+
+// stringValue and uint32Value unwrap optional GraphQL arguments; null becomes the zero value.
+func orderFromProto(o *marketpb.Order) (*Order, error) {
+	var decimals decimalReader
+	order := &Order{
+		ID:           o.Id,
+		UserID:       o.UserId,
+		SpiceGradeID: o.SpiceGradeId,
+		Side:         o.Side,
+		Price:        decimals.read(o.Price),
+		Currency:     o.Currency,
+		Quantity:     decimals.read(o.Quantity),
+		RemainingQty: decimals.read(o.RemainingQty),
+		Status:       o.Status,
+		CreatedAt:    optionalString(o.CreatedAt),
+		UpdatedAt:    optionalString(o.UpdatedAt),
+	}
+	return order, decimals.err
+}
+
+func orderResultFromProto(o *marketpb.Order, fills []*marketpb.OrderFill) (*OrderResult, error) {
+	order, err := orderFromProto(o)
+	if err != nil {
+		return nil, err
+	}
+	var decimals decimalReader
+	out := &OrderResult{Order: order, Fills: make([]*OrderFill, len(fills))}
+	for i, f := range fills {
+		out.Fills[i] = &OrderFill{
+			ID:                f.Id,
+			SpiceGradeID:      f.SpiceGradeId,
+			BuyOrderID:        f.BuyOrderId,
+			SellOrderID:       f.SellOrderId,
+			BuyTransactionID:  f.BuyTransactionId,
+			SellTransactionID: f.SellTransactionId,
+			Quantity:          decimals.read(f.Quantity),
+			Price:             decimals.read(f.Price),
+			CreatedAt:         f.CreatedAt,
+		}
+	}
+	return out, decimals.err
+}
+
+func orderBookLevelsFromProto(levels []*marketpb.OrderBookLevel) ([]*OrderBookLevel, error) {
+	var decimals decimalReader
+	out := make([]*OrderBookLevel, len(levels))
+	for i, l := range levels {
+		out[i] = &OrderBookLevel{
+			Price:    decimals.read(l.Price),
+			Quantity: decimals.read(l.Quantity),
+			Orders:   int(l.Orders),
+		}
+	}
+	return out, decimals.err
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// decimalString maps an optional decimal to its proto string; nil is empty.
+func decimalString(v *decimal.Decimal) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func uint32Value(v *int) uint32 {
+	if v == nil || *v < 0 {
+		return 0
+	}
+	return uint32(*v)
+}
+
+// optionalString maps an empty proto string to a GraphQL null.
+func optionalString(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func dailyPriceFromProto(dp *controlpb.DailyPrice) (*DailyPrice, error) {
+	var decimals decimalReader
+	price := &DailyPrice{
+		ID:        dp.Id,
+		ProductID: dp.ProductId,
+		GradeID:   dp.GradeId,
+		Price:     decimals.read(dp.Price),
+		Date:      dp.Date,
+		Time:      dp.Time,
+		Open:      decimals.read(dp.Open),
+		High:      decimals.read(dp.High),
+		Low:       decimals.read(dp.Low),
+		Last:      decimals.read(dp.Last),
+		Close:     decimals.optional(dp.Close),
+		TickCount: int(dp.TickCount),
+		Currency:  dp.Currency,
+	}
+	return price, decimals.err
+}
+
+func priceTickFromProto(t *controlpb.PriceTick) (*PriceTick, error) {
+	var decimals decimalReader
+	tick := &PriceTick{
+		ID:          t.Id,
+		ProductID:   t.ProductId,
+		GradeID:     t.GradeId,
+		Price:       decimals.read(t.Price),
+		Currency:    t.Currency,
+		Source:      t.Source,
+		PublishedBy: optionalString(t.PublishedBy),
+		TickedAt:    t.TickedAt,
+		Status:      t.Status,
+		SubmittedAt: optionalString(t.SubmittedAt),
+		ReviewedBy:  optionalString(t.ReviewedBy),
+		ReviewedAt:  optionalString(t.ReviewedAt),
+		ReviewNote:  optionalString(t.ReviewNote),
+	}
+	return tick, decimals.err
+}
+
+func priceTicksFromProto(ticks []*controlpb.PriceTick) ([]*PriceTick, error) {
+	out := make([]*PriceTick, len(ticks))
+	for i, t := range ticks {
+		tick, err := priceTickFromProto(t)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = tick
+	}
+	return out, nil
+}
+
+func feeScheduleFromProto(fs *controlpb.FeeSchedule) (*FeeSchedule, error) {
+	var decimals decimalReader
+	schedule := &FeeSchedule{
+		ID:            fs.Id,
+		GradeID:       optionalString(fs.GradeId),
+		Category:      optionalString(fs.Category),
+		Code:          fs.Code,
+		Kind:          fs.Kind,
+		Side:          fs.Side,
+		Basis:         fs.Basis,
+		Rate:          decimals.read(fs.Rate),
+		Currency:      fs.Currency,
+		EffectiveDate: fs.EffectiveDate,
+		UpdatedBy:     optionalString(fs.UpdatedBy),
+		UpdatedAt:     fs.UpdatedAt,
+	}
+	return schedule, decimals.err
+}
+
+func fxRateFromProto(rate *controlpb.FxRate) (*FxRate, error) {
+	var decimals decimalReader
+	fx := &FxRate{
+		ID:            rate.Id,
+		BaseCurrency:  rate.BaseCurrency,
+		QuoteCurrency: rate.QuoteCurrency,
+		Rate:          decimals.read(rate.Rate),
+		EffectiveDate: rate.EffectiveDate,
+		UpdatedBy:     optionalString(rate.UpdatedBy),
+		UpdatedAt:     rate.UpdatedAt,
+	}
+	return fx, decimals.err
+}
+
+func sessionFromProto(s *controlpb.Session) *Session {
+	return &Session{
+		ID:         s.Id,
+		DeviceID:   s.DeviceId,
+		DeviceName: optionalString(s.DeviceName),
+		IPAddress:  optionalString(s.IpAddress),
+		UserAgent:  optionalString(s.UserAgent),
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+		Current:    s.Current,
+	}
 }
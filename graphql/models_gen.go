@@ -2,57 +2,124 @@
 
 package graphql
 
+import (
+	"github.com/shopspring/decimal"
+)
+
 type ActivityDay struct {
-	Date         string  `json:"date"`
-	BuyQuantity  float64 `json:"buyQuantity"`
-	SellQuantity float64 `json:"sellQuantity"`
-	BuyCount     int     `json:"buyCount"`
-	SellCount    int     `json:"sellCount"`
+	Date         string          `json:"date"`
+	BuyQuantity  decimal.Decimal `json:"buyQuantity"`
+	SellQuantity decimal.Decimal `json:"sellQuantity"`
+	BuyCount     int             `json:"buyCount"`
+	SellCount    int             `json:"sellCount"`
 }
 
 type ActivityDayDetail struct {
 	Date         string                `json:"date"`
-	BuyQuantity  float64               `json:"buyQuantity"`
-	SellQuantity float64               `json:"sellQuantity"`
+	BuyQuantity  decimal.Decimal       `json:"buyQuantity"`
+	SellQuantity decimal.Decimal       `json:"sellQuantity"`
 	BuyCount     int                   `json:"buyCount"`
 	SellCount    int                   `json:"sellCount"`
 	Products     []*ActivityProductDay `json:"products"`
 }
 
 type ActivityProductDay struct {
-	SpiceGradeID string  `json:"spiceGradeId"`
-	ProductName  string  `json:"productName"`
-	GradeName    string  `json:"gradeName"`
-	BuyQuantity  float64 `json:"buyQuantity"`
-	SellQuantity float64 `json:"sellQuantity"`
-	BuyCount     int     `json:"buyCount"`
-	SellCount    int     `json:"sellCount"`
+	SpiceGradeID string          `json:"spiceGradeId"`
+	ProductName  string          `json:"productName"`
+	GradeName    string          `json:"gradeName"`
+	BuyQuantity  decimal.Decimal `json:"buyQuantity"`
+	SellQuantity decimal.Decimal `json:"sellQuantity"`
+	BuyCount     int             `json:"buyCount"`
+	SellCount    int             `json:"sellCount"`
 }
 
 type AdminDashboard struct {
-	TotalUsers         int            `json:"totalUsers"`
-	TotalProducts      int            `json:"totalProducts"`
-	TotalTransactions  int            `json:"totalTransactions"`
-	TotalVolume        float64        `json:"totalVolume"`
-	RecentTransactions []*Transaction `json:"recentTransactions"`
-	TopProducts        []*TopProduct  `json:"topProducts"`
+	TotalUsers        int `json:"totalUsers"`
+	TotalProducts     int `json:"totalProducts"`
+	TotalTransactions int `json:"totalTransactions"`
+	// Quantity traded (kg); a quantity, so it is not converted.
+	TotalVolume decimal.Decimal `json:"totalVolume"`
+	// Value traded, converted into currency (the caller's reporting currency).
+	TotalValue decimal.Decimal `json:"totalValue"`
+	Currency   string          `json:"currency"`
+	// Trade currencies with no FX rate into currency; left out of totalValue.
+	UnconvertedCurrencies []string       `json:"unconvertedCurrencies"`
+	RecentTransactions    []*Transaction `json:"recentTransactions"`
+	TopProducts           []*TopProduct  `json:"topProducts"`
+}
+
+type AgeingBucket struct {
+	// 0-30 | 31-90 | 90+ | EXPIRED
+	Label    string          `json:"label"`
+	Quantity decimal.Decimal `json:"quantity"`
+	Cost     decimal.Decimal `json:"cost"`
+	Lots     int             `json:"lots"`
+}
+
+type BuyLot struct {
+	ID            string          `json:"id"`
+	TransactionID string          `json:"transactionId"`
+	UserID        string          `json:"userId"`
+	SpiceGradeID  string          `json:"spiceGradeId"`
+	OriginalQty   decimal.Decimal `json:"originalQty"`
+	RemainingQty  decimal.Decimal `json:"remainingQty"`
+	Price         decimal.Decimal `json:"price"`
+	TradeDate     string          `json:"tradeDate"`
+	CreatedAt     string          `json:"createdAt"`
+}
+
+// OHLC of one DAY, WEEK or MONTH bucket. close is the bucket's last price so far.
+type Candle struct {
+	PeriodStart string          `json:"periodStart"`
+	PeriodEnd   string          `json:"periodEnd"`
+	Open        decimal.Decimal `json:"open"`
+	High        decimal.Decimal `json:"high"`
+	Low         decimal.Decimal `json:"low"`
+	Close       decimal.Decimal `json:"close"`
+	TickCount   int             `json:"tickCount"`
+	// Change of close against the previous close, in percent.
+	ChangePercent *float64 `json:"changePercent,omitempty"`
+	// Moving averages of daily closes up to the end of the bucket.
+	Sma7  *decimal.Decimal `json:"sma7,omitempty"`
+	Sma30 *decimal.Decimal `json:"sma30,omitempty"`
+	// No price was published in the bucket; it is carried at the previous close.
+	Filled bool `json:"filled"`
+}
+
+type CostBasisPreference struct {
+	UserID       string  `json:"userId"`
+	SpiceGradeID *string `json:"spiceGradeId,omitempty"`
+	Method       string  `json:"method"`
+	Source       string  `json:"source"`
+	UpdatedAt    *string `json:"updatedAt,omitempty"`
 }
 
 type CreateDailyPriceInput struct {
-	ID        string  `json:"id"`
-	ProductID string  `json:"productId"`
-	GradeID   string  `json:"gradeId"`
-	Price     float64 `json:"price"`
-	Date      string  `json:"date"`
-	Time      string  `json:"time"`
+	ID        string          `json:"id"`
+	ProductID string          `json:"productId"`
+	GradeID   string          `json:"gradeId"`
+	Price     decimal.Decimal `json:"price"`
+	// ISO 4217 code; defaults to INR.
+	Currency *string `json:"currency,omitempty"`
+	Date     string  `json:"date"`
+	Time     string  `json:"time"`
+	// Where the price came from; defaults to MANUAL.
+	Source *string `json:"source,omitempty"`
+	// Keep the price as a DRAFT instead of submitting it for approval.
+	Draft *bool `json:"draft,omitempty"`
 }
 
 type CreateGradeInput struct {
-	ID          string  `json:"id"`
-	ProductID   string  `json:"productId"`
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
-	Status      *string `json:"status,omitempty"`
+	ID            string  `json:"id"`
+	ProductID     string  `json:"productId"`
+	Name          string  `json:"name"`
+	Description   *string `json:"description,omitempty"`
+	Status        *string `json:"status,omitempty"`
+	ShelfLifeDays *int    `json:"shelfLifeDays,omitempty"`
+	// KG (default), QUINTAL, TONNE or BAG.
+	Unit *string `json:"unit,omitempty"`
+	// Required for BAG; fixed for the other units.
+	KgPerUnit *decimal.Decimal `json:"kgPerUnit,omitempty"`
 }
 
 type CreateProductInput struct {
@@ -63,19 +130,103 @@ type CreateProductInput struct {
 	Status      *string `json:"status,omitempty"`
 }
 
+// The rollup of a grade's price ticks on one date. price is the last tick.
 type DailyPrice struct {
-	ID        string  `json:"id"`
-	ProductID string  `json:"productId"`
-	GradeID   string  `json:"gradeId"`
-	Price     float64 `json:"price"`
-	Date      string  `json:"date"`
-	Time      string  `json:"time"`
+	ID        string          `json:"id"`
+	ProductID string          `json:"productId"`
+	GradeID   string          `json:"gradeId"`
+	Price     decimal.Decimal `json:"price"`
+	Date      string          `json:"date"`
+	// Time of the last tick.
+	Time string          `json:"time"`
+	Open decimal.Decimal `json:"open"`
+	High decimal.Decimal `json:"high"`
+	Low  decimal.Decimal `json:"low"`
+	Last decimal.Decimal `json:"last"`
+	// Last tick of a day that has ended; null while the day is open.
+	Close     *decimal.Decimal `json:"close,omitempty"`
+	TickCount int              `json:"tickCount"`
+	Currency  string           `json:"currency"`
+}
+
+// A fee or tax charged on trades of one grade, or of every grade in a category, from effectiveDate.
+// rate is a percentage of trade value (PERCENT), or an amount in currency per kilogram (PER_KG) or
+// per trade (FLAT).
+type FeeSchedule struct {
+	ID       string  `json:"id"`
+	GradeID  *string `json:"gradeId,omitempty"`
+	Category *string `json:"category,omitempty"`
+	Code     string  `json:"code"`
+	// FEE or TAX.
+	Kind string `json:"kind"`
+	// BUY, SELL or BOTH.
+	Side          string          `json:"side"`
+	Basis         string          `json:"basis"`
+	Rate          decimal.Decimal `json:"rate"`
+	Currency      string          `json:"currency"`
+	EffectiveDate string          `json:"effectiveDate"`
+	UpdatedBy     *string         `json:"updatedBy,omitempty"`
+	UpdatedAt     string          `json:"updatedAt"`
+}
+
+type FeeScheduleInput struct {
+	// Exactly one of gradeId and category.
+	GradeID  *string `json:"gradeId,omitempty"`
+	Category *string `json:"category,omitempty"`
+	Code     string  `json:"code"`
+	Kind     string  `json:"kind"`
+	// Defaults to BOTH.
+	Side  *string         `json:"side,omitempty"`
+	Basis string          `json:"basis"`
+	Rate  decimal.Decimal `json:"rate"`
+	// Defaults to INR.
+	Currency *string `json:"currency,omitempty"`
+	// Defaults to today.
+	EffectiveDate *string `json:"effectiveDate,omitempty"`
+}
+
+// 1 baseCurrency = rate quoteCurrency from effectiveDate until a later rate of the pair.
+type FxRate struct {
+	ID            string          `json:"id"`
+	BaseCurrency  string          `json:"baseCurrency"`
+	QuoteCurrency string          `json:"quoteCurrency"`
+	Rate          decimal.Decimal `json:"rate"`
+	EffectiveDate string          `json:"effectiveDate"`
+	UpdatedBy     *string         `json:"updatedBy,omitempty"`
+	UpdatedAt     string          `json:"updatedAt"`
+}
+
+type GradeAgeing struct {
+	SpiceGradeID   string          `json:"spiceGradeId"`
+	ProductName    string          `json:"productName"`
+	GradeName      string          `json:"gradeName"`
+	ShelfLifeDays  int             `json:"shelfLifeDays"`
+	Buckets        []*AgeingBucket `json:"buckets"`
+	NearExpiryQty  decimal.Decimal `json:"nearExpiryQty"`
+	NearExpiryLots int             `json:"nearExpiryLots"`
+	NextExpiryDate *string         `json:"nextExpiryDate,omitempty"`
+}
+
+type LotAgeing struct {
+	AsOf    string          `json:"asOf"`
+	Buckets []*AgeingBucket `json:"buckets"`
+	Grades  []*GradeAgeing  `json:"grades"`
+}
+
+type LotHistory struct {
+	Lot         *BuyLot           `json:"lot"`
+	Allocations []*SellAllocation `json:"allocations"`
+}
+
+type LotSelectionInput struct {
+	LotID    string           `json:"lotId"`
+	Quantity *decimal.Decimal `json:"quantity,omitempty"`
 }
 
 type MerchantActivityTrend struct {
 	Days              int                  `json:"days"`
-	TotalBuyQuantity  float64              `json:"totalBuyQuantity"`
-	TotalSellQuantity float64              `json:"totalSellQuantity"`
+	TotalBuyQuantity  decimal.Decimal      `json:"totalBuyQuantity"`
+	TotalSellQuantity decimal.Decimal      `json:"totalSellQuantity"`
 	TotalTrades       int                  `json:"totalTrades"`
 	Points            []*ActivityDayDetail `json:"points"`
 }
@@ -92,18 +243,30 @@ type MerchantDashboard struct {
 }
 
 type MerchantHolding struct {
-	SpiceGradeID         string  `json:"spiceGradeId"`
-	ProductName          string  `json:"productName"`
-	GradeName            string  `json:"gradeName"`
-	Quantity             float64 `json:"quantity"`
-	AvgCost              float64 `json:"avgCost"`
-	TodayPrice           float64 `json:"todayPrice"`
-	MarketValue          float64 `json:"marketValue"`
-	CostBasis            float64 `json:"costBasis"`
-	UnrealizedPnL        float64 `json:"unrealizedPnL"`
-	UnrealizedPnLPercent float64 `json:"unrealizedPnLPercent"`
-	RealizedPnL          float64 `json:"realizedPnL"`
-	WeightPercent        float64 `json:"weightPercent"`
+	SpiceGradeID         string          `json:"spiceGradeId"`
+	ProductName          string          `json:"productName"`
+	GradeName            string          `json:"gradeName"`
+	Quantity             decimal.Decimal `json:"quantity"`
+	AvgCost              decimal.Decimal `json:"avgCost"`
+	TodayPrice           decimal.Decimal `json:"todayPrice"`
+	MarketValue          decimal.Decimal `json:"marketValue"`
+	CostBasis            decimal.Decimal `json:"costBasis"`
+	UnrealizedPnL        decimal.Decimal `json:"unrealizedPnL"`
+	UnrealizedPnLPercent float64         `json:"unrealizedPnLPercent"`
+	RealizedPnL          decimal.Decimal `json:"realizedPnL"`
+	WeightPercent        float64         `json:"weightPercent"`
+	PriceDate            *string         `json:"priceDate,omitempty"`
+	PriceSource          *string         `json:"priceSource,omitempty"`
+	Stale                bool            `json:"stale"`
+	// Currency of the amounts above: the reporting currency when fxRate converted them, otherwise
+	// the position's own currency.
+	Currency string `json:"currency"`
+	// Position currency → reporting currency; null when no rate is stored.
+	FxRate *decimal.Decimal `json:"fxRate,omitempty"`
+	// The grade's unit; quantity is in kilograms and unitQuantity is it in unit.
+	Unit         string          `json:"unit"`
+	KgPerUnit    decimal.Decimal `json:"kgPerUnit"`
+	UnitQuantity decimal.Decimal `json:"unitQuantity"`
 }
 
 type MerchantInsight struct {
@@ -116,66 +279,216 @@ type MerchantInsight struct {
 
 type MerchantPnlTrend struct {
 	Days              int             `json:"days"`
-	PeriodRealizedPnL float64         `json:"periodRealizedPnL"`
+	PeriodRealizedPnL decimal.Decimal `json:"periodRealizedPnL"`
 	Points            []*PnLDayDetail `json:"points"`
 }
 
+// Money amounts are in currency, the account's reporting currency. Holdings without an FX rate
+// into it are left out of the totals.
 type MerchantSummary struct {
-	PortfolioValue     float64 `json:"portfolioValue"`
-	TotalCost          float64 `json:"totalCost"`
-	TotalRealizedPnL   float64 `json:"totalRealizedPnL"`
-	TotalUnrealizedPnL float64 `json:"totalUnrealizedPnL"`
-	NetPnL             float64 `json:"netPnL"`
-	OpenPositions      int     `json:"openPositions"`
-	TotalQuantityKg    float64 `json:"totalQuantityKg"`
-	TradesInPeriod     int     `json:"tradesInPeriod"`
-	BuyVolumeInPeriod  float64 `json:"buyVolumeInPeriod"`
-	SellVolumeInPeriod float64 `json:"sellVolumeInPeriod"`
+	Currency           string          `json:"currency"`
+	PortfolioValue     decimal.Decimal `json:"portfolioValue"`
+	TotalCost          decimal.Decimal `json:"totalCost"`
+	TotalRealizedPnL   decimal.Decimal `json:"totalRealizedPnL"`
+	TotalUnrealizedPnL decimal.Decimal `json:"totalUnrealizedPnL"`
+	NetPnL             decimal.Decimal `json:"netPnL"`
+	OpenPositions      int             `json:"openPositions"`
+	// Held quantity of every grade in kilograms, the base unit.
+	TotalQuantityKg    decimal.Decimal `json:"totalQuantityKg"`
+	TradesInPeriod     int             `json:"tradesInPeriod"`
+	BuyVolumeInPeriod  decimal.Decimal `json:"buyVolumeInPeriod"`
+	SellVolumeInPeriod decimal.Decimal `json:"sellVolumeInPeriod"`
 }
 
 type Mutation struct {
 }
 
+type Order struct {
+	ID           string          `json:"id"`
+	UserID       string          `json:"userId"`
+	SpiceGradeID string          `json:"spiceGradeId"`
+	Side         string          `json:"side"`
+	Price        decimal.Decimal `json:"price"`
+	// Orders only match orders of the same currency.
+	Currency     string          `json:"currency"`
+	Quantity     decimal.Decimal `json:"quantity"`
+	RemainingQty decimal.Decimal `json:"remainingQty"`
+	Status       string          `json:"status"`
+	CreatedAt    *string         `json:"createdAt,omitempty"`
+	UpdatedAt    *string         `json:"updatedAt,omitempty"`
+}
+
+type OrderBook struct {
+	SpiceGradeID string            `json:"spiceGradeId"`
+	Currency     string            `json:"currency"`
+	Bids         []*OrderBookLevel `json:"bids"`
+	Asks         []*OrderBookLevel `json:"asks"`
+}
+
+type OrderBookLevel struct {
+	Price    decimal.Decimal `json:"price"`
+	Quantity decimal.Decimal `json:"quantity"`
+	Orders   int             `json:"orders"`
+}
+
+type OrderFill struct {
+	ID                string          `json:"id"`
+	SpiceGradeID      string          `json:"spiceGradeId"`
+	BuyOrderID        string          `json:"buyOrderId"`
+	SellOrderID       string          `json:"sellOrderId"`
+	BuyTransactionID  string          `json:"buyTransactionId"`
+	SellTransactionID string          `json:"sellTransactionId"`
+	Quantity          decimal.Decimal `json:"quantity"`
+	Price             decimal.Decimal `json:"price"`
+	CreatedAt         string          `json:"createdAt"`
+}
+
+type OrderResult struct {
+	Order *Order       `json:"order"`
+	Fills []*OrderFill `json:"fills"`
+}
+
 type PnLDayDetail struct {
 	Date                  string           `json:"date"`
-	DailyRealizedPnL      float64          `json:"dailyRealizedPnL"`
-	CumulativeRealizedPnL float64          `json:"cumulativeRealizedPnL"`
+	DailyRealizedPnL      decimal.Decimal  `json:"dailyRealizedPnL"`
+	CumulativeRealizedPnL decimal.Decimal  `json:"cumulativeRealizedPnL"`
 	Products              []*PnLProductDay `json:"products"`
 }
 
 type PnLPoint struct {
-	Date                  string  `json:"date"`
-	DailyRealizedPnL      float64 `json:"dailyRealizedPnL"`
-	CumulativeRealizedPnL float64 `json:"cumulativeRealizedPnL"`
+	Date                  string          `json:"date"`
+	DailyRealizedPnL      decimal.Decimal `json:"dailyRealizedPnL"`
+	CumulativeRealizedPnL decimal.Decimal `json:"cumulativeRealizedPnL"`
 }
 
 type PnLProductDay struct {
-	SpiceGradeID string  `json:"spiceGradeId"`
-	ProductName  string  `json:"productName"`
-	GradeName    string  `json:"gradeName"`
-	RealizedPnL  float64 `json:"realizedPnL"`
+	SpiceGradeID string          `json:"spiceGradeId"`
+	ProductName  string          `json:"productName"`
+	GradeName    string          `json:"gradeName"`
+	RealizedPnL  decimal.Decimal `json:"realizedPnL"`
 }
 
 type PortfolioSlice struct {
-	Label    string  `json:"label"`
-	Value    float64 `json:"value"`
-	Quantity float64 `json:"quantity"`
+	Label    string          `json:"label"`
+	Value    decimal.Decimal `json:"value"`
+	Quantity decimal.Decimal `json:"quantity"`
 }
 
 type PriceMover struct {
-	SpiceGradeID  string  `json:"spiceGradeId"`
-	ProductName   string  `json:"productName"`
-	GradeName     string  `json:"gradeName"`
-	TodayPrice    float64 `json:"todayPrice"`
-	PreviousPrice float64 `json:"previousPrice"`
-	ChangePercent float64 `json:"changePercent"`
-	Direction     string  `json:"direction"`
+	SpiceGradeID  string          `json:"spiceGradeId"`
+	ProductName   string          `json:"productName"`
+	GradeName     string          `json:"gradeName"`
+	TodayPrice    decimal.Decimal `json:"todayPrice"`
+	PreviousPrice decimal.Decimal `json:"previousPrice"`
+	ChangePercent float64         `json:"changePercent"`
+	Direction     string          `json:"direction"`
+	PriceDate     *string         `json:"priceDate,omitempty"`
+	PriceSource   *string         `json:"priceSource,omitempty"`
+	Stale         bool            `json:"stale"`
+	// Currency of both prices: the position's.
+	Currency string `json:"currency"`
+}
+
+type PriceReview struct {
+	Ticks []*PriceTick `json:"ticks"`
+	// Daily prices rebuilt by an approval; empty for a rejection.
+	DailyPrices []*DailyPrice `json:"dailyPrices"`
+}
+
+type PriceSeries struct {
+	GradeID   string    `json:"gradeId"`
+	ProductID string    `json:"productId"`
+	Interval  string    `json:"interval"`
+	Candles   []*Candle `json:"candles"`
+}
+
+// A proposed price. It counts towards daily prices only once a second admin approves it.
+// status: DRAFT | SUBMITTED | APPROVED | REJECTED.
+type PriceTick struct {
+	ID        string          `json:"id"`
+	ProductID string          `json:"productId"`
+	GradeID   string          `json:"gradeId"`
+	Price     decimal.Decimal `json:"price"`
+	Currency  string          `json:"currency"`
+	Source    string          `json:"source"`
+	// Admin who proposed the price.
+	PublishedBy *string `json:"publishedBy,omitempty"`
+	TickedAt    string  `json:"tickedAt"`
+	Status      string  `json:"status"`
+	SubmittedAt *string `json:"submittedAt,omitempty"`
+	// Admin who approved or rejected the price.
+	ReviewedBy *string `json:"reviewedBy,omitempty"`
+	ReviewedAt *string `json:"reviewedAt,omitempty"`
+	ReviewNote *string `json:"reviewNote,omitempty"`
 }
 
 type Query struct {
 }
 
+type SellAllocation struct {
+	ID                      string          `json:"id"`
+	SellTransactionID       string          `json:"sellTransactionId"`
+	BuyLotID                string          `json:"buyLotId"`
+	UserID                  string          `json:"userId"`
+	SpiceGradeID            string          `json:"spiceGradeId"`
+	Quantity                decimal.Decimal `json:"quantity"`
+	BuyPrice                decimal.Decimal `json:"buyPrice"`
+	SellPrice               decimal.Decimal `json:"sellPrice"`
+	RealizedPnL             decimal.Decimal `json:"realizedPnL"`
+	CostBasisMethod         string          `json:"costBasisMethod"`
+	SellTradeDate           string          `json:"sellTradeDate"`
+	ReversedByTransactionID *string         `json:"reversedByTransactionId,omitempty"`
+	CreatedAt               string          `json:"createdAt"`
+}
+
+// A signed-in device. ipAddress and userAgent are the client's as last seen; current marks the
+// session making the request.
+type Session struct {
+	ID         string  `json:"id"`
+	DeviceID   string  `json:"deviceId"`
+	DeviceName *string `json:"deviceName,omitempty"`
+	IPAddress  *string `json:"ipAddress,omitempty"`
+	UserAgent  *string `json:"userAgent,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+	LastSeenAt string  `json:"lastSeenAt"`
+	ExpiresAt  string  `json:"expiresAt"`
+	Current    bool    `json:"current"`
+}
+
 type TopProduct struct {
-	Name   string  `json:"name"`
-	Volume float64 `json:"volume"`
+	Name   string          `json:"name"`
+	Volume decimal.Decimal `json:"volume"`
+}
+
+type TradingPermissions struct {
+	UserID            string  `json:"userId"`
+	AllowShortSelling bool    `json:"allowShortSelling"`
+	UpdatedBy         *string `json:"updatedBy,omitempty"`
+	UpdatedAt         *string `json:"updatedAt,omitempty"`
+}
+
+type TransactionAmendment struct {
+	Transaction        *Transaction `json:"transaction"`
+	Original           *Transaction `json:"original"`
+	Reversal           *Transaction `json:"reversal"`
+	ReallocatedSellIds []string     `json:"reallocatedSellIds"`
+}
+
+type TransactionCancellation struct {
+	Original           *Transaction `json:"original"`
+	Reversal           *Transaction `json:"reversal"`
+	ReallocatedSellIds []string     `json:"reallocatedSellIds"`
+}
+
+type TransactionFee struct {
+	ID            string `json:"id"`
+	FeeScheduleID string `json:"feeScheduleId"`
+	Code          string `json:"code"`
+	// FEE or TAX.
+	Kind string `json:"kind"`
+	// PERCENT, PER_KG or FLAT.
+	Basis    string          `json:"basis"`
+	Rate     decimal.Decimal `json:"rate"`
+	Amount   decimal.Decimal `json:"amount"`
+	Currency string          `json:"currency"`
 }
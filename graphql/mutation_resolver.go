@@ -5,6 +5,8 @@ import (
 
 	"github.com/Asif-Faizal/SpiceLedger-Backend/control/pb"
 	marketpb "github.com/Asif-Faizal/SpiceLedger-Backend/market/pb"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+	"github.com/shopspring/decimal"
 )
 
 // CreateProduct is the resolver for the createProduct field.
@@ -55,97 +57,350 @@ func (r *mutationResolver) CreateGrade(ctx context.Context, input CreateGradeInp
 	}
 
 	resp, err := r.server.controlClient.CreateOrUpdateGrade(ctx, &pb.CreateOrUpdateGradeRequest{
-		Id:          input.ID,
-		ProductId:   input.ProductID,
-		Name:        input.Name,
-		Description: desc,
-		Status:      status,
+		Id:            input.ID,
+		ProductId:     input.ProductID,
+		Name:          input.Name,
+		Description:   desc,
+		Status:        status,
+		ShelfLifeDays: uint32Value(input.ShelfLifeDays),
+		Unit:          stringValue(input.Unit),
+		KgPerUnit:     decimalString(input.KgPerUnit),
 	})
 	if err != nil {
 		return nil, err
 	}
+	kgPerUnit, err := decimalFromProto(resp.Grade.KgPerUnit)
+	if err != nil {
+		return nil, err
+	}
 	return &GradeWithPrice{
-		ID:          resp.Grade.Id,
-		ProductID:   resp.Grade.ProductId,
-		Name:        resp.Grade.Name,
-		Description: resp.Grade.Description,
-		Status:      resp.Grade.Status,
+		ID:            resp.Grade.Id,
+		ProductID:     resp.Grade.ProductId,
+		Name:          resp.Grade.Name,
+		Description:   resp.Grade.Description,
+		Status:        resp.Grade.Status,
+		ShelfLifeDays: int(resp.Grade.ShelfLifeDays),
+		Unit:          resp.Grade.Unit,
+		KgPerUnit:     kgPerUnit,
 	}, nil
 }
 
 // CreateDailyPrice is the resolver for the createDailyPrice field.
-func (r *mutationResolver) CreateDailyPrice(ctx context.Context, input CreateDailyPriceInput) (*DailyPrice, error) {
+func (r *mutationResolver) CreateDailyPrice(ctx context.Context, input CreateDailyPriceInput) (*PriceTick, error) {
 	resp, err := r.server.controlClient.CreateOrUpdateDailyPrice(ctx, &pb.CreateOrUpdateDailyPriceRequest{
 		Id:        input.ID,
 		ProductId: input.ProductID,
 		GradeId:   input.GradeID,
-		Price:     input.Price,
+		Price:     input.Price.String(),
+		Currency:  stringValue(input.Currency),
 		Date:      input.Date,
 		Time:      input.Time,
+		Source:    stringValue(input.Source),
+		Draft:     input.Draft != nil && *input.Draft,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &DailyPrice{
-		ID:        resp.DailyPrice.Id,
-		ProductID: resp.DailyPrice.ProductId,
-		GradeID:   resp.DailyPrice.GradeId,
-		Price:     resp.DailyPrice.Price,
-		Date:      resp.DailyPrice.Date,
-		Time:      resp.DailyPrice.Time,
-	}, nil
+	return priceTickFromProto(resp.Tick)
+}
+
+// SubmitDailyPrice is the resolver for the submitDailyPrice field.
+func (r *mutationResolver) SubmitDailyPrice(ctx context.Context, id string) (*PriceTick, error) {
+	resp, err := r.server.controlClient.SubmitPriceTick(ctx, &pb.SubmitPriceTickRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return priceTickFromProto(resp.Tick)
+}
+
+// ReviewDailyPrices is the resolver for the reviewDailyPrices field.
+func (r *mutationResolver) ReviewDailyPrices(ctx context.Context, ids []string, decision string, note *string) (*PriceReview, error) {
+	resp, err := r.server.controlClient.ReviewPriceTicks(ctx, &pb.ReviewPriceTicksRequest{
+		Ids:      ids,
+		Decision: decision,
+		Note:     stringValue(note),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ticks, err := priceTicksFromProto(resp.Ticks)
+	if err != nil {
+		return nil, err
+	}
+	review := &PriceReview{
+		Ticks:       ticks,
+		DailyPrices: make([]*DailyPrice, len(resp.DailyPrices)),
+	}
+	for i, dp := range resp.DailyPrices {
+		if review.DailyPrices[i], err = dailyPriceFromProto(dp); err != nil {
+			return nil, err
+		}
+	}
+	return review, nil
 }
 
 // Buy is the resolver for the buy field.
-func (r *mutationResolver) Buy(ctx context.Context, spiceGradeID string, quantity float64, price float64, tradeDate *string) (*Transaction, error) {
+func (r *mutationResolver) Buy(ctx context.Context, spiceGradeID string, quantity decimal.Decimal, price decimal.Decimal, tradeDate *string, idempotencyKey *string, unit *string) (*Transaction, error) {
 	dateStr := ""
 	if tradeDate != nil {
 		dateStr = *tradeDate
 	}
 	resp, err := r.server.marketClient.Buy(ctx, &marketpb.BuyRequest{
-		SpiceGradeId: spiceGradeID,
-		Quantity:     quantity,
-		Price:        price,
-		TradeDate:    dateStr,
+		SpiceGradeId:   spiceGradeID,
+		Quantity:       quantity.String(),
+		Price:          price.String(),
+		TradeDate:      dateStr,
+		IdempotencyKey: resolveIdempotencyKey(ctx, idempotencyKey),
+		Unit:           stringValue(unit),
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{
-		ID:           resp.Transaction.Id,
-		UserID:       resp.Transaction.UserId,
-		SpiceGradeID: resp.Transaction.SpiceGradeId,
-		Type:         resp.Transaction.Type,
-		Quantity:     resp.Transaction.Quantity,
-		Price:        resp.Transaction.Price,
-		TradeDate:    resp.Transaction.TradeDate,
-		CreatedAt:    resp.Transaction.CreatedAt,
-	}, nil
+	return transactionFromProto(resp.Transaction)
 }
 
 // Sell is the resolver for the sell field.
-func (r *mutationResolver) Sell(ctx context.Context, spiceGradeID string, quantity float64, price float64, tradeDate *string) (*Transaction, error) {
+func (r *mutationResolver) Sell(ctx context.Context, spiceGradeID string, quantity decimal.Decimal, price decimal.Decimal, tradeDate *string, costBasisMethod *string, lots []*LotSelectionInput, idempotencyKey *string, unit *string) (*Transaction, error) {
 	dateStr := ""
 	if tradeDate != nil {
 		dateStr = *tradeDate
 	}
+	methodStr := ""
+	if costBasisMethod != nil {
+		methodStr = *costBasisMethod
+	}
 	resp, err := r.server.marketClient.Sell(ctx, &marketpb.SellRequest{
+		SpiceGradeId:    spiceGradeID,
+		Quantity:        quantity.String(),
+		Price:           price.String(),
+		TradeDate:       dateStr,
+		CostBasisMethod: methodStr,
+		Lots:            lotSelectionsToProto(lots),
+		IdempotencyKey:  resolveIdempotencyKey(ctx, idempotencyKey),
+		Unit:            stringValue(unit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transactionFromProto(resp.Transaction)
+}
+
+// SetCostBasisMethod is the resolver for the setCostBasisMethod field.
+func (r *mutationResolver) SetCostBasisMethod(ctx context.Context, spiceGradeID *string, method string) (*CostBasisPreference, error) {
+	gradeStr := ""
+	if spiceGradeID != nil {
+		gradeStr = *spiceGradeID
+	}
+	resp, err := r.server.marketClient.SetCostBasisMethod(ctx, &marketpb.SetCostBasisMethodRequest{
+		SpiceGradeId: gradeStr,
+		Method:       method,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return costBasisPreferenceFromProto(resp.Preference), nil
+}
+
+// SetTradingPermissions is the resolver for the setTradingPermissions field.
+func (r *mutationResolver) SetTradingPermissions(ctx context.Context, userID string, allowShortSelling bool) (*TradingPermissions, error) {
+	resp, err := r.server.marketClient.SetTradingPermissions(ctx, &marketpb.SetTradingPermissionsRequest{
+		UserId:            userID,
+		AllowShortSelling: allowShortSelling,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tradingPermissionsFromProto(resp.Permissions), nil
+}
+
+// PlaceOrder is the resolver for the placeOrder field.
+func (r *mutationResolver) PlaceOrder(ctx context.Context, spiceGradeID string, side string, quantity decimal.Decimal, price decimal.Decimal) (*OrderResult, error) {
+	resp, err := r.server.marketClient.PlaceOrder(ctx, &marketpb.PlaceOrderRequest{
 		SpiceGradeId: spiceGradeID,
-		Quantity:     quantity,
-		Price:        price,
-		TradeDate:    dateStr,
+		Side:         side,
+		Quantity:     quantity.String(),
+		Price:        price.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orderResultFromProto(resp.Order, resp.Fills)
+}
+
+// AmendOrder is the resolver for the amendOrder field.
+func (r *mutationResolver) AmendOrder(ctx context.Context, id string, quantity *decimal.Decimal, price *decimal.Decimal) (*OrderResult, error) {
+	req := &marketpb.AmendOrderRequest{OrderId: id}
+	if quantity != nil {
+		req.Quantity = quantity.String()
+	}
+	if price != nil {
+		req.Price = price.String()
+	}
+	resp, err := r.server.marketClient.AmendOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return orderResultFromProto(resp.Order, resp.Fills)
+}
+
+// CancelOrder is the resolver for the cancelOrder field.
+func (r *mutationResolver) CancelOrder(ctx context.Context, id string) (*Order, error) {
+	resp, err := r.server.marketClient.CancelOrder(ctx, &marketpb.CancelOrderRequest{OrderId: id})
+	if err != nil {
+		return nil, err
+	}
+	return orderFromProto(resp.Order)
+}
+
+// CancelTransaction is the resolver for the cancelTransaction field.
+func (r *mutationResolver) CancelTransaction(ctx context.Context, id string, reason *string, reallocate *bool) (*TransactionCancellation, error) {
+	req := &marketpb.CancelTransactionRequest{TransactionId: id}
+	if reason != nil {
+		req.Reason = *reason
+	}
+	if reallocate != nil {
+		req.Reallocate = *reallocate
+	}
+	resp, err := r.server.marketClient.CancelTransaction(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	txns, err := transactionsFromProto([]*marketpb.Transaction{resp.Original, resp.Reversal})
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionCancellation{
+		Original:           txns[0],
+		Reversal:           txns[1],
+		ReallocatedSellIds: nonNilStrings(resp.ReallocatedSellIds),
+	}, nil
+}
+
+// SetFxRate is the resolver for the setFxRate field.
+func (r *mutationResolver) SetFxRate(ctx context.Context, baseCurrency string, quoteCurrency string, rate decimal.Decimal, effectiveDate *string) (*FxRate, error) {
+	resp, err := r.server.controlClient.SetFxRate(ctx, &pb.SetFxRateRequest{
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+		Rate:          rate.String(),
+		EffectiveDate: stringValue(effectiveDate),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fxRateFromProto(resp.Rate)
+}
+
+// SetFeeSchedule is the resolver for the setFeeSchedule field.
+func (r *mutationResolver) SetFeeSchedule(ctx context.Context, input FeeScheduleInput) (*FeeSchedule, error) {
+	resp, err := r.server.controlClient.SetFeeSchedule(ctx, &pb.SetFeeScheduleRequest{
+		GradeId:       stringValue(input.GradeID),
+		Category:      stringValue(input.Category),
+		Code:          input.Code,
+		Kind:          input.Kind,
+		Side:          stringValue(input.Side),
+		Basis:         input.Basis,
+		Rate:          input.Rate.String(),
+		Currency:      stringValue(input.Currency),
+		EffectiveDate: stringValue(input.EffectiveDate),
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{
-		ID:           resp.Transaction.Id,
-		UserID:       resp.Transaction.UserId,
-		SpiceGradeID: resp.Transaction.SpiceGradeId,
-		Type:         resp.Transaction.Type,
-		Quantity:     resp.Transaction.Quantity,
-		Price:        resp.Transaction.Price,
-		TradeDate:    resp.Transaction.TradeDate,
-		CreatedAt:    resp.Transaction.CreatedAt,
+	return feeScheduleFromProto(resp.Schedule)
+}
+
+// RevokeSession is the resolver for the revokeSession field.
+func (r *mutationResolver) RevokeSession(ctx context.Context, id string) (bool, error) {
+	resp, err := r.server.controlClient.RevokeSession(ctx, &pb.RevokeSessionRequest{SessionId: id})
+	if err != nil {
+		return false, err
+	}
+	return resp.Success, nil
+}
+
+// RevokeOtherSessions is the resolver for the revokeOtherSessions field.
+func (r *mutationResolver) RevokeOtherSessions(ctx context.Context) (int, error) {
+	resp, err := r.server.controlClient.RevokeAllOtherSessions(ctx, &pb.RevokeAllOtherSessionsRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return int(resp.Revoked), nil
+}
+
+// ForceLogout is the resolver for the forceLogout field.
+func (r *mutationResolver) ForceLogout(ctx context.Context, accountID string) (int, error) {
+	resp, err := r.server.controlClient.ForceLogout(ctx, &pb.ForceLogoutRequest{AccountId: accountID})
+	if err != nil {
+		return 0, err
+	}
+	return int(resp.Revoked), nil
+}
+
+// AmendTransaction is the resolver for the amendTransaction field.
+func (r *mutationResolver) AmendTransaction(ctx context.Context, id string, quantity *decimal.Decimal, price *decimal.Decimal, tradeDate *string, reason *string, reallocate *bool, costBasisMethod *string, lots []*LotSelectionInput) (*TransactionAmendment, error) {
+	req := &marketpb.AmendTransactionRequest{
+		TransactionId: id,
+		Lots:          lotSelectionsToProto(lots),
+	}
+	if quantity != nil {
+		req.Quantity = quantity.String()
+	}
+	if price != nil {
+		req.Price = price.String()
+	}
+	if tradeDate != nil {
+		req.TradeDate = *tradeDate
+	}
+	if reason != nil {
+		req.Reason = *reason
+	}
+	if reallocate != nil {
+		req.Reallocate = *reallocate
+	}
+	if costBasisMethod != nil {
+		req.CostBasisMethod = *costBasisMethod
+	}
+	resp, err := r.server.marketClient.AmendTransaction(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	txns, err := transactionsFromProto([]*marketpb.Transaction{resp.Transaction, resp.Original, resp.Reversal})
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionAmendment{
+		Transaction:        txns[0],
+		Original:           txns[1],
+		Reversal:           txns[2],
+		ReallocatedSellIds: nonNilStrings(resp.ReallocatedSellIds),
 	}, nil
 }
+
+func lotSelectionsToProto(lots []*LotSelectionInput) []*marketpb.LotSelection {
+	selections := make([]*marketpb.LotSelection, 0, len(lots))
+	for _, lot := range lots {
+		sel := &marketpb.LotSelection{LotId: lot.LotID}
+		if lot.Quantity != nil {
+			sel.Quantity = lot.Quantity.String()
+		}
+		selections = append(selections, sel)
+	}
+	return selections
+}
+
+// nonNilStrings keeps non-null GraphQL lists from serialising as null.
+func nonNilStrings(v []string) []string {
+	if v == nil {
+		return []string{}
+	}
+	return v
+}
+
+// resolveIdempotencyKey prefers the mutation argument over the Idempotency-Key request header.
+func resolveIdempotencyKey(ctx context.Context, arg *string) string {
+	if arg != nil && *arg != "" {
+		return *arg
+	}
+	key, _ := ctx.Value(util.IdempotencyKeyKey).(string)
+	return key
+}
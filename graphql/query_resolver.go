@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/Asif-Faizal/SpiceLedger-Backend/control/pb"
 	marketpb "github.com/Asif-Faizal/SpiceLedger-Backend/market/pb"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+	"github.com/shopspring/decimal"
 )
 
 // Products is the resolver for the products field.
@@ -32,17 +35,22 @@ func (r *queryResolver) Products(ctx context.Context, date *string, search *stri
 		return nil, err
 	}
 
+	var decimals decimalReader
 	products := make([]*ProductWithGradesAndPrice, len(resp.Products))
 	for i, p := range resp.Products {
 		grades := make([]*GradeWithPrice, len(p.Grades))
 		for j, g := range p.Grades {
 			grades[j] = &GradeWithPrice{
-				ID:          g.Id,
-				ProductID:   g.ProductId,
-				Name:        g.Name,
-				Description: g.Description,
-				Status:      g.Status,
-				Price:       g.Price,
+				ID:            g.Id,
+				ProductID:     g.ProductId,
+				Name:          g.Name,
+				Description:   g.Description,
+				Status:        g.Status,
+				Price:         decimals.read(g.Price),
+				Currency:      g.Currency,
+				ShelfLifeDays: int(g.ShelfLifeDays),
+				Unit:          g.Unit,
+				KgPerUnit:     decimals.read(g.KgPerUnit),
 			}
 		}
 
@@ -55,9 +63,74 @@ func (r *queryResolver) Products(ctx context.Context, date *string, search *stri
 			Grades:      grades,
 		}
 	}
+	if decimals.err != nil {
+		return nil, decimals.err
+	}
 	return products, nil
 }
 
+// PriceTicks is the resolver for the priceTicks field.
+func (r *queryResolver) PriceTicks(ctx context.Context, gradeID *string, date *string, status *string) ([]*PriceTick, error) {
+	resp, err := r.server.controlClient.ListPriceTicks(ctx, &pb.ListPriceTicksRequest{
+		GradeId: stringValue(gradeID),
+		Date:    stringValue(date),
+		Status:  stringValue(status),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return priceTicksFromProto(resp.Ticks)
+}
+
+// PriceCandles is the resolver for the priceCandles field.
+func (r *queryResolver) PriceCandles(ctx context.Context, gradeID *string, productID *string, interval *string, dateFrom *string, dateTo *string, fillGaps *bool) ([]*PriceSeries, error) {
+	resp, err := r.server.controlClient.GetPriceCandles(ctx, &pb.GetPriceCandlesRequest{
+		GradeId:   stringValue(gradeID),
+		ProductId: stringValue(productID),
+		Interval:  stringValue(interval),
+		DateFrom:  stringValue(dateFrom),
+		DateTo:    stringValue(dateTo),
+		FillGaps:  fillGaps != nil && *fillGaps,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var decimals decimalReader
+	series := make([]*PriceSeries, len(resp.Series))
+	for i, ps := range resp.Series {
+		candles := make([]*Candle, len(ps.Candles))
+		for j, c := range ps.Candles {
+			candles[j] = &Candle{
+				PeriodStart: c.PeriodStart,
+				PeriodEnd:   c.PeriodEnd,
+				Open:        decimals.read(c.Open),
+				High:        decimals.read(c.High),
+				Low:         decimals.read(c.Low),
+				Close:       decimals.read(c.Close),
+				TickCount:   int(c.TickCount),
+				Sma7:        decimals.optional(c.Sma_7),
+				Sma30:       decimals.optional(c.Sma_30),
+				Filled:      c.Filled,
+			}
+			if c.ChangePercent != "" {
+				change := decimals.read(c.ChangePercent).InexactFloat64()
+				candles[j].ChangePercent = &change
+			}
+		}
+		series[i] = &PriceSeries{
+			GradeID:   ps.GradeId,
+			ProductID: ps.ProductId,
+			Interval:  ps.Interval,
+			Candles:   candles,
+		}
+	}
+	if decimals.err != nil {
+		return nil, decimals.err
+	}
+	return series, nil
+}
+
 // GetGradePosition is the resolver for the getGradePosition field.
 func (r *queryResolver) GetGradePosition(ctx context.Context, spiceGradeID string) (*PositionView, error) {
 	resp, err := r.server.marketClient.GetGradePosition(ctx, &marketpb.GetGradePositionRequest{
@@ -66,17 +139,7 @@ func (r *queryResolver) GetGradePosition(ctx context.Context, spiceGradeID strin
 	if err != nil {
 		return nil, err
 	}
-	return &PositionView{
-		UserID:        resp.Position.UserId,
-		SpiceGradeID:  resp.Position.SpiceGradeId,
-		TotalQty:      resp.Position.TotalQty,
-		TotalCost:     resp.Position.TotalCost,
-		AvgCost:       resp.Position.AvgCost,
-		TodayPrice:    resp.Position.TodayPrice,
-		RealizedPnL:   resp.Position.RealizedPnl,
-		UnrealizedPnL: resp.Position.UnrealizedPnl,
-		UpdatedAt:     resp.Position.UpdatedAt,
-	}, nil
+	return positionViewFromProto(resp.Position)
 }
 
 // GetPositions is the resolver for the getPositions field.
@@ -87,16 +150,8 @@ func (r *queryResolver) GetPositions(ctx context.Context) ([]*PositionView, erro
 	}
 	positions := make([]*PositionView, len(resp.Positions))
 	for i, p := range resp.Positions {
-		positions[i] = &PositionView{
-			UserID:        p.UserId,
-			SpiceGradeID:  p.SpiceGradeId,
-			TotalQty:      p.TotalQty,
-			TotalCost:     p.TotalCost,
-			AvgCost:       p.AvgCost,
-			TodayPrice:    p.TodayPrice,
-			RealizedPnL:   p.RealizedPnl,
-			UnrealizedPnL: p.UnrealizedPnl,
-			UpdatedAt:     p.UpdatedAt,
+		if positions[i], err = positionViewFromProto(p); err != nil {
+			return nil, err
 		}
 	}
 	return positions, nil
@@ -132,20 +187,7 @@ func (r *queryResolver) ListGradeTransactions(ctx context.Context, spiceGradeID
 	if err != nil {
 		return nil, err
 	}
-	transactions := make([]*Transaction, len(resp.Transactions))
-	for i, t := range resp.Transactions {
-		transactions[i] = &Transaction{
-			ID:           t.Id,
-			UserID:       t.UserId,
-			SpiceGradeID: t.SpiceGradeId,
-			Type:         t.Type,
-			Quantity:     t.Quantity,
-			Price:        t.Price,
-			TradeDate:    t.TradeDate,
-			CreatedAt:    t.CreatedAt,
-		}
-	}
-	return transactions, nil
+	return transactionsFromProto(resp.Transactions)
 }
 
 // AdminDashboard is the resolver for the adminDashboard field.
@@ -170,36 +212,35 @@ func (r *queryResolver) AdminDashboard(ctx context.Context) (*AdminDashboard, er
 		return nil, err
 	}
 
-	recentTransactions := make([]*Transaction, len(txns.Transactions))
-	for i, t := range txns.Transactions {
-		recentTransactions[i] = &Transaction{
-			ID:           t.Id,
-			UserID:       t.UserId,
-			SpiceGradeID: t.SpiceGradeId,
-			Type:         t.Type,
-			Quantity:     t.Quantity,
-			Price:        t.Price,
-			TradeDate:    t.TradeDate,
-			CreatedAt:    t.CreatedAt,
-		}
+	recentTransactions, err := transactionsFromProto(txns.Transactions)
+	if err != nil {
+		return nil, err
 	}
 
+	var decimals decimalReader
 	topProducts := make([]*TopProduct, len(marketResp.TopProducts))
 	for i, p := range marketResp.TopProducts {
 		topProducts[i] = &TopProduct{
 			Name:   fmt.Sprintf("%s - %s", p.ProductName, p.GradeName),
-			Volume: p.Volume,
+			Volume: decimals.read(p.Volume),
 		}
 	}
 
-	return &AdminDashboard{
-		TotalUsers:         int(systemResp.TotalUsers),
-		TotalProducts:      int(systemResp.TotalProducts),
-		TotalTransactions:  int(marketResp.TotalTransactions),
-		TotalVolume:        marketResp.TotalVolume,
-		RecentTransactions: recentTransactions,
-		TopProducts:        topProducts,
-	}, nil
+	dashboard := &AdminDashboard{
+		TotalUsers:            int(systemResp.TotalUsers),
+		TotalProducts:         int(systemResp.TotalProducts),
+		TotalTransactions:     int(marketResp.TotalTransactions),
+		TotalVolume:           decimals.read(marketResp.TotalVolume),
+		TotalValue:            decimals.read(marketResp.TotalValue),
+		Currency:              marketResp.Currency,
+		UnconvertedCurrencies: marketResp.UnconvertedCurrencies,
+		RecentTransactions:    recentTransactions,
+		TopProducts:           topProducts,
+	}
+	if decimals.err != nil {
+		return nil, decimals.err
+	}
+	return dashboard, nil
 }
 
 // MerchantDashboard is the resolver for the merchantDashboard field.
@@ -250,73 +291,110 @@ func (r *queryResolver) MerchantDashboard(ctx context.Context, days *int) (*Merc
 		return nil, err
 	}
 
+	// 7. Get lot ageing for shelf-life alerts
+	ageingResp, err := r.server.marketClient.GetLotAgeing(ctx, &marketpb.GetLotAgeingRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Holdings are converted into the reporting currency; one without an FX rate keeps its own
+	// currency and stays out of the totals.
+	var decimals decimalReader
+	reportingCurrency := holdingsResp.ReportingCurrency
 	holdings := make([]*MerchantHolding, 0, len(holdingsResp.Holdings))
-	var totalPortfolioValue float64
+	var unconverted []*MerchantHolding
+	totalPortfolioValue := decimal.Zero
 	for _, row := range holdingsResp.Holdings {
 		h := &MerchantHolding{
 			SpiceGradeID: row.SpiceGradeId,
 			ProductName:  row.ProductName,
 			GradeName:    row.GradeName,
-			Quantity:     row.Quantity,
-			CostBasis:    row.TotalCost,
-			RealizedPnL:  row.RealizedPnl,
-			TodayPrice:   row.TodayPrice,
+			Quantity:     decimals.read(row.Quantity),
+			PriceDate:    optionalString(row.PriceDate),
+			PriceSource:  optionalString(row.PriceSource),
+			Stale:        row.Stale,
+			Currency:     row.Currency,
+			FxRate:       decimals.optional(row.FxRate),
+			Unit:         row.Unit,
+			KgPerUnit:    decimals.read(row.KgPerUnit),
+			UnitQuantity: decimals.read(row.UnitQty),
+		}
+		rate := decimal.NewFromInt(1)
+		if h.FxRate != nil {
+			rate = *h.FxRate
+			h.Currency = reportingCurrency
 		}
-		if row.Quantity > 0 {
-			h.AvgCost = row.TotalCost / row.Quantity
+		h.CostBasis = util.RoundMoney(decimals.read(row.TotalCost).Mul(rate), h.Currency)
+		h.RealizedPnL = util.RoundMoney(decimals.read(row.RealizedPnl).Mul(rate), h.Currency)
+		h.TodayPrice = util.RoundPrice(decimals.read(row.TodayPrice).Mul(rate))
+		if h.Quantity.IsPositive() {
+			h.AvgCost = h.CostBasis.DivRound(h.Quantity, util.PriceScale)
 		}
-		if row.TodayPrice > 0 {
-			h.MarketValue = row.Quantity * row.TodayPrice
-			h.UnrealizedPnL = (row.TodayPrice - h.AvgCost) * row.Quantity
+		if h.TodayPrice.IsPositive() {
+			h.MarketValue = util.RoundMoney(h.Quantity.Mul(h.TodayPrice), h.Currency)
+			h.UnrealizedPnL = h.MarketValue.Sub(h.CostBasis)
 		} else {
-			h.MarketValue = row.TotalCost
+			h.MarketValue = h.CostBasis
 		}
-		if row.TotalCost > 0 {
-			h.UnrealizedPnLPercent = (h.UnrealizedPnL / row.TotalCost) * 100
+		if h.CostBasis.IsPositive() {
+			h.UnrealizedPnLPercent = percentOf(h.UnrealizedPnL, h.CostBasis)
+		}
+		if h.FxRate != nil {
+			totalPortfolioValue = totalPortfolioValue.Add(h.MarketValue)
+		} else {
+			unconverted = append(unconverted, h)
 		}
-		totalPortfolioValue += h.MarketValue
 		holdings = append(holdings, h)
 	}
-	if totalPortfolioValue > 0 {
+	if totalPortfolioValue.IsPositive() {
 		for _, h := range holdings {
-			h.WeightPercent = (h.MarketValue / totalPortfolioValue) * 100
+			if h.FxRate != nil {
+				h.WeightPercent = percentOf(h.MarketValue, totalPortfolioValue)
+			}
 		}
 	}
 
-	portfolioMix := make([]*PortfolioSlice, len(holdings))
-	for i, h := range holdings {
-		portfolioMix[i] = &PortfolioSlice{
+	portfolioMix := make([]*PortfolioSlice, 0, len(holdings))
+	for _, h := range holdings {
+		if h.FxRate == nil {
+			continue
+		}
+		portfolioMix = append(portfolioMix, &PortfolioSlice{
 			Label:    fmt.Sprintf("%s - %s", h.ProductName, h.GradeName),
 			Value:    h.MarketValue,
 			Quantity: h.Quantity,
-		}
+		})
 	}
 
 	summary := &MerchantSummary{
+		Currency:           reportingCurrency,
 		OpenPositions:      len(holdings),
 		TradesInPeriod:     int(statsResp.TradesInPeriod),
-		BuyVolumeInPeriod:  statsResp.BuyVolumeInPeriod,
-		SellVolumeInPeriod: statsResp.SellVolumeInPeriod,
+		BuyVolumeInPeriod:  decimals.read(statsResp.BuyVolumeInPeriod),
+		SellVolumeInPeriod: decimals.read(statsResp.SellVolumeInPeriod),
 	}
 	for _, h := range holdings {
-		summary.TotalCost += h.CostBasis
-		summary.TotalRealizedPnL += h.RealizedPnL
-		summary.TotalUnrealizedPnL += h.UnrealizedPnL
-		summary.PortfolioValue += h.MarketValue
-		summary.TotalQuantityKg += h.Quantity
+		if h.FxRate == nil {
+			continue
+		}
+		summary.TotalCost = summary.TotalCost.Add(h.CostBasis)
+		summary.TotalRealizedPnL = summary.TotalRealizedPnL.Add(h.RealizedPnL)
+		summary.TotalUnrealizedPnL = summary.TotalUnrealizedPnL.Add(h.UnrealizedPnL)
+		summary.PortfolioValue = summary.PortfolioValue.Add(h.MarketValue)
+		summary.TotalQuantityKg = summary.TotalQuantityKg.Add(h.Quantity)
 	}
-	summary.NetPnL = summary.TotalRealizedPnL + summary.TotalUnrealizedPnL
+	summary.NetPnL = summary.TotalRealizedPnL.Add(summary.TotalUnrealizedPnL)
 
-	pnlByDate := make(map[string]float64, len(pnlResp.Rows))
+	pnlByDate := make(map[string]decimal.Decimal, len(pnlResp.Rows))
 	for _, row := range pnlResp.Rows {
-		pnlByDate[row.Date] += row.Amount
+		pnlByDate[row.Date] = pnlByDate[row.Date].Add(decimals.read(row.Amount))
 	}
 	pnlTrend := make([]*PnLPoint, 0, windowDays+1)
-	var cumulativePnL float64
+	cumulativePnL := decimal.Zero
 	for i := int(windowDays); i >= 0; i-- {
 		key := time.Now().AddDate(0, 0, -i).Format("2006-01-02")
 		daily := pnlByDate[key]
-		cumulativePnL += daily
+		cumulativePnL = cumulativePnL.Add(daily)
 		pnlTrend = append(pnlTrend, &PnLPoint{
 			Date:                  key,
 			DailyRealizedPnL:      daily,
@@ -333,10 +411,10 @@ func (r *queryResolver) MerchantDashboard(ctx context.Context, days *int) (*Merc
 		}
 		switch row.Type {
 		case "BUY":
-			day.BuyQuantity += row.Quantity
+			day.BuyQuantity = day.BuyQuantity.Add(decimals.read(row.Quantity))
 			day.BuyCount += int(row.Count)
 		case "SELL":
-			day.SellQuantity += row.Quantity
+			day.SellQuantity = day.SellQuantity.Add(decimals.read(row.Quantity))
 			day.SellCount += int(row.Count)
 		}
 	}
@@ -350,18 +428,9 @@ func (r *queryResolver) MerchantDashboard(ctx context.Context, days *int) (*Merc
 		}
 	}
 
-	recentTransactions := make([]*Transaction, len(txnsResp.Transactions))
-	for i, t := range txnsResp.Transactions {
-		recentTransactions[i] = &Transaction{
-			ID:           t.Id,
-			UserID:       t.UserId,
-			SpiceGradeID: t.SpiceGradeId,
-			Type:         t.Type,
-			Quantity:     t.Quantity,
-			Price:        t.Price,
-			TradeDate:    t.TradeDate,
-			CreatedAt:    t.CreatedAt,
-		}
+	recentTransactions, err := transactionsFromProto(txnsResp.Transactions)
+	if err != nil {
+		return nil, err
 	}
 
 	var insights []*MerchantInsight
@@ -383,7 +452,7 @@ func (r *queryResolver) MerchantDashboard(ctx context.Context, days *int) (*Merc
 		}
 		var best, worst *MerchantHolding
 		for _, h := range holdings {
-			if h.TodayPrice <= 0 {
+			if !h.TodayPrice.IsPositive() {
 				continue
 			}
 			if best == nil || h.UnrealizedPnLPercent > best.UnrealizedPnLPercent {
@@ -426,15 +495,34 @@ func (r *queryResolver) MerchantDashboard(ctx context.Context, days *int) (*Merc
 				break
 			}
 		}
-		var periodRealized float64
+		if len(unconverted) > 0 {
+			names := make([]string, len(unconverted))
+			for i, h := range unconverted {
+				names[i] = fmt.Sprintf("%s - %s (%s)", h.ProductName, h.GradeName, h.Currency)
+			}
+			insights = append(insights, &MerchantInsight{
+				Kind:     "FX_MISSING",
+				Title:    "Missing exchange rate",
+				Body:     fmt.Sprintf("No %s rate is set for %s, so they are left out of your totals.", reportingCurrency, strings.Join(names, ", ")),
+				Severity: "warning",
+			})
+		}
+		insight, err := ageingStockInsight(ageingResp.Grades)
+		if err != nil {
+			return nil, err
+		}
+		if insight != nil {
+			insights = append(insights, insight)
+		}
+		periodRealized := decimal.Zero
 		for _, row := range pnlResp.Rows {
-			periodRealized += row.Amount
+			periodRealized = periodRealized.Add(decimals.read(row.Amount))
 		}
-		if periodRealized > 0 {
+		if periodRealized.IsPositive() {
 			insights = append(insights, &MerchantInsight{
 				Kind:     "MILESTONE",
 				Title:    "Profitable period",
-				Body:     fmt.Sprintf("You locked in %.2f realized P&L in the selected window.", periodRealized),
+				Body:     fmt.Sprintf("You locked in %s realized P&L in the selected window.", periodRealized.StringFixed(util.CurrencyScale(util.DefaultCurrency))),
 				Severity: "success",
 			})
 		}
@@ -446,12 +534,16 @@ func (r *queryResolver) MerchantDashboard(ctx context.Context, days *int) (*Merc
 			SpiceGradeID:  snap.SpiceGradeId,
 			ProductName:   snap.ProductName,
 			GradeName:     snap.GradeName,
-			TodayPrice:    snap.TodayPrice,
-			PreviousPrice: snap.PreviousPrice,
+			TodayPrice:    decimals.read(snap.TodayPrice),
+			PreviousPrice: decimals.read(snap.PreviousPrice),
 			Direction:     "FLAT",
+			PriceDate:     optionalString(snap.PriceDate),
+			PriceSource:   optionalString(snap.PriceSource),
+			Stale:         snap.Stale,
+			Currency:      snap.Currency,
 		}
-		if snap.PreviousPrice > 0 && snap.TodayPrice > 0 {
-			m.ChangePercent = ((snap.TodayPrice - snap.PreviousPrice) / snap.PreviousPrice) * 100
+		if m.PreviousPrice.IsPositive() && m.TodayPrice.IsPositive() {
+			m.ChangePercent = percentOf(m.TodayPrice.Sub(m.PreviousPrice), m.PreviousPrice)
 			if m.ChangePercent > 0.01 {
 				m.Direction = "UP"
 			} else if m.ChangePercent < -0.01 {
@@ -460,6 +552,9 @@ func (r *queryResolver) MerchantDashboard(ctx context.Context, days *int) (*Merc
 		}
 		movers[i] = m
 	}
+	if decimals.err != nil {
+		return nil, decimals.err
+	}
 
 	return &MerchantDashboard{
 		Summary:            summary,
@@ -537,20 +632,55 @@ func (r *queryResolver) ListTransactions(ctx context.Context, skip *int, take *i
 	if err != nil {
 		return nil, err
 	}
-	transactions := make([]*Transaction, len(resp.Transactions))
-	for i, t := range resp.Transactions {
-		transactions[i] = &Transaction{
-			ID:           t.Id,
-			UserID:       t.UserId,
-			SpiceGradeID: t.SpiceGradeId,
-			Type:         t.Type,
-			Quantity:     t.Quantity,
-			Price:        t.Price,
-			TradeDate:    t.TradeDate,
-			CreatedAt:    t.CreatedAt,
+	return transactionsFromProto(resp.Transactions)
+}
+
+// ageingStockInsight names the grades holding expired lots or lots close to their shelf life.
+func ageingStockInsight(grades []*marketpb.GradeAgeing) (*MerchantInsight, error) {
+	var decimals decimalReader
+	var names []string
+	var only string
+	for _, g := range grades {
+		expired := decimal.Zero
+		for _, b := range g.Buckets {
+			if b.Label == "EXPIRED" {
+				expired = decimals.read(b.Quantity)
+			}
+		}
+		if g.NearExpiryLots == 0 && !expired.IsPositive() {
+			continue
+		}
+		label := fmt.Sprintf("%s - %s", g.ProductName, g.GradeName)
+		switch {
+		case expired.IsPositive():
+			label += fmt.Sprintf(" (%s expired)", expired.String())
+		case g.NextExpiryDate != "":
+			label += fmt.Sprintf(" (expires %s)", g.NextExpiryDate)
 		}
+		names = append(names, label)
+		only = g.SpiceGradeId
 	}
-	return transactions, nil
+	if decimals.err != nil {
+		return nil, decimals.err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	insight := &MerchantInsight{
+		Kind:     "AGEING_STOCK",
+		Title:    "Stock nearing shelf life",
+		Body:     fmt.Sprintf("Sell or re-grade soon: %s.", strings.Join(names, ", ")),
+		Severity: "warning",
+	}
+	if len(names) == 1 {
+		insight.SpiceGradeID = &only
+	}
+	return insight, nil
+}
+
+// percentOf returns part/whole as a percentage; display-only, so float is fine here.
+func percentOf(part, whole decimal.Decimal) float64 {
+	return part.Div(whole).Mul(decimal.NewFromInt(100)).InexactFloat64()
 }
 
 func trendWindowDays(days *int) uint32 {
@@ -576,9 +706,10 @@ func (r *queryResolver) MerchantPnlTrend(ctx context.Context, days *int) (*Merch
 	}
 
 	type dayAgg struct {
-		total    float64
+		total    decimal.Decimal
 		products map[string]*PnLProductDay
 	}
+	var decimals decimalReader
 	byDate := make(map[string]*dayAgg)
 	for _, row := range pnlResp.Rows {
 		agg, ok := byDate[row.Date]
@@ -586,7 +717,8 @@ func (r *queryResolver) MerchantPnlTrend(ctx context.Context, days *int) (*Merch
 			agg = &dayAgg{products: map[string]*PnLProductDay{}}
 			byDate[row.Date] = agg
 		}
-		agg.total += row.Amount
+		amount := decimals.read(row.Amount)
+		agg.total = agg.total.Add(amount)
 		key := row.SpiceGradeId
 		if key == "" {
 			key = row.ProductName + "|" + row.GradeName
@@ -600,15 +732,18 @@ func (r *queryResolver) MerchantPnlTrend(ctx context.Context, days *int) (*Merch
 			}
 			agg.products[key] = prod
 		}
-		prod.RealizedPnL += row.Amount
+		prod.RealizedPnL = prod.RealizedPnL.Add(amount)
+	}
+	if decimals.err != nil {
+		return nil, decimals.err
 	}
 
 	points := make([]*PnLDayDetail, 0, windowDays+1)
-	var cumulative, period float64
+	cumulative, period := decimal.Zero, decimal.Zero
 	for i := int(windowDays); i >= 0; i-- {
 		key := time.Now().AddDate(0, 0, -i).Format("2006-01-02")
 		agg := byDate[key]
-		daily := 0.0
+		daily := decimal.Zero
 		products := []*PnLProductDay{}
 		if agg != nil {
 			daily = agg.total
@@ -617,8 +752,8 @@ func (r *queryResolver) MerchantPnlTrend(ctx context.Context, days *int) (*Merch
 				products = append(products, p)
 			}
 		}
-		cumulative += daily
-		period += daily
+		cumulative = cumulative.Add(daily)
+		period = period.Add(daily)
 		points = append(points, &PnLDayDetail{
 			Date:                  key,
 			DailyRealizedPnL:      daily,
@@ -646,10 +781,11 @@ func (r *queryResolver) MerchantActivityTrend(ctx context.Context, days *int) (*
 	}
 
 	type dayAgg struct {
-		buyQty, sellQty     float64
+		buyQty, sellQty     decimal.Decimal
 		buyCount, sellCount int
 		products            map[string]*ActivityProductDay
 	}
+	var decimals decimalReader
 	byDate := make(map[string]*dayAgg)
 	for _, row := range activityResp.Rows {
 		agg, ok := byDate[row.Date]
@@ -670,22 +806,26 @@ func (r *queryResolver) MerchantActivityTrend(ctx context.Context, days *int) (*
 			}
 			agg.products[key] = prod
 		}
+		qty := decimals.read(row.Quantity)
 		switch row.Type {
 		case "BUY":
-			agg.buyQty += row.Quantity
+			agg.buyQty = agg.buyQty.Add(qty)
 			agg.buyCount += int(row.Count)
-			prod.BuyQuantity += row.Quantity
+			prod.BuyQuantity = prod.BuyQuantity.Add(qty)
 			prod.BuyCount += int(row.Count)
 		case "SELL":
-			agg.sellQty += row.Quantity
+			agg.sellQty = agg.sellQty.Add(qty)
 			agg.sellCount += int(row.Count)
-			prod.SellQuantity += row.Quantity
+			prod.SellQuantity = prod.SellQuantity.Add(qty)
 			prod.SellCount += int(row.Count)
 		}
 	}
+	if decimals.err != nil {
+		return nil, decimals.err
+	}
 
 	points := make([]*ActivityDayDetail, 0, windowDays+1)
-	var totalBuy, totalSell float64
+	totalBuy, totalSell := decimal.Zero, decimal.Zero
 	var totalTrades int
 	for i := int(windowDays); i >= 0; i-- {
 		key := time.Now().AddDate(0, 0, -i).Format("2006-01-02")
@@ -701,8 +841,8 @@ func (r *queryResolver) MerchantActivityTrend(ctx context.Context, days *int) (*
 				products = append(products, p)
 			}
 			point.Products = products
-			totalBuy += agg.buyQty
-			totalSell += agg.sellQty
+			totalBuy = totalBuy.Add(agg.buyQty)
+			totalSell = totalSell.Add(agg.sellQty)
 			totalTrades += agg.buyCount + agg.sellCount
 		}
 		points = append(points, point)
@@ -716,3 +856,251 @@ func (r *queryResolver) MerchantActivityTrend(ctx context.Context, days *int) (*
 		Points:            points,
 	}, nil
 }
+
+// CostBasisMethod is the resolver for the costBasisMethod field.
+func (r *queryResolver) CostBasisMethod(ctx context.Context, spiceGradeID *string) (*CostBasisPreference, error) {
+	gradeStr := ""
+	if spiceGradeID != nil {
+		gradeStr = *spiceGradeID
+	}
+	resp, err := r.server.marketClient.GetCostBasisMethod(ctx, &marketpb.GetCostBasisMethodRequest{
+		SpiceGradeId: gradeStr,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return costBasisPreferenceFromProto(resp.Preference), nil
+}
+
+// TradingPermissions is the resolver for the tradingPermissions field.
+func (r *queryResolver) TradingPermissions(ctx context.Context, userID *string) (*TradingPermissions, error) {
+	resp, err := r.server.marketClient.GetTradingPermissions(ctx, &marketpb.GetTradingPermissionsRequest{
+		UserId: stringValue(userID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tradingPermissionsFromProto(resp.Permissions), nil
+}
+
+// Order is the resolver for the order field.
+func (r *queryResolver) Order(ctx context.Context, id string) (*OrderResult, error) {
+	resp, err := r.server.marketClient.GetOrder(ctx, &marketpb.GetOrderRequest{OrderId: id})
+	if err != nil {
+		return nil, err
+	}
+	return orderResultFromProto(resp.Order, resp.Fills)
+}
+
+// Orders is the resolver for the orders field.
+func (r *queryResolver) Orders(ctx context.Context, spiceGradeID *string, side *string, status *string, skip *int, take *int) ([]*Order, error) {
+	resp, err := r.server.marketClient.ListOrders(ctx, &marketpb.ListOrdersRequest{
+		SpiceGradeId: stringValue(spiceGradeID),
+		Side:         stringValue(side),
+		Status:       stringValue(status),
+		Skip:         uint32Value(skip),
+		Take:         uint32Value(take),
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Order, len(resp.Orders))
+	for i, o := range resp.Orders {
+		if out[i], err = orderFromProto(o); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// OrderBook is the resolver for the orderBook field.
+func (r *queryResolver) OrderBook(ctx context.Context, spiceGradeID string, currency *string, depth *int) (*OrderBook, error) {
+	resp, err := r.server.marketClient.GetOrderBook(ctx, &marketpb.GetOrderBookRequest{
+		SpiceGradeId: spiceGradeID,
+		Currency:     stringValue(currency),
+		Depth:        uint32Value(depth),
+	})
+	if err != nil {
+		return nil, err
+	}
+	book := &OrderBook{SpiceGradeID: resp.SpiceGradeId, Currency: resp.Currency}
+	if book.Bids, err = orderBookLevelsFromProto(resp.Bids); err != nil {
+		return nil, err
+	}
+	if book.Asks, err = orderBookLevelsFromProto(resp.Asks); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// OpenLots is the resolver for the openLots field.
+func (r *queryResolver) OpenLots(ctx context.Context, spiceGradeID *string, skip *int, take *int, sort *string, dateFrom *string, dateTo *string) ([]*BuyLot, error) {
+	resp, err := r.server.marketClient.ListOpenLots(ctx, &marketpb.ListOpenLotsRequest{
+		SpiceGradeId: stringValue(spiceGradeID),
+		Skip:         uint32Value(skip),
+		Take:         uint32Value(take),
+		Sort:         stringValue(sort),
+		DateFrom:     stringValue(dateFrom),
+		DateTo:       stringValue(dateTo),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buyLotsFromProto(resp.Lots)
+}
+
+// LotHistory is the resolver for the lotHistory field.
+func (r *queryResolver) LotHistory(ctx context.Context, lotID string, skip *int, take *int, dateFrom *string, dateTo *string) (*LotHistory, error) {
+	resp, err := r.server.marketClient.GetLotHistory(ctx, &marketpb.GetLotHistoryRequest{
+		LotId:    lotID,
+		Skip:     uint32Value(skip),
+		Take:     uint32Value(take),
+		DateFrom: stringValue(dateFrom),
+		DateTo:   stringValue(dateTo),
+	})
+	if err != nil {
+		return nil, err
+	}
+	history := &LotHistory{}
+	if history.Lot, err = buyLotFromProto(resp.Lot); err != nil {
+		return nil, err
+	}
+	if history.Allocations, err = sellAllocationsFromProto(resp.Allocations); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// LotAgeing is the resolver for the lotAgeing field.
+func (r *queryResolver) LotAgeing(ctx context.Context, asOf *string) (*LotAgeing, error) {
+	resp, err := r.server.marketClient.GetLotAgeing(ctx, &marketpb.GetLotAgeingRequest{
+		AsOf: stringValue(asOf),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var decimals decimalReader
+	grades := make([]*GradeAgeing, len(resp.Grades))
+	for i, g := range resp.Grades {
+		grades[i] = &GradeAgeing{
+			SpiceGradeID:   g.SpiceGradeId,
+			ProductName:    g.ProductName,
+			GradeName:      g.GradeName,
+			ShelfLifeDays:  int(g.ShelfLifeDays),
+			Buckets:        ageingBucketsFromProto(g.Buckets, &decimals),
+			NearExpiryQty:  decimals.read(g.NearExpiryQty),
+			NearExpiryLots: int(g.NearExpiryLots),
+			NextExpiryDate: optionalString(g.NextExpiryDate),
+		}
+	}
+	ageing := &LotAgeing{
+		AsOf:    resp.AsOf,
+		Buckets: ageingBucketsFromProto(resp.Buckets, &decimals),
+		Grades:  grades,
+	}
+	if decimals.err != nil {
+		return nil, decimals.err
+	}
+	return ageing, nil
+}
+
+func ageingBucketsFromProto(buckets []*marketpb.AgeingBucket, decimals *decimalReader) []*AgeingBucket {
+	out := make([]*AgeingBucket, len(buckets))
+	for i, b := range buckets {
+		out[i] = &AgeingBucket{
+			Label:    b.Label,
+			Quantity: decimals.read(b.Quantity),
+			Cost:     decimals.read(b.Cost),
+			Lots:     int(b.Lots),
+		}
+	}
+	return out
+}
+
+// FxRates is the resolver for the fxRates field.
+func (r *queryResolver) FxRates(ctx context.Context, baseCurrency *string, quoteCurrency *string) ([]*FxRate, error) {
+	resp, err := r.server.controlClient.ListFxRates(ctx, &pb.ListFxRatesRequest{
+		BaseCurrency:  stringValue(baseCurrency),
+		QuoteCurrency: stringValue(quoteCurrency),
+	})
+	if err != nil {
+		return nil, err
+	}
+	rates := make([]*FxRate, len(resp.Rates))
+	for i, rate := range resp.Rates {
+		if rates[i], err = fxRateFromProto(rate); err != nil {
+			return nil, err
+		}
+	}
+	return rates, nil
+}
+
+// FeeSchedules is the resolver for the feeSchedules field.
+func (r *queryResolver) FeeSchedules(ctx context.Context, gradeID *string, category *string) ([]*FeeSchedule, error) {
+	resp, err := r.server.controlClient.ListFeeSchedules(ctx, &pb.ListFeeSchedulesRequest{
+		GradeId:  stringValue(gradeID),
+		Category: stringValue(category),
+	})
+	if err != nil {
+		return nil, err
+	}
+	schedules := make([]*FeeSchedule, len(resp.Schedules))
+	for i, fs := range resp.Schedules {
+		if schedules[i], err = feeScheduleFromProto(fs); err != nil {
+			return nil, err
+		}
+	}
+	return schedules, nil
+}
+
+// Sessions is the resolver for the sessions field.
+func (r *queryResolver) Sessions(ctx context.Context) ([]*Session, error) {
+	resp, err := r.server.controlClient.ListSessions(ctx, &pb.ListSessionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*Session, len(resp.Sessions))
+	for i, s := range resp.Sessions {
+		sessions[i] = sessionFromProto(s)
+	}
+	return sessions, nil
+}
+
+// SellAllocations is the resolver for the sellAllocations field.
+func (r *queryResolver) SellAllocations(ctx context.Context, sellTransactionID *string, spiceGradeID *string, skip *int, take *int, dateFrom *string, dateTo *string, includeReversed *bool) ([]*SellAllocation, error) {
+	req := &marketpb.GetSellAllocationsRequest{
+		SellTransactionId: stringValue(sellTransactionID),
+		SpiceGradeId:      stringValue(spiceGradeID),
+		Skip:              uint32Value(skip),
+		Take:              uint32Value(take),
+		DateFrom:          stringValue(dateFrom),
+		DateTo:            stringValue(dateTo),
+	}
+	if includeReversed != nil {
+		req.IncludeReversed = *includeReversed
+	}
+	resp, err := r.server.marketClient.GetSellAllocations(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return sellAllocationsFromProto(resp.Allocations)
+}
+
+func costBasisPreferenceFromProto(p *marketpb.CostBasisPreference) *CostBasisPreference {
+	return &CostBasisPreference{
+		UserID:       p.UserId,
+		SpiceGradeID: optionalString(p.SpiceGradeId),
+		Method:       p.Method,
+		Source:       p.Source,
+		UpdatedAt:    optionalString(p.UpdatedAt),
+	}
+}
+
+func tradingPermissionsFromProto(p *marketpb.TradingPermissions) *TradingPermissions {
+	return &TradingPermissions{
+		UserID:            p.UserId,
+		AllowShortSelling: p.AllowShortSelling,
+		UpdatedBy:         optionalString(p.UpdatedBy),
+		UpdatedAt:         optionalString(p.UpdatedAt),
+	}
+}
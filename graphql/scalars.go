@@ -0,0 +1,72 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shopspring/decimal"
+)
+
+// MarshalDecimal writes a Decimal scalar as a JSON string so clients never round-trip
+// quantities or money through binary floating point.
+func MarshalDecimal(d decimal.Decimal) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(d.String()))
+	})
+}
+
+// UnmarshalDecimal accepts a decimal string ("12.5") or a JSON number literal.
+func UnmarshalDecimal(v interface{}) (decimal.Decimal, error) {
+	switch v := v.(type) {
+	case string:
+		return decimal.NewFromString(v)
+	case json.Number:
+		return decimal.NewFromString(v.String())
+	case int:
+		return decimal.NewFromInt(int64(v)), nil
+	case int64:
+		return decimal.NewFromInt(v), nil
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	default:
+		return decimal.Zero, fmt.Errorf("%T is not a valid Decimal", v)
+	}
+}
+
+// decimalFromProto reads a decimal string written by a backend service; empty is zero.
+func decimalFromProto(v string) (decimal.Decimal, error) {
+	if v == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(v)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("malformed decimal %q from backend: %w", v, err)
+	}
+	return d, nil
+}
+
+// decimalReader reads the decimal strings of one backend message and keeps the first
+// malformed one, so a converter can fill a struct literal and check once.
+type decimalReader struct {
+	err error
+}
+
+func (r *decimalReader) read(v string) decimal.Decimal {
+	d, err := decimalFromProto(v)
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	return d
+}
+
+// optional maps an empty proto decimal string to a GraphQL null.
+func (r *decimalReader) optional(v string) *decimal.Decimal {
+	if v == "" {
+		return nil
+	}
+	d := r.read(v)
+	return &d
+}
@@ -0,0 +1,49 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDecimalFromProto(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: "0"},
+		{in: "12.5", want: "12.5"},
+		{in: "-0.0100", want: "-0.01"},
+		{in: "12,5", wantErr: true},
+		{in: "NaN", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := decimalFromProto(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("decimalFromProto(%q) = %s, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("decimalFromProto(%q) = %s, %v; want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecimalReaderKeepsFirstError(t *testing.T) {
+	var decimals decimalReader
+	if d := decimals.read("1.5"); !d.Equal(decimal.RequireFromString("1.5")) || decimals.err != nil {
+		t.Fatalf("read(1.5) = %s, err %v", d, decimals.err)
+	}
+	if decimals.optional("") != nil {
+		t.Error("optional(\"\") is not null")
+	}
+	decimals.read("bad")
+	first := decimals.err
+	decimals.read("worse")
+	if first == nil || decimals.err != first {
+		t.Errorf("err = %v, want the first malformed value %v", decimals.err, first)
+	}
+}
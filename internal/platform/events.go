@@ -0,0 +1,147 @@
+package platform
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// ErrCursorExpired is returned when a resume cursor is from another bus instance (the
+// service restarted) or older than the retained events. The client must reload its state
+// and subscribe again without a cursor.
+var ErrCursorExpired = errors.New("event cursor expired: reload state and subscribe without a cursor")
+
+// ErrSubscriberLagged ends a subscription that fell a full buffer behind the publishers.
+// The client can resume from the last sequence it received.
+var ErrSubscriberLagged = errors.New("subscriber fell behind: resume from the last received sequence")
+
+// Event is one published change. Seq increases by one per Publish on a bus; Epoch names
+// the bus instance so a cursor from before a restart is recognised as stale.
+type Event struct {
+	Epoch   string
+	Seq     uint64
+	Topic   string
+	At      time.Time
+	Payload any
+}
+
+// EventBus is an in-process publish/subscribe hub. It retains the most recent events so
+// a reconnecting subscriber can resume from its last sequence instead of reloading.
+type EventBus struct {
+	mu        sync.Mutex
+	epoch     string
+	seq       uint64
+	retention int
+	retained  []Event
+	subs      map[*Subscription]struct{}
+}
+
+// NewEventBus returns a bus that keeps the last retention events for resuming subscribers.
+func NewEventBus(retention int) *EventBus {
+	if retention <= 0 {
+		retention = 1024
+	}
+	return &EventBus{
+		epoch:     ksuid.New().String(),
+		retention: retention,
+		subs:      make(map[*Subscription]struct{}),
+	}
+}
+
+// Epoch identifies this bus instance; it changes on every restart.
+func (b *EventBus) Epoch() string {
+	return b.epoch
+}
+
+// Publish assigns the next sequence to payload and fans it out to matching subscribers.
+// It never blocks: a subscriber whose buffer is full is closed with ErrSubscriberLagged.
+func (b *EventBus) Publish(topic string, payload any) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	ev := Event{Epoch: b.epoch, Seq: b.seq, Topic: topic, At: time.Now(), Payload: payload}
+	b.retained = append(b.retained, ev)
+	if len(b.retained) > b.retention {
+		b.retained = b.retained[len(b.retained)-b.retention:]
+	}
+
+	for sub := range b.subs {
+		if !sub.match(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			sub.err = ErrSubscriberLagged
+			b.remove(sub)
+		}
+	}
+	return ev
+}
+
+// Subscribe delivers every later event accepted by match. With a cursor (epoch and after
+// set), retained events after that sequence are replayed first, so nothing in between is lost.
+func (b *EventBus) Subscribe(epoch string, after uint64, match func(Event) bool) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &Subscription{
+		bus:   b,
+		ch:    make(chan Event, b.retention+64),
+		match: match,
+	}
+	sub.C = sub.ch
+
+	if epoch != "" || after > 0 {
+		if epoch != b.epoch || after > b.seq {
+			return nil, ErrCursorExpired
+		}
+		if len(b.retained) > 0 && after+1 < b.retained[0].Seq {
+			return nil, ErrCursorExpired
+		}
+		for _, ev := range b.retained {
+			if ev.Seq > after && match(ev) {
+				sub.ch <- ev
+			}
+		}
+	}
+
+	b.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// remove detaches a subscription and closes its channel; callers hold b.mu.
+func (b *EventBus) remove(sub *Subscription) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.ch)
+}
+
+// Subscription is a live feed from an EventBus. C is closed when the subscription ends;
+// Err then tells a lagging subscriber apart from one that was closed.
+type Subscription struct {
+	C     <-chan Event
+	bus   *EventBus
+	ch    chan Event
+	match func(Event) bool
+	err   error
+}
+
+// Close detaches the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.remove(s)
+}
+
+// Err reports why C was closed: ErrSubscriberLagged, or nil after Close.
+func (s *Subscription) Err() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.err
+}
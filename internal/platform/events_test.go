@@ -0,0 +1,144 @@
+package platform
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func all(Event) bool { return true }
+
+func topic(name string) func(Event) bool {
+	return func(ev Event) bool { return ev.Topic == name }
+}
+
+// drain returns the sequences buffered on a subscription without blocking, and whether
+// its channel was closed.
+func drain(sub *Subscription) (seqs []uint64, closed bool) {
+	for {
+		select {
+		case ev, ok := <-sub.C:
+			if !ok {
+				return seqs, true
+			}
+			seqs = append(seqs, ev.Seq)
+		default:
+			return seqs, false
+		}
+	}
+}
+
+// publish puts n events on the bus, alternating topics "a" and "b".
+func publish(bus *EventBus, n int) {
+	for i := 0; i < n; i++ {
+		bus.Publish(string(rune('a'+i%2)), i)
+	}
+}
+
+func TestSubscribeResume(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention int
+		published int
+		epoch     string // "current" for the bus's own epoch
+		after     uint64
+		match     func(Event) bool
+		want      []uint64 // replayed, then the live event published after subscribing
+		wantErr   error
+	}{
+		{name: "no cursor gets live events only", retention: 8, published: 5, match: all, want: []uint64{6}},
+		{name: "cursor replays the events after it", retention: 8, published: 5, epoch: "current", after: 2, match: all, want: []uint64{3, 4, 5, 6}},
+		{name: "cursor at the head replays nothing", retention: 8, published: 5, epoch: "current", after: 5, match: all, want: []uint64{6}},
+		{name: "replay applies the filter", retention: 8, published: 5, epoch: "current", after: 1, match: topic("a"), want: []uint64{3, 5, 6}},
+		{name: "cursor just inside retention", retention: 4, published: 10, epoch: "current", after: 6, match: all, want: []uint64{7, 8, 9, 10, 11}},
+		{name: "cursor older than retention", retention: 4, published: 10, epoch: "current", after: 5, match: all, wantErr: ErrCursorExpired},
+		{name: "cursor from another bus instance", retention: 8, published: 5, epoch: "restarted", after: 2, match: all, wantErr: ErrCursorExpired},
+		{name: "cursor ahead of the bus", retention: 8, published: 5, epoch: "current", after: 9, match: all, wantErr: ErrCursorExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewEventBus(tt.retention)
+			publish(bus, tt.published)
+			epoch := tt.epoch
+			if epoch == "current" {
+				epoch = bus.Epoch()
+			}
+
+			sub, err := bus.Subscribe(epoch, tt.after, tt.match)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer sub.Close()
+			bus.Publish("a", "live")
+
+			got, closed := drain(sub)
+			if closed {
+				t.Fatal("subscription closed")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaggedSubscriberResumes(t *testing.T) {
+	const retention = 4
+	bus := NewEventBus(retention)
+	lagging, err := bus.Subscribe("", 0, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered, err := bus.Subscribe("", 0, topic("none"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer filtered.Close()
+
+	// The buffer holds retention+64 events; one more ends the subscription.
+	buffered := retention + 64
+	publish(bus, buffered+1)
+
+	got, closed := drain(lagging)
+	if !closed {
+		t.Fatal("lagging subscription still open")
+	}
+	if len(got) != buffered || got[len(got)-1] != uint64(buffered) {
+		t.Fatalf("received %d events ending at %v, want %d", len(got), got[len(got)-1:], buffered)
+	}
+	if !errors.Is(lagging.Err(), ErrSubscriberLagged) {
+		t.Errorf("Err() = %v, want ErrSubscriberLagged", lagging.Err())
+	}
+	if _, closed := drain(filtered); closed || filtered.Err() != nil {
+		t.Errorf("subscriber that matched nothing was closed: %v", filtered.Err())
+	}
+
+	resumed, err := bus.Subscribe(bus.Epoch(), got[len(got)-1], all)
+	if err != nil {
+		t.Fatalf("resume after lag: %v", err)
+	}
+	defer resumed.Close()
+	if rest, _ := drain(resumed); !reflect.DeepEqual(rest, []uint64{uint64(buffered + 1)}) {
+		t.Errorf("resumed with %v, want [%d]", rest, buffered+1)
+	}
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	bus := NewEventBus(8)
+	sub, err := bus.Subscribe("", 0, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub.Close()
+	sub.Close()
+	if _, closed := drain(sub); !closed {
+		t.Error("channel not closed")
+	}
+	if sub.Err() != nil {
+		t.Errorf("Err() = %v after Close, want nil", sub.Err())
+	}
+	bus.Publish("a", "after close")
+}
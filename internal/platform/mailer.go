@@ -0,0 +1,183 @@
+package platform
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+	"github.com/segmentio/ksuid"
+)
+
+// Mail transports accepted by NewMailer.
+const (
+	MailerSMTP = "smtp"
+	MailerFile = "file"
+)
+
+// Mail is one plain-text message to a single recipient.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outbound mail.
+type Mailer interface {
+	Send(ctx context.Context, message Mail) error
+}
+
+// NewMailer returns the transport named by MAILER.
+func NewMailer(config *util.Config, logger util.Logger) (Mailer, error) {
+	if _, err := mail.ParseAddress(config.MailFrom); err != nil {
+		return nil, fmt.Errorf("MAIL_FROM: %w", err)
+	}
+	switch strings.ToLower(config.Mailer) {
+	case MailerSMTP:
+		if config.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required when MAILER is smtp")
+		}
+		return &SMTPMailer{
+			host:     config.SMTPHost,
+			port:     config.SMTPPort,
+			username: config.SMTPUser,
+			password: config.SMTPPassword,
+			from:     config.MailFrom,
+		}, nil
+	case MailerFile:
+		if err := os.MkdirAll(config.MailDir, 0o750); err != nil {
+			return nil, err
+		}
+		return &FileMailer{dir: config.MailDir, from: config.MailFrom, logger: logger}, nil
+	}
+	return nil, fmt.Errorf("unsupported MAILER %q: use smtp or file", config.Mailer)
+}
+
+// SMTPMailer sends through an SMTP server, upgrading to TLS when the server offers STARTTLS
+// and authenticating when a username is set.
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+func (mailer *SMTPMailer) Send(ctx context.Context, message Mail) error {
+	data, err := composeMail(mailer.from, message)
+	if err != nil {
+		return err
+	}
+	from, _ := mail.ParseAddress(mailer.from)
+	to, _ := mail.ParseAddress(message.To)
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(mailer.host, strconv.Itoa(mailer.port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, mailer.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: mailer.host}); err != nil {
+			return err
+		}
+	}
+	if mailer.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", mailer.username, mailer.password, mailer.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// FileMailer writes each message as an .eml file instead of sending it, for local use.
+type FileMailer struct {
+	dir    string
+	from   string
+	logger util.Logger
+}
+
+func (mailer *FileMailer) Send(ctx context.Context, message Mail) error {
+	data, err := composeMail(mailer.from, message)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(mailer.dir, time.Now().UTC().Format("20060102T150405")+"-"+ksuid.New().String()+".eml")
+	if err := os.WriteFile(path, data, 0o640); err != nil {
+		return err
+	}
+	mailer.logger.Service().Info().Str("to", message.To).Str("subject", message.Subject).Str("path", path).Msg("Mail written to file")
+	return nil
+}
+
+// composeMail renders an RFC 5322 message. Addresses are parsed and the subject is encoded,
+// so header values cannot smuggle in extra headers.
+func composeMail(from string, message Mail) ([]byte, error) {
+	fromAddress, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender: %w", err)
+	}
+	toAddress, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	var buffer bytes.Buffer
+	header := func(name, value string) {
+		buffer.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", fromAddress.String())
+	header("To", toAddress.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+ksuid.New().String()+"@"+messageIDHost(fromAddress.Address)+">")
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "8bit")
+	buffer.WriteString("\r\n")
+	buffer.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return buffer.Bytes(), nil
+}
+
+func messageIDHost(address string) string {
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		return address[at+1:]
+	}
+	return "localhost"
+}
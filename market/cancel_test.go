@@ -0,0 +1,258 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testUser  = "user-1"
+	testGrade = "grade-1"
+)
+
+func day(n int) time.Time {
+	return time.Date(2026, 1, n, 0, 0, 0, 0, time.UTC)
+}
+
+func mustBuy(t *testing.T, s *MarketService, qty, price string, date time.Time) *Transaction {
+	t.Helper()
+	buy, err := s.Buy(context.Background(), testUser, testGrade, dec(qty), dec(price), "", date, "")
+	if err != nil {
+		t.Fatalf("buy %s @ %s: %v", qty, price, err)
+	}
+	return buy
+}
+
+func mustSell(t *testing.T, s *MarketService, qty, price string, date time.Time, opts SellOptions) *Transaction {
+	t.Helper()
+	sell, err := s.Sell(context.Background(), testUser, testGrade, dec(qty), dec(price), "", date, "", opts)
+	if err != nil {
+		t.Fatalf("sell %s @ %s: %v", qty, price, err)
+	}
+	return sell
+}
+
+type wantPosition struct {
+	qty, cost, pnl string
+}
+
+// checkPosition compares the stored position and checks that its cost equals the open lots'
+// less the open shorts'.
+func checkPosition(t *testing.T, repo *fakeRepository, want wantPosition) {
+	t.Helper()
+	pos := repo.position(testUser, testGrade)
+	if !pos.TotalQty.Equal(dec(want.qty)) || !pos.TotalCost.Equal(dec(want.cost)) || !pos.RealizedPnL.Equal(dec(want.pnl)) {
+		t.Errorf("position = qty %s cost %s pnl %s, want qty %s cost %s pnl %s",
+			pos.TotalQty, pos.TotalCost, pos.RealizedPnL, want.qty, want.cost, want.pnl)
+	}
+	if lots := repo.openLotCost(testUser, testGrade); !lots.Equal(pos.TotalCost) {
+		t.Errorf("open lots cost %s, position total_cost %s", lots, pos.TotalCost)
+	}
+}
+
+func TestCancelSellRestoresLots(t *testing.T) {
+	s, repo := newTestService()
+	mustBuy(t, s, "10", "100", day(1))
+	mustBuy(t, s, "5", "120", day(2))
+	sell := mustSell(t, s, "12", "150", day(3), SellOptions{})
+	checkPosition(t, repo, wantPosition{"3", "360", "560"})
+
+	result, err := s.CancelTransaction(context.Background(), testUser, sell.ID, "wrong grade", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Original.Status != TransactionCancelled || result.Reversal.Type != "REVERSAL" || result.Reversal.ReversesTransactionID != sell.ID {
+		t.Errorf("cancel result = %+v / %+v", result.Original, result.Reversal)
+	}
+	checkPosition(t, repo, wantPosition{"15", "1600", "0"})
+	if allocs, _ := repo.ListActiveAllocationsBySell(context.Background(), sell.ID); len(allocs) != 0 {
+		t.Errorf("%d allocations still active", len(allocs))
+	}
+}
+
+func TestCancelBuyReallocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		secondBuy   string // quantity of the later lot the sell can move to; empty for none
+		sellQty     string
+		reallocate  bool
+		wantErr     string
+		wantPos     wantPosition
+		wantRealloc int
+	}{
+		{
+			name:      "unconsumed lot cancels without reallocating",
+			secondBuy: "10",
+			sellQty:   "",
+			wantPos:   wantPosition{"10", "1200", "0"},
+		},
+		{
+			name:      "consumed lot needs reallocate",
+			secondBuy: "10",
+			sellQty:   "8",
+			wantErr:   "buy lot is used by later sells",
+		},
+		{
+			name:        "sell moves to the next lot",
+			secondBuy:   "10",
+			sellQty:     "8",
+			reallocate:  true,
+			wantPos:     wantPosition{"2", "240", "240"},
+			wantRealloc: 1,
+		},
+		{
+			name:       "remaining lots cannot cover the sell",
+			secondBuy:  "5",
+			sellQty:    "8",
+			reallocate: true,
+			wantErr:    "cannot re-allocate sell",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestService()
+			first := mustBuy(t, s, "10", "100", day(1))
+			mustBuy(t, s, tt.secondBuy, "120", day(2))
+			if tt.sellQty != "" {
+				mustSell(t, s, tt.sellQty, "150", day(3), SellOptions{})
+			}
+
+			result, err := s.CancelTransaction(context.Background(), testUser, first.ID, "duplicate", tt.reallocate)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.ReallocatedSellIDs) != tt.wantRealloc {
+				t.Errorf("reallocated %d sells, want %d", len(result.ReallocatedSellIDs), tt.wantRealloc)
+			}
+			checkPosition(t, repo, tt.wantPos)
+		})
+	}
+}
+
+func TestCancelBuyReallocatesWeightedAverageSells(t *testing.T) {
+	s, repo := newTestService()
+	repo.preferences[positionKeyOf(testUser, "")] = &CostBasisPreference{UserID: testUser, Method: CostBasisWeightedAverage}
+	first := mustBuy(t, s, "10", "100", day(1))
+	mustBuy(t, s, "10", "120", day(2))
+	// Priced at the 110 average, though FIFO only draws on the first lot.
+	mustSell(t, s, "5", "150", day(3), SellOptions{})
+	// Weighted average releases the average cost, so total_cost no longer matches the lots.
+	pos := repo.position(testUser, testGrade)
+	if !pos.TotalQty.Equal(dec("15")) || !pos.TotalCost.Equal(dec("1650")) || !pos.RealizedPnL.Equal(dec("200")) {
+		t.Fatalf("position = qty %s cost %s pnl %s", pos.TotalQty, pos.TotalCost, pos.RealizedPnL)
+	}
+
+	if _, err := s.CancelTransaction(context.Background(), testUser, first.ID, "", false); !errors.Is(err, ErrLotConsumed) {
+		t.Fatalf("error = %v, want ErrLotConsumed", err)
+	}
+	result, err := s.CancelTransaction(context.Background(), testUser, first.ID, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ReallocatedSellIDs) != 1 {
+		t.Fatalf("reallocated %v", result.ReallocatedSellIDs)
+	}
+	// Re-matched against the 120 lot alone.
+	pos = repo.position(testUser, testGrade)
+	if !pos.TotalQty.Equal(dec("5")) || !pos.TotalCost.Equal(dec("600")) || !pos.RealizedPnL.Equal(dec("150")) {
+		t.Errorf("position = qty %s cost %s pnl %s", pos.TotalQty, pos.TotalCost, pos.RealizedPnL)
+	}
+}
+
+func TestCancelWeightedAverageClosingSell(t *testing.T) {
+	s, repo := newTestService()
+	repo.preferences[positionKeyOf(testUser, "")] = &CostBasisPreference{UserID: testUser, Method: CostBasisWeightedAverage}
+	mustBuy(t, s, "1", "10", day(1))
+	mustBuy(t, s, "1", "10", day(2))
+	mustBuy(t, s, "1", "10.01", day(3))
+	// Three units at the 10.0033 average cost 30.00, a cent short of the 30.01 held.
+	sell := mustSell(t, s, "3", "12", day(4), SellOptions{})
+	checkPosition(t, repo, wantPosition{"0", "0", "5.99"})
+
+	allocs, _ := repo.ListActiveAllocationsBySell(context.Background(), sell.ID)
+	if len(allocs) != 3 || !allocs[2].CloseAdjustment.Equal(dec("0.01")) {
+		t.Fatalf("allocations %+v, want the 0.01 close adjustment on the last", allocs)
+	}
+	pnl := dec("0")
+	for _, a := range allocs {
+		pnl = pnl.Add(a.RealizedPnL)
+	}
+	if !pnl.Equal(dec("5.99")) {
+		t.Errorf("allocations realize %s, want 5.99", pnl)
+	}
+
+	if _, err := s.CancelTransaction(context.Background(), testUser, sell.ID, "", false); err != nil {
+		t.Fatal(err)
+	}
+	checkPosition(t, repo, wantPosition{"3", "30.01", "0"})
+}
+
+func TestAmendTransaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		amend   func(buy, sell *Transaction) (string, Amendment)
+		wantErr string
+		wantPos wantPosition
+	}{
+		{
+			name: "buy repriced with reallocation",
+			amend: func(buy, sell *Transaction) (string, Amendment) {
+				return buy.ID, Amendment{Price: dec("90"), Reallocate: true}
+			},
+			wantPos: wantPosition{"6", "540", "240"},
+		},
+		{
+			name: "buy repriced without reallocation",
+			amend: func(buy, sell *Transaction) (string, Amendment) {
+				return buy.ID, Amendment{Price: dec("90")}
+			},
+			wantErr: "buy lot is used by later sells",
+		},
+		{
+			name: "sell quantity raised",
+			amend: func(buy, sell *Transaction) (string, Amendment) {
+				return sell.ID, Amendment{Quantity: dec("6")}
+			},
+			wantPos: wantPosition{"4", "400", "300"},
+		},
+		{
+			name: "sell beyond inventory",
+			amend: func(buy, sell *Transaction) (string, Amendment) {
+				return sell.ID, Amendment{Quantity: dec("11")}
+			},
+			wantErr: "insufficient inventory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestService()
+			buy := mustBuy(t, s, "10", "100", day(1))
+			sell := mustSell(t, s, "4", "150", day(2), SellOptions{})
+
+			id, amendment := tt.amend(buy, sell)
+			result, err := s.AmendTransaction(context.Background(), testUser, id, amendment)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Transaction.AmendsTransactionID != id || result.Cancel.Original.Status != TransactionCancelled {
+				t.Errorf("amend result = %+v", result.Transaction)
+			}
+			checkPosition(t, repo, tt.wantPos)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	pb "github.com/Asif-Faizal/SpiceLedger-Backend/market/pb"
+	"github.com/shopspring/decimal"
 	"google.golang.org/grpc"
 )
 
@@ -27,22 +28,22 @@ func (c *MarketClient) Close() error {
 	return c.connection.Close()
 }
 
-func (c *MarketClient) Buy(ctx context.Context, userID, spiceGradeID string, quantity, price float64, tradeDate string) (*pb.BuyResponse, error) {
+func (c *MarketClient) Buy(ctx context.Context, userID, spiceGradeID string, quantity, price decimal.Decimal, tradeDate string) (*pb.BuyResponse, error) {
 	return c.client.Buy(ctx, &pb.BuyRequest{
 		UserId:       userID,
 		SpiceGradeId: spiceGradeID,
-		Quantity:     quantity,
-		Price:        price,
+		Quantity:     quantity.String(),
+		Price:        price.String(),
 		TradeDate:    tradeDate,
 	})
 }
 
-func (c *MarketClient) Sell(ctx context.Context, userID, spiceGradeID string, quantity, price float64, tradeDate string) (*pb.SellResponse, error) {
+func (c *MarketClient) Sell(ctx context.Context, userID, spiceGradeID string, quantity, price decimal.Decimal, tradeDate string) (*pb.SellResponse, error) {
 	return c.client.Sell(ctx, &pb.SellRequest{
 		UserId:       userID,
 		SpiceGradeId: spiceGradeID,
-		Quantity:     quantity,
-		Price:        price,
+		Quantity:     quantity.String(),
+		Price:        price.String(),
 		TradeDate:    tradeDate,
 	})
 }
@@ -76,3 +77,36 @@ func (c *MarketClient) ListTransactions(ctx context.Context, userID string, skip
 		Take:   take,
 	})
 }
+
+func (c *MarketClient) ListOpenLots(ctx context.Context, userID, spiceGradeID string, skip, take uint32) (*pb.ListOpenLotsResponse, error) {
+	return c.client.ListOpenLots(ctx, &pb.ListOpenLotsRequest{
+		UserId:       userID,
+		SpiceGradeId: spiceGradeID,
+		Skip:         skip,
+		Take:         take,
+	})
+}
+
+func (c *MarketClient) GetLotHistory(ctx context.Context, userID, lotID string, skip, take uint32) (*pb.GetLotHistoryResponse, error) {
+	return c.client.GetLotHistory(ctx, &pb.GetLotHistoryRequest{
+		UserId: userID,
+		LotId:  lotID,
+		Skip:   skip,
+		Take:   take,
+	})
+}
+
+func (c *MarketClient) GetSellAllocations(ctx context.Context, userID, sellTransactionID string) (*pb.GetSellAllocationsResponse, error) {
+	return c.client.GetSellAllocations(ctx, &pb.GetSellAllocationsRequest{
+		UserId:            userID,
+		SellTransactionId: sellTransactionID,
+	})
+}
+
+func (c *MarketClient) GetRealizedGainsReport(ctx context.Context, request *pb.GetRealizedGainsReportRequest) (*pb.GetRealizedGainsReportResponse, error) {
+	return c.client.GetRealizedGainsReport(ctx, request)
+}
+
+func (c *MarketClient) ExportLedger(ctx context.Context, request *pb.ExportLedgerRequest) (pb.MarketService_ExportLedgerClient, error) {
+	return c.client.ExportLedger(ctx, request)
+}
@@ -5,6 +5,8 @@ import (
 
 	_ "github.com/go-sql-driver/mysql"
 
+	"github.com/Asif-Faizal/SpiceLedger-Backend/control"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/internal/platform"
 	"github.com/Asif-Faizal/SpiceLedger-Backend/market"
 	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
 )
@@ -24,10 +26,24 @@ func main() {
 	defer repo.Close()
 
 	// 4. Initialize Service
-	marketService := market.NewMarketService(repo, logger)
+	valuation, err := market.NewValuationPolicy(config.PriceValuation, config.PriceMaxAgeDays)
+	if err != nil {
+		log.Fatalf("invalid price valuation: %v", err)
+	}
+	marketService := market.NewMarketService(repo, platform.NewEventBus(config.EventRetention), valuation, logger)
+
+	// 5. Verify tokens against the keys the control service publishes, and their sessions
+	// against control's sessions so revoked ones stop within SESSION_CACHE_TTL
+	controlClient, err := control.NewControlClient(config.ResolveAccountGrpcURL())
+	if err != nil {
+		log.Fatalf("could not connect to control service: %v", err)
+	}
+	defer controlClient.Close()
+	keys := util.NewKeySet(controlClient.SigningKeys, config.JWKSCacheTTL, logger)
+	sessions := util.NewSessionCache(controlClient.CheckSession, config.SessionCacheTTL, logger)
 
-	// 5. Start gRPC Server
-	if err := market.ListenGrpcServer(marketService, logger, config); err != nil {
+	// 6. Start gRPC Server
+	if err := market.ListenGrpcServer(marketService, keys, sessions, logger, config); err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 }
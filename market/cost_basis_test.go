@@ -0,0 +1,251 @@
+package market
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+// testLots returns open lots in FIFO order: L1 10 @ 100, L2 5 @ 120, L3 8 @ 90.
+func testLots() []*BuyLot {
+	return []*BuyLot{
+		{ID: "L1", RemainingQty: dec("10"), Price: dec("100")},
+		{ID: "L2", RemainingQty: dec("5"), Price: dec("120")},
+		{ID: "L3", RemainingQty: dec("8"), Price: dec("90")},
+	}
+}
+
+func reversed(lots []*BuyLot) []*BuyLot {
+	out := make([]*BuyLot, 0, len(lots))
+	for i := len(lots) - 1; i >= 0; i-- {
+		out = append(out, lots[i])
+	}
+	return out
+}
+
+type wantDraw struct {
+	lotID string
+	qty   string
+}
+
+func TestPlanLotDraws(t *testing.T) {
+	tests := []struct {
+		name       string
+		lots       []*BuyLot
+		quantity   string
+		method     string
+		selections []LotSelection
+		want       []wantDraw
+		wantErr    string
+	}{
+		{
+			name:     "FIFO spans lots oldest first",
+			lots:     testLots(),
+			quantity: "12",
+			method:   CostBasisFIFO,
+			want:     []wantDraw{{"L1", "10"}, {"L2", "2"}},
+		},
+		{
+			name:     "FIFO inside the first lot",
+			lots:     testLots(),
+			quantity: "4.5",
+			method:   CostBasisFIFO,
+			want:     []wantDraw{{"L1", "4.5"}},
+		},
+		{
+			name:     "LIFO takes lots in the order given, newest first",
+			lots:     reversed(testLots()),
+			quantity: "9",
+			method:   CostBasisLIFO,
+			want:     []wantDraw{{"L3", "8"}, {"L2", "1"}},
+		},
+		{
+			name:     "weighted average tracks remainders FIFO",
+			lots:     testLots(),
+			quantity: "15",
+			method:   CostBasisWeightedAverage,
+			want:     []wantDraw{{"L1", "10"}, {"L2", "5"}},
+		},
+		{
+			name:     "more than the open lots leaves the rest undrawn",
+			lots:     testLots(),
+			quantity: "30",
+			method:   CostBasisFIFO,
+			want:     []wantDraw{{"L1", "10"}, {"L2", "5"}, {"L3", "8"}},
+		},
+		{
+			name:     "no open lots",
+			lots:     nil,
+			quantity: "1",
+			method:   CostBasisFIFO,
+			want:     nil,
+		},
+		{
+			name:     "specific lots follow the selection order",
+			lots:     testLots(),
+			quantity: "7",
+			method:   CostBasisSpecificLot,
+			selections: []LotSelection{
+				{LotID: "L3", Quantity: dec("4")},
+				{LotID: "L1", Quantity: dec("3")},
+			},
+			want: []wantDraw{{"L3", "4"}, {"L1", "3"}},
+		},
+		{
+			name:     "specific lot without quantity takes what is left to sell",
+			lots:     testLots(),
+			quantity: "6",
+			method:   CostBasisSpecificLot,
+			selections: []LotSelection{
+				{LotID: "L2", Quantity: dec("2")},
+				{LotID: "L3"},
+			},
+			want: []wantDraw{{"L2", "2"}, {"L3", "4"}},
+		},
+		{
+			name:     "specific lot without quantity is capped by the lot",
+			lots:     testLots(),
+			quantity: "9",
+			method:   CostBasisSpecificLot,
+			selections: []LotSelection{
+				{LotID: "L2"},
+				{LotID: "L1"},
+			},
+			want: []wantDraw{{"L2", "5"}, {"L1", "4"}},
+		},
+		{
+			name:       "specific lot that is not open",
+			lots:       testLots(),
+			quantity:   "1",
+			method:     CostBasisSpecificLot,
+			selections: []LotSelection{{LotID: "L9", Quantity: dec("1")}},
+			wantErr:    "lot L9 is not an open lot",
+		},
+		{
+			name:     "specific lot selected twice",
+			lots:     testLots(),
+			quantity: "2",
+			method:   CostBasisSpecificLot,
+			selections: []LotSelection{
+				{LotID: "L1", Quantity: dec("1")},
+				{LotID: "L1", Quantity: dec("1")},
+			},
+			wantErr: "lot L1 is selected more than once",
+		},
+		{
+			name:       "negative selection",
+			lots:       testLots(),
+			quantity:   "1",
+			method:     CostBasisSpecificLot,
+			selections: []LotSelection{{LotID: "L1", Quantity: dec("-1")}},
+			wantErr:    "must not be negative",
+		},
+		{
+			name:       "selection finer than the quantity scale",
+			lots:       testLots(),
+			quantity:   "1",
+			method:     CostBasisSpecificLot,
+			selections: []LotSelection{{LotID: "L1", Quantity: dec("0.00001")}},
+			wantErr:    "decimal places",
+		},
+		{
+			name:       "selection beyond the lot's remainder",
+			lots:       testLots(),
+			quantity:   "6",
+			method:     CostBasisSpecificLot,
+			selections: []LotSelection{{LotID: "L2", Quantity: dec("6")}},
+			wantErr:    "lot L2 has only 5.0000 remaining",
+		},
+		{
+			name:     "selections beyond the sell quantity",
+			lots:     testLots(),
+			quantity: "5",
+			method:   CostBasisSpecificLot,
+			selections: []LotSelection{
+				{LotID: "L1", Quantity: dec("3")},
+				{LotID: "L3", Quantity: dec("3")},
+			},
+			wantErr: "exceed the sell quantity",
+		},
+		{
+			name:       "selections short of the sell quantity",
+			lots:       testLots(),
+			quantity:   "5",
+			method:     CostBasisSpecificLot,
+			selections: []LotSelection{{LotID: "L1", Quantity: dec("3")}},
+			wantErr:    "do not cover the sell quantity",
+		},
+		{
+			name:     "no selections",
+			lots:     testLots(),
+			quantity: "1",
+			method:   CostBasisSpecificLot,
+			wantErr:  "do not cover the sell quantity",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draws, err := planLotDraws(tt.lots, dec(tt.quantity), tt.method, tt.selections)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(draws) != len(tt.want) {
+				t.Fatalf("got %d draws, want %d", len(draws), len(tt.want))
+			}
+			for i, w := range tt.want {
+				if draws[i].lot.ID != w.lotID || !draws[i].qty.Equal(dec(w.qty)) {
+					t.Errorf("draw %d = %s %s, want %s %s", i, draws[i].lot.ID, draws[i].qty, w.lotID, w.qty)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeCostBasisMethod(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"fifo", CostBasisFIFO, false},
+		{" lifo ", CostBasisLIFO, false},
+		{"Weighted_Average", CostBasisWeightedAverage, false},
+		{"SPECIFIC_LOT", CostBasisSpecificLot, false},
+		{"HIFO", "", true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeCostBasisMethod(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeCostBasisMethod(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestCostBasisFamily(t *testing.T) {
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{CostBasisFIFO, "LOT"},
+		{CostBasisLIFO, "LOT"},
+		{CostBasisSpecificLot, "LOT"},
+		{CostBasisWeightedAverage, CostBasisWeightedAverage},
+	}
+	for _, tt := range tests {
+		if got := costBasisFamily(tt.method); got != tt.want {
+			t.Errorf("costBasisFamily(%s) = %s, want %s", tt.method, got, tt.want)
+		}
+	}
+}
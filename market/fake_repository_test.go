@@ -0,0 +1,635 @@
+package market
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+)
+
+// fakeConnector lets BeginTx return a real *sql.Tx for the service to commit or roll back.
+// Beginning snapshots the fake repository and rolling back restores it, so a failed
+// operation leaves nothing behind, as in MySQL.
+type fakeConnector struct {
+	repo *fakeRepository
+}
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) { return fakeConn(c), nil }
+func (c fakeConnector) Driver() driver.Driver                            { return nil }
+
+type fakeConn struct {
+	repo *fakeRepository
+}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake connection runs no statements")
+}
+func (fakeConn) Close() error { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{repo: c.repo, snapshot: c.repo.snapshot()}, nil
+}
+
+type fakeTx struct {
+	repo     *fakeRepository
+	snapshot *fakeRepository
+}
+
+func (fakeTx) Commit() error { return nil }
+func (tx fakeTx) Rollback() error {
+	tx.repo.restore(tx.snapshot)
+	return nil
+}
+
+// fakeRepository keeps the ledger in memory and implements the Repository methods the
+// trading paths use. Any other method panics through the nil embedded interface.
+type fakeRepository struct {
+	Repository
+
+	mu           sync.Mutex
+	db           *sql.DB
+	clock        time.Time
+	transactions map[string]*Transaction
+	lots         map[string]*BuyLot
+	allocations  []*SellAllocation
+	shorts       map[string]*ShortLot
+	closedShorts map[string]bool
+	covers       []*ShortCover
+	positions    map[string]*Position
+	orders       map[string]*Order
+	fills        []*OrderFill
+	permissions  map[string]*TradingPermissions
+	preferences  map[string]*CostBasisPreference
+	currencies   map[string]string
+}
+
+func newFakeRepository() *fakeRepository {
+	f := &fakeRepository{
+		clock:        time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+		transactions: map[string]*Transaction{},
+		lots:         map[string]*BuyLot{},
+		shorts:       map[string]*ShortLot{},
+		closedShorts: map[string]bool{},
+		positions:    map[string]*Position{},
+		orders:       map[string]*Order{},
+		permissions:  map[string]*TradingPermissions{},
+		preferences:  map[string]*CostBasisPreference{},
+		currencies:   map[string]string{},
+	}
+	f.db = sql.OpenDB(fakeConnector{repo: f})
+	return f
+}
+
+// snapshot deep-copies the ledger state.
+func (f *fakeRepository) snapshot() *fakeRepository {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := &fakeRepository{
+		clock:        f.clock,
+		transactions: map[string]*Transaction{},
+		lots:         map[string]*BuyLot{},
+		shorts:       map[string]*ShortLot{},
+		closedShorts: map[string]bool{},
+		positions:    map[string]*Position{},
+		orders:       map[string]*Order{},
+	}
+	for k, v := range f.transactions {
+		c := *v
+		s.transactions[k] = &c
+	}
+	for k, v := range f.lots {
+		c := *v
+		s.lots[k] = &c
+	}
+	for _, v := range f.allocations {
+		c := *v
+		s.allocations = append(s.allocations, &c)
+	}
+	for k, v := range f.shorts {
+		c := *v
+		s.shorts[k] = &c
+	}
+	for k, v := range f.closedShorts {
+		s.closedShorts[k] = v
+	}
+	for _, v := range f.covers {
+		c := *v
+		s.covers = append(s.covers, &c)
+	}
+	for k, v := range f.positions {
+		c := *v
+		s.positions[k] = &c
+	}
+	for k, v := range f.orders {
+		c := *v
+		s.orders[k] = &c
+	}
+	for _, v := range f.fills {
+		c := *v
+		s.fills = append(s.fills, &c)
+	}
+	return s
+}
+
+// restore puts back the ledger state of a snapshot. Settings (permissions, preferences and
+// currencies) are not transactional in the fake.
+func (f *fakeRepository) restore(s *fakeRepository) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.transactions, f.lots, f.allocations = s.transactions, s.lots, s.allocations
+	f.shorts, f.closedShorts, f.covers = s.shorts, s.closedShorts, s.covers
+	f.positions, f.orders, f.fills = s.positions, s.orders, s.fills
+}
+
+// tick advances the fake clock, so rows get distinct, ordered creation times.
+func (f *fakeRepository) tick() time.Time {
+	f.clock = f.clock.Add(time.Second)
+	return f.clock
+}
+
+func positionKeyOf(userID, spiceGradeID string) string { return userID + "/" + spiceGradeID }
+
+func (f *fakeRepository) BeginTx(ctx context.Context) (context.Context, *sql.Tx, error) {
+	tx, err := f.db.BeginTx(ctx, nil)
+	return ctx, tx, err
+}
+
+func (f *fakeRepository) InsertTransaction(ctx context.Context, t *Transaction) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if t.IdempotencyKey != "" {
+		for _, other := range f.transactions {
+			if other.UserID == t.UserID && other.IdempotencyKey == t.IdempotencyKey {
+				return "", ErrDuplicateIdempotencyKey
+			}
+		}
+	}
+	if t.Status == "" {
+		t.Status = TransactionActive
+	}
+	t.CreatedAt = f.tick()
+	stored := *t
+	f.transactions[t.ID] = &stored
+	return t.ID, nil
+}
+
+func (f *fakeRepository) GetTransactionByIdempotencyKey(ctx context.Context, userID, key string) (*Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range f.transactions {
+		if t.UserID == userID && t.IdempotencyKey == key {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeRepository) GetTransactionByID(ctx context.Context, id string) (*Transaction, error) {
+	return f.LockTransaction(ctx, id)
+}
+
+func (f *fakeRepository) LockTransaction(ctx context.Context, id string) (*Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.transactions[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (f *fakeRepository) ListActiveSellsSince(ctx context.Context, userID string, spiceGradeID string, method string, since time.Time) ([]*Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var sells []*Transaction
+	for _, t := range f.transactions {
+		if t.UserID == userID && t.SpiceGradeID == spiceGradeID && t.Type == "SELL" &&
+			t.Status == TransactionActive && t.CostBasisMethod == method && !t.CreatedAt.Before(since) {
+			copied := *t
+			sells = append(sells, &copied)
+		}
+	}
+	sort.Slice(sells, func(i, j int) bool { return sells[i].CreatedAt.Before(sells[j].CreatedAt) })
+	return sells, nil
+}
+
+func (f *fakeRepository) SetTransactionStatus(ctx context.Context, id string, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.transactions[id].Status = status
+	return nil
+}
+
+func (f *fakeRepository) ListFeeSchedules(ctx context.Context, spiceGradeID string, side string, on time.Time) ([]*FeeSchedule, error) {
+	return nil, nil
+}
+
+func (f *fakeRepository) InsertBuyLot(ctx context.Context, lot *BuyLot) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	lot.CreatedAt = f.tick()
+	stored := *lot
+	f.lots[lot.ID] = &stored
+	return lot.ID, nil
+}
+
+func (f *fakeRepository) GetOpenBuyLots(ctx context.Context, userID string, spiceGradeID string, method string) ([]*BuyLot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var lots []*BuyLot
+	for _, l := range f.lots {
+		if l.UserID == userID && l.SpiceGradeID == spiceGradeID && l.RemainingQty.IsPositive() {
+			copied := *l
+			lots = append(lots, &copied)
+		}
+	}
+	sort.Slice(lots, func(i, j int) bool {
+		a, b := lots[i], lots[j]
+		if method == CostBasisLIFO {
+			a, b = b, a
+		}
+		if !a.TradeDate.Equal(b.TradeDate) {
+			return a.TradeDate.Before(b.TradeDate)
+		}
+		return a.ID < b.ID
+	})
+	return lots, nil
+}
+
+func (f *fakeRepository) DeductBuyLotQty(ctx context.Context, lotID string, qty decimal.Decimal) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	lot := f.lots[lotID]
+	if lot.RemainingQty.LessThan(qty) {
+		return ErrInsufficientLotQty
+	}
+	lot.RemainingQty = lot.RemainingQty.Sub(qty)
+	return nil
+}
+
+func (f *fakeRepository) RestoreBuyLotQty(ctx context.Context, lotID string, qty decimal.Decimal) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	lot := f.lots[lotID]
+	lot.RemainingQty = lot.RemainingQty.Add(qty)
+	return nil
+}
+
+func (f *fakeRepository) LockBuyLotByTransaction(ctx context.Context, transactionID string) (*BuyLot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, l := range f.lots {
+		if l.TransactionID == transactionID {
+			copied := *l
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeRepository) CloseBuyLot(ctx context.Context, lotID string, reversalID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lots[lotID].RemainingQty = decimal.Zero
+	return nil
+}
+
+func (f *fakeRepository) InsertSellAllocation(ctx context.Context, alloc *SellAllocation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	alloc.CreatedAt = f.tick()
+	stored := *alloc
+	f.allocations = append(f.allocations, &stored)
+	return nil
+}
+
+func (f *fakeRepository) activeAllocations(match func(*SellAllocation) bool) []*SellAllocation {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var allocs []*SellAllocation
+	for _, a := range f.allocations {
+		if a.ReversedByTransactionID == "" && match(a) {
+			copied := *a
+			allocs = append(allocs, &copied)
+		}
+	}
+	return allocs
+}
+
+func (f *fakeRepository) ListActiveAllocationsBySell(ctx context.Context, sellTransactionID string) ([]*SellAllocation, error) {
+	return f.activeAllocations(func(a *SellAllocation) bool { return a.SellTransactionID == sellTransactionID }), nil
+}
+
+func (f *fakeRepository) ListActiveAllocationsByLot(ctx context.Context, lotID string) ([]*SellAllocation, error) {
+	return f.activeAllocations(func(a *SellAllocation) bool { return a.BuyLotID == lotID }), nil
+}
+
+func (f *fakeRepository) ReverseSellAllocation(ctx context.Context, allocID string, reversalID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, a := range f.allocations {
+		if a.ID == allocID {
+			a.ReversedByTransactionID = reversalID
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepository) InsertShortLot(ctx context.Context, lot *ShortLot) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	lot.CreatedAt = f.tick()
+	stored := *lot
+	f.shorts[lot.ID] = &stored
+	return nil
+}
+
+func (f *fakeRepository) GetOpenShortLots(ctx context.Context, userID string, spiceGradeID string) ([]*ShortLot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var lots []*ShortLot
+	for _, l := range f.shorts {
+		if l.UserID == userID && l.SpiceGradeID == spiceGradeID && l.RemainingQty.IsPositive() {
+			copied := *l
+			lots = append(lots, &copied)
+		}
+	}
+	sort.Slice(lots, func(i, j int) bool {
+		if !lots[i].TradeDate.Equal(lots[j].TradeDate) {
+			return lots[i].TradeDate.Before(lots[j].TradeDate)
+		}
+		return lots[i].ID < lots[j].ID
+	})
+	return lots, nil
+}
+
+func (f *fakeRepository) DeductShortLotQty(ctx context.Context, lotID string, qty decimal.Decimal) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	lot := f.shorts[lotID]
+	if lot.RemainingQty.LessThan(qty) {
+		return ErrInsufficientLotQty
+	}
+	lot.RemainingQty = lot.RemainingQty.Sub(qty)
+	return nil
+}
+
+func (f *fakeRepository) RestoreShortLotQty(ctx context.Context, lotID string, qty decimal.Decimal) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	lot := f.shorts[lotID]
+	lot.RemainingQty = lot.RemainingQty.Add(qty)
+	return nil
+}
+
+func (f *fakeRepository) ListActiveShortLotsBySell(ctx context.Context, sellTransactionID string) ([]*ShortLot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var lots []*ShortLot
+	for _, l := range f.shorts {
+		if l.TransactionID == sellTransactionID && !f.closedShorts[l.ID] {
+			copied := *l
+			lots = append(lots, &copied)
+		}
+	}
+	return lots, nil
+}
+
+func (f *fakeRepository) CloseShortLot(ctx context.Context, lotID string, reversalID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.shorts[lotID].RemainingQty = decimal.Zero
+	f.closedShorts[lotID] = true
+	return nil
+}
+
+func (f *fakeRepository) InsertShortCover(ctx context.Context, cover *ShortCover) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cover.CreatedAt = f.tick()
+	stored := *cover
+	f.covers = append(f.covers, &stored)
+	return nil
+}
+
+func (f *fakeRepository) ListActiveCoversByBuy(ctx context.Context, buyTransactionID string) ([]*ShortCover, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var covers []*ShortCover
+	for _, c := range f.covers {
+		if c.BuyTransactionID == buyTransactionID && c.ReversedByTransactionID == "" {
+			copied := *c
+			covers = append(covers, &copied)
+		}
+	}
+	return covers, nil
+}
+
+func (f *fakeRepository) ReverseShortCover(ctx context.Context, coverID string, reversalID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range f.covers {
+		if c.ID == coverID {
+			c.ReversedByTransactionID = reversalID
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepository) GetTradingPermissions(ctx context.Context, userID string) (*TradingPermissions, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	perms, ok := f.permissions[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *perms
+	return &copied, nil
+}
+
+// UpsertPosition adds the deltas like the MySQL upsert, taking the currency when flat.
+func (f *fakeRepository) UpsertPosition(ctx context.Context, pos *Position) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := positionKeyOf(pos.UserID, pos.SpiceGradeID)
+	stored, ok := f.positions[key]
+	if !ok {
+		currency := pos.Currency
+		if currency == "" {
+			currency = util.DefaultCurrency
+		}
+		f.positions[key] = &Position{
+			UserID:       pos.UserID,
+			SpiceGradeID: pos.SpiceGradeID,
+			Currency:     currency,
+			TotalQty:     pos.TotalQty,
+			TotalCost:    pos.TotalCost,
+			RealizedPnL:  pos.RealizedPnL,
+		}
+		return nil
+	}
+	if pos.Currency != "" && stored.TotalQty.IsZero() {
+		stored.Currency = pos.Currency
+	}
+	stored.TotalQty = stored.TotalQty.Add(pos.TotalQty)
+	stored.TotalCost = stored.TotalCost.Add(pos.TotalCost)
+	stored.RealizedPnL = stored.RealizedPnL.Add(pos.RealizedPnL)
+	return nil
+}
+
+func (f *fakeRepository) GetGradePosition(ctx context.Context, userID string, spiceGradeID string) (*Position, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pos, ok := f.positions[positionKeyOf(userID, spiceGradeID)]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *pos
+	return &copied, nil
+}
+
+func (f *fakeRepository) LockGradePosition(ctx context.Context, userID string, spiceGradeID string) (*Position, error) {
+	return f.GetGradePosition(ctx, userID, spiceGradeID)
+}
+
+func (f *fakeRepository) GetCostBasisPreference(ctx context.Context, userID string, spiceGradeID string) (*CostBasisPreference, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if pref, ok := f.preferences[positionKeyOf(userID, spiceGradeID)]; ok {
+		copied := *pref
+		return &copied, nil
+	}
+	if pref, ok := f.preferences[positionKeyOf(userID, "")]; ok {
+		copied := *pref
+		return &copied, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeRepository) GetAccountCurrencies(ctx context.Context, userID string) (string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	currency, ok := f.currencies[userID]
+	if !ok {
+		currency = util.DefaultCurrency
+	}
+	return currency, currency, nil
+}
+
+func (f *fakeRepository) LockOrderBook(ctx context.Context, spiceGradeID string) error {
+	return nil
+}
+
+func (f *fakeRepository) InsertOrder(ctx context.Context, order *Order) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := *order
+	f.orders[order.ID] = &stored
+	return nil
+}
+
+// ListCrossingOrders returns resting orders in price-time priority like the MySQL query.
+func (f *fakeRepository) ListCrossingOrders(ctx context.Context, spiceGradeID string, currency string, side string, limitPrice decimal.Decimal, excludeUserID string) ([]*Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var orders []*Order
+	for _, o := range f.orders {
+		if o.SpiceGradeID != spiceGradeID || o.Currency != currency || o.Side != side || o.UserID == excludeUserID {
+			continue
+		}
+		if o.Status != OrderOpen && o.Status != OrderPartiallyFilled {
+			continue
+		}
+		if (side == OrderSideBuy && o.Price.LessThan(limitPrice)) || (side == OrderSideSell && o.Price.GreaterThan(limitPrice)) {
+			continue
+		}
+		copied := *o
+		orders = append(orders, &copied)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		a, b := orders[i], orders[j]
+		if !a.Price.Equal(b.Price) {
+			if side == OrderSideBuy {
+				return a.Price.GreaterThan(b.Price)
+			}
+			return a.Price.LessThan(b.Price)
+		}
+		if !a.PriorityAt.Equal(b.PriorityAt) {
+			return a.PriorityAt.Before(b.PriorityAt)
+		}
+		return a.ID < b.ID
+	})
+	return orders, nil
+}
+
+func (f *fakeRepository) SumRestingSellQty(ctx context.Context, userID string, spiceGradeID string, excludeOrderID string) (decimal.Decimal, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	total := decimal.Zero
+	for _, o := range f.orders {
+		if o.UserID == userID && o.SpiceGradeID == spiceGradeID && o.Side == OrderSideSell && o.ID != excludeOrderID &&
+			(o.Status == OrderOpen || o.Status == OrderPartiallyFilled) {
+			total = total.Add(o.RemainingQty)
+		}
+	}
+	return total, nil
+}
+
+func (f *fakeRepository) UpdateOrder(ctx context.Context, order *Order) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := *order
+	f.orders[order.ID] = &stored
+	return nil
+}
+
+func (f *fakeRepository) InsertOrderFill(ctx context.Context, fill *OrderFill) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := *fill
+	f.fills = append(f.fills, &stored)
+	return nil
+}
+
+// newTestService wires a MarketService over a fresh fake repository, without an event bus.
+func newTestService() (*MarketService, *fakeRepository) {
+	repo := newFakeRepository()
+	return &MarketService{repository: repo, logger: util.NewLogger("error")}, repo
+}
+
+// position returns the stored position, or a zero one when none exists.
+func (f *fakeRepository) position(userID, spiceGradeID string) Position {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if pos, ok := f.positions[positionKeyOf(userID, spiceGradeID)]; ok {
+		return *pos
+	}
+	return Position{}
+}
+
+// openLotCost sums remaining × price over a user's open lots of a grade, less the proceeds
+// still held by its open short lots.
+func (f *fakeRepository) openLotCost(userID, spiceGradeID string) decimal.Decimal {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	total := decimal.Zero
+	for _, l := range f.lots {
+		if l.UserID == userID && l.SpiceGradeID == spiceGradeID {
+			total = total.Add(lotCost(l.RemainingQty, l.Price, util.DefaultCurrency))
+		}
+	}
+	for _, l := range f.shorts {
+		if l.UserID == userID && l.SpiceGradeID == spiceGradeID {
+			total = total.Sub(lotCost(l.RemainingQty, l.Price, util.DefaultCurrency))
+		}
+	}
+	return total
+}
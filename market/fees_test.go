@@ -0,0 +1,77 @@
+package market
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFeeShares(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    string
+		quantity string
+		currency string
+		parts    []string
+		want     []string
+	}{
+		{
+			name: "single part takes the whole fee", total: "12.34", quantity: "10", currency: "INR",
+			parts: []string{"10"}, want: []string{"12.34"},
+		},
+		{
+			name: "even split", total: "10", quantity: "10", currency: "INR",
+			parts: []string{"5", "5"}, want: []string{"5", "5"},
+		},
+		{
+			name: "last part takes the rounding remainder", total: "10", quantity: "3", currency: "INR",
+			parts: []string{"1", "1", "1"}, want: []string{"3.33", "3.33", "3.34"},
+		},
+		{
+			name: "remainder can make the last share smaller", total: "0.05", quantity: "3", currency: "INR",
+			parts: []string{"1", "1", "1"}, want: []string{"0.02", "0.02", "0.01"},
+		},
+		{
+			name: "rounds to a zero-decimal currency", total: "100", quantity: "7", currency: "JPY",
+			parts: []string{"3", "4"}, want: []string{"43", "57"},
+		},
+		{
+			name: "rounds to a three-decimal currency", total: "1", quantity: "3", currency: "KWD",
+			parts: []string{"2", "1"}, want: []string{"0.667", "0.333"},
+		},
+		{
+			name: "fractional quantities", total: "7.5", quantity: "2.5", currency: "USD",
+			parts: []string{"0.3333", "2.1667"}, want: []string{"1", "6.5"},
+		},
+		{
+			name: "zero fee", total: "0", quantity: "4", currency: "INR",
+			parts: []string{"1", "3"}, want: []string{"0", "0"},
+		},
+		{
+			name: "no parts", total: "5", quantity: "4", currency: "INR",
+			parts: nil, want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := make([]decimal.Decimal, len(tt.parts))
+			for i, p := range tt.parts {
+				parts[i] = dec(p)
+			}
+			got := feeShares(dec(tt.total), dec(tt.quantity), tt.currency, parts)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d shares, want %d", len(got), len(tt.want))
+			}
+			sum := decimal.Zero
+			for i, share := range got {
+				if !share.Equal(dec(tt.want[i])) {
+					t.Errorf("share %d = %s, want %s", i, share, tt.want[i])
+				}
+				sum = sum.Add(share)
+			}
+			if len(got) > 0 && !sum.Equal(dec(tt.total)) {
+				t.Errorf("shares add up to %s, want %s", sum, tt.total)
+			}
+		})
+	}
+}
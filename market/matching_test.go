@@ -0,0 +1,220 @@
+package market
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// restingOrder is an order already in the book, inserted straight into the fake repository.
+type restingOrder struct {
+	name     string
+	user     string
+	side     string
+	qty      string
+	price    string
+	priority int    // day the order took its place in the queue
+	stocked  bool   // whether a seller holds the inventory
+	heldIn   string // currency of a position the owner already holds in the grade
+}
+
+type wantFill struct {
+	resting string
+	qty     string
+	price   string
+}
+
+type wantOrder struct {
+	status    string
+	remaining string
+}
+
+func TestMatchOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		resting    []restingOrder
+		side       string
+		qty        string
+		price      string
+		wantFills  []wantFill
+		wantOrder  wantOrder
+		wantRested map[string]wantOrder
+	}{
+		{
+			name: "buy takes the cheapest sells, oldest first at a price",
+			resting: []restingOrder{
+				{name: "A", user: "seller-a", side: OrderSideSell, qty: "5", price: "125", priority: 2, stocked: true},
+				{name: "B", user: "seller-b", side: OrderSideSell, qty: "5", price: "120", priority: 3, stocked: true},
+				{name: "C", user: "seller-c", side: OrderSideSell, qty: "5", price: "120", priority: 1, stocked: true},
+				{name: "D", user: "seller-d", side: OrderSideSell, qty: "5", price: "135", priority: 1, stocked: true},
+			},
+			side: OrderSideBuy, qty: "10", price: "130",
+			wantFills: []wantFill{{"C", "5", "120"}, {"B", "5", "120"}},
+			wantOrder: wantOrder{OrderFilled, "0"},
+			wantRested: map[string]wantOrder{
+				"A": {OrderOpen, "5"}, "B": {OrderFilled, "0"}, "C": {OrderFilled, "0"}, "D": {OrderOpen, "5"},
+			},
+		},
+		{
+			name: "sell takes the highest bids first",
+			resting: []restingOrder{
+				{name: "X", user: "buyer-x", side: OrderSideBuy, qty: "5", price: "110", priority: 1},
+				{name: "Y", user: "buyer-y", side: OrderSideBuy, qty: "5", price: "115", priority: 2},
+				{name: "Z", user: "buyer-z", side: OrderSideBuy, qty: "5", price: "95", priority: 1},
+			},
+			side: OrderSideSell, qty: "7", price: "100",
+			wantFills: []wantFill{{"Y", "5", "115"}, {"X", "2", "110"}},
+			wantOrder: wantOrder{OrderFilled, "0"},
+			wantRested: map[string]wantOrder{
+				"X": {OrderPartiallyFilled, "3"}, "Y": {OrderFilled, "0"}, "Z": {OrderOpen, "5"},
+			},
+		},
+		{
+			name: "resting order partially filled",
+			resting: []restingOrder{
+				{name: "A", user: "seller-a", side: OrderSideSell, qty: "5", price: "120", priority: 1, stocked: true},
+			},
+			side: OrderSideBuy, qty: "3", price: "130",
+			wantFills:  []wantFill{{"A", "3", "120"}},
+			wantOrder:  wantOrder{OrderFilled, "0"},
+			wantRested: map[string]wantOrder{"A": {OrderPartiallyFilled, "2"}},
+		},
+		{
+			name: "incoming order partially filled rests the rest",
+			resting: []restingOrder{
+				{name: "A", user: "seller-a", side: OrderSideSell, qty: "5", price: "120", priority: 1, stocked: true},
+			},
+			side: OrderSideBuy, qty: "8", price: "130",
+			wantFills:  []wantFill{{"A", "5", "120"}},
+			wantOrder:  wantOrder{OrderPartiallyFilled, "3"},
+			wantRested: map[string]wantOrder{"A": {OrderFilled, "0"}},
+		},
+		{
+			name: "no crossing price leaves both orders open",
+			resting: []restingOrder{
+				{name: "A", user: "seller-a", side: OrderSideSell, qty: "5", price: "120", priority: 1, stocked: true},
+			},
+			side: OrderSideBuy, qty: "5", price: "100",
+			wantOrder:  wantOrder{OrderOpen, "5"},
+			wantRested: map[string]wantOrder{"A": {OrderOpen, "5"}},
+		},
+		{
+			name: "own resting orders are skipped",
+			resting: []restingOrder{
+				{name: "own", user: testUser, side: OrderSideSell, qty: "5", price: "110", priority: 1, stocked: true},
+				{name: "A", user: "seller-a", side: OrderSideSell, qty: "5", price: "125", priority: 2, stocked: true},
+			},
+			side: OrderSideBuy, qty: "5", price: "130",
+			wantFills:  []wantFill{{"A", "5", "125"}},
+			wantOrder:  wantOrder{OrderFilled, "0"},
+			wantRested: map[string]wantOrder{"own": {OrderOpen, "5"}, "A": {OrderFilled, "0"}},
+		},
+		{
+			name: "resting sell no longer covered by inventory is cancelled",
+			resting: []restingOrder{
+				{name: "A", user: "seller-a", side: OrderSideSell, qty: "5", price: "120", priority: 1},
+				{name: "B", user: "seller-b", side: OrderSideSell, qty: "5", price: "125", priority: 2, stocked: true},
+			},
+			side: OrderSideBuy, qty: "5", price: "130",
+			wantFills:  []wantFill{{"B", "5", "125"}},
+			wantOrder:  wantOrder{OrderFilled, "0"},
+			wantRested: map[string]wantOrder{"A": {OrderCancelled, "5"}, "B": {OrderFilled, "0"}},
+		},
+		{
+			name: "resting buy whose owner now holds the grade in another currency is cancelled",
+			resting: []restingOrder{
+				{name: "X", user: "buyer-x", side: OrderSideBuy, qty: "5", price: "115", priority: 1, heldIn: "USD"},
+				{name: "Y", user: "buyer-y", side: OrderSideBuy, qty: "5", price: "110", priority: 2},
+			},
+			side: OrderSideSell, qty: "5", price: "100",
+			wantFills:  []wantFill{{"Y", "5", "110"}},
+			wantOrder:  wantOrder{OrderFilled, "0"},
+			wantRested: map[string]wantOrder{"X": {OrderCancelled, "5"}, "Y": {OrderFilled, "0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, repo := newTestService()
+
+			held := decimal.Zero
+			ids := map[string]string{}
+			names := map[string]string{}
+			placedAt := map[string]Order{}
+			for _, r := range tt.resting {
+				if r.stocked {
+					if r.user == testUser {
+						held = held.Add(dec(r.qty))
+					}
+					if _, err := s.Buy(ctx, r.user, testGrade, dec(r.qty), dec("100"), "", day(1), ""); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if r.heldIn != "" {
+					repo.currencies[r.user] = r.heldIn
+					if _, err := s.Buy(ctx, r.user, testGrade, dec("1"), dec("100"), "", day(1), ""); err != nil {
+						t.Fatal(err)
+					}
+					delete(repo.currencies, r.user)
+				}
+				order := &Order{
+					ID: "order-" + r.name, UserID: r.user, SpiceGradeID: testGrade, Side: r.side,
+					Price: dec(r.price), Currency: "INR", Quantity: dec(r.qty), RemainingQty: dec(r.qty),
+					Status: OrderOpen, PriorityAt: day(r.priority), CreatedAt: day(r.priority), UpdatedAt: day(r.priority),
+				}
+				if err := repo.InsertOrder(ctx, order); err != nil {
+					t.Fatal(err)
+				}
+				ids[r.name], names[order.ID], placedAt[r.name] = order.ID, r.name, *order
+			}
+			if tt.side == OrderSideSell {
+				mustBuy(t, s, tt.qty, "100", day(1))
+			}
+
+			result, err := s.PlaceOrder(ctx, testUser, testGrade, tt.side, dec(tt.qty), dec(tt.price))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Fills) != len(tt.wantFills) {
+				t.Fatalf("got %d fills, want %d", len(result.Fills), len(tt.wantFills))
+			}
+			for i, fill := range result.Fills {
+				want := tt.wantFills[i]
+				resting := fill.SellOrderID
+				if tt.side == OrderSideSell {
+					resting = fill.BuyOrderID
+				}
+				if names[resting] != want.resting || !fill.Quantity.Equal(dec(want.qty)) || !fill.Price.Equal(dec(want.price)) {
+					t.Errorf("fill %d = %s %s @ %s, want %s %s @ %s",
+						i, names[resting], fill.Quantity, fill.Price, want.resting, want.qty, want.price)
+				}
+			}
+
+			checkOrder := func(label string, got *Order, want wantOrder) {
+				t.Helper()
+				if got.Status != want.status || !got.RemainingQty.Equal(dec(want.remaining)) {
+					t.Errorf("%s = %s with %s left, want %s with %s left", label, got.Status, got.RemainingQty, want.status, want.remaining)
+				}
+			}
+			checkOrder("incoming order", result.Order, tt.wantOrder)
+			checkOrder("stored incoming order", repo.orders[result.Order.ID], tt.wantOrder)
+			for name, want := range tt.wantRested {
+				got := repo.orders[ids[name]]
+				checkOrder("resting "+name, got, want)
+				if got.Status != placedAt[name].Status && !got.UpdatedAt.After(placedAt[name].UpdatedAt) {
+					t.Errorf("resting %s changed status but kept updated_at %s", name, got.UpdatedAt)
+				}
+			}
+
+			want := held
+			for _, f := range tt.wantFills {
+				want = want.Add(dec(f.qty))
+			}
+			if pos := repo.position(testUser, testGrade); tt.side == OrderSideBuy && !pos.TotalQty.Equal(want) {
+				t.Errorf("buyer position = %s, want %s", pos.TotalQty, want)
+			}
+		})
+	}
+}
@@ -1,26 +1,88 @@
 package market
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type Transaction struct {
 	ID           string
 	UserID       string
 	SpiceGradeID string
 	Type         string
-	Quantity     float64
-	Price        float64
-	TradeDate    time.Time
-	CreatedAt    time.Time
+	Quantity     decimal.Decimal
+	Price        decimal.Decimal
+	// Currency is the account's trading currency when the trade was booked; a reversal or
+	// amendment keeps the original's.
+	Currency string
+	// FeeTotal sums the Fees charged on a BUY or SELL. A BUY capitalises it into its lot's
+	// cost; a SELL deducts it from its realized P&L. Fees is only loaded when booking.
+	FeeTotal decimal.Decimal
+	Fees     []*TransactionFee
+	// CostBasisMethod is set on SELL rows only; empty for BUY.
+	CostBasisMethod string
+	Status          string // ACTIVE or CANCELLED
+	// ReversesTransactionID is set on REVERSAL rows; AmendsTransactionID on the
+	// replacement trade booked by AmendTransaction.
+	ReversesTransactionID string
+	AmendsTransactionID   string
+	Note                  string
+	// IdempotencyKey is the client-supplied retry key, unique per user.
+	IdempotencyKey string
+	TradeDate      time.Time
+	CreatedAt      time.Time
+}
+
+// TransactionFee is one fee or tax charged on a trade by a control fee schedule, in the
+// trade's currency.
+type TransactionFee struct {
+	ID            string
+	TransactionID string
+	FeeScheduleID string
+	Code          string
+	Kind          string // FEE or TAX
+	Basis         string // PERCENT, PER_KG or FLAT
+	Rate          decimal.Decimal
+	Amount        decimal.Decimal
+	Currency      string
+}
+
+// FeeSchedule is a control fee_schedules row as it applies to a trade: GradeID is empty for a
+// schedule set on the grade's product category.
+type FeeSchedule struct {
+	ID            string
+	GradeID       string
+	Code          string
+	Kind          string
+	Side          string // BUY, SELL or BOTH
+	Basis         string
+	Rate          decimal.Decimal
+	Currency      string
+	EffectiveDate time.Time
 }
 
+// Fee schedule bases: a percentage of the trade value, an amount per kilogram or per trade.
+const (
+	FeeBasisPercent = "PERCENT"
+	FeeBasisPerKg   = "PER_KG"
+	FeeBasisFlat    = "FLAT"
+)
+
+// Transaction statuses. A cancelled trade keeps its row; a REVERSAL row records the cancellation.
+const (
+	TransactionActive    = "ACTIVE"
+	TransactionCancelled = "CANCELLED"
+)
+
 type BuyLot struct {
 	ID            string
 	TransactionID string
 	UserID        string
 	SpiceGradeID  string
-	OriginalQty   float64
-	RemainingQty  float64
-	Price         float64
+	OriginalQty   decimal.Decimal
+	RemainingQty  decimal.Decimal
+	Price         decimal.Decimal
 	TradeDate     time.Time
 	CreatedAt     time.Time
 }
@@ -29,33 +91,196 @@ type SellAllocation struct {
 	ID                string
 	SellTransactionID string
 	BuyLotID          string
-	Quantity          float64
-	BuyPrice          float64
-	SellPrice         float64
-	RealizedPnL       float64
-	CreatedAt         time.Time
+	Quantity          decimal.Decimal
+	BuyPrice          decimal.Decimal
+	SellPrice         decimal.Decimal
+	RealizedPnL       decimal.Decimal
+	CostBasisMethod   string
+	// CloseAdjustment is cost beyond quantity × buy price. A weighted-average sell that closes
+	// the position releases its exact stored cost; the rounding difference is booked on the
+	// sell's last allocation, already included in its RealizedPnL.
+	CloseAdjustment decimal.Decimal
+	// ReversedByTransactionID is the REVERSAL that undid this allocation; empty while it stands.
+	ReversedByTransactionID string
+	CreatedAt               time.Time
+}
+
+// cost is what the allocation released from the position's total cost.
+func (a *SellAllocation) cost(currency string) decimal.Decimal {
+	return lotCost(a.Quantity, a.BuyPrice, currency).Add(a.CloseAdjustment)
+}
+
+// ShortLot is the part of a SELL that went beyond the open buy lots, carried at the
+// sell price until later BUYs cover it.
+type ShortLot struct {
+	ID            string
+	TransactionID string
+	UserID        string
+	SpiceGradeID  string
+	OriginalQty   decimal.Decimal
+	RemainingQty  decimal.Decimal
+	Price         decimal.Decimal
+	TradeDate     time.Time
+	CreatedAt     time.Time
+}
+
+// ShortCover pairs a covering BUY with one short lot. RealizedPnL is the short
+// proceeds less the cost of buying the quantity back.
+type ShortCover struct {
+	ID               string
+	BuyTransactionID string
+	ShortLotID       string
+	Quantity         decimal.Decimal
+	ShortPrice       decimal.Decimal
+	CoverPrice       decimal.Decimal
+	RealizedPnL      decimal.Decimal
+	// ReversedByTransactionID is the REVERSAL that undid this cover; empty while it stands.
+	ReversedByTransactionID string
+	CreatedAt               time.Time
+}
+
+// TradingPermissions are the opt-in capabilities of an account. Accounts without a
+// stored row have every capability off.
+type TradingPermissions struct {
+	UserID            string
+	AllowShortSelling bool
+	UpdatedBy         string
+	UpdatedAt         time.Time
+}
+
+// AllocationDetail is a sell allocation read back for the lot-level query API, with
+// the account, grade and trade date of the SELL it belongs to.
+type AllocationDetail struct {
+	SellAllocation
+	UserID        string
+	SpiceGradeID  string
+	SellTradeDate time.Time
+}
+
+// AllocationFilter narrows GetSellAllocations. Empty IDs and dates do not filter;
+// reversed allocations are left out unless IncludeReversed is set.
+type AllocationFilter struct {
+	UserID            string
+	SellTransactionID string
+	BuyLotID          string
+	SpiceGradeID      string
+	DateFrom          string // SELL trade_date, YYYY-MM-DD
+	DateTo            string
+	IncludeReversed   bool
+	Skip              uint
+	Take              uint
+}
+
+// LotHistory is a buy lot together with every allocation ever drawn from it,
+// including allocations later reversed by a cancellation.
+type LotHistory struct {
+	Lot         *BuyLot
+	Allocations []*AllocationDetail
 }
 
 type Position struct {
 	UserID       string
 	SpiceGradeID string
-	TotalQty     float64
-	TotalCost    float64
-	RealizedPnL  float64
+	// Currency is what the position is held in; trades in another are refused while it is open.
+	Currency    string
+	TotalQty    decimal.Decimal
+	TotalCost   decimal.Decimal
+	RealizedPnL decimal.Decimal
+	UpdatedAt   time.Time
+}
+
+// Cost-basis methods a SELL can be matched with. FIFO applies when neither the
+// request nor the account/grade preference names one.
+const (
+	CostBasisFIFO            = "FIFO"
+	CostBasisLIFO            = "LIFO"
+	CostBasisWeightedAverage = "WEIGHTED_AVERAGE"
+	CostBasisSpecificLot     = "SPECIFIC_LOT"
+)
+
+// LotSelection names a buy lot to consume in a SPECIFIC_LOT sell.
+// A zero Quantity takes as much of the lot as the sell still needs.
+type LotSelection struct {
+	LotID    string
+	Quantity decimal.Decimal
+}
+
+// SellOptions carries the per-sell cost-basis choice. Empty CostBasisMethod
+// falls back to the stored preference.
+type SellOptions struct {
+	CostBasisMethod string
+	Lots            []LotSelection
+}
+
+// CancelResult describes a cancellation: the original trade (now CANCELLED), the
+// compensating REVERSAL row, and any later sells that were re-matched to other lots.
+type CancelResult struct {
+	Original           *Transaction
+	Reversal           *Transaction
+	ReallocatedSellIDs []string
+}
+
+// Amendment holds the corrected values for AmendTransaction. Zero fields keep the original value.
+type Amendment struct {
+	Quantity   decimal.Decimal
+	Price      decimal.Decimal
+	TradeDate  time.Time
+	Reason     string
+	Reallocate bool
+	Sell       SellOptions
+}
+
+// AmendResult is the replacement trade plus the cancellation of the original.
+type AmendResult struct {
+	Transaction *Transaction
+	Cancel      *CancelResult
+}
+
+// CostBasisPreference is a stored default method. An empty SpiceGradeID is the
+// account-wide default; a grade row overrides it.
+type CostBasisPreference struct {
+	UserID       string
+	SpiceGradeID string
+	Method       string
+	Source       string // GRADE, ACCOUNT or DEFAULT when read back as the effective method
 	UpdatedAt    time.Time
 }
 
-// PositionView extends Position with unrealised P&L computed at read time.
+// PositionView extends Position with unrealised P&L computed at read time. Quantities are in
+// kilograms and prices per kilogram; UnitQty restates TotalQty in the grade's Unit. Amounts are
+// in Currency; Reporting holds them converted into the account's reporting currency.
 type PositionView struct {
 	UserID        string
 	SpiceGradeID  string
-	TotalQty      float64
-	TotalCost     float64
-	AvgCost       float64 // total_cost / total_qty
-	TodayPrice    float64 // from daily_price; 0 if not yet published
-	RealizedPnL   float64
-	UnrealizedPnL float64 // (today_price - avg_cost) × total_qty
+	Currency      string
+	TotalQty      decimal.Decimal
+	TotalCost     decimal.Decimal
+	AvgCost       decimal.Decimal // total_cost / total_qty
+	TodayPrice    decimal.Decimal // valuation price in Currency; 0 if the policy found none
+	RealizedPnL   decimal.Decimal
+	UnrealizedPnL decimal.Decimal // (today_price - avg_cost) × total_qty
 	UpdatedAt     time.Time
+	Valuation     Valuation
+	Reporting     ReportingAmounts
+	Unit          GradeUnit
+	UnitQty       decimal.Decimal // TotalQty in Unit
+}
+
+// GradeUnit is the unit a grade trades in and how many kilograms, the unit every ledger
+// quantity is booked in, one of it holds.
+type GradeUnit struct {
+	Unit      string
+	KgPerUnit decimal.Decimal
+}
+
+// ReportingAmounts are a position's money amounts converted into a reporting currency at
+// FxRate. A zero FxRate means no rate was found and the amounts are left zero.
+type ReportingAmounts struct {
+	Currency      string
+	FxRate        decimal.Decimal
+	TotalCost     decimal.Decimal
+	RealizedPnL   decimal.Decimal
+	UnrealizedPnL decimal.Decimal
 }
 
 // --- Merchant dashboard domain models ---
@@ -65,19 +290,26 @@ const (
 	MaxDashboardDays     = 90
 )
 
+// EnrichedHoldingRow is a held grade in the position's Currency. FxRate converts its amounts
+// into the account's reporting currency; zero when no rate was found.
 type EnrichedHoldingRow struct {
 	SpiceGradeID string
 	ProductName  string
 	GradeName    string
-	TotalQty     float64
-	TotalCost    float64
-	RealizedPnL  float64
-	TodayPrice   float64
+	Currency     string
+	FxRate       decimal.Decimal
+	TotalQty     decimal.Decimal
+	TotalCost    decimal.Decimal
+	RealizedPnL  decimal.Decimal
+	TodayPrice   decimal.Decimal
+	Valuation    Valuation
+	Unit         GradeUnit
+	UnitQty      decimal.Decimal // TotalQty in Unit
 }
 
 type DailyRealizedPnLRow struct {
 	Date             time.Time
-	DailyRealizedPnL float64
+	DailyRealizedPnL decimal.Decimal
 	SpiceGradeID     string
 	ProductName      string
 	GradeName        string
@@ -86,7 +318,7 @@ type DailyRealizedPnLRow struct {
 type DailyActivityRow struct {
 	Date         time.Time
 	Type         string // BUY or SELL
-	Quantity     float64
+	Quantity     decimal.Decimal
 	Count        int
 	SpiceGradeID string
 	ProductName  string
@@ -96,15 +328,355 @@ type DailyActivityRow struct {
 // PeriodTradeStats aggregates buy/sell volume and trade count over a date window.
 type PeriodTradeStats struct {
 	TradesInPeriod     int
-	BuyVolumeInPeriod  float64
-	SellVolumeInPeriod float64
+	BuyVolumeInPeriod  decimal.Decimal
+	SellVolumeInPeriod decimal.Decimal
 }
 
-// PriceSnapshot holds today and previous daily_price for a held grade.
+// PriceSnapshot holds the valuation price of a held grade and the daily_price published
+// before it.
 type PriceSnapshot struct {
 	SpiceGradeID  string
 	ProductName   string
 	GradeName     string
-	TodayPrice    float64
-	PreviousPrice float64
+	Currency      string // the position's; both prices are converted into it
+	TodayPrice    decimal.Decimal
+	PreviousPrice decimal.Decimal
+	Valuation     Valuation
+}
+
+// LedgerLot is a buy lot read for reconciliation, with the status and booking time of its BUY.
+type LedgerLot struct {
+	BuyLot
+	BuyStatus string
+	Currency  string // of the BUY
+	BookedAt  time.Time
+}
+
+// LedgerShortLot is a short lot read for reconciliation, with the booking time of its SELL.
+type LedgerShortLot struct {
+	ShortLot
+	ReversedByTransactionID string
+	BookedAt                time.Time
+}
+
+// LedgerCover is a standing cover of an ACTIVE buy, with the account, grade and booking
+// time of that BUY.
+type LedgerCover struct {
+	ShortCover
+	UserID       string
+	SpiceGradeID string
+	Currency     string
+	BookedAt     time.Time
+}
+
+// PositionDrift compares a stored positions row with the totals replayed from the ledger.
+// Missing is set when the ledger implies a position that has no stored row.
+type PositionDrift struct {
+	UserID       string
+	SpiceGradeID string
+	Stored       Position
+	Expected     Position
+	Missing      bool
+}
+
+// LotDrift compares a lot's stored remaining_qty with its original quantity less active
+// allocations (buy lots) or active covers (short lots, Short set).
+type LotDrift struct {
+	LotID             string
+	Short             bool
+	UserID            string
+	SpiceGradeID      string
+	StoredRemaining   decimal.Decimal
+	ExpectedRemaining decimal.Decimal
+}
+
+// ReconciliationReport is the outcome of replaying the ledger. Rebuilt is set when the
+// drifting rows were overwritten with the replayed values.
+type ReconciliationReport struct {
+	PositionsChecked int
+	LotsChecked      int
+	Positions        []PositionDrift
+	Lots             []LotDrift
+	Rebuilt          bool
+}
+
+// Ageing buckets for open lots, by days since the BUY trade date. A lot whose grade has a
+// shelf life and has reached it is EXPIRED instead of being counted by age.
+const (
+	AgeBucket0To30   = "0-30"
+	AgeBucket31To90  = "31-90"
+	AgeBucket90Plus  = "90+"
+	AgeBucketExpired = "EXPIRED"
+
+	// NearExpiryDays is how close to its shelf life a lot is flagged as ageing stock.
+	NearExpiryDays = 14
+)
+
+// AgeingLotRow is an open lot with the shelf life configured on its grade (0 = not perishable).
+type AgeingLotRow struct {
+	LotID         string
+	SpiceGradeID  string
+	ProductName   string
+	GradeName     string
+	ShelfLifeDays int
+	Currency      string
+	RemainingQty  decimal.Decimal
+	Price         decimal.Decimal
+	TradeDate     time.Time
+}
+
+// AgeingBucket totals the open quantity and its cost in one age band.
+type AgeingBucket struct {
+	Label    string
+	Quantity decimal.Decimal
+	Cost     decimal.Decimal
+	Lots     int
+}
+
+// GradeAgeing is the age profile of one grade's open lots. NextExpiry is the earliest
+// expiry date of a lot not yet expired; zero when nothing is due.
+type GradeAgeing struct {
+	SpiceGradeID   string
+	ProductName    string
+	GradeName      string
+	ShelfLifeDays  int
+	Buckets        []AgeingBucket
+	NearExpiryQty  decimal.Decimal
+	NearExpiryLots int
+	NextExpiry     time.Time
+}
+
+// LotAgeingReport buckets a merchant's open lots by age, in total and per grade.
+type LotAgeingReport struct {
+	AsOf    time.Time
+	Buckets []AgeingBucket
+	Grades  []GradeAgeing
+}
+
+// Realized gains reporting. A LONG gain is a sell drawing on a buy lot; a SHORT gain is a buy
+// covering a short lot. A match held longer than the long-term threshold is LONG_TERM.
+const (
+	GainLong  = "LONG"
+	GainShort = "SHORT"
+
+	HoldingShortTerm = "SHORT_TERM"
+	HoldingLongTerm  = "LONG_TERM"
+
+	DefaultLongTermDays     = 365
+	FinancialYearStartMonth = time.April
+)
+
+// GainsFilter selects a realized gains report: a financial year ("2025-26" runs April 2025 to
+// March 2026) or a date range on the date each gain was realized.
+type GainsFilter struct {
+	UserID        string
+	SpiceGradeID  string
+	FinancialYear string
+	DateFrom      string // YYYY-MM-DD
+	DateTo        string
+	LongTermDays  int // 0 = DefaultLongTermDays
+}
+
+// RealizedGain is one standing sell allocation or short cover. For a SHORT gain the buy is the
+// cover and the lot is the short lot, so AcquiredDate falls after DisposedDate. Proceeds are
+// the gross sale value; Fees are the sell fees charged to it, which a short lot's net price
+// already carries. Cost includes the buy fees.
+type RealizedGain struct {
+	Kind              string
+	MatchID           string
+	SellTransactionID string
+	BuyTransactionID  string
+	LotID             string
+	SpiceGradeID      string
+	ProductName       string
+	GradeName         string
+	Currency          string
+	CostBasisMethod   string
+	Quantity          decimal.Decimal
+	BuyPrice          decimal.Decimal
+	SellPrice         decimal.Decimal
+	CloseAdjustment   decimal.Decimal // see SellAllocation.CloseAdjustment
+	AcquiredDate      time.Time
+	DisposedDate      time.Time
+	RealizedDate      time.Time
+	HoldingDays       int
+	HoldingClass      string
+	Cost              decimal.Decimal
+	Proceeds          decimal.Decimal
+	Fees              decimal.Decimal
+	Gain              decimal.Decimal
+}
+
+// GainsSubtotal sums gains in one currency, for one grade or one holding class, or over the
+// whole report when neither is set.
+type GainsSubtotal struct {
+	SpiceGradeID string
+	ProductName  string
+	GradeName    string
+	HoldingClass string
+	Currency     string
+	Quantity     decimal.Decimal
+	Cost         decimal.Decimal
+	Proceeds     decimal.Decimal
+	Fees         decimal.Decimal
+	Gain         decimal.Decimal
+	Lines        int
+}
+
+// RealizedGainsReport lists a user's realized gains between two dates, oldest first, with
+// subtotals per grade, per holding class and per currency.
+type RealizedGainsReport struct {
+	UserID         string
+	DateFrom       time.Time
+	DateTo         time.Time
+	LongTermDays   int
+	Gains          []*RealizedGain
+	Grades         []*GainsSubtotal
+	HoldingClasses []*GainsSubtotal
+	Totals         []*GainsSubtotal
+}
+
+// Ledger export kinds: which records ExportLedger streams.
+const (
+	ExportTransactions = "TRANSACTIONS"
+	ExportLots         = "LOTS"
+	ExportAllocations  = "ALLOCATIONS"
+	ExportPositions    = "POSITIONS"
+)
+
+// ExportFilter selects the records of a ledger export with the listTransactions filters, but
+// without a page limit. Dates are on the transaction's, lot's or sell's trade date and do not
+// apply to positions. An empty UserID (admin) exports every account.
+type ExportFilter struct {
+	Kind            string
+	UserID          string
+	SpiceGradeID    string
+	ProductID       string
+	Sort            string // DESC (default) | ASC on trade date
+	DateFrom        string // YYYY-MM-DD
+	DateTo          string
+	OpenOnly        bool // LOTS: only lots with remaining quantity
+	IncludeReversed bool // ALLOCATIONS: also allocations undone by a cancellation
+}
+
+// LedgerExportRow is one exported record with its grade's names. Exactly one of Transaction,
+// Lot, Allocation and Position is set.
+type LedgerExportRow struct {
+	ProductName string
+	GradeName   string
+	Transaction *Transaction
+	Lot         *BuyLot
+	Allocation  *AllocationDetail
+	Position    *PositionView
+}
+
+// Price bases for reading daily_price: LAST is the newest tick so far, CLOSE the last tick of
+// a day that has ended.
+const (
+	PriceBasisLast  = "LAST"
+	PriceBasisClose = "CLOSE"
+)
+
+// Valuation policies: which daily_price values a position when today's is not published yet.
+const (
+	ValuationToday         = "TODAY"          // today's price only; none means no valuation
+	ValuationLastAvailable = "LAST_AVAILABLE" // today's price, else the newest within MaxAgeDays
+	ValuationPreviousClose = "PREVIOUS_CLOSE" // the newest close of an ended day within MaxAgeDays
+)
+
+// ValuationPolicy picks the daily price positions are valued at. MaxAgeDays bounds how far
+// back a fallback price may be; 0 means no limit.
+type ValuationPolicy struct {
+	Mode       string
+	MaxAgeDays int
+}
+
+// Valuation records which daily price a position was valued at. Source is LAST for today's
+// running price and CLOSE for an ended day's; Stale is set when the price is older than the
+// policy aims for. A zero Date means no price qualified and the position is not valued.
+type Valuation struct {
+	Date   time.Time
+	Source string
+	Stale  bool
+}
+
+// fxRateScale is the precision of fx_rates.rate, kept when a stored rate is inverted.
+const fxRateScale = 8
+
+// TopicTrades is the event-bus topic for committed transactions; the payload is a *Transaction.
+const TopicTrades = "market.trades"
+
+// Order sides and statuses. An order rests in the book while OPEN or PARTIALLY_FILLED.
+const (
+	OrderSideBuy  = "BUY"
+	OrderSideSell = "SELL"
+
+	OrderOpen            = "OPEN"
+	OrderPartiallyFilled = "PARTIALLY_FILLED"
+	OrderFilled          = "FILLED"
+	OrderCancelled       = "CANCELLED"
+)
+
+// Order is a limit order on one grade. PriorityAt ranks resting orders at the same price;
+// an amendment that raises the quantity or changes the price moves it to the back.
+type Order struct {
+	ID           string
+	UserID       string
+	SpiceGradeID string
+	Side         string
+	Price        decimal.Decimal
+	Currency     string // the account's trading currency; orders only match within one currency
+	Quantity     decimal.Decimal
+	RemainingQty decimal.Decimal
+	Status       string
+	PriorityAt   time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// OrderFill is one match between a BUY and a SELL order, booked as a BUY transaction for
+// the buyer and a SELL transaction for the seller at the resting order's price.
+type OrderFill struct {
+	ID                string
+	SpiceGradeID      string
+	BuyOrderID        string
+	SellOrderID       string
+	BuyTransactionID  string
+	SellTransactionID string
+	Quantity          decimal.Decimal
+	Price             decimal.Decimal
+	CreatedAt         time.Time
+}
+
+// OrderResult is an order after placement or amendment, with the fills that matching produced.
+type OrderResult struct {
+	Order *Order
+	Fills []*OrderFill
+}
+
+// OrderFilter selects orders for ListOrders. Empty fields do not filter; an empty UserID
+// (admin) lists every account.
+type OrderFilter struct {
+	UserID       string
+	SpiceGradeID string
+	Side         string
+	Status       string
+	Skip         uint
+	Take         uint
+}
+
+// OrderBookLevel aggregates the resting quantity at one price.
+type OrderBookLevel struct {
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+	Orders   int
+}
+
+// OrderBook is the resting depth of one grade in one currency: bids best (highest) first,
+// asks best (lowest) first.
+type OrderBook struct {
+	SpiceGradeID string
+	Currency     string
+	Bids         []OrderBookLevel
+	Asks         []OrderBookLevel
 }
@@ -24,17 +24,25 @@ const (
 )
 
 type Transaction struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	SpiceGradeId  string                 `protobuf:"bytes,3,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
-	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
-	Quantity      float64                `protobuf:"fixed64,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Price         float64                `protobuf:"fixed64,6,opt,name=price,proto3" json:"price,omitempty"`
-	TradeDate     string                 `protobuf:"bytes,7,opt,name=trade_date,json=tradeDate,proto3" json:"trade_date,omitempty"` // YYYY-MM-DD
-	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // YYYY-MM-DD HH:MM:SS
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId                string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId          string                 `protobuf:"bytes,3,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	Type                  string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Quantity              string                 `protobuf:"bytes,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price                 string                 `protobuf:"bytes,6,opt,name=price,proto3" json:"price,omitempty"`
+	TradeDate             string                 `protobuf:"bytes,7,opt,name=trade_date,json=tradeDate,proto3" json:"trade_date,omitempty"`                                        // YYYY-MM-DD
+	CreatedAt             string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                                        // YYYY-MM-DD HH:MM:SS
+	CostBasisMethod       string                 `protobuf:"bytes,9,opt,name=cost_basis_method,json=costBasisMethod,proto3" json:"cost_basis_method,omitempty"`                    // SELL only: FIFO | LIFO | WEIGHTED_AVERAGE | SPECIFIC_LOT
+	Status                string                 `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`                                                              // ACTIVE | CANCELLED
+	ReversesTransactionId string                 `protobuf:"bytes,11,opt,name=reverses_transaction_id,json=reversesTransactionId,proto3" json:"reverses_transaction_id,omitempty"` // REVERSAL rows: the cancelled trade
+	AmendsTransactionId   string                 `protobuf:"bytes,12,opt,name=amends_transaction_id,json=amendsTransactionId,proto3" json:"amends_transaction_id,omitempty"`       // replacement trades: the amended trade
+	Note                  string                 `protobuf:"bytes,13,opt,name=note,proto3" json:"note,omitempty"`                                                                  // cancellation / amendment reason
+	IdempotencyKey        string                 `protobuf:"bytes,14,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`                        // client retry key, if one was sent
+	Currency              string                 `protobuf:"bytes,15,opt,name=currency,proto3" json:"currency,omitempty"`                                                          // ISO 4217; the account's trading currency when booked
+	FeeTotal              string                 `protobuf:"bytes,16,opt,name=fee_total,json=feeTotal,proto3" json:"fee_total,omitempty"`                                          // fees and taxes charged, in currency; see ListTransactionFees
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *Transaction) Reset() {
@@ -95,18 +103,18 @@ func (x *Transaction) GetType() string {
 	return ""
 }
 
-func (x *Transaction) GetQuantity() float64 {
+func (x *Transaction) GetQuantity() string {
 	if x != nil {
 		return x.Quantity
 	}
-	return 0
+	return ""
 }
 
-func (x *Transaction) GetPrice() float64 {
+func (x *Transaction) GetPrice() string {
 	if x != nil {
 		return x.Price
 	}
-	return 0
+	return ""
 }
 
 func (x *Transaction) GetTradeDate() string {
@@ -123,35 +131,91 @@ func (x *Transaction) GetCreatedAt() string {
 	return ""
 }
 
-type PositionView struct {
+func (x *Transaction) GetCostBasisMethod() string {
+	if x != nil {
+		return x.CostBasisMethod
+	}
+	return ""
+}
+
+func (x *Transaction) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Transaction) GetReversesTransactionId() string {
+	if x != nil {
+		return x.ReversesTransactionId
+	}
+	return ""
+}
+
+func (x *Transaction) GetAmendsTransactionId() string {
+	if x != nil {
+		return x.AmendsTransactionId
+	}
+	return ""
+}
+
+func (x *Transaction) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *Transaction) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *Transaction) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Transaction) GetFeeTotal() string {
+	if x != nil {
+		return x.FeeTotal
+	}
+	return ""
+}
+
+type TransactionFee struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
-	TotalQty      float64                `protobuf:"fixed64,3,opt,name=total_qty,json=totalQty,proto3" json:"total_qty,omitempty"`
-	TotalCost     float64                `protobuf:"fixed64,4,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
-	AvgCost       float64                `protobuf:"fixed64,5,opt,name=avg_cost,json=avgCost,proto3" json:"avg_cost,omitempty"`
-	TodayPrice    float64                `protobuf:"fixed64,6,opt,name=today_price,json=todayPrice,proto3" json:"today_price,omitempty"`
-	RealizedPnl   float64                `protobuf:"fixed64,7,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
-	UnrealizedPnl float64                `protobuf:"fixed64,8,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TransactionId string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	FeeScheduleId string                 `protobuf:"bytes,3,opt,name=fee_schedule_id,json=feeScheduleId,proto3" json:"fee_schedule_id,omitempty"`
+	Code          string                 `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`   // e.g. COMMISSION, MARKET_CESS, GST
+	Kind          string                 `protobuf:"bytes,5,opt,name=kind,proto3" json:"kind,omitempty"`   // FEE | TAX
+	Basis         string                 `protobuf:"bytes,6,opt,name=basis,proto3" json:"basis,omitempty"` // PERCENT | PER_KG | FLAT
+	Rate          string                 `protobuf:"bytes,7,opt,name=rate,proto3" json:"rate,omitempty"`   // in the schedule's currency for PER_KG and FLAT
+	Amount        string                 `protobuf:"bytes,8,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"` // the trade's currency
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PositionView) Reset() {
-	*x = PositionView{}
+func (x *TransactionFee) Reset() {
+	*x = TransactionFee{}
 	mi := &file_market_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PositionView) String() string {
+func (x *TransactionFee) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PositionView) ProtoMessage() {}
+func (*TransactionFee) ProtoMessage() {}
 
-func (x *PositionView) ProtoReflect() protoreflect.Message {
+func (x *TransactionFee) ProtoReflect() protoreflect.Message {
 	mi := &file_market_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -163,99 +227,96 @@ func (x *PositionView) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PositionView.ProtoReflect.Descriptor instead.
-func (*PositionView) Descriptor() ([]byte, []int) {
+// Deprecated: Use TransactionFee.ProtoReflect.Descriptor instead.
+func (*TransactionFee) Descriptor() ([]byte, []int) {
 	return file_market_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *PositionView) GetUserId() string {
+func (x *TransactionFee) GetId() string {
 	if x != nil {
-		return x.UserId
+		return x.Id
 	}
 	return ""
 }
 
-func (x *PositionView) GetSpiceGradeId() string {
+func (x *TransactionFee) GetTransactionId() string {
 	if x != nil {
-		return x.SpiceGradeId
+		return x.TransactionId
 	}
 	return ""
 }
 
-func (x *PositionView) GetTotalQty() float64 {
+func (x *TransactionFee) GetFeeScheduleId() string {
 	if x != nil {
-		return x.TotalQty
+		return x.FeeScheduleId
 	}
-	return 0
+	return ""
 }
 
-func (x *PositionView) GetTotalCost() float64 {
+func (x *TransactionFee) GetCode() string {
 	if x != nil {
-		return x.TotalCost
+		return x.Code
 	}
-	return 0
+	return ""
 }
 
-func (x *PositionView) GetAvgCost() float64 {
+func (x *TransactionFee) GetKind() string {
 	if x != nil {
-		return x.AvgCost
+		return x.Kind
 	}
-	return 0
+	return ""
 }
 
-func (x *PositionView) GetTodayPrice() float64 {
+func (x *TransactionFee) GetBasis() string {
 	if x != nil {
-		return x.TodayPrice
+		return x.Basis
 	}
-	return 0
+	return ""
 }
 
-func (x *PositionView) GetRealizedPnl() float64 {
+func (x *TransactionFee) GetRate() string {
 	if x != nil {
-		return x.RealizedPnl
+		return x.Rate
 	}
-	return 0
+	return ""
 }
 
-func (x *PositionView) GetUnrealizedPnl() float64 {
+func (x *TransactionFee) GetAmount() string {
 	if x != nil {
-		return x.UnrealizedPnl
+		return x.Amount
 	}
-	return 0
+	return ""
 }
 
-func (x *PositionView) GetUpdatedAt() string {
+func (x *TransactionFee) GetCurrency() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Currency
 	}
 	return ""
 }
 
-type BuyRequest struct {
+type ListTransactionFeesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
-	Quantity      float64                `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	TradeDate     string                 `protobuf:"bytes,5,opt,name=trade_date,json=tradeDate,proto3" json:"trade_date,omitempty"` // YYYY-MM-DD
+	TransactionId string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BuyRequest) Reset() {
-	*x = BuyRequest{}
+func (x *ListTransactionFeesRequest) Reset() {
+	*x = ListTransactionFeesRequest{}
 	mi := &file_market_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BuyRequest) String() string {
+func (x *ListTransactionFeesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BuyRequest) ProtoMessage() {}
+func (*ListTransactionFeesRequest) ProtoMessage() {}
 
-func (x *BuyRequest) ProtoReflect() protoreflect.Message {
+func (x *ListTransactionFeesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_market_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -267,67 +328,46 @@ func (x *BuyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BuyRequest.ProtoReflect.Descriptor instead.
-func (*BuyRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListTransactionFeesRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionFeesRequest) Descriptor() ([]byte, []int) {
 	return file_market_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *BuyRequest) GetUserId() string {
+func (x *ListTransactionFeesRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *BuyRequest) GetSpiceGradeId() string {
-	if x != nil {
-		return x.SpiceGradeId
-	}
-	return ""
-}
-
-func (x *BuyRequest) GetQuantity() float64 {
-	if x != nil {
-		return x.Quantity
-	}
-	return 0
-}
-
-func (x *BuyRequest) GetPrice() float64 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
-}
-
-func (x *BuyRequest) GetTradeDate() string {
+func (x *ListTransactionFeesRequest) GetTransactionId() string {
 	if x != nil {
-		return x.TradeDate
+		return x.TransactionId
 	}
 	return ""
 }
 
-type BuyResponse struct {
+type ListTransactionFeesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Transaction   *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	Fees          []*TransactionFee      `protobuf:"bytes,1,rep,name=fees,proto3" json:"fees,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BuyResponse) Reset() {
-	*x = BuyResponse{}
+func (x *ListTransactionFeesResponse) Reset() {
+	*x = ListTransactionFeesResponse{}
 	mi := &file_market_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BuyResponse) String() string {
+func (x *ListTransactionFeesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BuyResponse) ProtoMessage() {}
+func (*ListTransactionFeesResponse) ProtoMessage() {}
 
-func (x *BuyResponse) ProtoReflect() protoreflect.Message {
+func (x *ListTransactionFeesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_market_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -339,43 +379,65 @@ func (x *BuyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BuyResponse.ProtoReflect.Descriptor instead.
-func (*BuyResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListTransactionFeesResponse.ProtoReflect.Descriptor instead.
+func (*ListTransactionFeesResponse) Descriptor() ([]byte, []int) {
 	return file_market_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *BuyResponse) GetTransaction() *Transaction {
+func (x *ListTransactionFeesResponse) GetFees() []*TransactionFee {
 	if x != nil {
-		return x.Transaction
+		return x.Fees
 	}
 	return nil
 }
 
-type SellRequest struct {
+type PositionView struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
-	Quantity      float64                `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	TradeDate     string                 `protobuf:"bytes,5,opt,name=trade_date,json=tradeDate,proto3" json:"trade_date,omitempty"` // YYYY-MM-DD
+	TotalQty      string                 `protobuf:"bytes,3,opt,name=total_qty,json=totalQty,proto3" json:"total_qty,omitempty"`
+	TotalCost     string                 `protobuf:"bytes,4,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	AvgCost       string                 `protobuf:"bytes,5,opt,name=avg_cost,json=avgCost,proto3" json:"avg_cost,omitempty"`
+	TodayPrice    string                 `protobuf:"bytes,6,opt,name=today_price,json=todayPrice,proto3" json:"today_price,omitempty"`
+	RealizedPnl   string                 `protobuf:"bytes,7,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	UnrealizedPnl string                 `protobuf:"bytes,8,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Date (YYYY-MM-DD) and basis (LAST or CLOSE) of the price valued at; empty when none qualified.
+	PriceDate   string `protobuf:"bytes,10,opt,name=price_date,json=priceDate,proto3" json:"price_date,omitempty"`
+	PriceSource string `protobuf:"bytes,11,opt,name=price_source,json=priceSource,proto3" json:"price_source,omitempty"`
+	// True when the price is older than the valuation policy aims for.
+	Stale    bool   `protobuf:"varint,12,opt,name=stale,proto3" json:"stale,omitempty"`
+	Currency string `protobuf:"bytes,13,opt,name=currency,proto3" json:"currency,omitempty"` // the position's; amounts above are in it
+	// Amounts converted into the account's reporting currency at fx_rate; fx_rate is empty
+	// and the reporting amounts are left out when no rate is stored.
+	ReportingCurrency      string `protobuf:"bytes,14,opt,name=reporting_currency,json=reportingCurrency,proto3" json:"reporting_currency,omitempty"`
+	FxRate                 string `protobuf:"bytes,15,opt,name=fx_rate,json=fxRate,proto3" json:"fx_rate,omitempty"`
+	ReportingTotalCost     string `protobuf:"bytes,16,opt,name=reporting_total_cost,json=reportingTotalCost,proto3" json:"reporting_total_cost,omitempty"`
+	ReportingRealizedPnl   string `protobuf:"bytes,17,opt,name=reporting_realized_pnl,json=reportingRealizedPnl,proto3" json:"reporting_realized_pnl,omitempty"`
+	ReportingUnrealizedPnl string `protobuf:"bytes,18,opt,name=reporting_unrealized_pnl,json=reportingUnrealizedPnl,proto3" json:"reporting_unrealized_pnl,omitempty"`
+	// Quantities above are in kilograms and prices per kilogram. The grade trades in unit, of
+	// kg_per_unit kilograms each; unit_qty is total_qty in it.
+	Unit          string `protobuf:"bytes,19,opt,name=unit,proto3" json:"unit,omitempty"`
+	KgPerUnit     string `protobuf:"bytes,20,opt,name=kg_per_unit,json=kgPerUnit,proto3" json:"kg_per_unit,omitempty"`
+	UnitQty       string `protobuf:"bytes,21,opt,name=unit_qty,json=unitQty,proto3" json:"unit_qty,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SellRequest) Reset() {
-	*x = SellRequest{}
+func (x *PositionView) Reset() {
+	*x = PositionView{}
 	mi := &file_market_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SellRequest) String() string {
+func (x *PositionView) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SellRequest) ProtoMessage() {}
+func (*PositionView) ProtoMessage() {}
 
-func (x *SellRequest) ProtoReflect() protoreflect.Message {
+func (x *PositionView) ProtoReflect() protoreflect.Message {
 	mi := &file_market_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -387,90 +449,4522 @@ func (x *SellRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SellRequest.ProtoReflect.Descriptor instead.
-func (*SellRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PositionView.ProtoReflect.Descriptor instead.
+func (*PositionView) Descriptor() ([]byte, []int) {
 	return file_market_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *SellRequest) GetUserId() string {
+func (x *PositionView) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *SellRequest) GetSpiceGradeId() string {
+func (x *PositionView) GetSpiceGradeId() string {
 	if x != nil {
 		return x.SpiceGradeId
 	}
 	return ""
 }
 
-func (x *SellRequest) GetQuantity() float64 {
+func (x *PositionView) GetTotalQty() string {
 	if x != nil {
-		return x.Quantity
+		return x.TotalQty
 	}
-	return 0
+	return ""
 }
 
-func (x *SellRequest) GetPrice() float64 {
+func (x *PositionView) GetTotalCost() string {
 	if x != nil {
-		return x.Price
+		return x.TotalCost
 	}
-	return 0
+	return ""
 }
 
-func (x *SellRequest) GetTradeDate() string {
+func (x *PositionView) GetAvgCost() string {
 	if x != nil {
-		return x.TradeDate
+		return x.AvgCost
 	}
 	return ""
 }
 
-type SellResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Transaction   *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *PositionView) GetTodayPrice() string {
+	if x != nil {
+		return x.TodayPrice
+	}
+	return ""
 }
 
-func (x *SellResponse) Reset() {
-	*x = SellResponse{}
-	mi := &file_market_proto_msgTypes[5]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *PositionView) GetRealizedPnl() string {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return ""
 }
 
-func (x *SellResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *PositionView) GetUnrealizedPnl() string {
+	if x != nil {
+		return x.UnrealizedPnl
+	}
+	return ""
 }
 
-func (*SellResponse) ProtoMessage() {}
+func (x *PositionView) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
 
-func (x *SellResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[5]
+func (x *PositionView) GetPriceDate() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.PriceDate
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use SellResponse.ProtoReflect.Descriptor instead.
-func (*SellResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{5}
+func (x *PositionView) GetPriceSource() string {
+	if x != nil {
+		return x.PriceSource
+	}
+	return ""
+}
+
+func (x *PositionView) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *PositionView) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PositionView) GetReportingCurrency() string {
+	if x != nil {
+		return x.ReportingCurrency
+	}
+	return ""
+}
+
+func (x *PositionView) GetFxRate() string {
+	if x != nil {
+		return x.FxRate
+	}
+	return ""
+}
+
+func (x *PositionView) GetReportingTotalCost() string {
+	if x != nil {
+		return x.ReportingTotalCost
+	}
+	return ""
+}
+
+func (x *PositionView) GetReportingRealizedPnl() string {
+	if x != nil {
+		return x.ReportingRealizedPnl
+	}
+	return ""
+}
+
+func (x *PositionView) GetReportingUnrealizedPnl() string {
+	if x != nil {
+		return x.ReportingUnrealizedPnl
+	}
+	return ""
+}
+
+func (x *PositionView) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *PositionView) GetKgPerUnit() string {
+	if x != nil {
+		return x.KgPerUnit
+	}
+	return ""
+}
+
+func (x *PositionView) GetUnitQty() string {
+	if x != nil {
+		return x.UnitQty
+	}
+	return ""
+}
+
+type BuyRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId   string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	Quantity       string                 `protobuf:"bytes,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price          string                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	TradeDate      string                 `protobuf:"bytes,5,opt,name=trade_date,json=tradeDate,proto3" json:"trade_date,omitempty"`                // YYYY-MM-DD
+	IdempotencyKey string                 `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // optional; a repeat returns the original transaction
+	Unit           string                 `protobuf:"bytes,7,opt,name=unit,proto3" json:"unit,omitempty"`                                           // optional; unit of quantity and price: KG (default), QUINTAL, TONNE or BAG
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BuyRequest) Reset() {
+	*x = BuyRequest{}
+	mi := &file_market_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuyRequest) ProtoMessage() {}
+
+func (x *BuyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuyRequest.ProtoReflect.Descriptor instead.
+func (*BuyRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BuyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *BuyRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *BuyRequest) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *BuyRequest) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *BuyRequest) GetTradeDate() string {
+	if x != nil {
+		return x.TradeDate
+	}
+	return ""
+}
+
+func (x *BuyRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *BuyRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type BuyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transaction   *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuyResponse) Reset() {
+	*x = BuyResponse{}
+	mi := &file_market_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuyResponse) ProtoMessage() {}
+
+func (x *BuyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuyResponse.ProtoReflect.Descriptor instead.
+func (*BuyResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BuyResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type LotSelection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         string                 `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	Quantity      string                 `protobuf:"bytes,2,opt,name=quantity,proto3" json:"quantity,omitempty"` // empty or "0" = as much of the lot as the sell still needs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LotSelection) Reset() {
+	*x = LotSelection{}
+	mi := &file_market_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LotSelection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LotSelection) ProtoMessage() {}
+
+func (x *LotSelection) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LotSelection.ProtoReflect.Descriptor instead.
+func (*LotSelection) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LotSelection) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *LotSelection) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+type SellRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId    string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	Quantity        string                 `protobuf:"bytes,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price           string                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	TradeDate       string                 `protobuf:"bytes,5,opt,name=trade_date,json=tradeDate,proto3" json:"trade_date,omitempty"`                     // YYYY-MM-DD
+	CostBasisMethod string                 `protobuf:"bytes,6,opt,name=cost_basis_method,json=costBasisMethod,proto3" json:"cost_basis_method,omitempty"` // optional; falls back to grade, then account preference, then FIFO
+	Lots            []*LotSelection        `protobuf:"bytes,7,rep,name=lots,proto3" json:"lots,omitempty"`                                                // required for SPECIFIC_LOT, in consumption order
+	IdempotencyKey  string                 `protobuf:"bytes,8,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`      // optional; a repeat returns the original transaction
+	Unit            string                 `protobuf:"bytes,9,opt,name=unit,proto3" json:"unit,omitempty"`                                                // optional; unit of quantity, price and lot quantities: KG (default), QUINTAL, TONNE or BAG
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SellRequest) Reset() {
+	*x = SellRequest{}
+	mi := &file_market_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SellRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SellRequest) ProtoMessage() {}
+
+func (x *SellRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SellRequest.ProtoReflect.Descriptor instead.
+func (*SellRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SellRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SellRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *SellRequest) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *SellRequest) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *SellRequest) GetTradeDate() string {
+	if x != nil {
+		return x.TradeDate
+	}
+	return ""
+}
+
+func (x *SellRequest) GetCostBasisMethod() string {
+	if x != nil {
+		return x.CostBasisMethod
+	}
+	return ""
+}
+
+func (x *SellRequest) GetLots() []*LotSelection {
+	if x != nil {
+		return x.Lots
+	}
+	return nil
+}
+
+func (x *SellRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *SellRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type SellResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transaction   *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SellResponse) Reset() {
+	*x = SellResponse{}
+	mi := &file_market_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SellResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SellResponse) ProtoMessage() {}
+
+func (x *SellResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SellResponse.ProtoReflect.Descriptor instead.
+func (*SellResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SellResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type CancelTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // ignored for admins, who may cancel any account's trade
+	TransactionId string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Reallocate    bool                   `protobuf:"varint,4,opt,name=reallocate,proto3" json:"reallocate,omitempty"` // BUY only: re-match later sells that used this lot
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelTransactionRequest) Reset() {
+	*x = CancelTransactionRequest{}
+	mi := &file_market_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTransactionRequest) ProtoMessage() {}
+
+func (x *CancelTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTransactionRequest.ProtoReflect.Descriptor instead.
+func (*CancelTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CancelTransactionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CancelTransactionRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *CancelTransactionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CancelTransactionRequest) GetReallocate() bool {
+	if x != nil {
+		return x.Reallocate
+	}
+	return false
+}
+
+type CancelTransactionResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Original           *Transaction           `protobuf:"bytes,1,opt,name=original,proto3" json:"original,omitempty"` // now CANCELLED
+	Reversal           *Transaction           `protobuf:"bytes,2,opt,name=reversal,proto3" json:"reversal,omitempty"` // compensating REVERSAL row
+	ReallocatedSellIds []string               `protobuf:"bytes,3,rep,name=reallocated_sell_ids,json=reallocatedSellIds,proto3" json:"reallocated_sell_ids,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CancelTransactionResponse) Reset() {
+	*x = CancelTransactionResponse{}
+	mi := &file_market_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTransactionResponse) ProtoMessage() {}
+
+func (x *CancelTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTransactionResponse.ProtoReflect.Descriptor instead.
+func (*CancelTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CancelTransactionResponse) GetOriginal() *Transaction {
+	if x != nil {
+		return x.Original
+	}
+	return nil
+}
+
+func (x *CancelTransactionResponse) GetReversal() *Transaction {
+	if x != nil {
+		return x.Reversal
+	}
+	return nil
+}
+
+func (x *CancelTransactionResponse) GetReallocatedSellIds() []string {
+	if x != nil {
+		return x.ReallocatedSellIds
+	}
+	return nil
+}
+
+type AmendTransactionRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // ignored for admins
+	TransactionId   string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Quantity        string                 `protobuf:"bytes,3,opt,name=quantity,proto3" json:"quantity,omitempty"`                    // empty = keep original
+	Price           string                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`                          // empty = keep original
+	TradeDate       string                 `protobuf:"bytes,5,opt,name=trade_date,json=tradeDate,proto3" json:"trade_date,omitempty"` // YYYY-MM-DD; empty = keep original
+	Reason          string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Reallocate      bool                   `protobuf:"varint,7,opt,name=reallocate,proto3" json:"reallocate,omitempty"`
+	CostBasisMethod string                 `protobuf:"bytes,8,opt,name=cost_basis_method,json=costBasisMethod,proto3" json:"cost_basis_method,omitempty"` // SELL only; empty = original method
+	Lots            []*LotSelection        `protobuf:"bytes,9,rep,name=lots,proto3" json:"lots,omitempty"`                                                // SELL only, for SPECIFIC_LOT
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AmendTransactionRequest) Reset() {
+	*x = AmendTransactionRequest{}
+	mi := &file_market_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AmendTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmendTransactionRequest) ProtoMessage() {}
+
+func (x *AmendTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmendTransactionRequest.ProtoReflect.Descriptor instead.
+func (*AmendTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AmendTransactionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AmendTransactionRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *AmendTransactionRequest) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *AmendTransactionRequest) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *AmendTransactionRequest) GetTradeDate() string {
+	if x != nil {
+		return x.TradeDate
+	}
+	return ""
+}
+
+func (x *AmendTransactionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AmendTransactionRequest) GetReallocate() bool {
+	if x != nil {
+		return x.Reallocate
+	}
+	return false
+}
+
+func (x *AmendTransactionRequest) GetCostBasisMethod() string {
+	if x != nil {
+		return x.CostBasisMethod
+	}
+	return ""
+}
+
+func (x *AmendTransactionRequest) GetLots() []*LotSelection {
+	if x != nil {
+		return x.Lots
+	}
+	return nil
+}
+
+type AmendTransactionResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Transaction        *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"` // replacement trade
+	Original           *Transaction           `protobuf:"bytes,2,opt,name=original,proto3" json:"original,omitempty"`
+	Reversal           *Transaction           `protobuf:"bytes,3,opt,name=reversal,proto3" json:"reversal,omitempty"`
+	ReallocatedSellIds []string               `protobuf:"bytes,4,rep,name=reallocated_sell_ids,json=reallocatedSellIds,proto3" json:"reallocated_sell_ids,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *AmendTransactionResponse) Reset() {
+	*x = AmendTransactionResponse{}
+	mi := &file_market_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AmendTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmendTransactionResponse) ProtoMessage() {}
+
+func (x *AmendTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmendTransactionResponse.ProtoReflect.Descriptor instead.
+func (*AmendTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AmendTransactionResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *AmendTransactionResponse) GetOriginal() *Transaction {
+	if x != nil {
+		return x.Original
+	}
+	return nil
+}
+
+func (x *AmendTransactionResponse) GetReversal() *Transaction {
+	if x != nil {
+		return x.Reversal
+	}
+	return nil
+}
+
+func (x *AmendTransactionResponse) GetReallocatedSellIds() []string {
+	if x != nil {
+		return x.ReallocatedSellIds
+	}
+	return nil
+}
+
+type PositionTotals struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalQty      string                 `protobuf:"bytes,1,opt,name=total_qty,json=totalQty,proto3" json:"total_qty,omitempty"`
+	TotalCost     string                 `protobuf:"bytes,2,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	RealizedPnl   string                 `protobuf:"bytes,3,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PositionTotals) Reset() {
+	*x = PositionTotals{}
+	mi := &file_market_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PositionTotals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PositionTotals) ProtoMessage() {}
+
+func (x *PositionTotals) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PositionTotals.ProtoReflect.Descriptor instead.
+func (*PositionTotals) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PositionTotals) GetTotalQty() string {
+	if x != nil {
+		return x.TotalQty
+	}
+	return ""
+}
+
+func (x *PositionTotals) GetTotalCost() string {
+	if x != nil {
+		return x.TotalCost
+	}
+	return ""
+}
+
+func (x *PositionTotals) GetRealizedPnl() string {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return ""
+}
+
+func (x *PositionTotals) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type PositionDrift struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	Stored        *PositionTotals        `protobuf:"bytes,3,opt,name=stored,proto3" json:"stored,omitempty"`
+	Expected      *PositionTotals        `protobuf:"bytes,4,opt,name=expected,proto3" json:"expected,omitempty"` // replayed from transactions, lots, allocations and short covers
+	Missing       bool                   `protobuf:"varint,5,opt,name=missing,proto3" json:"missing,omitempty"`  // no positions row exists for a non-empty ledger
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PositionDrift) Reset() {
+	*x = PositionDrift{}
+	mi := &file_market_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PositionDrift) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PositionDrift) ProtoMessage() {}
+
+func (x *PositionDrift) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PositionDrift.ProtoReflect.Descriptor instead.
+func (*PositionDrift) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PositionDrift) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PositionDrift) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *PositionDrift) GetStored() *PositionTotals {
+	if x != nil {
+		return x.Stored
+	}
+	return nil
+}
+
+func (x *PositionDrift) GetExpected() *PositionTotals {
+	if x != nil {
+		return x.Expected
+	}
+	return nil
+}
+
+func (x *PositionDrift) GetMissing() bool {
+	if x != nil {
+		return x.Missing
+	}
+	return false
+}
+
+type LotDrift struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	LotId             string                 `protobuf:"bytes,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	UserId            string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId      string                 `protobuf:"bytes,3,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	StoredRemaining   string                 `protobuf:"bytes,4,opt,name=stored_remaining,json=storedRemaining,proto3" json:"stored_remaining,omitempty"`
+	ExpectedRemaining string                 `protobuf:"bytes,5,opt,name=expected_remaining,json=expectedRemaining,proto3" json:"expected_remaining,omitempty"`
+	Short             bool                   `protobuf:"varint,6,opt,name=short,proto3" json:"short,omitempty"` // lot_id is a short lot, checked against its covers
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LotDrift) Reset() {
+	*x = LotDrift{}
+	mi := &file_market_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LotDrift) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LotDrift) ProtoMessage() {}
+
+func (x *LotDrift) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LotDrift.ProtoReflect.Descriptor instead.
+func (*LotDrift) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LotDrift) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *LotDrift) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *LotDrift) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *LotDrift) GetStoredRemaining() string {
+	if x != nil {
+		return x.StoredRemaining
+	}
+	return ""
+}
+
+func (x *LotDrift) GetExpectedRemaining() string {
+	if x != nil {
+		return x.ExpectedRemaining
+	}
+	return ""
+}
+
+func (x *LotDrift) GetShort() bool {
+	if x != nil {
+		return x.Short
+	}
+	return false
+}
+
+type ReconcileLedgerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                     // optional; empty = every account
+	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"` // optional; empty = every grade
+	Rebuild       bool                   `protobuf:"varint,3,opt,name=rebuild,proto3" json:"rebuild,omitempty"`                                // overwrite drifting positions and lot remainders in one DB transaction
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReconcileLedgerRequest) Reset() {
+	*x = ReconcileLedgerRequest{}
+	mi := &file_market_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconcileLedgerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileLedgerRequest) ProtoMessage() {}
+
+func (x *ReconcileLedgerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileLedgerRequest.ProtoReflect.Descriptor instead.
+func (*ReconcileLedgerRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReconcileLedgerRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReconcileLedgerRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *ReconcileLedgerRequest) GetRebuild() bool {
+	if x != nil {
+		return x.Rebuild
+	}
+	return false
+}
+
+type ReconcileLedgerResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PositionsChecked uint32                 `protobuf:"varint,1,opt,name=positions_checked,json=positionsChecked,proto3" json:"positions_checked,omitempty"`
+	LotsChecked      uint32                 `protobuf:"varint,2,opt,name=lots_checked,json=lotsChecked,proto3" json:"lots_checked,omitempty"`
+	Positions        []*PositionDrift       `protobuf:"bytes,3,rep,name=positions,proto3" json:"positions,omitempty"`
+	Lots             []*LotDrift            `protobuf:"bytes,4,rep,name=lots,proto3" json:"lots,omitempty"`
+	Rebuilt          bool                   `protobuf:"varint,5,opt,name=rebuilt,proto3" json:"rebuilt,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ReconcileLedgerResponse) Reset() {
+	*x = ReconcileLedgerResponse{}
+	mi := &file_market_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconcileLedgerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileLedgerResponse) ProtoMessage() {}
+
+func (x *ReconcileLedgerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileLedgerResponse.ProtoReflect.Descriptor instead.
+func (*ReconcileLedgerResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ReconcileLedgerResponse) GetPositionsChecked() uint32 {
+	if x != nil {
+		return x.PositionsChecked
+	}
+	return 0
+}
+
+func (x *ReconcileLedgerResponse) GetLotsChecked() uint32 {
+	if x != nil {
+		return x.LotsChecked
+	}
+	return 0
+}
+
+func (x *ReconcileLedgerResponse) GetPositions() []*PositionDrift {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+func (x *ReconcileLedgerResponse) GetLots() []*LotDrift {
+	if x != nil {
+		return x.Lots
+	}
+	return nil
+}
+
+func (x *ReconcileLedgerResponse) GetRebuilt() bool {
+	if x != nil {
+		return x.Rebuilt
+	}
+	return false
+}
+
+type BuyLot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TransactionId string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // the BUY that created the lot
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId  string                 `protobuf:"bytes,4,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	OriginalQty   string                 `protobuf:"bytes,5,opt,name=original_qty,json=originalQty,proto3" json:"original_qty,omitempty"`
+	RemainingQty  string                 `protobuf:"bytes,6,opt,name=remaining_qty,json=remainingQty,proto3" json:"remaining_qty,omitempty"`
+	Price         string                 `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
+	TradeDate     string                 `protobuf:"bytes,8,opt,name=trade_date,json=tradeDate,proto3" json:"trade_date,omitempty"` // YYYY-MM-DD
+	CreatedAt     string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // YYYY-MM-DD HH:MM:SS
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuyLot) Reset() {
+	*x = BuyLot{}
+	mi := &file_market_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuyLot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuyLot) ProtoMessage() {}
+
+func (x *BuyLot) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuyLot.ProtoReflect.Descriptor instead.
+func (*BuyLot) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *BuyLot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BuyLot) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *BuyLot) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *BuyLot) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *BuyLot) GetOriginalQty() string {
+	if x != nil {
+		return x.OriginalQty
+	}
+	return ""
+}
+
+func (x *BuyLot) GetRemainingQty() string {
+	if x != nil {
+		return x.RemainingQty
+	}
+	return ""
+}
+
+func (x *BuyLot) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *BuyLot) GetTradeDate() string {
+	if x != nil {
+		return x.TradeDate
+	}
+	return ""
+}
+
+func (x *BuyLot) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type SellAllocation struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Id                      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SellTransactionId       string                 `protobuf:"bytes,2,opt,name=sell_transaction_id,json=sellTransactionId,proto3" json:"sell_transaction_id,omitempty"`
+	BuyLotId                string                 `protobuf:"bytes,3,opt,name=buy_lot_id,json=buyLotId,proto3" json:"buy_lot_id,omitempty"`
+	UserId                  string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId            string                 `protobuf:"bytes,5,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	Quantity                string                 `protobuf:"bytes,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	BuyPrice                string                 `protobuf:"bytes,7,opt,name=buy_price,json=buyPrice,proto3" json:"buy_price,omitempty"`
+	SellPrice               string                 `protobuf:"bytes,8,opt,name=sell_price,json=sellPrice,proto3" json:"sell_price,omitempty"`
+	RealizedPnl             string                 `protobuf:"bytes,9,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	CostBasisMethod         string                 `protobuf:"bytes,10,opt,name=cost_basis_method,json=costBasisMethod,proto3" json:"cost_basis_method,omitempty"`
+	SellTradeDate           string                 `protobuf:"bytes,11,opt,name=sell_trade_date,json=sellTradeDate,proto3" json:"sell_trade_date,omitempty"`                                 // YYYY-MM-DD
+	ReversedByTransactionId string                 `protobuf:"bytes,12,opt,name=reversed_by_transaction_id,json=reversedByTransactionId,proto3" json:"reversed_by_transaction_id,omitempty"` // set once a cancellation undid the allocation
+	CreatedAt               string                 `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                                               // YYYY-MM-DD HH:MM:SS
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *SellAllocation) Reset() {
+	*x = SellAllocation{}
+	mi := &file_market_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SellAllocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SellAllocation) ProtoMessage() {}
+
+func (x *SellAllocation) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SellAllocation.ProtoReflect.Descriptor instead.
+func (*SellAllocation) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SellAllocation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetSellTransactionId() string {
+	if x != nil {
+		return x.SellTransactionId
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetBuyLotId() string {
+	if x != nil {
+		return x.BuyLotId
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetBuyPrice() string {
+	if x != nil {
+		return x.BuyPrice
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetSellPrice() string {
+	if x != nil {
+		return x.SellPrice
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetRealizedPnl() string {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetCostBasisMethod() string {
+	if x != nil {
+		return x.CostBasisMethod
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetSellTradeDate() string {
+	if x != nil {
+		return x.SellTradeDate
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetReversedByTransactionId() string {
+	if x != nil {
+		return x.ReversedByTransactionId
+	}
+	return ""
+}
+
+func (x *SellAllocation) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListOpenLotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"` // optional
+	Skip          uint32                 `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint32                 `protobuf:"varint,4,opt,name=take,proto3" json:"take,omitempty"`
+	Sort          string                 `protobuf:"bytes,5,opt,name=sort,proto3" json:"sort,omitempty"`                         // ASC | DESC (default ASC, consumption order)
+	DateFrom      string                 `protobuf:"bytes,6,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"` // YYYY-MM-DD optional, on the BUY trade date
+	DateTo        string                 `protobuf:"bytes,7,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`       // YYYY-MM-DD optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOpenLotsRequest) Reset() {
+	*x = ListOpenLotsRequest{}
+	mi := &file_market_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOpenLotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOpenLotsRequest) ProtoMessage() {}
+
+func (x *ListOpenLotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOpenLotsRequest.ProtoReflect.Descriptor instead.
+func (*ListOpenLotsRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListOpenLotsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListOpenLotsRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *ListOpenLotsRequest) GetSkip() uint32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *ListOpenLotsRequest) GetTake() uint32 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+func (x *ListOpenLotsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListOpenLotsRequest) GetDateFrom() string {
+	if x != nil {
+		return x.DateFrom
+	}
+	return ""
+}
+
+func (x *ListOpenLotsRequest) GetDateTo() string {
+	if x != nil {
+		return x.DateTo
+	}
+	return ""
+}
+
+type ListOpenLotsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lots          []*BuyLot              `protobuf:"bytes,1,rep,name=lots,proto3" json:"lots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOpenLotsResponse) Reset() {
+	*x = ListOpenLotsResponse{}
+	mi := &file_market_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOpenLotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOpenLotsResponse) ProtoMessage() {}
+
+func (x *ListOpenLotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOpenLotsResponse.ProtoReflect.Descriptor instead.
+func (*ListOpenLotsResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListOpenLotsResponse) GetLots() []*BuyLot {
+	if x != nil {
+		return x.Lots
+	}
+	return nil
+}
+
+type GetLotHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	LotId         string                 `protobuf:"bytes,2,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	Skip          uint32                 `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"` // paginates the allocations
+	Take          uint32                 `protobuf:"varint,4,opt,name=take,proto3" json:"take,omitempty"`
+	DateFrom      string                 `protobuf:"bytes,5,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"` // YYYY-MM-DD optional, on the SELL trade date
+	DateTo        string                 `protobuf:"bytes,6,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`       // YYYY-MM-DD optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLotHistoryRequest) Reset() {
+	*x = GetLotHistoryRequest{}
+	mi := &file_market_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLotHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLotHistoryRequest) ProtoMessage() {}
+
+func (x *GetLotHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLotHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetLotHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetLotHistoryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetLotHistoryRequest) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *GetLotHistoryRequest) GetSkip() uint32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetLotHistoryRequest) GetTake() uint32 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+func (x *GetLotHistoryRequest) GetDateFrom() string {
+	if x != nil {
+		return x.DateFrom
+	}
+	return ""
+}
+
+func (x *GetLotHistoryRequest) GetDateTo() string {
+	if x != nil {
+		return x.DateTo
+	}
+	return ""
+}
+
+type GetLotHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lot           *BuyLot                `protobuf:"bytes,1,opt,name=lot,proto3" json:"lot,omitempty"`
+	Allocations   []*SellAllocation      `protobuf:"bytes,2,rep,name=allocations,proto3" json:"allocations,omitempty"` // reversed allocations included
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLotHistoryResponse) Reset() {
+	*x = GetLotHistoryResponse{}
+	mi := &file_market_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLotHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLotHistoryResponse) ProtoMessage() {}
+
+func (x *GetLotHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLotHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetLotHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetLotHistoryResponse) GetLot() *BuyLot {
+	if x != nil {
+		return x.Lot
+	}
+	return nil
+}
+
+func (x *GetLotHistoryResponse) GetAllocations() []*SellAllocation {
+	if x != nil {
+		return x.Allocations
+	}
+	return nil
+}
+
+type GetSellAllocationsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UserId            string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SellTransactionId string                 `protobuf:"bytes,2,opt,name=sell_transaction_id,json=sellTransactionId,proto3" json:"sell_transaction_id,omitempty"` // optional; one sell's matching
+	SpiceGradeId      string                 `protobuf:"bytes,3,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`                // optional
+	Skip              uint32                 `protobuf:"varint,4,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take              uint32                 `protobuf:"varint,5,opt,name=take,proto3" json:"take,omitempty"`
+	DateFrom          string                 `protobuf:"bytes,6,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"` // YYYY-MM-DD optional, on the SELL trade date
+	DateTo            string                 `protobuf:"bytes,7,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`       // YYYY-MM-DD optional
+	IncludeReversed   bool                   `protobuf:"varint,8,opt,name=include_reversed,json=includeReversed,proto3" json:"include_reversed,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetSellAllocationsRequest) Reset() {
+	*x = GetSellAllocationsRequest{}
+	mi := &file_market_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSellAllocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSellAllocationsRequest) ProtoMessage() {}
+
+func (x *GetSellAllocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSellAllocationsRequest.ProtoReflect.Descriptor instead.
+func (*GetSellAllocationsRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetSellAllocationsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetSellAllocationsRequest) GetSellTransactionId() string {
+	if x != nil {
+		return x.SellTransactionId
+	}
+	return ""
+}
+
+func (x *GetSellAllocationsRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *GetSellAllocationsRequest) GetSkip() uint32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetSellAllocationsRequest) GetTake() uint32 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+func (x *GetSellAllocationsRequest) GetDateFrom() string {
+	if x != nil {
+		return x.DateFrom
+	}
+	return ""
+}
+
+func (x *GetSellAllocationsRequest) GetDateTo() string {
+	if x != nil {
+		return x.DateTo
+	}
+	return ""
+}
+
+func (x *GetSellAllocationsRequest) GetIncludeReversed() bool {
+	if x != nil {
+		return x.IncludeReversed
+	}
+	return false
+}
+
+type GetSellAllocationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Allocations   []*SellAllocation      `protobuf:"bytes,1,rep,name=allocations,proto3" json:"allocations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSellAllocationsResponse) Reset() {
+	*x = GetSellAllocationsResponse{}
+	mi := &file_market_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSellAllocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSellAllocationsResponse) ProtoMessage() {}
+
+func (x *GetSellAllocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSellAllocationsResponse.ProtoReflect.Descriptor instead.
+func (*GetSellAllocationsResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetSellAllocationsResponse) GetAllocations() []*SellAllocation {
+	if x != nil {
+		return x.Allocations
+	}
+	return nil
+}
+
+type AgeingBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"` // 0-30 | 31-90 | 90+ | EXPIRED
+	Quantity      string                 `protobuf:"bytes,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Cost          string                 `protobuf:"bytes,3,opt,name=cost,proto3" json:"cost,omitempty"` // remaining quantity at lot price
+	Lots          uint32                 `protobuf:"varint,4,opt,name=lots,proto3" json:"lots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgeingBucket) Reset() {
+	*x = AgeingBucket{}
+	mi := &file_market_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgeingBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgeingBucket) ProtoMessage() {}
+
+func (x *AgeingBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgeingBucket.ProtoReflect.Descriptor instead.
+func (*AgeingBucket) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AgeingBucket) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *AgeingBucket) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *AgeingBucket) GetCost() string {
+	if x != nil {
+		return x.Cost
+	}
+	return ""
+}
+
+func (x *AgeingBucket) GetLots() uint32 {
+	if x != nil {
+		return x.Lots
+	}
+	return 0
+}
+
+type GradeAgeing struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SpiceGradeId   string                 `protobuf:"bytes,1,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	ProductName    string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	GradeName      string                 `protobuf:"bytes,3,opt,name=grade_name,json=gradeName,proto3" json:"grade_name,omitempty"`
+	ShelfLifeDays  uint32                 `protobuf:"varint,4,opt,name=shelf_life_days,json=shelfLifeDays,proto3" json:"shelf_life_days,omitempty"` // 0 = not perishable; such lots never expire
+	Buckets        []*AgeingBucket        `protobuf:"bytes,5,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	NearExpiryQty  string                 `protobuf:"bytes,6,opt,name=near_expiry_qty,json=nearExpiryQty,proto3" json:"near_expiry_qty,omitempty"` // within 14 days of shelf life
+	NearExpiryLots uint32                 `protobuf:"varint,7,opt,name=near_expiry_lots,json=nearExpiryLots,proto3" json:"near_expiry_lots,omitempty"`
+	NextExpiryDate string                 `protobuf:"bytes,8,opt,name=next_expiry_date,json=nextExpiryDate,proto3" json:"next_expiry_date,omitempty"` // YYYY-MM-DD; empty when no lot is due
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GradeAgeing) Reset() {
+	*x = GradeAgeing{}
+	mi := &file_market_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GradeAgeing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GradeAgeing) ProtoMessage() {}
+
+func (x *GradeAgeing) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GradeAgeing.ProtoReflect.Descriptor instead.
+func (*GradeAgeing) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GradeAgeing) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *GradeAgeing) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *GradeAgeing) GetGradeName() string {
+	if x != nil {
+		return x.GradeName
+	}
+	return ""
+}
+
+func (x *GradeAgeing) GetShelfLifeDays() uint32 {
+	if x != nil {
+		return x.ShelfLifeDays
+	}
+	return 0
+}
+
+func (x *GradeAgeing) GetBuckets() []*AgeingBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GradeAgeing) GetNearExpiryQty() string {
+	if x != nil {
+		return x.NearExpiryQty
+	}
+	return ""
+}
+
+func (x *GradeAgeing) GetNearExpiryLots() uint32 {
+	if x != nil {
+		return x.NearExpiryLots
+	}
+	return 0
+}
+
+func (x *GradeAgeing) GetNextExpiryDate() string {
+	if x != nil {
+		return x.NextExpiryDate
+	}
+	return ""
+}
+
+type GetLotAgeingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AsOf          string                 `protobuf:"bytes,2,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"` // YYYY-MM-DD optional; defaults to today
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLotAgeingRequest) Reset() {
+	*x = GetLotAgeingRequest{}
+	mi := &file_market_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLotAgeingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLotAgeingRequest) ProtoMessage() {}
+
+func (x *GetLotAgeingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLotAgeingRequest.ProtoReflect.Descriptor instead.
+func (*GetLotAgeingRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetLotAgeingRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetLotAgeingRequest) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+type GetLotAgeingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AsOf          string                 `protobuf:"bytes,1,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	Buckets       []*AgeingBucket        `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"` // all grades
+	Grades        []*GradeAgeing         `protobuf:"bytes,3,rep,name=grades,proto3" json:"grades,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLotAgeingResponse) Reset() {
+	*x = GetLotAgeingResponse{}
+	mi := &file_market_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLotAgeingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLotAgeingResponse) ProtoMessage() {}
+
+func (x *GetLotAgeingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLotAgeingResponse.ProtoReflect.Descriptor instead.
+func (*GetLotAgeingResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetLotAgeingResponse) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+func (x *GetLotAgeingResponse) GetBuckets() []*AgeingBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetLotAgeingResponse) GetGrades() []*GradeAgeing {
+	if x != nil {
+		return x.Grades
+	}
+	return nil
+}
+
+type RealizedGain struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Kind              string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`                      // LONG: a sell drawing on a buy lot | SHORT: a buy covering a short lot
+	MatchId           string                 `protobuf:"bytes,2,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"` // the sell allocation or short cover
+	SellTransactionId string                 `protobuf:"bytes,3,opt,name=sell_transaction_id,json=sellTransactionId,proto3" json:"sell_transaction_id,omitempty"`
+	BuyTransactionId  string                 `protobuf:"bytes,4,opt,name=buy_transaction_id,json=buyTransactionId,proto3" json:"buy_transaction_id,omitempty"`
+	LotId             string                 `protobuf:"bytes,5,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"` // the buy lot or short lot
+	SpiceGradeId      string                 `protobuf:"bytes,6,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	ProductName       string                 `protobuf:"bytes,7,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	GradeName         string                 `protobuf:"bytes,8,opt,name=grade_name,json=gradeName,proto3" json:"grade_name,omitempty"`
+	Currency          string                 `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
+	CostBasisMethod   string                 `protobuf:"bytes,10,opt,name=cost_basis_method,json=costBasisMethod,proto3" json:"cost_basis_method,omitempty"` // LONG only
+	Quantity          string                 `protobuf:"bytes,11,opt,name=quantity,proto3" json:"quantity,omitempty"`                                        // kg
+	BuyPrice          string                 `protobuf:"bytes,12,opt,name=buy_price,json=buyPrice,proto3" json:"buy_price,omitempty"`                        // per kg, buy fees included
+	SellPrice         string                 `protobuf:"bytes,13,opt,name=sell_price,json=sellPrice,proto3" json:"sell_price,omitempty"`                     // per kg; net of sell fees for SHORT
+	AcquiredDate      string                 `protobuf:"bytes,14,opt,name=acquired_date,json=acquiredDate,proto3" json:"acquired_date,omitempty"`            // YYYY-MM-DD; after disposed_date for SHORT
+	DisposedDate      string                 `protobuf:"bytes,15,opt,name=disposed_date,json=disposedDate,proto3" json:"disposed_date,omitempty"`            // YYYY-MM-DD
+	RealizedDate      string                 `protobuf:"bytes,16,opt,name=realized_date,json=realizedDate,proto3" json:"realized_date,omitempty"`            // YYYY-MM-DD; the sell, or the covering buy
+	HoldingDays       uint32                 `protobuf:"varint,17,opt,name=holding_days,json=holdingDays,proto3" json:"holding_days,omitempty"`
+	HoldingClass      string                 `protobuf:"bytes,18,opt,name=holding_class,json=holdingClass,proto3" json:"holding_class,omitempty"` // SHORT_TERM | LONG_TERM
+	Cost              string                 `protobuf:"bytes,19,opt,name=cost,proto3" json:"cost,omitempty"`
+	Proceeds          string                 `protobuf:"bytes,20,opt,name=proceeds,proto3" json:"proceeds,omitempty"`
+	Fees              string                 `protobuf:"bytes,21,opt,name=fees,proto3" json:"fees,omitempty"` // sell fees
+	Gain              string                 `protobuf:"bytes,22,opt,name=gain,proto3" json:"gain,omitempty"` // proceeds - cost - fees
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RealizedGain) Reset() {
+	*x = RealizedGain{}
+	mi := &file_market_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RealizedGain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RealizedGain) ProtoMessage() {}
+
+func (x *RealizedGain) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RealizedGain.ProtoReflect.Descriptor instead.
+func (*RealizedGain) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RealizedGain) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetSellTransactionId() string {
+	if x != nil {
+		return x.SellTransactionId
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetBuyTransactionId() string {
+	if x != nil {
+		return x.BuyTransactionId
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetLotId() string {
+	if x != nil {
+		return x.LotId
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetGradeName() string {
+	if x != nil {
+		return x.GradeName
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetCostBasisMethod() string {
+	if x != nil {
+		return x.CostBasisMethod
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetBuyPrice() string {
+	if x != nil {
+		return x.BuyPrice
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetSellPrice() string {
+	if x != nil {
+		return x.SellPrice
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetAcquiredDate() string {
+	if x != nil {
+		return x.AcquiredDate
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetDisposedDate() string {
+	if x != nil {
+		return x.DisposedDate
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetRealizedDate() string {
+	if x != nil {
+		return x.RealizedDate
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetHoldingDays() uint32 {
+	if x != nil {
+		return x.HoldingDays
+	}
+	return 0
+}
+
+func (x *RealizedGain) GetHoldingClass() string {
+	if x != nil {
+		return x.HoldingClass
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetCost() string {
+	if x != nil {
+		return x.Cost
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetProceeds() string {
+	if x != nil {
+		return x.Proceeds
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetFees() string {
+	if x != nil {
+		return x.Fees
+	}
+	return ""
+}
+
+func (x *RealizedGain) GetGain() string {
+	if x != nil {
+		return x.Gain
+	}
+	return ""
+}
+
+type GainsSubtotal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpiceGradeId  string                 `protobuf:"bytes,1,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"` // grade subtotals
+	ProductName   string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	GradeName     string                 `protobuf:"bytes,3,opt,name=grade_name,json=gradeName,proto3" json:"grade_name,omitempty"`
+	HoldingClass  string                 `protobuf:"bytes,4,opt,name=holding_class,json=holdingClass,proto3" json:"holding_class,omitempty"` // holding-class subtotals
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Quantity      string                 `protobuf:"bytes,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Cost          string                 `protobuf:"bytes,7,opt,name=cost,proto3" json:"cost,omitempty"`
+	Proceeds      string                 `protobuf:"bytes,8,opt,name=proceeds,proto3" json:"proceeds,omitempty"`
+	Fees          string                 `protobuf:"bytes,9,opt,name=fees,proto3" json:"fees,omitempty"`
+	Gain          string                 `protobuf:"bytes,10,opt,name=gain,proto3" json:"gain,omitempty"`
+	Lines         uint32                 `protobuf:"varint,11,opt,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GainsSubtotal) Reset() {
+	*x = GainsSubtotal{}
+	mi := &file_market_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GainsSubtotal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GainsSubtotal) ProtoMessage() {}
+
+func (x *GainsSubtotal) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GainsSubtotal.ProtoReflect.Descriptor instead.
+func (*GainsSubtotal) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GainsSubtotal) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *GainsSubtotal) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *GainsSubtotal) GetGradeName() string {
+	if x != nil {
+		return x.GradeName
+	}
+	return ""
+}
+
+func (x *GainsSubtotal) GetHoldingClass() string {
+	if x != nil {
+		return x.HoldingClass
+	}
+	return ""
+}
+
+func (x *GainsSubtotal) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GainsSubtotal) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *GainsSubtotal) GetCost() string {
+	if x != nil {
+		return x.Cost
+	}
+	return ""
+}
+
+func (x *GainsSubtotal) GetProceeds() string {
+	if x != nil {
+		return x.Proceeds
+	}
+	return ""
+}
+
+func (x *GainsSubtotal) GetFees() string {
+	if x != nil {
+		return x.Fees
+	}
+	return ""
+}
+
+func (x *GainsSubtotal) GetGain() string {
+	if x != nil {
+		return x.Gain
+	}
+	return ""
+}
+
+func (x *GainsSubtotal) GetLines() uint32 {
+	if x != nil {
+		return x.Lines
+	}
+	return 0
+}
+
+type GetRealizedGainsReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FinancialYear string                 `protobuf:"bytes,2,opt,name=financial_year,json=financialYear,proto3" json:"financial_year,omitempty"` // "2025-26" or "2025": April to March; or both dates below
+	DateFrom      string                 `protobuf:"bytes,3,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`                // YYYY-MM-DD, on the date a gain was realized
+	DateTo        string                 `protobuf:"bytes,4,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	SpiceGradeId  string                 `protobuf:"bytes,5,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`  // optional
+	LongTermDays  uint32                 `protobuf:"varint,6,opt,name=long_term_days,json=longTermDays,proto3" json:"long_term_days,omitempty"` // held longer is LONG_TERM; default 365
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRealizedGainsReportRequest) Reset() {
+	*x = GetRealizedGainsReportRequest{}
+	mi := &file_market_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRealizedGainsReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRealizedGainsReportRequest) ProtoMessage() {}
+
+func (x *GetRealizedGainsReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRealizedGainsReportRequest.ProtoReflect.Descriptor instead.
+func (*GetRealizedGainsReportRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetRealizedGainsReportRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetRealizedGainsReportRequest) GetFinancialYear() string {
+	if x != nil {
+		return x.FinancialYear
+	}
+	return ""
+}
+
+func (x *GetRealizedGainsReportRequest) GetDateFrom() string {
+	if x != nil {
+		return x.DateFrom
+	}
+	return ""
+}
+
+func (x *GetRealizedGainsReportRequest) GetDateTo() string {
+	if x != nil {
+		return x.DateTo
+	}
+	return ""
+}
+
+func (x *GetRealizedGainsReportRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *GetRealizedGainsReportRequest) GetLongTermDays() uint32 {
+	if x != nil {
+		return x.LongTermDays
+	}
+	return 0
+}
+
+type GetRealizedGainsReportResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DateFrom       string                 `protobuf:"bytes,2,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	DateTo         string                 `protobuf:"bytes,3,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	LongTermDays   uint32                 `protobuf:"varint,4,opt,name=long_term_days,json=longTermDays,proto3" json:"long_term_days,omitempty"`
+	Gains          []*RealizedGain        `protobuf:"bytes,5,rep,name=gains,proto3" json:"gains,omitempty"`                                         // oldest first
+	Grades         []*GainsSubtotal       `protobuf:"bytes,6,rep,name=grades,proto3" json:"grades,omitempty"`                                       // per grade and currency
+	HoldingClasses []*GainsSubtotal       `protobuf:"bytes,7,rep,name=holding_classes,json=holdingClasses,proto3" json:"holding_classes,omitempty"` // per holding class and currency
+	Totals         []*GainsSubtotal       `protobuf:"bytes,8,rep,name=totals,proto3" json:"totals,omitempty"`                                       // per currency
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetRealizedGainsReportResponse) Reset() {
+	*x = GetRealizedGainsReportResponse{}
+	mi := &file_market_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRealizedGainsReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRealizedGainsReportResponse) ProtoMessage() {}
+
+func (x *GetRealizedGainsReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRealizedGainsReportResponse.ProtoReflect.Descriptor instead.
+func (*GetRealizedGainsReportResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetRealizedGainsReportResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetRealizedGainsReportResponse) GetDateFrom() string {
+	if x != nil {
+		return x.DateFrom
+	}
+	return ""
+}
+
+func (x *GetRealizedGainsReportResponse) GetDateTo() string {
+	if x != nil {
+		return x.DateTo
+	}
+	return ""
+}
+
+func (x *GetRealizedGainsReportResponse) GetLongTermDays() uint32 {
+	if x != nil {
+		return x.LongTermDays
+	}
+	return 0
+}
+
+func (x *GetRealizedGainsReportResponse) GetGains() []*RealizedGain {
+	if x != nil {
+		return x.Gains
+	}
+	return nil
+}
+
+func (x *GetRealizedGainsReportResponse) GetGrades() []*GainsSubtotal {
+	if x != nil {
+		return x.Grades
+	}
+	return nil
+}
+
+func (x *GetRealizedGainsReportResponse) GetHoldingClasses() []*GainsSubtotal {
+	if x != nil {
+		return x.HoldingClasses
+	}
+	return nil
+}
+
+func (x *GetRealizedGainsReportResponse) GetTotals() []*GainsSubtotal {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+type ExportLedgerRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Kind            string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`                                               // TRANSACTIONS | LOTS | ALLOCATIONS | POSITIONS
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                             // admins: empty exports every account
+	SpiceGradeId    string                 `protobuf:"bytes,3,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`         // optional
+	ProductId       string                 `protobuf:"bytes,4,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`                    // optional; every grade of the product
+	Sort            string                 `protobuf:"bytes,5,opt,name=sort,proto3" json:"sort,omitempty"`                                               // DESC (default) | ASC on trade date; positions are by account and grade
+	DateFrom        string                 `protobuf:"bytes,6,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`                       // YYYY-MM-DD optional, on the transaction, lot or SELL trade date
+	DateTo          string                 `protobuf:"bytes,7,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`                             // YYYY-MM-DD optional; positions ignore both dates
+	OpenOnly        bool                   `protobuf:"varint,8,opt,name=open_only,json=openOnly,proto3" json:"open_only,omitempty"`                      // LOTS: only lots with remaining quantity
+	IncludeReversed bool                   `protobuf:"varint,9,opt,name=include_reversed,json=includeReversed,proto3" json:"include_reversed,omitempty"` // ALLOCATIONS: also allocations undone by a cancellation
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ExportLedgerRequest) Reset() {
+	*x = ExportLedgerRequest{}
+	mi := &file_market_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportLedgerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportLedgerRequest) ProtoMessage() {}
+
+func (x *ExportLedgerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportLedgerRequest.ProtoReflect.Descriptor instead.
+func (*ExportLedgerRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ExportLedgerRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ExportLedgerRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ExportLedgerRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *ExportLedgerRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ExportLedgerRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ExportLedgerRequest) GetDateFrom() string {
+	if x != nil {
+		return x.DateFrom
+	}
+	return ""
+}
+
+func (x *ExportLedgerRequest) GetDateTo() string {
+	if x != nil {
+		return x.DateTo
+	}
+	return ""
+}
+
+func (x *ExportLedgerRequest) GetOpenOnly() bool {
+	if x != nil {
+		return x.OpenOnly
+	}
+	return false
+}
+
+func (x *ExportLedgerRequest) GetIncludeReversed() bool {
+	if x != nil {
+		return x.IncludeReversed
+	}
+	return false
+}
+
+type ExportLedgerRow struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ProductName string                 `protobuf:"bytes,1,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	GradeName   string                 `protobuf:"bytes,2,opt,name=grade_name,json=gradeName,proto3" json:"grade_name,omitempty"`
+	// Types that are valid to be assigned to Record:
+	//
+	//	*ExportLedgerRow_Transaction
+	//	*ExportLedgerRow_Lot
+	//	*ExportLedgerRow_Allocation
+	//	*ExportLedgerRow_Position
+	Record        isExportLedgerRow_Record `protobuf_oneof:"record"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportLedgerRow) Reset() {
+	*x = ExportLedgerRow{}
+	mi := &file_market_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportLedgerRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportLedgerRow) ProtoMessage() {}
+
+func (x *ExportLedgerRow) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportLedgerRow.ProtoReflect.Descriptor instead.
+func (*ExportLedgerRow) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ExportLedgerRow) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *ExportLedgerRow) GetGradeName() string {
+	if x != nil {
+		return x.GradeName
+	}
+	return ""
+}
+
+func (x *ExportLedgerRow) GetRecord() isExportLedgerRow_Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *ExportLedgerRow) GetTransaction() *Transaction {
+	if x != nil {
+		if x, ok := x.Record.(*ExportLedgerRow_Transaction); ok {
+			return x.Transaction
+		}
+	}
+	return nil
+}
+
+func (x *ExportLedgerRow) GetLot() *BuyLot {
+	if x != nil {
+		if x, ok := x.Record.(*ExportLedgerRow_Lot); ok {
+			return x.Lot
+		}
+	}
+	return nil
+}
+
+func (x *ExportLedgerRow) GetAllocation() *SellAllocation {
+	if x != nil {
+		if x, ok := x.Record.(*ExportLedgerRow_Allocation); ok {
+			return x.Allocation
+		}
+	}
+	return nil
+}
+
+func (x *ExportLedgerRow) GetPosition() *PositionView {
+	if x != nil {
+		if x, ok := x.Record.(*ExportLedgerRow_Position); ok {
+			return x.Position
+		}
+	}
+	return nil
+}
+
+type isExportLedgerRow_Record interface {
+	isExportLedgerRow_Record()
+}
+
+type ExportLedgerRow_Transaction struct {
+	Transaction *Transaction `protobuf:"bytes,3,opt,name=transaction,proto3,oneof"`
+}
+
+type ExportLedgerRow_Lot struct {
+	Lot *BuyLot `protobuf:"bytes,4,opt,name=lot,proto3,oneof"`
+}
+
+type ExportLedgerRow_Allocation struct {
+	Allocation *SellAllocation `protobuf:"bytes,5,opt,name=allocation,proto3,oneof"`
+}
+
+type ExportLedgerRow_Position struct {
+	Position *PositionView `protobuf:"bytes,6,opt,name=position,proto3,oneof"`
+}
+
+func (*ExportLedgerRow_Transaction) isExportLedgerRow_Record() {}
+
+func (*ExportLedgerRow_Lot) isExportLedgerRow_Record() {}
+
+func (*ExportLedgerRow_Allocation) isExportLedgerRow_Record() {}
+
+func (*ExportLedgerRow_Position) isExportLedgerRow_Record() {}
+
+type CostBasisPreference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"` // empty = account-wide default
+	Method        string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`                        // GRADE | ACCOUNT | DEFAULT
+	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // YYYY-MM-DD HH:MM:SS; empty for DEFAULT
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CostBasisPreference) Reset() {
+	*x = CostBasisPreference{}
+	mi := &file_market_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostBasisPreference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostBasisPreference) ProtoMessage() {}
+
+func (x *CostBasisPreference) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostBasisPreference.ProtoReflect.Descriptor instead.
+func (*CostBasisPreference) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CostBasisPreference) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CostBasisPreference) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *CostBasisPreference) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *CostBasisPreference) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CostBasisPreference) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type SetCostBasisMethodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"` // optional; empty sets the account default
+	Method        string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`                                   // FIFO | LIFO | WEIGHTED_AVERAGE
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCostBasisMethodRequest) Reset() {
+	*x = SetCostBasisMethodRequest{}
+	mi := &file_market_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCostBasisMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCostBasisMethodRequest) ProtoMessage() {}
+
+func (x *SetCostBasisMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCostBasisMethodRequest.ProtoReflect.Descriptor instead.
+func (*SetCostBasisMethodRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SetCostBasisMethodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetCostBasisMethodRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *SetCostBasisMethodRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+type SetCostBasisMethodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Preference    *CostBasisPreference   `protobuf:"bytes,1,opt,name=preference,proto3" json:"preference,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCostBasisMethodResponse) Reset() {
+	*x = SetCostBasisMethodResponse{}
+	mi := &file_market_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCostBasisMethodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCostBasisMethodResponse) ProtoMessage() {}
+
+func (x *SetCostBasisMethodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCostBasisMethodResponse.ProtoReflect.Descriptor instead.
+func (*SetCostBasisMethodResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SetCostBasisMethodResponse) GetPreference() *CostBasisPreference {
+	if x != nil {
+		return x.Preference
+	}
+	return nil
+}
+
+type GetCostBasisMethodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"` // optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCostBasisMethodRequest) Reset() {
+	*x = GetCostBasisMethodRequest{}
+	mi := &file_market_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCostBasisMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCostBasisMethodRequest) ProtoMessage() {}
+
+func (x *GetCostBasisMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCostBasisMethodRequest.ProtoReflect.Descriptor instead.
+func (*GetCostBasisMethodRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetCostBasisMethodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetCostBasisMethodRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+type GetCostBasisMethodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Preference    *CostBasisPreference   `protobuf:"bytes,1,opt,name=preference,proto3" json:"preference,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCostBasisMethodResponse) Reset() {
+	*x = GetCostBasisMethodResponse{}
+	mi := &file_market_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCostBasisMethodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCostBasisMethodResponse) ProtoMessage() {}
+
+func (x *GetCostBasisMethodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCostBasisMethodResponse.ProtoReflect.Descriptor instead.
+func (*GetCostBasisMethodResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetCostBasisMethodResponse) GetPreference() *CostBasisPreference {
+	if x != nil {
+		return x.Preference
+	}
+	return nil
+}
+
+type TradingPermissions struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UserId            string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AllowShortSelling bool                   `protobuf:"varint,2,opt,name=allow_short_selling,json=allowShortSelling,proto3" json:"allow_short_selling,omitempty"`
+	UpdatedBy         string                 `protobuf:"bytes,3,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"` // admin account; empty when never configured
+	UpdatedAt         string                 `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // YYYY-MM-DD HH:MM:SS; empty when never configured
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *TradingPermissions) Reset() {
+	*x = TradingPermissions{}
+	mi := &file_market_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradingPermissions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradingPermissions) ProtoMessage() {}
+
+func (x *TradingPermissions) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradingPermissions.ProtoReflect.Descriptor instead.
+func (*TradingPermissions) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *TradingPermissions) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TradingPermissions) GetAllowShortSelling() bool {
+	if x != nil {
+		return x.AllowShortSelling
+	}
+	return false
+}
+
+func (x *TradingPermissions) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+func (x *TradingPermissions) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type SetTradingPermissionsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UserId            string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AllowShortSelling bool                   `protobuf:"varint,2,opt,name=allow_short_selling,json=allowShortSelling,proto3" json:"allow_short_selling,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SetTradingPermissionsRequest) Reset() {
+	*x = SetTradingPermissionsRequest{}
+	mi := &file_market_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTradingPermissionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTradingPermissionsRequest) ProtoMessage() {}
+
+func (x *SetTradingPermissionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTradingPermissionsRequest.ProtoReflect.Descriptor instead.
+func (*SetTradingPermissionsRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *SetTradingPermissionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetTradingPermissionsRequest) GetAllowShortSelling() bool {
+	if x != nil {
+		return x.AllowShortSelling
+	}
+	return false
+}
+
+type SetTradingPermissionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Permissions   *TradingPermissions    `protobuf:"bytes,1,opt,name=permissions,proto3" json:"permissions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTradingPermissionsResponse) Reset() {
+	*x = SetTradingPermissionsResponse{}
+	mi := &file_market_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTradingPermissionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTradingPermissionsResponse) ProtoMessage() {}
+
+func (x *SetTradingPermissionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTradingPermissionsResponse.ProtoReflect.Descriptor instead.
+func (*SetTradingPermissionsResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *SetTradingPermissionsResponse) GetPermissions() *TradingPermissions {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+type GetTradingPermissionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // admin only; merchants always read their own
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTradingPermissionsRequest) Reset() {
+	*x = GetTradingPermissionsRequest{}
+	mi := &file_market_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTradingPermissionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTradingPermissionsRequest) ProtoMessage() {}
+
+func (x *GetTradingPermissionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTradingPermissionsRequest.ProtoReflect.Descriptor instead.
+func (*GetTradingPermissionsRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetTradingPermissionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetTradingPermissionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Permissions   *TradingPermissions    `protobuf:"bytes,1,opt,name=permissions,proto3" json:"permissions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTradingPermissionsResponse) Reset() {
+	*x = GetTradingPermissionsResponse{}
+	mi := &file_market_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTradingPermissionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTradingPermissionsResponse) ProtoMessage() {}
+
+func (x *GetTradingPermissionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTradingPermissionsResponse.ProtoReflect.Descriptor instead.
+func (*GetTradingPermissionsResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetTradingPermissionsResponse) GetPermissions() *TradingPermissions {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+// Omit epoch and after_sequence for a fresh subscription. To resume after a disconnect, send
+// the epoch and sequence of the last event received; events retained since then are replayed.
+type SubscribeTradesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                     // admins only; others always receive their own trades
+	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"` // optional grade filter
+	Epoch         string                 `protobuf:"bytes,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	AfterSequence uint64                 `protobuf:"varint,4,opt,name=after_sequence,json=afterSequence,proto3" json:"after_sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeTradesRequest) Reset() {
+	*x = SubscribeTradesRequest{}
+	mi := &file_market_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeTradesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeTradesRequest) ProtoMessage() {}
+
+func (x *SubscribeTradesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeTradesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeTradesRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SubscribeTradesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubscribeTradesRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *SubscribeTradesRequest) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *SubscribeTradesRequest) GetAfterSequence() uint64 {
+	if x != nil {
+		return x.AfterSequence
+	}
+	return 0
+}
+
+type TradeEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Epoch         string                 `protobuf:"bytes,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Transaction   *Transaction           `protobuf:"bytes,3,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TradeEvent) Reset() {
+	*x = TradeEvent{}
+	mi := &file_market_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeEvent) ProtoMessage() {}
+
+func (x *TradeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeEvent.ProtoReflect.Descriptor instead.
+func (*TradeEvent) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *TradeEvent) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *TradeEvent) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *TradeEvent) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId  string                 `protobuf:"bytes,3,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	Side          string                 `protobuf:"bytes,4,opt,name=side,proto3" json:"side,omitempty"`   // BUY | SELL
+	Price         string                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"` // limit price
+	Quantity      string                 `protobuf:"bytes,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	RemainingQty  string                 `protobuf:"bytes,7,opt,name=remaining_qty,json=remainingQty,proto3" json:"remaining_qty,omitempty"` // unfilled quantity
+	Status        string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`                                 // OPEN | PARTIALLY_FILLED | FILLED | CANCELLED
+	CreatedAt     string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`          // YYYY-MM-DD HH:MM:SS
+	UpdatedAt     string                 `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`         // YYYY-MM-DD HH:MM:SS
+	Currency      string                 `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`                            // orders only match orders of the same currency
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_market_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *Order) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Order) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Order) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *Order) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *Order) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *Order) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *Order) GetRemainingQty() string {
+	if x != nil {
+		return x.RemainingQty
+	}
+	return ""
+}
+
+func (x *Order) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Order) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Order) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Order) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type OrderFill struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SpiceGradeId      string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	BuyOrderId        string                 `protobuf:"bytes,3,opt,name=buy_order_id,json=buyOrderId,proto3" json:"buy_order_id,omitempty"`
+	SellOrderId       string                 `protobuf:"bytes,4,opt,name=sell_order_id,json=sellOrderId,proto3" json:"sell_order_id,omitempty"`
+	BuyTransactionId  string                 `protobuf:"bytes,5,opt,name=buy_transaction_id,json=buyTransactionId,proto3" json:"buy_transaction_id,omitempty"`
+	SellTransactionId string                 `protobuf:"bytes,6,opt,name=sell_transaction_id,json=sellTransactionId,proto3" json:"sell_transaction_id,omitempty"`
+	Quantity          string                 `protobuf:"bytes,7,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price             string                 `protobuf:"bytes,8,opt,name=price,proto3" json:"price,omitempty"`                          // the resting order's price
+	CreatedAt         string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // YYYY-MM-DD HH:MM:SS
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *OrderFill) Reset() {
+	*x = OrderFill{}
+	mi := &file_market_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderFill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFill) ProtoMessage() {}
+
+func (x *OrderFill) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFill.ProtoReflect.Descriptor instead.
+func (*OrderFill) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *OrderFill) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrderFill) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *OrderFill) GetBuyOrderId() string {
+	if x != nil {
+		return x.BuyOrderId
+	}
+	return ""
+}
+
+func (x *OrderFill) GetSellOrderId() string {
+	if x != nil {
+		return x.SellOrderId
+	}
+	return ""
+}
+
+func (x *OrderFill) GetBuyTransactionId() string {
+	if x != nil {
+		return x.BuyTransactionId
+	}
+	return ""
+}
+
+func (x *OrderFill) GetSellTransactionId() string {
+	if x != nil {
+		return x.SellTransactionId
+	}
+	return ""
+}
+
+func (x *OrderFill) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *OrderFill) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *OrderFill) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type PlaceOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	Side          string                 `protobuf:"bytes,3,opt,name=side,proto3" json:"side,omitempty"` // BUY | SELL
+	Quantity      string                 `protobuf:"bytes,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         string                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"` // limit price
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceOrderRequest) Reset() {
+	*x = PlaceOrderRequest{}
+	mi := &file_market_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceOrderRequest) ProtoMessage() {}
+
+func (x *PlaceOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceOrderRequest.ProtoReflect.Descriptor instead.
+func (*PlaceOrderRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *PlaceOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+type PlaceOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Fills         []*OrderFill           `protobuf:"bytes,2,rep,name=fills,proto3" json:"fills,omitempty"` // matches made on placement
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceOrderResponse) Reset() {
+	*x = PlaceOrderResponse{}
+	mi := &file_market_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceOrderResponse) ProtoMessage() {}
+
+func (x *PlaceOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceOrderResponse.ProtoReflect.Descriptor instead.
+func (*PlaceOrderResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *PlaceOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *PlaceOrderResponse) GetFills() []*OrderFill {
+	if x != nil {
+		return x.Fills
+	}
+	return nil
+}
+
+type AmendOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Quantity      string                 `protobuf:"bytes,3,opt,name=quantity,proto3" json:"quantity,omitempty"` // new total quantity; empty keeps the current one
+	Price         string                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`       // new limit price; empty keeps the current one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AmendOrderRequest) Reset() {
+	*x = AmendOrderRequest{}
+	mi := &file_market_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AmendOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmendOrderRequest) ProtoMessage() {}
+
+func (x *AmendOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmendOrderRequest.ProtoReflect.Descriptor instead.
+func (*AmendOrderRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *AmendOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+type AmendOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Fills         []*OrderFill           `protobuf:"bytes,2,rep,name=fills,proto3" json:"fills,omitempty"` // matches made after a price change
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AmendOrderResponse) Reset() {
+	*x = AmendOrderResponse{}
+	mi := &file_market_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AmendOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmendOrderResponse) ProtoMessage() {}
+
+func (x *AmendOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmendOrderResponse.ProtoReflect.Descriptor instead.
+func (*AmendOrderResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *AmendOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *AmendOrderResponse) GetFills() []*OrderFill {
+	if x != nil {
+		return x.Fills
+	}
+	return nil
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_market_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *CancelOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_market_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CancelOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_market_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GetOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Fills         []*OrderFill           `protobuf:"bytes,2,rep,name=fills,proto3" json:"fills,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderResponse) Reset() {
+	*x = GetOrderResponse{}
+	mi := &file_market_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderResponse) ProtoMessage() {}
+
+func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GetOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *GetOrderResponse) GetFills() []*OrderFill {
+	if x != nil {
+		return x.Fills
+	}
+	return nil
+}
+
+type ListOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpiceGradeId  string                 `protobuf:"bytes,2,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"` // optional
+	Side          string                 `protobuf:"bytes,3,opt,name=side,proto3" json:"side,omitempty"`                                       // optional: BUY | SELL
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                   // optional: OPEN | PARTIALLY_FILLED | FILLED | CANCELLED
+	Skip          uint32                 `protobuf:"varint,5,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint32                 `protobuf:"varint,6,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_market_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ListOrdersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetSkip() uint32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetTake() uint32 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type ListOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_market_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type OrderBookLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         string                 `protobuf:"bytes,1,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      string                 `protobuf:"bytes,2,opt,name=quantity,proto3" json:"quantity,omitempty"` // resting quantity at this price
+	Orders        uint32                 `protobuf:"varint,3,opt,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderBookLevel) Reset() {
+	*x = OrderBookLevel{}
+	mi := &file_market_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBookLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBookLevel) ProtoMessage() {}
+
+func (x *OrderBookLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBookLevel.ProtoReflect.Descriptor instead.
+func (*OrderBookLevel) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *OrderBookLevel) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *OrderBookLevel) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *OrderBookLevel) GetOrders() uint32 {
+	if x != nil {
+		return x.Orders
+	}
+	return 0
+}
+
+type GetOrderBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpiceGradeId  string                 `protobuf:"bytes,1,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	Depth         uint32                 `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`      // price levels per side; default 10, max 50
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"` // defaults to the caller's trading currency
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderBookRequest) Reset() {
+	*x = GetOrderBookRequest{}
+	mi := &file_market_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderBookRequest) ProtoMessage() {}
+
+func (x *GetOrderBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderBookRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderBookRequest) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetOrderBookRequest) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *GetOrderBookRequest) GetDepth() uint32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *GetOrderBookRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type GetOrderBookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpiceGradeId  string                 `protobuf:"bytes,1,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
+	Bids          []*OrderBookLevel      `protobuf:"bytes,2,rep,name=bids,proto3" json:"bids,omitempty"` // highest price first
+	Asks          []*OrderBookLevel      `protobuf:"bytes,3,rep,name=asks,proto3" json:"asks,omitempty"` // lowest price first
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderBookResponse) Reset() {
+	*x = GetOrderBookResponse{}
+	mi := &file_market_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderBookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderBookResponse) ProtoMessage() {}
+
+func (x *GetOrderBookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderBookResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderBookResponse) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *GetOrderBookResponse) GetSpiceGradeId() string {
+	if x != nil {
+		return x.SpiceGradeId
+	}
+	return ""
+}
+
+func (x *GetOrderBookResponse) GetBids() []*OrderBookLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
 }
 
-func (x *SellResponse) GetTransaction() *Transaction {
+func (x *GetOrderBookResponse) GetAsks() []*OrderBookLevel {
 	if x != nil {
-		return x.Transaction
+		return x.Asks
 	}
 	return nil
 }
 
+func (x *GetOrderBookResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type GetGradePositionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -481,7 +4975,7 @@ type GetGradePositionRequest struct {
 
 func (x *GetGradePositionRequest) Reset() {
 	*x = GetGradePositionRequest{}
-	mi := &file_market_proto_msgTypes[6]
+	mi := &file_market_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -493,7 +4987,7 @@ func (x *GetGradePositionRequest) String() string {
 func (*GetGradePositionRequest) ProtoMessage() {}
 
 func (x *GetGradePositionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[6]
+	mi := &file_market_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -506,7 +5000,7 @@ func (x *GetGradePositionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetGradePositionRequest.ProtoReflect.Descriptor instead.
 func (*GetGradePositionRequest) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{6}
+	return file_market_proto_rawDescGZIP(), []int{64}
 }
 
 func (x *GetGradePositionRequest) GetUserId() string {
@@ -532,7 +5026,7 @@ type GetGradePositionResponse struct {
 
 func (x *GetGradePositionResponse) Reset() {
 	*x = GetGradePositionResponse{}
-	mi := &file_market_proto_msgTypes[7]
+	mi := &file_market_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -544,7 +5038,7 @@ func (x *GetGradePositionResponse) String() string {
 func (*GetGradePositionResponse) ProtoMessage() {}
 
 func (x *GetGradePositionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[7]
+	mi := &file_market_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -557,7 +5051,7 @@ func (x *GetGradePositionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetGradePositionResponse.ProtoReflect.Descriptor instead.
 func (*GetGradePositionResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{7}
+	return file_market_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *GetGradePositionResponse) GetPosition() *PositionView {
@@ -576,7 +5070,7 @@ type GetPositionsRequest struct {
 
 func (x *GetPositionsRequest) Reset() {
 	*x = GetPositionsRequest{}
-	mi := &file_market_proto_msgTypes[8]
+	mi := &file_market_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -588,7 +5082,7 @@ func (x *GetPositionsRequest) String() string {
 func (*GetPositionsRequest) ProtoMessage() {}
 
 func (x *GetPositionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[8]
+	mi := &file_market_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -601,7 +5095,7 @@ func (x *GetPositionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPositionsRequest.ProtoReflect.Descriptor instead.
 func (*GetPositionsRequest) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{8}
+	return file_market_proto_rawDescGZIP(), []int{66}
 }
 
 func (x *GetPositionsRequest) GetUserId() string {
@@ -620,7 +5114,7 @@ type GetPositionsResponse struct {
 
 func (x *GetPositionsResponse) Reset() {
 	*x = GetPositionsResponse{}
-	mi := &file_market_proto_msgTypes[9]
+	mi := &file_market_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -632,7 +5126,7 @@ func (x *GetPositionsResponse) String() string {
 func (*GetPositionsResponse) ProtoMessage() {}
 
 func (x *GetPositionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[9]
+	mi := &file_market_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -645,7 +5139,7 @@ func (x *GetPositionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPositionsResponse.ProtoReflect.Descriptor instead.
 func (*GetPositionsResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{9}
+	return file_market_proto_rawDescGZIP(), []int{67}
 }
 
 func (x *GetPositionsResponse) GetPositions() []*PositionView {
@@ -670,7 +5164,7 @@ type ListGradeTransactionsRequest struct {
 
 func (x *ListGradeTransactionsRequest) Reset() {
 	*x = ListGradeTransactionsRequest{}
-	mi := &file_market_proto_msgTypes[10]
+	mi := &file_market_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -682,7 +5176,7 @@ func (x *ListGradeTransactionsRequest) String() string {
 func (*ListGradeTransactionsRequest) ProtoMessage() {}
 
 func (x *ListGradeTransactionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[10]
+	mi := &file_market_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -695,7 +5189,7 @@ func (x *ListGradeTransactionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListGradeTransactionsRequest.ProtoReflect.Descriptor instead.
 func (*ListGradeTransactionsRequest) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{10}
+	return file_market_proto_rawDescGZIP(), []int{68}
 }
 
 func (x *ListGradeTransactionsRequest) GetUserId() string {
@@ -756,7 +5250,7 @@ type ListGradeTransactionsResponse struct {
 
 func (x *ListGradeTransactionsResponse) Reset() {
 	*x = ListGradeTransactionsResponse{}
-	mi := &file_market_proto_msgTypes[11]
+	mi := &file_market_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -768,7 +5262,7 @@ func (x *ListGradeTransactionsResponse) String() string {
 func (*ListGradeTransactionsResponse) ProtoMessage() {}
 
 func (x *ListGradeTransactionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[11]
+	mi := &file_market_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -781,7 +5275,7 @@ func (x *ListGradeTransactionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListGradeTransactionsResponse.ProtoReflect.Descriptor instead.
 func (*ListGradeTransactionsResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{11}
+	return file_market_proto_rawDescGZIP(), []int{69}
 }
 
 func (x *ListGradeTransactionsResponse) GetTransactions() []*Transaction {
@@ -807,7 +5301,7 @@ type ListTransactionsRequest struct {
 
 func (x *ListTransactionsRequest) Reset() {
 	*x = ListTransactionsRequest{}
-	mi := &file_market_proto_msgTypes[12]
+	mi := &file_market_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -819,7 +5313,7 @@ func (x *ListTransactionsRequest) String() string {
 func (*ListTransactionsRequest) ProtoMessage() {}
 
 func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[12]
+	mi := &file_market_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -832,7 +5326,7 @@ func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTransactionsRequest.ProtoReflect.Descriptor instead.
 func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{12}
+	return file_market_proto_rawDescGZIP(), []int{70}
 }
 
 func (x *ListTransactionsRequest) GetUserId() string {
@@ -900,7 +5394,7 @@ type ListTransactionsResponse struct {
 
 func (x *ListTransactionsResponse) Reset() {
 	*x = ListTransactionsResponse{}
-	mi := &file_market_proto_msgTypes[13]
+	mi := &file_market_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -912,7 +5406,7 @@ func (x *ListTransactionsResponse) String() string {
 func (*ListTransactionsResponse) ProtoMessage() {}
 
 func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[13]
+	mi := &file_market_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -925,7 +5419,7 @@ func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTransactionsResponse.ProtoReflect.Descriptor instead.
 func (*ListTransactionsResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{13}
+	return file_market_proto_rawDescGZIP(), []int{71}
 }
 
 func (x *ListTransactionsResponse) GetTransactions() []*Transaction {
@@ -943,7 +5437,7 @@ type GetMarketMetricsRequest struct {
 
 func (x *GetMarketMetricsRequest) Reset() {
 	*x = GetMarketMetricsRequest{}
-	mi := &file_market_proto_msgTypes[14]
+	mi := &file_market_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -955,7 +5449,7 @@ func (x *GetMarketMetricsRequest) String() string {
 func (*GetMarketMetricsRequest) ProtoMessage() {}
 
 func (x *GetMarketMetricsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[14]
+	mi := &file_market_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -968,21 +5462,26 @@ func (x *GetMarketMetricsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMarketMetricsRequest.ProtoReflect.Descriptor instead.
 func (*GetMarketMetricsRequest) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{14}
+	return file_market_proto_rawDescGZIP(), []int{72}
 }
 
 type GetMarketMetricsResponse struct {
 	state             protoimpl.MessageState                 `protogen:"open.v1"`
 	TotalTransactions uint32                                 `protobuf:"varint,1,opt,name=total_transactions,json=totalTransactions,proto3" json:"total_transactions,omitempty"`
-	TotalVolume       float64                                `protobuf:"fixed64,2,opt,name=total_volume,json=totalVolume,proto3" json:"total_volume,omitempty"`
+	TotalVolume       string                                 `protobuf:"bytes,2,opt,name=total_volume,json=totalVolume,proto3" json:"total_volume,omitempty"`
 	TopProducts       []*GetMarketMetricsResponse_TopProduct `protobuf:"bytes,3,rep,name=top_products,json=topProducts,proto3" json:"top_products,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// Value of active trades converted into the caller's reporting currency; currencies
+	// without an FX rate are left out of total_value and listed in unconverted_currencies.
+	TotalValue            string   `protobuf:"bytes,4,opt,name=total_value,json=totalValue,proto3" json:"total_value,omitempty"`
+	Currency              string   `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	UnconvertedCurrencies []string `protobuf:"bytes,6,rep,name=unconverted_currencies,json=unconvertedCurrencies,proto3" json:"unconverted_currencies,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *GetMarketMetricsResponse) Reset() {
 	*x = GetMarketMetricsResponse{}
-	mi := &file_market_proto_msgTypes[15]
+	mi := &file_market_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -994,7 +5493,7 @@ func (x *GetMarketMetricsResponse) String() string {
 func (*GetMarketMetricsResponse) ProtoMessage() {}
 
 func (x *GetMarketMetricsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[15]
+	mi := &file_market_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1007,7 +5506,7 @@ func (x *GetMarketMetricsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMarketMetricsResponse.ProtoReflect.Descriptor instead.
 func (*GetMarketMetricsResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{15}
+	return file_market_proto_rawDescGZIP(), []int{73}
 }
 
 func (x *GetMarketMetricsResponse) GetTotalTransactions() uint32 {
@@ -1017,11 +5516,11 @@ func (x *GetMarketMetricsResponse) GetTotalTransactions() uint32 {
 	return 0
 }
 
-func (x *GetMarketMetricsResponse) GetTotalVolume() float64 {
+func (x *GetMarketMetricsResponse) GetTotalVolume() string {
 	if x != nil {
 		return x.TotalVolume
 	}
-	return 0
+	return ""
 }
 
 func (x *GetMarketMetricsResponse) GetTopProducts() []*GetMarketMetricsResponse_TopProduct {
@@ -1031,22 +5530,51 @@ func (x *GetMarketMetricsResponse) GetTopProducts() []*GetMarketMetricsResponse_
 	return nil
 }
 
+func (x *GetMarketMetricsResponse) GetTotalValue() string {
+	if x != nil {
+		return x.TotalValue
+	}
+	return ""
+}
+
+func (x *GetMarketMetricsResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetMarketMetricsResponse) GetUnconvertedCurrencies() []string {
+	if x != nil {
+		return x.UnconvertedCurrencies
+	}
+	return nil
+}
+
 type EnrichedHolding struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SpiceGradeId  string                 `protobuf:"bytes,1,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
 	ProductName   string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
 	GradeName     string                 `protobuf:"bytes,3,opt,name=grade_name,json=gradeName,proto3" json:"grade_name,omitempty"`
-	Quantity      float64                `protobuf:"fixed64,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	TotalCost     float64                `protobuf:"fixed64,5,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
-	RealizedPnl   float64                `protobuf:"fixed64,6,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
-	TodayPrice    float64                `protobuf:"fixed64,7,opt,name=today_price,json=todayPrice,proto3" json:"today_price,omitempty"`
+	Quantity      string                 `protobuf:"bytes,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	TotalCost     string                 `protobuf:"bytes,5,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	RealizedPnl   string                 `protobuf:"bytes,6,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	TodayPrice    string                 `protobuf:"bytes,7,opt,name=today_price,json=todayPrice,proto3" json:"today_price,omitempty"`
+	PriceDate     string                 `protobuf:"bytes,8,opt,name=price_date,json=priceDate,proto3" json:"price_date,omitempty"`
+	PriceSource   string                 `protobuf:"bytes,9,opt,name=price_source,json=priceSource,proto3" json:"price_source,omitempty"`
+	Stale         bool                   `protobuf:"varint,10,opt,name=stale,proto3" json:"stale,omitempty"`
+	Currency      string                 `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`           // the position's; amounts above are in it
+	FxRate        string                 `protobuf:"bytes,12,opt,name=fx_rate,json=fxRate,proto3" json:"fx_rate,omitempty"` // currency → the response's reporting_currency; empty when no rate is stored
+	Unit          string                 `protobuf:"bytes,13,opt,name=unit,proto3" json:"unit,omitempty"`                   // the grade's; quantity is in kilograms
+	KgPerUnit     string                 `protobuf:"bytes,14,opt,name=kg_per_unit,json=kgPerUnit,proto3" json:"kg_per_unit,omitempty"`
+	UnitQty       string                 `protobuf:"bytes,15,opt,name=unit_qty,json=unitQty,proto3" json:"unit_qty,omitempty"` // quantity in unit
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *EnrichedHolding) Reset() {
 	*x = EnrichedHolding{}
-	mi := &file_market_proto_msgTypes[16]
+	mi := &file_market_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1058,7 +5586,7 @@ func (x *EnrichedHolding) String() string {
 func (*EnrichedHolding) ProtoMessage() {}
 
 func (x *EnrichedHolding) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[16]
+	mi := &file_market_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1071,7 +5599,7 @@ func (x *EnrichedHolding) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EnrichedHolding.ProtoReflect.Descriptor instead.
 func (*EnrichedHolding) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{16}
+	return file_market_proto_rawDescGZIP(), []int{74}
 }
 
 func (x *EnrichedHolding) GetSpiceGradeId() string {
@@ -1095,32 +5623,88 @@ func (x *EnrichedHolding) GetGradeName() string {
 	return ""
 }
 
-func (x *EnrichedHolding) GetQuantity() float64 {
+func (x *EnrichedHolding) GetQuantity() string {
 	if x != nil {
 		return x.Quantity
 	}
-	return 0
+	return ""
 }
 
-func (x *EnrichedHolding) GetTotalCost() float64 {
+func (x *EnrichedHolding) GetTotalCost() string {
 	if x != nil {
 		return x.TotalCost
 	}
-	return 0
+	return ""
 }
 
-func (x *EnrichedHolding) GetRealizedPnl() float64 {
+func (x *EnrichedHolding) GetRealizedPnl() string {
 	if x != nil {
 		return x.RealizedPnl
 	}
-	return 0
+	return ""
 }
 
-func (x *EnrichedHolding) GetTodayPrice() float64 {
+func (x *EnrichedHolding) GetTodayPrice() string {
 	if x != nil {
 		return x.TodayPrice
 	}
-	return 0
+	return ""
+}
+
+func (x *EnrichedHolding) GetPriceDate() string {
+	if x != nil {
+		return x.PriceDate
+	}
+	return ""
+}
+
+func (x *EnrichedHolding) GetPriceSource() string {
+	if x != nil {
+		return x.PriceSource
+	}
+	return ""
+}
+
+func (x *EnrichedHolding) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *EnrichedHolding) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *EnrichedHolding) GetFxRate() string {
+	if x != nil {
+		return x.FxRate
+	}
+	return ""
+}
+
+func (x *EnrichedHolding) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *EnrichedHolding) GetKgPerUnit() string {
+	if x != nil {
+		return x.KgPerUnit
+	}
+	return ""
+}
+
+func (x *EnrichedHolding) GetUnitQty() string {
+	if x != nil {
+		return x.UnitQty
+	}
+	return ""
 }
 
 type GetHoldingsRequest struct {
@@ -1132,7 +5716,7 @@ type GetHoldingsRequest struct {
 
 func (x *GetHoldingsRequest) Reset() {
 	*x = GetHoldingsRequest{}
-	mi := &file_market_proto_msgTypes[17]
+	mi := &file_market_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1144,7 +5728,7 @@ func (x *GetHoldingsRequest) String() string {
 func (*GetHoldingsRequest) ProtoMessage() {}
 
 func (x *GetHoldingsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[17]
+	mi := &file_market_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1157,7 +5741,7 @@ func (x *GetHoldingsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetHoldingsRequest.ProtoReflect.Descriptor instead.
 func (*GetHoldingsRequest) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{17}
+	return file_market_proto_rawDescGZIP(), []int{75}
 }
 
 func (x *GetHoldingsRequest) GetUserId() string {
@@ -1168,15 +5752,16 @@ func (x *GetHoldingsRequest) GetUserId() string {
 }
 
 type GetHoldingsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Holdings      []*EnrichedHolding     `protobuf:"bytes,1,rep,name=holdings,proto3" json:"holdings,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Holdings          []*EnrichedHolding     `protobuf:"bytes,1,rep,name=holdings,proto3" json:"holdings,omitempty"`
+	ReportingCurrency string                 `protobuf:"bytes,2,opt,name=reporting_currency,json=reportingCurrency,proto3" json:"reporting_currency,omitempty"` // the account's
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *GetHoldingsResponse) Reset() {
 	*x = GetHoldingsResponse{}
-	mi := &file_market_proto_msgTypes[18]
+	mi := &file_market_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1188,7 +5773,7 @@ func (x *GetHoldingsResponse) String() string {
 func (*GetHoldingsResponse) ProtoMessage() {}
 
 func (x *GetHoldingsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[18]
+	mi := &file_market_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1201,7 +5786,7 @@ func (x *GetHoldingsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetHoldingsResponse.ProtoReflect.Descriptor instead.
 func (*GetHoldingsResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{18}
+	return file_market_proto_rawDescGZIP(), []int{76}
 }
 
 func (x *GetHoldingsResponse) GetHoldings() []*EnrichedHolding {
@@ -1211,10 +5796,17 @@ func (x *GetHoldingsResponse) GetHoldings() []*EnrichedHolding {
 	return nil
 }
 
+func (x *GetHoldingsResponse) GetReportingCurrency() string {
+	if x != nil {
+		return x.ReportingCurrency
+	}
+	return ""
+}
+
 type RealizedPnLRow struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD
-	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Amount        string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
 	SpiceGradeId  string                 `protobuf:"bytes,3,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
 	ProductName   string                 `protobuf:"bytes,4,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
 	GradeName     string                 `protobuf:"bytes,5,opt,name=grade_name,json=gradeName,proto3" json:"grade_name,omitempty"`
@@ -1224,7 +5816,7 @@ type RealizedPnLRow struct {
 
 func (x *RealizedPnLRow) Reset() {
 	*x = RealizedPnLRow{}
-	mi := &file_market_proto_msgTypes[19]
+	mi := &file_market_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1236,7 +5828,7 @@ func (x *RealizedPnLRow) String() string {
 func (*RealizedPnLRow) ProtoMessage() {}
 
 func (x *RealizedPnLRow) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[19]
+	mi := &file_market_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1249,7 +5841,7 @@ func (x *RealizedPnLRow) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RealizedPnLRow.ProtoReflect.Descriptor instead.
 func (*RealizedPnLRow) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{19}
+	return file_market_proto_rawDescGZIP(), []int{77}
 }
 
 func (x *RealizedPnLRow) GetDate() string {
@@ -1259,11 +5851,11 @@ func (x *RealizedPnLRow) GetDate() string {
 	return ""
 }
 
-func (x *RealizedPnLRow) GetAmount() float64 {
+func (x *RealizedPnLRow) GetAmount() string {
 	if x != nil {
 		return x.Amount
 	}
-	return 0
+	return ""
 }
 
 func (x *RealizedPnLRow) GetSpiceGradeId() string {
@@ -1297,7 +5889,7 @@ type GetRealizedPnLHistoryRequest struct {
 
 func (x *GetRealizedPnLHistoryRequest) Reset() {
 	*x = GetRealizedPnLHistoryRequest{}
-	mi := &file_market_proto_msgTypes[20]
+	mi := &file_market_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1309,7 +5901,7 @@ func (x *GetRealizedPnLHistoryRequest) String() string {
 func (*GetRealizedPnLHistoryRequest) ProtoMessage() {}
 
 func (x *GetRealizedPnLHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[20]
+	mi := &file_market_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1322,7 +5914,7 @@ func (x *GetRealizedPnLHistoryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRealizedPnLHistoryRequest.ProtoReflect.Descriptor instead.
 func (*GetRealizedPnLHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{20}
+	return file_market_proto_rawDescGZIP(), []int{78}
 }
 
 func (x *GetRealizedPnLHistoryRequest) GetUserId() string {
@@ -1348,7 +5940,7 @@ type GetRealizedPnLHistoryResponse struct {
 
 func (x *GetRealizedPnLHistoryResponse) Reset() {
 	*x = GetRealizedPnLHistoryResponse{}
-	mi := &file_market_proto_msgTypes[21]
+	mi := &file_market_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1360,7 +5952,7 @@ func (x *GetRealizedPnLHistoryResponse) String() string {
 func (*GetRealizedPnLHistoryResponse) ProtoMessage() {}
 
 func (x *GetRealizedPnLHistoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[21]
+	mi := &file_market_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1373,7 +5965,7 @@ func (x *GetRealizedPnLHistoryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRealizedPnLHistoryResponse.ProtoReflect.Descriptor instead.
 func (*GetRealizedPnLHistoryResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{21}
+	return file_market_proto_rawDescGZIP(), []int{79}
 }
 
 func (x *GetRealizedPnLHistoryResponse) GetRows() []*RealizedPnLRow {
@@ -1387,7 +5979,7 @@ type TradeActivityRow struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD
 	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // BUY or SELL
-	Quantity      float64                `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Quantity      string                 `protobuf:"bytes,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	Count         uint32                 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
 	SpiceGradeId  string                 `protobuf:"bytes,5,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
 	ProductName   string                 `protobuf:"bytes,6,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
@@ -1398,7 +5990,7 @@ type TradeActivityRow struct {
 
 func (x *TradeActivityRow) Reset() {
 	*x = TradeActivityRow{}
-	mi := &file_market_proto_msgTypes[22]
+	mi := &file_market_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1410,7 +6002,7 @@ func (x *TradeActivityRow) String() string {
 func (*TradeActivityRow) ProtoMessage() {}
 
 func (x *TradeActivityRow) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[22]
+	mi := &file_market_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1423,7 +6015,7 @@ func (x *TradeActivityRow) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TradeActivityRow.ProtoReflect.Descriptor instead.
 func (*TradeActivityRow) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{22}
+	return file_market_proto_rawDescGZIP(), []int{80}
 }
 
 func (x *TradeActivityRow) GetDate() string {
@@ -1440,11 +6032,11 @@ func (x *TradeActivityRow) GetType() string {
 	return ""
 }
 
-func (x *TradeActivityRow) GetQuantity() float64 {
+func (x *TradeActivityRow) GetQuantity() string {
 	if x != nil {
 		return x.Quantity
 	}
-	return 0
+	return ""
 }
 
 func (x *TradeActivityRow) GetCount() uint32 {
@@ -1485,7 +6077,7 @@ type GetTradeActivityRequest struct {
 
 func (x *GetTradeActivityRequest) Reset() {
 	*x = GetTradeActivityRequest{}
-	mi := &file_market_proto_msgTypes[23]
+	mi := &file_market_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1497,7 +6089,7 @@ func (x *GetTradeActivityRequest) String() string {
 func (*GetTradeActivityRequest) ProtoMessage() {}
 
 func (x *GetTradeActivityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[23]
+	mi := &file_market_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1510,7 +6102,7 @@ func (x *GetTradeActivityRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTradeActivityRequest.ProtoReflect.Descriptor instead.
 func (*GetTradeActivityRequest) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{23}
+	return file_market_proto_rawDescGZIP(), []int{81}
 }
 
 func (x *GetTradeActivityRequest) GetUserId() string {
@@ -1536,7 +6128,7 @@ type GetTradeActivityResponse struct {
 
 func (x *GetTradeActivityResponse) Reset() {
 	*x = GetTradeActivityResponse{}
-	mi := &file_market_proto_msgTypes[24]
+	mi := &file_market_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1548,7 +6140,7 @@ func (x *GetTradeActivityResponse) String() string {
 func (*GetTradeActivityResponse) ProtoMessage() {}
 
 func (x *GetTradeActivityResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[24]
+	mi := &file_market_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1561,7 +6153,7 @@ func (x *GetTradeActivityResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTradeActivityResponse.ProtoReflect.Descriptor instead.
 func (*GetTradeActivityResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{24}
+	return file_market_proto_rawDescGZIP(), []int{82}
 }
 
 func (x *GetTradeActivityResponse) GetRows() []*TradeActivityRow {
@@ -1581,7 +6173,7 @@ type GetTradeStatsRequest struct {
 
 func (x *GetTradeStatsRequest) Reset() {
 	*x = GetTradeStatsRequest{}
-	mi := &file_market_proto_msgTypes[25]
+	mi := &file_market_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1593,7 +6185,7 @@ func (x *GetTradeStatsRequest) String() string {
 func (*GetTradeStatsRequest) ProtoMessage() {}
 
 func (x *GetTradeStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[25]
+	mi := &file_market_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1606,7 +6198,7 @@ func (x *GetTradeStatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTradeStatsRequest.ProtoReflect.Descriptor instead.
 func (*GetTradeStatsRequest) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{25}
+	return file_market_proto_rawDescGZIP(), []int{83}
 }
 
 func (x *GetTradeStatsRequest) GetUserId() string {
@@ -1626,15 +6218,15 @@ func (x *GetTradeStatsRequest) GetDays() uint32 {
 type GetTradeStatsResponse struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
 	TradesInPeriod     uint32                 `protobuf:"varint,1,opt,name=trades_in_period,json=tradesInPeriod,proto3" json:"trades_in_period,omitempty"`
-	BuyVolumeInPeriod  float64                `protobuf:"fixed64,2,opt,name=buy_volume_in_period,json=buyVolumeInPeriod,proto3" json:"buy_volume_in_period,omitempty"`
-	SellVolumeInPeriod float64                `protobuf:"fixed64,3,opt,name=sell_volume_in_period,json=sellVolumeInPeriod,proto3" json:"sell_volume_in_period,omitempty"`
+	BuyVolumeInPeriod  string                 `protobuf:"bytes,2,opt,name=buy_volume_in_period,json=buyVolumeInPeriod,proto3" json:"buy_volume_in_period,omitempty"`
+	SellVolumeInPeriod string                 `protobuf:"bytes,3,opt,name=sell_volume_in_period,json=sellVolumeInPeriod,proto3" json:"sell_volume_in_period,omitempty"`
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
 
 func (x *GetTradeStatsResponse) Reset() {
 	*x = GetTradeStatsResponse{}
-	mi := &file_market_proto_msgTypes[26]
+	mi := &file_market_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1646,7 +6238,7 @@ func (x *GetTradeStatsResponse) String() string {
 func (*GetTradeStatsResponse) ProtoMessage() {}
 
 func (x *GetTradeStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[26]
+	mi := &file_market_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1659,7 +6251,7 @@ func (x *GetTradeStatsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTradeStatsResponse.ProtoReflect.Descriptor instead.
 func (*GetTradeStatsResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{26}
+	return file_market_proto_rawDescGZIP(), []int{84}
 }
 
 func (x *GetTradeStatsResponse) GetTradesInPeriod() uint32 {
@@ -1669,18 +6261,18 @@ func (x *GetTradeStatsResponse) GetTradesInPeriod() uint32 {
 	return 0
 }
 
-func (x *GetTradeStatsResponse) GetBuyVolumeInPeriod() float64 {
+func (x *GetTradeStatsResponse) GetBuyVolumeInPeriod() string {
 	if x != nil {
 		return x.BuyVolumeInPeriod
 	}
-	return 0
+	return ""
 }
 
-func (x *GetTradeStatsResponse) GetSellVolumeInPeriod() float64 {
+func (x *GetTradeStatsResponse) GetSellVolumeInPeriod() string {
 	if x != nil {
 		return x.SellVolumeInPeriod
 	}
-	return 0
+	return ""
 }
 
 type PriceSnapshot struct {
@@ -1688,15 +6280,19 @@ type PriceSnapshot struct {
 	SpiceGradeId  string                 `protobuf:"bytes,1,opt,name=spice_grade_id,json=spiceGradeId,proto3" json:"spice_grade_id,omitempty"`
 	ProductName   string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
 	GradeName     string                 `protobuf:"bytes,3,opt,name=grade_name,json=gradeName,proto3" json:"grade_name,omitempty"`
-	TodayPrice    float64                `protobuf:"fixed64,4,opt,name=today_price,json=todayPrice,proto3" json:"today_price,omitempty"`
-	PreviousPrice float64                `protobuf:"fixed64,5,opt,name=previous_price,json=previousPrice,proto3" json:"previous_price,omitempty"`
+	TodayPrice    string                 `protobuf:"bytes,4,opt,name=today_price,json=todayPrice,proto3" json:"today_price,omitempty"`
+	PreviousPrice string                 `protobuf:"bytes,5,opt,name=previous_price,json=previousPrice,proto3" json:"previous_price,omitempty"`
+	PriceDate     string                 `protobuf:"bytes,6,opt,name=price_date,json=priceDate,proto3" json:"price_date,omitempty"`
+	PriceSource   string                 `protobuf:"bytes,7,opt,name=price_source,json=priceSource,proto3" json:"price_source,omitempty"`
+	Stale         bool                   `protobuf:"varint,8,opt,name=stale,proto3" json:"stale,omitempty"`
+	Currency      string                 `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"` // the position's; both prices are converted into it
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PriceSnapshot) Reset() {
 	*x = PriceSnapshot{}
-	mi := &file_market_proto_msgTypes[27]
+	mi := &file_market_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1708,7 +6304,7 @@ func (x *PriceSnapshot) String() string {
 func (*PriceSnapshot) ProtoMessage() {}
 
 func (x *PriceSnapshot) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[27]
+	mi := &file_market_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1721,7 +6317,7 @@ func (x *PriceSnapshot) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PriceSnapshot.ProtoReflect.Descriptor instead.
 func (*PriceSnapshot) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{27}
+	return file_market_proto_rawDescGZIP(), []int{85}
 }
 
 func (x *PriceSnapshot) GetSpiceGradeId() string {
@@ -1745,18 +6341,46 @@ func (x *PriceSnapshot) GetGradeName() string {
 	return ""
 }
 
-func (x *PriceSnapshot) GetTodayPrice() float64 {
+func (x *PriceSnapshot) GetTodayPrice() string {
 	if x != nil {
 		return x.TodayPrice
 	}
-	return 0
+	return ""
 }
 
-func (x *PriceSnapshot) GetPreviousPrice() float64 {
+func (x *PriceSnapshot) GetPreviousPrice() string {
 	if x != nil {
 		return x.PreviousPrice
 	}
-	return 0
+	return ""
+}
+
+func (x *PriceSnapshot) GetPriceDate() string {
+	if x != nil {
+		return x.PriceDate
+	}
+	return ""
+}
+
+func (x *PriceSnapshot) GetPriceSource() string {
+	if x != nil {
+		return x.PriceSource
+	}
+	return ""
+}
+
+func (x *PriceSnapshot) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *PriceSnapshot) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
 }
 
 type GetPriceSnapshotsRequest struct {
@@ -1768,7 +6392,7 @@ type GetPriceSnapshotsRequest struct {
 
 func (x *GetPriceSnapshotsRequest) Reset() {
 	*x = GetPriceSnapshotsRequest{}
-	mi := &file_market_proto_msgTypes[28]
+	mi := &file_market_proto_msgTypes[86]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1780,7 +6404,7 @@ func (x *GetPriceSnapshotsRequest) String() string {
 func (*GetPriceSnapshotsRequest) ProtoMessage() {}
 
 func (x *GetPriceSnapshotsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[28]
+	mi := &file_market_proto_msgTypes[86]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1793,7 +6417,7 @@ func (x *GetPriceSnapshotsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPriceSnapshotsRequest.ProtoReflect.Descriptor instead.
 func (*GetPriceSnapshotsRequest) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{28}
+	return file_market_proto_rawDescGZIP(), []int{86}
 }
 
 func (x *GetPriceSnapshotsRequest) GetUserId() string {
@@ -1812,7 +6436,7 @@ type GetPriceSnapshotsResponse struct {
 
 func (x *GetPriceSnapshotsResponse) Reset() {
 	*x = GetPriceSnapshotsResponse{}
-	mi := &file_market_proto_msgTypes[29]
+	mi := &file_market_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1824,7 +6448,7 @@ func (x *GetPriceSnapshotsResponse) String() string {
 func (*GetPriceSnapshotsResponse) ProtoMessage() {}
 
 func (x *GetPriceSnapshotsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[29]
+	mi := &file_market_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1837,7 +6461,7 @@ func (x *GetPriceSnapshotsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPriceSnapshotsResponse.ProtoReflect.Descriptor instead.
 func (*GetPriceSnapshotsResponse) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{29}
+	return file_market_proto_rawDescGZIP(), []int{87}
 }
 
 func (x *GetPriceSnapshotsResponse) GetSnapshots() []*PriceSnapshot {
@@ -1851,14 +6475,14 @@ type GetMarketMetricsResponse_TopProduct struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductName   string                 `protobuf:"bytes,1,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
 	GradeName     string                 `protobuf:"bytes,2,opt,name=grade_name,json=gradeName,proto3" json:"grade_name,omitempty"`
-	Volume        float64                `protobuf:"fixed64,3,opt,name=volume,proto3" json:"volume,omitempty"`
+	Volume        string                 `protobuf:"bytes,3,opt,name=volume,proto3" json:"volume,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetMarketMetricsResponse_TopProduct) Reset() {
 	*x = GetMarketMetricsResponse_TopProduct{}
-	mi := &file_market_proto_msgTypes[30]
+	mi := &file_market_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1870,7 +6494,7 @@ func (x *GetMarketMetricsResponse_TopProduct) String() string {
 func (*GetMarketMetricsResponse_TopProduct) ProtoMessage() {}
 
 func (x *GetMarketMetricsResponse_TopProduct) ProtoReflect() protoreflect.Message {
-	mi := &file_market_proto_msgTypes[30]
+	mi := &file_market_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1883,7 +6507,7 @@ func (x *GetMarketMetricsResponse_TopProduct) ProtoReflect() protoreflect.Messag
 
 // Deprecated: Use GetMarketMetricsResponse_TopProduct.ProtoReflect.Descriptor instead.
 func (*GetMarketMetricsResponse_TopProduct) Descriptor() ([]byte, []int) {
-	return file_market_proto_rawDescGZIP(), []int{15, 0}
+	return file_market_proto_rawDescGZIP(), []int{73, 0}
 }
 
 func (x *GetMarketMetricsResponse_TopProduct) GetProductName() string {
@@ -1900,61 +6524,455 @@ func (x *GetMarketMetricsResponse_TopProduct) GetGradeName() string {
 	return ""
 }
 
-func (x *GetMarketMetricsResponse_TopProduct) GetVolume() float64 {
+func (x *GetMarketMetricsResponse_TopProduct) GetVolume() string {
 	if x != nil {
 		return x.Volume
 	}
-	return 0
+	return ""
 }
 
 var File_market_proto protoreflect.FileDescriptor
 
 const file_market_proto_rawDesc = "" +
 	"\n" +
-	"\fmarket.proto\x12\x02pb\"\xe0\x01\n" +
+	"\fmarket.proto\x12\x02pb\"\x86\x04\n" +
 	"\vTransaction\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12$\n" +
 	"\x0espice_grade_id\x18\x03 \x01(\tR\fspiceGradeId\x12\x12\n" +
 	"\x04type\x18\x04 \x01(\tR\x04type\x12\x1a\n" +
-	"\bquantity\x18\x05 \x01(\x01R\bquantity\x12\x14\n" +
-	"\x05price\x18\x06 \x01(\x01R\x05price\x12\x1d\n" +
+	"\bquantity\x18\x05 \x01(\tR\bquantity\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\tR\x05price\x12\x1d\n" +
 	"\n" +
 	"trade_date\x18\a \x01(\tR\ttradeDate\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\b \x01(\tR\tcreatedAt\"\xae\x02\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12*\n" +
+	"\x11cost_basis_method\x18\t \x01(\tR\x0fcostBasisMethod\x12\x16\n" +
+	"\x06status\x18\n" +
+	" \x01(\tR\x06status\x126\n" +
+	"\x17reverses_transaction_id\x18\v \x01(\tR\x15reversesTransactionId\x122\n" +
+	"\x15amends_transaction_id\x18\f \x01(\tR\x13amendsTransactionId\x12\x12\n" +
+	"\x04note\x18\r \x01(\tR\x04note\x12'\n" +
+	"\x0fidempotency_key\x18\x0e \x01(\tR\x0eidempotencyKey\x12\x1a\n" +
+	"\bcurrency\x18\x0f \x01(\tR\bcurrency\x12\x1b\n" +
+	"\tfee_total\x18\x10 \x01(\tR\bfeeTotal\"\xf5\x01\n" +
+	"\x0eTransactionFee\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\x12&\n" +
+	"\x0ffee_schedule_id\x18\x03 \x01(\tR\rfeeScheduleId\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\tR\x04code\x12\x12\n" +
+	"\x04kind\x18\x05 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05basis\x18\x06 \x01(\tR\x05basis\x12\x12\n" +
+	"\x04rate\x18\a \x01(\tR\x04rate\x12\x16\n" +
+	"\x06amount\x18\b \x01(\tR\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\"\\\n" +
+	"\x1aListTransactionFeesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\"E\n" +
+	"\x1bListTransactionFeesResponse\x12&\n" +
+	"\x04fees\x18\x01 \x03(\v2\x12.pb.TransactionFeeR\x04fees\"\xdb\x05\n" +
 	"\fPositionView\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
 	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12\x1b\n" +
-	"\ttotal_qty\x18\x03 \x01(\x01R\btotalQty\x12\x1d\n" +
+	"\ttotal_qty\x18\x03 \x01(\tR\btotalQty\x12\x1d\n" +
 	"\n" +
-	"total_cost\x18\x04 \x01(\x01R\ttotalCost\x12\x19\n" +
-	"\bavg_cost\x18\x05 \x01(\x01R\aavgCost\x12\x1f\n" +
-	"\vtoday_price\x18\x06 \x01(\x01R\n" +
+	"total_cost\x18\x04 \x01(\tR\ttotalCost\x12\x19\n" +
+	"\bavg_cost\x18\x05 \x01(\tR\aavgCost\x12\x1f\n" +
+	"\vtoday_price\x18\x06 \x01(\tR\n" +
 	"todayPrice\x12!\n" +
-	"\frealized_pnl\x18\a \x01(\x01R\vrealizedPnl\x12%\n" +
-	"\x0eunrealized_pnl\x18\b \x01(\x01R\runrealizedPnl\x12\x1d\n" +
+	"\frealized_pnl\x18\a \x01(\tR\vrealizedPnl\x12%\n" +
+	"\x0eunrealized_pnl\x18\b \x01(\tR\runrealizedPnl\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\t \x01(\tR\tupdatedAt\"\x9c\x01\n" +
+	"updated_at\x18\t \x01(\tR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"price_date\x18\n" +
+	" \x01(\tR\tpriceDate\x12!\n" +
+	"\fprice_source\x18\v \x01(\tR\vpriceSource\x12\x14\n" +
+	"\x05stale\x18\f \x01(\bR\x05stale\x12\x1a\n" +
+	"\bcurrency\x18\r \x01(\tR\bcurrency\x12-\n" +
+	"\x12reporting_currency\x18\x0e \x01(\tR\x11reportingCurrency\x12\x17\n" +
+	"\afx_rate\x18\x0f \x01(\tR\x06fxRate\x120\n" +
+	"\x14reporting_total_cost\x18\x10 \x01(\tR\x12reportingTotalCost\x124\n" +
+	"\x16reporting_realized_pnl\x18\x11 \x01(\tR\x14reportingRealizedPnl\x128\n" +
+	"\x18reporting_unrealized_pnl\x18\x12 \x01(\tR\x16reportingUnrealizedPnl\x12\x12\n" +
+	"\x04unit\x18\x13 \x01(\tR\x04unit\x12\x1e\n" +
+	"\vkg_per_unit\x18\x14 \x01(\tR\tkgPerUnit\x12\x19\n" +
+	"\bunit_qty\x18\x15 \x01(\tR\aunitQty\"\xd9\x01\n" +
 	"\n" +
 	"BuyRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
 	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x01R\bquantity\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1d\n" +
+	"\bquantity\x18\x03 \x01(\tR\bquantity\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\tR\x05price\x12\x1d\n" +
 	"\n" +
-	"trade_date\x18\x05 \x01(\tR\ttradeDate\"@\n" +
+	"trade_date\x18\x05 \x01(\tR\ttradeDate\x12'\n" +
+	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\x12\x12\n" +
+	"\x04unit\x18\a \x01(\tR\x04unit\"@\n" +
 	"\vBuyResponse\x121\n" +
-	"\vtransaction\x18\x01 \x01(\v2\x0f.pb.TransactionR\vtransaction\"\x9d\x01\n" +
+	"\vtransaction\x18\x01 \x01(\v2\x0f.pb.TransactionR\vtransaction\"A\n" +
+	"\fLotSelection\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\tR\x05lotId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\tR\bquantity\"\xac\x02\n" +
 	"\vSellRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
 	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x01R\bquantity\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1d\n" +
+	"\bquantity\x18\x03 \x01(\tR\bquantity\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\tR\x05price\x12\x1d\n" +
 	"\n" +
-	"trade_date\x18\x05 \x01(\tR\ttradeDate\"A\n" +
+	"trade_date\x18\x05 \x01(\tR\ttradeDate\x12*\n" +
+	"\x11cost_basis_method\x18\x06 \x01(\tR\x0fcostBasisMethod\x12$\n" +
+	"\x04lots\x18\a \x03(\v2\x10.pb.LotSelectionR\x04lots\x12'\n" +
+	"\x0fidempotency_key\x18\b \x01(\tR\x0eidempotencyKey\x12\x12\n" +
+	"\x04unit\x18\t \x01(\tR\x04unit\"A\n" +
 	"\fSellResponse\x121\n" +
-	"\vtransaction\x18\x01 \x01(\v2\x0f.pb.TransactionR\vtransaction\"X\n" +
+	"\vtransaction\x18\x01 \x01(\v2\x0f.pb.TransactionR\vtransaction\"\x92\x01\n" +
+	"\x18CancelTransactionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1e\n" +
+	"\n" +
+	"reallocate\x18\x04 \x01(\bR\n" +
+	"reallocate\"\xa7\x01\n" +
+	"\x19CancelTransactionResponse\x12+\n" +
+	"\boriginal\x18\x01 \x01(\v2\x0f.pb.TransactionR\boriginal\x12+\n" +
+	"\breversal\x18\x02 \x01(\v2\x0f.pb.TransactionR\breversal\x120\n" +
+	"\x14reallocated_sell_ids\x18\x03 \x03(\tR\x12reallocatedSellIds\"\xb4\x02\n" +
+	"\x17AmendTransactionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\tR\bquantity\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\tR\x05price\x12\x1d\n" +
+	"\n" +
+	"trade_date\x18\x05 \x01(\tR\ttradeDate\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x1e\n" +
+	"\n" +
+	"reallocate\x18\a \x01(\bR\n" +
+	"reallocate\x12*\n" +
+	"\x11cost_basis_method\x18\b \x01(\tR\x0fcostBasisMethod\x12$\n" +
+	"\x04lots\x18\t \x03(\v2\x10.pb.LotSelectionR\x04lots\"\xd9\x01\n" +
+	"\x18AmendTransactionResponse\x121\n" +
+	"\vtransaction\x18\x01 \x01(\v2\x0f.pb.TransactionR\vtransaction\x12+\n" +
+	"\boriginal\x18\x02 \x01(\v2\x0f.pb.TransactionR\boriginal\x12+\n" +
+	"\breversal\x18\x03 \x01(\v2\x0f.pb.TransactionR\breversal\x120\n" +
+	"\x14reallocated_sell_ids\x18\x04 \x03(\tR\x12reallocatedSellIds\"\x8b\x01\n" +
+	"\x0ePositionTotals\x12\x1b\n" +
+	"\ttotal_qty\x18\x01 \x01(\tR\btotalQty\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x02 \x01(\tR\ttotalCost\x12!\n" +
+	"\frealized_pnl\x18\x03 \x01(\tR\vrealizedPnl\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"\xc4\x01\n" +
+	"\rPositionDrift\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12*\n" +
+	"\x06stored\x18\x03 \x01(\v2\x12.pb.PositionTotalsR\x06stored\x12.\n" +
+	"\bexpected\x18\x04 \x01(\v2\x12.pb.PositionTotalsR\bexpected\x12\x18\n" +
+	"\amissing\x18\x05 \x01(\bR\amissing\"\xd0\x01\n" +
+	"\bLotDrift\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\tR\x05lotId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x03 \x01(\tR\fspiceGradeId\x12)\n" +
+	"\x10stored_remaining\x18\x04 \x01(\tR\x0fstoredRemaining\x12-\n" +
+	"\x12expected_remaining\x18\x05 \x01(\tR\x11expectedRemaining\x12\x14\n" +
+	"\x05short\x18\x06 \x01(\bR\x05short\"q\n" +
+	"\x16ReconcileLedgerRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12\x18\n" +
+	"\arebuild\x18\x03 \x01(\bR\arebuild\"\xd6\x01\n" +
+	"\x17ReconcileLedgerResponse\x12+\n" +
+	"\x11positions_checked\x18\x01 \x01(\rR\x10positionsChecked\x12!\n" +
+	"\flots_checked\x18\x02 \x01(\rR\vlotsChecked\x12/\n" +
+	"\tpositions\x18\x03 \x03(\v2\x11.pb.PositionDriftR\tpositions\x12 \n" +
+	"\x04lots\x18\x04 \x03(\v2\f.pb.LotDriftR\x04lots\x12\x18\n" +
+	"\arebuilt\x18\x05 \x01(\bR\arebuilt\"\x9a\x02\n" +
+	"\x06BuyLot\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x04 \x01(\tR\fspiceGradeId\x12!\n" +
+	"\foriginal_qty\x18\x05 \x01(\tR\voriginalQty\x12#\n" +
+	"\rremaining_qty\x18\x06 \x01(\tR\fremainingQty\x12\x14\n" +
+	"\x05price\x18\a \x01(\tR\x05price\x12\x1d\n" +
+	"\n" +
+	"trade_date\x18\b \x01(\tR\ttradeDate\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"\xd8\x03\n" +
+	"\x0eSellAllocation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
+	"\x13sell_transaction_id\x18\x02 \x01(\tR\x11sellTransactionId\x12\x1c\n" +
+	"\n" +
+	"buy_lot_id\x18\x03 \x01(\tR\bbuyLotId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x05 \x01(\tR\fspiceGradeId\x12\x1a\n" +
+	"\bquantity\x18\x06 \x01(\tR\bquantity\x12\x1b\n" +
+	"\tbuy_price\x18\a \x01(\tR\bbuyPrice\x12\x1d\n" +
+	"\n" +
+	"sell_price\x18\b \x01(\tR\tsellPrice\x12!\n" +
+	"\frealized_pnl\x18\t \x01(\tR\vrealizedPnl\x12*\n" +
+	"\x11cost_basis_method\x18\n" +
+	" \x01(\tR\x0fcostBasisMethod\x12&\n" +
+	"\x0fsell_trade_date\x18\v \x01(\tR\rsellTradeDate\x12;\n" +
+	"\x1areversed_by_transaction_id\x18\f \x01(\tR\x17reversedByTransactionId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\r \x01(\tR\tcreatedAt\"\xc6\x01\n" +
+	"\x13ListOpenLotsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12\x12\n" +
+	"\x04skip\x18\x03 \x01(\rR\x04skip\x12\x12\n" +
+	"\x04take\x18\x04 \x01(\rR\x04take\x12\x12\n" +
+	"\x04sort\x18\x05 \x01(\tR\x04sort\x12\x1b\n" +
+	"\tdate_from\x18\x06 \x01(\tR\bdateFrom\x12\x17\n" +
+	"\adate_to\x18\a \x01(\tR\x06dateTo\"6\n" +
+	"\x14ListOpenLotsResponse\x12\x1e\n" +
+	"\x04lots\x18\x01 \x03(\v2\n" +
+	".pb.BuyLotR\x04lots\"\xa4\x01\n" +
+	"\x14GetLotHistoryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x15\n" +
+	"\x06lot_id\x18\x02 \x01(\tR\x05lotId\x12\x12\n" +
+	"\x04skip\x18\x03 \x01(\rR\x04skip\x12\x12\n" +
+	"\x04take\x18\x04 \x01(\rR\x04take\x12\x1b\n" +
+	"\tdate_from\x18\x05 \x01(\tR\bdateFrom\x12\x17\n" +
+	"\adate_to\x18\x06 \x01(\tR\x06dateTo\"k\n" +
+	"\x15GetLotHistoryResponse\x12\x1c\n" +
+	"\x03lot\x18\x01 \x01(\v2\n" +
+	".pb.BuyLotR\x03lot\x124\n" +
+	"\vallocations\x18\x02 \x03(\v2\x12.pb.SellAllocationR\vallocations\"\x93\x02\n" +
+	"\x19GetSellAllocationsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12.\n" +
+	"\x13sell_transaction_id\x18\x02 \x01(\tR\x11sellTransactionId\x12$\n" +
+	"\x0espice_grade_id\x18\x03 \x01(\tR\fspiceGradeId\x12\x12\n" +
+	"\x04skip\x18\x04 \x01(\rR\x04skip\x12\x12\n" +
+	"\x04take\x18\x05 \x01(\rR\x04take\x12\x1b\n" +
+	"\tdate_from\x18\x06 \x01(\tR\bdateFrom\x12\x17\n" +
+	"\adate_to\x18\a \x01(\tR\x06dateTo\x12)\n" +
+	"\x10include_reversed\x18\b \x01(\bR\x0fincludeReversed\"R\n" +
+	"\x1aGetSellAllocationsResponse\x124\n" +
+	"\vallocations\x18\x01 \x03(\v2\x12.pb.SellAllocationR\vallocations\"h\n" +
+	"\fAgeingBucket\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\tR\bquantity\x12\x12\n" +
+	"\x04cost\x18\x03 \x01(\tR\x04cost\x12\x12\n" +
+	"\x04lots\x18\x04 \x01(\rR\x04lots\"\xc5\x02\n" +
+	"\vGradeAgeing\x12$\n" +
+	"\x0espice_grade_id\x18\x01 \x01(\tR\fspiceGradeId\x12!\n" +
+	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1d\n" +
+	"\n" +
+	"grade_name\x18\x03 \x01(\tR\tgradeName\x12&\n" +
+	"\x0fshelf_life_days\x18\x04 \x01(\rR\rshelfLifeDays\x12*\n" +
+	"\abuckets\x18\x05 \x03(\v2\x10.pb.AgeingBucketR\abuckets\x12&\n" +
+	"\x0fnear_expiry_qty\x18\x06 \x01(\tR\rnearExpiryQty\x12(\n" +
+	"\x10near_expiry_lots\x18\a \x01(\rR\x0enearExpiryLots\x12(\n" +
+	"\x10next_expiry_date\x18\b \x01(\tR\x0enextExpiryDate\"C\n" +
+	"\x13GetLotAgeingRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x13\n" +
+	"\x05as_of\x18\x02 \x01(\tR\x04asOf\"\x80\x01\n" +
+	"\x14GetLotAgeingResponse\x12\x13\n" +
+	"\x05as_of\x18\x01 \x01(\tR\x04asOf\x12*\n" +
+	"\abuckets\x18\x02 \x03(\v2\x10.pb.AgeingBucketR\abuckets\x12'\n" +
+	"\x06grades\x18\x03 \x03(\v2\x0f.pb.GradeAgeingR\x06grades\"\xc9\x05\n" +
+	"\fRealizedGain\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x19\n" +
+	"\bmatch_id\x18\x02 \x01(\tR\amatchId\x12.\n" +
+	"\x13sell_transaction_id\x18\x03 \x01(\tR\x11sellTransactionId\x12,\n" +
+	"\x12buy_transaction_id\x18\x04 \x01(\tR\x10buyTransactionId\x12\x15\n" +
+	"\x06lot_id\x18\x05 \x01(\tR\x05lotId\x12$\n" +
+	"\x0espice_grade_id\x18\x06 \x01(\tR\fspiceGradeId\x12!\n" +
+	"\fproduct_name\x18\a \x01(\tR\vproductName\x12\x1d\n" +
+	"\n" +
+	"grade_name\x18\b \x01(\tR\tgradeName\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\x12*\n" +
+	"\x11cost_basis_method\x18\n" +
+	" \x01(\tR\x0fcostBasisMethod\x12\x1a\n" +
+	"\bquantity\x18\v \x01(\tR\bquantity\x12\x1b\n" +
+	"\tbuy_price\x18\f \x01(\tR\bbuyPrice\x12\x1d\n" +
+	"\n" +
+	"sell_price\x18\r \x01(\tR\tsellPrice\x12#\n" +
+	"\racquired_date\x18\x0e \x01(\tR\facquiredDate\x12#\n" +
+	"\rdisposed_date\x18\x0f \x01(\tR\fdisposedDate\x12#\n" +
+	"\rrealized_date\x18\x10 \x01(\tR\frealizedDate\x12!\n" +
+	"\fholding_days\x18\x11 \x01(\rR\vholdingDays\x12#\n" +
+	"\rholding_class\x18\x12 \x01(\tR\fholdingClass\x12\x12\n" +
+	"\x04cost\x18\x13 \x01(\tR\x04cost\x12\x1a\n" +
+	"\bproceeds\x18\x14 \x01(\tR\bproceeds\x12\x12\n" +
+	"\x04fees\x18\x15 \x01(\tR\x04fees\x12\x12\n" +
+	"\x04gain\x18\x16 \x01(\tR\x04gain\"\xc2\x02\n" +
+	"\rGainsSubtotal\x12$\n" +
+	"\x0espice_grade_id\x18\x01 \x01(\tR\fspiceGradeId\x12!\n" +
+	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1d\n" +
+	"\n" +
+	"grade_name\x18\x03 \x01(\tR\tgradeName\x12#\n" +
+	"\rholding_class\x18\x04 \x01(\tR\fholdingClass\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bquantity\x18\x06 \x01(\tR\bquantity\x12\x12\n" +
+	"\x04cost\x18\a \x01(\tR\x04cost\x12\x1a\n" +
+	"\bproceeds\x18\b \x01(\tR\bproceeds\x12\x12\n" +
+	"\x04fees\x18\t \x01(\tR\x04fees\x12\x12\n" +
+	"\x04gain\x18\n" +
+	" \x01(\tR\x04gain\x12\x14\n" +
+	"\x05lines\x18\v \x01(\rR\x05lines\"\xe1\x01\n" +
+	"\x1dGetRealizedGainsReportRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x0efinancial_year\x18\x02 \x01(\tR\rfinancialYear\x12\x1b\n" +
+	"\tdate_from\x18\x03 \x01(\tR\bdateFrom\x12\x17\n" +
+	"\adate_to\x18\x04 \x01(\tR\x06dateTo\x12$\n" +
+	"\x0espice_grade_id\x18\x05 \x01(\tR\fspiceGradeId\x12$\n" +
+	"\x0elong_term_days\x18\x06 \x01(\rR\flongTermDays\"\xcf\x02\n" +
+	"\x1eGetRealizedGainsReportResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdate_from\x18\x02 \x01(\tR\bdateFrom\x12\x17\n" +
+	"\adate_to\x18\x03 \x01(\tR\x06dateTo\x12$\n" +
+	"\x0elong_term_days\x18\x04 \x01(\rR\flongTermDays\x12&\n" +
+	"\x05gains\x18\x05 \x03(\v2\x10.pb.RealizedGainR\x05gains\x12)\n" +
+	"\x06grades\x18\x06 \x03(\v2\x11.pb.GainsSubtotalR\x06grades\x12:\n" +
+	"\x0fholding_classes\x18\a \x03(\v2\x11.pb.GainsSubtotalR\x0eholdingClasses\x12)\n" +
+	"\x06totals\x18\b \x03(\v2\x11.pb.GainsSubtotalR\x06totals\"\x99\x02\n" +
+	"\x13ExportLedgerRequest\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x03 \x01(\tR\fspiceGradeId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x04 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04sort\x18\x05 \x01(\tR\x04sort\x12\x1b\n" +
+	"\tdate_from\x18\x06 \x01(\tR\bdateFrom\x12\x17\n" +
+	"\adate_to\x18\a \x01(\tR\x06dateTo\x12\x1b\n" +
+	"\topen_only\x18\b \x01(\bR\bopenOnly\x12)\n" +
+	"\x10include_reversed\x18\t \x01(\bR\x0fincludeReversed\"\x98\x02\n" +
+	"\x0fExportLedgerRow\x12!\n" +
+	"\fproduct_name\x18\x01 \x01(\tR\vproductName\x12\x1d\n" +
+	"\n" +
+	"grade_name\x18\x02 \x01(\tR\tgradeName\x123\n" +
+	"\vtransaction\x18\x03 \x01(\v2\x0f.pb.TransactionH\x00R\vtransaction\x12\x1e\n" +
+	"\x03lot\x18\x04 \x01(\v2\n" +
+	".pb.BuyLotH\x00R\x03lot\x124\n" +
+	"\n" +
+	"allocation\x18\x05 \x01(\v2\x12.pb.SellAllocationH\x00R\n" +
+	"allocation\x12.\n" +
+	"\bposition\x18\x06 \x01(\v2\x10.pb.PositionViewH\x00R\bpositionB\b\n" +
+	"\x06record\"\xa3\x01\n" +
+	"\x13CostBasisPreference\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\"r\n" +
+	"\x19SetCostBasisMethodRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\"U\n" +
+	"\x1aSetCostBasisMethodResponse\x127\n" +
+	"\n" +
+	"preference\x18\x01 \x01(\v2\x17.pb.CostBasisPreferenceR\n" +
+	"preference\"Z\n" +
+	"\x19GetCostBasisMethodRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\"U\n" +
+	"\x1aGetCostBasisMethodResponse\x127\n" +
+	"\n" +
+	"preference\x18\x01 \x01(\v2\x17.pb.CostBasisPreferenceR\n" +
+	"preference\"\x9b\x01\n" +
+	"\x12TradingPermissions\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12.\n" +
+	"\x13allow_short_selling\x18\x02 \x01(\bR\x11allowShortSelling\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\x03 \x01(\tR\tupdatedBy\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\"g\n" +
+	"\x1cSetTradingPermissionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12.\n" +
+	"\x13allow_short_selling\x18\x02 \x01(\bR\x11allowShortSelling\"Y\n" +
+	"\x1dSetTradingPermissionsResponse\x128\n" +
+	"\vpermissions\x18\x01 \x01(\v2\x16.pb.TradingPermissionsR\vpermissions\"7\n" +
+	"\x1cGetTradingPermissionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"Y\n" +
+	"\x1dGetTradingPermissionsResponse\x128\n" +
+	"\vpermissions\x18\x01 \x01(\v2\x16.pb.TradingPermissionsR\vpermissions\"\x94\x01\n" +
+	"\x16SubscribeTradesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\tR\x05epoch\x12%\n" +
+	"\x0eafter_sequence\x18\x04 \x01(\x04R\rafterSequence\"q\n" +
+	"\n" +
+	"TradeEvent\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x14\n" +
+	"\x05epoch\x18\x02 \x01(\tR\x05epoch\x121\n" +
+	"\vtransaction\x18\x03 \x01(\v2\x0f.pb.TransactionR\vtransaction\"\xb3\x02\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x03 \x01(\tR\fspiceGradeId\x12\x12\n" +
+	"\x04side\x18\x04 \x01(\tR\x04side\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\tR\x05price\x12\x1a\n" +
+	"\bquantity\x18\x06 \x01(\tR\bquantity\x12#\n" +
+	"\rremaining_qty\x18\a \x01(\tR\fremainingQty\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\tR\tupdatedAt\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\"\xb6\x02\n" +
+	"\tOrderFill\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12$\n" +
+	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12 \n" +
+	"\fbuy_order_id\x18\x03 \x01(\tR\n" +
+	"buyOrderId\x12\"\n" +
+	"\rsell_order_id\x18\x04 \x01(\tR\vsellOrderId\x12,\n" +
+	"\x12buy_transaction_id\x18\x05 \x01(\tR\x10buyTransactionId\x12.\n" +
+	"\x13sell_transaction_id\x18\x06 \x01(\tR\x11sellTransactionId\x12\x1a\n" +
+	"\bquantity\x18\a \x01(\tR\bquantity\x12\x14\n" +
+	"\x05price\x18\b \x01(\tR\x05price\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"\x98\x01\n" +
+	"\x11PlaceOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12\x12\n" +
+	"\x04side\x18\x03 \x01(\tR\x04side\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\tR\bquantity\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\tR\x05price\"Z\n" +
+	"\x12PlaceOrderResponse\x12\x1f\n" +
+	"\x05order\x18\x01 \x01(\v2\t.pb.OrderR\x05order\x12#\n" +
+	"\x05fills\x18\x02 \x03(\v2\r.pb.OrderFillR\x05fills\"y\n" +
+	"\x11AmendOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\tR\bquantity\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\tR\x05price\"Z\n" +
+	"\x12AmendOrderResponse\x12\x1f\n" +
+	"\x05order\x18\x01 \x01(\v2\t.pb.OrderR\x05order\x12#\n" +
+	"\x05fills\x18\x02 \x03(\v2\r.pb.OrderFillR\x05fills\"H\n" +
+	"\x12CancelOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\"6\n" +
+	"\x13CancelOrderResponse\x12\x1f\n" +
+	"\x05order\x18\x01 \x01(\v2\t.pb.OrderR\x05order\"E\n" +
+	"\x0fGetOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\"X\n" +
+	"\x10GetOrderResponse\x12\x1f\n" +
+	"\x05order\x18\x01 \x01(\v2\t.pb.OrderR\x05order\x12#\n" +
+	"\x05fills\x18\x02 \x03(\v2\r.pb.OrderFillR\x05fills\"\xa6\x01\n" +
+	"\x11ListOrdersRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\x12\x12\n" +
+	"\x04side\x18\x03 \x01(\tR\x04side\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x12\n" +
+	"\x04skip\x18\x05 \x01(\rR\x04skip\x12\x12\n" +
+	"\x04take\x18\x06 \x01(\rR\x04take\"7\n" +
+	"\x12ListOrdersResponse\x12!\n" +
+	"\x06orders\x18\x01 \x03(\v2\t.pb.OrderR\x06orders\"Z\n" +
+	"\x0eOrderBookLevel\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\tR\x05price\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\tR\bquantity\x12\x16\n" +
+	"\x06orders\x18\x03 \x01(\rR\x06orders\"m\n" +
+	"\x13GetOrderBookRequest\x12$\n" +
+	"\x0espice_grade_id\x18\x01 \x01(\tR\fspiceGradeId\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\rR\x05depth\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\xa8\x01\n" +
+	"\x14GetOrderBookResponse\x12$\n" +
+	"\x0espice_grade_id\x18\x01 \x01(\tR\fspiceGradeId\x12&\n" +
+	"\x04bids\x18\x02 \x03(\v2\x12.pb.OrderBookLevelR\x04bids\x12&\n" +
+	"\x04asks\x18\x03 \x03(\v2\x12.pb.OrderBookLevelR\x04asks\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"X\n" +
 	"\x17GetGradePositionRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
 	"\x0espice_grade_id\x18\x02 \x01(\tR\fspiceGradeId\"H\n" +
@@ -1985,35 +7003,50 @@ const file_market_proto_rawDesc = "" +
 	"\adate_to\x18\b \x01(\tR\x06dateTo\"O\n" +
 	"\x18ListTransactionsResponse\x123\n" +
 	"\ftransactions\x18\x01 \x03(\v2\x0f.pb.TransactionR\ftransactions\"\x19\n" +
-	"\x17GetMarketMetricsRequest\"\xa0\x02\n" +
+	"\x17GetMarketMetricsRequest\"\x94\x03\n" +
 	"\x18GetMarketMetricsResponse\x12-\n" +
 	"\x12total_transactions\x18\x01 \x01(\rR\x11totalTransactions\x12!\n" +
-	"\ftotal_volume\x18\x02 \x01(\x01R\vtotalVolume\x12J\n" +
-	"\ftop_products\x18\x03 \x03(\v2'.pb.GetMarketMetricsResponse.TopProductR\vtopProducts\x1af\n" +
+	"\ftotal_volume\x18\x02 \x01(\tR\vtotalVolume\x12J\n" +
+	"\ftop_products\x18\x03 \x03(\v2'.pb.GetMarketMetricsResponse.TopProductR\vtopProducts\x12\x1f\n" +
+	"\vtotal_value\x18\x04 \x01(\tR\n" +
+	"totalValue\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x125\n" +
+	"\x16unconverted_currencies\x18\x06 \x03(\tR\x15unconvertedCurrencies\x1af\n" +
 	"\n" +
 	"TopProduct\x12!\n" +
 	"\fproduct_name\x18\x01 \x01(\tR\vproductName\x12\x1d\n" +
 	"\n" +
 	"grade_name\x18\x02 \x01(\tR\tgradeName\x12\x16\n" +
-	"\x06volume\x18\x03 \x01(\x01R\x06volume\"\xf8\x01\n" +
+	"\x06volume\x18\x03 \x01(\tR\x06volume\"\xd4\x03\n" +
 	"\x0fEnrichedHolding\x12$\n" +
 	"\x0espice_grade_id\x18\x01 \x01(\tR\fspiceGradeId\x12!\n" +
 	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1d\n" +
 	"\n" +
 	"grade_name\x18\x03 \x01(\tR\tgradeName\x12\x1a\n" +
-	"\bquantity\x18\x04 \x01(\x01R\bquantity\x12\x1d\n" +
+	"\bquantity\x18\x04 \x01(\tR\bquantity\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x05 \x01(\tR\ttotalCost\x12!\n" +
+	"\frealized_pnl\x18\x06 \x01(\tR\vrealizedPnl\x12\x1f\n" +
+	"\vtoday_price\x18\a \x01(\tR\n" +
+	"todayPrice\x12\x1d\n" +
 	"\n" +
-	"total_cost\x18\x05 \x01(\x01R\ttotalCost\x12!\n" +
-	"\frealized_pnl\x18\x06 \x01(\x01R\vrealizedPnl\x12\x1f\n" +
-	"\vtoday_price\x18\a \x01(\x01R\n" +
-	"todayPrice\"-\n" +
+	"price_date\x18\b \x01(\tR\tpriceDate\x12!\n" +
+	"\fprice_source\x18\t \x01(\tR\vpriceSource\x12\x14\n" +
+	"\x05stale\x18\n" +
+	" \x01(\bR\x05stale\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\x12\x17\n" +
+	"\afx_rate\x18\f \x01(\tR\x06fxRate\x12\x12\n" +
+	"\x04unit\x18\r \x01(\tR\x04unit\x12\x1e\n" +
+	"\vkg_per_unit\x18\x0e \x01(\tR\tkgPerUnit\x12\x19\n" +
+	"\bunit_qty\x18\x0f \x01(\tR\aunitQty\"-\n" +
 	"\x12GetHoldingsRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"F\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"u\n" +
 	"\x13GetHoldingsResponse\x12/\n" +
-	"\bholdings\x18\x01 \x03(\v2\x13.pb.EnrichedHoldingR\bholdings\"\xa4\x01\n" +
+	"\bholdings\x18\x01 \x03(\v2\x13.pb.EnrichedHoldingR\bholdings\x12-\n" +
+	"\x12reporting_currency\x18\x02 \x01(\tR\x11reportingCurrency\"\xa4\x01\n" +
 	"\x0eRealizedPnLRow\x12\x12\n" +
 	"\x04date\x18\x01 \x01(\tR\x04date\x12\x16\n" +
-	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12$\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\x12$\n" +
 	"\x0espice_grade_id\x18\x03 \x01(\tR\fspiceGradeId\x12!\n" +
 	"\fproduct_name\x18\x04 \x01(\tR\vproductName\x12\x1d\n" +
 	"\n" +
@@ -2026,7 +7059,7 @@ const file_market_proto_rawDesc = "" +
 	"\x10TradeActivityRow\x12\x12\n" +
 	"\x04date\x18\x01 \x01(\tR\x04date\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x01R\bquantity\x12\x14\n" +
+	"\bquantity\x18\x03 \x01(\tR\bquantity\x12\x14\n" +
 	"\x05count\x18\x04 \x01(\rR\x05count\x12$\n" +
 	"\x0espice_grade_id\x18\x05 \x01(\tR\fspiceGradeId\x12!\n" +
 	"\fproduct_name\x18\x06 \x01(\tR\vproductName\x12\x1d\n" +
@@ -2042,23 +7075,52 @@ const file_market_proto_rawDesc = "" +
 	"\x04days\x18\x02 \x01(\rR\x04days\"\xa5\x01\n" +
 	"\x15GetTradeStatsResponse\x12(\n" +
 	"\x10trades_in_period\x18\x01 \x01(\rR\x0etradesInPeriod\x12/\n" +
-	"\x14buy_volume_in_period\x18\x02 \x01(\x01R\x11buyVolumeInPeriod\x121\n" +
-	"\x15sell_volume_in_period\x18\x03 \x01(\x01R\x12sellVolumeInPeriod\"\xbf\x01\n" +
+	"\x14buy_volume_in_period\x18\x02 \x01(\tR\x11buyVolumeInPeriod\x121\n" +
+	"\x15sell_volume_in_period\x18\x03 \x01(\tR\x12sellVolumeInPeriod\"\xb3\x02\n" +
 	"\rPriceSnapshot\x12$\n" +
 	"\x0espice_grade_id\x18\x01 \x01(\tR\fspiceGradeId\x12!\n" +
 	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1d\n" +
 	"\n" +
 	"grade_name\x18\x03 \x01(\tR\tgradeName\x12\x1f\n" +
-	"\vtoday_price\x18\x04 \x01(\x01R\n" +
+	"\vtoday_price\x18\x04 \x01(\tR\n" +
 	"todayPrice\x12%\n" +
-	"\x0eprevious_price\x18\x05 \x01(\x01R\rpreviousPrice\"3\n" +
+	"\x0eprevious_price\x18\x05 \x01(\tR\rpreviousPrice\x12\x1d\n" +
+	"\n" +
+	"price_date\x18\x06 \x01(\tR\tpriceDate\x12!\n" +
+	"\fprice_source\x18\a \x01(\tR\vpriceSource\x12\x14\n" +
+	"\x05stale\x18\b \x01(\bR\x05stale\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\"3\n" +
 	"\x18GetPriceSnapshotsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"L\n" +
 	"\x19GetPriceSnapshotsResponse\x12/\n" +
-	"\tsnapshots\x18\x01 \x03(\v2\x11.pb.PriceSnapshotR\tsnapshots2\xf5\x06\n" +
+	"\tsnapshots\x18\x01 \x03(\v2\x11.pb.PriceSnapshotR\tsnapshots2\x94\x13\n" +
 	"\rMarketService\x12&\n" +
 	"\x03Buy\x12\x0e.pb.BuyRequest\x1a\x0f.pb.BuyResponse\x12)\n" +
-	"\x04Sell\x12\x0f.pb.SellRequest\x1a\x10.pb.SellResponse\x12M\n" +
+	"\x04Sell\x12\x0f.pb.SellRequest\x1a\x10.pb.SellResponse\x12P\n" +
+	"\x11CancelTransaction\x12\x1c.pb.CancelTransactionRequest\x1a\x1d.pb.CancelTransactionResponse\x12M\n" +
+	"\x10AmendTransaction\x12\x1b.pb.AmendTransactionRequest\x1a\x1c.pb.AmendTransactionResponse\x12J\n" +
+	"\x0fReconcileLedger\x12\x1a.pb.ReconcileLedgerRequest\x1a\x1b.pb.ReconcileLedgerResponse\x12A\n" +
+	"\fListOpenLots\x12\x17.pb.ListOpenLotsRequest\x1a\x18.pb.ListOpenLotsResponse\x12D\n" +
+	"\rGetLotHistory\x12\x18.pb.GetLotHistoryRequest\x1a\x19.pb.GetLotHistoryResponse\x12S\n" +
+	"\x12GetSellAllocations\x12\x1d.pb.GetSellAllocationsRequest\x1a\x1e.pb.GetSellAllocationsResponse\x12V\n" +
+	"\x13ListTransactionFees\x12\x1e.pb.ListTransactionFeesRequest\x1a\x1f.pb.ListTransactionFeesResponse\x12A\n" +
+	"\fGetLotAgeing\x12\x17.pb.GetLotAgeingRequest\x1a\x18.pb.GetLotAgeingResponse\x12_\n" +
+	"\x16GetRealizedGainsReport\x12!.pb.GetRealizedGainsReportRequest\x1a\".pb.GetRealizedGainsReportResponse\x12>\n" +
+	"\fExportLedger\x12\x17.pb.ExportLedgerRequest\x1a\x13.pb.ExportLedgerRow0\x01\x12S\n" +
+	"\x12SetCostBasisMethod\x12\x1d.pb.SetCostBasisMethodRequest\x1a\x1e.pb.SetCostBasisMethodResponse\x12S\n" +
+	"\x12GetCostBasisMethod\x12\x1d.pb.GetCostBasisMethodRequest\x1a\x1e.pb.GetCostBasisMethodResponse\x12;\n" +
+	"\n" +
+	"PlaceOrder\x12\x15.pb.PlaceOrderRequest\x1a\x16.pb.PlaceOrderResponse\x12;\n" +
+	"\n" +
+	"AmendOrder\x12\x15.pb.AmendOrderRequest\x1a\x16.pb.AmendOrderResponse\x12>\n" +
+	"\vCancelOrder\x12\x16.pb.CancelOrderRequest\x1a\x17.pb.CancelOrderResponse\x125\n" +
+	"\bGetOrder\x12\x13.pb.GetOrderRequest\x1a\x14.pb.GetOrderResponse\x12;\n" +
+	"\n" +
+	"ListOrders\x12\x15.pb.ListOrdersRequest\x1a\x16.pb.ListOrdersResponse\x12A\n" +
+	"\fGetOrderBook\x12\x17.pb.GetOrderBookRequest\x1a\x18.pb.GetOrderBookResponse\x12\\\n" +
+	"\x15SetTradingPermissions\x12 .pb.SetTradingPermissionsRequest\x1a!.pb.SetTradingPermissionsResponse\x12\\\n" +
+	"\x15GetTradingPermissions\x12 .pb.GetTradingPermissionsRequest\x1a!.pb.GetTradingPermissionsResponse\x12?\n" +
+	"\x0fSubscribeTrades\x12\x1a.pb.SubscribeTradesRequest\x1a\x0e.pb.TradeEvent0\x01\x12M\n" +
 	"\x10GetGradePosition\x12\x1b.pb.GetGradePositionRequest\x1a\x1c.pb.GetGradePositionResponse\x12A\n" +
 	"\fGetPositions\x12\x17.pb.GetPositionsRequest\x1a\x18.pb.GetPositionsResponse\x12\\\n" +
 	"\x15ListGradeTransactions\x12 .pb.ListGradeTransactionsRequest\x1a!.pb.ListGradeTransactionsResponse\x12M\n" +
@@ -2082,81 +7144,223 @@ func file_market_proto_rawDescGZIP() []byte {
 	return file_market_proto_rawDescData
 }
 
-var file_market_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_market_proto_msgTypes = make([]protoimpl.MessageInfo, 89)
 var file_market_proto_goTypes = []any{
 	(*Transaction)(nil),                         // 0: pb.Transaction
-	(*PositionView)(nil),                        // 1: pb.PositionView
-	(*BuyRequest)(nil),                          // 2: pb.BuyRequest
-	(*BuyResponse)(nil),                         // 3: pb.BuyResponse
-	(*SellRequest)(nil),                         // 4: pb.SellRequest
-	(*SellResponse)(nil),                        // 5: pb.SellResponse
-	(*GetGradePositionRequest)(nil),             // 6: pb.GetGradePositionRequest
-	(*GetGradePositionResponse)(nil),            // 7: pb.GetGradePositionResponse
-	(*GetPositionsRequest)(nil),                 // 8: pb.GetPositionsRequest
-	(*GetPositionsResponse)(nil),                // 9: pb.GetPositionsResponse
-	(*ListGradeTransactionsRequest)(nil),        // 10: pb.ListGradeTransactionsRequest
-	(*ListGradeTransactionsResponse)(nil),       // 11: pb.ListGradeTransactionsResponse
-	(*ListTransactionsRequest)(nil),             // 12: pb.ListTransactionsRequest
-	(*ListTransactionsResponse)(nil),            // 13: pb.ListTransactionsResponse
-	(*GetMarketMetricsRequest)(nil),             // 14: pb.GetMarketMetricsRequest
-	(*GetMarketMetricsResponse)(nil),            // 15: pb.GetMarketMetricsResponse
-	(*EnrichedHolding)(nil),                     // 16: pb.EnrichedHolding
-	(*GetHoldingsRequest)(nil),                  // 17: pb.GetHoldingsRequest
-	(*GetHoldingsResponse)(nil),                 // 18: pb.GetHoldingsResponse
-	(*RealizedPnLRow)(nil),                      // 19: pb.RealizedPnLRow
-	(*GetRealizedPnLHistoryRequest)(nil),        // 20: pb.GetRealizedPnLHistoryRequest
-	(*GetRealizedPnLHistoryResponse)(nil),       // 21: pb.GetRealizedPnLHistoryResponse
-	(*TradeActivityRow)(nil),                    // 22: pb.TradeActivityRow
-	(*GetTradeActivityRequest)(nil),             // 23: pb.GetTradeActivityRequest
-	(*GetTradeActivityResponse)(nil),            // 24: pb.GetTradeActivityResponse
-	(*GetTradeStatsRequest)(nil),                // 25: pb.GetTradeStatsRequest
-	(*GetTradeStatsResponse)(nil),               // 26: pb.GetTradeStatsResponse
-	(*PriceSnapshot)(nil),                       // 27: pb.PriceSnapshot
-	(*GetPriceSnapshotsRequest)(nil),            // 28: pb.GetPriceSnapshotsRequest
-	(*GetPriceSnapshotsResponse)(nil),           // 29: pb.GetPriceSnapshotsResponse
-	(*GetMarketMetricsResponse_TopProduct)(nil), // 30: pb.GetMarketMetricsResponse.TopProduct
+	(*TransactionFee)(nil),                      // 1: pb.TransactionFee
+	(*ListTransactionFeesRequest)(nil),          // 2: pb.ListTransactionFeesRequest
+	(*ListTransactionFeesResponse)(nil),         // 3: pb.ListTransactionFeesResponse
+	(*PositionView)(nil),                        // 4: pb.PositionView
+	(*BuyRequest)(nil),                          // 5: pb.BuyRequest
+	(*BuyResponse)(nil),                         // 6: pb.BuyResponse
+	(*LotSelection)(nil),                        // 7: pb.LotSelection
+	(*SellRequest)(nil),                         // 8: pb.SellRequest
+	(*SellResponse)(nil),                        // 9: pb.SellResponse
+	(*CancelTransactionRequest)(nil),            // 10: pb.CancelTransactionRequest
+	(*CancelTransactionResponse)(nil),           // 11: pb.CancelTransactionResponse
+	(*AmendTransactionRequest)(nil),             // 12: pb.AmendTransactionRequest
+	(*AmendTransactionResponse)(nil),            // 13: pb.AmendTransactionResponse
+	(*PositionTotals)(nil),                      // 14: pb.PositionTotals
+	(*PositionDrift)(nil),                       // 15: pb.PositionDrift
+	(*LotDrift)(nil),                            // 16: pb.LotDrift
+	(*ReconcileLedgerRequest)(nil),              // 17: pb.ReconcileLedgerRequest
+	(*ReconcileLedgerResponse)(nil),             // 18: pb.ReconcileLedgerResponse
+	(*BuyLot)(nil),                              // 19: pb.BuyLot
+	(*SellAllocation)(nil),                      // 20: pb.SellAllocation
+	(*ListOpenLotsRequest)(nil),                 // 21: pb.ListOpenLotsRequest
+	(*ListOpenLotsResponse)(nil),                // 22: pb.ListOpenLotsResponse
+	(*GetLotHistoryRequest)(nil),                // 23: pb.GetLotHistoryRequest
+	(*GetLotHistoryResponse)(nil),               // 24: pb.GetLotHistoryResponse
+	(*GetSellAllocationsRequest)(nil),           // 25: pb.GetSellAllocationsRequest
+	(*GetSellAllocationsResponse)(nil),          // 26: pb.GetSellAllocationsResponse
+	(*AgeingBucket)(nil),                        // 27: pb.AgeingBucket
+	(*GradeAgeing)(nil),                         // 28: pb.GradeAgeing
+	(*GetLotAgeingRequest)(nil),                 // 29: pb.GetLotAgeingRequest
+	(*GetLotAgeingResponse)(nil),                // 30: pb.GetLotAgeingResponse
+	(*RealizedGain)(nil),                        // 31: pb.RealizedGain
+	(*GainsSubtotal)(nil),                       // 32: pb.GainsSubtotal
+	(*GetRealizedGainsReportRequest)(nil),       // 33: pb.GetRealizedGainsReportRequest
+	(*GetRealizedGainsReportResponse)(nil),      // 34: pb.GetRealizedGainsReportResponse
+	(*ExportLedgerRequest)(nil),                 // 35: pb.ExportLedgerRequest
+	(*ExportLedgerRow)(nil),                     // 36: pb.ExportLedgerRow
+	(*CostBasisPreference)(nil),                 // 37: pb.CostBasisPreference
+	(*SetCostBasisMethodRequest)(nil),           // 38: pb.SetCostBasisMethodRequest
+	(*SetCostBasisMethodResponse)(nil),          // 39: pb.SetCostBasisMethodResponse
+	(*GetCostBasisMethodRequest)(nil),           // 40: pb.GetCostBasisMethodRequest
+	(*GetCostBasisMethodResponse)(nil),          // 41: pb.GetCostBasisMethodResponse
+	(*TradingPermissions)(nil),                  // 42: pb.TradingPermissions
+	(*SetTradingPermissionsRequest)(nil),        // 43: pb.SetTradingPermissionsRequest
+	(*SetTradingPermissionsResponse)(nil),       // 44: pb.SetTradingPermissionsResponse
+	(*GetTradingPermissionsRequest)(nil),        // 45: pb.GetTradingPermissionsRequest
+	(*GetTradingPermissionsResponse)(nil),       // 46: pb.GetTradingPermissionsResponse
+	(*SubscribeTradesRequest)(nil),              // 47: pb.SubscribeTradesRequest
+	(*TradeEvent)(nil),                          // 48: pb.TradeEvent
+	(*Order)(nil),                               // 49: pb.Order
+	(*OrderFill)(nil),                           // 50: pb.OrderFill
+	(*PlaceOrderRequest)(nil),                   // 51: pb.PlaceOrderRequest
+	(*PlaceOrderResponse)(nil),                  // 52: pb.PlaceOrderResponse
+	(*AmendOrderRequest)(nil),                   // 53: pb.AmendOrderRequest
+	(*AmendOrderResponse)(nil),                  // 54: pb.AmendOrderResponse
+	(*CancelOrderRequest)(nil),                  // 55: pb.CancelOrderRequest
+	(*CancelOrderResponse)(nil),                 // 56: pb.CancelOrderResponse
+	(*GetOrderRequest)(nil),                     // 57: pb.GetOrderRequest
+	(*GetOrderResponse)(nil),                    // 58: pb.GetOrderResponse
+	(*ListOrdersRequest)(nil),                   // 59: pb.ListOrdersRequest
+	(*ListOrdersResponse)(nil),                  // 60: pb.ListOrdersResponse
+	(*OrderBookLevel)(nil),                      // 61: pb.OrderBookLevel
+	(*GetOrderBookRequest)(nil),                 // 62: pb.GetOrderBookRequest
+	(*GetOrderBookResponse)(nil),                // 63: pb.GetOrderBookResponse
+	(*GetGradePositionRequest)(nil),             // 64: pb.GetGradePositionRequest
+	(*GetGradePositionResponse)(nil),            // 65: pb.GetGradePositionResponse
+	(*GetPositionsRequest)(nil),                 // 66: pb.GetPositionsRequest
+	(*GetPositionsResponse)(nil),                // 67: pb.GetPositionsResponse
+	(*ListGradeTransactionsRequest)(nil),        // 68: pb.ListGradeTransactionsRequest
+	(*ListGradeTransactionsResponse)(nil),       // 69: pb.ListGradeTransactionsResponse
+	(*ListTransactionsRequest)(nil),             // 70: pb.ListTransactionsRequest
+	(*ListTransactionsResponse)(nil),            // 71: pb.ListTransactionsResponse
+	(*GetMarketMetricsRequest)(nil),             // 72: pb.GetMarketMetricsRequest
+	(*GetMarketMetricsResponse)(nil),            // 73: pb.GetMarketMetricsResponse
+	(*EnrichedHolding)(nil),                     // 74: pb.EnrichedHolding
+	(*GetHoldingsRequest)(nil),                  // 75: pb.GetHoldingsRequest
+	(*GetHoldingsResponse)(nil),                 // 76: pb.GetHoldingsResponse
+	(*RealizedPnLRow)(nil),                      // 77: pb.RealizedPnLRow
+	(*GetRealizedPnLHistoryRequest)(nil),        // 78: pb.GetRealizedPnLHistoryRequest
+	(*GetRealizedPnLHistoryResponse)(nil),       // 79: pb.GetRealizedPnLHistoryResponse
+	(*TradeActivityRow)(nil),                    // 80: pb.TradeActivityRow
+	(*GetTradeActivityRequest)(nil),             // 81: pb.GetTradeActivityRequest
+	(*GetTradeActivityResponse)(nil),            // 82: pb.GetTradeActivityResponse
+	(*GetTradeStatsRequest)(nil),                // 83: pb.GetTradeStatsRequest
+	(*GetTradeStatsResponse)(nil),               // 84: pb.GetTradeStatsResponse
+	(*PriceSnapshot)(nil),                       // 85: pb.PriceSnapshot
+	(*GetPriceSnapshotsRequest)(nil),            // 86: pb.GetPriceSnapshotsRequest
+	(*GetPriceSnapshotsResponse)(nil),           // 87: pb.GetPriceSnapshotsResponse
+	(*GetMarketMetricsResponse_TopProduct)(nil), // 88: pb.GetMarketMetricsResponse.TopProduct
 }
 var file_market_proto_depIdxs = []int32{
-	0,  // 0: pb.BuyResponse.transaction:type_name -> pb.Transaction
-	0,  // 1: pb.SellResponse.transaction:type_name -> pb.Transaction
-	1,  // 2: pb.GetGradePositionResponse.position:type_name -> pb.PositionView
-	1,  // 3: pb.GetPositionsResponse.positions:type_name -> pb.PositionView
-	0,  // 4: pb.ListGradeTransactionsResponse.transactions:type_name -> pb.Transaction
-	0,  // 5: pb.ListTransactionsResponse.transactions:type_name -> pb.Transaction
-	30, // 6: pb.GetMarketMetricsResponse.top_products:type_name -> pb.GetMarketMetricsResponse.TopProduct
-	16, // 7: pb.GetHoldingsResponse.holdings:type_name -> pb.EnrichedHolding
-	19, // 8: pb.GetRealizedPnLHistoryResponse.rows:type_name -> pb.RealizedPnLRow
-	22, // 9: pb.GetTradeActivityResponse.rows:type_name -> pb.TradeActivityRow
-	27, // 10: pb.GetPriceSnapshotsResponse.snapshots:type_name -> pb.PriceSnapshot
-	2,  // 11: pb.MarketService.Buy:input_type -> pb.BuyRequest
-	4,  // 12: pb.MarketService.Sell:input_type -> pb.SellRequest
-	6,  // 13: pb.MarketService.GetGradePosition:input_type -> pb.GetGradePositionRequest
-	8,  // 14: pb.MarketService.GetPositions:input_type -> pb.GetPositionsRequest
-	10, // 15: pb.MarketService.ListGradeTransactions:input_type -> pb.ListGradeTransactionsRequest
-	12, // 16: pb.MarketService.ListTransactions:input_type -> pb.ListTransactionsRequest
-	14, // 17: pb.MarketService.GetMarketMetrics:input_type -> pb.GetMarketMetricsRequest
-	17, // 18: pb.MarketService.GetHoldings:input_type -> pb.GetHoldingsRequest
-	20, // 19: pb.MarketService.GetRealizedPnLHistory:input_type -> pb.GetRealizedPnLHistoryRequest
-	23, // 20: pb.MarketService.GetTradeActivity:input_type -> pb.GetTradeActivityRequest
-	25, // 21: pb.MarketService.GetTradeStats:input_type -> pb.GetTradeStatsRequest
-	28, // 22: pb.MarketService.GetPriceSnapshots:input_type -> pb.GetPriceSnapshotsRequest
-	3,  // 23: pb.MarketService.Buy:output_type -> pb.BuyResponse
-	5,  // 24: pb.MarketService.Sell:output_type -> pb.SellResponse
-	7,  // 25: pb.MarketService.GetGradePosition:output_type -> pb.GetGradePositionResponse
-	9,  // 26: pb.MarketService.GetPositions:output_type -> pb.GetPositionsResponse
-	11, // 27: pb.MarketService.ListGradeTransactions:output_type -> pb.ListGradeTransactionsResponse
-	13, // 28: pb.MarketService.ListTransactions:output_type -> pb.ListTransactionsResponse
-	15, // 29: pb.MarketService.GetMarketMetrics:output_type -> pb.GetMarketMetricsResponse
-	18, // 30: pb.MarketService.GetHoldings:output_type -> pb.GetHoldingsResponse
-	21, // 31: pb.MarketService.GetRealizedPnLHistory:output_type -> pb.GetRealizedPnLHistoryResponse
-	24, // 32: pb.MarketService.GetTradeActivity:output_type -> pb.GetTradeActivityResponse
-	26, // 33: pb.MarketService.GetTradeStats:output_type -> pb.GetTradeStatsResponse
-	29, // 34: pb.MarketService.GetPriceSnapshots:output_type -> pb.GetPriceSnapshotsResponse
-	23, // [23:35] is the sub-list for method output_type
-	11, // [11:23] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	1,  // 0: pb.ListTransactionFeesResponse.fees:type_name -> pb.TransactionFee
+	0,  // 1: pb.BuyResponse.transaction:type_name -> pb.Transaction
+	7,  // 2: pb.SellRequest.lots:type_name -> pb.LotSelection
+	0,  // 3: pb.SellResponse.transaction:type_name -> pb.Transaction
+	0,  // 4: pb.CancelTransactionResponse.original:type_name -> pb.Transaction
+	0,  // 5: pb.CancelTransactionResponse.reversal:type_name -> pb.Transaction
+	7,  // 6: pb.AmendTransactionRequest.lots:type_name -> pb.LotSelection
+	0,  // 7: pb.AmendTransactionResponse.transaction:type_name -> pb.Transaction
+	0,  // 8: pb.AmendTransactionResponse.original:type_name -> pb.Transaction
+	0,  // 9: pb.AmendTransactionResponse.reversal:type_name -> pb.Transaction
+	14, // 10: pb.PositionDrift.stored:type_name -> pb.PositionTotals
+	14, // 11: pb.PositionDrift.expected:type_name -> pb.PositionTotals
+	15, // 12: pb.ReconcileLedgerResponse.positions:type_name -> pb.PositionDrift
+	16, // 13: pb.ReconcileLedgerResponse.lots:type_name -> pb.LotDrift
+	19, // 14: pb.ListOpenLotsResponse.lots:type_name -> pb.BuyLot
+	19, // 15: pb.GetLotHistoryResponse.lot:type_name -> pb.BuyLot
+	20, // 16: pb.GetLotHistoryResponse.allocations:type_name -> pb.SellAllocation
+	20, // 17: pb.GetSellAllocationsResponse.allocations:type_name -> pb.SellAllocation
+	27, // 18: pb.GradeAgeing.buckets:type_name -> pb.AgeingBucket
+	27, // 19: pb.GetLotAgeingResponse.buckets:type_name -> pb.AgeingBucket
+	28, // 20: pb.GetLotAgeingResponse.grades:type_name -> pb.GradeAgeing
+	31, // 21: pb.GetRealizedGainsReportResponse.gains:type_name -> pb.RealizedGain
+	32, // 22: pb.GetRealizedGainsReportResponse.grades:type_name -> pb.GainsSubtotal
+	32, // 23: pb.GetRealizedGainsReportResponse.holding_classes:type_name -> pb.GainsSubtotal
+	32, // 24: pb.GetRealizedGainsReportResponse.totals:type_name -> pb.GainsSubtotal
+	0,  // 25: pb.ExportLedgerRow.transaction:type_name -> pb.Transaction
+	19, // 26: pb.ExportLedgerRow.lot:type_name -> pb.BuyLot
+	20, // 27: pb.ExportLedgerRow.allocation:type_name -> pb.SellAllocation
+	4,  // 28: pb.ExportLedgerRow.position:type_name -> pb.PositionView
+	37, // 29: pb.SetCostBasisMethodResponse.preference:type_name -> pb.CostBasisPreference
+	37, // 30: pb.GetCostBasisMethodResponse.preference:type_name -> pb.CostBasisPreference
+	42, // 31: pb.SetTradingPermissionsResponse.permissions:type_name -> pb.TradingPermissions
+	42, // 32: pb.GetTradingPermissionsResponse.permissions:type_name -> pb.TradingPermissions
+	0,  // 33: pb.TradeEvent.transaction:type_name -> pb.Transaction
+	49, // 34: pb.PlaceOrderResponse.order:type_name -> pb.Order
+	50, // 35: pb.PlaceOrderResponse.fills:type_name -> pb.OrderFill
+	49, // 36: pb.AmendOrderResponse.order:type_name -> pb.Order
+	50, // 37: pb.AmendOrderResponse.fills:type_name -> pb.OrderFill
+	49, // 38: pb.CancelOrderResponse.order:type_name -> pb.Order
+	49, // 39: pb.GetOrderResponse.order:type_name -> pb.Order
+	50, // 40: pb.GetOrderResponse.fills:type_name -> pb.OrderFill
+	49, // 41: pb.ListOrdersResponse.orders:type_name -> pb.Order
+	61, // 42: pb.GetOrderBookResponse.bids:type_name -> pb.OrderBookLevel
+	61, // 43: pb.GetOrderBookResponse.asks:type_name -> pb.OrderBookLevel
+	4,  // 44: pb.GetGradePositionResponse.position:type_name -> pb.PositionView
+	4,  // 45: pb.GetPositionsResponse.positions:type_name -> pb.PositionView
+	0,  // 46: pb.ListGradeTransactionsResponse.transactions:type_name -> pb.Transaction
+	0,  // 47: pb.ListTransactionsResponse.transactions:type_name -> pb.Transaction
+	88, // 48: pb.GetMarketMetricsResponse.top_products:type_name -> pb.GetMarketMetricsResponse.TopProduct
+	74, // 49: pb.GetHoldingsResponse.holdings:type_name -> pb.EnrichedHolding
+	77, // 50: pb.GetRealizedPnLHistoryResponse.rows:type_name -> pb.RealizedPnLRow
+	80, // 51: pb.GetTradeActivityResponse.rows:type_name -> pb.TradeActivityRow
+	85, // 52: pb.GetPriceSnapshotsResponse.snapshots:type_name -> pb.PriceSnapshot
+	5,  // 53: pb.MarketService.Buy:input_type -> pb.BuyRequest
+	8,  // 54: pb.MarketService.Sell:input_type -> pb.SellRequest
+	10, // 55: pb.MarketService.CancelTransaction:input_type -> pb.CancelTransactionRequest
+	12, // 56: pb.MarketService.AmendTransaction:input_type -> pb.AmendTransactionRequest
+	17, // 57: pb.MarketService.ReconcileLedger:input_type -> pb.ReconcileLedgerRequest
+	21, // 58: pb.MarketService.ListOpenLots:input_type -> pb.ListOpenLotsRequest
+	23, // 59: pb.MarketService.GetLotHistory:input_type -> pb.GetLotHistoryRequest
+	25, // 60: pb.MarketService.GetSellAllocations:input_type -> pb.GetSellAllocationsRequest
+	2,  // 61: pb.MarketService.ListTransactionFees:input_type -> pb.ListTransactionFeesRequest
+	29, // 62: pb.MarketService.GetLotAgeing:input_type -> pb.GetLotAgeingRequest
+	33, // 63: pb.MarketService.GetRealizedGainsReport:input_type -> pb.GetRealizedGainsReportRequest
+	35, // 64: pb.MarketService.ExportLedger:input_type -> pb.ExportLedgerRequest
+	38, // 65: pb.MarketService.SetCostBasisMethod:input_type -> pb.SetCostBasisMethodRequest
+	40, // 66: pb.MarketService.GetCostBasisMethod:input_type -> pb.GetCostBasisMethodRequest
+	51, // 67: pb.MarketService.PlaceOrder:input_type -> pb.PlaceOrderRequest
+	53, // 68: pb.MarketService.AmendOrder:input_type -> pb.AmendOrderRequest
+	55, // 69: pb.MarketService.CancelOrder:input_type -> pb.CancelOrderRequest
+	57, // 70: pb.MarketService.GetOrder:input_type -> pb.GetOrderRequest
+	59, // 71: pb.MarketService.ListOrders:input_type -> pb.ListOrdersRequest
+	62, // 72: pb.MarketService.GetOrderBook:input_type -> pb.GetOrderBookRequest
+	43, // 73: pb.MarketService.SetTradingPermissions:input_type -> pb.SetTradingPermissionsRequest
+	45, // 74: pb.MarketService.GetTradingPermissions:input_type -> pb.GetTradingPermissionsRequest
+	47, // 75: pb.MarketService.SubscribeTrades:input_type -> pb.SubscribeTradesRequest
+	64, // 76: pb.MarketService.GetGradePosition:input_type -> pb.GetGradePositionRequest
+	66, // 77: pb.MarketService.GetPositions:input_type -> pb.GetPositionsRequest
+	68, // 78: pb.MarketService.ListGradeTransactions:input_type -> pb.ListGradeTransactionsRequest
+	70, // 79: pb.MarketService.ListTransactions:input_type -> pb.ListTransactionsRequest
+	72, // 80: pb.MarketService.GetMarketMetrics:input_type -> pb.GetMarketMetricsRequest
+	75, // 81: pb.MarketService.GetHoldings:input_type -> pb.GetHoldingsRequest
+	78, // 82: pb.MarketService.GetRealizedPnLHistory:input_type -> pb.GetRealizedPnLHistoryRequest
+	81, // 83: pb.MarketService.GetTradeActivity:input_type -> pb.GetTradeActivityRequest
+	83, // 84: pb.MarketService.GetTradeStats:input_type -> pb.GetTradeStatsRequest
+	86, // 85: pb.MarketService.GetPriceSnapshots:input_type -> pb.GetPriceSnapshotsRequest
+	6,  // 86: pb.MarketService.Buy:output_type -> pb.BuyResponse
+	9,  // 87: pb.MarketService.Sell:output_type -> pb.SellResponse
+	11, // 88: pb.MarketService.CancelTransaction:output_type -> pb.CancelTransactionResponse
+	13, // 89: pb.MarketService.AmendTransaction:output_type -> pb.AmendTransactionResponse
+	18, // 90: pb.MarketService.ReconcileLedger:output_type -> pb.ReconcileLedgerResponse
+	22, // 91: pb.MarketService.ListOpenLots:output_type -> pb.ListOpenLotsResponse
+	24, // 92: pb.MarketService.GetLotHistory:output_type -> pb.GetLotHistoryResponse
+	26, // 93: pb.MarketService.GetSellAllocations:output_type -> pb.GetSellAllocationsResponse
+	3,  // 94: pb.MarketService.ListTransactionFees:output_type -> pb.ListTransactionFeesResponse
+	30, // 95: pb.MarketService.GetLotAgeing:output_type -> pb.GetLotAgeingResponse
+	34, // 96: pb.MarketService.GetRealizedGainsReport:output_type -> pb.GetRealizedGainsReportResponse
+	36, // 97: pb.MarketService.ExportLedger:output_type -> pb.ExportLedgerRow
+	39, // 98: pb.MarketService.SetCostBasisMethod:output_type -> pb.SetCostBasisMethodResponse
+	41, // 99: pb.MarketService.GetCostBasisMethod:output_type -> pb.GetCostBasisMethodResponse
+	52, // 100: pb.MarketService.PlaceOrder:output_type -> pb.PlaceOrderResponse
+	54, // 101: pb.MarketService.AmendOrder:output_type -> pb.AmendOrderResponse
+	56, // 102: pb.MarketService.CancelOrder:output_type -> pb.CancelOrderResponse
+	58, // 103: pb.MarketService.GetOrder:output_type -> pb.GetOrderResponse
+	60, // 104: pb.MarketService.ListOrders:output_type -> pb.ListOrdersResponse
+	63, // 105: pb.MarketService.GetOrderBook:output_type -> pb.GetOrderBookResponse
+	44, // 106: pb.MarketService.SetTradingPermissions:output_type -> pb.SetTradingPermissionsResponse
+	46, // 107: pb.MarketService.GetTradingPermissions:output_type -> pb.GetTradingPermissionsResponse
+	48, // 108: pb.MarketService.SubscribeTrades:output_type -> pb.TradeEvent
+	65, // 109: pb.MarketService.GetGradePosition:output_type -> pb.GetGradePositionResponse
+	67, // 110: pb.MarketService.GetPositions:output_type -> pb.GetPositionsResponse
+	69, // 111: pb.MarketService.ListGradeTransactions:output_type -> pb.ListGradeTransactionsResponse
+	71, // 112: pb.MarketService.ListTransactions:output_type -> pb.ListTransactionsResponse
+	73, // 113: pb.MarketService.GetMarketMetrics:output_type -> pb.GetMarketMetricsResponse
+	76, // 114: pb.MarketService.GetHoldings:output_type -> pb.GetHoldingsResponse
+	79, // 115: pb.MarketService.GetRealizedPnLHistory:output_type -> pb.GetRealizedPnLHistoryResponse
+	82, // 116: pb.MarketService.GetTradeActivity:output_type -> pb.GetTradeActivityResponse
+	84, // 117: pb.MarketService.GetTradeStats:output_type -> pb.GetTradeStatsResponse
+	87, // 118: pb.MarketService.GetPriceSnapshots:output_type -> pb.GetPriceSnapshotsResponse
+	86, // [86:119] is the sub-list for method output_type
+	53, // [53:86] is the sub-list for method input_type
+	53, // [53:53] is the sub-list for extension type_name
+	53, // [53:53] is the sub-list for extension extendee
+	0,  // [0:53] is the sub-list for field type_name
 }
 
 func init() { file_market_proto_init() }
@@ -2164,13 +7368,19 @@ func file_market_proto_init() {
 	if File_market_proto != nil {
 		return
 	}
+	file_market_proto_msgTypes[36].OneofWrappers = []any{
+		(*ExportLedgerRow_Transaction)(nil),
+		(*ExportLedgerRow_Lot)(nil),
+		(*ExportLedgerRow_Allocation)(nil),
+		(*ExportLedgerRow_Position)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_market_proto_rawDesc), len(file_market_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   31,
+			NumMessages:   89,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -21,18 +21,39 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MarketService_Buy_FullMethodName                   = "/pb.MarketService/Buy"
-	MarketService_Sell_FullMethodName                  = "/pb.MarketService/Sell"
-	MarketService_GetGradePosition_FullMethodName      = "/pb.MarketService/GetGradePosition"
-	MarketService_GetPositions_FullMethodName          = "/pb.MarketService/GetPositions"
-	MarketService_ListGradeTransactions_FullMethodName = "/pb.MarketService/ListGradeTransactions"
-	MarketService_ListTransactions_FullMethodName      = "/pb.MarketService/ListTransactions"
-	MarketService_GetMarketMetrics_FullMethodName      = "/pb.MarketService/GetMarketMetrics"
-	MarketService_GetHoldings_FullMethodName           = "/pb.MarketService/GetHoldings"
-	MarketService_GetRealizedPnLHistory_FullMethodName = "/pb.MarketService/GetRealizedPnLHistory"
-	MarketService_GetTradeActivity_FullMethodName      = "/pb.MarketService/GetTradeActivity"
-	MarketService_GetTradeStats_FullMethodName         = "/pb.MarketService/GetTradeStats"
-	MarketService_GetPriceSnapshots_FullMethodName     = "/pb.MarketService/GetPriceSnapshots"
+	MarketService_Buy_FullMethodName                    = "/pb.MarketService/Buy"
+	MarketService_Sell_FullMethodName                   = "/pb.MarketService/Sell"
+	MarketService_CancelTransaction_FullMethodName      = "/pb.MarketService/CancelTransaction"
+	MarketService_AmendTransaction_FullMethodName       = "/pb.MarketService/AmendTransaction"
+	MarketService_ReconcileLedger_FullMethodName        = "/pb.MarketService/ReconcileLedger"
+	MarketService_ListOpenLots_FullMethodName           = "/pb.MarketService/ListOpenLots"
+	MarketService_GetLotHistory_FullMethodName          = "/pb.MarketService/GetLotHistory"
+	MarketService_GetSellAllocations_FullMethodName     = "/pb.MarketService/GetSellAllocations"
+	MarketService_ListTransactionFees_FullMethodName    = "/pb.MarketService/ListTransactionFees"
+	MarketService_GetLotAgeing_FullMethodName           = "/pb.MarketService/GetLotAgeing"
+	MarketService_GetRealizedGainsReport_FullMethodName = "/pb.MarketService/GetRealizedGainsReport"
+	MarketService_ExportLedger_FullMethodName           = "/pb.MarketService/ExportLedger"
+	MarketService_SetCostBasisMethod_FullMethodName     = "/pb.MarketService/SetCostBasisMethod"
+	MarketService_GetCostBasisMethod_FullMethodName     = "/pb.MarketService/GetCostBasisMethod"
+	MarketService_PlaceOrder_FullMethodName             = "/pb.MarketService/PlaceOrder"
+	MarketService_AmendOrder_FullMethodName             = "/pb.MarketService/AmendOrder"
+	MarketService_CancelOrder_FullMethodName            = "/pb.MarketService/CancelOrder"
+	MarketService_GetOrder_FullMethodName               = "/pb.MarketService/GetOrder"
+	MarketService_ListOrders_FullMethodName             = "/pb.MarketService/ListOrders"
+	MarketService_GetOrderBook_FullMethodName           = "/pb.MarketService/GetOrderBook"
+	MarketService_SetTradingPermissions_FullMethodName  = "/pb.MarketService/SetTradingPermissions"
+	MarketService_GetTradingPermissions_FullMethodName  = "/pb.MarketService/GetTradingPermissions"
+	MarketService_SubscribeTrades_FullMethodName        = "/pb.MarketService/SubscribeTrades"
+	MarketService_GetGradePosition_FullMethodName       = "/pb.MarketService/GetGradePosition"
+	MarketService_GetPositions_FullMethodName           = "/pb.MarketService/GetPositions"
+	MarketService_ListGradeTransactions_FullMethodName  = "/pb.MarketService/ListGradeTransactions"
+	MarketService_ListTransactions_FullMethodName       = "/pb.MarketService/ListTransactions"
+	MarketService_GetMarketMetrics_FullMethodName       = "/pb.MarketService/GetMarketMetrics"
+	MarketService_GetHoldings_FullMethodName            = "/pb.MarketService/GetHoldings"
+	MarketService_GetRealizedPnLHistory_FullMethodName  = "/pb.MarketService/GetRealizedPnLHistory"
+	MarketService_GetTradeActivity_FullMethodName       = "/pb.MarketService/GetTradeActivity"
+	MarketService_GetTradeStats_FullMethodName          = "/pb.MarketService/GetTradeStats"
+	MarketService_GetPriceSnapshots_FullMethodName      = "/pb.MarketService/GetPriceSnapshots"
 )
 
 // MarketServiceClient is the client API for MarketService service.
@@ -41,6 +62,27 @@ const (
 type MarketServiceClient interface {
 	Buy(ctx context.Context, in *BuyRequest, opts ...grpc.CallOption) (*BuyResponse, error)
 	Sell(ctx context.Context, in *SellRequest, opts ...grpc.CallOption) (*SellResponse, error)
+	CancelTransaction(ctx context.Context, in *CancelTransactionRequest, opts ...grpc.CallOption) (*CancelTransactionResponse, error)
+	AmendTransaction(ctx context.Context, in *AmendTransactionRequest, opts ...grpc.CallOption) (*AmendTransactionResponse, error)
+	ReconcileLedger(ctx context.Context, in *ReconcileLedgerRequest, opts ...grpc.CallOption) (*ReconcileLedgerResponse, error)
+	ListOpenLots(ctx context.Context, in *ListOpenLotsRequest, opts ...grpc.CallOption) (*ListOpenLotsResponse, error)
+	GetLotHistory(ctx context.Context, in *GetLotHistoryRequest, opts ...grpc.CallOption) (*GetLotHistoryResponse, error)
+	GetSellAllocations(ctx context.Context, in *GetSellAllocationsRequest, opts ...grpc.CallOption) (*GetSellAllocationsResponse, error)
+	ListTransactionFees(ctx context.Context, in *ListTransactionFeesRequest, opts ...grpc.CallOption) (*ListTransactionFeesResponse, error)
+	GetLotAgeing(ctx context.Context, in *GetLotAgeingRequest, opts ...grpc.CallOption) (*GetLotAgeingResponse, error)
+	GetRealizedGainsReport(ctx context.Context, in *GetRealizedGainsReportRequest, opts ...grpc.CallOption) (*GetRealizedGainsReportResponse, error)
+	ExportLedger(ctx context.Context, in *ExportLedgerRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportLedgerRow], error)
+	SetCostBasisMethod(ctx context.Context, in *SetCostBasisMethodRequest, opts ...grpc.CallOption) (*SetCostBasisMethodResponse, error)
+	GetCostBasisMethod(ctx context.Context, in *GetCostBasisMethodRequest, opts ...grpc.CallOption) (*GetCostBasisMethodResponse, error)
+	PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error)
+	AmendOrder(ctx context.Context, in *AmendOrderRequest, opts ...grpc.CallOption) (*AmendOrderResponse, error)
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error)
+	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
+	GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*GetOrderBookResponse, error)
+	SetTradingPermissions(ctx context.Context, in *SetTradingPermissionsRequest, opts ...grpc.CallOption) (*SetTradingPermissionsResponse, error)
+	GetTradingPermissions(ctx context.Context, in *GetTradingPermissionsRequest, opts ...grpc.CallOption) (*GetTradingPermissionsResponse, error)
+	SubscribeTrades(ctx context.Context, in *SubscribeTradesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TradeEvent], error)
 	GetGradePosition(ctx context.Context, in *GetGradePositionRequest, opts ...grpc.CallOption) (*GetGradePositionResponse, error)
 	GetPositions(ctx context.Context, in *GetPositionsRequest, opts ...grpc.CallOption) (*GetPositionsResponse, error)
 	ListGradeTransactions(ctx context.Context, in *ListGradeTransactionsRequest, opts ...grpc.CallOption) (*ListGradeTransactionsResponse, error)
@@ -81,6 +123,234 @@ func (c *marketServiceClient) Sell(ctx context.Context, in *SellRequest, opts ..
 	return out, nil
 }
 
+func (c *marketServiceClient) CancelTransaction(ctx context.Context, in *CancelTransactionRequest, opts ...grpc.CallOption) (*CancelTransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelTransactionResponse)
+	err := c.cc.Invoke(ctx, MarketService_CancelTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) AmendTransaction(ctx context.Context, in *AmendTransactionRequest, opts ...grpc.CallOption) (*AmendTransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AmendTransactionResponse)
+	err := c.cc.Invoke(ctx, MarketService_AmendTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) ReconcileLedger(ctx context.Context, in *ReconcileLedgerRequest, opts ...grpc.CallOption) (*ReconcileLedgerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReconcileLedgerResponse)
+	err := c.cc.Invoke(ctx, MarketService_ReconcileLedger_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) ListOpenLots(ctx context.Context, in *ListOpenLotsRequest, opts ...grpc.CallOption) (*ListOpenLotsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOpenLotsResponse)
+	err := c.cc.Invoke(ctx, MarketService_ListOpenLots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) GetLotHistory(ctx context.Context, in *GetLotHistoryRequest, opts ...grpc.CallOption) (*GetLotHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLotHistoryResponse)
+	err := c.cc.Invoke(ctx, MarketService_GetLotHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) GetSellAllocations(ctx context.Context, in *GetSellAllocationsRequest, opts ...grpc.CallOption) (*GetSellAllocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSellAllocationsResponse)
+	err := c.cc.Invoke(ctx, MarketService_GetSellAllocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) ListTransactionFees(ctx context.Context, in *ListTransactionFeesRequest, opts ...grpc.CallOption) (*ListTransactionFeesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransactionFeesResponse)
+	err := c.cc.Invoke(ctx, MarketService_ListTransactionFees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) GetLotAgeing(ctx context.Context, in *GetLotAgeingRequest, opts ...grpc.CallOption) (*GetLotAgeingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLotAgeingResponse)
+	err := c.cc.Invoke(ctx, MarketService_GetLotAgeing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) GetRealizedGainsReport(ctx context.Context, in *GetRealizedGainsReportRequest, opts ...grpc.CallOption) (*GetRealizedGainsReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRealizedGainsReportResponse)
+	err := c.cc.Invoke(ctx, MarketService_GetRealizedGainsReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) ExportLedger(ctx context.Context, in *ExportLedgerRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportLedgerRow], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MarketService_ServiceDesc.Streams[0], MarketService_ExportLedger_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportLedgerRequest, ExportLedgerRow]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketService_ExportLedgerClient = grpc.ServerStreamingClient[ExportLedgerRow]
+
+func (c *marketServiceClient) SetCostBasisMethod(ctx context.Context, in *SetCostBasisMethodRequest, opts ...grpc.CallOption) (*SetCostBasisMethodResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetCostBasisMethodResponse)
+	err := c.cc.Invoke(ctx, MarketService_SetCostBasisMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) GetCostBasisMethod(ctx context.Context, in *GetCostBasisMethodRequest, opts ...grpc.CallOption) (*GetCostBasisMethodResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCostBasisMethodResponse)
+	err := c.cc.Invoke(ctx, MarketService_GetCostBasisMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlaceOrderResponse)
+	err := c.cc.Invoke(ctx, MarketService_PlaceOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) AmendOrder(ctx context.Context, in *AmendOrderRequest, opts ...grpc.CallOption) (*AmendOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AmendOrderResponse)
+	err := c.cc.Invoke(ctx, MarketService_AmendOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelOrderResponse)
+	err := c.cc.Invoke(ctx, MarketService_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderResponse)
+	err := c.cc.Invoke(ctx, MarketService_GetOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrdersResponse)
+	err := c.cc.Invoke(ctx, MarketService_ListOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*GetOrderBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderBookResponse)
+	err := c.cc.Invoke(ctx, MarketService_GetOrderBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) SetTradingPermissions(ctx context.Context, in *SetTradingPermissionsRequest, opts ...grpc.CallOption) (*SetTradingPermissionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetTradingPermissionsResponse)
+	err := c.cc.Invoke(ctx, MarketService_SetTradingPermissions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) GetTradingPermissions(ctx context.Context, in *GetTradingPermissionsRequest, opts ...grpc.CallOption) (*GetTradingPermissionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTradingPermissionsResponse)
+	err := c.cc.Invoke(ctx, MarketService_GetTradingPermissions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketServiceClient) SubscribeTrades(ctx context.Context, in *SubscribeTradesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TradeEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MarketService_ServiceDesc.Streams[1], MarketService_SubscribeTrades_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeTradesRequest, TradeEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketService_SubscribeTradesClient = grpc.ServerStreamingClient[TradeEvent]
+
 func (c *marketServiceClient) GetGradePosition(ctx context.Context, in *GetGradePositionRequest, opts ...grpc.CallOption) (*GetGradePositionResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetGradePositionResponse)
@@ -187,6 +457,27 @@ func (c *marketServiceClient) GetPriceSnapshots(ctx context.Context, in *GetPric
 type MarketServiceServer interface {
 	Buy(context.Context, *BuyRequest) (*BuyResponse, error)
 	Sell(context.Context, *SellRequest) (*SellResponse, error)
+	CancelTransaction(context.Context, *CancelTransactionRequest) (*CancelTransactionResponse, error)
+	AmendTransaction(context.Context, *AmendTransactionRequest) (*AmendTransactionResponse, error)
+	ReconcileLedger(context.Context, *ReconcileLedgerRequest) (*ReconcileLedgerResponse, error)
+	ListOpenLots(context.Context, *ListOpenLotsRequest) (*ListOpenLotsResponse, error)
+	GetLotHistory(context.Context, *GetLotHistoryRequest) (*GetLotHistoryResponse, error)
+	GetSellAllocations(context.Context, *GetSellAllocationsRequest) (*GetSellAllocationsResponse, error)
+	ListTransactionFees(context.Context, *ListTransactionFeesRequest) (*ListTransactionFeesResponse, error)
+	GetLotAgeing(context.Context, *GetLotAgeingRequest) (*GetLotAgeingResponse, error)
+	GetRealizedGainsReport(context.Context, *GetRealizedGainsReportRequest) (*GetRealizedGainsReportResponse, error)
+	ExportLedger(*ExportLedgerRequest, grpc.ServerStreamingServer[ExportLedgerRow]) error
+	SetCostBasisMethod(context.Context, *SetCostBasisMethodRequest) (*SetCostBasisMethodResponse, error)
+	GetCostBasisMethod(context.Context, *GetCostBasisMethodRequest) (*GetCostBasisMethodResponse, error)
+	PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error)
+	AmendOrder(context.Context, *AmendOrderRequest) (*AmendOrderResponse, error)
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error)
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
+	GetOrderBook(context.Context, *GetOrderBookRequest) (*GetOrderBookResponse, error)
+	SetTradingPermissions(context.Context, *SetTradingPermissionsRequest) (*SetTradingPermissionsResponse, error)
+	GetTradingPermissions(context.Context, *GetTradingPermissionsRequest) (*GetTradingPermissionsResponse, error)
+	SubscribeTrades(*SubscribeTradesRequest, grpc.ServerStreamingServer[TradeEvent]) error
 	GetGradePosition(context.Context, *GetGradePositionRequest) (*GetGradePositionResponse, error)
 	GetPositions(context.Context, *GetPositionsRequest) (*GetPositionsResponse, error)
 	ListGradeTransactions(context.Context, *ListGradeTransactionsRequest) (*ListGradeTransactionsResponse, error)
@@ -213,6 +504,69 @@ func (UnimplementedMarketServiceServer) Buy(context.Context, *BuyRequest) (*BuyR
 func (UnimplementedMarketServiceServer) Sell(context.Context, *SellRequest) (*SellResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Sell not implemented")
 }
+func (UnimplementedMarketServiceServer) CancelTransaction(context.Context, *CancelTransactionRequest) (*CancelTransactionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelTransaction not implemented")
+}
+func (UnimplementedMarketServiceServer) AmendTransaction(context.Context, *AmendTransactionRequest) (*AmendTransactionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AmendTransaction not implemented")
+}
+func (UnimplementedMarketServiceServer) ReconcileLedger(context.Context, *ReconcileLedgerRequest) (*ReconcileLedgerResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReconcileLedger not implemented")
+}
+func (UnimplementedMarketServiceServer) ListOpenLots(context.Context, *ListOpenLotsRequest) (*ListOpenLotsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListOpenLots not implemented")
+}
+func (UnimplementedMarketServiceServer) GetLotHistory(context.Context, *GetLotHistoryRequest) (*GetLotHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetLotHistory not implemented")
+}
+func (UnimplementedMarketServiceServer) GetSellAllocations(context.Context, *GetSellAllocationsRequest) (*GetSellAllocationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSellAllocations not implemented")
+}
+func (UnimplementedMarketServiceServer) ListTransactionFees(context.Context, *ListTransactionFeesRequest) (*ListTransactionFeesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTransactionFees not implemented")
+}
+func (UnimplementedMarketServiceServer) GetLotAgeing(context.Context, *GetLotAgeingRequest) (*GetLotAgeingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetLotAgeing not implemented")
+}
+func (UnimplementedMarketServiceServer) GetRealizedGainsReport(context.Context, *GetRealizedGainsReportRequest) (*GetRealizedGainsReportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRealizedGainsReport not implemented")
+}
+func (UnimplementedMarketServiceServer) ExportLedger(*ExportLedgerRequest, grpc.ServerStreamingServer[ExportLedgerRow]) error {
+	return status.Error(codes.Unimplemented, "method ExportLedger not implemented")
+}
+func (UnimplementedMarketServiceServer) SetCostBasisMethod(context.Context, *SetCostBasisMethodRequest) (*SetCostBasisMethodResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetCostBasisMethod not implemented")
+}
+func (UnimplementedMarketServiceServer) GetCostBasisMethod(context.Context, *GetCostBasisMethodRequest) (*GetCostBasisMethodResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCostBasisMethod not implemented")
+}
+func (UnimplementedMarketServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedMarketServiceServer) AmendOrder(context.Context, *AmendOrderRequest) (*AmendOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AmendOrder not implemented")
+}
+func (UnimplementedMarketServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedMarketServiceServer) GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedMarketServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListOrders not implemented")
+}
+func (UnimplementedMarketServiceServer) GetOrderBook(context.Context, *GetOrderBookRequest) (*GetOrderBookResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOrderBook not implemented")
+}
+func (UnimplementedMarketServiceServer) SetTradingPermissions(context.Context, *SetTradingPermissionsRequest) (*SetTradingPermissionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetTradingPermissions not implemented")
+}
+func (UnimplementedMarketServiceServer) GetTradingPermissions(context.Context, *GetTradingPermissionsRequest) (*GetTradingPermissionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTradingPermissions not implemented")
+}
+func (UnimplementedMarketServiceServer) SubscribeTrades(*SubscribeTradesRequest, grpc.ServerStreamingServer[TradeEvent]) error {
+	return status.Error(codes.Unimplemented, "method SubscribeTrades not implemented")
+}
 func (UnimplementedMarketServiceServer) GetGradePosition(context.Context, *GetGradePositionRequest) (*GetGradePositionResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetGradePosition not implemented")
 }
@@ -300,6 +654,370 @@ func _MarketService_Sell_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MarketService_CancelTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).CancelTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_CancelTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).CancelTransaction(ctx, req.(*CancelTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_AmendTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AmendTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).AmendTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_AmendTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).AmendTransaction(ctx, req.(*AmendTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_ReconcileLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconcileLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).ReconcileLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_ReconcileLedger_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).ReconcileLedger(ctx, req.(*ReconcileLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_ListOpenLots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOpenLotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).ListOpenLots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_ListOpenLots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).ListOpenLots(ctx, req.(*ListOpenLotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_GetLotHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLotHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).GetLotHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_GetLotHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).GetLotHistory(ctx, req.(*GetLotHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_GetSellAllocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSellAllocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).GetSellAllocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_GetSellAllocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).GetSellAllocations(ctx, req.(*GetSellAllocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_ListTransactionFees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionFeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).ListTransactionFees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_ListTransactionFees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).ListTransactionFees(ctx, req.(*ListTransactionFeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_GetLotAgeing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLotAgeingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).GetLotAgeing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_GetLotAgeing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).GetLotAgeing(ctx, req.(*GetLotAgeingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_GetRealizedGainsReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRealizedGainsReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).GetRealizedGainsReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_GetRealizedGainsReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).GetRealizedGainsReport(ctx, req.(*GetRealizedGainsReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_ExportLedger_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportLedgerRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketServiceServer).ExportLedger(m, &grpc.GenericServerStream[ExportLedgerRequest, ExportLedgerRow]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketService_ExportLedgerServer = grpc.ServerStreamingServer[ExportLedgerRow]
+
+func _MarketService_SetCostBasisMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCostBasisMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).SetCostBasisMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_SetCostBasisMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).SetCostBasisMethod(ctx, req.(*SetCostBasisMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_GetCostBasisMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCostBasisMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).GetCostBasisMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_GetCostBasisMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).GetCostBasisMethod(ctx, req.(*GetCostBasisMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_PlaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_AmendOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AmendOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).AmendOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_AmendOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).AmendOrder(ctx, req.(*AmendOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).ListOrders(ctx, req.(*ListOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_GetOrderBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).GetOrderBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_GetOrderBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).GetOrderBook(ctx, req.(*GetOrderBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_SetTradingPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTradingPermissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).SetTradingPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_SetTradingPermissions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).SetTradingPermissions(ctx, req.(*SetTradingPermissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_GetTradingPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTradingPermissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketServiceServer).GetTradingPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketService_GetTradingPermissions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketServiceServer).GetTradingPermissions(ctx, req.(*GetTradingPermissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketService_SubscribeTrades_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeTradesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketServiceServer).SubscribeTrades(m, &grpc.GenericServerStream[SubscribeTradesRequest, TradeEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketService_SubscribeTradesServer = grpc.ServerStreamingServer[TradeEvent]
+
 func _MarketService_GetGradePosition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetGradePositionRequest)
 	if err := dec(in); err != nil {
@@ -495,6 +1213,82 @@ var MarketService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Sell",
 			Handler:    _MarketService_Sell_Handler,
 		},
+		{
+			MethodName: "CancelTransaction",
+			Handler:    _MarketService_CancelTransaction_Handler,
+		},
+		{
+			MethodName: "AmendTransaction",
+			Handler:    _MarketService_AmendTransaction_Handler,
+		},
+		{
+			MethodName: "ReconcileLedger",
+			Handler:    _MarketService_ReconcileLedger_Handler,
+		},
+		{
+			MethodName: "ListOpenLots",
+			Handler:    _MarketService_ListOpenLots_Handler,
+		},
+		{
+			MethodName: "GetLotHistory",
+			Handler:    _MarketService_GetLotHistory_Handler,
+		},
+		{
+			MethodName: "GetSellAllocations",
+			Handler:    _MarketService_GetSellAllocations_Handler,
+		},
+		{
+			MethodName: "ListTransactionFees",
+			Handler:    _MarketService_ListTransactionFees_Handler,
+		},
+		{
+			MethodName: "GetLotAgeing",
+			Handler:    _MarketService_GetLotAgeing_Handler,
+		},
+		{
+			MethodName: "GetRealizedGainsReport",
+			Handler:    _MarketService_GetRealizedGainsReport_Handler,
+		},
+		{
+			MethodName: "SetCostBasisMethod",
+			Handler:    _MarketService_SetCostBasisMethod_Handler,
+		},
+		{
+			MethodName: "GetCostBasisMethod",
+			Handler:    _MarketService_GetCostBasisMethod_Handler,
+		},
+		{
+			MethodName: "PlaceOrder",
+			Handler:    _MarketService_PlaceOrder_Handler,
+		},
+		{
+			MethodName: "AmendOrder",
+			Handler:    _MarketService_AmendOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _MarketService_CancelOrder_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _MarketService_GetOrder_Handler,
+		},
+		{
+			MethodName: "ListOrders",
+			Handler:    _MarketService_ListOrders_Handler,
+		},
+		{
+			MethodName: "GetOrderBook",
+			Handler:    _MarketService_GetOrderBook_Handler,
+		},
+		{
+			MethodName: "SetTradingPermissions",
+			Handler:    _MarketService_SetTradingPermissions_Handler,
+		},
+		{
+			MethodName: "GetTradingPermissions",
+			Handler:    _MarketService_GetTradingPermissions_Handler,
+		},
 		{
 			MethodName: "GetGradePosition",
 			Handler:    _MarketService_GetGradePosition_Handler,
@@ -536,6 +1330,17 @@ var MarketService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _MarketService_GetPriceSnapshots_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportLedger",
+			Handler:       _MarketService_ExportLedger_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeTrades",
+			Handler:       _MarketService_SubscribeTrades_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "market.proto",
 }
@@ -0,0 +1,193 @@
+package market
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func at(second int) time.Time {
+	return time.Date(2026, 1, 1, 9, 0, second, 0, time.UTC)
+}
+
+func buyEvent(id string, second int, qty, price, currency string) ledgerEvent {
+	return ledgerEvent{at: at(second), id: id, lot: &LedgerLot{
+		BuyLot:   BuyLot{ID: "lot-" + id, TransactionID: id, OriginalQty: dec(qty), Price: dec(price)},
+		Currency: currency,
+	}}
+}
+
+func sellEvent(id string, second int, qty, price, method, currency string) ledgerEvent {
+	return ledgerEvent{at: at(second), id: id, sell: &Transaction{
+		ID: id, Type: "SELL", Quantity: dec(qty), Price: dec(price), CostBasisMethod: method, Currency: currency,
+	}}
+}
+
+func coverEvent(id string, second int, qty, shortPrice, coverPrice, currency string) ledgerEvent {
+	q := dec(qty)
+	return ledgerEvent{at: at(second), id: id, covers: []*LedgerCover{{
+		ShortCover: ShortCover{
+			BuyTransactionID: id, Quantity: q, ShortPrice: dec(shortPrice), CoverPrice: dec(coverPrice),
+			RealizedPnL: q.Mul(dec(shortPrice).Sub(dec(coverPrice))),
+		},
+		Currency: currency,
+	}}}
+}
+
+func allocation(qty, buyPrice, sellPrice string) *SellAllocation {
+	q := dec(qty)
+	return &SellAllocation{
+		Quantity: q, BuyPrice: dec(buyPrice), SellPrice: dec(sellPrice),
+		RealizedPnL: q.Mul(dec(sellPrice).Sub(dec(buyPrice))).Round(2),
+	}
+}
+
+// withCloseAdjustment books a weighted-average closing difference on an allocation, as
+// allocateSell does on the last one.
+func withCloseAdjustment(a *SellAllocation, adjustment string) *SellAllocation {
+	a.CloseAdjustment = dec(adjustment)
+	a.RealizedPnL = a.RealizedPnL.Sub(a.CloseAdjustment)
+	return a
+}
+
+func shortLot(qty, price string) *LedgerShortLot {
+	return &LedgerShortLot{ShortLot: ShortLot{OriginalQty: dec(qty), RemainingQty: dec(qty), Price: dec(price)}}
+}
+
+func TestReplayPosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []ledgerEvent
+		allocs   map[string][]*SellAllocation
+		shorts   map[string][]*LedgerShortLot
+		currency string
+		want     wantPosition
+	}{
+		{
+			name:     "no events is a flat position in the default currency",
+			currency: "INR",
+			want:     wantPosition{"0", "0", "0"},
+		},
+		{
+			name: "fifo sell across two lots",
+			events: []ledgerEvent{
+				buyEvent("b1", 1, "10", "100", "INR"),
+				buyEvent("b2", 2, "10", "120", "INR"),
+				sellEvent("s1", 3, "15", "150", CostBasisFIFO, "INR"),
+			},
+			allocs:   map[string][]*SellAllocation{"s1": {allocation("10", "100", "150"), allocation("5", "120", "150")}},
+			currency: "INR",
+			want:     wantPosition{"5", "600", "650"},
+		},
+		{
+			name: "buy booked in the same second replays before the sell",
+			events: []ledgerEvent{
+				sellEvent("a-sell", 1, "4", "130", CostBasisFIFO, "INR"),
+				buyEvent("b-buy", 1, "10", "100", "INR"),
+			},
+			allocs:   map[string][]*SellAllocation{"a-sell": {allocation("4", "100", "130")}},
+			currency: "INR",
+			want:     wantPosition{"6", "600", "120"},
+		},
+		{
+			name: "weighted average full close releases the rounding remainder into pnl",
+			events: []ledgerEvent{
+				buyEvent("b1", 1, "100", "1", "INR"),
+				buyEvent("b2", 2, "200", "1.01", "INR"),
+				sellEvent("s1", 3, "300", "2", CostBasisWeightedAverage, "INR"),
+			},
+			// 300 at the 1.0067 average costs 302.01 against 302 booked.
+			allocs:   map[string][]*SellAllocation{"s1": {allocation("300", "1.0067", "2")}},
+			currency: "INR",
+			want:     wantPosition{"0", "0", "298"},
+		},
+		{
+			name: "weighted average full close uses the stored close adjustment",
+			events: []ledgerEvent{
+				buyEvent("b1", 1, "100", "1", "INR"),
+				buyEvent("b2", 2, "200", "1.01", "INR"),
+				sellEvent("s1", 3, "300", "2", CostBasisWeightedAverage, "INR"),
+			},
+			allocs: map[string][]*SellAllocation{"s1": {
+				allocation("100", "1.0067", "2"),
+				withCloseAdjustment(allocation("200", "1.0067", "2"), "-0.01"),
+			}},
+			currency: "INR",
+			want:     wantPosition{"0", "0", "298"},
+		},
+		{
+			name: "weighted average partial sell keeps the average cost",
+			events: []ledgerEvent{
+				buyEvent("b1", 1, "100", "1", "INR"),
+				buyEvent("b2", 2, "200", "1.01", "INR"),
+				sellEvent("s1", 3, "150", "2", CostBasisWeightedAverage, "INR"),
+			},
+			allocs:   map[string][]*SellAllocation{"s1": {allocation("150", "1.0067", "2")}},
+			currency: "INR",
+			want:     wantPosition{"150", "150.99", "149"},
+		},
+		{
+			name: "sell beyond the lots opens a short",
+			events: []ledgerEvent{
+				buyEvent("b1", 1, "5", "100", "INR"),
+				sellEvent("s1", 2, "8", "150", CostBasisFIFO, "INR"),
+			},
+			allocs:   map[string][]*SellAllocation{"s1": {allocation("5", "100", "150")}},
+			shorts:   map[string][]*LedgerShortLot{"s1": {shortLot("3", "150")}},
+			currency: "INR",
+			want:     wantPosition{"-3", "-450", "250"},
+		},
+		{
+			name: "cover closes the short at the short price",
+			events: []ledgerEvent{
+				sellEvent("s1", 1, "10", "150", CostBasisFIFO, "INR"),
+				coverEvent("b1", 2, "10", "150", "120", "INR"),
+			},
+			shorts:   map[string][]*LedgerShortLot{"s1": {shortLot("10", "150")}},
+			currency: "INR",
+			want:     wantPosition{"0", "0", "300"},
+		},
+		{
+			name:     "amounts round to the minor unit of the trade currency",
+			events:   []ledgerEvent{buyEvent("b1", 1, "3", "100.5", "JPY")},
+			currency: "JPY",
+			want:     wantPosition{"3", "302", "0"},
+		},
+		{
+			name: "currency follows the first trade after the position went flat",
+			events: []ledgerEvent{
+				buyEvent("b1", 1, "1", "10", "JPY"),
+				sellEvent("s1", 2, "1", "12", CostBasisFIFO, "JPY"),
+				buyEvent("b2", 3, "2", "5.555", "USD"),
+			},
+			allocs:   map[string][]*SellAllocation{"s1": {allocation("1", "10", "12")}},
+			currency: "USD",
+			want:     wantPosition{"2", "11.11", "2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := positionKey{userID: testUser, spiceGradeID: testGrade}
+			pos := replayPosition(key, tt.events, tt.allocs, tt.shorts)
+			if pos.UserID != testUser || pos.SpiceGradeID != testGrade {
+				t.Fatalf("position key = %s/%s", pos.UserID, pos.SpiceGradeID)
+			}
+			if pos.Currency != tt.currency {
+				t.Errorf("currency = %q, want %q", pos.Currency, tt.currency)
+			}
+			for _, f := range []struct {
+				field     string
+				got, want decimal.Decimal
+			}{
+				{"total_qty", pos.TotalQty, dec(tt.want.qty)},
+				{"total_cost", pos.TotalCost, dec(tt.want.cost)},
+				{"realized_pnl", pos.RealizedPnL, dec(tt.want.pnl)},
+			} {
+				if !f.got.Equal(f.want) {
+					t.Errorf("%s = %s, want %s", f.field, f.got, f.want)
+				}
+			}
+		})
+	}
+}
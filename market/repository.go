@@ -3,49 +3,166 @@ package market
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+	"github.com/go-sql-driver/mysql"
+	"github.com/shopspring/decimal"
 )
 
+// mysqlErrDuplicateEntry is ER_DUP_ENTRY, raised when a UNIQUE key is violated.
+const mysqlErrDuplicateEntry = 1062
+
 type Repository interface {
 	Close()
 
 	// Transactions
 	InsertTransaction(ctx context.Context, tx *Transaction) (string, error)
+	GetTransactionByIdempotencyKey(ctx context.Context, userID, key string) (*Transaction, error)
 	GetTransactionByID(ctx context.Context, id string) (*Transaction, error)
+	// LockTransaction reads a transaction with FOR UPDATE — must be called inside a DB transaction.
+	LockTransaction(ctx context.Context, id string) (*Transaction, error)
+	// ListActiveSellsSince returns ACTIVE sells of one grade using a given cost-basis method,
+	// created at or after since, with FOR UPDATE.
+	ListActiveSellsSince(ctx context.Context, userID string, spiceGradeID string, method string, since time.Time) ([]*Transaction, error)
+	// SetTransactionStatus flips ACTIVE → CANCELLED; no other column of a transaction is ever updated.
+	SetTransactionStatus(ctx context.Context, id string, status string) error
 	ListGradeTransactionsByUser(ctx context.Context, userID, spiceGradeID string, skip, take uint, sort, dateFrom, dateTo string) ([]*Transaction, error)
 	ListTransactionsByUser(ctx context.Context, userID string, skip, take uint, spiceGradeID string, spiceGradeIDs []string, sort, dateFrom, dateTo string) ([]*Transaction, error)
 
+	// Fees — schedules come from the control catalog; lines are written by InsertTransaction.
+	// ListFeeSchedules returns the schedules of a grade and of its product category for one
+	// trade side (BOTH included) effective on or before a date, newest first.
+	ListFeeSchedules(ctx context.Context, spiceGradeID string, side string, on time.Time) ([]*FeeSchedule, error)
+	ListTransactionFees(ctx context.Context, transactionID string) ([]*TransactionFee, error)
+
 	// Buy Lots (inventory)
 	InsertBuyLot(ctx context.Context, lot *BuyLot) (string, error)
-	// GetOpenBuyLots returns lots with remaining_qty > 0 in FIFO order (oldest trade_date first).
+	// GetOpenBuyLots returns lots with remaining_qty > 0, newest first for LIFO and
+	// oldest trade_date first for every other method.
 	// Uses FOR UPDATE — must be called inside a DB transaction.
-	GetOpenBuyLots(ctx context.Context, userID string, spiceGradeID string) ([]*BuyLot, error)
-	DeductBuyLotQty(ctx context.Context, lotID string, deductQty float64) error
+	GetOpenBuyLots(ctx context.Context, userID string, spiceGradeID string, method string) ([]*BuyLot, error)
+	DeductBuyLotQty(ctx context.Context, lotID string, deductQty decimal.Decimal) error
+	// RestoreBuyLotQty adds back quantity released by a reversed sell allocation.
+	RestoreBuyLotQty(ctx context.Context, lotID string, qty decimal.Decimal) error
+	// LockBuyLotByTransaction returns the lot created by a BUY, with FOR UPDATE.
+	LockBuyLotByTransaction(ctx context.Context, transactionID string) (*BuyLot, error)
+	// CloseBuyLot zeroes a fully unconsumed lot and links it to the REVERSAL that cancelled its BUY.
+	CloseBuyLot(ctx context.Context, lotID string, reversalID string) error
 
 	// Sell Allocations (FIFO audit trail)
 	InsertSellAllocation(ctx context.Context, alloc *SellAllocation) error
+	// ListActiveAllocationsBySell / ByLot return allocations not yet reversed, with FOR UPDATE.
+	ListActiveAllocationsBySell(ctx context.Context, sellTransactionID string) ([]*SellAllocation, error)
+	ListActiveAllocationsByLot(ctx context.Context, lotID string) ([]*SellAllocation, error)
+	ReverseSellAllocation(ctx context.Context, allocID string, reversalID string) error
+
+	// Short lots and covers (short selling)
+	InsertShortLot(ctx context.Context, lot *ShortLot) error
+	// GetOpenShortLots returns short lots with remaining_qty > 0, oldest trade_date first.
+	// Uses FOR UPDATE — must be called inside a DB transaction.
+	GetOpenShortLots(ctx context.Context, userID string, spiceGradeID string) ([]*ShortLot, error)
+	DeductShortLotQty(ctx context.Context, lotID string, qty decimal.Decimal) error
+	// RestoreShortLotQty reopens quantity released by a reversed cover.
+	RestoreShortLotQty(ctx context.Context, lotID string, qty decimal.Decimal) error
+	// ListActiveShortLotsBySell returns the unreversed short lots a SELL opened, with FOR UPDATE.
+	ListActiveShortLotsBySell(ctx context.Context, sellTransactionID string) ([]*ShortLot, error)
+	// CloseShortLot zeroes an uncovered short lot and links it to the REVERSAL that undid its SELL.
+	CloseShortLot(ctx context.Context, lotID string, reversalID string) error
+	InsertShortCover(ctx context.Context, cover *ShortCover) error
+	// ListActiveCoversByBuy returns the unreversed covers of a BUY, with FOR UPDATE.
+	ListActiveCoversByBuy(ctx context.Context, buyTransactionID string) ([]*ShortCover, error)
+	ReverseShortCover(ctx context.Context, coverID string, reversalID string) error
+
+	// Trading permissions — GetTradingPermissions returns sql.ErrNoRows when none are stored.
+	GetTradingPermissions(ctx context.Context, userID string) (*TradingPermissions, error)
+	UpsertTradingPermissions(ctx context.Context, perms *TradingPermissions) error
+
+	// Order book — LockOrderBook serializes placement and matching per grade and must
+	// be called inside a DB transaction before any order of that grade is locked.
+	LockOrderBook(ctx context.Context, spiceGradeID string) error
+	InsertOrder(ctx context.Context, order *Order) error
+	// GetOrder reads an order without locking; LockOrder adds FOR UPDATE.
+	// Both return sql.ErrNoRows when it does not exist.
+	GetOrder(ctx context.Context, orderID string) (*Order, error)
+	LockOrder(ctx context.Context, orderID string) (*Order, error)
+	// ListCrossingOrders returns resting orders of side that trade at limitPrice or better,
+	// in price-time priority, skipping excludeUserID's own orders. Uses FOR UPDATE.
+	ListCrossingOrders(ctx context.Context, spiceGradeID string, currency string, side string, limitPrice decimal.Decimal, excludeUserID string) ([]*Order, error)
+	UpdateOrder(ctx context.Context, order *Order) error
+	// SumRestingSellQty totals the unfilled quantity of a user's resting SELL orders on a grade.
+	SumRestingSellQty(ctx context.Context, userID string, spiceGradeID string, excludeOrderID string) (decimal.Decimal, error)
+	InsertOrderFill(ctx context.Context, fill *OrderFill) error
+	ListOrderFills(ctx context.Context, orderID string) ([]*OrderFill, error)
+	ListOrders(ctx context.Context, filter OrderFilter) ([]*Order, error)
+	// GetOrderBookDepth aggregates resting orders by price, depth levels per side.
+	GetOrderBookDepth(ctx context.Context, spiceGradeID string, currency string, depth uint) (*OrderBook, error)
 
 	// Positions (aggregate state)
 	UpsertPosition(ctx context.Context, pos *Position) error
 	GetGradePosition(ctx context.Context, userID string, spiceGradeID string) (*Position, error)
 	GetPositionsByUser(ctx context.Context, userID string) ([]*Position, error)
+	// LockGradePosition reads a position with FOR UPDATE — must be called inside a DB transaction.
+	LockGradePosition(ctx context.Context, userID string, spiceGradeID string) (*Position, error)
+
+	// Lot queries (read-only) — an empty userID widens the scope to every account.
+	ListOpenLots(ctx context.Context, userID, spiceGradeID string, skip, take uint, sort, dateFrom, dateTo string) ([]*BuyLot, error)
+	GetBuyLot(ctx context.Context, lotID string) (*BuyLot, error)
+	ListSellAllocations(ctx context.Context, filter AllocationFilter) ([]*AllocationDetail, error)
+	ListRealizedGains(ctx context.Context, userID, spiceGradeID, dateFrom, dateTo string) ([]*RealizedGain, error)
+	ExportTransactions(ctx context.Context, filter ExportFilter, fn func(*LedgerExportRow) error) error
+	ExportBuyLots(ctx context.Context, filter ExportFilter, fn func(*LedgerExportRow) error) error
+	ExportSellAllocations(ctx context.Context, filter ExportFilter, fn func(*LedgerExportRow) error) error
+	ExportPositions(ctx context.Context, filter ExportFilter, fn func(pos *Position, productName, gradeName string) error) error
+	// ListAgeingLots joins open lots with grade.shelf_life_days from the control catalog.
+	ListAgeingLots(ctx context.Context, userID string) ([]AgeingLotRow, error)
+
+	// Reconciliation — empty userID / spiceGradeID widen the scope to all users / grades.
+	ListLedgerLots(ctx context.Context, userID, spiceGradeID string) ([]*LedgerLot, error)
+	ListLedgerSells(ctx context.Context, userID, spiceGradeID string) ([]*Transaction, error)
+	ListLedgerAllocations(ctx context.Context, userID, spiceGradeID string) ([]*SellAllocation, error)
+	ListLedgerShortLots(ctx context.Context, userID, spiceGradeID string) ([]*LedgerShortLot, error)
+	ListLedgerCovers(ctx context.Context, userID, spiceGradeID string) ([]*LedgerCover, error)
+	ListStoredPositions(ctx context.Context, userID, spiceGradeID string) ([]*Position, error)
+	SetBuyLotRemaining(ctx context.Context, lotID string, remainingQty decimal.Decimal) error
+	SetShortLotRemaining(ctx context.Context, lotID string, remainingQty decimal.Decimal) error
+	ReplacePosition(ctx context.Context, pos *Position) error
+
+	// Cost-basis preferences (account default and per-grade overrides)
+	UpsertCostBasisPreference(ctx context.Context, pref *CostBasisPreference) error
+	// GetCostBasisPreference returns the grade override if present, else the account default.
+	// Returns sql.ErrNoRows when neither is stored.
+	GetCostBasisPreference(ctx context.Context, userID string, spiceGradeID string) (*CostBasisPreference, error)
 
 	// Daily Price (read from control service's shared table)
 	// Returns ErrNoPriceAvailable when no price is published for that date yet.
-	GetDailyPrice(ctx context.Context, gradeID string, date time.Time) (float64, error)
+	GetDailyPrice(ctx context.Context, gradeID string, date time.Time, basis string) (decimal.Decimal, error)
+	// GetLatestDailyPrice returns the newest price dated within [from, to] with its currency and
+	// date; a zero from leaves the window open. Returns ErrNoPriceAvailable when there is none.
+	GetLatestDailyPrice(ctx context.Context, gradeID string, from, to time.Time) (decimal.Decimal, string, time.Time, error)
+
+	// Accounts and FX rates (read from control service's shared tables)
+	// GetAccountCurrencies returns an account's trading and reporting currencies.
+	GetAccountCurrencies(ctx context.Context, userID string) (string, string, error)
+	// GetFxRate returns how many units of to one unit of from buys on a date, from the newest
+	// rate of the pair (or its inverse) effective by then. Returns ErrNoFxRate when there is none.
+	GetFxRate(ctx context.Context, from, to string, on time.Time) (decimal.Decimal, error)
+	// GetGradeUnit returns the unit a grade trades in from the control catalog.
+	GetGradeUnit(ctx context.Context, spiceGradeID string) (GradeUnit, error)
 
 	// BeginTx starts a DB transaction and returns a context carrying it.
 	// The service layer calls this to wrap multi-step FIFO operations atomically.
 	BeginTx(ctx context.Context) (context.Context, *sql.Tx, error)
-	GetMarketMetrics(ctx context.Context) (uint32, float64, []struct {
+	GetMarketMetrics(ctx context.Context) (uint32, decimal.Decimal, []struct {
 		ProductName string
 		GradeName   string
-		Volume      float64
+		Volume      decimal.Decimal
 	}, error)
+	// GetTradedValueByCurrency sums quantity × price of active BUY and SELL trades per currency.
+	GetTradedValueByCurrency(ctx context.Context) (map[string]decimal.Decimal, error)
 	ListAllTransactions(ctx context.Context, skip, take uint, spiceGradeID string, spiceGradeIDs []string, sort, dateFrom, dateTo string) ([]*Transaction, error)
 
 	// Merchant dashboard (all queries scoped by userID from JWT)
@@ -118,11 +235,11 @@ func (r *MysqlRepository) ListAllTransactions(ctx context.Context, skip, take ui
 	case "ASC", "OLDEST", "OLDEST_FIRST":
 		orderBy = "ORDER BY trade_date ASC, id ASC"
 	}
-	query := fmt.Sprintf(`SELECT id, user_id, spice_grade_id, type, quantity, price, trade_date, created_at
+	query := fmt.Sprintf(`SELECT %s
 	          FROM transactions
 	          WHERE %s
 	          %s
-	          LIMIT ? OFFSET ?`, where, orderBy)
+	          LIMIT ? OFFSET ?`, transactionColumns, where, orderBy)
 	args = append(args, take, skip)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -140,9 +257,8 @@ func (r *MysqlRepository) ListAllTransactions(ctx context.Context, skip, take ui
 
 	var txns []*Transaction
 	for rows.Next() {
-		t := &Transaction{}
-		if err := rows.Scan(&t.ID, &t.UserID, &t.SpiceGradeID, &t.Type,
-			&t.Quantity, &t.Price, &t.TradeDate, &t.CreatedAt); err != nil {
+		t, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
 		txns = append(txns, t)
@@ -150,18 +266,18 @@ func (r *MysqlRepository) ListAllTransactions(ctx context.Context, skip, take ui
 	return txns, nil
 }
 
-func (r *MysqlRepository) GetMarketMetrics(ctx context.Context) (uint32, float64, []struct {
+func (r *MysqlRepository) GetMarketMetrics(ctx context.Context) (uint32, decimal.Decimal, []struct {
 	ProductName string
 	GradeName   string
-	Volume      float64
+	Volume      decimal.Decimal
 }, error) {
 	var totalTx uint32
-	var totalVol float64
+	var totalVol decimal.Decimal
 
 	// Total transactions and volume
-	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*), COALESCE(SUM(quantity), 0) FROM transactions").Scan(&totalTx, &totalVol)
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*), COALESCE(SUM(quantity), 0) FROM transactions WHERE type IN ('BUY','SELL') AND status = 'ACTIVE'").Scan(&totalTx, &totalVol)
 	if err != nil {
-		return 0, 0, nil, err
+		return 0, decimal.Zero, nil, err
 	}
 
 	// Top products by volume with both Product Name and Grade Name
@@ -169,6 +285,7 @@ func (r *MysqlRepository) GetMarketMetrics(ctx context.Context) (uint32, float64
 	          FROM transactions t
 	          JOIN grade g ON t.spice_grade_id = g.id
 	          JOIN products p ON g.product_id = p.id
+	          WHERE t.type IN ('BUY','SELL') AND t.status = 'ACTIVE'
 	          GROUP BY p.name, g.name
 	          ORDER BY vol DESC
 	          LIMIT 5`
@@ -182,24 +299,59 @@ func (r *MysqlRepository) GetMarketMetrics(ctx context.Context) (uint32, float64
 	var tops []struct {
 		ProductName string
 		GradeName   string
-		Volume      float64
+		Volume      decimal.Decimal
 	}
 	for rows.Next() {
 		var pName, gName string
-		var vol float64
+		var vol decimal.Decimal
 		if err := rows.Scan(&pName, &gName, &vol); err != nil {
 			return totalTx, totalVol, nil, err
 		}
 		tops = append(tops, struct {
 			ProductName string
 			GradeName   string
-			Volume      float64
+			Volume      decimal.Decimal
 		}{pName, gName, vol})
 	}
 
 	return totalTx, totalVol, tops, nil
 }
 
+func (r *MysqlRepository) GetTradedValueByCurrency(ctx context.Context) (map[string]decimal.Decimal, error) {
+	start := time.Now()
+	query := `SELECT currency, COALESCE(SUM(quantity * price), 0)
+	          FROM transactions
+	          WHERE type IN ('BUY','SELL') AND status = 'ACTIVE'
+	          GROUP BY currency`
+
+	rows, err := r.db.QueryContext(ctx, query)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetTradedValueByCurrency")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string]decimal.Decimal)
+	for rows.Next() {
+		var currency string
+		var value decimal.Decimal
+		if err := rows.Scan(&currency, &value); err != nil {
+			return nil, err
+		}
+		values[currency] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
 // execer is satisfied by both *sql.DB and *sql.Tx.
 type execer interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
@@ -224,14 +376,45 @@ func (r *MysqlRepository) BeginTx(ctx context.Context) (context.Context, *sql.Tx
 	return context.WithValue(ctx, txKey{}, tx), tx, nil
 }
 
-// InsertTransaction inserts an immutable BUY or SELL record and returns its new ID.
+// transactionColumns is the SELECT list read by scanTransaction.
+const transactionColumns = `id, user_id, spice_grade_id, type, quantity, price, currency, fees,
+	          COALESCE(cost_basis_method, ''), status,
+	          COALESCE(reverses_transaction_id, ''), COALESCE(amends_transaction_id, ''), COALESCE(note, ''),
+	          COALESCE(idempotency_key, ''), trade_date, created_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTransaction(row rowScanner) (*Transaction, error) {
+	t := &Transaction{}
+	if err := row.Scan(&t.ID, &t.UserID, &t.SpiceGradeID, &t.Type, &t.Quantity, &t.Price, &t.Currency, &t.FeeTotal,
+		&t.CostBasisMethod, &t.Status, &t.ReversesTransactionID, &t.AmendsTransactionID, &t.Note,
+		&t.IdempotencyKey, &t.TradeDate, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// InsertTransaction inserts an immutable BUY, SELL or REVERSAL record with its fee lines and
+// returns its new ID.
+// Returns ErrDuplicateIdempotencyKey if the user already booked a trade with the same key.
 func (r *MysqlRepository) InsertTransaction(ctx context.Context, t *Transaction) (string, error) {
 	start := time.Now()
-	query := `INSERT INTO transactions (id, user_id, spice_grade_id, type, quantity, price, trade_date)
-	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+	if t.Status == "" {
+		t.Status = TransactionActive
+	}
+	if t.Currency == "" {
+		t.Currency = util.DefaultCurrency
+	}
+	query := `INSERT INTO transactions (id, user_id, spice_grade_id, type, quantity, price, currency, fees, cost_basis_method,
+	            status, reverses_transaction_id, amends_transaction_id, note, idempotency_key, trade_date)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?)`
 
 	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
-		t.ID, t.UserID, t.SpiceGradeID, t.Type, t.Quantity, t.Price,
+		t.ID, t.UserID, t.SpiceGradeID, t.Type, t.Quantity, t.Price, t.Currency, t.FeeTotal, t.CostBasisMethod,
+		t.Status, t.ReversesTransactionID, t.AmendsTransactionID, t.Note, t.IdempotencyKey,
 		t.TradeDate.Format("2006-01-02"),
 	)
 
@@ -241,22 +424,117 @@ func (r *MysqlRepository) InsertTransaction(ctx context.Context, t *Transaction)
 		Bool("success", err == nil).
 		Msg("InsertTransaction")
 
+	var mysqlErr *mysql.MySQLError
+	if t.IdempotencyKey != "" && errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+		return "", ErrDuplicateIdempotencyKey
+	}
 	if err != nil {
 		return "", err
 	}
+	for _, fee := range t.Fees {
+		fee.TransactionID = t.ID
+		if err := r.insertTransactionFee(ctx, fee); err != nil {
+			return "", err
+		}
+	}
 	return t.ID, nil
 }
 
+func (r *MysqlRepository) insertTransactionFee(ctx context.Context, fee *TransactionFee) error {
+	start := time.Now()
+	query := `INSERT INTO transaction_fees (id, transaction_id, fee_schedule_id, code, kind, basis, rate, amount, currency)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
+		fee.ID, fee.TransactionID, fee.FeeScheduleID, fee.Code, fee.Kind, fee.Basis, fee.Rate, fee.Amount, fee.Currency,
+	)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("InsertTransactionFee")
+
+	return err
+}
+
+// ListTransactionFees returns the fee lines of one trade, largest first.
+func (r *MysqlRepository) ListTransactionFees(ctx context.Context, transactionID string) ([]*TransactionFee, error) {
+	start := time.Now()
+	query := `SELECT id, transaction_id, fee_schedule_id, code, kind, basis, rate, amount, currency
+	          FROM transaction_fees
+	          WHERE transaction_id = ?
+	          ORDER BY amount DESC, code`
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, transactionID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListTransactionFees")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fees := []*TransactionFee{}
+	for rows.Next() {
+		fee := &TransactionFee{}
+		if err := rows.Scan(&fee.ID, &fee.TransactionID, &fee.FeeScheduleID, &fee.Code, &fee.Kind,
+			&fee.Basis, &fee.Rate, &fee.Amount, &fee.Currency); err != nil {
+			return nil, err
+		}
+		fees = append(fees, fee)
+	}
+	return fees, rows.Err()
+}
+
+// ListFeeSchedules reads the control fee_schedules set on a grade or on its product's category.
+func (r *MysqlRepository) ListFeeSchedules(ctx context.Context, spiceGradeID string, side string, on time.Time) ([]*FeeSchedule, error) {
+	start := time.Now()
+	query := `SELECT fs.id, fs.grade_id, fs.code, fs.kind, fs.side, fs.basis, fs.rate, fs.currency, fs.effective_date
+	          FROM fee_schedules fs
+	          LEFT JOIN grade g ON g.id = ?
+	          LEFT JOIN products p ON p.id = g.product_id
+	          WHERE (fs.grade_id = ? OR (fs.grade_id = '' AND fs.category = p.category))
+	            AND fs.side IN (?, 'BOTH')
+	            AND fs.effective_date <= ?
+	          ORDER BY fs.effective_date DESC, fs.id`
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, spiceGradeID, spiceGradeID, side, on.Format("2006-01-02"))
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListFeeSchedules")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []*FeeSchedule{}
+	for rows.Next() {
+		fs := &FeeSchedule{}
+		if err := rows.Scan(&fs.ID, &fs.GradeID, &fs.Code, &fs.Kind, &fs.Side, &fs.Basis, &fs.Rate,
+			&fs.Currency, &fs.EffectiveDate); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, fs)
+	}
+	return schedules, rows.Err()
+}
+
 // GetTransactionByID fetches a single transaction by its primary key.
 func (r *MysqlRepository) GetTransactionByID(ctx context.Context, id string) (*Transaction, error) {
 	start := time.Now()
-	query := `SELECT id, user_id, spice_grade_id, type, quantity, price, trade_date, created_at
+	query := `SELECT ` + transactionColumns + `
 	          FROM transactions WHERE id = ?`
 
-	row := r.dbFromContext(ctx).QueryRowContext(ctx, query, id)
-	t := &Transaction{}
-	err := row.Scan(&t.ID, &t.UserID, &t.SpiceGradeID, &t.Type,
-		&t.Quantity, &t.Price, &t.TradeDate, &t.CreatedAt)
+	t, err := scanTransaction(r.dbFromContext(ctx).QueryRowContext(ctx, query, id))
 
 	r.logger.Database().Debug().
 		Str("query", query).
@@ -270,6 +548,112 @@ func (r *MysqlRepository) GetTransactionByID(ctx context.Context, id string) (*T
 	return t, nil
 }
 
+// GetTransactionByIdempotencyKey fetches the trade a user booked under the given key.
+// Returns sql.ErrNoRows if the key has not been used.
+func (r *MysqlRepository) GetTransactionByIdempotencyKey(ctx context.Context, userID, key string) (*Transaction, error) {
+	start := time.Now()
+	query := `SELECT ` + transactionColumns + `
+	          FROM transactions WHERE user_id = ? AND idempotency_key = ?`
+
+	t, err := scanTransaction(r.dbFromContext(ctx).QueryRowContext(ctx, query, userID, key))
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetTransactionByIdempotencyKey")
+
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// LockTransaction fetches a transaction and locks its row for the rest of the DB transaction.
+func (r *MysqlRepository) LockTransaction(ctx context.Context, id string) (*Transaction, error) {
+	start := time.Now()
+	query := `SELECT ` + transactionColumns + `
+	          FROM transactions WHERE id = ?
+	          FOR UPDATE`
+
+	t, err := scanTransaction(r.dbFromContext(ctx).QueryRowContext(ctx, query, id))
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("LockTransaction")
+
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ListActiveSellsSince returns ACTIVE sells for a user + grade matched with method,
+// created at or after since, and locks them.
+func (r *MysqlRepository) ListActiveSellsSince(ctx context.Context, userID string, spiceGradeID string, method string, since time.Time) ([]*Transaction, error) {
+	start := time.Now()
+	query := `SELECT ` + transactionColumns + `
+	          FROM transactions
+	          WHERE user_id = ? AND spice_grade_id = ? AND type = 'SELL' AND status = 'ACTIVE'
+	            AND cost_basis_method = ? AND created_at >= ?
+	          ORDER BY created_at ASC, id ASC
+	          FOR UPDATE`
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, userID, spiceGradeID, method, since)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListActiveSellsSince")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txns []*Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		txns = append(txns, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return txns, nil
+}
+
+// SetTransactionStatus updates the status flag of an ACTIVE transaction.
+func (r *MysqlRepository) SetTransactionStatus(ctx context.Context, id string, status string) error {
+	start := time.Now()
+	query := `UPDATE transactions SET status = ? WHERE id = ? AND status = 'ACTIVE'`
+
+	res, err := r.dbFromContext(ctx).ExecContext(ctx, query, status, id)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("SetTransactionStatus")
+
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrTransactionNotActive
+	}
+	return nil
+}
+
 // ListGradeTransactionsByUser returns paginated transactions for a user + grade.
 func (r *MysqlRepository) ListGradeTransactionsByUser(ctx context.Context, userID, spiceGradeID string, skip, take uint, sort, dateFrom, dateTo string) ([]*Transaction, error) {
 	start := time.Now()
@@ -301,11 +685,11 @@ func (r *MysqlRepository) ListGradeTransactionsByUser(ctx context.Context, userI
 	case "ASC", "OLDEST", "OLDEST_FIRST":
 		orderBy = "ORDER BY trade_date ASC, id ASC"
 	}
-	query := fmt.Sprintf(`SELECT id, user_id, spice_grade_id, type, quantity, price, trade_date, created_at
+	query := fmt.Sprintf(`SELECT %s
 	          FROM transactions
 	          WHERE %s
 	          %s
-	          LIMIT ? OFFSET ?`, where, orderBy)
+	          LIMIT ? OFFSET ?`, transactionColumns, where, orderBy)
 	args = append(args, take, skip)
 
 	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
@@ -323,9 +707,8 @@ func (r *MysqlRepository) ListGradeTransactionsByUser(ctx context.Context, userI
 
 	var txns []*Transaction
 	for rows.Next() {
-		t := &Transaction{}
-		if err := rows.Scan(&t.ID, &t.UserID, &t.SpiceGradeID, &t.Type,
-			&t.Quantity, &t.Price, &t.TradeDate, &t.CreatedAt); err != nil {
+		t, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
 		txns = append(txns, t)
@@ -375,11 +758,11 @@ func (r *MysqlRepository) ListTransactionsByUser(ctx context.Context, userID str
 	case "ASC", "OLDEST", "OLDEST_FIRST":
 		orderBy = "ORDER BY trade_date ASC, id ASC"
 	}
-	query := fmt.Sprintf(`SELECT id, user_id, spice_grade_id, type, quantity, price, trade_date, created_at
+	query := fmt.Sprintf(`SELECT %s
 	          FROM transactions
 	          WHERE %s
 	          %s
-	          LIMIT ? OFFSET ?`, where, orderBy)
+	          LIMIT ? OFFSET ?`, transactionColumns, where, orderBy)
 	args = append(args, take, skip)
 
 	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
@@ -397,9 +780,8 @@ func (r *MysqlRepository) ListTransactionsByUser(ctx context.Context, userID str
 
 	var txns []*Transaction
 	for rows.Next() {
-		t := &Transaction{}
-		if err := rows.Scan(&t.ID, &t.UserID, &t.SpiceGradeID, &t.Type,
-			&t.Quantity, &t.Price, &t.TradeDate, &t.CreatedAt); err != nil {
+		t, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
 		txns = append(txns, t)
@@ -434,13 +816,17 @@ func (r *MysqlRepository) InsertBuyLot(ctx context.Context, lot *BuyLot) (string
 	return lot.ID, nil
 }
 
-func (r *MysqlRepository) GetOpenBuyLots(ctx context.Context, userID string, spiceGradeID string) ([]*BuyLot, error) {
+func (r *MysqlRepository) GetOpenBuyLots(ctx context.Context, userID string, spiceGradeID string, method string) ([]*BuyLot, error) {
 	start := time.Now()
-	query := `SELECT id, transaction_id, user_id, spice_grade_id, original_qty, remaining_qty, price, trade_date, created_at
+	orderBy := "ORDER BY trade_date ASC, id ASC"
+	if method == CostBasisLIFO {
+		orderBy = "ORDER BY trade_date DESC, id DESC"
+	}
+	query := fmt.Sprintf(`SELECT id, transaction_id, user_id, spice_grade_id, original_qty, remaining_qty, price, trade_date, created_at
 	          FROM buy_lots
 	          WHERE user_id = ? AND spice_grade_id = ? AND remaining_qty > 0
-	          ORDER BY trade_date ASC, id ASC
-	          FOR UPDATE`
+	          %s
+	          FOR UPDATE`, orderBy)
 
 	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, userID, spiceGradeID)
 
@@ -472,7 +858,7 @@ func (r *MysqlRepository) GetOpenBuyLots(ctx context.Context, userID string, spi
 
 // DeductBuyLotQty subtracts deductQty from a lot's remaining_qty.
 // The WHERE remaining_qty >= ? guard is the last-line defence against oversell.
-func (r *MysqlRepository) DeductBuyLotQty(ctx context.Context, lotID string, deductQty float64) error {
+func (r *MysqlRepository) DeductBuyLotQty(ctx context.Context, lotID string, deductQty decimal.Decimal) error {
 	start := time.Now()
 	query := `UPDATE buy_lots SET remaining_qty = remaining_qty - ? WHERE id = ? AND remaining_qty >= ?`
 
@@ -497,379 +883,2081 @@ func (r *MysqlRepository) DeductBuyLotQty(ctx context.Context, lotID string, ded
 	return nil
 }
 
-// InsertSellAllocation records one FIFO pairing between a SELL transaction and a BuyLot.
-func (r *MysqlRepository) InsertSellAllocation(ctx context.Context, alloc *SellAllocation) error {
+// RestoreBuyLotQty adds qty back to a lot's remaining_qty.
+// The guard keeps remaining_qty from ever exceeding original_qty.
+func (r *MysqlRepository) RestoreBuyLotQty(ctx context.Context, lotID string, qty decimal.Decimal) error {
 	start := time.Now()
-	query := `INSERT INTO sell_allocations (id, sell_transaction_id, buy_lot_id, quantity, buy_price, sell_price, realized_pnl)
-	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+	query := `UPDATE buy_lots SET remaining_qty = remaining_qty + ?
+	          WHERE id = ? AND remaining_qty + ? <= original_qty AND reversed_by_transaction_id IS NULL`
 
-	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
-		alloc.ID, alloc.SellTransactionID, alloc.BuyLotID,
-		alloc.Quantity, alloc.BuyPrice, alloc.SellPrice, alloc.RealizedPnL,
-	)
+	res, err := r.dbFromContext(ctx).ExecContext(ctx, query, qty, lotID, qty)
 
 	r.logger.Database().Debug().
 		Str("query", query).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("InsertSellAllocation")
+		Msg("RestoreBuyLotQty")
 
-	return err
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLotRestoreMismatch
+	}
+	return nil
 }
 
-// UpsertPosition inserts or updates the aggregate position for a user + grade.
-// total_qty, total_cost, and realized_pnl are updated relatively.
-func (r *MysqlRepository) UpsertPosition(ctx context.Context, pos *Position) error {
+// LockBuyLotByTransaction returns the lot a BUY transaction created and locks it.
+func (r *MysqlRepository) LockBuyLotByTransaction(ctx context.Context, transactionID string) (*BuyLot, error) {
 	start := time.Now()
-	var err error
-	var query string
+	query := `SELECT id, transaction_id, user_id, spice_grade_id, original_qty, remaining_qty, price, trade_date, created_at
+	          FROM buy_lots WHERE transaction_id = ?
+	          FOR UPDATE`
 
-	if pos.TotalQty < 0 {
-		// For SELL (reduction), use a pure UPDATE to avoid violating CHECK constraints on the INSERT attempt.
-		query = `UPDATE positions 
-		          SET total_qty = total_qty + ?, 
-		              total_cost = total_cost + ?, 
-		              realized_pnl = realized_pnl + ?
-		          WHERE user_id = ? AND spice_grade_id = ?`
-		_, err = r.dbFromContext(ctx).ExecContext(ctx, query,
-			pos.TotalQty, pos.TotalCost, pos.RealizedPnL,
-			pos.UserID, pos.SpiceGradeID,
-		)
-	} else {
-		// For BUY (increase), use INSERT ... ON DUPLICATE KEY UPDATE.
-		query = `INSERT INTO positions (user_id, spice_grade_id, total_qty, total_cost, realized_pnl)
-		          VALUES (?, ?, ?, ?, ?)
-		          ON DUPLICATE KEY UPDATE
-		            total_qty    = total_qty + VALUES(total_qty),
-		            total_cost   = total_cost + VALUES(total_cost),
-		            realized_pnl = realized_pnl + VALUES(realized_pnl)`
-		_, err = r.dbFromContext(ctx).ExecContext(ctx, query,
-			pos.UserID, pos.SpiceGradeID, pos.TotalQty, pos.TotalCost, pos.RealizedPnL,
-		)
-	}
+	row := r.dbFromContext(ctx).QueryRowContext(ctx, query, transactionID)
+	l := &BuyLot{}
+	err := row.Scan(&l.ID, &l.TransactionID, &l.UserID, &l.SpiceGradeID,
+		&l.OriginalQty, &l.RemainingQty, &l.Price, &l.TradeDate, &l.CreatedAt)
 
 	r.logger.Database().Debug().
 		Str("query", query).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("UpsertPosition")
+		Msg("LockBuyLotByTransaction")
 
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
 }
 
-// GetPosition returns the current position for a user + grade.
-// Returns sql.ErrNoRows if the user has never traded this grade.
-func (r *MysqlRepository) GetGradePosition(ctx context.Context, userID string, spiceGradeID string) (*Position, error) {
+// CloseBuyLot zeroes a lot whose BUY is being cancelled. Only an untouched lot can be closed.
+func (r *MysqlRepository) CloseBuyLot(ctx context.Context, lotID string, reversalID string) error {
 	start := time.Now()
-	query := `SELECT user_id, spice_grade_id, total_qty, total_cost, realized_pnl, updated_at
-	          FROM positions WHERE user_id = ? AND spice_grade_id = ?`
+	query := `UPDATE buy_lots SET remaining_qty = 0, reversed_by_transaction_id = ?
+	          WHERE id = ? AND remaining_qty = original_qty AND reversed_by_transaction_id IS NULL`
 
-	row := r.dbFromContext(ctx).QueryRowContext(ctx, query, userID, spiceGradeID)
-	pos := &Position{}
-	err := row.Scan(&pos.UserID, &pos.SpiceGradeID, &pos.TotalQty, &pos.TotalCost, &pos.RealizedPnL, &pos.UpdatedAt)
+	res, err := r.dbFromContext(ctx).ExecContext(ctx, query, reversalID, lotID)
 
 	r.logger.Database().Debug().
 		Str("query", query).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("GetPosition")
+		Msg("CloseBuyLot")
 
 	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLotConsumed
+	}
+	return nil
+}
+
+// InsertSellAllocation records one FIFO pairing between a SELL transaction and a BuyLot.
+func (r *MysqlRepository) InsertSellAllocation(ctx context.Context, alloc *SellAllocation) error {
+	start := time.Now()
+	query := `INSERT INTO sell_allocations (id, sell_transaction_id, buy_lot_id, quantity, buy_price, sell_price, realized_pnl, cost_basis_method, close_adjustment)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
+		alloc.ID, alloc.SellTransactionID, alloc.BuyLotID,
+		alloc.Quantity, alloc.BuyPrice, alloc.SellPrice, alloc.RealizedPnL, alloc.CostBasisMethod, alloc.CloseAdjustment,
+	)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("InsertSellAllocation")
+
+	return err
+}
+
+const allocationColumns = `id, sell_transaction_id, buy_lot_id, quantity, buy_price, sell_price, realized_pnl,
+	          cost_basis_method, close_adjustment, COALESCE(reversed_by_transaction_id, ''), created_at`
+
+func scanSellAllocation(row rowScanner) (*SellAllocation, error) {
+	a := &SellAllocation{}
+	if err := row.Scan(&a.ID, &a.SellTransactionID, &a.BuyLotID, &a.Quantity, &a.BuyPrice, &a.SellPrice,
+		&a.RealizedPnL, &a.CostBasisMethod, &a.CloseAdjustment, &a.ReversedByTransactionID, &a.CreatedAt); err != nil {
 		return nil, err
 	}
-	return pos, nil
+	return a, nil
 }
 
-// GetPositionsByUser returns all positions for a user across all grades.
-// Includes positions with zero quantity if they have realized P&L.
-func (r *MysqlRepository) GetPositionsByUser(ctx context.Context, userID string) ([]*Position, error) {
+// ListActiveAllocationsBySell returns the standing allocations of one SELL, locked.
+func (r *MysqlRepository) ListActiveAllocationsBySell(ctx context.Context, sellTransactionID string) ([]*SellAllocation, error) {
+	return r.listActiveAllocations(ctx, "sell_transaction_id", sellTransactionID, "ListActiveAllocationsBySell")
+}
+
+// ListActiveAllocationsByLot returns the standing allocations drawn from one lot, locked.
+func (r *MysqlRepository) ListActiveAllocationsByLot(ctx context.Context, lotID string) ([]*SellAllocation, error) {
+	return r.listActiveAllocations(ctx, "buy_lot_id", lotID, "ListActiveAllocationsByLot")
+}
+
+func (r *MysqlRepository) listActiveAllocations(ctx context.Context, column, id, name string) ([]*SellAllocation, error) {
 	start := time.Now()
-	query := `SELECT user_id, spice_grade_id, total_qty, total_cost, realized_pnl, updated_at
-	          FROM positions WHERE user_id = ? AND (total_qty > 0 OR realized_pnl != 0)`
+	query := fmt.Sprintf(`SELECT %s
+	          FROM sell_allocations
+	          WHERE %s = ? AND reversed_by_transaction_id IS NULL
+	          ORDER BY created_at ASC, id ASC
+	          FOR UPDATE`, allocationColumns, column)
 
-	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, userID)
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, id)
 
 	r.logger.Database().Debug().
 		Str("query", query).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("GetPositionsByUser")
+		Msg(name)
 
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var positions []*Position
+	var allocs []*SellAllocation
 	for rows.Next() {
-		pos := &Position{}
-		if err := rows.Scan(&pos.UserID, &pos.SpiceGradeID, &pos.TotalQty, &pos.TotalCost, &pos.RealizedPnL, &pos.UpdatedAt); err != nil {
+		a, err := scanSellAllocation(rows)
+		if err != nil {
 			return nil, err
 		}
-		positions = append(positions, pos)
+		allocs = append(allocs, a)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return positions, nil
+	return allocs, nil
 }
 
-// GetDailyPrice returns the canonical market price for a grade on a given date.
-// daily_price enforces UNIQUE(grade_id, date) so at most one row is returned.
-// Returns ErrNoPriceAvailable if no price entry exists for that date yet.
-func (r *MysqlRepository) GetDailyPrice(ctx context.Context, gradeID string, date time.Time) (float64, error) {
+// ReverseSellAllocation links an allocation to the REVERSAL that undid it.
+func (r *MysqlRepository) ReverseSellAllocation(ctx context.Context, allocID string, reversalID string) error {
+	start := time.Now()
+	query := `UPDATE sell_allocations SET reversed_by_transaction_id = ?
+	          WHERE id = ? AND reversed_by_transaction_id IS NULL`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query, reversalID, allocID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ReverseSellAllocation")
+
+	return err
+}
+
+// UpsertPosition inserts or updates the aggregate position for a user + grade.
+// total_qty, total_cost, and realized_pnl are updated relatively; a short sale can
+// open a position with negative quantity and cost. A non-empty currency is taken only
+// while the position is flat, so an open position keeps the currency it was opened in.
+func (r *MysqlRepository) UpsertPosition(ctx context.Context, pos *Position) error {
+	start := time.Now()
+	currency := pos.Currency
+	if currency == "" {
+		currency = util.DefaultCurrency
+	}
+	query := `INSERT INTO positions (user_id, spice_grade_id, currency, total_qty, total_cost, realized_pnl)
+	          VALUES (?, ?, ?, ?, ?, ?)
+	          ON DUPLICATE KEY UPDATE
+	            currency     = IF(? != '' AND total_qty = 0, VALUES(currency), currency),
+	            total_qty    = total_qty + VALUES(total_qty),
+	            total_cost   = total_cost + VALUES(total_cost),
+	            realized_pnl = realized_pnl + VALUES(realized_pnl)`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
+		pos.UserID, pos.SpiceGradeID, currency, pos.TotalQty, pos.TotalCost, pos.RealizedPnL, pos.Currency,
+	)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("UpsertPosition")
+
+	return err
+}
+
+// --- Short Lots ---
+
+const shortLotColumns = `id, transaction_id, user_id, spice_grade_id, original_qty, remaining_qty,
+	          price, trade_date, created_at`
+
+func scanShortLot(row rowScanner) (*ShortLot, error) {
+	l := &ShortLot{}
+	if err := row.Scan(&l.ID, &l.TransactionID, &l.UserID, &l.SpiceGradeID,
+		&l.OriginalQty, &l.RemainingQty, &l.Price, &l.TradeDate, &l.CreatedAt); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// InsertShortLot records the uncovered part of a SELL.
+func (r *MysqlRepository) InsertShortLot(ctx context.Context, lot *ShortLot) error {
+	start := time.Now()
+	query := `INSERT INTO short_lots (id, transaction_id, user_id, spice_grade_id, original_qty, remaining_qty, price, trade_date)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
+		lot.ID, lot.TransactionID, lot.UserID, lot.SpiceGradeID,
+		lot.OriginalQty, lot.RemainingQty, lot.Price,
+		lot.TradeDate.Format("2006-01-02"),
+	)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("InsertShortLot")
+
+	return err
+}
+
+func (r *MysqlRepository) GetOpenShortLots(ctx context.Context, userID string, spiceGradeID string) ([]*ShortLot, error) {
+	return r.listShortLots(ctx, "user_id = ? AND spice_grade_id = ? AND remaining_qty > 0",
+		"ORDER BY trade_date ASC, id ASC", "GetOpenShortLots", userID, spiceGradeID)
+}
+
+// ListActiveShortLotsBySell returns the short lots a SELL opened that no REVERSAL has closed, locked.
+func (r *MysqlRepository) ListActiveShortLotsBySell(ctx context.Context, sellTransactionID string) ([]*ShortLot, error) {
+	return r.listShortLots(ctx, "transaction_id = ? AND reversed_by_transaction_id IS NULL",
+		"ORDER BY id ASC", "ListActiveShortLotsBySell", sellTransactionID)
+}
+
+func (r *MysqlRepository) listShortLots(ctx context.Context, where, orderBy, name string, args ...any) ([]*ShortLot, error) {
+	start := time.Now()
+	query := fmt.Sprintf(`SELECT %s
+	          FROM short_lots
+	          WHERE %s
+	          %s
+	          FOR UPDATE`, shortLotColumns, where, orderBy)
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg(name)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*ShortLot
+	for rows.Next() {
+		l, err := scanShortLot(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// DeductShortLotQty covers qty of a short lot. The guard stops a cover from going past the lot.
+func (r *MysqlRepository) DeductShortLotQty(ctx context.Context, lotID string, qty decimal.Decimal) error {
+	start := time.Now()
+	query := `UPDATE short_lots SET remaining_qty = remaining_qty - ? WHERE id = ? AND remaining_qty >= ?`
+
+	res, err := r.dbFromContext(ctx).ExecContext(ctx, query, qty, lotID, qty)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("DeductShortLotQty")
+
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrInsufficientShortQty
+	}
+	return nil
+}
+
+// RestoreShortLotQty reopens qty of a short lot.
+// The guard keeps remaining_qty from ever exceeding original_qty.
+func (r *MysqlRepository) RestoreShortLotQty(ctx context.Context, lotID string, qty decimal.Decimal) error {
+	start := time.Now()
+	query := `UPDATE short_lots SET remaining_qty = remaining_qty + ?
+	          WHERE id = ? AND remaining_qty + ? <= original_qty AND reversed_by_transaction_id IS NULL`
+
+	res, err := r.dbFromContext(ctx).ExecContext(ctx, query, qty, lotID, qty)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("RestoreShortLotQty")
+
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrShortRestoreMismatch
+	}
+	return nil
+}
+
+// CloseShortLot zeroes a short lot whose SELL is being cancelled or re-matched.
+// Only a lot no BUY has covered yet can be closed.
+func (r *MysqlRepository) CloseShortLot(ctx context.Context, lotID string, reversalID string) error {
+	start := time.Now()
+	query := `UPDATE short_lots SET remaining_qty = 0, reversed_by_transaction_id = ?
+	          WHERE id = ? AND remaining_qty = original_qty AND reversed_by_transaction_id IS NULL`
+
+	res, err := r.dbFromContext(ctx).ExecContext(ctx, query, reversalID, lotID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("CloseShortLot")
+
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrShortLotCovered
+	}
+	return nil
+}
+
+// InsertShortCover records one pairing between a covering BUY and a short lot.
+func (r *MysqlRepository) InsertShortCover(ctx context.Context, cover *ShortCover) error {
+	start := time.Now()
+	query := `INSERT INTO short_covers (id, buy_transaction_id, short_lot_id, quantity, short_price, cover_price, realized_pnl)
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
+		cover.ID, cover.BuyTransactionID, cover.ShortLotID,
+		cover.Quantity, cover.ShortPrice, cover.CoverPrice, cover.RealizedPnL,
+	)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("InsertShortCover")
+
+	return err
+}
+
+const shortCoverColumns = `sc.id, sc.buy_transaction_id, sc.short_lot_id, sc.quantity, sc.short_price, sc.cover_price,
+	          sc.realized_pnl, COALESCE(sc.reversed_by_transaction_id, ''), sc.created_at`
+
+func scanShortCover(row rowScanner, extra ...any) (*ShortCover, error) {
+	c := &ShortCover{}
+	dest := append([]any{&c.ID, &c.BuyTransactionID, &c.ShortLotID, &c.Quantity, &c.ShortPrice, &c.CoverPrice,
+		&c.RealizedPnL, &c.ReversedByTransactionID, &c.CreatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListActiveCoversByBuy returns the standing covers of one BUY, locked.
+func (r *MysqlRepository) ListActiveCoversByBuy(ctx context.Context, buyTransactionID string) ([]*ShortCover, error) {
+	start := time.Now()
+	query := `SELECT ` + shortCoverColumns + `
+	          FROM short_covers sc
+	          WHERE sc.buy_transaction_id = ? AND sc.reversed_by_transaction_id IS NULL
+	          ORDER BY sc.created_at ASC, sc.id ASC
+	          FOR UPDATE`
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, buyTransactionID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListActiveCoversByBuy")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var covers []*ShortCover
+	for rows.Next() {
+		c, err := scanShortCover(rows)
+		if err != nil {
+			return nil, err
+		}
+		covers = append(covers, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return covers, nil
+}
+
+// ReverseShortCover links a cover to the REVERSAL that undid it.
+func (r *MysqlRepository) ReverseShortCover(ctx context.Context, coverID string, reversalID string) error {
+	start := time.Now()
+	query := `UPDATE short_covers SET reversed_by_transaction_id = ?
+	          WHERE id = ? AND reversed_by_transaction_id IS NULL`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query, reversalID, coverID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ReverseShortCover")
+
+	return err
+}
+
+// --- Trading Permissions ---
+
+// GetTradingPermissions returns the stored capabilities of an account.
+// Returns sql.ErrNoRows when the account has never been configured.
+func (r *MysqlRepository) GetTradingPermissions(ctx context.Context, userID string) (*TradingPermissions, error) {
+	start := time.Now()
+	query := `SELECT user_id, allow_short_selling, COALESCE(updated_by, ''), updated_at
+	          FROM trading_permissions WHERE user_id = ?`
+
+	row := r.dbFromContext(ctx).QueryRowContext(ctx, query, userID)
+	p := &TradingPermissions{}
+	err := row.Scan(&p.UserID, &p.AllowShortSelling, &p.UpdatedBy, &p.UpdatedAt)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetTradingPermissions")
+
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// UpsertTradingPermissions stores the capabilities of an account.
+func (r *MysqlRepository) UpsertTradingPermissions(ctx context.Context, perms *TradingPermissions) error {
+	start := time.Now()
+	query := `INSERT INTO trading_permissions (user_id, allow_short_selling, updated_by)
+	          VALUES (?, ?, NULLIF(?, ''))
+	          ON DUPLICATE KEY UPDATE
+	            allow_short_selling = VALUES(allow_short_selling),
+	            updated_by          = VALUES(updated_by)`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query, perms.UserID, perms.AllowShortSelling, perms.UpdatedBy)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("UpsertTradingPermissions")
+
+	return err
+}
+
+// --- Order Book ---
+
+// LockOrderBook takes the per-grade book row lock, creating the row on first use.
+// Concurrent placements on the same grade wait here, so two crossing orders never both rest.
+func (r *MysqlRepository) LockOrderBook(ctx context.Context, spiceGradeID string) error {
+	start := time.Now()
+	query := `INSERT INTO order_books (spice_grade_id) VALUES (?)
+	          ON DUPLICATE KEY UPDATE updated_at = CURRENT_TIMESTAMP`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query, spiceGradeID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("LockOrderBook")
+
+	return err
+}
+
+const orderColumns = `id, user_id, spice_grade_id, side, price, currency, quantity, remaining_qty, status,
+	          priority_at, created_at, updated_at`
+
+func scanOrder(row rowScanner) (*Order, error) {
+	o := &Order{}
+	if err := row.Scan(&o.ID, &o.UserID, &o.SpiceGradeID, &o.Side, &o.Price, &o.Currency, &o.Quantity, &o.RemainingQty,
+		&o.Status, &o.PriorityAt, &o.CreatedAt, &o.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// InsertOrder records a new limit order.
+func (r *MysqlRepository) InsertOrder(ctx context.Context, order *Order) error {
+	start := time.Now()
+	query := `INSERT INTO orders (id, user_id, spice_grade_id, side, price, currency, quantity, remaining_qty, status, priority_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
+		order.ID, order.UserID, order.SpiceGradeID, order.Side, order.Price, order.Currency,
+		order.Quantity, order.RemainingQty, order.Status, order.PriorityAt,
+	)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("InsertOrder")
+
+	return err
+}
+
+func (r *MysqlRepository) GetOrder(ctx context.Context, orderID string) (*Order, error) {
+	return r.getOrder(ctx, orderID, "", "GetOrder")
+}
+
+func (r *MysqlRepository) LockOrder(ctx context.Context, orderID string) (*Order, error) {
+	return r.getOrder(ctx, orderID, " FOR UPDATE", "LockOrder")
+}
+
+func (r *MysqlRepository) getOrder(ctx context.Context, orderID, lock, name string) (*Order, error) {
+	start := time.Now()
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = ?` + lock
+
+	o, err := scanOrder(r.dbFromContext(ctx).QueryRowContext(ctx, query, orderID))
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg(name)
+
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// ListCrossingOrders returns the resting orders in currency an incoming order at limitPrice can
+// trade with: bids at or above it, best price first, or asks at or below it, best price first.
+func (r *MysqlRepository) ListCrossingOrders(ctx context.Context, spiceGradeID string, currency string, side string, limitPrice decimal.Decimal, excludeUserID string) ([]*Order, error) {
+	start := time.Now()
+	priceCond, orderBy := "price <= ?", "price ASC"
+	if side == OrderSideBuy {
+		priceCond, orderBy = "price >= ?", "price DESC"
+	}
+	query := `SELECT ` + orderColumns + `
+	          FROM orders
+	          WHERE spice_grade_id = ? AND currency = ? AND side = ? AND status IN ('OPEN', 'PARTIALLY_FILLED')
+	            AND ` + priceCond + ` AND user_id != ?
+	          ORDER BY ` + orderBy + `, priority_at ASC, id ASC
+	          FOR UPDATE`
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, spiceGradeID, currency, side, limitPrice, excludeUserID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListCrossingOrders")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*Order
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// UpdateOrder writes an order's price, quantities, status and priority.
+func (r *MysqlRepository) UpdateOrder(ctx context.Context, order *Order) error {
+	start := time.Now()
+	query := `UPDATE orders
+	          SET price = ?, quantity = ?, remaining_qty = ?, status = ?, priority_at = ?
+	          WHERE id = ?`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
+		order.Price, order.Quantity, order.RemainingQty, order.Status, order.PriorityAt, order.ID,
+	)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("UpdateOrder")
+
+	return err
+}
+
+// SumRestingSellQty totals what a user has already offered on a grade, so a new SELL order
+// cannot promise the same inventory twice.
+func (r *MysqlRepository) SumRestingSellQty(ctx context.Context, userID string, spiceGradeID string, excludeOrderID string) (decimal.Decimal, error) {
+	start := time.Now()
+	query := `SELECT COALESCE(SUM(remaining_qty), 0)
+	          FROM orders
+	          WHERE user_id = ? AND spice_grade_id = ? AND side = 'SELL'
+	            AND status IN ('OPEN', 'PARTIALLY_FILLED') AND id != ?`
+
+	var total decimal.Decimal
+	err := r.dbFromContext(ctx).QueryRowContext(ctx, query, userID, spiceGradeID, excludeOrderID).Scan(&total)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("SumRestingSellQty")
+
+	return total, err
+}
+
+// InsertOrderFill records one match and the two transactions it booked.
+func (r *MysqlRepository) InsertOrderFill(ctx context.Context, fill *OrderFill) error {
+	start := time.Now()
+	query := `INSERT INTO order_fills (id, spice_grade_id, buy_order_id, sell_order_id,
+	                                  buy_transaction_id, sell_transaction_id, quantity, price)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
+		fill.ID, fill.SpiceGradeID, fill.BuyOrderID, fill.SellOrderID,
+		fill.BuyTransactionID, fill.SellTransactionID, fill.Quantity, fill.Price,
+	)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("InsertOrderFill")
+
+	return err
+}
+
+// ListOrderFills returns every fill of an order on either side, oldest first.
+func (r *MysqlRepository) ListOrderFills(ctx context.Context, orderID string) ([]*OrderFill, error) {
+	start := time.Now()
+	query := `SELECT id, spice_grade_id, buy_order_id, sell_order_id, buy_transaction_id, sell_transaction_id,
+	                 quantity, price, created_at
+	          FROM order_fills
+	          WHERE buy_order_id = ? OR sell_order_id = ?
+	          ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, orderID, orderID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListOrderFills")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fills []*OrderFill
+	for rows.Next() {
+		f := &OrderFill{}
+		if err := rows.Scan(&f.ID, &f.SpiceGradeID, &f.BuyOrderID, &f.SellOrderID, &f.BuyTransactionID,
+			&f.SellTransactionID, &f.Quantity, &f.Price, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		fills = append(fills, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return fills, nil
+}
+
+// ListOrders returns orders matching the filter, newest first.
+func (r *MysqlRepository) ListOrders(ctx context.Context, filter OrderFilter) ([]*Order, error) {
+	start := time.Now()
+	take := filter.Take
+	if take == 0 || take > 100 {
+		take = 100
+	}
+
+	where, args := ledgerScope("o", filter.UserID, filter.SpiceGradeID)
+	if filter.Side != "" {
+		where += " AND o.side = ?"
+		args = append(args, filter.Side)
+	}
+	if filter.Status != "" {
+		where += " AND o.status = ?"
+		args = append(args, filter.Status)
+	}
+	query := `SELECT o.id, o.user_id, o.spice_grade_id, o.side, o.price, o.currency, o.quantity, o.remaining_qty, o.status,
+	                 o.priority_at, o.created_at, o.updated_at
+	          FROM orders o
+	          WHERE ` + where + `
+	          ORDER BY o.created_at DESC, o.id DESC
+	          LIMIT ? OFFSET ?`
+	args = append(args, take, filter.Skip)
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListOrders")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*Order
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetOrderBookDepth aggregates the resting orders of a grade in one currency by price level.
+func (r *MysqlRepository) GetOrderBookDepth(ctx context.Context, spiceGradeID string, currency string, depth uint) (*OrderBook, error) {
+	book := &OrderBook{SpiceGradeID: spiceGradeID, Currency: currency}
+	var err error
+	if book.Bids, err = r.listBookLevels(ctx, spiceGradeID, currency, OrderSideBuy, "DESC", depth); err != nil {
+		return nil, err
+	}
+	if book.Asks, err = r.listBookLevels(ctx, spiceGradeID, currency, OrderSideSell, "ASC", depth); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+func (r *MysqlRepository) listBookLevels(ctx context.Context, spiceGradeID, currency, side, direction string, depth uint) ([]OrderBookLevel, error) {
+	start := time.Now()
+	query := `SELECT price, SUM(remaining_qty), COUNT(*)
+	          FROM orders
+	          WHERE spice_grade_id = ? AND currency = ? AND side = ? AND status IN ('OPEN', 'PARTIALLY_FILLED')
+	          GROUP BY price
+	          ORDER BY price ` + direction + `
+	          LIMIT ?`
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, spiceGradeID, currency, side, depth)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetOrderBookDepth")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []OrderBookLevel
+	for rows.Next() {
+		var l OrderBookLevel
+		if err := rows.Scan(&l.Price, &l.Quantity, &l.Orders); err != nil {
+			return nil, err
+		}
+		levels = append(levels, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// GetPosition returns the current position for a user + grade.
+// Returns sql.ErrNoRows if the user has never traded this grade.
+func (r *MysqlRepository) GetGradePosition(ctx context.Context, userID string, spiceGradeID string) (*Position, error) {
+	start := time.Now()
+	query := `SELECT user_id, spice_grade_id, currency, total_qty, total_cost, realized_pnl, updated_at
+	          FROM positions WHERE user_id = ? AND spice_grade_id = ?`
+
+	row := r.dbFromContext(ctx).QueryRowContext(ctx, query, userID, spiceGradeID)
+	pos := &Position{}
+	err := row.Scan(&pos.UserID, &pos.SpiceGradeID, &pos.Currency, &pos.TotalQty, &pos.TotalCost, &pos.RealizedPnL, &pos.UpdatedAt)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetPosition")
+
+	if err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+// GetPositionsByUser returns all positions for a user across all grades.
+// Includes short (negative) positions, and positions with zero quantity if they have realized P&L.
+func (r *MysqlRepository) GetPositionsByUser(ctx context.Context, userID string) ([]*Position, error) {
+	start := time.Now()
+	query := `SELECT user_id, spice_grade_id, currency, total_qty, total_cost, realized_pnl, updated_at
+	          FROM positions WHERE user_id = ? AND (total_qty != 0 OR realized_pnl != 0)`
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, userID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetPositionsByUser")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var positions []*Position
+	for rows.Next() {
+		pos := &Position{}
+		if err := rows.Scan(&pos.UserID, &pos.SpiceGradeID, &pos.Currency, &pos.TotalQty, &pos.TotalCost, &pos.RealizedPnL, &pos.UpdatedAt); err != nil {
+			return nil, err
+		}
+		positions = append(positions, pos)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// LockGradePosition returns the position row for a user + grade and locks it for the
+// rest of the DB transaction. Returns sql.ErrNoRows if the user has never traded this grade.
+func (r *MysqlRepository) LockGradePosition(ctx context.Context, userID string, spiceGradeID string) (*Position, error) {
+	start := time.Now()
+	query := `SELECT user_id, spice_grade_id, currency, total_qty, total_cost, realized_pnl, updated_at
+	          FROM positions WHERE user_id = ? AND spice_grade_id = ?
+	          FOR UPDATE`
+
+	row := r.dbFromContext(ctx).QueryRowContext(ctx, query, userID, spiceGradeID)
+	pos := &Position{}
+	err := row.Scan(&pos.UserID, &pos.SpiceGradeID, &pos.Currency, &pos.TotalQty, &pos.TotalCost, &pos.RealizedPnL, &pos.UpdatedAt)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("LockGradePosition")
+
+	if err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+// UpsertCostBasisPreference stores the default method for an account (empty grade) or one grade.
+func (r *MysqlRepository) UpsertCostBasisPreference(ctx context.Context, pref *CostBasisPreference) error {
+	start := time.Now()
+	query := `INSERT INTO cost_basis_preferences (user_id, spice_grade_id, method)
+	          VALUES (?, ?, ?)
+	          ON DUPLICATE KEY UPDATE method = VALUES(method)`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query, pref.UserID, pref.SpiceGradeID, pref.Method)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("UpsertCostBasisPreference")
+
+	return err
+}
+
+// GetCostBasisPreference returns the most specific stored method: the grade override
+// when one exists, otherwise the account-wide default stored with an empty spice_grade_id.
+func (r *MysqlRepository) GetCostBasisPreference(ctx context.Context, userID string, spiceGradeID string) (*CostBasisPreference, error) {
+	start := time.Now()
+	query := `SELECT user_id, spice_grade_id, method, updated_at
+	          FROM cost_basis_preferences
+	          WHERE user_id = ? AND spice_grade_id IN (?, '')
+	          ORDER BY spice_grade_id DESC
+	          LIMIT 1`
+
+	row := r.dbFromContext(ctx).QueryRowContext(ctx, query, userID, spiceGradeID)
+	pref := &CostBasisPreference{}
+	err := row.Scan(&pref.UserID, &pref.SpiceGradeID, &pref.Method, &pref.UpdatedAt)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetCostBasisPreference")
+
+	if err != nil {
+		return nil, err
+	}
+	return pref, nil
+}
+
+// GetDailyPrice returns a grade's price on a given date from the daily_price rollup, whose
+// price is the day's last tick. PriceBasisClose only answers for days that have ended.
+// Returns ErrNoPriceAvailable if there is no price at that basis yet.
+func (r *MysqlRepository) GetDailyPrice(ctx context.Context, gradeID string, date time.Time, basis string) (decimal.Decimal, error) {
 	start := time.Now()
 	query := `SELECT price FROM daily_price WHERE grade_id = ? AND date = ? LIMIT 1`
+	if basis == PriceBasisClose {
+		query = `SELECT price FROM daily_price WHERE grade_id = ? AND date = ? AND date < CURDATE() LIMIT 1`
+	}
+
+	row := r.dbFromContext(ctx).QueryRowContext(ctx, query, gradeID, date.Format("2006-01-02"))
+	var price decimal.Decimal
+	err := row.Scan(&price)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetDailyPrice")
+
+	if err == sql.ErrNoRows {
+		return decimal.Zero, ErrNoPriceAvailable
+	}
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return price, nil
+}
+
+// GetLatestDailyPrice returns the newest daily_price of a grade dated between from and to.
+// A zero from means no lower bound. Returns ErrNoPriceAvailable if there is none.
+func (r *MysqlRepository) GetLatestDailyPrice(ctx context.Context, gradeID string, from, to time.Time) (decimal.Decimal, string, time.Time, error) {
+	start := time.Now()
+	query := `SELECT price, currency, date FROM daily_price
+	          WHERE grade_id = ? AND date <= ?`
+	args := []any{gradeID, to.Format("2006-01-02")}
+	if !from.IsZero() {
+		query += ` AND date >= ?`
+		args = append(args, from.Format("2006-01-02"))
+	}
+	query += ` ORDER BY date DESC LIMIT 1`
+
+	var price decimal.Decimal
+	var currency string
+	var date time.Time
+	err := r.dbFromContext(ctx).QueryRowContext(ctx, query, args...).Scan(&price, &currency, &date)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetLatestDailyPrice")
+
+	if err == sql.ErrNoRows {
+		return decimal.Zero, "", time.Time{}, ErrNoPriceAvailable
+	}
+	if err != nil {
+		return decimal.Zero, "", time.Time{}, err
+	}
+	return price, currency, date, nil
+}
+
+// GetAccountCurrencies reads an account's trading and reporting currencies. An account
+// the market has no row for trades and reports in the default currency.
+func (r *MysqlRepository) GetAccountCurrencies(ctx context.Context, userID string) (string, string, error) {
+	start := time.Now()
+	query := `SELECT currency, reporting_currency FROM accounts WHERE id = ?`
+
+	var currency, reporting string
+	err := r.dbFromContext(ctx).QueryRowContext(ctx, query, userID).Scan(&currency, &reporting)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil || err == sql.ErrNoRows).
+		Msg("GetAccountCurrencies")
+
+	if err == sql.ErrNoRows {
+		return util.DefaultCurrency, util.DefaultCurrency, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return currency, reporting, nil
+}
+
+// GetGradeUnit returns the unit a grade trades in. A grade missing from the catalog trades in
+// kilograms.
+func (r *MysqlRepository) GetGradeUnit(ctx context.Context, spiceGradeID string) (GradeUnit, error) {
+	start := time.Now()
+	query := `SELECT unit, kg_per_unit FROM grade WHERE id = ?`
+
+	var unit GradeUnit
+	err := r.dbFromContext(ctx).QueryRowContext(ctx, query, spiceGradeID).Scan(&unit.Unit, &unit.KgPerUnit)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil || err == sql.ErrNoRows).
+		Msg("GetGradeUnit")
+
+	if err == sql.ErrNoRows {
+		return GradeUnit{Unit: util.BaseUnit, KgPerUnit: decimal.NewFromInt(1)}, nil
+	}
+	if err != nil {
+		return GradeUnit{}, err
+	}
+	return unit, nil
+}
+
+// GetFxRate returns the rate converting from into to on a date. A stored rate for the inverse
+// pair is inverted; the newest effective rate of either direction wins.
+func (r *MysqlRepository) GetFxRate(ctx context.Context, from, to string, on time.Time) (decimal.Decimal, error) {
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+	start := time.Now()
+	query := `SELECT base_currency, rate FROM fx_rates
+	          WHERE ((base_currency = ? AND quote_currency = ?) OR (base_currency = ? AND quote_currency = ?))
+	            AND effective_date <= ?
+	          ORDER BY effective_date DESC, base_currency = ? DESC
+	          LIMIT 1`
+
+	var base string
+	var rate decimal.Decimal
+	err := r.dbFromContext(ctx).QueryRowContext(ctx, query,
+		from, to, to, from, on.Format("2006-01-02"), from,
+	).Scan(&base, &rate)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetFxRate")
+
+	if err == sql.ErrNoRows {
+		return decimal.Zero, ErrNoFxRate
+	}
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if base != from {
+		return decimal.NewFromInt(1).DivRound(rate, fxRateScale), nil
+	}
+	return rate, nil
+}
+
+// GetEnrichedHoldings returns a merchant's open positions with product and grade names. Prices
+// are left for the service to fill in under its valuation policy.
+func (r *MysqlRepository) GetEnrichedHoldings(ctx context.Context, userID string) ([]EnrichedHoldingRow, error) {
+	start := time.Now()
+	query := `SELECT p.spice_grade_id, pr.name, g.name, p.currency, g.unit, g.kg_per_unit,
+	                 p.total_qty, p.total_cost, p.realized_pnl
+	          FROM positions p
+	          INNER JOIN grade g ON g.id = p.spice_grade_id
+	          INNER JOIN products pr ON pr.id = g.product_id
+	          WHERE p.user_id = ? AND p.total_qty > 0
+	          ORDER BY p.total_qty DESC, pr.name, g.name`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("user_id", userID).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetEnrichedHoldings")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holdings []EnrichedHoldingRow
+	for rows.Next() {
+		var row EnrichedHoldingRow
+		if err := rows.Scan(
+			&row.SpiceGradeID,
+			&row.ProductName,
+			&row.GradeName,
+			&row.Currency,
+			&row.Unit.Unit,
+			&row.Unit.KgPerUnit,
+			&row.TotalQty,
+			&row.TotalCost,
+			&row.RealizedPnL,
+		); err != nil {
+			return nil, err
+		}
+		holdings = append(holdings, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return holdings, nil
+}
+
+// GetDailyRealizedPnLByUser returns realized P&L grouped by sell trade_date + grade.
+func (r *MysqlRepository) GetDailyRealizedPnLByUser(ctx context.Context, userID string, days uint) ([]DailyRealizedPnLRow, error) {
+	start := time.Now()
+	// Realized P&L comes from sells drawing on lots and from buys covering short lots.
+	query := `SELECT t.trade_date AS d,
+	                 COALESCE(SUM(rp.realized_pnl), 0),
+	                 t.spice_grade_id,
+	                 COALESCE(p.name, ''),
+	                 COALESCE(g.name, '')
+	          FROM (
+	                SELECT sell_transaction_id AS transaction_id, realized_pnl
+	                FROM sell_allocations WHERE reversed_by_transaction_id IS NULL
+	                UNION ALL
+	                SELECT buy_transaction_id, realized_pnl
+	                FROM short_covers WHERE reversed_by_transaction_id IS NULL
+	          ) rp
+	          INNER JOIN transactions t ON t.id = rp.transaction_id
+	          LEFT JOIN grade g ON g.id = t.spice_grade_id
+	          LEFT JOIN products p ON p.id = g.product_id
+	          WHERE t.user_id = ?
+	            AND t.trade_date >= DATE_SUB(CURDATE(), INTERVAL ? DAY)
+	          GROUP BY t.trade_date, t.spice_grade_id, p.name, g.name
+	          ORDER BY d ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, days)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("user_id", userID).
+		Int("days", int(days)).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetDailyRealizedPnLByUser")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []DailyRealizedPnLRow
+	for rows.Next() {
+		var row DailyRealizedPnLRow
+		if err := rows.Scan(
+			&row.Date,
+			&row.DailyRealizedPnL,
+			&row.SpiceGradeID,
+			&row.ProductName,
+			&row.GradeName,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetDailyActivityByUser returns buy/sell quantity and counts grouped by trade date + grade.
+func (r *MysqlRepository) GetDailyActivityByUser(ctx context.Context, userID string, days uint) ([]DailyActivityRow, error) {
+	start := time.Now()
+	query := `SELECT t.trade_date, t.type, COALESCE(SUM(t.quantity), 0), COUNT(*),
+	                 t.spice_grade_id, COALESCE(p.name, ''), COALESCE(g.name, '')
+	          FROM transactions t
+	          LEFT JOIN grade g ON g.id = t.spice_grade_id
+	          LEFT JOIN products p ON p.id = g.product_id
+	          WHERE t.user_id = ?
+	            AND t.type IN ('BUY','SELL') AND t.status = 'ACTIVE'
+	            AND t.trade_date >= DATE_SUB(CURDATE(), INTERVAL ? DAY)
+	          GROUP BY t.trade_date, t.type, t.spice_grade_id, p.name, g.name
+	          ORDER BY t.trade_date ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, days)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("user_id", userID).
+		Int("days", int(days)).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetDailyActivityByUser")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []DailyActivityRow
+	for rows.Next() {
+		var row DailyActivityRow
+		if err := rows.Scan(
+			&row.Date,
+			&row.Type,
+			&row.Quantity,
+			&row.Count,
+			&row.SpiceGradeID,
+			&row.ProductName,
+			&row.GradeName,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetPeriodTradeStats aggregates trade count and buy/sell volume for a merchant over a date window.
+func (r *MysqlRepository) GetPeriodTradeStats(ctx context.Context, userID string, days uint) (*PeriodTradeStats, error) {
+	start := time.Now()
+	query := `SELECT COUNT(*),
+	                 COALESCE(SUM(CASE WHEN type = 'BUY' THEN quantity ELSE 0 END), 0),
+	                 COALESCE(SUM(CASE WHEN type = 'SELL' THEN quantity ELSE 0 END), 0)
+	          FROM transactions
+	          WHERE user_id = ?
+	            AND type IN ('BUY','SELL') AND status = 'ACTIVE'
+	            AND trade_date >= DATE_SUB(CURDATE(), INTERVAL ? DAY)`
+
+	stats := &PeriodTradeStats{}
+	err := r.db.QueryRowContext(ctx, query, userID, days).Scan(
+		&stats.TradesInPeriod,
+		&stats.BuyVolumeInPeriod,
+		&stats.SellVolumeInPeriod,
+	)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("user_id", userID).
+		Int("days", int(days)).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetPeriodTradeStats")
+
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetPriceSnapshotsForHoldings lists the grades the merchant holds. Prices are left for the
+// service to fill in under its valuation policy.
+func (r *MysqlRepository) GetPriceSnapshotsForHoldings(ctx context.Context, userID string) ([]PriceSnapshot, error) {
+	start := time.Now()
+	query := `SELECT p.spice_grade_id, pr.name, g.name, p.currency
+	          FROM positions p
+	          INNER JOIN grade g ON g.id = p.spice_grade_id
+	          INNER JOIN products pr ON pr.id = g.product_id
+	          WHERE p.user_id = ? AND p.total_qty > 0
+	          ORDER BY pr.name, g.name`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("user_id", userID).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("GetPriceSnapshotsForHoldings")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []PriceSnapshot
+	for rows.Next() {
+		var snap PriceSnapshot
+		if err := rows.Scan(
+			&snap.SpiceGradeID,
+			&snap.ProductName,
+			&snap.GradeName,
+			&snap.Currency,
+		); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// --- Reconciliation ---
+
+// ledgerScope builds the optional user/grade filter shared by the ledger reads.
+// Empty values match every user or grade.
+func ledgerScope(alias, userID, spiceGradeID string) (string, []any) {
+	where := "1=1"
+	var args []any
+	if userID != "" {
+		where += " AND " + alias + ".user_id = ?"
+		args = append(args, userID)
+	}
+	if spiceGradeID != "" {
+		where += " AND " + alias + ".spice_grade_id = ?"
+		args = append(args, spiceGradeID)
+	}
+	return where, args
+}
+
+// lockInTx returns FOR UPDATE when ctx carries a DB transaction, so a rebuild
+// holds the rows it recomputes while a read-only check takes no locks.
+func lockInTx(ctx context.Context) string {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok && tx != nil {
+		return " FOR UPDATE"
+	}
+	return ""
+}
+
+// ListLedgerLots returns every buy lot in scope together with the status of the BUY that created it.
+func (r *MysqlRepository) ListLedgerLots(ctx context.Context, userID, spiceGradeID string) ([]*LedgerLot, error) {
+	start := time.Now()
+	where, args := ledgerScope("l", userID, spiceGradeID)
+	query := `SELECT l.id, l.transaction_id, l.user_id, l.spice_grade_id, l.original_qty, l.remaining_qty,
+	                 l.price, l.trade_date, l.created_at, t.status, t.currency, t.created_at
+	          FROM buy_lots l
+	          JOIN transactions t ON t.id = l.transaction_id
+	          WHERE ` + where + `
+	          ORDER BY l.user_id, l.spice_grade_id, t.created_at, l.transaction_id` + lockInTx(ctx)
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListLedgerLots")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*LedgerLot
+	for rows.Next() {
+		l := &LedgerLot{}
+		if err := rows.Scan(&l.ID, &l.TransactionID, &l.UserID, &l.SpiceGradeID, &l.OriginalQty, &l.RemainingQty,
+			&l.Price, &l.TradeDate, &l.CreatedAt, &l.BuyStatus, &l.Currency, &l.BookedAt); err != nil {
+			return nil, err
+		}
+		lots = append(lots, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// ListLedgerSells returns the ACTIVE sells in scope in booking order.
+func (r *MysqlRepository) ListLedgerSells(ctx context.Context, userID, spiceGradeID string) ([]*Transaction, error) {
+	start := time.Now()
+	where, args := ledgerScope("transactions", userID, spiceGradeID)
+	query := `SELECT ` + transactionColumns + `
+	          FROM transactions
+	          WHERE ` + where + ` AND type = 'SELL' AND status = 'ACTIVE'
+	          ORDER BY user_id, spice_grade_id, created_at, id` + lockInTx(ctx)
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListLedgerSells")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txns []*Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		txns = append(txns, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return txns, nil
+}
+
+// ListLedgerAllocations returns the unreversed allocations of ACTIVE sells in scope.
+func (r *MysqlRepository) ListLedgerAllocations(ctx context.Context, userID, spiceGradeID string) ([]*SellAllocation, error) {
+	start := time.Now()
+	where, args := ledgerScope("t", userID, spiceGradeID)
+	query := `SELECT sa.id, sa.sell_transaction_id, sa.buy_lot_id, sa.quantity, sa.buy_price, sa.sell_price,
+	                 sa.realized_pnl, sa.cost_basis_method, sa.close_adjustment, COALESCE(sa.reversed_by_transaction_id, ''), sa.created_at
+	          FROM sell_allocations sa
+	          JOIN transactions t ON t.id = sa.sell_transaction_id
+	          WHERE ` + where + ` AND t.status = 'ACTIVE' AND sa.reversed_by_transaction_id IS NULL
+	          ORDER BY sa.sell_transaction_id, sa.id` + lockInTx(ctx)
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListLedgerAllocations")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var allocs []*SellAllocation
+	for rows.Next() {
+		a, err := scanSellAllocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		allocs = append(allocs, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return allocs, nil
+}
+
+// ListLedgerShortLots returns every short lot in scope with the booking time of its SELL.
+func (r *MysqlRepository) ListLedgerShortLots(ctx context.Context, userID, spiceGradeID string) ([]*LedgerShortLot, error) {
+	start := time.Now()
+	where, args := ledgerScope("sl", userID, spiceGradeID)
+	query := `SELECT sl.id, sl.transaction_id, sl.user_id, sl.spice_grade_id, sl.original_qty, sl.remaining_qty,
+	                 sl.price, sl.trade_date, sl.created_at, COALESCE(sl.reversed_by_transaction_id, ''), t.created_at
+	          FROM short_lots sl
+	          JOIN transactions t ON t.id = sl.transaction_id
+	          WHERE ` + where + `
+	          ORDER BY sl.user_id, sl.spice_grade_id, t.created_at, sl.id` + lockInTx(ctx)
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListLedgerShortLots")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*LedgerShortLot
+	for rows.Next() {
+		l := &LedgerShortLot{}
+		if err := rows.Scan(&l.ID, &l.TransactionID, &l.UserID, &l.SpiceGradeID, &l.OriginalQty, &l.RemainingQty,
+			&l.Price, &l.TradeDate, &l.CreatedAt, &l.ReversedByTransactionID, &l.BookedAt); err != nil {
+			return nil, err
+		}
+		lots = append(lots, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// ListLedgerCovers returns the unreversed covers of ACTIVE buys in scope.
+func (r *MysqlRepository) ListLedgerCovers(ctx context.Context, userID, spiceGradeID string) ([]*LedgerCover, error) {
+	start := time.Now()
+	where, args := ledgerScope("t", userID, spiceGradeID)
+	query := `SELECT ` + shortCoverColumns + `, t.user_id, t.spice_grade_id, t.currency, t.created_at
+	          FROM short_covers sc
+	          JOIN transactions t ON t.id = sc.buy_transaction_id
+	          WHERE ` + where + ` AND t.status = 'ACTIVE' AND sc.reversed_by_transaction_id IS NULL
+	          ORDER BY sc.buy_transaction_id, sc.id` + lockInTx(ctx)
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListLedgerCovers")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var covers []*LedgerCover
+	for rows.Next() {
+		lc := &LedgerCover{}
+		c, err := scanShortCover(rows, &lc.UserID, &lc.SpiceGradeID, &lc.Currency, &lc.BookedAt)
+		if err != nil {
+			return nil, err
+		}
+		lc.ShortCover = *c
+		covers = append(covers, lc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return covers, nil
+}
+
+// ListStoredPositions returns the stored aggregate rows in scope.
+func (r *MysqlRepository) ListStoredPositions(ctx context.Context, userID, spiceGradeID string) ([]*Position, error) {
+	start := time.Now()
+	where, args := ledgerScope("positions", userID, spiceGradeID)
+	query := `SELECT user_id, spice_grade_id, currency, total_qty, total_cost, realized_pnl, updated_at
+	          FROM positions
+	          WHERE ` + where + `
+	          ORDER BY user_id, spice_grade_id` + lockInTx(ctx)
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListStoredPositions")
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var positions []*Position
+	for rows.Next() {
+		p := &Position{}
+		if err := rows.Scan(&p.UserID, &p.SpiceGradeID, &p.Currency, &p.TotalQty, &p.TotalCost, &p.RealizedPnL, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		positions = append(positions, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// SetBuyLotRemaining overwrites a lot's remaining quantity; used only by a ledger rebuild.
+func (r *MysqlRepository) SetBuyLotRemaining(ctx context.Context, lotID string, remainingQty decimal.Decimal) error {
+	start := time.Now()
+	query := `UPDATE buy_lots SET remaining_qty = ? WHERE id = ?`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query, remainingQty, lotID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("SetBuyLotRemaining")
+
+	return err
+}
+
+// SetShortLotRemaining overwrites a short lot's remaining quantity; used only by a ledger rebuild.
+func (r *MysqlRepository) SetShortLotRemaining(ctx context.Context, lotID string, remainingQty decimal.Decimal) error {
+	start := time.Now()
+	query := `UPDATE short_lots SET remaining_qty = ? WHERE id = ?`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query, remainingQty, lotID)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("SetShortLotRemaining")
+
+	return err
+}
+
+// ReplacePosition writes absolute position totals, unlike the incremental UpsertPosition;
+// used only by a ledger rebuild.
+func (r *MysqlRepository) ReplacePosition(ctx context.Context, pos *Position) error {
+	start := time.Now()
+	currency := pos.Currency
+	if currency == "" {
+		currency = util.DefaultCurrency
+	}
+	query := `INSERT INTO positions (user_id, spice_grade_id, currency, total_qty, total_cost, realized_pnl)
+	          VALUES (?, ?, ?, ?, ?, ?)
+	          ON DUPLICATE KEY UPDATE
+	            currency     = VALUES(currency),
+	            total_qty    = VALUES(total_qty),
+	            total_cost   = VALUES(total_cost),
+	            realized_pnl = VALUES(realized_pnl)`
+
+	_, err := r.dbFromContext(ctx).ExecContext(ctx, query,
+		pos.UserID, pos.SpiceGradeID, currency, pos.TotalQty, pos.TotalCost, pos.RealizedPnL,
+	)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ReplacePosition")
+
+	return err
+}
+
+// --- Lot Queries ---
+
+const buyLotColumns = `l.id, l.transaction_id, l.user_id, l.spice_grade_id, l.original_qty, l.remaining_qty,
+	          l.price, l.trade_date, l.created_at`
+
+// appendDateRange adds inclusive YYYY-MM-DD bounds on column to a WHERE clause.
+func appendDateRange(where string, args []interface{}, column, dateFrom, dateTo string) (string, []interface{}, error) {
+	if dateFrom != "" {
+		if _, err := time.Parse("2006-01-02", dateFrom); err != nil {
+			return "", nil, fmt.Errorf("invalid date_from %q: use YYYY-MM-DD", dateFrom)
+		}
+		where += " AND " + column + " >= ?"
+		args = append(args, dateFrom)
+	}
+	if dateTo != "" {
+		if _, err := time.Parse("2006-01-02", dateTo); err != nil {
+			return "", nil, fmt.Errorf("invalid date_to %q: use YYYY-MM-DD", dateTo)
+		}
+		where += " AND " + column + " <= ?"
+		args = append(args, dateTo)
+	}
+	return where, args, nil
+}
+
+// ListOpenLots returns lots with remaining_qty > 0, oldest first unless sort is DESC.
+// An empty userID lists every account (admin); dates filter the BUY trade_date.
+func (r *MysqlRepository) ListOpenLots(ctx context.Context, userID, spiceGradeID string, skip, take uint, sort, dateFrom, dateTo string) ([]*BuyLot, error) {
+	start := time.Now()
+	if take == 0 || take > 100 {
+		take = 100
+	}
+
+	where, args := ledgerScope("l", userID, spiceGradeID)
+	where += " AND l.remaining_qty > 0"
+	where, args, err := appendDateRange(where, args, "l.trade_date", dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	orderBy := "ORDER BY l.trade_date ASC, l.id ASC"
+	switch strings.ToUpper(strings.TrimSpace(sort)) {
+	case "DESC", "NEWEST", "NEWEST_FIRST":
+		orderBy = "ORDER BY l.trade_date DESC, l.id DESC"
+	}
+	query := fmt.Sprintf(`SELECT %s
+	          FROM buy_lots l
+	          WHERE %s
+	          %s
+	          LIMIT ? OFFSET ?`, buyLotColumns, where, orderBy)
+	args = append(args, take, skip)
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
+
+	r.logger.Database().Debug().
+		Str("query", query).
+		Str("duration", time.Since(start).String()).
+		Bool("success", err == nil).
+		Msg("ListOpenLots")
 
-	row := r.dbFromContext(ctx).QueryRowContext(ctx, query, gradeID, date.Format("2006-01-02"))
-	var price float64
-	err := row.Scan(&price)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*BuyLot
+	for rows.Next() {
+		l := &BuyLot{}
+		if err := rows.Scan(&l.ID, &l.TransactionID, &l.UserID, &l.SpiceGradeID,
+			&l.OriginalQty, &l.RemainingQty, &l.Price, &l.TradeDate, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		lots = append(lots, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// GetBuyLot reads one lot without locking. Returns sql.ErrNoRows when it does not exist.
+func (r *MysqlRepository) GetBuyLot(ctx context.Context, lotID string) (*BuyLot, error) {
+	start := time.Now()
+	query := fmt.Sprintf(`SELECT %s FROM buy_lots l WHERE l.id = ?`, buyLotColumns)
+
+	row := r.dbFromContext(ctx).QueryRowContext(ctx, query, lotID)
+	l := &BuyLot{}
+	err := row.Scan(&l.ID, &l.TransactionID, &l.UserID, &l.SpiceGradeID,
+		&l.OriginalQty, &l.RemainingQty, &l.Price, &l.TradeDate, &l.CreatedAt)
 
 	r.logger.Database().Debug().
 		Str("query", query).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("GetDailyPrice")
+		Msg("GetBuyLot")
 
-	if err == sql.ErrNoRows {
-		return 0, ErrNoPriceAvailable
-	}
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return price, nil
+	return l, nil
 }
 
-func (r *MysqlRepository) GetEnrichedHoldings(ctx context.Context, userID string) ([]EnrichedHoldingRow, error) {
+// ListSellAllocations returns allocations joined with their SELL, in booking order.
+func (r *MysqlRepository) ListSellAllocations(ctx context.Context, filter AllocationFilter) ([]*AllocationDetail, error) {
 	start := time.Now()
-	query := `SELECT p.spice_grade_id, pr.name, g.name,
-	                 p.total_qty, p.total_cost, p.realized_pnl,
-	                 COALESCE(dp.price, 0)
-	          FROM positions p
-	          INNER JOIN grade g ON g.id = p.spice_grade_id
-	          INNER JOIN products pr ON pr.id = g.product_id
-	          LEFT JOIN daily_price dp ON dp.grade_id = p.spice_grade_id AND dp.date = CURDATE()
-	          WHERE p.user_id = ? AND p.total_qty > 0
-	          ORDER BY p.total_qty DESC, pr.name, g.name`
+	take := filter.Take
+	if take == 0 || take > 100 {
+		take = 100
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	where, args := ledgerScope("t", filter.UserID, filter.SpiceGradeID)
+	if filter.SellTransactionID != "" {
+		where += " AND a.sell_transaction_id = ?"
+		args = append(args, filter.SellTransactionID)
+	}
+	if filter.BuyLotID != "" {
+		where += " AND a.buy_lot_id = ?"
+		args = append(args, filter.BuyLotID)
+	}
+	if !filter.IncludeReversed {
+		where += " AND a.reversed_by_transaction_id IS NULL"
+	}
+	where, args, err := appendDateRange(where, args, "t.trade_date", filter.DateFrom, filter.DateTo)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`SELECT a.id, a.sell_transaction_id, a.buy_lot_id, a.quantity, a.buy_price, a.sell_price,
+	                 a.realized_pnl, a.cost_basis_method, COALESCE(a.reversed_by_transaction_id, ''), a.created_at,
+	                 t.user_id, t.spice_grade_id, t.trade_date
+	          FROM sell_allocations a
+	          JOIN transactions t ON t.id = a.sell_transaction_id
+	          WHERE %s
+	          ORDER BY t.trade_date ASC, a.created_at ASC, a.id ASC
+	          LIMIT ? OFFSET ?`, where)
+	args = append(args, take, filter.Skip)
+
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
 
 	r.logger.Database().Debug().
 		Str("query", query).
-		Str("user_id", userID).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("GetEnrichedHoldings")
+		Msg("ListSellAllocations")
 
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var holdings []EnrichedHoldingRow
+	var allocs []*AllocationDetail
 	for rows.Next() {
-		var row EnrichedHoldingRow
-		if err := rows.Scan(
-			&row.SpiceGradeID,
-			&row.ProductName,
-			&row.GradeName,
-			&row.TotalQty,
-			&row.TotalCost,
-			&row.RealizedPnL,
-			&row.TodayPrice,
-		); err != nil {
+		a := &AllocationDetail{}
+		if err := rows.Scan(&a.ID, &a.SellTransactionID, &a.BuyLotID, &a.Quantity, &a.BuyPrice, &a.SellPrice,
+			&a.RealizedPnL, &a.CostBasisMethod, &a.ReversedByTransactionID, &a.CreatedAt,
+			&a.UserID, &a.SpiceGradeID, &a.SellTradeDate); err != nil {
 			return nil, err
 		}
-		holdings = append(holdings, row)
+		allocs = append(allocs, a)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return holdings, nil
+	return allocs, nil
 }
 
-// GetDailyRealizedPnLByUser returns realized P&L grouped by sell trade_date + grade.
-func (r *MysqlRepository) GetDailyRealizedPnLByUser(ctx context.Context, userID string, days uint) ([]DailyRealizedPnLRow, error) {
+// ListRealizedGains returns the standing sell allocations and short covers of a user's trades
+// realized between two dates: on the SELL's trade date for allocations and the covering BUY's
+// for covers. Cost, proceeds and holding period are left to the caller; Gain is realized_pnl.
+func (r *MysqlRepository) ListRealizedGains(ctx context.Context, userID, spiceGradeID, dateFrom, dateTo string) ([]*RealizedGain, error) {
 	start := time.Now()
-	query := `SELECT t.trade_date AS d,
-	                 COALESCE(SUM(sa.realized_pnl), 0),
-	                 t.spice_grade_id,
-	                 COALESCE(p.name, ''),
-	                 COALESCE(g.name, '')
-	          FROM sell_allocations sa
-	          INNER JOIN transactions t ON t.id = sa.sell_transaction_id
+	where, args := ledgerScope("t", userID, spiceGradeID)
+	where, args, err := appendDateRange(where, args, "t.trade_date", dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`SELECT 'LONG', a.id, a.sell_transaction_id, l.transaction_id, a.buy_lot_id,
+	                 t.spice_grade_id, COALESCE(p.name, ''), COALESCE(g.name, ''), t.currency, a.cost_basis_method,
+	                 a.quantity, a.buy_price, a.sell_price, a.close_adjustment, a.realized_pnl,
+	                 l.trade_date, t.trade_date, t.trade_date AS realized_date, a.created_at AS booked_at
+	          FROM sell_allocations a
+	          JOIN transactions t ON t.id = a.sell_transaction_id
+	          JOIN buy_lots l ON l.id = a.buy_lot_id
 	          LEFT JOIN grade g ON g.id = t.spice_grade_id
 	          LEFT JOIN products p ON p.id = g.product_id
-	          WHERE t.user_id = ?
-	            AND t.trade_date >= DATE_SUB(CURDATE(), INTERVAL ? DAY)
-	          GROUP BY t.trade_date, t.spice_grade_id, p.name, g.name
-	          ORDER BY d ASC`
+	          WHERE a.reversed_by_transaction_id IS NULL AND %[1]s
+	          UNION ALL
+	          SELECT 'SHORT', c.id, s.transaction_id, c.buy_transaction_id, c.short_lot_id,
+	                 t.spice_grade_id, COALESCE(p.name, ''), COALESCE(g.name, ''), t.currency, '',
+	                 c.quantity, c.cover_price, c.short_price, 0, c.realized_pnl,
+	                 t.trade_date, s.trade_date, t.trade_date, c.created_at
+	          FROM short_covers c
+	          JOIN transactions t ON t.id = c.buy_transaction_id
+	          JOIN short_lots s ON s.id = c.short_lot_id
+	          LEFT JOIN grade g ON g.id = t.spice_grade_id
+	          LEFT JOIN products p ON p.id = g.product_id
+	          WHERE c.reversed_by_transaction_id IS NULL AND %[1]s
+	          ORDER BY realized_date ASC, booked_at ASC, 2 ASC`, where)
+	args = append(args, args...)
 
-	rows, err := r.db.QueryContext(ctx, query, userID, days)
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
 
 	r.logger.Database().Debug().
 		Str("query", query).
-		Str("user_id", userID).
-		Int("days", int(days)).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("GetDailyRealizedPnLByUser")
+		Msg("ListRealizedGains")
 
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var result []DailyRealizedPnLRow
+	var gains []*RealizedGain
 	for rows.Next() {
-		var row DailyRealizedPnLRow
-		if err := rows.Scan(
-			&row.Date,
-			&row.DailyRealizedPnL,
-			&row.SpiceGradeID,
-			&row.ProductName,
-			&row.GradeName,
-		); err != nil {
+		g := &RealizedGain{}
+		var bookedAt time.Time
+		if err := rows.Scan(&g.Kind, &g.MatchID, &g.SellTransactionID, &g.BuyTransactionID, &g.LotID,
+			&g.SpiceGradeID, &g.ProductName, &g.GradeName, &g.Currency, &g.CostBasisMethod,
+			&g.Quantity, &g.BuyPrice, &g.SellPrice, &g.CloseAdjustment, &g.Gain,
+			&g.AcquiredDate, &g.DisposedDate, &g.RealizedDate, &bookedAt); err != nil {
 			return nil, err
 		}
-		result = append(result, row)
+		gains = append(gains, g)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return result, nil
+	return gains, nil
 }
 
-// GetDailyActivityByUser returns buy/sell quantity and counts grouped by trade date + grade.
-func (r *MysqlRepository) GetDailyActivityByUser(ctx context.Context, userID string, days uint) ([]DailyActivityRow, error) {
-	start := time.Now()
-	query := `SELECT t.trade_date, t.type, COALESCE(SUM(t.quantity), 0), COUNT(*),
-	                 t.spice_grade_id, COALESCE(p.name, ''), COALESCE(g.name, '')
-	          FROM transactions t
-	          LEFT JOIN grade g ON g.id = t.spice_grade_id
-	          LEFT JOIN products p ON p.id = g.product_id
-	          WHERE t.user_id = ?
-	            AND t.trade_date >= DATE_SUB(CURDATE(), INTERVAL ? DAY)
-	          GROUP BY t.trade_date, t.type, t.spice_grade_id, p.name, g.name
-	          ORDER BY t.trade_date ASC`
+// gradeNamesJoin joins the product and grade names of the grade in column as n. None of its
+// columns share a name with a ledger table's, so the ledger columns can stay unqualified.
+const gradeNamesJoin = `LEFT JOIN (SELECT g.id AS grade_id, g.product_id, g.name AS grade_name, p.name AS product_name
+	                     FROM grade g LEFT JOIN products p ON p.id = g.product_id) n ON n.grade_id = %s`
+
+// exportScope builds the WHERE clause of an export over alias, with dates on dateColumn; an
+// empty dateColumn ignores the dates.
+func exportScope(alias, dateColumn string, filter ExportFilter) (string, []any, error) {
+	where, args := ledgerScope(alias, filter.UserID, filter.SpiceGradeID)
+	if filter.ProductID != "" {
+		where += " AND n.product_id = ?"
+		args = append(args, filter.ProductID)
+	}
+	if dateColumn == "" {
+		return where, args, nil
+	}
+	return appendDateRange(where, args, dateColumn, filter.DateFrom, filter.DateTo)
+}
 
-	rows, err := r.db.QueryContext(ctx, query, userID, days)
+// exportDirection is the trade-date order of an export: newest first unless sort is ASC.
+func exportDirection(sort string) string {
+	switch strings.ToUpper(strings.TrimSpace(sort)) {
+	case "ASC", "OLDEST", "OLDEST_FIRST":
+		return "ASC"
+	}
+	return "DESC"
+}
+
+// streamRows runs query and hands each row to scan as it is read, so an export never holds
+// more than one row in memory.
+func (r *MysqlRepository) streamRows(ctx context.Context, name, query string, args []any, scan func(rowScanner) error) error {
+	start := time.Now()
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, args...)
 
 	r.logger.Database().Debug().
 		Str("query", query).
-		Str("user_id", userID).
-		Int("days", int(days)).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("GetDailyActivityByUser")
+		Msg(name)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var result []DailyActivityRow
 	for rows.Next() {
-		var row DailyActivityRow
-		if err := rows.Scan(
-			&row.Date,
-			&row.Type,
-			&row.Quantity,
-			&row.Count,
-			&row.SpiceGradeID,
-			&row.ProductName,
-			&row.GradeName,
-		); err != nil {
-			return nil, err
+		if err := scan(rows); err != nil {
+			return err
 		}
-		result = append(result, row)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return result, nil
+	return rows.Err()
 }
 
-// GetPeriodTradeStats aggregates trade count and buy/sell volume for a merchant over a date window.
-func (r *MysqlRepository) GetPeriodTradeStats(ctx context.Context, userID string, days uint) (*PeriodTradeStats, error) {
-	start := time.Now()
-	query := `SELECT COUNT(*),
-	                 COALESCE(SUM(CASE WHEN type = 'BUY' THEN quantity ELSE 0 END), 0),
-	                 COALESCE(SUM(CASE WHEN type = 'SELL' THEN quantity ELSE 0 END), 0)
+// ExportTransactions streams every transaction matching filter, in trade-date order.
+func (r *MysqlRepository) ExportTransactions(ctx context.Context, filter ExportFilter, fn func(*LedgerExportRow) error) error {
+	where, args, err := exportScope("transactions", "transactions.trade_date", filter)
+	if err != nil {
+		return err
+	}
+	dir := exportDirection(filter.Sort)
+	query := fmt.Sprintf(`SELECT %s, COALESCE(n.product_name, ''), COALESCE(n.grade_name, '')
 	          FROM transactions
-	          WHERE user_id = ?
-	            AND trade_date >= DATE_SUB(CURDATE(), INTERVAL ? DAY)`
+	          %s
+	          WHERE %s
+	          ORDER BY transactions.trade_date %s, transactions.created_at %s, transactions.id %s`,
+		transactionColumns, fmt.Sprintf(gradeNamesJoin, "transactions.spice_grade_id"), where, dir, dir, dir)
 
-	stats := &PeriodTradeStats{}
-	err := r.db.QueryRowContext(ctx, query, userID, days).Scan(
-		&stats.TradesInPeriod,
-		&stats.BuyVolumeInPeriod,
-		&stats.SellVolumeInPeriod,
-	)
+	return r.streamRows(ctx, "ExportTransactions", query, args, func(row rowScanner) error {
+		t := &Transaction{}
+		out := &LedgerExportRow{Transaction: t}
+		if err := row.Scan(&t.ID, &t.UserID, &t.SpiceGradeID, &t.Type, &t.Quantity, &t.Price, &t.Currency, &t.FeeTotal,
+			&t.CostBasisMethod, &t.Status, &t.ReversesTransactionID, &t.AmendsTransactionID, &t.Note,
+			&t.IdempotencyKey, &t.TradeDate, &t.CreatedAt, &out.ProductName, &out.GradeName); err != nil {
+			return err
+		}
+		return fn(out)
+	})
+}
 
-	r.logger.Database().Debug().
-		Str("query", query).
-		Str("user_id", userID).
-		Int("days", int(days)).
-		Str("duration", time.Since(start).String()).
-		Bool("success", err == nil).
-		Msg("GetPeriodTradeStats")
+// ExportBuyLots streams the buy lots matching filter, closed ones too unless OpenOnly is set.
+func (r *MysqlRepository) ExportBuyLots(ctx context.Context, filter ExportFilter, fn func(*LedgerExportRow) error) error {
+	where, args, err := exportScope("l", "l.trade_date", filter)
+	if err != nil {
+		return err
+	}
+	if filter.OpenOnly {
+		where += " AND l.remaining_qty > 0"
+	}
+	dir := exportDirection(filter.Sort)
+	query := fmt.Sprintf(`SELECT %s, COALESCE(n.product_name, ''), COALESCE(n.grade_name, '')
+	          FROM buy_lots l
+	          %s
+	          WHERE %s
+	          ORDER BY l.trade_date %s, l.created_at %s, l.id %s`,
+		buyLotColumns, fmt.Sprintf(gradeNamesJoin, "l.spice_grade_id"), where, dir, dir, dir)
+
+	return r.streamRows(ctx, "ExportBuyLots", query, args, func(row rowScanner) error {
+		l := &BuyLot{}
+		out := &LedgerExportRow{Lot: l}
+		if err := row.Scan(&l.ID, &l.TransactionID, &l.UserID, &l.SpiceGradeID,
+			&l.OriginalQty, &l.RemainingQty, &l.Price, &l.TradeDate, &l.CreatedAt,
+			&out.ProductName, &out.GradeName); err != nil {
+			return err
+		}
+		return fn(out)
+	})
+}
 
+// ExportSellAllocations streams the allocations of the sells matching filter, dated by the sell.
+func (r *MysqlRepository) ExportSellAllocations(ctx context.Context, filter ExportFilter, fn func(*LedgerExportRow) error) error {
+	where, args, err := exportScope("t", "t.trade_date", filter)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return stats, nil
+	if !filter.IncludeReversed {
+		where += " AND a.reversed_by_transaction_id IS NULL"
+	}
+	dir := exportDirection(filter.Sort)
+	query := fmt.Sprintf(`SELECT a.id, a.sell_transaction_id, a.buy_lot_id, a.quantity, a.buy_price, a.sell_price,
+	                 a.realized_pnl, a.cost_basis_method, COALESCE(a.reversed_by_transaction_id, ''), a.created_at,
+	                 t.user_id, t.spice_grade_id, t.trade_date,
+	                 COALESCE(n.product_name, ''), COALESCE(n.grade_name, '')
+	          FROM sell_allocations a
+	          JOIN transactions t ON t.id = a.sell_transaction_id
+	          %s
+	          WHERE %s
+	          ORDER BY t.trade_date %s, a.created_at %s, a.id %s`,
+		fmt.Sprintf(gradeNamesJoin, "t.spice_grade_id"), where, dir, dir, dir)
+
+	return r.streamRows(ctx, "ExportSellAllocations", query, args, func(row rowScanner) error {
+		a := &AllocationDetail{}
+		out := &LedgerExportRow{Allocation: a}
+		if err := row.Scan(&a.ID, &a.SellTransactionID, &a.BuyLotID, &a.Quantity, &a.BuyPrice, &a.SellPrice,
+			&a.RealizedPnL, &a.CostBasisMethod, &a.ReversedByTransactionID, &a.CreatedAt,
+			&a.UserID, &a.SpiceGradeID, &a.SellTradeDate,
+			&out.ProductName, &out.GradeName); err != nil {
+			return err
+		}
+		return fn(out)
+	})
 }
 
-// GetPriceSnapshotsForHoldings returns today vs yesterday daily_price for grades the merchant holds.
-func (r *MysqlRepository) GetPriceSnapshotsForHoldings(ctx context.Context, userID string) ([]PriceSnapshot, error) {
+// ExportPositions streams the stored positions matching filter, open or with realized P&L,
+// by account, product and grade. Dates and sort do not apply.
+func (r *MysqlRepository) ExportPositions(ctx context.Context, filter ExportFilter, fn func(pos *Position, productName, gradeName string) error) error {
+	where, args, err := exportScope("positions", "", filter)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`SELECT user_id, spice_grade_id, currency, total_qty, total_cost, realized_pnl, updated_at,
+	                 COALESCE(n.product_name, ''), COALESCE(n.grade_name, '')
+	          FROM positions
+	          %s
+	          WHERE %s AND (total_qty != 0 OR realized_pnl != 0)
+	          ORDER BY user_id, n.product_name, n.grade_name, spice_grade_id`,
+		fmt.Sprintf(gradeNamesJoin, "positions.spice_grade_id"), where)
+
+	return r.streamRows(ctx, "ExportPositions", query, args, func(row rowScanner) error {
+		pos := &Position{}
+		var productName, gradeName string
+		if err := row.Scan(&pos.UserID, &pos.SpiceGradeID, &pos.Currency, &pos.TotalQty, &pos.TotalCost,
+			&pos.RealizedPnL, &pos.UpdatedAt, &productName, &gradeName); err != nil {
+			return err
+		}
+		return fn(pos, productName, gradeName)
+	})
+}
+
+// ListAgeingLots returns a user's open lots with their grade's shelf life, grouped by grade.
+func (r *MysqlRepository) ListAgeingLots(ctx context.Context, userID string) ([]AgeingLotRow, error) {
 	start := time.Now()
-	query := `SELECT p.spice_grade_id, pr.name, g.name,
-	                 COALESCE(dp_today.price, 0),
-	                 COALESCE(dp_yesterday.price, 0)
-	          FROM positions p
-	          INNER JOIN grade g ON g.id = p.spice_grade_id
-	          INNER JOIN products pr ON pr.id = g.product_id
-	          LEFT JOIN daily_price dp_today
-	            ON dp_today.grade_id = p.spice_grade_id AND dp_today.date = CURDATE()
-	          LEFT JOIN daily_price dp_yesterday
-	            ON dp_yesterday.grade_id = p.spice_grade_id
-	           AND dp_yesterday.date = DATE_SUB(CURDATE(), INTERVAL 1 DAY)
-	          WHERE p.user_id = ? AND p.total_qty > 0
-	          ORDER BY pr.name, g.name`
+	query := `SELECT l.id, l.spice_grade_id, COALESCE(p.name, ''), COALESCE(g.name, ''),
+	                 COALESCE(g.shelf_life_days, 0), COALESCE(t.currency, ''), l.remaining_qty, l.price, l.trade_date
+	          FROM buy_lots l
+	          LEFT JOIN transactions t ON t.id = l.transaction_id
+	          LEFT JOIN grade g ON g.id = l.spice_grade_id
+	          LEFT JOIN products p ON p.id = g.product_id
+	          WHERE l.user_id = ? AND l.remaining_qty > 0
+	          ORDER BY l.spice_grade_id, l.trade_date, l.id`
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := r.dbFromContext(ctx).QueryContext(ctx, query, userID)
 
 	r.logger.Database().Debug().
 		Str("query", query).
-		Str("user_id", userID).
 		Str("duration", time.Since(start).String()).
 		Bool("success", err == nil).
-		Msg("GetPriceSnapshotsForHoldings")
+		Msg("ListAgeingLots")
 
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var snapshots []PriceSnapshot
+	var lots []AgeingLotRow
 	for rows.Next() {
-		var snap PriceSnapshot
-		if err := rows.Scan(
-			&snap.SpiceGradeID,
-			&snap.ProductName,
-			&snap.GradeName,
-			&snap.TodayPrice,
-			&snap.PreviousPrice,
-		); err != nil {
+		var l AgeingLotRow
+		if err := rows.Scan(&l.LotID, &l.SpiceGradeID, &l.ProductName, &l.GradeName,
+			&l.ShelfLifeDays, &l.Currency, &l.RemainingQty, &l.Price, &l.TradeDate); err != nil {
 			return nil, err
 		}
-		snapshots = append(snapshots, snap)
+		lots = append(lots, l)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return snapshots, nil
+	return lots, nil
 }
 
 // --- Sentinel Errors ---
@@ -880,8 +2968,86 @@ type errInsufficientLotQty string
 
 func (e errInsufficientLotQty) Error() string { return string(e) }
 
+var ErrDuplicateIdempotencyKey = errDuplicateIdempotencyKey("idempotency key already used by this account")
+
+type errDuplicateIdempotencyKey string
+
+func (e errDuplicateIdempotencyKey) Error() string { return string(e) }
+
+var ErrIdempotencyKeyReused = errIdempotencyKeyReused("idempotency key was already used for a different trade")
+
+type errIdempotencyKeyReused string
+
+func (e errIdempotencyKeyReused) Error() string { return string(e) }
+
+var ErrTransactionNotActive = errTransactionNotActive("transaction is not active: it was already cancelled or is a reversal")
+
+type errTransactionNotActive string
+
+func (e errTransactionNotActive) Error() string { return string(e) }
+
+var ErrLotConsumed = errLotConsumed("buy lot is used by later sells (consumed, or priced into a weighted average): cancel with reallocate to re-match them")
+
+type errLotConsumed string
+
+func (e errLotConsumed) Error() string { return string(e) }
+
+var ErrLotRestoreMismatch = errLotRestoreMismatch("buy lot cannot take back more than its original quantity")
+
+type errLotRestoreMismatch string
+
+func (e errLotRestoreMismatch) Error() string { return string(e) }
+
 var ErrNoPriceAvailable = errNoPriceAvailable("no daily price available for this grade on the given date")
 
 type errNoPriceAvailable string
 
 func (e errNoPriceAvailable) Error() string { return string(e) }
+
+var ErrInsufficientShortQty = errInsufficientShortQty("insufficient short lot quantity: possible concurrent cover")
+
+type errInsufficientShortQty string
+
+func (e errInsufficientShortQty) Error() string { return string(e) }
+
+var ErrShortRestoreMismatch = errShortRestoreMismatch("short lot cannot reopen more than its original quantity")
+
+type errShortRestoreMismatch string
+
+func (e errShortRestoreMismatch) Error() string { return string(e) }
+
+var ErrShortLotCovered = errShortLotCovered("short position opened by this sell is already (partly) covered by later buys: cancel those buys first")
+
+type errShortLotCovered string
+
+func (e errShortLotCovered) Error() string { return string(e) }
+
+var ErrCoverSuperseded = errCoverSuperseded("short lots covered by this buy cannot reopen while later buys hold open inventory of the grade: cancel those buys first")
+
+type errCoverSuperseded string
+
+func (e errCoverSuperseded) Error() string { return string(e) }
+
+var ErrOrderNotOpen = errOrderNotOpen("order is no longer open: it was filled or cancelled")
+
+type errOrderNotOpen string
+
+func (e errOrderNotOpen) Error() string { return string(e) }
+
+var ErrNoFxRate = errNoFxRate("no FX rate available for the currency pair on the given date")
+
+type errNoFxRate string
+
+func (e errNoFxRate) Error() string { return string(e) }
+
+var ErrCurrencyMismatch = errCurrencyMismatch("trade currency differs from the currency of the open position")
+
+type errCurrencyMismatch string
+
+func (e errCurrencyMismatch) Error() string { return string(e) }
+
+var ErrUnitNotTraded = errUnitNotTraded("grade does not trade in this unit: only a grade sold in bags takes BAG")
+
+type errUnitNotTraded string
+
+func (e errUnitNotTraded) Error() string { return string(e) }
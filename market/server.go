@@ -2,13 +2,16 @@ package market
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"time"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 
 	"github.com/Asif-Faizal/SpiceLedger-Backend/internal/platform"
 	pb "github.com/Asif-Faizal/SpiceLedger-Backend/market/pb"
@@ -22,7 +25,7 @@ type GrpcServer struct {
 	pb.UnimplementedMarketServiceServer
 }
 
-func ListenGrpcServer(service Service, logger util.Logger, config *util.Config) error {
+func ListenGrpcServer(service Service, keys util.KeyResolver, sessions util.SessionVerifier, logger util.Logger, config *util.Config) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", config.MarketGrpcPort))
 	if err != nil {
 		return err
@@ -31,7 +34,11 @@ func ListenGrpcServer(service Service, logger util.Logger, config *util.Config)
 	grpcServer := grpc.NewServer(
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			util.UnaryServerInterceptor(logger),
-			util.AuthInterceptor(config.JWTSecret, config.BasicAuthUser, config.BasicAuthPass),
+			util.AuthInterceptor(keys, sessions, config.BasicAuthUser, config.BasicAuthPass),
+		)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
+			util.StreamServerInterceptor(logger),
+			util.StreamAuthInterceptor(keys, sessions, config.BasicAuthUser, config.BasicAuthPass),
 		)),
 	)
 
@@ -52,20 +59,28 @@ func (server *GrpcServer) GetMarketMetrics(ctx context.Context, req *pb.GetMarke
 	if err != nil {
 		return nil, err
 	}
+	callerID, _ := ctx.Value(util.AccountIDKey).(string)
+	totalValue, currency, unconverted, err := server.marketService.GetTradedValue(ctx, callerID)
+	if err != nil {
+		return nil, err
+	}
 
 	var pbTops []*pb.GetMarketMetricsResponse_TopProduct
 	for _, top := range tops {
 		pbTops = append(pbTops, &pb.GetMarketMetricsResponse_TopProduct{
 			ProductName: top.ProductName,
 			GradeName:   top.GradeName,
-			Volume:      top.Volume,
+			Volume:      top.Volume.String(),
 		})
 	}
 
 	return &pb.GetMarketMetricsResponse{
-		TotalTransactions: totalTx,
-		TotalVolume:       totalVol,
-		TopProducts:       pbTops,
+		TotalTransactions:     totalTx,
+		TotalVolume:           totalVol.String(),
+		TopProducts:           pbTops,
+		TotalValue:            totalValue.String(),
+		Currency:              currency,
+		UnconvertedCurrencies: unconverted,
 	}, nil
 }
 
@@ -82,22 +97,22 @@ func (server *GrpcServer) Buy(ctx context.Context, req *pb.BuyRequest) (*pb.BuyR
 		}
 	}
 
-	txn, err := server.marketService.Buy(ctx, userID, req.SpiceGradeId, req.Quantity, req.Price, tradeDate)
+	quantity, err := util.ParseDecimal("quantity", req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	price, err := util.ParseDecimal("price", req.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := server.marketService.Buy(ctx, userID, req.SpiceGradeId, quantity, price, req.Unit, tradeDate, req.IdempotencyKey)
 	if err != nil {
 		return nil, err
 	}
 
 	return &pb.BuyResponse{
-		Transaction: &pb.Transaction{
-			Id:           txn.ID,
-			UserId:       txn.UserID,
-			SpiceGradeId: txn.SpiceGradeID,
-			Type:         txn.Type,
-			Quantity:     txn.Quantity,
-			Price:        txn.Price,
-			TradeDate:    txn.TradeDate.Format("2006-01-02"),
-			CreatedAt:    txn.CreatedAt.Format("2006-01-02 15:04:05"),
-		},
+		Transaction: transactionToProto(txn),
 	}, nil
 }
 
@@ -114,25 +129,392 @@ func (server *GrpcServer) Sell(ctx context.Context, req *pb.SellRequest) (*pb.Se
 		}
 	}
 
-	txn, err := server.marketService.Sell(ctx, userID, req.SpiceGradeId, req.Quantity, req.Price, tradeDate)
+	quantity, err := util.ParseDecimal("quantity", req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	price, err := util.ParseDecimal("price", req.Price)
+	if err != nil {
+		return nil, err
+	}
+	lots, err := lotSelectionsFromProto(req.Lots)
+	if err != nil {
+		return nil, err
+	}
+	opts := SellOptions{CostBasisMethod: req.CostBasisMethod, Lots: lots}
+
+	txn, err := server.marketService.Sell(ctx, userID, req.SpiceGradeId, quantity, price, req.Unit, tradeDate, req.IdempotencyKey, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	return &pb.SellResponse{
-		Transaction: &pb.Transaction{
-			Id:           txn.ID,
-			UserId:       txn.UserID,
-			SpiceGradeId: txn.SpiceGradeID,
-			Type:         txn.Type,
-			Quantity:     txn.Quantity,
-			Price:        txn.Price,
-			TradeDate:    txn.TradeDate.Format("2006-01-02"),
-			CreatedAt:    txn.CreatedAt.Format("2006-01-02 15:04:05"),
-		},
+		Transaction: transactionToProto(txn),
+	}, nil
+}
+
+func (server *GrpcServer) CancelTransaction(ctx context.Context, req *pb.CancelTransactionRequest) (*pb.CancelTransactionResponse, error) {
+	userID := tradeOwnerScope(ctx, req.UserId)
+
+	result, err := server.marketService.CancelTransaction(ctx, userID, req.TransactionId, req.Reason, req.Reallocate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CancelTransactionResponse{
+		Original:           transactionToProto(result.Original),
+		Reversal:           transactionToProto(result.Reversal),
+		ReallocatedSellIds: result.ReallocatedSellIDs,
 	}, nil
 }
 
+func (server *GrpcServer) AmendTransaction(ctx context.Context, req *pb.AmendTransactionRequest) (*pb.AmendTransactionResponse, error) {
+	userID := tradeOwnerScope(ctx, req.UserId)
+
+	quantity, err := util.ParseDecimal("quantity", req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	price, err := util.ParseDecimal("price", req.Price)
+	if err != nil {
+		return nil, err
+	}
+	lots, err := lotSelectionsFromProto(req.Lots)
+	if err != nil {
+		return nil, err
+	}
+
+	amend := Amendment{
+		Quantity:   quantity,
+		Price:      price,
+		Reason:     req.Reason,
+		Reallocate: req.Reallocate,
+		Sell:       SellOptions{CostBasisMethod: req.CostBasisMethod, Lots: lots},
+	}
+	if req.TradeDate != "" {
+		tradeDate, err := time.Parse("2006-01-02", req.TradeDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trade_date %q: use YYYY-MM-DD", req.TradeDate)
+		}
+		amend.TradeDate = tradeDate
+	}
+
+	result, err := server.marketService.AmendTransaction(ctx, userID, req.TransactionId, amend)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AmendTransactionResponse{
+		Transaction:        transactionToProto(result.Transaction),
+		Original:           transactionToProto(result.Cancel.Original),
+		Reversal:           transactionToProto(result.Cancel.Reversal),
+		ReallocatedSellIds: result.Cancel.ReallocatedSellIDs,
+	}, nil
+}
+
+// ReconcileLedger replays the ledger and reports (optionally rebuilds) drifting positions and lots. Admin only.
+func (server *GrpcServer) ReconcileLedger(ctx context.Context, req *pb.ReconcileLedgerRequest) (*pb.ReconcileLedgerResponse, error) {
+	if isAdmin, ok := ctx.Value(util.IsAdminKey).(bool); !ok || !isAdmin {
+		return nil, status.Error(codes.PermissionDenied, "admin access required")
+	}
+
+	report, err := server.marketService.ReconcileLedger(ctx, req.UserId, req.SpiceGradeId, req.Rebuild)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ReconcileLedgerResponse{
+		PositionsChecked: uint32(report.PositionsChecked),
+		LotsChecked:      uint32(report.LotsChecked),
+		Rebuilt:          report.Rebuilt,
+	}
+	for _, d := range report.Positions {
+		resp.Positions = append(resp.Positions, &pb.PositionDrift{
+			UserId:       d.UserID,
+			SpiceGradeId: d.SpiceGradeID,
+			Stored:       positionTotalsToProto(d.Stored),
+			Expected:     positionTotalsToProto(d.Expected),
+			Missing:      d.Missing,
+		})
+	}
+	for _, d := range report.Lots {
+		resp.Lots = append(resp.Lots, &pb.LotDrift{
+			LotId:             d.LotID,
+			Short:             d.Short,
+			UserId:            d.UserID,
+			SpiceGradeId:      d.SpiceGradeID,
+			StoredRemaining:   d.StoredRemaining.String(),
+			ExpectedRemaining: d.ExpectedRemaining.String(),
+		})
+	}
+	return resp, nil
+}
+
+func positionTotalsToProto(p Position) *pb.PositionTotals {
+	return &pb.PositionTotals{
+		TotalQty:    p.TotalQty.String(),
+		TotalCost:   p.TotalCost.String(),
+		RealizedPnl: p.RealizedPnL.String(),
+		Currency:    p.Currency,
+	}
+}
+
+func lotSelectionsFromProto(lots []*pb.LotSelection) ([]LotSelection, error) {
+	var selections []LotSelection
+	for _, lot := range lots {
+		qty, err := util.ParseDecimal("lot quantity", lot.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, LotSelection{LotID: lot.LotId, Quantity: qty})
+	}
+	return selections, nil
+}
+
+// tradeOwnerScope returns the account a cancel/amend is limited to. Admins are not
+// limited (empty scope); everyone else is held to their own trades.
+func tradeOwnerScope(ctx context.Context, requested string) string {
+	if isAdmin, ok := ctx.Value(util.IsAdminKey).(bool); ok && isAdmin {
+		return ""
+	}
+	if id, ok := ctx.Value(util.AccountIDKey).(string); ok && id != "" {
+		return id
+	}
+	return requested
+}
+
+// preferenceOwner returns the account whose cost-basis preference a call reads or writes.
+// Non-admins are held to their own account like tradeOwnerScope; an admin acts on the
+// account they name, or their own.
+func preferenceOwner(ctx context.Context, requested string) string {
+	if userID := tradeOwnerScope(ctx, requested); userID != "" {
+		return userID
+	}
+	if requested != "" {
+		return requested
+	}
+	id, _ := ctx.Value(util.AccountIDKey).(string)
+	return id
+}
+
+func (server *GrpcServer) SetCostBasisMethod(ctx context.Context, req *pb.SetCostBasisMethodRequest) (*pb.SetCostBasisMethodResponse, error) {
+	pref, err := server.marketService.SetCostBasisMethod(ctx, preferenceOwner(ctx, req.UserId), req.SpiceGradeId, req.Method)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetCostBasisMethodResponse{Preference: costBasisPreferenceToProto(pref)}, nil
+}
+
+func (server *GrpcServer) GetCostBasisMethod(ctx context.Context, req *pb.GetCostBasisMethodRequest) (*pb.GetCostBasisMethodResponse, error) {
+	pref, err := server.marketService.GetCostBasisMethod(ctx, preferenceOwner(ctx, req.UserId), req.SpiceGradeId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetCostBasisMethodResponse{Preference: costBasisPreferenceToProto(pref)}, nil
+}
+
+func (server *GrpcServer) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.PlaceOrderResponse, error) {
+	userID := req.UserId
+	if userID == "" {
+		if id, ok := ctx.Value(util.AccountIDKey).(string); ok {
+			userID = id
+		}
+	}
+
+	quantity, err := util.ParseDecimal("quantity", req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	price, err := util.ParseDecimal("price", req.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := server.marketService.PlaceOrder(ctx, userID, req.SpiceGradeId, req.Side, quantity, price)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PlaceOrderResponse{
+		Order: orderToProto(result.Order),
+		Fills: orderFillsToProto(result.Fills),
+	}, nil
+}
+
+func (server *GrpcServer) AmendOrder(ctx context.Context, req *pb.AmendOrderRequest) (*pb.AmendOrderResponse, error) {
+	quantity, err := util.ParseDecimal("quantity", req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	price, err := util.ParseDecimal("price", req.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := server.marketService.AmendOrder(ctx, tradeOwnerScope(ctx, req.UserId), req.OrderId, quantity, price)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AmendOrderResponse{
+		Order: orderToProto(result.Order),
+		Fills: orderFillsToProto(result.Fills),
+	}, nil
+}
+
+func (server *GrpcServer) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.CancelOrderResponse, error) {
+	order, err := server.marketService.CancelOrder(ctx, tradeOwnerScope(ctx, req.UserId), req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CancelOrderResponse{Order: orderToProto(order)}, nil
+}
+
+func (server *GrpcServer) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.GetOrderResponse, error) {
+	result, err := server.marketService.GetOrder(ctx, lotReadScope(ctx, req.UserId), req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetOrderResponse{
+		Order: orderToProto(result.Order),
+		Fills: orderFillsToProto(result.Fills),
+	}, nil
+}
+
+func (server *GrpcServer) ListOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	orders, err := server.marketService.ListOrders(ctx, OrderFilter{
+		UserID:       lotReadScope(ctx, req.UserId),
+		SpiceGradeID: req.SpiceGradeId,
+		Side:         req.Side,
+		Status:       req.Status,
+		Skip:         uint(req.Skip),
+		Take:         uint(req.Take),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	protoOrders := make([]*pb.Order, 0, len(orders))
+	for _, o := range orders {
+		protoOrders = append(protoOrders, orderToProto(o))
+	}
+	return &pb.ListOrdersResponse{Orders: protoOrders}, nil
+}
+
+// GetOrderBook returns the anonymous resting depth of a grade; any authenticated caller may read it.
+func (server *GrpcServer) GetOrderBook(ctx context.Context, req *pb.GetOrderBookRequest) (*pb.GetOrderBookResponse, error) {
+	callerID, _ := ctx.Value(util.AccountIDKey).(string)
+	book, err := server.marketService.GetOrderBook(ctx, callerID, req.SpiceGradeId, req.Currency, uint(req.Depth))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetOrderBookResponse{
+		SpiceGradeId: book.SpiceGradeID,
+		Currency:     book.Currency,
+		Bids:         orderBookLevelsToProto(book.Bids),
+		Asks:         orderBookLevelsToProto(book.Asks),
+	}, nil
+}
+
+// SetTradingPermissions turns opt-in capabilities such as short selling on or off for an account. Admin only.
+func (server *GrpcServer) SetTradingPermissions(ctx context.Context, req *pb.SetTradingPermissionsRequest) (*pb.SetTradingPermissionsResponse, error) {
+	if isAdmin, ok := ctx.Value(util.IsAdminKey).(bool); !ok || !isAdmin {
+		return nil, status.Error(codes.PermissionDenied, "admin access required")
+	}
+	updatedBy, _ := ctx.Value(util.AccountIDKey).(string)
+
+	perms, err := server.marketService.SetTradingPermissions(ctx, req.UserId, req.AllowShortSelling, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetTradingPermissionsResponse{Permissions: tradingPermissionsToProto(perms)}, nil
+}
+
+// GetTradingPermissions returns an account's capabilities. Only admins may name another account.
+func (server *GrpcServer) GetTradingPermissions(ctx context.Context, req *pb.GetTradingPermissionsRequest) (*pb.GetTradingPermissionsResponse, error) {
+	userID := req.UserId
+	if isAdmin, ok := ctx.Value(util.IsAdminKey).(bool); !ok || !isAdmin || userID == "" {
+		if id, ok := ctx.Value(util.AccountIDKey).(string); ok && id != "" {
+			userID = id
+		}
+	}
+
+	perms, err := server.marketService.GetTradingPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetTradingPermissionsResponse{Permissions: tradingPermissionsToProto(perms)}, nil
+}
+
+// SubscribeTrades streams the caller's committed trades until the client disconnects.
+// Admins may follow another account, or every account by leaving user_id empty.
+func (server *GrpcServer) SubscribeTrades(req *pb.SubscribeTradesRequest, stream pb.MarketService_SubscribeTradesServer) error {
+	ctx := stream.Context()
+	if isAuthenticated, ok := ctx.Value(util.IsAuthenticatedKey).(bool); !ok || !isAuthenticated {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	sub, err := server.marketService.SubscribeTrades(lotReadScope(ctx, req.UserId), req.SpiceGradeId, req.Epoch, req.AfterSequence)
+	if errors.Is(err, platform.ErrCursorExpired) {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, sub.Err().Error())
+			}
+			if err := stream.Send(&pb.TradeEvent{
+				Sequence:    ev.Seq,
+				Epoch:       ev.Epoch,
+				Transaction: transactionToProto(ev.Payload.(*Transaction)),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ExportLedger streams every transaction, lot, allocation or position matching the filters,
+// one message per record, without the list queries' page limit. Merchants export their own
+// ledger; admins may name an account or leave user_id empty for all of them.
+func (server *GrpcServer) ExportLedger(req *pb.ExportLedgerRequest, stream pb.MarketService_ExportLedgerServer) error {
+	ctx := stream.Context()
+	if isAuthenticated, ok := ctx.Value(util.IsAuthenticatedKey).(bool); !ok || !isAuthenticated {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	return server.marketService.ExportLedger(ctx, ExportFilter{
+		Kind:            req.Kind,
+		UserID:          lotReadScope(ctx, req.UserId),
+		SpiceGradeID:    req.SpiceGradeId,
+		ProductID:       req.ProductId,
+		Sort:            req.Sort,
+		DateFrom:        req.DateFrom,
+		DateTo:          req.DateTo,
+		OpenOnly:        req.OpenOnly,
+		IncludeReversed: req.IncludeReversed,
+	}, func(row *LedgerExportRow) error {
+		out := &pb.ExportLedgerRow{ProductName: row.ProductName, GradeName: row.GradeName}
+		switch {
+		case row.Transaction != nil:
+			out.Record = &pb.ExportLedgerRow_Transaction{Transaction: transactionToProto(row.Transaction)}
+		case row.Lot != nil:
+			out.Record = &pb.ExportLedgerRow_Lot{Lot: buyLotToProto(row.Lot)}
+		case row.Allocation != nil:
+			out.Record = &pb.ExportLedgerRow_Allocation{Allocation: allocationToProto(row.Allocation)}
+		case row.Position != nil:
+			out.Record = &pb.ExportLedgerRow_Position{Position: positionViewToProto(row.Position)}
+		}
+		return stream.Send(out)
+	})
+}
+
 func (server *GrpcServer) GetGradePosition(ctx context.Context, req *pb.GetGradePositionRequest) (*pb.GetGradePositionResponse, error) {
 	userID := req.UserId
 	if userID == "" {
@@ -147,17 +529,7 @@ func (server *GrpcServer) GetGradePosition(ctx context.Context, req *pb.GetGrade
 	}
 
 	return &pb.GetGradePositionResponse{
-		Position: &pb.PositionView{
-			UserId:        pos.UserID,
-			SpiceGradeId:  pos.SpiceGradeID,
-			TotalQty:      pos.TotalQty,
-			TotalCost:     pos.TotalCost,
-			AvgCost:       pos.AvgCost,
-			TodayPrice:    pos.TodayPrice,
-			RealizedPnl:   pos.RealizedPnL,
-			UnrealizedPnl: pos.UnrealizedPnL,
-			UpdatedAt:     pos.UpdatedAt.Format("2006-01-02 15:04:05"),
-		},
+		Position: positionViewToProto(pos),
 	}, nil
 }
 
@@ -176,17 +548,7 @@ func (server *GrpcServer) GetPositions(ctx context.Context, req *pb.GetPositions
 
 	var pbPositions []*pb.PositionView
 	for _, pos := range positions {
-		pbPositions = append(pbPositions, &pb.PositionView{
-			UserId:        pos.UserID,
-			SpiceGradeId:  pos.SpiceGradeID,
-			TotalQty:      pos.TotalQty,
-			TotalCost:     pos.TotalCost,
-			AvgCost:       pos.AvgCost,
-			TodayPrice:    pos.TodayPrice,
-			RealizedPnl:   pos.RealizedPnL,
-			UnrealizedPnl: pos.UnrealizedPnL,
-			UpdatedAt:     pos.UpdatedAt.Format("2006-01-02 15:04:05"),
-		})
+		pbPositions = append(pbPositions, positionViewToProto(pos))
 	}
 
 	return &pb.GetPositionsResponse{
@@ -218,16 +580,7 @@ func (server *GrpcServer) ListGradeTransactions(ctx context.Context, req *pb.Lis
 
 	var protoTxns []*pb.Transaction
 	for _, txn := range txns {
-		protoTxns = append(protoTxns, &pb.Transaction{
-			Id:           txn.ID,
-			UserId:       txn.UserID,
-			SpiceGradeId: txn.SpiceGradeID,
-			Type:         txn.Type,
-			Quantity:     txn.Quantity,
-			Price:        txn.Price,
-			TradeDate:    txn.TradeDate.Format("2006-01-02"),
-			CreatedAt:    txn.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
+		protoTxns = append(protoTxns, transactionToProto(txn))
 	}
 
 	return &pb.ListGradeTransactionsResponse{
@@ -281,16 +634,7 @@ func (server *GrpcServer) ListTransactions(ctx context.Context, req *pb.ListTran
 
 	var protoTxns []*pb.Transaction
 	for _, txn := range txns {
-		protoTxns = append(protoTxns, &pb.Transaction{
-			Id:           txn.ID,
-			UserId:       txn.UserID,
-			SpiceGradeId: txn.SpiceGradeID,
-			Type:         txn.Type,
-			Quantity:     txn.Quantity,
-			Price:        txn.Price,
-			TradeDate:    txn.TradeDate.Format("2006-01-02"),
-			CreatedAt:    txn.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
+		protoTxns = append(protoTxns, transactionToProto(txn))
 	}
 
 	return &pb.ListTransactionsResponse{
@@ -298,6 +642,202 @@ func (server *GrpcServer) ListTransactions(ctx context.Context, req *pb.ListTran
 	}, nil
 }
 
+func (server *GrpcServer) ListOpenLots(ctx context.Context, req *pb.ListOpenLotsRequest) (*pb.ListOpenLotsResponse, error) {
+	lots, err := server.marketService.ListOpenLots(
+		ctx,
+		lotReadScope(ctx, req.UserId),
+		req.SpiceGradeId,
+		uint(req.Skip),
+		uint(req.Take),
+		req.Sort,
+		req.DateFrom,
+		req.DateTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	protoLots := make([]*pb.BuyLot, 0, len(lots))
+	for _, lot := range lots {
+		protoLots = append(protoLots, buyLotToProto(lot))
+	}
+	return &pb.ListOpenLotsResponse{Lots: protoLots}, nil
+}
+
+func (server *GrpcServer) GetLotHistory(ctx context.Context, req *pb.GetLotHistoryRequest) (*pb.GetLotHistoryResponse, error) {
+	history, err := server.marketService.GetLotHistory(
+		ctx,
+		lotReadScope(ctx, req.UserId),
+		req.LotId,
+		uint(req.Skip),
+		uint(req.Take),
+		req.DateFrom,
+		req.DateTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetLotHistoryResponse{
+		Lot:         buyLotToProto(history.Lot),
+		Allocations: allocationsToProto(history.Allocations),
+	}, nil
+}
+
+func (server *GrpcServer) GetSellAllocations(ctx context.Context, req *pb.GetSellAllocationsRequest) (*pb.GetSellAllocationsResponse, error) {
+	allocs, err := server.marketService.GetSellAllocations(ctx, AllocationFilter{
+		UserID:            lotReadScope(ctx, req.UserId),
+		SellTransactionID: req.SellTransactionId,
+		SpiceGradeID:      req.SpiceGradeId,
+		DateFrom:          req.DateFrom,
+		DateTo:            req.DateTo,
+		IncludeReversed:   req.IncludeReversed,
+		Skip:              uint(req.Skip),
+		Take:              uint(req.Take),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetSellAllocationsResponse{Allocations: allocationsToProto(allocs)}, nil
+}
+
+func (server *GrpcServer) ListTransactionFees(ctx context.Context, req *pb.ListTransactionFeesRequest) (*pb.ListTransactionFeesResponse, error) {
+	fees, err := server.marketService.ListTransactionFees(ctx, lotReadScope(ctx, req.UserId), req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*pb.TransactionFee, 0, len(fees))
+	for _, f := range fees {
+		out = append(out, &pb.TransactionFee{
+			Id:            f.ID,
+			TransactionId: f.TransactionID,
+			FeeScheduleId: f.FeeScheduleID,
+			Code:          f.Code,
+			Kind:          f.Kind,
+			Basis:         f.Basis,
+			Rate:          f.Rate.String(),
+			Amount:        f.Amount.String(),
+			Currency:      f.Currency,
+		})
+	}
+	return &pb.ListTransactionFeesResponse{Fees: out}, nil
+}
+
+func (server *GrpcServer) GetRealizedGainsReport(ctx context.Context, req *pb.GetRealizedGainsReportRequest) (*pb.GetRealizedGainsReportResponse, error) {
+	report, err := server.marketService.GetRealizedGainsReport(ctx, GainsFilter{
+		UserID:        lotReadScope(ctx, req.UserId),
+		SpiceGradeID:  req.SpiceGradeId,
+		FinancialYear: req.FinancialYear,
+		DateFrom:      req.DateFrom,
+		DateTo:        req.DateTo,
+		LongTermDays:  int(req.LongTermDays),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gains := make([]*pb.RealizedGain, len(report.Gains))
+	for i, g := range report.Gains {
+		gains[i] = &pb.RealizedGain{
+			Kind:              g.Kind,
+			MatchId:           g.MatchID,
+			SellTransactionId: g.SellTransactionID,
+			BuyTransactionId:  g.BuyTransactionID,
+			LotId:             g.LotID,
+			SpiceGradeId:      g.SpiceGradeID,
+			ProductName:       g.ProductName,
+			GradeName:         g.GradeName,
+			Currency:          g.Currency,
+			CostBasisMethod:   g.CostBasisMethod,
+			Quantity:          g.Quantity.String(),
+			BuyPrice:          g.BuyPrice.String(),
+			SellPrice:         g.SellPrice.String(),
+			AcquiredDate:      g.AcquiredDate.Format("2006-01-02"),
+			DisposedDate:      g.DisposedDate.Format("2006-01-02"),
+			RealizedDate:      g.RealizedDate.Format("2006-01-02"),
+			HoldingDays:       uint32(g.HoldingDays),
+			HoldingClass:      g.HoldingClass,
+			Cost:              g.Cost.String(),
+			Proceeds:          g.Proceeds.String(),
+			Fees:              g.Fees.String(),
+			Gain:              g.Gain.String(),
+		}
+	}
+
+	return &pb.GetRealizedGainsReportResponse{
+		UserId:         report.UserID,
+		DateFrom:       report.DateFrom.Format("2006-01-02"),
+		DateTo:         report.DateTo.Format("2006-01-02"),
+		LongTermDays:   uint32(report.LongTermDays),
+		Gains:          gains,
+		Grades:         gainsSubtotalsToProto(report.Grades),
+		HoldingClasses: gainsSubtotalsToProto(report.HoldingClasses),
+		Totals:         gainsSubtotalsToProto(report.Totals),
+	}, nil
+}
+
+func (server *GrpcServer) GetLotAgeing(ctx context.Context, req *pb.GetLotAgeingRequest) (*pb.GetLotAgeingResponse, error) {
+	asOf := time.Now()
+	if req.AsOf != "" {
+		parsed, err := time.Parse("2006-01-02", req.AsOf)
+		if err != nil {
+			return nil, fmt.Errorf("invalid as_of %q: use YYYY-MM-DD", req.AsOf)
+		}
+		asOf = parsed
+	}
+
+	report, err := server.marketService.GetLotAgeing(ctx, lotReadScope(ctx, req.UserId), asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	grades := make([]*pb.GradeAgeing, 0, len(report.Grades))
+	for _, g := range report.Grades {
+		grade := &pb.GradeAgeing{
+			SpiceGradeId:   g.SpiceGradeID,
+			ProductName:    g.ProductName,
+			GradeName:      g.GradeName,
+			ShelfLifeDays:  uint32(g.ShelfLifeDays),
+			Buckets:        ageingBucketsToProto(g.Buckets),
+			NearExpiryQty:  g.NearExpiryQty.String(),
+			NearExpiryLots: uint32(g.NearExpiryLots),
+		}
+		if !g.NextExpiry.IsZero() {
+			grade.NextExpiryDate = g.NextExpiry.Format("2006-01-02")
+		}
+		grades = append(grades, grade)
+	}
+	return &pb.GetLotAgeingResponse{
+		AsOf:    report.AsOf.Format("2006-01-02"),
+		Buckets: ageingBucketsToProto(report.Buckets),
+		Grades:  grades,
+	}, nil
+}
+
+func ageingBucketsToProto(buckets []AgeingBucket) []*pb.AgeingBucket {
+	out := make([]*pb.AgeingBucket, len(buckets))
+	for i, b := range buckets {
+		out[i] = &pb.AgeingBucket{
+			Label:    b.Label,
+			Quantity: b.Quantity.String(),
+			Cost:     b.Cost.String(),
+			Lots:     uint32(b.Lots),
+		}
+	}
+	return out
+}
+
+// lotReadScope returns the account a lot query reads. Admins read the requested
+// account, or every account when none is named; everyone else reads their own.
+func lotReadScope(ctx context.Context, requested string) string {
+	if isAdmin, ok := ctx.Value(util.IsAdminKey).(bool); ok && isAdmin {
+		return requested
+	}
+	if id, ok := ctx.Value(util.AccountIDKey).(string); ok && id != "" {
+		return id
+	}
+	return requested
+}
+
 func (server *GrpcServer) GetHoldings(ctx context.Context, req *pb.GetHoldingsRequest) (*pb.GetHoldingsResponse, error) {
 	userID := req.GetUserId()
 	if userID == "" {
@@ -308,7 +848,7 @@ func (server *GrpcServer) GetHoldings(ctx context.Context, req *pb.GetHoldingsRe
 		}
 	}
 
-	rows, err := server.marketService.GetEnrichedHoldings(ctx, userID)
+	rows, reporting, err := server.marketService.GetEnrichedHoldings(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -319,14 +859,24 @@ func (server *GrpcServer) GetHoldings(ctx context.Context, req *pb.GetHoldingsRe
 			SpiceGradeId: row.SpiceGradeID,
 			ProductName:  row.ProductName,
 			GradeName:    row.GradeName,
-			Quantity:     row.TotalQty,
-			TotalCost:    row.TotalCost,
-			RealizedPnl:  row.RealizedPnL,
-			TodayPrice:   row.TodayPrice,
+			Quantity:     row.TotalQty.String(),
+			TotalCost:    row.TotalCost.String(),
+			RealizedPnl:  row.RealizedPnL.String(),
+			TodayPrice:   row.TodayPrice.String(),
+			PriceDate:    valuationDate(row.Valuation),
+			PriceSource:  row.Valuation.Source,
+			Stale:        row.Valuation.Stale,
+			Currency:     row.Currency,
+			Unit:         row.Unit.Unit,
+			KgPerUnit:    row.Unit.KgPerUnit.String(),
+			UnitQty:      row.UnitQty.String(),
+		}
+		if !row.FxRate.IsZero() {
+			holdings[i].FxRate = row.FxRate.String()
 		}
 	}
 
-	return &pb.GetHoldingsResponse{Holdings: holdings}, nil
+	return &pb.GetHoldingsResponse{Holdings: holdings, ReportingCurrency: reporting}, nil
 }
 
 func (server *GrpcServer) GetRealizedPnLHistory(ctx context.Context, req *pb.GetRealizedPnLHistoryRequest) (*pb.GetRealizedPnLHistoryResponse, error) {
@@ -348,7 +898,7 @@ func (server *GrpcServer) GetRealizedPnLHistory(ctx context.Context, req *pb.Get
 	for i, row := range rows {
 		out[i] = &pb.RealizedPnLRow{
 			Date:         row.Date.Format("2006-01-02"),
-			Amount:       row.DailyRealizedPnL,
+			Amount:       row.DailyRealizedPnL.String(),
 			SpiceGradeId: row.SpiceGradeID,
 			ProductName:  row.ProductName,
 			GradeName:    row.GradeName,
@@ -378,7 +928,7 @@ func (server *GrpcServer) GetTradeActivity(ctx context.Context, req *pb.GetTrade
 		out[i] = &pb.TradeActivityRow{
 			Date:         row.Date.Format("2006-01-02"),
 			Type:         row.Type,
-			Quantity:     row.Quantity,
+			Quantity:     row.Quantity.String(),
 			Count:        uint32(row.Count),
 			SpiceGradeId: row.SpiceGradeID,
 			ProductName:  row.ProductName,
@@ -406,8 +956,8 @@ func (server *GrpcServer) GetTradeStats(ctx context.Context, req *pb.GetTradeSta
 
 	return &pb.GetTradeStatsResponse{
 		TradesInPeriod:     uint32(stats.TradesInPeriod),
-		BuyVolumeInPeriod:  stats.BuyVolumeInPeriod,
-		SellVolumeInPeriod: stats.SellVolumeInPeriod,
+		BuyVolumeInPeriod:  stats.BuyVolumeInPeriod.String(),
+		SellVolumeInPeriod: stats.SellVolumeInPeriod.String(),
 	}, nil
 }
 
@@ -432,10 +982,209 @@ func (server *GrpcServer) GetPriceSnapshots(ctx context.Context, req *pb.GetPric
 			SpiceGradeId:  snap.SpiceGradeID,
 			ProductName:   snap.ProductName,
 			GradeName:     snap.GradeName,
-			TodayPrice:    snap.TodayPrice,
-			PreviousPrice: snap.PreviousPrice,
+			TodayPrice:    snap.TodayPrice.String(),
+			PreviousPrice: snap.PreviousPrice.String(),
+			PriceDate:     valuationDate(snap.Valuation),
+			PriceSource:   snap.Valuation.Source,
+			Stale:         snap.Valuation.Stale,
+			Currency:      snap.Currency,
 		}
 	}
 
 	return &pb.GetPriceSnapshotsResponse{Snapshots: out}, nil
 }
+
+// positionViewToProto leaves the reporting amounts out when no FX rate converted them.
+func positionViewToProto(pos *PositionView) *pb.PositionView {
+	out := &pb.PositionView{
+		UserId:            pos.UserID,
+		SpiceGradeId:      pos.SpiceGradeID,
+		TotalQty:          pos.TotalQty.String(),
+		TotalCost:         pos.TotalCost.String(),
+		AvgCost:           pos.AvgCost.String(),
+		TodayPrice:        pos.TodayPrice.String(),
+		RealizedPnl:       pos.RealizedPnL.String(),
+		UnrealizedPnl:     pos.UnrealizedPnL.String(),
+		UpdatedAt:         pos.UpdatedAt.Format("2006-01-02 15:04:05"),
+		PriceDate:         valuationDate(pos.Valuation),
+		PriceSource:       pos.Valuation.Source,
+		Stale:             pos.Valuation.Stale,
+		Currency:          pos.Currency,
+		ReportingCurrency: pos.Reporting.Currency,
+		Unit:              pos.Unit.Unit,
+		KgPerUnit:         pos.Unit.KgPerUnit.String(),
+		UnitQty:           pos.UnitQty.String(),
+	}
+	if !pos.Reporting.FxRate.IsZero() {
+		out.FxRate = pos.Reporting.FxRate.String()
+		out.ReportingTotalCost = pos.Reporting.TotalCost.String()
+		out.ReportingRealizedPnl = pos.Reporting.RealizedPnL.String()
+		out.ReportingUnrealizedPnl = pos.Reporting.UnrealizedPnL.String()
+	}
+	return out
+}
+
+// valuationDate formats the date of a valuation price; empty when no price qualified.
+func valuationDate(v Valuation) string {
+	if v.Date.IsZero() {
+		return ""
+	}
+	return v.Date.Format("2006-01-02")
+}
+
+func transactionToProto(txn *Transaction) *pb.Transaction {
+	return &pb.Transaction{
+		Id:                    txn.ID,
+		UserId:                txn.UserID,
+		SpiceGradeId:          txn.SpiceGradeID,
+		Type:                  txn.Type,
+		Quantity:              txn.Quantity.String(),
+		Price:                 txn.Price.String(),
+		TradeDate:             txn.TradeDate.Format("2006-01-02"),
+		CreatedAt:             txn.CreatedAt.Format("2006-01-02 15:04:05"),
+		CostBasisMethod:       txn.CostBasisMethod,
+		Status:                txn.Status,
+		ReversesTransactionId: txn.ReversesTransactionID,
+		AmendsTransactionId:   txn.AmendsTransactionID,
+		Note:                  txn.Note,
+		IdempotencyKey:        txn.IdempotencyKey,
+		Currency:              txn.Currency,
+		FeeTotal:              txn.FeeTotal.String(),
+	}
+}
+
+func gainsSubtotalsToProto(subtotals []*GainsSubtotal) []*pb.GainsSubtotal {
+	out := make([]*pb.GainsSubtotal, len(subtotals))
+	for i, s := range subtotals {
+		out[i] = &pb.GainsSubtotal{
+			SpiceGradeId: s.SpiceGradeID,
+			ProductName:  s.ProductName,
+			GradeName:    s.GradeName,
+			HoldingClass: s.HoldingClass,
+			Currency:     s.Currency,
+			Quantity:     s.Quantity.String(),
+			Cost:         s.Cost.String(),
+			Proceeds:     s.Proceeds.String(),
+			Fees:         s.Fees.String(),
+			Gain:         s.Gain.String(),
+			Lines:        uint32(s.Lines),
+		}
+	}
+	return out
+}
+
+func buyLotToProto(lot *BuyLot) *pb.BuyLot {
+	return &pb.BuyLot{
+		Id:            lot.ID,
+		TransactionId: lot.TransactionID,
+		UserId:        lot.UserID,
+		SpiceGradeId:  lot.SpiceGradeID,
+		OriginalQty:   lot.OriginalQty.String(),
+		RemainingQty:  lot.RemainingQty.String(),
+		Price:         lot.Price.String(),
+		TradeDate:     lot.TradeDate.Format("2006-01-02"),
+		CreatedAt:     lot.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func allocationsToProto(allocs []*AllocationDetail) []*pb.SellAllocation {
+	protoAllocs := make([]*pb.SellAllocation, 0, len(allocs))
+	for _, a := range allocs {
+		protoAllocs = append(protoAllocs, allocationToProto(a))
+	}
+	return protoAllocs
+}
+
+func allocationToProto(a *AllocationDetail) *pb.SellAllocation {
+	return &pb.SellAllocation{
+		Id:                      a.ID,
+		SellTransactionId:       a.SellTransactionID,
+		BuyLotId:                a.BuyLotID,
+		UserId:                  a.UserID,
+		SpiceGradeId:            a.SpiceGradeID,
+		Quantity:                a.Quantity.String(),
+		BuyPrice:                a.BuyPrice.String(),
+		SellPrice:               a.SellPrice.String(),
+		RealizedPnl:             a.RealizedPnL.String(),
+		CostBasisMethod:         a.CostBasisMethod,
+		SellTradeDate:           a.SellTradeDate.Format("2006-01-02"),
+		ReversedByTransactionId: a.ReversedByTransactionID,
+		CreatedAt:               a.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func costBasisPreferenceToProto(pref *CostBasisPreference) *pb.CostBasisPreference {
+	out := &pb.CostBasisPreference{
+		UserId:       pref.UserID,
+		SpiceGradeId: pref.SpiceGradeID,
+		Method:       pref.Method,
+		Source:       pref.Source,
+	}
+	if !pref.UpdatedAt.IsZero() {
+		out.UpdatedAt = pref.UpdatedAt.Format("2006-01-02 15:04:05")
+	}
+	return out
+}
+
+func tradingPermissionsToProto(perms *TradingPermissions) *pb.TradingPermissions {
+	out := &pb.TradingPermissions{
+		UserId:            perms.UserID,
+		AllowShortSelling: perms.AllowShortSelling,
+		UpdatedBy:         perms.UpdatedBy,
+	}
+	if !perms.UpdatedAt.IsZero() {
+		out.UpdatedAt = perms.UpdatedAt.Format("2006-01-02 15:04:05")
+	}
+	return out
+}
+
+func orderToProto(o *Order) *pb.Order {
+	out := &pb.Order{
+		Id:           o.ID,
+		UserId:       o.UserID,
+		SpiceGradeId: o.SpiceGradeID,
+		Side:         o.Side,
+		Price:        o.Price.String(),
+		Quantity:     o.Quantity.String(),
+		RemainingQty: o.RemainingQty.String(),
+		Status:       o.Status,
+		Currency:     o.Currency,
+	}
+	if !o.CreatedAt.IsZero() {
+		out.CreatedAt = o.CreatedAt.Format("2006-01-02 15:04:05")
+	}
+	if !o.UpdatedAt.IsZero() {
+		out.UpdatedAt = o.UpdatedAt.Format("2006-01-02 15:04:05")
+	}
+	return out
+}
+
+func orderFillsToProto(fills []*OrderFill) []*pb.OrderFill {
+	protoFills := make([]*pb.OrderFill, 0, len(fills))
+	for _, f := range fills {
+		protoFills = append(protoFills, &pb.OrderFill{
+			Id:                f.ID,
+			SpiceGradeId:      f.SpiceGradeID,
+			BuyOrderId:        f.BuyOrderID,
+			SellOrderId:       f.SellOrderID,
+			BuyTransactionId:  f.BuyTransactionID,
+			SellTransactionId: f.SellTransactionID,
+			Quantity:          f.Quantity.String(),
+			Price:             f.Price.String(),
+			CreatedAt:         f.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return protoFills
+}
+
+func orderBookLevelsToProto(levels []OrderBookLevel) []*pb.OrderBookLevel {
+	protoLevels := make([]*pb.OrderBookLevel, 0, len(levels))
+	for _, l := range levels {
+		protoLevels = append(protoLevels, &pb.OrderBookLevel{
+			Price:    l.Price.String(),
+			Quantity: l.Quantity.String(),
+			Orders:   uint32(l.Orders),
+		})
+	}
+	return protoLevels
+}
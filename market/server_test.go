@@ -0,0 +1,33 @@
+package market
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+)
+
+func TestPreferenceOwner(t *testing.T) {
+	tests := []struct {
+		name      string
+		caller    string
+		admin     bool
+		requested string
+		want      string
+	}{
+		{name: "merchant defaults to their own account", caller: "acc-1", want: "acc-1"},
+		{name: "merchant naming themselves", caller: "acc-1", requested: "acc-1", want: "acc-1"},
+		{name: "merchant naming another account is held to their own", caller: "acc-1", requested: "acc-2", want: "acc-1"},
+		{name: "admin acts on the account they name", caller: "admin-1", admin: true, requested: "acc-2", want: "acc-2"},
+		{name: "admin defaults to their own account", caller: "admin-1", admin: true, want: "admin-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), util.AccountIDKey, tt.caller)
+			ctx = context.WithValue(ctx, util.IsAdminKey, tt.admin)
+			if got := preferenceOwner(ctx, tt.requested); got != tt.want {
+				t.Errorf("preferenceOwner(%q) = %q, want %q", tt.requested, got, tt.want)
+			}
+		})
+	}
+}
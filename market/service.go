@@ -4,26 +4,54 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Asif-Faizal/SpiceLedger-Backend/internal/platform"
 	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
 	"github.com/segmentio/ksuid"
+	"github.com/shopspring/decimal"
 )
 
 type Service interface {
-	Buy(ctx context.Context, userID string, spiceGradeID string, quantity float64, price float64, tradeDate time.Time) (*Transaction, error)
-	Sell(ctx context.Context, userID string, spiceGradeID string, quantity float64, price float64, tradeDate time.Time) (*Transaction, error)
+	Buy(ctx context.Context, userID string, spiceGradeID string, quantity decimal.Decimal, price decimal.Decimal, unit string, tradeDate time.Time, idempotencyKey string) (*Transaction, error)
+	Sell(ctx context.Context, userID string, spiceGradeID string, quantity decimal.Decimal, price decimal.Decimal, unit string, tradeDate time.Time, idempotencyKey string, opts SellOptions) (*Transaction, error)
+	CancelTransaction(ctx context.Context, userID string, transactionID string, reason string, reallocate bool) (*CancelResult, error)
+	AmendTransaction(ctx context.Context, userID string, transactionID string, amend Amendment) (*AmendResult, error)
+	ReconcileLedger(ctx context.Context, userID string, spiceGradeID string, rebuild bool) (*ReconciliationReport, error)
+	SetCostBasisMethod(ctx context.Context, userID string, spiceGradeID string, method string) (*CostBasisPreference, error)
+	GetCostBasisMethod(ctx context.Context, userID string, spiceGradeID string) (*CostBasisPreference, error)
+	PlaceOrder(ctx context.Context, userID string, spiceGradeID string, side string, quantity decimal.Decimal, price decimal.Decimal) (*OrderResult, error)
+	AmendOrder(ctx context.Context, userID string, orderID string, quantity decimal.Decimal, price decimal.Decimal) (*OrderResult, error)
+	CancelOrder(ctx context.Context, userID string, orderID string) (*Order, error)
+	GetOrder(ctx context.Context, userID string, orderID string) (*OrderResult, error)
+	ListOrders(ctx context.Context, filter OrderFilter) ([]*Order, error)
+	GetOrderBook(ctx context.Context, userID string, spiceGradeID string, currency string, depth uint) (*OrderBook, error)
+	SetTradingPermissions(ctx context.Context, userID string, allowShortSelling bool, updatedBy string) (*TradingPermissions, error)
+	GetTradingPermissions(ctx context.Context, userID string) (*TradingPermissions, error)
+	SubscribeTrades(userID string, spiceGradeID string, epoch string, afterSequence uint64) (*platform.Subscription, error)
 	GetGradePosition(ctx context.Context, userID string, spiceGradeID string) (*PositionView, error)
 	GetPositions(ctx context.Context, userID string) ([]*PositionView, error)
 	ListGradeTransactions(ctx context.Context, userID, spiceGradeID string, skip, take uint, sort, dateFrom, dateTo string) ([]*Transaction, error)
 	ListTransactions(ctx context.Context, userID string, skip, take uint, spiceGradeID string, spiceGradeIDs []string, sort, dateFrom, dateTo string) ([]*Transaction, error)
 	ListAllTransactions(ctx context.Context, skip, take uint, spiceGradeID string, spiceGradeIDs []string, sort, dateFrom, dateTo string) ([]*Transaction, error)
-	GetMarketMetrics(ctx context.Context) (uint32, float64, []struct {
+	ListOpenLots(ctx context.Context, userID, spiceGradeID string, skip, take uint, sort, dateFrom, dateTo string) ([]*BuyLot, error)
+	GetLotHistory(ctx context.Context, userID, lotID string, skip, take uint, dateFrom, dateTo string) (*LotHistory, error)
+	GetSellAllocations(ctx context.Context, filter AllocationFilter) ([]*AllocationDetail, error)
+	ListTransactionFees(ctx context.Context, userID, transactionID string) ([]*TransactionFee, error)
+	GetRealizedGainsReport(ctx context.Context, filter GainsFilter) (*RealizedGainsReport, error)
+	ExportLedger(ctx context.Context, filter ExportFilter, fn func(*LedgerExportRow) error) error
+	GetLotAgeing(ctx context.Context, userID string, asOf time.Time) (*LotAgeingReport, error)
+	GetMarketMetrics(ctx context.Context) (uint32, decimal.Decimal, []struct {
 		ProductName string
 		GradeName   string
-		Volume      float64
+		Volume      decimal.Decimal
 	}, error)
-	GetEnrichedHoldings(ctx context.Context, userID string) ([]EnrichedHoldingRow, error)
+	GetTradedValue(ctx context.Context, userID string) (decimal.Decimal, string, []string, error)
+	GetEnrichedHoldings(ctx context.Context, userID string) ([]EnrichedHoldingRow, string, error)
 	GetDailyRealizedPnLByUser(ctx context.Context, userID string, days uint) ([]DailyRealizedPnLRow, error)
 	GetDailyActivityByUser(ctx context.Context, userID string, days uint) ([]DailyActivityRow, error)
 	GetPeriodTradeStats(ctx context.Context, userID string, days uint) (*PeriodTradeStats, error)
@@ -32,16 +60,39 @@ type Service interface {
 
 type MarketService struct {
 	repository Repository
+	events     *platform.EventBus
+	valuation  ValuationPolicy
 	logger     util.Logger
 }
 
-func NewMarketService(repository Repository, logger util.Logger) Service {
+// NewMarketService wires the service; committed trades are published to events when it is set.
+// valuation picks the daily price positions are valued at.
+func NewMarketService(repository Repository, events *platform.EventBus, valuation ValuationPolicy, logger util.Logger) Service {
 	return &MarketService{
 		repository: repository,
+		events:     events,
+		valuation:  valuation,
 		logger:     logger,
 	}
 }
 
+// NewValuationPolicy validates a policy read from configuration; an empty mode is LAST_AVAILABLE.
+func NewValuationPolicy(mode string, maxAgeDays int) (ValuationPolicy, error) {
+	mode = strings.ToUpper(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = ValuationLastAvailable
+	}
+	switch mode {
+	case ValuationToday, ValuationLastAvailable, ValuationPreviousClose:
+	default:
+		return ValuationPolicy{}, fmt.Errorf("unknown price valuation %q: use TODAY, LAST_AVAILABLE or PREVIOUS_CLOSE", mode)
+	}
+	if maxAgeDays < 0 {
+		return ValuationPolicy{}, errors.New("price max age must not be negative")
+	}
+	return ValuationPolicy{Mode: mode, MaxAgeDays: maxAgeDays}, nil
+}
+
 func (s *MarketService) ListAllTransactions(ctx context.Context, skip, take uint, spiceGradeID string, spiceGradeIDs []string, sort, dateFrom, dateTo string) ([]*Transaction, error) {
 	if take == 0 || take > 100 {
 		take = 100
@@ -49,316 +100,2626 @@ func (s *MarketService) ListAllTransactions(ctx context.Context, skip, take uint
 	return s.repository.ListAllTransactions(ctx, skip, take, spiceGradeID, spiceGradeIDs, sort, dateFrom, dateTo)
 }
 
-func (s *MarketService) GetMarketMetrics(ctx context.Context) (uint32, float64, []struct {
+func (s *MarketService) GetMarketMetrics(ctx context.Context) (uint32, decimal.Decimal, []struct {
 	ProductName string
 	GradeName   string
-	Volume      float64
+	Volume      decimal.Decimal
 }, error) {
 	return s.repository.GetMarketMetrics(ctx)
 }
 
-// Buy records a BUY transaction and creates a new buy_lot.
-func (s *MarketService) Buy(ctx context.Context, userID string, spiceGradeID string, quantity float64, price float64, tradeDate time.Time) (*Transaction, error) {
+// GetTradedValue totals the value of active trades converted into the account's reporting
+// currency at today's rates. Currencies without a rate are left out and returned.
+func (s *MarketService) GetTradedValue(ctx context.Context, userID string) (decimal.Decimal, string, []string, error) {
+	_, reporting, err := s.repository.GetAccountCurrencies(ctx, userID)
+	if err != nil {
+		return decimal.Zero, "", nil, err
+	}
+	values, err := s.repository.GetTradedValueByCurrency(ctx)
+	if err != nil {
+		return decimal.Zero, "", nil, err
+	}
+
+	total := decimal.Zero
+	var unconverted []string
+	now := time.Now()
+	for currency, value := range values {
+		rate, err := s.repository.GetFxRate(ctx, currency, reporting, now)
+		if errors.Is(err, ErrNoFxRate) {
+			unconverted = append(unconverted, currency)
+			continue
+		}
+		if err != nil {
+			return decimal.Zero, "", nil, err
+		}
+		total = total.Add(value.Mul(rate))
+	}
+	sort.Strings(unconverted)
+	return util.RoundMoney(total, reporting), reporting, unconverted, nil
+}
+
+// Buy records a BUY transaction and creates a new buy_lot. A quantity and price entered in
+// another unit than kilograms are converted first (see toBaseUnit).
+// A repeated idempotencyKey returns the trade booked on the first call.
+func (s *MarketService) Buy(ctx context.Context, userID string, spiceGradeID string, quantity decimal.Decimal, price decimal.Decimal, unit string, tradeDate time.Time, idempotencyKey string) (*Transaction, error) {
+	if err := validateTrade(userID, spiceGradeID, quantity, price); err != nil {
+		return nil, err
+	}
+	kgPerUnit, err := s.tradeUnitKg(ctx, spiceGradeID, unit)
+	if err != nil {
+		return nil, err
+	}
+	if quantity, price, err = toBaseUnit(quantity, price, kgPerUnit); err != nil {
+		return nil, err
+	}
+	if tradeDate.IsZero() {
+		tradeDate = time.Now()
+	}
+	idempotencyKey, err = normalizeIdempotencyKey(idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if prior, err := s.findReplay(ctx, userID, idempotencyKey, "BUY", spiceGradeID, quantity, price); err != nil || prior != nil {
+		return prior, err
+	}
+
+	txCtx, tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	t := &Transaction{
+		ID:             ksuid.New().String(),
+		UserID:         userID,
+		SpiceGradeID:   spiceGradeID,
+		Type:           "BUY",
+		Quantity:       quantity,
+		Price:          price,
+		IdempotencyKey: idempotencyKey,
+		TradeDate:      tradeDate,
+	}
+	if err = s.bookBuy(txCtx, t); err != nil {
+		if err == ErrDuplicateIdempotencyKey {
+			// A concurrent retry committed first; answer with its trade.
+			return s.findReplay(ctx, userID, idempotencyKey, "BUY", spiceGradeID, quantity, price)
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.publishTrades(t)
+	return t, nil
+}
+
+// bookBuy writes a BUY inside the caller's DB transaction: the transaction row, covers of
+// any open short lots, an inventory lot for the uncovered rest and the position increase.
+func (s *MarketService) bookBuy(txCtx context.Context, t *Transaction) error {
+	if err := s.stampCurrency(txCtx, t); err != nil {
+		return err
+	}
+	if err := s.chargeFees(txCtx, t); err != nil {
+		return err
+	}
+
+	// 1. Record the immutable transaction with its fee lines.
+	if _, err := s.repository.InsertTransaction(txCtx, t); err != nil {
+		return err
+	}
+
+	// 2. Cover open short lots first; only what is left over becomes inventory.
+	covered, released, coverPnL, err := s.coverShortLots(txCtx, t)
+	if err != nil {
+		return err
+	}
+	uncovered := t.Quantity.Sub(covered)
+
+	// 3. Create the inventory lot (original_qty = remaining_qty = uncovered purchase qty),
+	// priced at the landed cost so later sells carry the buy fees in their cost basis.
+	if uncovered.IsPositive() {
+		lot := &BuyLot{
+			ID:            ksuid.New().String(),
+			TransactionID: t.ID,
+			UserID:        t.UserID,
+			SpiceGradeID:  t.SpiceGradeID,
+			OriginalQty:   uncovered,
+			RemainingQty:  uncovered,
+			Price:         landedPrice(t),
+			TradeDate:     t.TradeDate,
+		}
+		if _, err := s.repository.InsertBuyLot(txCtx, lot); err != nil {
+			return err
+		}
+	}
+
+	// 4. Upsert position — increase qty; covered units release their short proceeds.
+	pos := &Position{
+		UserID:       t.UserID,
+		SpiceGradeID: t.SpiceGradeID,
+		Currency:     t.Currency,
+		TotalQty:     t.Quantity,
+		TotalCost:    released.Add(lotCost(uncovered, landedPrice(t), t.Currency)),
+		RealizedPnL:  coverPnL,
+	}
+	return s.repository.UpsertPosition(txCtx, pos)
+}
+
+// coverShortLots closes open short lots of the BUY's grade oldest first, recording one cover
+// per lot at the BUY's landed cost. It returns the quantity covered, the short proceeds released
+// from the position's cost and the P&L realized.
+func (s *MarketService) coverShortLots(txCtx context.Context, t *Transaction) (covered, released, pnl decimal.Decimal, err error) {
+	shorts, err := s.repository.GetOpenShortLots(txCtx, t.UserID, t.SpiceGradeID)
+	if err != nil {
+		return covered, released, pnl, err
+	}
+
+	for _, short := range shorts {
+		need := t.Quantity.Sub(covered)
+		if !need.IsPositive() {
+			break
+		}
+		qty := decimal.Min(need, short.RemainingQty)
+		if err = s.repository.DeductShortLotQty(txCtx, short.ID, qty); err != nil {
+			return covered, released, pnl, err
+		}
+
+		proceeds := lotCost(qty, short.Price, t.Currency)
+		coverPnL := proceeds.Sub(lotCost(qty, landedPrice(t), t.Currency))
+		cover := &ShortCover{
+			ID:               ksuid.New().String(),
+			BuyTransactionID: t.ID,
+			ShortLotID:       short.ID,
+			Quantity:         qty,
+			ShortPrice:       short.Price,
+			CoverPrice:       landedPrice(t),
+			RealizedPnL:      coverPnL,
+		}
+		if err = s.repository.InsertShortCover(txCtx, cover); err != nil {
+			return covered, released, pnl, err
+		}
+
+		covered = covered.Add(qty)
+		released = released.Add(proceeds)
+		pnl = pnl.Add(coverPnL)
+	}
+	return covered, released, pnl, nil
+}
+
+// Sell matches the requested quantity against open buy_lots using the cost-basis
+// method chosen on the request, or the stored grade/account preference (FIFO by default).
+// All lot deductions, sell_allocations, and position updates are atomic.
+// A repeated idempotencyKey returns the trade booked on the first call.
+func (s *MarketService) Sell(ctx context.Context, userID string, spiceGradeID string, quantity decimal.Decimal, price decimal.Decimal, unit string, tradeDate time.Time, idempotencyKey string, opts SellOptions) (*Transaction, error) {
+	if err := validateTrade(userID, spiceGradeID, quantity, price); err != nil {
+		return nil, err
+	}
+	kgPerUnit, err := s.tradeUnitKg(ctx, spiceGradeID, unit)
+	if err != nil {
+		return nil, err
+	}
+	if quantity, price, err = toBaseUnit(quantity, price, kgPerUnit); err != nil {
+		return nil, err
+	}
+	if opts.Lots, err = lotsToBaseUnit(opts.Lots, kgPerUnit); err != nil {
+		return nil, err
+	}
+	if tradeDate.IsZero() {
+		tradeDate = time.Now()
+	}
+	idempotencyKey, err = normalizeIdempotencyKey(idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if prior, err := s.findReplay(ctx, userID, idempotencyKey, "SELL", spiceGradeID, quantity, price); err != nil || prior != nil {
+		return prior, err
+	}
+
+	method, err := s.resolveSellMethod(ctx, userID, spiceGradeID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	txCtx, tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	t := &Transaction{
+		ID:              ksuid.New().String(),
+		UserID:          userID,
+		SpiceGradeID:    spiceGradeID,
+		Type:            "SELL",
+		Quantity:        quantity,
+		Price:           price,
+		CostBasisMethod: method,
+		IdempotencyKey:  idempotencyKey,
+		TradeDate:       tradeDate,
+	}
+	if err = s.stampCurrency(txCtx, t); err != nil {
+		return nil, err
+	}
+	if err = s.chargeFees(txCtx, t); err != nil {
+		return nil, err
+	}
+	if _, err = s.repository.InsertTransaction(txCtx, t); err != nil {
+		if err == ErrDuplicateIdempotencyKey {
+			return s.findReplay(ctx, userID, idempotencyKey, "SELL", spiceGradeID, quantity, price)
+		}
+		return nil, err
+	}
+	if err = s.allocateSell(txCtx, t, method, opts.Lots); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.publishTrades(t)
+	return t, nil
+}
+
+// stampCurrency gives a new trade its account's trading currency unless it already carries
+// one (an amendment or an order fill), and refuses it when the grade's open position is
+// held in another currency.
+func (s *MarketService) stampCurrency(txCtx context.Context, t *Transaction) error {
+	if t.Currency == "" {
+		currency, _, err := s.repository.GetAccountCurrencies(txCtx, t.UserID)
+		if err != nil {
+			return err
+		}
+		t.Currency = currency
+	}
+	return s.checkPositionCurrency(txCtx, t.UserID, t.SpiceGradeID, t.Currency)
+}
+
+// checkPositionCurrency returns ErrCurrencyMismatch when the account holds an open position
+// in the grade in a currency other than currency.
+func (s *MarketService) checkPositionCurrency(ctx context.Context, userID, spiceGradeID, currency string) error {
+	pos, err := s.repository.GetGradePosition(ctx, userID, spiceGradeID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !pos.TotalQty.IsZero() && pos.Currency != currency {
+		return ErrCurrencyMismatch
+	}
+	return nil
+}
+
+// maxIdempotencyKeyLen matches transactions.idempotency_key.
+const maxIdempotencyKeyLen = 128
+
+func normalizeIdempotencyKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if len(key) > maxIdempotencyKeyLen {
+		return "", fmt.Errorf("idempotency key must be at most %d characters", maxIdempotencyKeyLen)
+	}
+	return key, nil
+}
+
+// findReplay returns the trade already booked under idempotencyKey, or nil if the key is new.
+// Reusing a key for a different trade is an error rather than a silent replay.
+func (s *MarketService) findReplay(ctx context.Context, userID, idempotencyKey, tradeType, spiceGradeID string, quantity, price decimal.Decimal) (*Transaction, error) {
+	if idempotencyKey == "" {
+		return nil, nil
+	}
+	prior, err := s.repository.GetTransactionByIdempotencyKey(ctx, userID, idempotencyKey)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if prior.Type != tradeType || prior.SpiceGradeID != spiceGradeID ||
+		!prior.Quantity.Equal(quantity) || !prior.Price.Equal(price) {
+		return nil, ErrIdempotencyKeyReused
+	}
+	s.logger.Service().Info().
+		Str("user_id", userID).
+		Str("transaction_id", prior.ID).
+		Msg("Replayed idempotent trade")
+	return prior, nil
+}
+
+// resolveSellMethod settles the cost-basis method for a sell and checks it against the lot selection.
+func (s *MarketService) resolveSellMethod(ctx context.Context, userID, spiceGradeID string, opts SellOptions) (string, error) {
+	method, err := s.resolveCostBasisMethod(ctx, userID, spiceGradeID, opts.CostBasisMethod)
+	if err != nil {
+		return "", err
+	}
+	if method == CostBasisSpecificLot && len(opts.Lots) == 0 {
+		return "", errors.New("lots are required for SPECIFIC_LOT sells")
+	}
+	if method != CostBasisSpecificLot && len(opts.Lots) > 0 {
+		return "", errors.New("lots can only be given with the SPECIFIC_LOT cost-basis method")
+	}
+	if opts.CostBasisMethod != "" {
+		pref, err := s.GetCostBasisMethod(ctx, userID, spiceGradeID)
+		if err != nil {
+			return "", err
+		}
+		if costBasisFamily(method) != costBasisFamily(pref.Method) {
+			open, err := s.hasOpenLots(ctx, userID, spiceGradeID)
+			if err != nil {
+				return "", err
+			}
+			if open {
+				return "", fmt.Errorf("a %s sell cannot draw on lots held under %s: weighted average and the lot methods cannot be mixed while the grade has open lots", method, pref.Method)
+			}
+		}
+	}
+	return method, nil
+}
+
+// costBasisFamily tells weighted average apart from the lot methods. A weighted-average sell
+// releases the average cost from the position while the lot methods release the drawn lots'
+// own cost, so mixing them on one holding would leave total_cost out of step with the open
+// lots. The family can only change while the grade has no open lots.
+func costBasisFamily(method string) string {
+	if method == CostBasisWeightedAverage {
+		return CostBasisWeightedAverage
+	}
+	return "LOT"
+}
+
+// hasOpenLots reports whether the account holds a long position in the grade.
+func (s *MarketService) hasOpenLots(ctx context.Context, userID, spiceGradeID string) (bool, error) {
+	pos, err := s.repository.GetGradePosition(ctx, userID, spiceGradeID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return pos.TotalQty.IsPositive(), nil
+}
+
+// allocateSell matches an already-recorded SELL against open lots inside the caller's
+// DB transaction: lot deductions, one allocation per lot, and the position decrease.
+// When the account may sell short, quantity beyond the open lots becomes a short lot.
+// The sell's fees are shared out by quantity and deducted from each allocation's realized
+// P&L, and from the short lot's proceeds.
+func (s *MarketService) allocateSell(txCtx context.Context, t *Transaction, method string, selections []LotSelection) error {
+	// 1. Lock open lots in method order (FOR UPDATE prevents concurrent oversell).
+	lots, err := s.repository.GetOpenBuyLots(txCtx, t.UserID, t.SpiceGradeID, method)
+	if err != nil {
+		return err
+	}
+
+	// Service-layer inventory check.
+	totalAvailable := decimal.Zero
+	for _, l := range lots {
+		totalAvailable = totalAvailable.Add(l.RemainingQty)
+	}
+	longQty, shortQty := t.Quantity, decimal.Zero
+	if totalAvailable.LessThan(t.Quantity) {
+		allowed, err := s.shortSellingAllowed(txCtx, t.UserID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.New("insufficient inventory: sell quantity exceeds available buy lots")
+		}
+		if method == CostBasisSpecificLot {
+			return errors.New("SPECIFIC_LOT sells cannot go short: the selected lots must cover the full quantity")
+		}
+		longQty, shortQty = totalAvailable, t.Quantity.Sub(totalAvailable)
+	}
+
+	var draws []lotDraw
+	if longQty.IsPositive() {
+		if draws, err = planLotDraws(lots, longQty, method, selections); err != nil {
+			return err
+		}
+	}
+
+	// Weighted average prices every unit sold at the position's current average cost.
+	var avgCost decimal.Decimal
+	var pos *Position
+	if method == CostBasisWeightedAverage && longQty.IsPositive() {
+		pos, err = s.repository.LockGradePosition(txCtx, t.UserID, t.SpiceGradeID)
+		if err != nil {
+			return err
+		}
+		if !pos.TotalQty.IsPositive() {
+			return errors.New("insufficient inventory: no open position for weighted-average sell")
+		}
+		avgCost = averageCost(pos.TotalCost, pos.TotalQty)
+	}
+
+	parts := make([]decimal.Decimal, 0, len(draws)+1)
+	for _, draw := range draws {
+		parts = append(parts, draw.qty)
+	}
+	if shortQty.IsPositive() {
+		parts = append(parts, shortQty)
+	}
+	fees := feeShares(t.FeeTotal, t.Quantity, t.Currency, parts)
+
+	// 2. Consume the planned lots, recording one allocation per lot.
+	totalRealizedPnL := decimal.Zero
+	totalCostConsumed := decimal.Zero
+	allocs := make([]*SellAllocation, 0, len(draws))
+
+	for i, draw := range draws {
+		if err = s.repository.DeductBuyLotQty(txCtx, draw.lot.ID, draw.qty); err != nil {
+			return err
+		}
+
+		unitCost := draw.lot.Price
+		if method == CostBasisWeightedAverage {
+			unitCost = avgCost
+		}
+		cost := lotCost(draw.qty, unitCost, t.Currency)
+		lotPnL := lotCost(draw.qty, t.Price, t.Currency).Sub(cost).Sub(fees[i])
+		alloc := &SellAllocation{
+			ID:                ksuid.New().String(),
+			SellTransactionID: t.ID,
+			BuyLotID:          draw.lot.ID,
+			Quantity:          draw.qty,
+			BuyPrice:          unitCost,
+			SellPrice:         t.Price,
+			RealizedPnL:       lotPnL,
+			CostBasisMethod:   method,
+		}
+		allocs = append(allocs, alloc)
+
+		totalRealizedPnL = totalRealizedPnL.Add(lotPnL)
+		totalCostConsumed = totalCostConsumed.Add(cost)
+	}
+
+	// Closing the whole position under weighted average releases the exact stored cost,
+	// so rounding of the average never leaves residue in total_cost. The difference is
+	// kept on the last allocation so a reversal or a replay can undo it.
+	if pos != nil && longQty.Equal(pos.TotalQty) {
+		last := allocs[len(allocs)-1]
+		last.CloseAdjustment = pos.TotalCost.Sub(totalCostConsumed)
+		last.RealizedPnL = last.RealizedPnL.Sub(last.CloseAdjustment)
+		totalRealizedPnL = totalRealizedPnL.Sub(last.CloseAdjustment)
+		totalCostConsumed = pos.TotalCost
+	}
+	for _, alloc := range allocs {
+		if err = s.repository.InsertSellAllocation(txCtx, alloc); err != nil {
+			return err
+		}
+	}
+
+	// 3. Sell the rest short at the sell price net of its fees; the proceeds are carried as
+	// negative cost.
+	shortProceeds := decimal.Zero
+	if shortQty.IsPositive() {
+		netPrice := util.RoundPrice(t.Price.Sub(fees[len(draws)].Div(shortQty)))
+		short := &ShortLot{
+			ID:            ksuid.New().String(),
+			TransactionID: t.ID,
+			UserID:        t.UserID,
+			SpiceGradeID:  t.SpiceGradeID,
+			OriginalQty:   shortQty,
+			RemainingQty:  shortQty,
+			Price:         netPrice,
+			TradeDate:     t.TradeDate,
+		}
+		if err = s.repository.InsertShortLot(txCtx, short); err != nil {
+			return err
+		}
+		shortProceeds = lotCost(shortQty, netPrice, t.Currency)
+	}
+
+	// 4. Update position: decrease qty + cost, accumulate realized P&L.
+	update := &Position{
+		UserID:       t.UserID,
+		SpiceGradeID: t.SpiceGradeID,
+		Currency:     t.Currency,
+		TotalQty:     t.Quantity.Neg(),
+		TotalCost:    totalCostConsumed.Add(shortProceeds).Neg(),
+		RealizedPnL:  totalRealizedPnL,
+	}
+	return s.repository.UpsertPosition(txCtx, update)
+}
+
+// CancelTransaction reverses a BUY or SELL with a compensating REVERSAL row; nothing is deleted.
+// Cancelling a SELL restores its lots and reverses its allocations. Cancelling a BUY whose lot
+// later sells have consumed (or whose cost later weighted-average sells were priced with) fails
+// unless reallocate is set, in which case those sells are re-matched against the remaining lots
+// in the same DB transaction.
+// An empty userID (admin callers) skips the ownership check.
+func (s *MarketService) CancelTransaction(ctx context.Context, userID string, transactionID string, reason string, reallocate bool) (*CancelResult, error) {
+	if transactionID == "" {
+		return nil, errors.New("transaction_id is required")
+	}
+
+	txCtx, tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := s.cancelInTx(txCtx, userID, transactionID, reason, reallocate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.publishTrades(result.Original, result.Reversal)
+	return result, nil
+}
+
+// AmendTransaction replaces a trade with corrected values: the original is cancelled and a
+// new trade of the same type is booked, linked through amends_transaction_id. A corrected BUY
+// is booked before the cancellation so re-matched sells can draw on the new lot.
+func (s *MarketService) AmendTransaction(ctx context.Context, userID string, transactionID string, amend Amendment) (*AmendResult, error) {
+	if transactionID == "" {
+		return nil, errors.New("transaction_id is required")
+	}
+	if amend.Quantity.IsNegative() || amend.Price.IsNegative() {
+		return nil, errors.New("quantity and price must not be negative")
+	}
+
+	txCtx, tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	original, err := s.lockActiveTrade(txCtx, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := &Transaction{
+		ID:                  ksuid.New().String(),
+		UserID:              original.UserID,
+		SpiceGradeID:        original.SpiceGradeID,
+		Type:                original.Type,
+		Quantity:            original.Quantity,
+		Price:               original.Price,
+		Currency:            original.Currency,
+		AmendsTransactionID: original.ID,
+		Note:                amend.Reason,
+		TradeDate:           original.TradeDate,
+	}
+	if amend.Quantity.IsPositive() {
+		replacement.Quantity = amend.Quantity
+	}
+	if amend.Price.IsPositive() {
+		replacement.Price = amend.Price
+	}
+	if !amend.TradeDate.IsZero() {
+		replacement.TradeDate = amend.TradeDate
+	}
+	if err = validateTrade(replacement.UserID, replacement.SpiceGradeID, replacement.Quantity, replacement.Price); err != nil {
+		return nil, err
+	}
+
+	result := &AmendResult{Transaction: replacement}
+	var covers []*ShortCover
+	if original.Type == "BUY" {
+		if covers, err = s.repository.ListActiveCoversByBuy(txCtx, original.ID); err != nil {
+			return nil, err
+		}
+	}
+	if original.Type == "BUY" && len(covers) > 0 {
+		// The original covered short lots: cancel first so the replacement covers them again.
+		if result.Cancel, err = s.cancelInTx(txCtx, userID, original.ID, amend.Reason, amend.Reallocate); err != nil {
+			return nil, err
+		}
+		if err = s.bookBuy(txCtx, replacement); err != nil {
+			return nil, err
+		}
+	} else if original.Type == "BUY" {
+		if err = s.bookBuy(txCtx, replacement); err != nil {
+			return nil, err
+		}
+		if result.Cancel, err = s.cancelInTx(txCtx, userID, original.ID, amend.Reason, amend.Reallocate); err != nil {
+			return nil, err
+		}
+	} else {
+		if result.Cancel, err = s.cancelInTx(txCtx, userID, original.ID, amend.Reason, amend.Reallocate); err != nil {
+			return nil, err
+		}
+		opts := amend.Sell
+		if opts.CostBasisMethod == "" && len(opts.Lots) == 0 && original.CostBasisMethod != CostBasisSpecificLot {
+			opts.CostBasisMethod = original.CostBasisMethod
+		}
+		var method string
+		if method, err = s.resolveSellMethod(txCtx, original.UserID, original.SpiceGradeID, opts); err != nil {
+			return nil, err
+		}
+		replacement.CostBasisMethod = method
+		if err = s.stampCurrency(txCtx, replacement); err != nil {
+			return nil, err
+		}
+		if err = s.chargeFees(txCtx, replacement); err != nil {
+			return nil, err
+		}
+		if _, err = s.repository.InsertTransaction(txCtx, replacement); err != nil {
+			return nil, err
+		}
+		if err = s.allocateSell(txCtx, replacement, method, opts.Lots); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.publishTrades(result.Cancel.Original, result.Cancel.Reversal, replacement)
+	return result, nil
+}
+
+// lockActiveTrade loads and locks an ACTIVE BUY or SELL, enforcing ownership when userID is set.
+func (s *MarketService) lockActiveTrade(txCtx context.Context, userID, transactionID string) (*Transaction, error) {
+	original, err := s.repository.LockTransaction(txCtx, transactionID)
+	if err == sql.ErrNoRows || (err == nil && userID != "" && original.UserID != userID) {
+		return nil, errors.New("transaction not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if original.Status != TransactionActive || (original.Type != "BUY" && original.Type != "SELL") {
+		return nil, ErrTransactionNotActive
+	}
+	return original, nil
+}
+
+// cancelInTx does the work of CancelTransaction inside the caller's DB transaction.
+func (s *MarketService) cancelInTx(txCtx context.Context, userID, transactionID, reason string, reallocate bool) (*CancelResult, error) {
+	original, err := s.lockActiveTrade(txCtx, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	reversal := &Transaction{
+		ID:                    ksuid.New().String(),
+		UserID:                original.UserID,
+		SpiceGradeID:          original.SpiceGradeID,
+		Type:                  "REVERSAL",
+		Quantity:              original.Quantity,
+		Price:                 original.Price,
+		Currency:              original.Currency,
+		ReversesTransactionID: original.ID,
+		Note:                  reason,
+		TradeDate:             time.Now(),
+	}
+	if _, err = s.repository.InsertTransaction(txCtx, reversal); err != nil {
+		return nil, err
+	}
+	if err = s.repository.SetTransactionStatus(txCtx, original.ID, TransactionCancelled); err != nil {
+		return nil, err
+	}
+	original.Status = TransactionCancelled
+	result := &CancelResult{Original: original, Reversal: reversal}
+
+	if original.Type == "SELL" {
+		if err = s.reverseSellAllocations(txCtx, original, reversal.ID); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	// Short lots this BUY covered are reopened; a fully covering BUY has no lot of its own.
+	reopened, err := s.reverseShortCovers(txCtx, original, reversal.ID)
+	if err != nil {
+		return nil, err
+	}
+	lot, err := s.repository.LockBuyLotByTransaction(txCtx, original.ID)
+	if err == sql.ErrNoRows {
+		if err = s.checkReopenedShorts(txCtx, original, reopened); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Later sells drew on this lot, or (weighted average) were priced with its cost:
+	// undo them first, then re-match them once the lot is gone.
+	allocs, err := s.repository.ListActiveAllocationsByLot(txCtx, lot.ID)
+	if err != nil {
+		return nil, err
+	}
+	averaged, err := s.repository.ListActiveSellsSince(txCtx, original.UserID, original.SpiceGradeID, CostBasisWeightedAverage, original.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if (len(allocs) > 0 || len(averaged) > 0) && !reallocate {
+		return nil, ErrLotConsumed
+	}
+
+	var affected []*Transaction
+	seen := make(map[string]bool)
+	for _, a := range allocs {
+		if seen[a.SellTransactionID] {
+			continue
+		}
+		seen[a.SellTransactionID] = true
+		sell, err := s.repository.LockTransaction(txCtx, a.SellTransactionID)
+		if err != nil {
+			return nil, err
+		}
+		affected = append(affected, sell)
+	}
+	for _, sell := range averaged {
+		if !seen[sell.ID] {
+			seen[sell.ID] = true
+			affected = append(affected, sell)
+		}
+	}
+	for _, sell := range affected {
+		if err = s.reverseSellAllocations(txCtx, sell, reversal.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.repository.CloseBuyLot(txCtx, lot.ID, reversal.ID); err != nil {
+		return nil, err
+	}
+	if err = s.repository.UpsertPosition(txCtx, &Position{
+		UserID:       original.UserID,
+		SpiceGradeID: original.SpiceGradeID,
+		TotalQty:     lot.OriginalQty.Neg(),
+		TotalCost:    lotCost(lot.OriginalQty, lot.Price, original.Currency).Neg(),
+	}); err != nil {
+		return nil, err
+	}
+
+	// Re-match in trade order. Specific lots may no longer exist, so those sells fall back to FIFO.
+	sort.SliceStable(affected, func(i, j int) bool {
+		return affected[i].TradeDate.Before(affected[j].TradeDate)
+	})
+	for _, sell := range affected {
+		method := sell.CostBasisMethod
+		if method == "" || method == CostBasisSpecificLot {
+			method = CostBasisFIFO
+		}
+		if err = s.allocateSell(txCtx, sell, method, nil); err != nil {
+			return nil, fmt.Errorf("cannot re-allocate sell %s: %w", sell.ID, err)
+		}
+		result.ReallocatedSellIDs = append(result.ReallocatedSellIDs, sell.ID)
+	}
+	if err = s.checkReopenedShorts(txCtx, original, reopened); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// reverseShortCovers reopens every short lot a BUY covered and takes the covers' effect off
+// the position. It reports whether anything was reopened.
+func (s *MarketService) reverseShortCovers(txCtx context.Context, buy *Transaction, reversalID string) (bool, error) {
+	covers, err := s.repository.ListActiveCoversByBuy(txCtx, buy.ID)
+	if err != nil || len(covers) == 0 {
+		return false, err
+	}
+
+	qty, released, pnl := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, c := range covers {
+		if err := s.repository.RestoreShortLotQty(txCtx, c.ShortLotID, c.Quantity); err != nil {
+			return false, err
+		}
+		if err := s.repository.ReverseShortCover(txCtx, c.ID, reversalID); err != nil {
+			return false, err
+		}
+		qty = qty.Add(c.Quantity)
+		released = released.Add(lotCost(c.Quantity, c.ShortPrice, buy.Currency))
+		pnl = pnl.Add(c.RealizedPnL)
+	}
+
+	return true, s.repository.UpsertPosition(txCtx, &Position{
+		UserID:       buy.UserID,
+		SpiceGradeID: buy.SpiceGradeID,
+		TotalQty:     qty.Neg(),
+		TotalCost:    released.Neg(),
+		RealizedPnL:  pnl.Neg(),
+	})
+}
+
+// checkReopenedShorts refuses to leave reopened short lots beside open buy lots of the same
+// grade: a later BUY would have covered them, so those buys must be cancelled first.
+func (s *MarketService) checkReopenedShorts(txCtx context.Context, buy *Transaction, reopened bool) error {
+	if !reopened {
+		return nil
+	}
+	lots, err := s.repository.GetOpenBuyLots(txCtx, buy.UserID, buy.SpiceGradeID, CostBasisFIFO)
+	if err != nil {
+		return err
+	}
+	if len(lots) > 0 {
+		return ErrCoverSuperseded
+	}
+	return nil
+}
+
+// reverseSellAllocations puts every standing allocation of a SELL back into its lot, closes
+// any short lot it opened and undoes the sell's effect on the position.
+func (s *MarketService) reverseSellAllocations(txCtx context.Context, sell *Transaction, reversalID string) error {
+	allocs, err := s.repository.ListActiveAllocationsBySell(txCtx, sell.ID)
+	if err != nil {
+		return err
+	}
+
+	qty, cost, pnl := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, a := range allocs {
+		if err := s.repository.RestoreBuyLotQty(txCtx, a.BuyLotID, a.Quantity); err != nil {
+			return err
+		}
+		if err := s.repository.ReverseSellAllocation(txCtx, a.ID, reversalID); err != nil {
+			return err
+		}
+		qty = qty.Add(a.Quantity)
+		cost = cost.Add(a.cost(sell.Currency))
+		pnl = pnl.Add(a.RealizedPnL)
+	}
+
+	shorts, err := s.repository.ListActiveShortLotsBySell(txCtx, sell.ID)
+	if err != nil {
+		return err
+	}
+	for _, short := range shorts {
+		if err := s.repository.CloseShortLot(txCtx, short.ID, reversalID); err != nil {
+			return err
+		}
+		qty = qty.Add(short.OriginalQty)
+		cost = cost.Add(lotCost(short.OriginalQty, short.Price, sell.Currency))
+	}
+
+	return s.repository.UpsertPosition(txCtx, &Position{
+		UserID:       sell.UserID,
+		SpiceGradeID: sell.SpiceGradeID,
+		TotalQty:     qty,
+		TotalCost:    cost,
+		RealizedPnL:  pnl.Neg(),
+	})
+}
+
+// ReconcileLedger replays transactions, buy_lots, sell_allocations, short_lots and short_covers
+// for every user and grade in scope (empty values mean all) and reports where positions or lot
+// remainders disagree with the replay. With rebuild set, the drifting rows are overwritten with the replayed values inside
+// one DB transaction that holds the ledger rows locked.
+func (s *MarketService) ReconcileLedger(ctx context.Context, userID string, spiceGradeID string, rebuild bool) (*ReconciliationReport, error) {
+	if !rebuild {
+		return s.reconcile(ctx, userID, spiceGradeID, false)
+	}
+
+	txCtx, tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	report, err := s.reconcile(txCtx, userID, spiceGradeID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// positionKey identifies one user's position in one grade.
+type positionKey struct {
+	userID       string
+	spiceGradeID string
+}
+
+// ledgerEvent is one active BUY lot, the standing short covers of one BUY, or a SELL in replay order.
+type ledgerEvent struct {
+	at     time.Time
+	id     string
+	lot    *LedgerLot
+	covers []*LedgerCover
+	sell   *Transaction
+}
+
+// currency is the currency of the trade behind the event.
+func (ev ledgerEvent) currency() string {
+	switch {
+	case ev.lot != nil:
+		return ev.lot.Currency
+	case len(ev.covers) > 0:
+		return ev.covers[0].Currency
+	case ev.sell != nil:
+		return ev.sell.Currency
+	}
+	return ""
+}
+
+func (s *MarketService) reconcile(ctx context.Context, userID, spiceGradeID string, rebuild bool) (*ReconciliationReport, error) {
+	lots, err := s.repository.ListLedgerLots(ctx, userID, spiceGradeID)
+	if err != nil {
+		return nil, err
+	}
+	sells, err := s.repository.ListLedgerSells(ctx, userID, spiceGradeID)
+	if err != nil {
+		return nil, err
+	}
+	allocs, err := s.repository.ListLedgerAllocations(ctx, userID, spiceGradeID)
+	if err != nil {
+		return nil, err
+	}
+	shortLots, err := s.repository.ListLedgerShortLots(ctx, userID, spiceGradeID)
+	if err != nil {
+		return nil, err
+	}
+	covers, err := s.repository.ListLedgerCovers(ctx, userID, spiceGradeID)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.repository.ListStoredPositions(ctx, userID, spiceGradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	allocsBySell := make(map[string][]*SellAllocation)
+	allocatedByLot := make(map[string]decimal.Decimal)
+	for _, a := range allocs {
+		allocsBySell[a.SellTransactionID] = append(allocsBySell[a.SellTransactionID], a)
+		allocatedByLot[a.BuyLotID] = allocatedByLot[a.BuyLotID].Add(a.Quantity)
+	}
+
+	coversByBuy := make(map[string][]*LedgerCover)
+	coveredByShort := make(map[string]decimal.Decimal)
+	for _, c := range covers {
+		coversByBuy[c.BuyTransactionID] = append(coversByBuy[c.BuyTransactionID], c)
+		coveredByShort[c.ShortLotID] = coveredByShort[c.ShortLotID].Add(c.Quantity)
+	}
+
+	report := &ReconciliationReport{LotsChecked: len(lots) + len(shortLots)}
+
+	// 1. Lot remainders: original quantity less active allocations; lots of cancelled BUYs stay at zero.
+	events := make(map[positionKey][]ledgerEvent)
+	for _, l := range lots {
+		expected := decimal.Zero
+		if l.BuyStatus == TransactionActive {
+			expected = l.OriginalQty.Sub(allocatedByLot[l.ID])
+			key := positionKey{l.UserID, l.SpiceGradeID}
+			events[key] = append(events[key], ledgerEvent{at: l.BookedAt, id: l.TransactionID, lot: l})
+		}
+		if drifted(l.RemainingQty, expected) {
+			report.Lots = append(report.Lots, LotDrift{
+				LotID:             l.ID,
+				UserID:            l.UserID,
+				SpiceGradeID:      l.SpiceGradeID,
+				StoredRemaining:   l.RemainingQty,
+				ExpectedRemaining: expected,
+			})
+		}
+	}
+	for _, t := range sells {
+		key := positionKey{t.UserID, t.SpiceGradeID}
+		events[key] = append(events[key], ledgerEvent{at: t.CreatedAt, id: t.ID, sell: t})
+	}
+
+	// Short lots: original quantity less active covers; lots closed by a REVERSAL stay at zero.
+	shortsBySell := make(map[string][]*LedgerShortLot)
+	for _, l := range shortLots {
+		expected := decimal.Zero
+		if l.ReversedByTransactionID == "" {
+			expected = l.OriginalQty.Sub(coveredByShort[l.ID])
+			shortsBySell[l.TransactionID] = append(shortsBySell[l.TransactionID], l)
+		}
+		if drifted(l.RemainingQty, expected) {
+			report.Lots = append(report.Lots, LotDrift{
+				LotID:             l.ID,
+				Short:             true,
+				UserID:            l.UserID,
+				SpiceGradeID:      l.SpiceGradeID,
+				StoredRemaining:   l.RemainingQty,
+				ExpectedRemaining: expected,
+			})
+		}
+	}
+	for buyID, cs := range coversByBuy {
+		key := positionKey{cs[0].UserID, cs[0].SpiceGradeID}
+		events[key] = append(events[key], ledgerEvent{at: cs[0].BookedAt, id: buyID, covers: cs})
+	}
+
+	// 2. Positions: replay each user/grade in booking order and compare with the stored row.
+	storedByKey := make(map[positionKey]*Position, len(stored))
+	keys := make([]positionKey, 0, len(stored)+len(events))
+	for _, p := range stored {
+		key := positionKey{p.UserID, p.SpiceGradeID}
+		storedByKey[key] = p
+		keys = append(keys, key)
+	}
+	for key := range events {
+		if _, ok := storedByKey[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].userID != keys[j].userID {
+			return keys[i].userID < keys[j].userID
+		}
+		return keys[i].spiceGradeID < keys[j].spiceGradeID
+	})
+	report.PositionsChecked = len(keys)
+
+	for _, key := range keys {
+		expected := replayPosition(key, events[key], allocsBySell, shortsBySell)
+		current, ok := storedByKey[key]
+		if !ok {
+			if expected.TotalQty.IsZero() && expected.TotalCost.IsZero() && expected.RealizedPnL.IsZero() {
+				continue
+			}
+			current = &Position{UserID: key.userID, SpiceGradeID: key.spiceGradeID}
+		}
+		if ok && current.Currency == expected.Currency &&
+			!drifted(current.TotalQty, expected.TotalQty) &&
+			!drifted(current.TotalCost, expected.TotalCost) &&
+			!drifted(current.RealizedPnL, expected.RealizedPnL) {
+			continue
+		}
+		report.Positions = append(report.Positions, PositionDrift{
+			UserID:       key.userID,
+			SpiceGradeID: key.spiceGradeID,
+			Stored:       *current,
+			Expected:     expected,
+			Missing:      !ok,
+		})
+	}
+
+	if !rebuild {
+		return report, nil
+	}
+
+	// 3. Rebuild: overwrite only the rows that drifted; the rest already match the replay.
+	for _, d := range report.Lots {
+		set := s.repository.SetBuyLotRemaining
+		if d.Short {
+			set = s.repository.SetShortLotRemaining
+		}
+		if err := set(ctx, d.LotID, d.ExpectedRemaining); err != nil {
+			return nil, err
+		}
+	}
+	for i := range report.Positions {
+		if err := s.repository.ReplacePosition(ctx, &report.Positions[i].Expected); err != nil {
+			return nil, err
+		}
+	}
+	report.Rebuilt = true
+	return report, nil
+}
+
+// replayPosition rebuilds one position from its active lots, covers and sells the way Buy and
+// Sell book them, including the exact-cost release when a weighted-average sell closes the
+// long position and the short proceeds carried for any quantity sold short. The currency
+// follows UpsertPosition: a trade sets it when the position is flat.
+func replayPosition(key positionKey, events []ledgerEvent, allocsBySell map[string][]*SellAllocation, shortsBySell map[string][]*LedgerShortLot) Position {
+	// Buys sort before sells booked in the same second: a sell can only draw on lots that exist.
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		if (events[i].sell == nil) != (events[j].sell == nil) {
+			return events[i].sell == nil
+		}
+		return events[i].id < events[j].id
+	})
+
+	pos := Position{UserID: key.userID, SpiceGradeID: key.spiceGradeID}
+	for _, ev := range events {
+		if currency := ev.currency(); currency != "" && (pos.Currency == "" || pos.TotalQty.IsZero()) {
+			pos.Currency = currency
+		}
+		if ev.lot != nil {
+			pos.TotalQty = pos.TotalQty.Add(ev.lot.OriginalQty)
+			pos.TotalCost = pos.TotalCost.Add(lotCost(ev.lot.OriginalQty, ev.lot.Price, ev.lot.Currency))
+			continue
+		}
+		if ev.covers != nil {
+			for _, c := range ev.covers {
+				pos.TotalQty = pos.TotalQty.Add(c.Quantity)
+				pos.TotalCost = pos.TotalCost.Add(lotCost(c.Quantity, c.ShortPrice, c.Currency))
+				pos.RealizedPnL = pos.RealizedPnL.Add(c.RealizedPnL)
+			}
+			continue
+		}
+
+		cost, pnl := decimal.Zero, decimal.Zero
+		adjusted := false
+		for _, a := range allocsBySell[ev.sell.ID] {
+			cost = cost.Add(a.cost(ev.sell.Currency))
+			pnl = pnl.Add(a.RealizedPnL)
+			adjusted = adjusted || !a.CloseAdjustment.IsZero()
+		}
+		shortQty, proceeds := decimal.Zero, decimal.Zero
+		for _, l := range shortsBySell[ev.sell.ID] {
+			shortQty = shortQty.Add(l.OriginalQty)
+			proceeds = proceeds.Add(lotCost(l.OriginalQty, l.Price, ev.sell.Currency))
+		}
+		// Sells booked before close_adjustment was recorded carry none; their closing
+		// difference is worked out again from the replayed position.
+		longQty := ev.sell.Quantity.Sub(shortQty)
+		if !adjusted && ev.sell.CostBasisMethod == CostBasisWeightedAverage && longQty.IsPositive() && longQty.Equal(pos.TotalQty) {
+			pnl = pnl.Add(cost.Sub(pos.TotalCost))
+			cost = pos.TotalCost
+		}
+		pos.TotalQty = pos.TotalQty.Sub(ev.sell.Quantity)
+		pos.TotalCost = pos.TotalCost.Sub(cost).Sub(proceeds)
+		pos.RealizedPnL = pos.RealizedPnL.Add(pnl)
+	}
+	if pos.Currency == "" {
+		pos.Currency = util.DefaultCurrency
+	}
+	return pos
+}
+
+// drifted compares exactly: every ledger amount is booked pre-rounded, so the replay
+// reproduces stored values to the last digit.
+func drifted(stored, expected decimal.Decimal) bool {
+	return !stored.Equal(expected)
+}
+
+// validateTrade checks the fields every BUY and SELL needs. Quantities and prices must
+// fit the 4 dp ledger scale; they are rejected rather than silently rounded.
+func validateTrade(userID, spiceGradeID string, quantity, price decimal.Decimal) error {
+	if userID == "" {
+		return errors.New("user_id is required")
+	}
+	if spiceGradeID == "" {
+		return errors.New("spice_grade_id is required")
+	}
+	if !quantity.IsPositive() {
+		return errors.New("quantity must be greater than zero")
+	}
+	if !price.IsPositive() {
+		return errors.New("price must be greater than zero")
+	}
+	if !util.RoundQuantity(quantity).Equal(quantity) {
+		return fmt.Errorf("quantity supports at most %d decimal places", util.QuantityScale)
+	}
+	if !util.RoundPrice(price).Equal(price) {
+		return fmt.Errorf("price supports at most %d decimal places", util.PriceScale)
+	}
+	return nil
+}
+
+// tradeUnitKg returns the kilograms in one unit a trade was entered in; empty is kilograms.
+// Every grade trades in the fixed-size units, but only a grade sold in bags takes BAG.
+func (s *MarketService) tradeUnitKg(ctx context.Context, spiceGradeID, unit string) (decimal.Decimal, error) {
+	unit, err := util.ParseUnit(unit)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if unit == "" {
+		unit = util.BaseUnit
+	}
+	if kg, ok := util.UnitKg(unit); ok {
+		return kg, nil
+	}
+	gradeUnit, err := s.repository.GetGradeUnit(ctx, spiceGradeID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if gradeUnit.Unit != unit || !gradeUnit.KgPerUnit.IsPositive() {
+		return decimal.Zero, ErrUnitNotTraded
+	}
+	return gradeUnit.KgPerUnit, nil
+}
+
+// toBaseUnit restates a quantity and unit price entered per unit as kilograms and a price per
+// kilogram. The quantity must convert exactly; the price is rounded to the ledger scale, so the
+// booked value can differ from quantity × price as entered by that rounding.
+func toBaseUnit(quantity, price, kgPerUnit decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
+	if kgPerUnit.Equal(decimal.NewFromInt(1)) {
+		return quantity, price, nil
+	}
+	kg := quantity.Mul(kgPerUnit)
+	if !util.RoundQuantity(kg).Equal(kg) {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("quantity in kilograms supports at most %d decimal places", util.QuantityScale)
+	}
+	perKg := util.RoundPrice(price.Div(kgPerUnit))
+	if !perKg.IsPositive() {
+		return decimal.Zero, decimal.Zero, errors.New("price per kilogram rounds to zero")
+	}
+	return kg, perKg, nil
+}
+
+// lotsToBaseUnit restates SPECIFIC_LOT selections entered in the trade's unit in kilograms.
+func lotsToBaseUnit(lots []LotSelection, kgPerUnit decimal.Decimal) ([]LotSelection, error) {
+	if kgPerUnit.Equal(decimal.NewFromInt(1)) {
+		return lots, nil
+	}
+	converted := make([]LotSelection, len(lots))
+	for i, lot := range lots {
+		kg := lot.Quantity.Mul(kgPerUnit)
+		if !util.RoundQuantity(kg).Equal(kg) {
+			return nil, fmt.Errorf("lot %s quantity in kilograms supports at most %d decimal places", lot.LotID, util.QuantityScale)
+		}
+		converted[i] = LotSelection{LotID: lot.LotID, Quantity: kg}
+	}
+	return converted, nil
+}
+
+// unitQuantity restates a quantity in kilograms in a grade's unit.
+func unitQuantity(kg decimal.Decimal, unit GradeUnit) decimal.Decimal {
+	if !unit.KgPerUnit.IsPositive() {
+		return kg
+	}
+	return kg.DivRound(unit.KgPerUnit, util.QuantityScale)
+}
+
+// chargeFees prices the fee and tax schedules in force on a new BUY or SELL into fee lines in
+// its currency, and sets their total. Of the schedules sharing a code, a grade schedule beats a
+// category one and a BUY or SELL schedule beats a BOTH one; then the newest wins.
+func (s *MarketService) chargeFees(ctx context.Context, t *Transaction) error {
+	schedules, err := s.repository.ListFeeSchedules(ctx, t.SpiceGradeID, t.Type, t.TradeDate)
+	if err != nil {
+		return err
+	}
+	rank := func(fs *FeeSchedule) int {
+		r := 0
+		if fs.GradeID != "" {
+			r += 2
+		}
+		if fs.Side != "BOTH" {
+			r++
+		}
+		return r
+	}
+	// Schedules arrive newest first, so a later one only wins on a higher rank.
+	chosen := make(map[string]*FeeSchedule)
+	var codes []string
+	for _, fs := range schedules {
+		current, ok := chosen[fs.Code]
+		if !ok {
+			codes = append(codes, fs.Code)
+		}
+		if !ok || rank(fs) > rank(current) {
+			chosen[fs.Code] = fs
+		}
+	}
+	sort.Strings(codes)
+
+	t.Fees, t.FeeTotal = nil, decimal.Zero
+	for _, code := range codes {
+		fs := chosen[code]
+		if !fs.Rate.IsPositive() {
+			continue
+		}
+		var amount decimal.Decimal
+		if fs.Basis == FeeBasisPercent {
+			amount = t.Quantity.Mul(t.Price).Mul(fs.Rate).Div(decimal.NewFromInt(100))
+		} else {
+			rate, err := s.repository.GetFxRate(ctx, fs.Currency, t.Currency, t.TradeDate)
+			if err != nil {
+				return fmt.Errorf("fee %s: %w", fs.Code, err)
+			}
+			amount = fs.Rate.Mul(rate)
+			if fs.Basis == FeeBasisPerKg {
+				amount = amount.Mul(t.Quantity)
+			}
+		}
+		amount = util.RoundMoney(amount, t.Currency)
+		if !amount.IsPositive() {
+			continue
+		}
+		t.Fees = append(t.Fees, &TransactionFee{
+			ID:            ksuid.New().String(),
+			FeeScheduleID: fs.ID,
+			Code:          fs.Code,
+			Kind:          fs.Kind,
+			Basis:         fs.Basis,
+			Rate:          fs.Rate,
+			Amount:        amount,
+			Currency:      t.Currency,
+		})
+		t.FeeTotal = t.FeeTotal.Add(amount)
+	}
+	return nil
+}
+
+// landedPrice is a BUY's cost per kilogram with its fees capitalised, rounded to the ledger
+// scale.
+func landedPrice(t *Transaction) decimal.Decimal {
+	if !t.FeeTotal.IsPositive() {
+		return t.Price
+	}
+	return util.RoundPrice(t.Price.Add(t.FeeTotal.Div(t.Quantity)))
+}
+
+// feeShares splits a trade's fees over parts of its quantity in proportion, rounded to the
+// currency's minor unit. The last part takes the remainder so the shares add up exactly.
+func feeShares(total, quantity decimal.Decimal, currency string, parts []decimal.Decimal) []decimal.Decimal {
+	shares := make([]decimal.Decimal, len(parts))
+	left := total
+	for i, part := range parts {
+		if i == len(parts)-1 {
+			shares[i] = left
+			break
+		}
+		shares[i] = util.RoundMoney(total.Mul(part).Div(quantity), currency)
+		left = left.Sub(shares[i])
+	}
+	return shares
+}
+
+// lotCost is the money amount of quantity × unit price, rounded to the trade currency's
+// minor unit. Every cost and proceeds figure is booked through it, so position totals are
+// exact sums of the per-lot amounts.
+func lotCost(quantity, price decimal.Decimal, currency string) decimal.Decimal {
+	return util.RoundMoney(quantity.Mul(price), currency)
+}
+
+// averageCost is total cost per unit at the ledger price scale.
+func averageCost(totalCost, totalQty decimal.Decimal) decimal.Decimal {
+	if totalQty.IsZero() {
+		return decimal.Zero
+	}
+	return util.RoundPrice(totalCost.DivRound(totalQty, util.PriceScale+2))
+}
+
+// unrealizedPnL is market value at price less the position's stored cost. For a short
+// position both are negative: the proceeds held less what covering at price would cost.
+// Market value is rounded in the position's currency.
+func unrealizedPnL(pos *Position, price decimal.Decimal) decimal.Decimal {
+	if pos.TotalQty.IsZero() {
+		return decimal.Zero
+	}
+	return lotCost(pos.TotalQty, price, pos.Currency).Sub(pos.TotalCost)
+}
+
+// lotDraw is the quantity a sell takes from one open lot.
+type lotDraw struct {
+	lot *BuyLot
+	qty decimal.Decimal
+}
+
+// planLotDraws decides how much each lot contributes to a sell. Lots arrive already
+// ordered for FIFO/LIFO/weighted average; SPECIFIC_LOT follows the caller's selection order.
+func planLotDraws(lots []*BuyLot, quantity decimal.Decimal, method string, selections []LotSelection) ([]lotDraw, error) {
+	var draws []lotDraw
+	remaining := quantity
+
+	if method != CostBasisSpecificLot {
+		for _, lot := range lots {
+			if !remaining.IsPositive() {
+				break
+			}
+			consume := decimal.Min(lot.RemainingQty, remaining)
+			draws = append(draws, lotDraw{lot: lot, qty: consume})
+			remaining = remaining.Sub(consume)
+		}
+		return draws, nil
+	}
+
+	open := make(map[string]*BuyLot, len(lots))
+	for _, lot := range lots {
+		open[lot.ID] = lot
+	}
+	seen := make(map[string]bool, len(selections))
+	for _, sel := range selections {
+		lot, ok := open[sel.LotID]
+		if !ok {
+			return nil, fmt.Errorf("lot %s is not an open lot for this user and grade", sel.LotID)
+		}
+		if seen[sel.LotID] {
+			return nil, fmt.Errorf("lot %s is selected more than once", sel.LotID)
+		}
+		seen[sel.LotID] = true
+		if sel.Quantity.IsNegative() {
+			return nil, fmt.Errorf("quantity for lot %s must not be negative", sel.LotID)
+		}
+		if !util.RoundQuantity(sel.Quantity).Equal(sel.Quantity) {
+			return nil, fmt.Errorf("quantity for lot %s supports at most %d decimal places", sel.LotID, util.QuantityScale)
+		}
+
+		consume := sel.Quantity
+		if consume.IsZero() {
+			consume = decimal.Min(lot.RemainingQty, remaining)
+		}
+		if consume.GreaterThan(lot.RemainingQty) {
+			return nil, fmt.Errorf("lot %s has only %s remaining", sel.LotID, lot.RemainingQty.StringFixed(util.QuantityScale))
+		}
+		if consume.GreaterThan(remaining) {
+			return nil, errors.New("selected lot quantities exceed the sell quantity")
+		}
+		if consume.IsZero() {
+			continue
+		}
+		draws = append(draws, lotDraw{lot: lot, qty: consume})
+		remaining = remaining.Sub(consume)
+	}
+	if remaining.IsPositive() {
+		return nil, errors.New("selected lots do not cover the sell quantity")
+	}
+	return draws, nil
+}
+
+// normalizeCostBasisMethod validates a method name; empty input stays empty.
+func normalizeCostBasisMethod(method string) (string, error) {
+	method = strings.ToUpper(strings.TrimSpace(method))
+	switch method {
+	case "", CostBasisFIFO, CostBasisLIFO, CostBasisWeightedAverage, CostBasisSpecificLot:
+		return method, nil
+	}
+	return "", fmt.Errorf("unsupported cost_basis_method %q: use FIFO, LIFO, WEIGHTED_AVERAGE or SPECIFIC_LOT", method)
+}
+
+// resolveCostBasisMethod picks the method for a sell: the request wins, then the
+// grade override, then the account default, then FIFO.
+func (s *MarketService) resolveCostBasisMethod(ctx context.Context, userID, spiceGradeID, requested string) (string, error) {
+	method, err := normalizeCostBasisMethod(requested)
+	if err != nil {
+		return "", err
+	}
+	if method != "" {
+		return method, nil
+	}
+	pref, err := s.GetCostBasisMethod(ctx, userID, spiceGradeID)
+	if err != nil {
+		return "", err
+	}
+	return pref.Method, nil
+}
+
+// SetCostBasisMethod stores the default method for an account, or for one grade when
+// spiceGradeID is set. SPECIFIC_LOT cannot be a default because it needs lots per sell.
+// Switching between weighted average and the lot methods is refused for a grade with open
+// lots (see costBasisFamily).
+func (s *MarketService) SetCostBasisMethod(ctx context.Context, userID string, spiceGradeID string, method string) (*CostBasisPreference, error) {
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	method, err := normalizeCostBasisMethod(method)
+	if err != nil {
+		return nil, err
+	}
+	if method == "" {
+		return nil, errors.New("cost_basis_method is required")
+	}
+	if method == CostBasisSpecificLot {
+		return nil, errors.New("SPECIFIC_LOT must be chosen per sell and cannot be a default")
+	}
+	if err := s.checkCostBasisSwitch(ctx, userID, spiceGradeID, method); err != nil {
+		return nil, err
+	}
+
+	pref := &CostBasisPreference{
+		UserID:       userID,
+		SpiceGradeID: spiceGradeID,
+		Method:       method,
+	}
+	if err := s.repository.UpsertCostBasisPreference(ctx, pref); err != nil {
+		return nil, err
+	}
+	return s.GetCostBasisMethod(ctx, userID, spiceGradeID)
+}
+
+// checkCostBasisSwitch refuses a new default that changes the cost-basis family of a grade
+// with open lots. An account default applies to every grade without its own override.
+func (s *MarketService) checkCostBasisSwitch(ctx context.Context, userID, spiceGradeID, method string) error {
+	var grades []string
+	if spiceGradeID != "" {
+		grades = []string{spiceGradeID}
+	} else {
+		positions, err := s.repository.GetPositionsByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, pos := range positions {
+			grades = append(grades, pos.SpiceGradeID)
+		}
+	}
+	for _, gradeID := range grades {
+		current, err := s.GetCostBasisMethod(ctx, userID, gradeID)
+		if err != nil {
+			return err
+		}
+		if spiceGradeID == "" && current.Source == "GRADE" {
+			continue
+		}
+		if costBasisFamily(current.Method) == costBasisFamily(method) {
+			continue
+		}
+		open, err := s.hasOpenLots(ctx, userID, gradeID)
+		if err != nil {
+			return err
+		}
+		if open {
+			return fmt.Errorf("grade %s has open lots held under %s: switch to or from WEIGHTED_AVERAGE once they are sold", gradeID, current.Method)
+		}
+	}
+	return nil
+}
+
+// GetCostBasisMethod returns the method a sell would use when the request names none.
+func (s *MarketService) GetCostBasisMethod(ctx context.Context, userID string, spiceGradeID string) (*CostBasisPreference, error) {
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+
+	pref, err := s.repository.GetCostBasisPreference(ctx, userID, spiceGradeID)
+	if err == sql.ErrNoRows {
+		return &CostBasisPreference{
+			UserID:       userID,
+			SpiceGradeID: spiceGradeID,
+			Method:       CostBasisFIFO,
+			Source:       "DEFAULT",
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pref.Source = "ACCOUNT"
+	if pref.SpiceGradeID != "" {
+		pref.Source = "GRADE"
+	}
+	return pref, nil
+}
+
+// PlaceOrder books a limit order and matches it against the resting orders of the other
+// side in price-time priority. Each fill books a BUY for the buyer and a SELL for the seller
+// through the ledger path, at the resting order's price; whatever is left rests in the book.
+// The order, its fills and every ledger row they touch commit in one DB transaction.
+func (s *MarketService) PlaceOrder(ctx context.Context, userID string, spiceGradeID string, side string, quantity decimal.Decimal, price decimal.Decimal) (*OrderResult, error) {
+	if err := validateTrade(userID, spiceGradeID, quantity, price); err != nil {
+		return nil, err
+	}
+	side = strings.ToUpper(strings.TrimSpace(side))
+	if side != OrderSideBuy && side != OrderSideSell {
+		return nil, errors.New("side must be BUY or SELL")
+	}
+
+	txCtx, tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = s.repository.LockOrderBook(txCtx, spiceGradeID); err != nil {
+		return nil, err
+	}
+	if side == OrderSideSell {
+		if err = s.checkSellOrderCovered(txCtx, userID, spiceGradeID, "", quantity); err != nil {
+			return nil, err
+		}
+	}
+	currency, _, err := s.repository.GetAccountCurrencies(txCtx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkPositionCurrency(txCtx, userID, spiceGradeID, currency); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order := &Order{
+		ID:           ksuid.New().String(),
+		UserID:       userID,
+		SpiceGradeID: spiceGradeID,
+		Side:         side,
+		Price:        price,
+		Currency:     currency,
+		Quantity:     quantity,
+		RemainingQty: quantity,
+		Status:       OrderOpen,
+		PriorityAt:   now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err = s.repository.InsertOrder(txCtx, order); err != nil {
+		return nil, err
+	}
+	fills, trades, err := s.matchOrder(txCtx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.publishTrades(trades...)
+	s.logger.Service().Info().
+		Str("order_id", order.ID).
+		Str("user_id", userID).
+		Str("side", side).
+		Int("fills", len(fills)).
+		Msg("Placed order")
+	return &OrderResult{Order: order, Fills: fills}, nil
+}
+
+// AmendOrder changes the price and/or quantity of a resting order; zero values keep the
+// current ones. The quantity is the new total and must exceed what is already filled.
+// A higher quantity or a new price loses time priority, and a repriced order is matched again.
+func (s *MarketService) AmendOrder(ctx context.Context, userID string, orderID string, quantity decimal.Decimal, price decimal.Decimal) (*OrderResult, error) {
+	if orderID == "" {
+		return nil, errors.New("order_id is required")
+	}
+	if quantity.IsNegative() || price.IsNegative() {
+		return nil, errors.New("quantity and price must not be negative")
+	}
+
+	// The book lock comes before the order lock, as in PlaceOrder, so the two never deadlock.
+	current, err := s.repository.GetOrder(ctx, orderID)
+	if err == sql.ErrNoRows || (err == nil && userID != "" && current.UserID != userID) {
+		return nil, errors.New("order not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	txCtx, tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = s.repository.LockOrderBook(txCtx, current.SpiceGradeID); err != nil {
+		return nil, err
+	}
+	order, err := s.lockOpenOrder(txCtx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	filled := order.Quantity.Sub(order.RemainingQty)
+	newQty, newPrice := order.Quantity, order.Price
+	if quantity.IsPositive() {
+		newQty = quantity
+	}
+	if price.IsPositive() {
+		newPrice = price
+	}
+	if err = validateTrade(order.UserID, order.SpiceGradeID, newQty, newPrice); err != nil {
+		return nil, err
+	}
+	if !newQty.GreaterThan(filled) {
+		err = fmt.Errorf("quantity must exceed the %s already filled", filled)
+		return nil, err
+	}
+	if order.Side == OrderSideSell {
+		if err = s.checkSellOrderCovered(txCtx, order.UserID, order.SpiceGradeID, order.ID, newQty.Sub(filled)); err != nil {
+			return nil, err
+		}
+	}
+
+	repriced := !newPrice.Equal(order.Price)
+	order.UpdatedAt = time.Now()
+	if repriced || newQty.GreaterThan(order.Quantity) {
+		order.PriorityAt = order.UpdatedAt
+	}
+	order.Quantity, order.Price = newQty, newPrice
+	order.RemainingQty = newQty.Sub(filled)
+	if err = s.repository.UpdateOrder(txCtx, order); err != nil {
+		return nil, err
+	}
+
+	var fills []*OrderFill
+	var trades []*Transaction
+	if repriced {
+		if fills, trades, err = s.matchOrder(txCtx, order); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.publishTrades(trades...)
+	return &OrderResult{Order: order, Fills: fills}, nil
+}
+
+// CancelOrder takes a resting order out of the book. Fills already booked stand; cancel
+// their transactions to undo them.
+func (s *MarketService) CancelOrder(ctx context.Context, userID string, orderID string) (*Order, error) {
+	if orderID == "" {
+		return nil, errors.New("order_id is required")
+	}
+
+	txCtx, tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := s.lockOpenOrder(txCtx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	order.Status = OrderCancelled
+	order.UpdatedAt = time.Now()
+	if err = s.repository.UpdateOrder(txCtx, order); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// lockOpenOrder loads and locks a resting order, enforcing ownership when userID is set.
+func (s *MarketService) lockOpenOrder(txCtx context.Context, userID, orderID string) (*Order, error) {
+	order, err := s.repository.LockOrder(txCtx, orderID)
+	if err == sql.ErrNoRows || (err == nil && userID != "" && order.UserID != userID) {
+		return nil, errors.New("order not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != OrderOpen && order.Status != OrderPartiallyFilled {
+		return nil, ErrOrderNotOpen
+	}
+	return order, nil
+}
+
+// checkSellOrderCovered refuses a SELL order for more than the seller's open lots less what
+// their other resting SELL orders already offer. Accounts allowed to sell short are not limited.
+func (s *MarketService) checkSellOrderCovered(txCtx context.Context, userID, spiceGradeID, excludeOrderID string, quantity decimal.Decimal) error {
+	allowed, err := s.shortSellingAllowed(txCtx, userID)
+	if err != nil || allowed {
+		return err
+	}
+	available, err := s.openLotQty(txCtx, userID, spiceGradeID)
+	if err != nil {
+		return err
+	}
+	offered, err := s.repository.SumRestingSellQty(txCtx, userID, spiceGradeID, excludeOrderID)
+	if err != nil {
+		return err
+	}
+	if available.Sub(offered).LessThan(quantity) {
+		return errors.New("insufficient inventory: sell order exceeds open lots not already offered in the book")
+	}
+	return nil
+}
+
+// openLotQty sums the remaining quantity of a user's open lots on a grade, locking them.
+func (s *MarketService) openLotQty(txCtx context.Context, userID, spiceGradeID string) (decimal.Decimal, error) {
+	lots, err := s.repository.GetOpenBuyLots(txCtx, userID, spiceGradeID, CostBasisFIFO)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	total := decimal.Zero
+	for _, l := range lots {
+		total = total.Add(l.RemainingQty)
+	}
+	return total, nil
+}
+
+// matchOrder fills an incoming order against crossing resting orders inside the caller's
+// DB transaction, best price first and oldest first at the same price. An account's own
+// resting orders are skipped. A resting SELL whose seller no longer holds the inventory
+// (it was sold directly since), or a resting order its owner's ledger no longer takes, is
+// cancelled instead of filled.
+func (s *MarketService) matchOrder(txCtx context.Context, order *Order) ([]*OrderFill, []*Transaction, error) {
+	restingSide := OrderSideSell
+	if order.Side == OrderSideSell {
+		restingSide = OrderSideBuy
+	}
+	resting, err := s.repository.ListCrossingOrders(txCtx, order.SpiceGradeID, order.Currency, restingSide, order.Price, order.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fills []*OrderFill
+	var trades []*Transaction
+	for _, r := range resting {
+		if !order.RemainingQty.IsPositive() {
+			break
+		}
+		qty := decimal.Min(order.RemainingQty, r.RemainingQty)
+
+		buy, sell := order, r
+		if order.Side == OrderSideSell {
+			buy, sell = r, order
+		}
+		if sell == r {
+			ok, err := s.canDeliver(txCtx, r, qty)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !ok {
+				if err := s.cancelResting(txCtx, r, "Cancelled resting sell order no longer covered by inventory"); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+		}
+		ok, err := s.canBook(txCtx, r, qty, r.Price)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			if err := s.cancelResting(txCtx, r, "Cancelled resting order its owner's ledger no longer accepts"); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		fill, booked, err := s.bookFill(txCtx, buy, sell, qty, r.Price)
+		if err != nil {
+			return nil, nil, err
+		}
+		fills = append(fills, fill)
+		trades = append(trades, booked...)
+
+		for _, o := range []*Order{order, r} {
+			o.RemainingQty = o.RemainingQty.Sub(qty)
+			o.Status = OrderPartiallyFilled
+			if o.RemainingQty.IsZero() {
+				o.Status = OrderFilled
+			}
+			o.UpdatedAt = time.Now()
+		}
+		if err := s.repository.UpdateOrder(txCtx, r); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(fills) > 0 {
+		if err := s.repository.UpdateOrder(txCtx, order); err != nil {
+			return nil, nil, err
+		}
+	}
+	return fills, trades, nil
+}
+
+// cancelResting takes a resting order that can no longer be filled out of the book.
+func (s *MarketService) cancelResting(txCtx context.Context, r *Order, msg string) error {
+	r.Status = OrderCancelled
+	r.UpdatedAt = time.Now()
+	if err := s.repository.UpdateOrder(txCtx, r); err != nil {
+		return err
+	}
+	s.logger.Service().Warn().
+		Str("order_id", r.ID).
+		Str("user_id", r.UserID).
+		Msg(msg)
+	return nil
+}
+
+// canBook reports whether the ledger still takes the resting order's side of a fill: the
+// owner's open position must be held in the order's currency, and fixed fees need an FX rate
+// into it. Either can change after the order was placed.
+func (s *MarketService) canBook(txCtx context.Context, r *Order, qty, price decimal.Decimal) (bool, error) {
+	t := &Transaction{
+		UserID:       r.UserID,
+		SpiceGradeID: r.SpiceGradeID,
+		Type:         r.Side,
+		Quantity:     qty,
+		Price:        price,
+		Currency:     r.Currency,
+		TradeDate:    time.Now(),
+	}
+	err := s.stampCurrency(txCtx, t)
+	if err == nil {
+		err = s.chargeFees(txCtx, t)
+	}
+	if errors.Is(err, ErrCurrencyMismatch) || errors.Is(err, ErrNoFxRate) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// canDeliver reports whether the seller of a resting order can still cover qty.
+func (s *MarketService) canDeliver(txCtx context.Context, sell *Order, qty decimal.Decimal) (bool, error) {
+	allowed, err := s.shortSellingAllowed(txCtx, sell.UserID)
+	if err != nil || allowed {
+		return allowed, err
+	}
+	available, err := s.openLotQty(txCtx, sell.UserID, sell.SpiceGradeID)
+	if err != nil {
+		return false, err
+	}
+	return !available.LessThan(qty), nil
+}
+
+// bookFill books one match through the ledger path: a BUY for the buyer (lot, short covers,
+// position) and a SELL for the seller allocated by their stored cost-basis method. It returns
+// the fill and the two booked transactions.
+func (s *MarketService) bookFill(txCtx context.Context, buy, sell *Order, qty, price decimal.Decimal) (*OrderFill, []*Transaction, error) {
+	tradeDate := time.Now()
+	buyTxn := &Transaction{
+		ID:           ksuid.New().String(),
+		UserID:       buy.UserID,
+		SpiceGradeID: buy.SpiceGradeID,
+		Type:         "BUY",
+		Quantity:     qty,
+		Price:        price,
+		Currency:     buy.Currency,
+		TradeDate:    tradeDate,
+	}
+	if err := s.bookBuy(txCtx, buyTxn); err != nil {
+		return nil, nil, err
+	}
+
+	method, err := s.resolveCostBasisMethod(txCtx, sell.UserID, sell.SpiceGradeID, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	sellTxn := &Transaction{
+		ID:              ksuid.New().String(),
+		UserID:          sell.UserID,
+		SpiceGradeID:    sell.SpiceGradeID,
+		Type:            "SELL",
+		Quantity:        qty,
+		Price:           price,
+		Currency:        sell.Currency,
+		CostBasisMethod: method,
+		TradeDate:       tradeDate,
+	}
+	if err := s.stampCurrency(txCtx, sellTxn); err != nil {
+		return nil, nil, err
+	}
+	if err := s.chargeFees(txCtx, sellTxn); err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.repository.InsertTransaction(txCtx, sellTxn); err != nil {
+		return nil, nil, err
+	}
+	if err := s.allocateSell(txCtx, sellTxn, method, nil); err != nil {
+		return nil, nil, err
+	}
+
+	fill := &OrderFill{
+		ID:                ksuid.New().String(),
+		SpiceGradeID:      buy.SpiceGradeID,
+		BuyOrderID:        buy.ID,
+		SellOrderID:       sell.ID,
+		BuyTransactionID:  buyTxn.ID,
+		SellTransactionID: sellTxn.ID,
+		Quantity:          qty,
+		Price:             price,
+		CreatedAt:         tradeDate,
+	}
+	if err := s.repository.InsertOrderFill(txCtx, fill); err != nil {
+		return nil, nil, err
+	}
+	return fill, []*Transaction{buyTxn, sellTxn}, nil
+}
+
+// GetOrder returns an order with all its fills. A non-empty userID limits it to that account.
+func (s *MarketService) GetOrder(ctx context.Context, userID string, orderID string) (*OrderResult, error) {
+	if orderID == "" {
+		return nil, errors.New("order_id is required")
+	}
+	order, err := s.repository.GetOrder(ctx, orderID)
+	if err == sql.ErrNoRows || (err == nil && userID != "" && order.UserID != userID) {
+		return nil, errors.New("order not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	fills, err := s.repository.ListOrderFills(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderResult{Order: order, Fills: fills}, nil
+}
+
+// ListOrders returns orders newest first. An empty filter.UserID (admin) lists every account.
+func (s *MarketService) ListOrders(ctx context.Context, filter OrderFilter) ([]*Order, error) {
+	filter.Side = strings.ToUpper(strings.TrimSpace(filter.Side))
+	filter.Status = strings.ToUpper(strings.TrimSpace(filter.Status))
+	return s.repository.ListOrders(ctx, filter)
+}
+
+// GetOrderBook returns the resting depth of a grade in one currency, up to depth price levels
+// per side (default 10, max 50). An empty currency is the caller's trading currency; admins
+// have none, so they must name one.
+func (s *MarketService) GetOrderBook(ctx context.Context, userID string, spiceGradeID string, currency string, depth uint) (*OrderBook, error) {
+	if spiceGradeID == "" {
+		return nil, errors.New("spice_grade_id is required")
+	}
+	currency, err := util.ParseCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		if userID == "" {
+			return nil, errors.New("currency is required")
+		}
+		if currency, _, err = s.repository.GetAccountCurrencies(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	if depth == 0 {
+		depth = 10
+	}
+	if depth > 50 {
+		depth = 50
+	}
+	return s.repository.GetOrderBookDepth(ctx, spiceGradeID, currency, depth)
+}
+
+// shortSellingAllowed reports whether an account may sell beyond its open lots.
+func (s *MarketService) shortSellingAllowed(ctx context.Context, userID string) (bool, error) {
+	perms, err := s.repository.GetTradingPermissions(ctx, userID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return perms.AllowShortSelling, nil
+}
+
+// SetTradingPermissions turns short selling on or off for an account. Switching it off
+// does not touch short lots already open; later buys still cover them.
+func (s *MarketService) SetTradingPermissions(ctx context.Context, userID string, allowShortSelling bool, updatedBy string) (*TradingPermissions, error) {
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+
+	perms := &TradingPermissions{
+		UserID:            userID,
+		AllowShortSelling: allowShortSelling,
+		UpdatedBy:         updatedBy,
+	}
+	if err := s.repository.UpsertTradingPermissions(ctx, perms); err != nil {
+		return nil, err
+	}
+	s.logger.Service().Info().
+		Str("user_id", userID).
+		Str("updated_by", updatedBy).
+		Bool("allow_short_selling", allowShortSelling).
+		Msg("Updated trading permissions")
+	return s.GetTradingPermissions(ctx, userID)
+}
+
+// GetTradingPermissions returns an account's capabilities; unconfigured accounts have all off.
+func (s *MarketService) GetTradingPermissions(ctx context.Context, userID string) (*TradingPermissions, error) {
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+
+	perms, err := s.repository.GetTradingPermissions(ctx, userID)
+	if err == sql.ErrNoRows {
+		return &TradingPermissions{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return perms, nil
+}
+
+// SubscribeTrades streams committed trade changes: new BUYs and SELLs, order fills, and the
+// cancelled originals, reversals and replacements of cancels and amendments. Empty userID or
+// spiceGradeID match every account or grade. A non-empty epoch resumes after afterSequence.
+func (s *MarketService) SubscribeTrades(userID string, spiceGradeID string, epoch string, afterSequence uint64) (*platform.Subscription, error) {
+	if s.events == nil {
+		return nil, errors.New("trade events are not enabled")
+	}
+	return s.events.Subscribe(epoch, afterSequence, func(ev platform.Event) bool {
+		t, ok := ev.Payload.(*Transaction)
+		return ev.Topic == TopicTrades && ok &&
+			(userID == "" || t.UserID == userID) &&
+			(spiceGradeID == "" || t.SpiceGradeID == spiceGradeID)
+	})
+}
+
+// publishTrades announces committed transactions to trade subscribers. Call it only after
+// the DB transaction has committed, so subscribers never see a trade that was rolled back.
+func (s *MarketService) publishTrades(trades ...*Transaction) {
+	if s.events == nil {
+		return
+	}
+	for _, t := range trades {
+		if t != nil {
+			s.events.Publish(TopicTrades, t)
+		}
+	}
+}
+
+// GetPosition returns the aggregate position with live unrealized P&L from today's daily_price.
+// If today's price is not yet published, UnrealizedPnL and TodayPrice are left as zero.
+func (s *MarketService) GetGradePosition(ctx context.Context, userID string, spiceGradeID string) (*PositionView, error) {
 	if userID == "" {
 		return nil, errors.New("user_id is required")
 	}
 	if spiceGradeID == "" {
 		return nil, errors.New("spice_grade_id is required")
 	}
-	if quantity <= 0 {
-		return nil, errors.New("quantity must be greater than zero")
-	}
-	if price <= 0 {
-		return nil, errors.New("price must be greater than zero")
+
+	pos, err := s.repository.GetGradePosition(ctx, userID, spiceGradeID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("no position found for this user and grade")
 	}
-	if tradeDate.IsZero() {
-		tradeDate = time.Now()
+	if err != nil {
+		return nil, err
 	}
 
-	txCtx, tx, err := s.repository.BeginTx(ctx)
+	_, reporting, err := s.repository.GetAccountCurrencies(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
+	return s.positionView(ctx, pos, reporting, time.Now()), nil
+}
 
-	// 1. Record the immutable transaction.
-	t := &Transaction{
-		ID:           ksuid.New().String(),
-		UserID:       userID,
-		SpiceGradeID: spiceGradeID,
-		Type:         "BUY",
-		Quantity:     quantity,
-		Price:        price,
-		TradeDate:    tradeDate,
+// GetPositions returns all aggregate positions for a user with live unrealized P&L.
+func (s *MarketService) GetPositions(ctx context.Context, userID string) ([]*PositionView, error) {
+	if userID == "" {
+		return nil, errors.New("user_id is required")
 	}
-	if _, err = s.repository.InsertTransaction(txCtx, t); err != nil {
+
+	positions, err := s.repository.GetPositionsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	_, reporting, err := s.repository.GetAccountCurrencies(ctx, userID)
+	if err != nil {
 		return nil, err
 	}
 
-	// 2. Create the inventory lot (original_qty = remaining_qty = full purchase qty).
-	lot := &BuyLot{
-		ID:            ksuid.New().String(),
-		TransactionID: t.ID,
-		UserID:        userID,
-		SpiceGradeID:  spiceGradeID,
-		OriginalQty:   quantity,
-		RemainingQty:  quantity,
-		Price:         price,
-		TradeDate:     tradeDate,
+	now := time.Now()
+	var views []*PositionView
+	for _, pos := range positions {
+		views = append(views, s.positionView(ctx, pos, reporting, now))
 	}
-	if _, err = s.repository.InsertBuyLot(txCtx, lot); err != nil {
-		return nil, err
+
+	return views, nil
+}
+
+// positionView values a position under the valuation policy and converts its amounts into the
+// reporting currency. Both are best-effort: without a qualifying price or FX rate the view is
+// returned without unrealized P&L or reporting amounts.
+func (s *MarketService) positionView(ctx context.Context, pos *Position, reporting string, now time.Time) *PositionView {
+	view := &PositionView{
+		UserID:       pos.UserID,
+		SpiceGradeID: pos.SpiceGradeID,
+		Currency:     pos.Currency,
+		TotalQty:     pos.TotalQty,
+		TotalCost:    pos.TotalCost,
+		RealizedPnL:  pos.RealizedPnL,
+		UpdatedAt:    pos.UpdatedAt,
+		Reporting:    ReportingAmounts{Currency: reporting},
+		Unit:         GradeUnit{Unit: util.BaseUnit, KgPerUnit: decimal.NewFromInt(1)},
 	}
 
-	// 3. Upsert position — increase qty and cost.
-	pos := &Position{
-		UserID:       userID,
-		SpiceGradeID: spiceGradeID,
-		TotalQty:     quantity,
-		TotalCost:    quantity * price,
-		RealizedPnL:  0,
+	view.AvgCost = averageCost(pos.TotalCost, pos.TotalQty)
+	if unit, err := s.repository.GetGradeUnit(ctx, pos.SpiceGradeID); err == nil {
+		view.Unit = unit
 	}
-	if err = s.repository.UpsertPosition(txCtx, pos); err != nil {
-		return nil, err
+	view.UnitQty = unitQuantity(pos.TotalQty, view.Unit)
+
+	price, valuation, priceErr := s.valuePrice(ctx, pos.SpiceGradeID, pos.Currency, now)
+	if priceErr == nil && !valuation.Date.IsZero() {
+		view.TodayPrice = price
+		view.UnrealizedPnL = unrealizedPnL(pos, price)
+		view.Valuation = valuation
 	}
 
-	if err = tx.Commit(); err != nil {
-		return nil, err
+	rate, rateErr := s.repository.GetFxRate(ctx, pos.Currency, reporting, now)
+	if rateErr == nil {
+		view.Reporting.FxRate = rate
+		view.Reporting.TotalCost = util.RoundMoney(view.TotalCost.Mul(rate), reporting)
+		view.Reporting.RealizedPnL = util.RoundMoney(view.RealizedPnL.Mul(rate), reporting)
+		view.Reporting.UnrealizedPnL = util.RoundMoney(view.UnrealizedPnL.Mul(rate), reporting)
 	}
-	return t, nil
+	return view
 }
 
-// Sell matches the requested quantity against open buy_lots in FIFO order.
-// All lot deductions, sell_allocations, and position updates are atomic.
-func (s *MarketService) Sell(ctx context.Context, userID string, spiceGradeID string, quantity float64, price float64, tradeDate time.Time) (*Transaction, error) {
+// ListGradeTransactions returns paginated trade history for one grade.
+func (s *MarketService) ListGradeTransactions(ctx context.Context, userID, spiceGradeID string, skip, take uint, sort, dateFrom, dateTo string) ([]*Transaction, error) {
 	if userID == "" {
 		return nil, errors.New("user_id is required")
 	}
 	if spiceGradeID == "" {
 		return nil, errors.New("spice_grade_id is required")
 	}
-	if quantity <= 0 {
-		return nil, errors.New("quantity must be greater than zero")
+	if take == 0 || take > 100 {
+		take = 100
 	}
-	if price <= 0 {
-		return nil, errors.New("price must be greater than zero")
+	return s.repository.ListGradeTransactionsByUser(ctx, userID, spiceGradeID, skip, take, sort, dateFrom, dateTo)
+}
+
+// ListTransactions returns paginated trade history for a user across grades.
+func (s *MarketService) ListTransactions(ctx context.Context, userID string, skip, take uint, spiceGradeID string, spiceGradeIDs []string, sort, dateFrom, dateTo string) ([]*Transaction, error) {
+	if userID == "" {
+		return nil, errors.New("user_id is required")
 	}
-	if tradeDate.IsZero() {
-		tradeDate = time.Now()
+	if take == 0 || take > 100 {
+		take = 100
 	}
+	return s.repository.ListTransactionsByUser(ctx, userID, skip, take, spiceGradeID, spiceGradeIDs, sort, dateFrom, dateTo)
+}
 
-	txCtx, tx, err := s.repository.BeginTx(ctx)
+// ListOpenLots returns the buy lots that still hold inventory. An empty userID (admin) lists every account.
+func (s *MarketService) ListOpenLots(ctx context.Context, userID, spiceGradeID string, skip, take uint, sort, dateFrom, dateTo string) ([]*BuyLot, error) {
+	return s.repository.ListOpenLots(ctx, userID, spiceGradeID, skip, take, sort, dateFrom, dateTo)
+}
+
+// GetLotHistory returns a lot and every allocation drawn from it, reversed ones included.
+// Dates filter the allocations by SELL trade_date.
+func (s *MarketService) GetLotHistory(ctx context.Context, userID, lotID string, skip, take uint, dateFrom, dateTo string) (*LotHistory, error) {
+	if lotID == "" {
+		return nil, errors.New("lot_id is required")
+	}
+	lot, err := s.repository.GetBuyLot(ctx, lotID)
+	if err == sql.ErrNoRows || (err == nil && userID != "" && lot.UserID != userID) {
+		return nil, errors.New("lot not found")
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
+	allocs, err := s.repository.ListSellAllocations(ctx, AllocationFilter{
+		BuyLotID:        lot.ID,
+		DateFrom:        dateFrom,
+		DateTo:          dateTo,
+		IncludeReversed: true,
+		Skip:            skip,
+		Take:            take,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &LotHistory{Lot: lot, Allocations: allocs}, nil
+}
+
+// GetSellAllocations returns how sells were matched to lots. With a SellTransactionID it
+// describes one sell, which must be a SELL owned by filter.UserID unless that is empty (admin).
+func (s *MarketService) GetSellAllocations(ctx context.Context, filter AllocationFilter) ([]*AllocationDetail, error) {
+	if filter.SellTransactionID != "" {
+		sell, err := s.repository.GetTransactionByID(ctx, filter.SellTransactionID)
+		if err == sql.ErrNoRows || (err == nil && filter.UserID != "" && sell.UserID != filter.UserID) {
+			return nil, errors.New("transaction not found")
+		}
 		if err != nil {
-			tx.Rollback()
+			return nil, err
 		}
-	}()
+		if sell.Type != "SELL" {
+			return nil, fmt.Errorf("transaction %s is a %s, not a SELL", sell.ID, sell.Type)
+		}
+	}
+	return s.repository.ListSellAllocations(ctx, filter)
+}
 
-	// 1. Lock open lots in FIFO order (FOR UPDATE prevents concurrent oversell).
-	lots, err := s.repository.GetOpenBuyLots(txCtx, userID, spiceGradeID)
+// ListTransactionFees returns the fee and tax lines charged on one trade. A userID scopes the
+// lookup to that user's trades.
+func (s *MarketService) ListTransactionFees(ctx context.Context, userID, transactionID string) ([]*TransactionFee, error) {
+	if transactionID == "" {
+		return nil, errors.New("transaction_id is required")
+	}
+	txn, err := s.repository.GetTransactionByID(ctx, transactionID)
+	if err == sql.ErrNoRows || (err == nil && userID != "" && txn.UserID != userID) {
+		return nil, errors.New("transaction not found")
+	}
 	if err != nil {
 		return nil, err
 	}
+	return s.repository.ListTransactionFees(ctx, txn.ID)
+}
 
-	// Service-layer inventory check.
-	var totalAvailable float64
-	for _, l := range lots {
-		totalAvailable += l.RemainingQty
+// GetRealizedGainsReport matches every standing sell of a user realized in a financial year
+// or date range to the lots it drew on, and every short sale to the buys that covered it, with
+// the holding period, cost, proceeds and gain of each match. Unlike the dashboard history it
+// has no day limit.
+func (s *MarketService) GetRealizedGainsReport(ctx context.Context, filter GainsFilter) (*RealizedGainsReport, error) {
+	if filter.UserID == "" {
+		return nil, errors.New("user_id is required")
 	}
-	if totalAvailable < quantity {
-		err = errors.New("insufficient inventory: sell quantity exceeds available buy lots")
+	from, to, err := gainsPeriod(filter.FinancialYear, filter.DateFrom, filter.DateTo)
+	if err != nil {
 		return nil, err
 	}
-
-	// 2. Record the immutable SELL transaction.
-	t := &Transaction{
-		ID:           ksuid.New().String(),
-		UserID:       userID,
-		SpiceGradeID: spiceGradeID,
-		Type:         "SELL",
-		Quantity:     quantity,
-		Price:        price,
-		TradeDate:    tradeDate,
+	if filter.LongTermDays < 0 {
+		return nil, errors.New("long_term_days cannot be negative")
 	}
-	if _, err = s.repository.InsertTransaction(txCtx, t); err != nil {
-		return nil, err
+	if filter.LongTermDays == 0 {
+		filter.LongTermDays = DefaultLongTermDays
 	}
 
-	// 3. Walk lots oldest→newest, consuming until the sell quantity is filled.
-	remaining := quantity
-	var totalRealizedPnL float64
-	var totalCostConsumed float64
+	gains, err := s.repository.ListRealizedGains(ctx, filter.UserID, filter.SpiceGradeID,
+		from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
 
-	for _, lot := range lots {
-		if remaining <= 0 {
-			break
+	report := &RealizedGainsReport{
+		UserID:       filter.UserID,
+		DateFrom:     from,
+		DateTo:       to,
+		LongTermDays: filter.LongTermDays,
+		Gains:        gains,
+	}
+	grades := make(map[string]*GainsSubtotal)
+	classes := make(map[string]*GainsSubtotal)
+	totals := make(map[string]*GainsSubtotal)
+	for _, g := range gains {
+		g.Cost = lotCost(g.Quantity, g.BuyPrice, g.Currency).Add(g.CloseAdjustment)
+		g.Proceeds = lotCost(g.Quantity, g.SellPrice, g.Currency)
+		if g.Kind == GainShort {
+			// The short lot's price is already net of the sell fees.
+			g.Gain = g.Proceeds.Sub(g.Cost)
 		}
-
-		consume := lot.RemainingQty
-		if consume > remaining {
-			consume = remaining
+		g.Fees = g.Proceeds.Sub(g.Cost).Sub(g.Gain)
+		held := g.DisposedDate.Sub(g.AcquiredDate)
+		if held < 0 {
+			held = -held
+		}
+		g.HoldingDays = int(held.Hours() / 24)
+		g.HoldingClass = HoldingShortTerm
+		if g.HoldingDays > filter.LongTermDays {
+			g.HoldingClass = HoldingLongTerm
 		}
 
-		if err = s.repository.DeductBuyLotQty(txCtx, lot.ID, consume); err != nil {
-			return nil, err
+		gradeKey := g.SpiceGradeID + "|" + g.Currency
+		if grades[gradeKey] == nil {
+			grades[gradeKey] = &GainsSubtotal{SpiceGradeID: g.SpiceGradeID, ProductName: g.ProductName, GradeName: g.GradeName, Currency: g.Currency}
+			report.Grades = append(report.Grades, grades[gradeKey])
+		}
+		classKey := g.HoldingClass + "|" + g.Currency
+		if classes[classKey] == nil {
+			classes[classKey] = &GainsSubtotal{HoldingClass: g.HoldingClass, Currency: g.Currency}
+			report.HoldingClasses = append(report.HoldingClasses, classes[classKey])
+		}
+		if totals[g.Currency] == nil {
+			totals[g.Currency] = &GainsSubtotal{Currency: g.Currency}
+			report.Totals = append(report.Totals, totals[g.Currency])
+		}
+		for _, sub := range []*GainsSubtotal{grades[gradeKey], classes[classKey], totals[g.Currency]} {
+			sub.Quantity = sub.Quantity.Add(g.Quantity)
+			sub.Cost = sub.Cost.Add(g.Cost)
+			sub.Proceeds = sub.Proceeds.Add(g.Proceeds)
+			sub.Fees = sub.Fees.Add(g.Fees)
+			sub.Gain = sub.Gain.Add(g.Gain)
+			sub.Lines++
 		}
+	}
 
-		lotPnL := (price - lot.Price) * consume
-		alloc := &SellAllocation{
-			ID:                ksuid.New().String(),
-			SellTransactionID: t.ID,
-			BuyLotID:          lot.ID,
-			Quantity:          consume,
-			BuyPrice:          lot.Price,
-			SellPrice:         price,
-			RealizedPnL:       lotPnL,
+	sort.SliceStable(report.Grades, func(i, j int) bool {
+		a, b := report.Grades[i], report.Grades[j]
+		if a.ProductName != b.ProductName {
+			return a.ProductName < b.ProductName
 		}
-		if err = s.repository.InsertSellAllocation(txCtx, alloc); err != nil {
-			return nil, err
+		if a.GradeName != b.GradeName {
+			return a.GradeName < b.GradeName
+		}
+		return a.Currency < b.Currency
+	})
+	sort.SliceStable(report.HoldingClasses, func(i, j int) bool {
+		a, b := report.HoldingClasses[i], report.HoldingClasses[j]
+		if a.HoldingClass != b.HoldingClass {
+			return a.HoldingClass == HoldingShortTerm
 		}
+		return a.Currency < b.Currency
+	})
+	sort.SliceStable(report.Totals, func(i, j int) bool {
+		return report.Totals[i].Currency < report.Totals[j].Currency
+	})
+	return report, nil
+}
 
-		totalRealizedPnL += lotPnL
-		totalCostConsumed += lot.Price * consume
-		remaining -= consume
+// ExportLedger streams every record of one kind matching filter to fn, as it is read and without
+// the list queries' page limit. Positions are valued like GetPositions; as an account holds few,
+// they are read in full before valuing so no query runs while the cursor is open.
+func (s *MarketService) ExportLedger(ctx context.Context, filter ExportFilter, fn func(*LedgerExportRow) error) error {
+	filter.Kind = strings.ToUpper(strings.TrimSpace(filter.Kind))
+	switch filter.Kind {
+	case ExportTransactions:
+		return s.repository.ExportTransactions(ctx, filter, fn)
+	case ExportLots:
+		return s.repository.ExportBuyLots(ctx, filter, fn)
+	case ExportAllocations:
+		return s.repository.ExportSellAllocations(ctx, filter, fn)
+	case ExportPositions:
+	default:
+		return fmt.Errorf("unknown export kind %q: use TRANSACTIONS, LOTS, ALLOCATIONS or POSITIONS", filter.Kind)
 	}
 
-	// 4. Update position: decrease qty + cost, accumulate realized P&L.
-	pos := &Position{
-		UserID:       userID,
-		SpiceGradeID: spiceGradeID,
-		TotalQty:     -(quantity),
-		TotalCost:    -(totalCostConsumed),
-		RealizedPnL:  totalRealizedPnL,
-	}
-	if err = s.repository.UpsertPosition(txCtx, pos); err != nil {
-		return nil, err
+	var rows []*LedgerExportRow
+	var positions []*Position
+	err := s.repository.ExportPositions(ctx, filter, func(pos *Position, productName, gradeName string) error {
+		rows = append(rows, &LedgerExportRow{ProductName: productName, GradeName: gradeName})
+		positions = append(positions, pos)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if err = tx.Commit(); err != nil {
-		return nil, err
+	now := time.Now()
+	reporting := make(map[string]string)
+	for i, pos := range positions {
+		if _, ok := reporting[pos.UserID]; !ok {
+			_, currency, err := s.repository.GetAccountCurrencies(ctx, pos.UserID)
+			if err != nil {
+				return err
+			}
+			reporting[pos.UserID] = currency
+		}
+		rows[i].Position = s.positionView(ctx, pos, reporting[pos.UserID], now)
+		if err := fn(rows[i]); err != nil {
+			return err
+		}
 	}
-	return t, nil
+	return nil
 }
 
-// GetPosition returns the aggregate position with live unrealized P&L from today's daily_price.
-// If today's price is not yet published, UnrealizedPnL and TodayPrice are left as zero.
-func (s *MarketService) GetGradePosition(ctx context.Context, userID string, spiceGradeID string) (*PositionView, error) {
-	if userID == "" {
-		return nil, errors.New("user_id is required")
-	}
-	if spiceGradeID == "" {
-		return nil, errors.New("spice_grade_id is required")
+// gainsPeriod resolves a gains report's period from a financial year ("2025-26" or "2025",
+// starting in FinancialYearStartMonth) or from both dates of a range.
+func gainsPeriod(financialYear, dateFrom, dateTo string) (time.Time, time.Time, error) {
+	financialYear = strings.TrimSpace(financialYear)
+	if financialYear != "" {
+		if dateFrom != "" || dateTo != "" {
+			return time.Time{}, time.Time{}, errors.New("give financial_year or date_from and date_to, not both")
+		}
+		startYear, suffix, hasSuffix := strings.Cut(financialYear, "-")
+		year, err := strconv.Atoi(startYear)
+		if err != nil || len(startYear) != 4 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid financial_year %q: use YYYY or YYYY-YY", financialYear)
+		}
+		if hasSuffix && suffix != fmt.Sprintf("%02d", (year+1)%100) {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid financial_year %q: use YYYY or YYYY-YY", financialYear)
+		}
+		from := time.Date(year, FinancialYearStartMonth, 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(1, 0, -1), nil
 	}
 
-	pos, err := s.repository.GetGradePosition(ctx, userID, spiceGradeID)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("no position found for this user and grade")
+	if dateFrom == "" || dateTo == "" {
+		return time.Time{}, time.Time{}, errors.New("financial_year, or date_from and date_to, are required")
 	}
+	from, err := time.Parse("2006-01-02", dateFrom)
 	if err != nil {
-		return nil, err
-	}
-
-	view := &PositionView{
-		UserID:       pos.UserID,
-		SpiceGradeID: pos.SpiceGradeID,
-		TotalQty:     pos.TotalQty,
-		TotalCost:    pos.TotalCost,
-		RealizedPnL:  pos.RealizedPnL,
-		UpdatedAt:    pos.UpdatedAt,
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date_from %q: use YYYY-MM-DD", dateFrom)
 	}
-
-	if pos.TotalQty > 0 {
-		view.AvgCost = pos.TotalCost / pos.TotalQty
+	to, err := time.Parse("2006-01-02", dateTo)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date_to %q: use YYYY-MM-DD", dateTo)
 	}
-
-	// Best-effort: fetch today's price for unrealized P&L.
-	// If not published yet, we return the position without unrealized P&L.
-	todayPrice, priceErr := s.repository.GetDailyPrice(ctx, spiceGradeID, time.Now())
-	if priceErr == nil {
-		view.TodayPrice = todayPrice
-		view.UnrealizedPnL = (todayPrice - view.AvgCost) * pos.TotalQty
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("date_to is before date_from")
 	}
-
-	return view, nil
+	return from, to, nil
 }
 
-// GetPositions returns all aggregate positions for a user with live unrealized P&L.
-func (s *MarketService) GetPositions(ctx context.Context, userID string) ([]*PositionView, error) {
+// GetLotAgeing buckets a user's open lots by age on asOf (0–30, 31–90 and 90+ days) and
+// moves lots past their grade's shelf life to EXPIRED. Lots within NearExpiryDays of
+// their shelf life are counted per grade as near expiry.
+func (s *MarketService) GetLotAgeing(ctx context.Context, userID string, asOf time.Time) (*LotAgeingReport, error) {
 	if userID == "" {
 		return nil, errors.New("user_id is required")
 	}
-
-	positions, err := s.repository.GetPositionsByUser(ctx, userID)
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	lots, err := s.repository.ListAgeingLots(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	var views []*PositionView
-	for _, pos := range positions {
-		view := &PositionView{
-			UserID:       pos.UserID,
-			SpiceGradeID: pos.SpiceGradeID,
-			TotalQty:     pos.TotalQty,
-			TotalCost:    pos.TotalCost,
-			RealizedPnL:  pos.RealizedPnL,
-			UpdatedAt:    pos.UpdatedAt,
+	report := &LotAgeingReport{AsOf: asOf, Buckets: newAgeingBuckets()}
+	var grade *GradeAgeing
+	for _, lot := range lots {
+		if grade == nil || grade.SpiceGradeID != lot.SpiceGradeID {
+			report.Grades = append(report.Grades, GradeAgeing{
+				SpiceGradeID:  lot.SpiceGradeID,
+				ProductName:   lot.ProductName,
+				GradeName:     lot.GradeName,
+				ShelfLifeDays: lot.ShelfLifeDays,
+				Buckets:       newAgeingBuckets(),
+			})
+			grade = &report.Grades[len(report.Grades)-1]
 		}
 
-		if pos.TotalQty > 0 {
-			view.AvgCost = pos.TotalCost / pos.TotalQty
+		tradeDate := time.Date(lot.TradeDate.Year(), lot.TradeDate.Month(), lot.TradeDate.Day(), 0, 0, 0, 0, time.UTC)
+		age := int(asOf.Sub(tradeDate).Hours() / 24)
+		if age < 0 {
+			age = 0
 		}
-
-		// Best-effort: fetch today's price for unrealized P&L
-		todayPrice, priceErr := s.repository.GetDailyPrice(ctx, pos.SpiceGradeID, time.Now())
-		if priceErr == nil {
-			view.TodayPrice = todayPrice
-			if pos.TotalQty > 0 {
-				view.UnrealizedPnL = (todayPrice - view.AvgCost) * pos.TotalQty
+		bucket := ageBucketIndex(age)
+		if lot.ShelfLifeDays > 0 {
+			daysLeft := lot.ShelfLifeDays - age
+			switch {
+			case daysLeft <= 0:
+				bucket = len(grade.Buckets) - 1 // EXPIRED
+			case daysLeft <= NearExpiryDays:
+				grade.NearExpiryQty = grade.NearExpiryQty.Add(lot.RemainingQty)
+				grade.NearExpiryLots++
+			}
+			if daysLeft > 0 {
+				expiry := tradeDate.AddDate(0, 0, lot.ShelfLifeDays)
+				if grade.NextExpiry.IsZero() || expiry.Before(grade.NextExpiry) {
+					grade.NextExpiry = expiry
+				}
 			}
 		}
-		views = append(views, view)
-	}
 
-	return views, nil
+		cost := lotCost(lot.RemainingQty, lot.Price, lot.Currency)
+		for _, b := range []*AgeingBucket{&grade.Buckets[bucket], &report.Buckets[bucket]} {
+			b.Quantity = b.Quantity.Add(lot.RemainingQty)
+			b.Cost = b.Cost.Add(cost)
+			b.Lots++
+		}
+	}
+	return report, nil
 }
 
-// ListGradeTransactions returns paginated trade history for one grade.
-func (s *MarketService) ListGradeTransactions(ctx context.Context, userID, spiceGradeID string, skip, take uint, sort, dateFrom, dateTo string) ([]*Transaction, error) {
-	if userID == "" {
-		return nil, errors.New("user_id is required")
+// newAgeingBuckets returns the empty bands in report order; EXPIRED is always last.
+func newAgeingBuckets() []AgeingBucket {
+	labels := []string{AgeBucket0To30, AgeBucket31To90, AgeBucket90Plus, AgeBucketExpired}
+	buckets := make([]AgeingBucket, len(labels))
+	for i, label := range labels {
+		buckets[i] = AgeingBucket{Label: label, Quantity: decimal.Zero, Cost: decimal.Zero}
 	}
-	if spiceGradeID == "" {
-		return nil, errors.New("spice_grade_id is required")
-	}
-	if take == 0 || take > 100 {
-		take = 100
+	return buckets
+}
+
+func ageBucketIndex(ageDays int) int {
+	switch {
+	case ageDays <= 30:
+		return 0
+	case ageDays <= 90:
+		return 1
+	default:
+		return 2
 	}
-	return s.repository.ListGradeTransactionsByUser(ctx, userID, spiceGradeID, skip, take, sort, dateFrom, dateTo)
 }
 
-// ListTransactions returns paginated trade history for a user across grades.
-func (s *MarketService) ListTransactions(ctx context.Context, userID string, skip, take uint, spiceGradeID string, spiceGradeIDs []string, sort, dateFrom, dateTo string) ([]*Transaction, error) {
+// GetEnrichedHoldings returns a merchant's open positions valued under the valuation policy,
+// with the rate converting each into the account's reporting currency, which it also returns.
+func (s *MarketService) GetEnrichedHoldings(ctx context.Context, userID string) ([]EnrichedHoldingRow, string, error) {
 	if userID == "" {
-		return nil, errors.New("user_id is required")
+		return nil, "", errors.New("user_id is required")
 	}
-	if take == 0 || take > 100 {
-		take = 100
+	holdings, err := s.repository.GetEnrichedHoldings(ctx, userID)
+	if err != nil {
+		return nil, "", err
 	}
-	return s.repository.ListTransactionsByUser(ctx, userID, skip, take, spiceGradeID, spiceGradeIDs, sort, dateFrom, dateTo)
-}
+	_, reporting, err := s.repository.GetAccountCurrencies(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	for i := range holdings {
+		price, valuation, err := s.valuePrice(ctx, holdings[i].SpiceGradeID, holdings[i].Currency, now)
+		if err != nil {
+			return nil, "", err
+		}
+		holdings[i].TodayPrice = price
+		holdings[i].Valuation = valuation
 
-func (s *MarketService) GetEnrichedHoldings(ctx context.Context, userID string) ([]EnrichedHoldingRow, error) {
-	if userID == "" {
-		return nil, errors.New("user_id is required")
+		rate, err := s.repository.GetFxRate(ctx, holdings[i].Currency, reporting, now)
+		if err != nil && !errors.Is(err, ErrNoFxRate) {
+			return nil, "", err
+		}
+		holdings[i].FxRate = rate
+		holdings[i].UnitQty = unitQuantity(holdings[i].TotalQty, holdings[i].Unit)
 	}
-	return s.repository.GetEnrichedHoldings(ctx, userID)
+	return holdings, reporting, nil
 }
 
 func (s *MarketService) GetDailyRealizedPnLByUser(ctx context.Context, userID string, days uint) ([]DailyRealizedPnLRow, error) {
@@ -399,9 +2760,103 @@ func (s *MarketService) GetPeriodTradeStats(ctx context.Context, userID string,
 	return s.repository.GetPeriodTradeStats(ctx, userID, days)
 }
 
+// GetPriceSnapshotsForHoldings compares each held grade's valuation price with the daily price
+// published before it. Without a valuation, the previous price is the newest before today.
 func (s *MarketService) GetPriceSnapshotsForHoldings(ctx context.Context, userID string) ([]PriceSnapshot, error) {
 	if userID == "" {
 		return nil, errors.New("user_id is required")
 	}
-	return s.repository.GetPriceSnapshotsForHoldings(ctx, userID)
+	snapshots, err := s.repository.GetPriceSnapshotsForHoldings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range snapshots {
+		price, valuation, err := s.valuePrice(ctx, snapshots[i].SpiceGradeID, snapshots[i].Currency, now)
+		if err != nil {
+			return nil, err
+		}
+		snapshots[i].TodayPrice = price
+		snapshots[i].Valuation = valuation
+
+		before := startOfDay(now)
+		if !valuation.Date.IsZero() {
+			before = valuation.Date
+		}
+		previous, currency, date, err := s.repository.GetLatestDailyPrice(ctx, snapshots[i].SpiceGradeID, time.Time{}, before.AddDate(0, 0, -1))
+		if errors.Is(err, ErrNoPriceAvailable) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		previous, ok, err := s.convertPrice(ctx, previous, currency, snapshots[i].Currency, date)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			snapshots[i].PreviousPrice = previous
+		}
+	}
+	return snapshots, nil
+}
+
+// valuePrice finds the daily price that values a grade at now under the service's policy,
+// converted into currency at the rate of the price's date. TODAY accepts only today's price;
+// LAST_AVAILABLE falls back to the newest within MaxAgeDays and PREVIOUS_CLOSE starts from
+// yesterday's close. When nothing qualifies, or no FX rate converts the price, it returns a
+// zero price and Valuation.
+func (s *MarketService) valuePrice(ctx context.Context, spiceGradeID string, currency string, now time.Time) (decimal.Decimal, Valuation, error) {
+	today := startOfDay(now)
+	target := today
+	if s.valuation.Mode == ValuationPreviousClose {
+		target = today.AddDate(0, 0, -1)
+	}
+	from := target
+	if s.valuation.Mode != ValuationToday {
+		from = time.Time{}
+		if s.valuation.MaxAgeDays > 0 {
+			from = target.AddDate(0, 0, -s.valuation.MaxAgeDays)
+		}
+	}
+
+	price, priceCurrency, date, err := s.repository.GetLatestDailyPrice(ctx, spiceGradeID, from, target)
+	if errors.Is(err, ErrNoPriceAvailable) {
+		return decimal.Zero, Valuation{}, nil
+	}
+	if err != nil {
+		return decimal.Zero, Valuation{}, err
+	}
+	price, ok, err := s.convertPrice(ctx, price, priceCurrency, currency, date)
+	if err != nil || !ok {
+		return decimal.Zero, Valuation{}, err
+	}
+
+	date = startOfDay(date)
+	valuation := Valuation{Date: date, Source: PriceBasisClose, Stale: date.Before(target)}
+	if date.Equal(today) {
+		valuation.Source = PriceBasisLast
+	}
+	return price, valuation, nil
+}
+
+// convertPrice converts a unit price between currencies at the rate effective on date. ok is
+// false when no rate is stored for the pair.
+func (s *MarketService) convertPrice(ctx context.Context, price decimal.Decimal, from, to string, date time.Time) (decimal.Decimal, bool, error) {
+	if from == to {
+		return price, true, nil
+	}
+	rate, err := s.repository.GetFxRate(ctx, from, to, date)
+	if errors.Is(err, ErrNoFxRate) {
+		return decimal.Zero, false, nil
+	}
+	if err != nil {
+		return decimal.Zero, false, err
+	}
+	return util.RoundPrice(price.Mul(rate)), true, nil
+}
+
+// startOfDay is t's calendar date at midnight UTC, the form daily_price dates are read in.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 }
@@ -0,0 +1,138 @@
+package market
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func allowShorts(repo *fakeRepository) {
+	repo.permissions[testUser] = &TradingPermissions{UserID: testUser, AllowShortSelling: true}
+}
+
+func TestCoverShortLots(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(t *testing.T, s *MarketService)
+		buyQty    string
+		buyPrice  string
+		want      wantPosition
+		covers    []string // quantity of each cover, oldest short first
+		openShort string   // short quantity left open
+		lotQty    string   // inventory lot opened by the buy; empty for none
+	}{
+		{
+			name:      "buy covers the short exactly",
+			setup:     func(t *testing.T, s *MarketService) { mustSell(t, s, "10", "150", day(1), SellOptions{}) },
+			buyQty:    "10",
+			buyPrice:  "120",
+			want:      wantPosition{"0", "0", "300"},
+			covers:    []string{"10"},
+			openShort: "0",
+		},
+		{
+			name:      "partial cover leaves the rest short at the short price",
+			setup:     func(t *testing.T, s *MarketService) { mustSell(t, s, "10", "150", day(1), SellOptions{}) },
+			buyQty:    "4",
+			buyPrice:  "120",
+			want:      wantPosition{"-6", "-900", "120"},
+			covers:    []string{"4"},
+			openShort: "6",
+		},
+		{
+			name:      "buy beyond the short opens a lot for the rest",
+			setup:     func(t *testing.T, s *MarketService) { mustSell(t, s, "10", "150", day(1), SellOptions{}) },
+			buyQty:    "15",
+			buyPrice:  "120",
+			want:      wantPosition{"5", "600", "300"},
+			covers:    []string{"10"},
+			openShort: "0",
+			lotQty:    "5",
+		},
+		{
+			name: "oldest short is covered first",
+			setup: func(t *testing.T, s *MarketService) {
+				mustSell(t, s, "5", "140", day(2), SellOptions{})
+				mustSell(t, s, "5", "150", day(1), SellOptions{})
+			},
+			buyQty:    "7",
+			buyPrice:  "100",
+			want:      wantPosition{"-3", "-420", "330"},
+			covers:    []string{"5", "2"},
+			openShort: "3",
+		},
+		{
+			name: "covering at a loss",
+			setup: func(t *testing.T, s *MarketService) {
+				mustBuy(t, s, "5", "100", day(1))
+				mustSell(t, s, "8", "150", day(2), SellOptions{})
+			},
+			buyQty:    "3",
+			buyPrice:  "160",
+			want:      wantPosition{"0", "0", "220"},
+			covers:    []string{"3"},
+			openShort: "0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestService()
+			allowShorts(repo)
+			tt.setup(t, s)
+
+			buy := mustBuy(t, s, tt.buyQty, tt.buyPrice, day(5))
+			checkPosition(t, repo, tt.want)
+
+			covers, err := repo.ListActiveCoversByBuy(context.Background(), buy.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(covers) != len(tt.covers) {
+				t.Fatalf("got %d covers, want %d", len(covers), len(tt.covers))
+			}
+			for i, c := range covers {
+				if !c.Quantity.Equal(dec(tt.covers[i])) || !c.CoverPrice.Equal(dec(tt.buyPrice)) {
+					t.Errorf("cover %d = %s @ %s, want %s @ %s", i, c.Quantity, c.CoverPrice, tt.covers[i], tt.buyPrice)
+				}
+				if !c.RealizedPnL.Equal(c.Quantity.Mul(c.ShortPrice.Sub(c.CoverPrice))) {
+					t.Errorf("cover %d pnl = %s", i, c.RealizedPnL)
+				}
+			}
+
+			shorts, _ := repo.GetOpenShortLots(context.Background(), testUser, testGrade)
+			open := decimal.Zero
+			for _, l := range shorts {
+				open = open.Add(l.RemainingQty)
+			}
+			if !open.Equal(dec(tt.openShort)) {
+				t.Errorf("open short quantity = %s, want %s", open, tt.openShort)
+			}
+
+			lotQty := ""
+			for _, l := range repo.lots {
+				if l.TransactionID == buy.ID {
+					lotQty = l.OriginalQty.String()
+				}
+			}
+			if lotQty != tt.lotQty {
+				t.Errorf("buy lot quantity = %q, want %q", lotQty, tt.lotQty)
+			}
+		})
+	}
+}
+
+func TestShortSellNeedsPermission(t *testing.T) {
+	s, repo := newTestService()
+	mustBuy(t, s, "5", "100", day(1))
+
+	_, err := s.Sell(context.Background(), testUser, testGrade, dec("8"), dec("150"), "", day(2), "", SellOptions{})
+	if err == nil || !strings.Contains(err.Error(), "insufficient inventory") {
+		t.Fatalf("err = %v, want insufficient inventory", err)
+	}
+	checkPosition(t, repo, wantPosition{"5", "500", "0"})
+	if len(repo.shorts) != 0 {
+		t.Errorf("%d short lots opened", len(repo.shorts))
+	}
+}
@@ -0,0 +1,27 @@
+-- +goose Up
+ALTER TABLE transactions
+  ADD COLUMN cost_basis_method ENUM('FIFO','LIFO','WEIGHTED_AVERAGE','SPECIFIC_LOT') NULL AFTER price;
+
+ALTER TABLE sell_allocations
+  ADD COLUMN cost_basis_method ENUM('FIFO','LIFO','WEIGHTED_AVERAGE','SPECIFIC_LOT') NOT NULL DEFAULT 'FIFO' AFTER realized_pnl;
+
+-- Every sell booked before this migration was matched FIFO.
+UPDATE transactions SET cost_basis_method = 'FIFO' WHERE type = 'SELL' AND cost_basis_method IS NULL;
+
+-- Default method per account (spice_grade_id = '') with optional per-grade overrides.
+CREATE TABLE IF NOT EXISTS cost_basis_preferences (
+  user_id        CHAR(27)     NOT NULL,
+  spice_grade_id VARCHAR(27)  NOT NULL DEFAULT '',
+  method         ENUM('FIFO','LIFO','WEIGHTED_AVERAGE') NOT NULL,
+  updated_at     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (user_id, spice_grade_id)
+) ENGINE=InnoDB;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (7, 'cost_basis_methods', 'Cost-basis method on sells and sell_allocations; cost_basis_preferences table');
+
+-- +goose Down
+DROP TABLE IF EXISTS cost_basis_preferences;
+ALTER TABLE sell_allocations DROP COLUMN cost_basis_method;
+ALTER TABLE transactions DROP COLUMN cost_basis_method;
@@ -0,0 +1,32 @@
+-- +goose Up
+ALTER TABLE transactions
+  MODIFY COLUMN type ENUM('BUY','SELL','REVERSAL') NOT NULL,
+  ADD COLUMN status                  ENUM('ACTIVE','CANCELLED') NOT NULL DEFAULT 'ACTIVE' AFTER cost_basis_method,
+  ADD COLUMN reverses_transaction_id CHAR(27)     NULL AFTER status,
+  ADD COLUMN amends_transaction_id   CHAR(27)     NULL AFTER reverses_transaction_id,
+  ADD COLUMN note                    VARCHAR(255) NULL AFTER amends_transaction_id,
+  ADD INDEX idx_txn_reverses (reverses_transaction_id),
+  ADD INDEX idx_txn_amends (amends_transaction_id);
+
+-- Allocations and lots are never deleted; a cancellation stamps the REVERSAL that undid them.
+ALTER TABLE sell_allocations
+  ADD COLUMN reversed_by_transaction_id CHAR(27) NULL AFTER cost_basis_method;
+
+ALTER TABLE buy_lots
+  ADD COLUMN reversed_by_transaction_id CHAR(27) NULL AFTER remaining_qty;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (8, 'transaction_reversals', 'Trade status, REVERSAL rows and amendment links; reversed markers on lots and allocations');
+
+-- +goose Down
+ALTER TABLE buy_lots DROP COLUMN reversed_by_transaction_id;
+ALTER TABLE sell_allocations DROP COLUMN reversed_by_transaction_id;
+DELETE FROM transactions WHERE type = 'REVERSAL';
+ALTER TABLE transactions
+  DROP INDEX idx_txn_amends,
+  DROP INDEX idx_txn_reverses,
+  DROP COLUMN note,
+  DROP COLUMN amends_transaction_id,
+  DROP COLUMN reverses_transaction_id,
+  DROP COLUMN status,
+  MODIFY COLUMN type ENUM('BUY','SELL') NOT NULL;
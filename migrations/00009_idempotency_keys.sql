@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE transactions
+  ADD COLUMN idempotency_key VARCHAR(128) NULL AFTER note,
+  ADD UNIQUE KEY uq_txn_user_idempotency (user_id, idempotency_key);
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (9, 'idempotency_keys', 'Client idempotency keys on transactions, unique per user');
+
+-- +goose Down
+ALTER TABLE transactions
+  DROP INDEX uq_txn_user_idempotency,
+  DROP COLUMN idempotency_key;
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE grade
+  ADD COLUMN shelf_life_days INT UNSIGNED NULL AFTER description;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (10, 'grade_shelf_life', 'Optional shelf life in days per grade for lot ageing');
+
+-- +goose Down
+ALTER TABLE grade
+  DROP COLUMN shelf_life_days;
@@ -0,0 +1,61 @@
+-- +goose Up
+-- Per-account trading capabilities; short selling is off unless an admin enables it.
+CREATE TABLE IF NOT EXISTS trading_permissions (
+  user_id             CHAR(27)  PRIMARY KEY,
+  allow_short_selling BOOLEAN   NOT NULL DEFAULT FALSE,
+  updated_by          CHAR(27)  NULL,
+  updated_at          DATETIME  NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
+
+-- The uncovered part of a SELL, at the sell price; later BUYs cover it oldest first.
+CREATE TABLE IF NOT EXISTS short_lots (
+  id                         CHAR(27)      PRIMARY KEY,
+  transaction_id             CHAR(27)      NOT NULL,
+  user_id                    CHAR(27)      NOT NULL,
+  spice_grade_id             CHAR(27)      NOT NULL,
+  original_qty               DECIMAL(15,4) NOT NULL CHECK (original_qty > 0),
+  remaining_qty              DECIMAL(15,4) NOT NULL CHECK (remaining_qty >= 0),
+  reversed_by_transaction_id CHAR(27)      NULL,
+  price                      DECIMAL(15,4) NOT NULL CHECK (price > 0),
+  trade_date                 DATE          NOT NULL,
+  created_at                 DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  INDEX idx_short_lots_fifo (user_id, spice_grade_id, remaining_qty, trade_date, id),
+
+  CONSTRAINT fk_short_lot_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(id)
+) ENGINE=InnoDB;
+
+-- One pairing between a covering BUY and a short lot, with the P&L it realized.
+CREATE TABLE IF NOT EXISTS short_covers (
+  id                         CHAR(27)      PRIMARY KEY,
+  buy_transaction_id         CHAR(27)      NOT NULL,
+  short_lot_id               CHAR(27)      NOT NULL,
+  quantity                   DECIMAL(15,4) NOT NULL CHECK (quantity > 0),
+  short_price                DECIMAL(15,4) NOT NULL,
+  cover_price                DECIMAL(15,4) NOT NULL,
+  realized_pnl               DECIMAL(15,4) NOT NULL,
+  reversed_by_transaction_id CHAR(27)      NULL,
+  created_at                 DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  INDEX idx_cover_buy (buy_transaction_id),
+  INDEX idx_cover_short_lot (short_lot_id),
+
+  CONSTRAINT fk_cover_buy       FOREIGN KEY (buy_transaction_id) REFERENCES transactions(id),
+  CONSTRAINT fk_cover_short_lot FOREIGN KEY (short_lot_id)       REFERENCES short_lots(id)
+) ENGINE=InnoDB;
+
+-- A short position carries negative quantity and cost (the proceeds still to be covered).
+ALTER TABLE positions
+  DROP CHECK positions_chk_1,
+  DROP CHECK positions_chk_2;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (11, 'short_selling', 'Opt-in short selling: trading_permissions, short_lots and short_covers; signed positions');
+
+-- +goose Down
+ALTER TABLE positions
+  ADD CONSTRAINT positions_chk_1 CHECK (total_qty >= 0),
+  ADD CONSTRAINT positions_chk_2 CHECK (total_cost >= 0);
+DROP TABLE IF EXISTS short_covers;
+DROP TABLE IF EXISTS short_lots;
+DROP TABLE IF EXISTS trading_permissions;
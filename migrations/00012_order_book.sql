@@ -0,0 +1,54 @@
+-- +goose Up
+-- One row per grade with a book; placing or amending an order locks it so matching is serialized per grade.
+CREATE TABLE IF NOT EXISTS order_books (
+  spice_grade_id CHAR(27) PRIMARY KEY,
+  updated_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
+
+-- Limit orders. priority_at orders resting orders at the same price; it is reset when an
+-- amendment raises the quantity or changes the price.
+CREATE TABLE IF NOT EXISTS orders (
+  id             CHAR(27)      PRIMARY KEY,
+  user_id        CHAR(27)      NOT NULL,
+  spice_grade_id CHAR(27)      NOT NULL,
+  side           ENUM('BUY','SELL') NOT NULL,
+  price          DECIMAL(15,4) NOT NULL CHECK (price > 0),
+  quantity       DECIMAL(15,4) NOT NULL CHECK (quantity > 0),
+  remaining_qty  DECIMAL(15,4) NOT NULL CHECK (remaining_qty >= 0),
+  status         ENUM('OPEN','PARTIALLY_FILLED','FILLED','CANCELLED') NOT NULL DEFAULT 'OPEN',
+  priority_at    DATETIME(6)   NOT NULL,
+  created_at     DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at     DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+  INDEX idx_orders_book (spice_grade_id, side, status, price, priority_at, id),
+  INDEX idx_orders_user (user_id, created_at)
+) ENGINE=InnoDB;
+
+-- One match between a BUY and a SELL order, with the BUY and SELL transactions it booked.
+CREATE TABLE IF NOT EXISTS order_fills (
+  id                  CHAR(27)      PRIMARY KEY,
+  spice_grade_id      CHAR(27)      NOT NULL,
+  buy_order_id        CHAR(27)      NOT NULL,
+  sell_order_id       CHAR(27)      NOT NULL,
+  buy_transaction_id  CHAR(27)      NOT NULL,
+  sell_transaction_id CHAR(27)      NOT NULL,
+  quantity            DECIMAL(15,4) NOT NULL CHECK (quantity > 0),
+  price               DECIMAL(15,4) NOT NULL CHECK (price > 0),
+  created_at          DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  INDEX idx_fill_buy_order (buy_order_id),
+  INDEX idx_fill_sell_order (sell_order_id),
+
+  CONSTRAINT fk_fill_buy_order  FOREIGN KEY (buy_order_id)        REFERENCES orders(id),
+  CONSTRAINT fk_fill_sell_order FOREIGN KEY (sell_order_id)       REFERENCES orders(id),
+  CONSTRAINT fk_fill_buy_txn    FOREIGN KEY (buy_transaction_id)  REFERENCES transactions(id),
+  CONSTRAINT fk_fill_sell_txn   FOREIGN KEY (sell_transaction_id) REFERENCES transactions(id)
+) ENGINE=InnoDB;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (12, 'order_book', 'Limit orders with price-time matching: order_books, orders, order_fills');
+
+-- +goose Down
+DROP TABLE IF EXISTS order_fills;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS order_books;
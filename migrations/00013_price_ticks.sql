@@ -0,0 +1,47 @@
+-- +goose Up
+-- Every published price, kept with its timestamp, source and publisher. Ticks are never
+-- updated; daily_price is rebuilt from them whenever one is added.
+CREATE TABLE IF NOT EXISTS price_ticks (
+  id           CHAR(27)      PRIMARY KEY,
+  product_id   CHAR(27)      NOT NULL,
+  grade_id     CHAR(27)      NOT NULL,
+  price        DECIMAL(15,4) NOT NULL CHECK (price > 0),
+  source       VARCHAR(32)   NOT NULL DEFAULT 'MANUAL',
+  published_by CHAR(27)      NULL,
+  tick_date    DATE          NOT NULL,
+  ticked_at    DATETIME(6)   NOT NULL,
+  created_at   DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  FOREIGN KEY (product_id) REFERENCES products(id),
+  FOREIGN KEY (grade_id) REFERENCES grade(id),
+  INDEX idx_price_ticks_day (grade_id, tick_date, ticked_at)
+) ENGINE=InnoDB;
+
+-- daily_price becomes the per-day rollup of the ticks: price is the last tick, time its time.
+ALTER TABLE daily_price
+  MODIFY COLUMN price DECIMAL(15,4) NOT NULL,
+  ADD COLUMN open_price DECIMAL(15,4) NULL AFTER price,
+  ADD COLUMN high_price DECIMAL(15,4) NULL AFTER open_price,
+  ADD COLUMN low_price  DECIMAL(15,4) NULL AFTER high_price,
+  ADD COLUMN tick_count INT UNSIGNED NOT NULL DEFAULT 0 AFTER low_price;
+
+-- Existing prices become the only tick of their day.
+INSERT IGNORE INTO price_ticks (id, product_id, grade_id, price, source, tick_date, ticked_at)
+SELECT id, product_id, grade_id, price, 'MIGRATED', date, TIMESTAMP(date, time)
+FROM daily_price;
+
+UPDATE daily_price
+SET open_price = price, high_price = price, low_price = price, tick_count = 1;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (13, 'price_ticks', 'Intraday price ticks; daily_price holds the open/high/low/last rollup');
+
+-- +goose Down
+ALTER TABLE daily_price
+  DROP COLUMN tick_count,
+  DROP COLUMN low_price,
+  DROP COLUMN high_price,
+  DROP COLUMN open_price,
+  MODIFY COLUMN price DECIMAL(10,2) NOT NULL;
+
+DROP TABLE IF EXISTS price_ticks;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Maker-checker on published prices. A tick is proposed (DRAFT or SUBMITTED) by one admin and
+-- only counts towards daily_price once a different admin has APPROVED it. published_by is the
+-- proposer; reviewed_by/reviewed_at record who approved or rejected it and when.
+ALTER TABLE price_ticks
+  ADD COLUMN status       ENUM('DRAFT','SUBMITTED','APPROVED','REJECTED') NOT NULL DEFAULT 'APPROVED' AFTER published_by,
+  ADD COLUMN submitted_at DATETIME(6)  NULL AFTER status,
+  ADD COLUMN reviewed_by  CHAR(27)     NULL AFTER submitted_at,
+  ADD COLUMN reviewed_at  DATETIME(6)  NULL AFTER reviewed_by,
+  ADD COLUMN review_note  VARCHAR(255) NULL AFTER reviewed_at,
+  ADD INDEX idx_price_ticks_status (status, ticked_at);
+
+-- Ticks published before the workflow existed were visible already; they stay approved.
+UPDATE price_ticks SET submitted_at = ticked_at, reviewed_at = ticked_at;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (14, 'price_approvals', 'Draft/submitted/approved/rejected price ticks; only approved ticks roll up into daily_price');
+
+-- +goose Down
+ALTER TABLE price_ticks
+  DROP INDEX idx_price_ticks_status,
+  DROP COLUMN review_note,
+  DROP COLUMN reviewed_at,
+  DROP COLUMN reviewed_by,
+  DROP COLUMN submitted_at,
+  DROP COLUMN status;
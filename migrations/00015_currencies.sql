@@ -0,0 +1,59 @@
+-- +goose Up
+-- Amounts carry an ISO 4217 currency. Accounts trade in currency and see positions and
+-- dashboards converted into reporting_currency; everything booked before this was rupees.
+ALTER TABLE accounts
+  ADD COLUMN currency           CHAR(3) NOT NULL DEFAULT 'INR',
+  ADD COLUMN reporting_currency CHAR(3) NOT NULL DEFAULT 'INR';
+
+ALTER TABLE price_ticks
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR' AFTER price;
+
+ALTER TABLE daily_price
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR' AFTER price;
+
+ALTER TABLE transactions
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR' AFTER price;
+
+-- A position is held in one currency; trades in another are refused while it is open.
+ALTER TABLE positions
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR' AFTER spice_grade_id;
+
+-- Orders only match orders of the same currency.
+ALTER TABLE orders
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR' AFTER price,
+  DROP INDEX idx_orders_book,
+  ADD INDEX idx_orders_book (spice_grade_id, currency, side, status, price, priority_at, id);
+
+-- FX rates maintained by admins: 1 base_currency = rate quote_currency from effective_date
+-- until a later rate for the pair. A pair is also read inverted.
+CREATE TABLE IF NOT EXISTS fx_rates (
+  id             CHAR(27)      PRIMARY KEY,
+  base_currency  CHAR(3)       NOT NULL,
+  quote_currency CHAR(3)       NOT NULL,
+  rate           DECIMAL(20,8) NOT NULL CHECK (rate > 0),
+  effective_date DATE          NOT NULL,
+  updated_by     CHAR(27)      NULL,
+  updated_at     DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+  UNIQUE KEY uq_fx_rates_pair_date (base_currency, quote_currency, effective_date)
+) ENGINE=InnoDB;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (15, 'currencies', 'Currency on accounts, prices, trades, positions and orders; fx_rates');
+
+-- +goose Down
+DROP TABLE IF EXISTS fx_rates;
+
+ALTER TABLE orders
+  DROP INDEX idx_orders_book,
+  ADD INDEX idx_orders_book (spice_grade_id, side, status, price, priority_at, id),
+  DROP COLUMN currency;
+
+ALTER TABLE positions DROP COLUMN currency;
+ALTER TABLE transactions DROP COLUMN currency;
+ALTER TABLE daily_price DROP COLUMN currency;
+ALTER TABLE price_ticks DROP COLUMN currency;
+
+ALTER TABLE accounts
+  DROP COLUMN reporting_currency,
+  DROP COLUMN currency;
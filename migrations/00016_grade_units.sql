@@ -0,0 +1,15 @@
+-- +goose Up
+-- Ledger quantities stay in kilograms, the base unit. A grade names the unit it is quoted and
+-- traded in and how many kilograms one unit holds (fixed for QUINTAL and TONNE, per grade for
+-- BAG); trades entered in that unit are converted to kilograms before they are booked.
+ALTER TABLE grade
+  ADD COLUMN unit        VARCHAR(16)   NOT NULL DEFAULT 'KG' AFTER shelf_life_days,
+  ADD COLUMN kg_per_unit DECIMAL(15,4) NOT NULL DEFAULT 1.0000 AFTER unit;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (16, 'grade_units', 'Unit of measure and kilograms per unit per grade');
+
+-- +goose Down
+ALTER TABLE grade
+  DROP COLUMN kg_per_unit,
+  DROP COLUMN unit;
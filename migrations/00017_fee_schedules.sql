@@ -0,0 +1,55 @@
+-- +goose Up
+-- Fees and taxes charged on trades, set per grade or per product category. A schedule applies
+-- from effective_date until a later one with the same scope, code and side; on a trade a grade
+-- schedule overrides a category schedule of the same code. rate is a percentage of the trade
+-- value (PERCENT) or an amount in currency per kilogram (PER_KG) or per trade (FLAT); 0 stops
+-- charging it.
+CREATE TABLE IF NOT EXISTS fee_schedules (
+  id             CHAR(27)      PRIMARY KEY,
+  grade_id       CHAR(27)      NOT NULL DEFAULT '',
+  category       VARCHAR(16)   NOT NULL DEFAULT '',
+  code           VARCHAR(32)   NOT NULL,
+  kind           ENUM('FEE','TAX') NOT NULL,
+  side           ENUM('BUY','SELL','BOTH') NOT NULL,
+  basis          ENUM('PERCENT','PER_KG','FLAT') NOT NULL,
+  rate           DECIMAL(15,4) NOT NULL CHECK (rate >= 0),
+  currency       CHAR(3)       NOT NULL DEFAULT 'INR',
+  effective_date DATE          NOT NULL,
+  updated_by     CHAR(27)      NULL,
+  updated_at     DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+  CHECK ((grade_id = '') <> (category = '')),
+  UNIQUE KEY uq_fee_schedules_scope (grade_id, category, code, side, effective_date)
+) ENGINE=InnoDB;
+
+-- Fees charged on a trade, one line per schedule, in the trade's currency. transactions.fees
+-- is their sum: capitalised into the lot cost of a BUY, deducted from the realized P&L of a SELL.
+ALTER TABLE transactions
+  ADD COLUMN fees DECIMAL(15,4) NOT NULL DEFAULT 0 AFTER currency;
+
+CREATE TABLE IF NOT EXISTS transaction_fees (
+  id              CHAR(27)      PRIMARY KEY,
+  transaction_id  CHAR(27)      NOT NULL,
+  fee_schedule_id CHAR(27)      NOT NULL,
+  code            VARCHAR(32)   NOT NULL,
+  kind            ENUM('FEE','TAX') NOT NULL,
+  basis           ENUM('PERCENT','PER_KG','FLAT') NOT NULL,
+  rate            DECIMAL(15,4) NOT NULL,
+  amount          DECIMAL(15,4) NOT NULL CHECK (amount >= 0),
+  currency        CHAR(3)       NOT NULL,
+  created_at      DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  INDEX idx_transaction_fees_txn (transaction_id),
+
+  CONSTRAINT fk_transaction_fees_txn FOREIGN KEY (transaction_id) REFERENCES transactions(id)
+) ENGINE=InnoDB;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (17, 'fee_schedules', 'Fee and tax schedules per grade or category; fee lines on transactions');
+
+-- +goose Down
+DROP TABLE IF EXISTS transaction_fees;
+
+ALTER TABLE transactions DROP COLUMN fees;
+
+DROP TABLE IF EXISTS fee_schedules;
@@ -0,0 +1,49 @@
+-- +goose Up
+-- Refresh tokens are kept as SHA-256 hashes, one row per token. A login starts a family and
+-- each refresh rotates the family's token; a rotated token that comes back means it was copied,
+-- so the family and the device's sessions are revoked.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+  id            CHAR(27)    PRIMARY KEY,
+  family_id     CHAR(27)    NOT NULL,
+  parent_id     CHAR(27)    NULL,
+  account_id    CHAR(27)    NOT NULL,
+  device_id     CHAR(27)    NOT NULL,
+  token_hash    CHAR(64)    NOT NULL,
+  expires_at    DATETIME    NOT NULL,
+  created_at    DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  rotated_at    DATETIME    NULL,
+  revoked_at    DATETIME    NULL,
+  revoke_reason VARCHAR(16) NULL,
+
+  UNIQUE KEY uq_refresh_tokens_hash (token_hash),
+  KEY idx_refresh_tokens_family (family_id),
+  KEY idx_refresh_tokens_device (account_id, device_id),
+  FOREIGN KEY (account_id) REFERENCES accounts(id)
+) ENGINE=InnoDB;
+
+ALTER TABLE sessions ADD COLUMN family_id CHAR(27) NULL AFTER device_id;
+
+-- Each open session becomes a family of one, so signed-in devices keep refreshing.
+UPDATE sessions SET family_id = id;
+
+INSERT IGNORE INTO refresh_tokens (id, family_id, account_id, device_id, token_hash, expires_at, created_at, revoked_at, revoke_reason)
+SELECT id, id, account_id, device_id, SHA2(refresh_token, 256), expires_at, created_at,
+       IF(is_revoked = 1, CURRENT_TIMESTAMP, NULL), IF(is_revoked = 1, 'LOGOUT', NULL)
+FROM sessions;
+
+ALTER TABLE sessions
+  MODIFY family_id CHAR(27) NOT NULL,
+  DROP COLUMN refresh_token;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (18, 'refresh_token_families', 'Hashed refresh_tokens in rotation families; sessions.family_id replaces sessions.refresh_token');
+
+-- +goose Down
+-- Plaintext tokens cannot be recovered, so every device signs in again.
+ALTER TABLE sessions ADD COLUMN refresh_token TEXT NULL AFTER access_token;
+UPDATE sessions SET refresh_token = '', is_revoked = 1;
+ALTER TABLE sessions
+  MODIFY refresh_token TEXT NOT NULL,
+  DROP COLUMN family_id;
+
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- What a session is used from, so an account can tell its devices apart and sign them out.
+-- last_seen_at moves on login, refresh and control calls, at most once a minute.
+ALTER TABLE sessions
+  ADD COLUMN device_name  VARCHAR(100) NULL AFTER device_id,
+  ADD COLUMN ip_address   VARCHAR(45)  NULL AFTER access_token,
+  ADD COLUMN user_agent   VARCHAR(255) NULL AFTER ip_address,
+  ADD COLUMN last_seen_at DATETIME     NULL AFTER created_at;
+
+UPDATE sessions SET last_seen_at = created_at;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (19, 'session_devices', 'Device name, IP address, user agent and last_seen_at on sessions');
+
+-- +goose Down
+ALTER TABLE sessions
+  DROP COLUMN last_seen_at,
+  DROP COLUMN user_agent,
+  DROP COLUMN ip_address,
+  DROP COLUMN device_name;
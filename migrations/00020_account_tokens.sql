@@ -0,0 +1,32 @@
+-- +goose Up
+-- Single-use tokens mailed to an account: password resets and email verification. Only the
+-- SHA-256 of a token is stored. email is the address a verification token was sent to, so
+-- changing the email first makes the token useless.
+CREATE TABLE IF NOT EXISTS account_tokens (
+  id          CHAR(27)     PRIMARY KEY,
+  account_id  CHAR(27)     NOT NULL,
+  purpose     VARCHAR(24)  NOT NULL,
+  email       VARCHAR(255) NOT NULL,
+  token_hash  CHAR(64)     NOT NULL,
+  expires_at  DATETIME     NOT NULL,
+  created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  used_at     DATETIME     NULL,
+
+  UNIQUE KEY uq_account_tokens_hash (token_hash),
+  KEY idx_account_tokens_account (account_id, purpose),
+  FOREIGN KEY (account_id) REFERENCES accounts(id)
+) ENGINE=InnoDB;
+
+ALTER TABLE accounts ADD COLUMN email_verified_at DATETIME NULL AFTER email;
+
+-- Seeded accounts are trusted.
+UPDATE accounts SET email_verified_at = CURRENT_TIMESTAMP
+WHERE id IN ('acc_admin_00000000000000001', 'acc_merchant_00000000000001');
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (20, 'account_tokens', 'Hashed password reset and email verification tokens; accounts.email_verified_at');
+
+-- +goose Down
+ALTER TABLE accounts DROP COLUMN email_verified_at;
+
+DROP TABLE IF EXISTS account_tokens;
@@ -0,0 +1,52 @@
+-- +goose Up
+-- TOTP two-factor authentication. The secret is stored AES-GCM encrypted with
+-- TOTP_ENCRYPTION_KEY; last_used_step stops a code from being replayed. An enrolment counts
+-- once confirmed_at is set.
+CREATE TABLE IF NOT EXISTS account_totp (
+  account_id     CHAR(27)     PRIMARY KEY,
+  secret         VARCHAR(255) NOT NULL,
+  confirmed_at   DATETIME     NULL,
+  last_used_step BIGINT       NULL,
+  created_at     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  FOREIGN KEY (account_id) REFERENCES accounts(id)
+) ENGINE=InnoDB;
+
+-- One-time recovery codes, stored as SHA-256 hashes.
+CREATE TABLE IF NOT EXISTS recovery_codes (
+  id         CHAR(27) PRIMARY KEY,
+  account_id CHAR(27) NOT NULL,
+  code_hash  CHAR(64) NOT NULL,
+  used_at    DATETIME NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  UNIQUE KEY uq_recovery_codes_account_hash (account_id, code_hash),
+  FOREIGN KEY (account_id) REFERENCES accounts(id)
+) ENGINE=InnoDB;
+
+-- The second login step. A password login of an account with 2FA (or an admin that must
+-- enrol) gets a challenge token instead of a session; purpose is VERIFY or ENROLL.
+CREATE TABLE IF NOT EXISTS login_challenges (
+  id          CHAR(27)     PRIMARY KEY,
+  account_id  CHAR(27)     NOT NULL,
+  device_id   CHAR(27)     NOT NULL,
+  device_name VARCHAR(100) NULL,
+  purpose     VARCHAR(8)   NOT NULL,
+  token_hash  CHAR(64)     NOT NULL,
+  attempts    INT          NOT NULL DEFAULT 0,
+  expires_at  DATETIME     NOT NULL,
+  created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  used_at     DATETIME     NULL,
+
+  UNIQUE KEY uq_login_challenges_hash (token_hash),
+  KEY idx_login_challenges_account (account_id),
+  FOREIGN KEY (account_id) REFERENCES accounts(id)
+) ENGINE=InnoDB;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (21, 'two_factor', 'TOTP enrolment (account_totp), recovery_codes and login_challenges');
+
+-- +goose Down
+DROP TABLE IF EXISTS login_challenges;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS account_totp;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Cost a weighted-average sell released beyond quantity × buy price when it closed the
+-- position, kept on its last allocation so a cancellation or a replay can undo it.
+ALTER TABLE sell_allocations
+  ADD COLUMN close_adjustment DECIMAL(15,4) NOT NULL DEFAULT 0 AFTER realized_pnl;
+
+INSERT IGNORE INTO schema_migrations (version, name, description)
+VALUES (22, 'sell_close_adjustment', 'Weighted-average closing difference on sell_allocations');
+
+-- +goose Down
+ALTER TABLE sell_allocations DROP COLUMN close_adjustment;
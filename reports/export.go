@@ -0,0 +1,236 @@
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/Asif-Faizal/SpiceLedger-Backend/market/pb"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+)
+
+// exportFlushRows is how many rows are buffered before they are pushed to the client.
+const exportFlushRows = 500
+
+// exportColumn is one column of a ledger export and how to read it from a streamed row.
+type exportColumn struct {
+	name    string
+	numeric bool
+	value   func(*pb.ExportLedgerRow) string
+}
+
+func text(name string, value func(*pb.ExportLedgerRow) string) exportColumn {
+	return exportColumn{name: name, value: value}
+}
+
+func number(name string, value func(*pb.ExportLedgerRow) string) exportColumn {
+	return exportColumn{name: name, numeric: true, value: value}
+}
+
+var (
+	productColumn = text("product", func(r *pb.ExportLedgerRow) string { return r.ProductName })
+	gradeColumn   = text("grade", func(r *pb.ExportLedgerRow) string { return r.GradeName })
+)
+
+// ledgerExport describes one export: the market record kind, the sheet name and the columns.
+type ledgerExport struct {
+	kind    string
+	sheet   string
+	columns []exportColumn
+}
+
+var (
+	transactionsExport = ledgerExport{kind: "TRANSACTIONS", sheet: "Transactions", columns: []exportColumn{
+		text("trade_date", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetTradeDate() }),
+		text("transaction_id", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetId() }),
+		text("type", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetType() }),
+		text("status", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetStatus() }),
+		productColumn,
+		gradeColumn,
+		text("spice_grade_id", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetSpiceGradeId() }),
+		number("quantity_kg", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetQuantity() }),
+		number("price", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetPrice() }),
+		number("fees", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetFeeTotal() }),
+		text("currency", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetCurrency() }),
+		text("cost_basis_method", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetCostBasisMethod() }),
+		text("reverses_transaction_id", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetReversesTransactionId() }),
+		text("amends_transaction_id", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetAmendsTransactionId() }),
+		text("note", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetNote() }),
+		text("idempotency_key", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetIdempotencyKey() }),
+		text("created_at", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetCreatedAt() }),
+		text("user_id", func(r *pb.ExportLedgerRow) string { return r.GetTransaction().GetUserId() }),
+	}}
+
+	lotsExport = ledgerExport{kind: "LOTS", sheet: "Lots", columns: []exportColumn{
+		text("trade_date", func(r *pb.ExportLedgerRow) string { return r.GetLot().GetTradeDate() }),
+		text("lot_id", func(r *pb.ExportLedgerRow) string { return r.GetLot().GetId() }),
+		text("transaction_id", func(r *pb.ExportLedgerRow) string { return r.GetLot().GetTransactionId() }),
+		productColumn,
+		gradeColumn,
+		text("spice_grade_id", func(r *pb.ExportLedgerRow) string { return r.GetLot().GetSpiceGradeId() }),
+		number("original_qty_kg", func(r *pb.ExportLedgerRow) string { return r.GetLot().GetOriginalQty() }),
+		number("remaining_qty_kg", func(r *pb.ExportLedgerRow) string { return r.GetLot().GetRemainingQty() }),
+		number("price", func(r *pb.ExportLedgerRow) string { return r.GetLot().GetPrice() }),
+		text("created_at", func(r *pb.ExportLedgerRow) string { return r.GetLot().GetCreatedAt() }),
+		text("user_id", func(r *pb.ExportLedgerRow) string { return r.GetLot().GetUserId() }),
+	}}
+
+	allocationsExport = ledgerExport{kind: "ALLOCATIONS", sheet: "Allocations", columns: []exportColumn{
+		text("sell_trade_date", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetSellTradeDate() }),
+		text("allocation_id", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetId() }),
+		text("sell_transaction_id", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetSellTransactionId() }),
+		text("buy_lot_id", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetBuyLotId() }),
+		productColumn,
+		gradeColumn,
+		text("spice_grade_id", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetSpiceGradeId() }),
+		number("quantity_kg", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetQuantity() }),
+		number("buy_price", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetBuyPrice() }),
+		number("sell_price", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetSellPrice() }),
+		number("realized_pnl", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetRealizedPnl() }),
+		text("cost_basis_method", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetCostBasisMethod() }),
+		text("reversed_by_transaction_id", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetReversedByTransactionId() }),
+		text("created_at", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetCreatedAt() }),
+		text("user_id", func(r *pb.ExportLedgerRow) string { return r.GetAllocation().GetUserId() }),
+	}}
+
+	positionsExport = ledgerExport{kind: "POSITIONS", sheet: "Positions", columns: []exportColumn{
+		productColumn,
+		gradeColumn,
+		text("spice_grade_id", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetSpiceGradeId() }),
+		text("currency", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetCurrency() }),
+		number("total_qty_kg", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetTotalQty() }),
+		number("avg_cost", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetAvgCost() }),
+		number("total_cost", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetTotalCost() }),
+		number("today_price", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetTodayPrice() }),
+		text("price_date", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetPriceDate() }),
+		text("price_source", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetPriceSource() }),
+		text("stale", func(r *pb.ExportLedgerRow) string { return strconv.FormatBool(r.GetPosition().GetStale()) }),
+		number("realized_pnl", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetRealizedPnl() }),
+		number("unrealized_pnl", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetUnrealizedPnl() }),
+		text("unit", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetUnit() }),
+		number("kg_per_unit", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetKgPerUnit() }),
+		number("unit_qty", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetUnitQty() }),
+		text("reporting_currency", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetReportingCurrency() }),
+		number("fx_rate", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetFxRate() }),
+		number("reporting_total_cost", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetReportingTotalCost() }),
+		number("reporting_realized_pnl", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetReportingRealizedPnl() }),
+		number("reporting_unrealized_pnl", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetReportingUnrealizedPnl() }),
+		text("updated_at", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetUpdatedAt() }),
+		text("user_id", func(r *pb.ExportLedgerRow) string { return r.GetPosition().GetUserId() }),
+	}}
+)
+
+// handleExport streams one ledger export as CSV (?format=csv, the default) or XLSX
+// (?format=xlsx), with the listTransactions filters: grade_id, product_id, sort, date_from and
+// date_to. Rows are written as market sends them, with no page limit.
+func (s *Server) handleExport(export ledgerExport) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			util.WriteMethodNotAllowed(w)
+			return
+		}
+
+		q := r.URL.Query()
+		format := strings.ToLower(q.Get("format"))
+		if format == "" {
+			format = "csv"
+		}
+		if format != "csv" && format != "xlsx" {
+			util.WriteBadRequest(w, "format must be csv or xlsx")
+			return
+		}
+		req := &pb.ExportLedgerRequest{
+			Kind:         export.kind,
+			UserId:       q.Get("user_id"),
+			SpiceGradeId: q.Get("grade_id"),
+			ProductId:    q.Get("product_id"),
+			Sort:         q.Get("sort"),
+			DateFrom:     q.Get("date_from"),
+			DateTo:       q.Get("date_to"),
+		}
+		for param, flag := range map[string]*bool{"open_only": &req.OpenOnly, "include_reversed": &req.IncludeReversed} {
+			if v := q.Get(param); v != "" {
+				parsed, err := strconv.ParseBool(v)
+				if err != nil {
+					util.WriteBadRequest(w, param+" must be true or false")
+					return
+				}
+				*flag = parsed
+			}
+		}
+
+		stream, err := s.marketClient.ExportLedger(s.withAuth(r), req)
+		if err != nil {
+			util.WriteGRPCErrorResponse(w, err)
+			return
+		}
+		// Market validates before sending anything, so a bad filter or missing auth fails the
+		// first receive, while the response can still be an error.
+		row, err := stream.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			util.WriteGRPCErrorResponse(w, err)
+			return
+		}
+
+		filename := fmt.Sprintf("%s_%s.%s", strings.ToLower(export.kind), time.Now().Format("20060102"), format)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.Header().Set("Cache-Control", "no-store")
+		var table tableWriter
+		if format == "xlsx" {
+			w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+			if table, err = newXLSXTable(w, export.sheet); err != nil {
+				s.abortExport(export, err)
+			}
+		} else {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			table = newCSVTable(w)
+		}
+
+		header := make([]cell, len(export.columns))
+		for i, col := range export.columns {
+			header[i] = cell{value: col.name}
+		}
+		if err := table.WriteRow(header); err != nil {
+			s.abortExport(export, err)
+		}
+
+		flusher := http.NewResponseController(w)
+		for rows := 0; row != nil; rows++ {
+			cells := make([]cell, len(export.columns))
+			for i, col := range export.columns {
+				cells[i] = cell{value: col.value(row), numeric: col.numeric}
+			}
+			if err := table.WriteRow(cells); err != nil {
+				s.abortExport(export, err)
+			}
+			if rows%exportFlushRows == exportFlushRows-1 {
+				if err := table.Flush(); err != nil {
+					s.abortExport(export, err)
+				}
+				_ = flusher.Flush()
+			}
+
+			row, err = stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				s.abortExport(export, err)
+			}
+		}
+		if err := table.Close(); err != nil {
+			s.abortExport(export, err)
+		}
+	}
+}
+
+// abortExport drops the connection of a download that failed after it started, so the client
+// sees an incomplete transfer instead of a file that looks whole.
+func (s *Server) abortExport(export ledgerExport, err error) {
+	s.logger.Transport().Error().Err(err).Str("export", export.kind).Msg("ledger export aborted")
+	panic(http.ErrAbortHandler)
+}
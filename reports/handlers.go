@@ -0,0 +1,197 @@
+package reports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/Asif-Faizal/SpiceLedger-Backend/market/pb"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+)
+
+// handleRealizedGains downloads the caller's realized gains for a financial year
+// (?financial_year=2025-26) or a date range (?date_from=&date_to=), as CSV (?format=csv)
+// or as a JSON statement (the default).
+func (s *Server) handleRealizedGains(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	q := r.URL.Query()
+	format := strings.ToLower(q.Get("format"))
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		util.WriteBadRequest(w, "format must be csv or json")
+		return
+	}
+	req := &pb.GetRealizedGainsReportRequest{
+		UserId:        q.Get("user_id"),
+		FinancialYear: q.Get("financial_year"),
+		DateFrom:      q.Get("date_from"),
+		DateTo:        q.Get("date_to"),
+		SpiceGradeId:  q.Get("grade_id"),
+	}
+	if v := q.Get("long_term_days"); v != "" {
+		days, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			util.WriteBadRequest(w, "long_term_days must be a whole number of days")
+			return
+		}
+		req.LongTermDays = uint32(days)
+	}
+
+	report, err := s.marketClient.GetRealizedGainsReport(s.withAuth(r), req)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("realized-gains_%s_%s.%s", report.DateFrom, report.DateTo, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Cache-Control", "no-store")
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		if err := writeGainsCSV(w, report); err != nil {
+			s.logger.Transport().Error().Err(err).Msg("realized gains CSV write failed")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	statement := toGainsStatement(report, q.Get("financial_year"))
+	if err := json.NewEncoder(w).Encode(statement); err != nil {
+		s.logger.Transport().Error().Err(err).Msg("realized gains JSON write failed")
+	}
+}
+
+var gainsCSVHeader = []string{
+	"row_type", "realized_date", "kind", "product", "grade", "spice_grade_id", "holding_class",
+	"acquired_date", "disposed_date", "holding_days", "quantity_kg", "buy_price", "sell_price",
+	"cost", "proceeds", "fees", "gain", "currency", "cost_basis_method",
+	"sell_transaction_id", "buy_transaction_id", "lot_id",
+}
+
+// writeGainsCSV writes one GAIN row per match, then GRADE, HOLDING_CLASS and TOTAL subtotal
+// rows in the same columns, so the sheet can be filtered on row_type.
+func writeGainsCSV(w http.ResponseWriter, report *pb.GetRealizedGainsReportResponse) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(gainsCSVHeader); err != nil {
+		return err
+	}
+	for _, g := range report.Gains {
+		if err := cw.Write([]string{
+			"GAIN", g.RealizedDate, g.Kind, g.ProductName, g.GradeName, g.SpiceGradeId, g.HoldingClass,
+			g.AcquiredDate, g.DisposedDate, strconv.FormatUint(uint64(g.HoldingDays), 10), g.Quantity, g.BuyPrice, g.SellPrice,
+			g.Cost, g.Proceeds, g.Fees, g.Gain, g.Currency, g.CostBasisMethod,
+			g.SellTransactionId, g.BuyTransactionId, g.LotId,
+		}); err != nil {
+			return err
+		}
+	}
+	subtotals := []struct {
+		rowType string
+		rows    []*pb.GainsSubtotal
+	}{
+		{"GRADE", report.Grades},
+		{"HOLDING_CLASS", report.HoldingClasses},
+		{"TOTAL", report.Totals},
+	}
+	for _, group := range subtotals {
+		for _, t := range group.rows {
+			if err := cw.Write([]string{
+				group.rowType, "", "", t.ProductName, t.GradeName, t.SpiceGradeId, t.HoldingClass,
+				"", "", "", t.Quantity, "", "",
+				t.Cost, t.Proceeds, t.Fees, t.Gain, t.Currency, "",
+				"", "", "",
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// toGainsStatement groups the gains under their grade subtotal, in the report's grade order.
+func toGainsStatement(report *pb.GetRealizedGainsReportResponse, financialYear string) *GainsStatement {
+	statement := &GainsStatement{
+		Title:          "Realized gains statement",
+		AccountID:      report.UserId,
+		FinancialYear:  financialYear,
+		DateFrom:       report.DateFrom,
+		DateTo:         report.DateTo,
+		LongTermDays:   report.LongTermDays,
+		GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+		Sections:       make([]*GradeSection, 0, len(report.Grades)),
+		HoldingClasses: toSubtotals(report.HoldingClasses),
+		Totals:         toSubtotals(report.Totals),
+	}
+
+	sections := make(map[string]*GradeSection, len(report.Grades))
+	for _, t := range report.Grades {
+		section := &GradeSection{
+			SpiceGradeID: t.SpiceGradeId,
+			ProductName:  t.ProductName,
+			GradeName:    t.GradeName,
+			Currency:     t.Currency,
+			Gains:        []*Gain{},
+			Subtotal:     toSubtotal(t),
+		}
+		sections[t.SpiceGradeId+"|"+t.Currency] = section
+		statement.Sections = append(statement.Sections, section)
+	}
+	for _, g := range report.Gains {
+		section := sections[g.SpiceGradeId+"|"+g.Currency]
+		if section == nil {
+			continue
+		}
+		section.Gains = append(section.Gains, &Gain{
+			Kind:              g.Kind,
+			RealizedDate:      g.RealizedDate,
+			AcquiredDate:      g.AcquiredDate,
+			DisposedDate:      g.DisposedDate,
+			HoldingDays:       g.HoldingDays,
+			HoldingClass:      g.HoldingClass,
+			Quantity:          g.Quantity,
+			BuyPrice:          g.BuyPrice,
+			SellPrice:         g.SellPrice,
+			Cost:              g.Cost,
+			Proceeds:          g.Proceeds,
+			Fees:              g.Fees,
+			Gain:              g.Gain,
+			CostBasisMethod:   g.CostBasisMethod,
+			SellTransactionID: g.SellTransactionId,
+			BuyTransactionID:  g.BuyTransactionId,
+			LotID:             g.LotId,
+		})
+	}
+	return statement
+}
+
+func toSubtotals(rows []*pb.GainsSubtotal) []*Subtotal {
+	out := make([]*Subtotal, len(rows))
+	for i, t := range rows {
+		out[i] = toSubtotal(t)
+	}
+	return out
+}
+
+func toSubtotal(t *pb.GainsSubtotal) *Subtotal {
+	return &Subtotal{
+		HoldingClass: t.HoldingClass,
+		Currency:     t.Currency,
+		Quantity:     t.Quantity,
+		Cost:         t.Cost,
+		Proceeds:     t.Proceeds,
+		Fees:         t.Fees,
+		Gain:         t.Gain,
+		Lines:        t.Lines,
+	}
+}
@@ -0,0 +1,57 @@
+package reports
+
+// GainsStatement is the JSON download of a realized gains report, laid out for rendering as
+// a document: one section per grade and currency with its gains and subtotal, then the
+// holding-class subtotals and the totals. Amounts are decimal strings.
+type GainsStatement struct {
+	Title          string          `json:"title"`
+	AccountID      string          `json:"account_id"`
+	FinancialYear  string          `json:"financial_year,omitempty"`
+	DateFrom       string          `json:"date_from"`
+	DateTo         string          `json:"date_to"`
+	LongTermDays   uint32          `json:"long_term_days"`
+	GeneratedAt    string          `json:"generated_at"`
+	Sections       []*GradeSection `json:"sections"`
+	HoldingClasses []*Subtotal     `json:"holding_classes"`
+	Totals         []*Subtotal     `json:"totals"`
+}
+
+type GradeSection struct {
+	SpiceGradeID string    `json:"spice_grade_id"`
+	ProductName  string    `json:"product_name"`
+	GradeName    string    `json:"grade_name"`
+	Currency     string    `json:"currency"`
+	Gains        []*Gain   `json:"gains"`
+	Subtotal     *Subtotal `json:"subtotal"`
+}
+
+type Gain struct {
+	Kind              string `json:"kind"` // LONG | SHORT
+	RealizedDate      string `json:"realized_date"`
+	AcquiredDate      string `json:"acquired_date"`
+	DisposedDate      string `json:"disposed_date"`
+	HoldingDays       uint32 `json:"holding_days"`
+	HoldingClass      string `json:"holding_class"` // SHORT_TERM | LONG_TERM
+	Quantity          string `json:"quantity"`      // kg
+	BuyPrice          string `json:"buy_price"`
+	SellPrice         string `json:"sell_price"`
+	Cost              string `json:"cost"`
+	Proceeds          string `json:"proceeds"`
+	Fees              string `json:"fees"`
+	Gain              string `json:"gain"`
+	CostBasisMethod   string `json:"cost_basis_method,omitempty"`
+	SellTransactionID string `json:"sell_transaction_id"`
+	BuyTransactionID  string `json:"buy_transaction_id"`
+	LotID             string `json:"lot_id"`
+}
+
+type Subtotal struct {
+	HoldingClass string `json:"holding_class,omitempty"`
+	Currency     string `json:"currency"`
+	Quantity     string `json:"quantity"`
+	Cost         string `json:"cost"`
+	Proceeds     string `json:"proceeds"`
+	Fees         string `json:"fees"`
+	Gain         string `json:"gain"`
+	Lines        uint32 `json:"lines"`
+}
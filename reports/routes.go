@@ -0,0 +1,55 @@
+package reports
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+)
+
+func NewHandler(server *Server) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/realized-gains", server.handleRealizedGains)
+	mux.HandleFunc("/transactions", server.handleExport(transactionsExport))
+	mux.HandleFunc("/lots", server.handleExport(lotsExport))
+	mux.HandleFunc("/allocations", server.handleExport(allocationsExport))
+	mux.HandleFunc("/positions", server.handleExport(positionsExport))
+	return logRequests(server.logger)(mux)
+}
+
+// statusRecorder keeps the response status for the access log.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rw *statusRecorder) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer to flush exports.
+func (rw *statusRecorder) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// logRequests logs each download without its body, unlike util.LoggingMiddleware: report
+// files are too large to copy into the log.
+func logRequests(logger util.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			logger.Transport().Info().
+				Str("layer", "reports").
+				Str("method", r.Method+" "+r.URL.Path).
+				Str("query", r.URL.RawQuery).
+				Int("status", rw.statusCode).
+				Str("duration", time.Since(start).String()).
+				Msg("API")
+		})
+	}
+}
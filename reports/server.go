@@ -0,0 +1,49 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Asif-Faizal/SpiceLedger-Backend/market"
+	"github.com/Asif-Faizal/SpiceLedger-Backend/util"
+	"google.golang.org/grpc/metadata"
+)
+
+// Server serves file downloads built from market data. Calls run as the caller: the
+// Authorization header is forwarded to market, which scopes merchants to their own ledger.
+type Server struct {
+	marketClient *market.MarketClient
+	logger       util.Logger
+}
+
+func NewServer(marketGrpcURL string, logger util.Logger) (*Server, error) {
+	if marketGrpcURL == "" {
+		return nil, fmt.Errorf("MARKET_GRPC_URL must be provided")
+	}
+
+	marketClient, err := market.NewMarketClient(marketGrpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to market service: %w", err)
+	}
+
+	return &Server{
+		marketClient: marketClient,
+		logger:       logger,
+	}, nil
+}
+
+func (s *Server) withAuth(r *http.Request) context.Context {
+	ctx := r.Context()
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		return metadata.AppendToOutgoingContext(ctx, "authorization", auth)
+	}
+	return ctx
+}
+
+func (s *Server) Close() error {
+	if s.marketClient != nil {
+		s.marketClient.Close()
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package reports
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// cell is one value of a table row; numeric cells are written as numbers in XLSX.
+type cell struct {
+	value   string
+	numeric bool
+}
+
+// tableWriter writes a table row by row, so a download never holds more than a row.
+type tableWriter interface {
+	WriteRow(cells []cell) error
+	// Flush pushes buffered rows to the underlying writer.
+	Flush() error
+	// Close finishes the file.
+	Close() error
+}
+
+// formulaPrefixes are the leading characters a spreadsheet reads as the start of a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula quotes a text cell that a spreadsheet would otherwise evaluate, so an
+// exported note or name such as =HYPERLINK(...) shows as text. Numeric cells are written
+// by the exporter itself and are left alone, negative amounts included.
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type csvTable struct {
+	w *csv.Writer
+}
+
+func newCSVTable(w io.Writer) *csvTable {
+	return &csvTable{w: csv.NewWriter(w)}
+}
+
+func (t *csvTable) WriteRow(cells []cell) error {
+	record := make([]string, len(cells))
+	for i, c := range cells {
+		record[i] = c.value
+		if !c.numeric {
+			record[i] = escapeFormula(c.value)
+		}
+	}
+	return t.w.Write(record)
+}
+
+func (t *csvTable) Flush() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+func (t *csvTable) Close() error {
+	return t.Flush()
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxTable writes a single-sheet workbook. The fixed parts go first and the sheet is the last
+// zip entry, so rows are compressed and sent as they are written. Text uses inline strings,
+// which needs no shared-string table.
+type xlsxTable struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+}
+
+func newXLSXTable(w io.Writer, sheetName string) (*xlsxTable, error) {
+	zw := zip.NewWriter(w)
+	var name strings.Builder
+	if err := xml.EscapeText(&name, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+	parts := []struct{ path, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, name.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	if _, err := sheet.WriteString(xlsxSheetStart); err != nil {
+		return nil, err
+	}
+	return &xlsxTable{zip: zw, sheet: sheet}, nil
+}
+
+func (t *xlsxTable) WriteRow(cells []cell) error {
+	t.sheet.WriteString("<row>")
+	for _, c := range cells {
+		switch {
+		case c.value == "":
+			t.sheet.WriteString("<c/>")
+		case c.numeric:
+			t.sheet.WriteString("<c><v>")
+			xml.EscapeText(t.sheet, []byte(c.value))
+			t.sheet.WriteString("</v></c>")
+		default:
+			t.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(t.sheet, []byte(escapeFormula(c.value)))
+			t.sheet.WriteString("</t></is></c>")
+		}
+	}
+	_, err := t.sheet.WriteString("</row>")
+	return err
+}
+
+func (t *xlsxTable) Flush() error {
+	if err := t.sheet.Flush(); err != nil {
+		return err
+	}
+	return t.zip.Flush()
+}
+
+func (t *xlsxTable) Close() error {
+	if _, err := t.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := t.sheet.Flush(); err != nil {
+		return err
+	}
+	return t.zip.Close()
+}
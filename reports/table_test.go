@@ -0,0 +1,89 @@
+package reports
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEscapeFormula(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"plain text", "Malabar pepper", "Malabar pepper"},
+		{"equals", "=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"plus", "+1+1", "'+1+1"},
+		{"minus", "-2+3", "'-2+3"},
+		{"at", "@SUM(A1)", "'@SUM(A1)"},
+		{"tab", "\t=1", "'\t=1"},
+		{"carriage return", "\r=1", "'\r=1"},
+		{"formula later in text", "note =1", "note =1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeFormula(tt.value); got != tt.want {
+				t.Errorf("escapeFormula(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVTableEscapesTextOnly(t *testing.T) {
+	var buf bytes.Buffer
+	table := newCSVTable(&buf)
+	if err := table.WriteRow([]cell{{value: "=cmd"}, {value: "-12.50", numeric: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "'=cmd,-12.50\n"; got != want {
+		t.Errorf("csv = %q, want %q", got, want)
+	}
+}
+
+func TestXLSXTableEscapesTextOnly(t *testing.T) {
+	var buf bytes.Buffer
+	table, err := newXLSXTable(&buf, "Ledger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := table.WriteRow([]cell{{value: "@cmd"}, {value: "-12.50", numeric: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sheet = string(body)
+	}
+	if !strings.Contains(sheet, `<t xml:space="preserve">&#39;@cmd</t>`) {
+		t.Errorf("text cell not escaped: %s", sheet)
+	}
+	if !strings.Contains(sheet, "<v>-12.50</v>") {
+		t.Errorf("numeric cell changed: %s", sheet)
+	}
+}
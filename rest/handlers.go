@@ -2,9 +2,12 @@ package rest
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	pb "github.com/Asif-Faizal/SpiceLedger-Backend/control/pb"
@@ -15,7 +18,7 @@ import (
 )
 
 func (s *Server) withAuth(r *http.Request) context.Context {
-	ctx := r.Context()
+	ctx := util.ForwardClient(r.Context(), r)
 	auth := r.Header.Get("Authorization")
 	if auth != "" {
 		return metadata.AppendToOutgoingContext(ctx, "authorization", auth)
@@ -68,13 +71,19 @@ func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := s.controlClient.Login(s.withAuth(r), req.Email, req.Password, req.DeviceID)
+	resp, err := s.controlClient.Login(s.withAuth(r), req.Email, req.Password, req.DeviceID, req.DeviceName)
 	if err != nil {
 		util.WriteJSONResponse(w, http.StatusUnauthorized, false, err.Error(), nil)
 		return
 	}
 
-	util.WriteJSONResponse(w, http.StatusOK, true, "Login successful", toAuthenticatedResponse(resp))
+	message := "Login successful"
+	if resp.EnrollmentRequired {
+		message = "Two-factor enrolment required"
+	} else if resp.TwoFactorRequired {
+		message = "Two-factor code required"
+	}
+	util.WriteJSONResponse(w, http.StatusOK, true, message, toAuthenticatedResponse(resp))
 }
 
 func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
@@ -143,17 +152,19 @@ func (s *Server) handleCreateOrUpdateAccount(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	resp, err := s.controlClient.CreateOrUpdateAccount(s.withAuth(r), req.ID, req.Name, req.UserType, req.Email, req.Password)
+	resp, err := s.controlClient.CreateOrUpdateAccount(s.withAuth(r), req.ID, req.Name, req.UserType, req.Email, req.Password, req.Currency, req.ReportingCurrency)
 	if err != nil {
 		util.WriteGRPCErrorResponse(w, err)
 		return
 	}
 
 	util.WriteJSONResponse(w, http.StatusOK, true, "Account created/updated successfully", &Account{
-		ID:       resp.Account.Id,
-		Name:     resp.Account.Name,
-		UserType: resp.Account.Usertype,
-		Email:    resp.Account.Email,
+		ID:                resp.Account.Id,
+		Name:              resp.Account.Name,
+		UserType:          resp.Account.Usertype,
+		Email:             resp.Account.Email,
+		Currency:          resp.Account.Currency,
+		ReportingCurrency: resp.Account.ReportingCurrency,
 	})
 }
 
@@ -169,10 +180,13 @@ func (s *Server) handleListAccounts(w http.ResponseWriter, r *http.Request) {
 			accounts := make([]*Account, len(resp.Accounts))
 			for i, a := range resp.Accounts {
 				accounts[i] = &Account{
-					ID:       a.Id,
-					Name:     a.Name,
-					UserType: a.Usertype,
-					Email:    a.Email,
+					ID:                a.Id,
+					Name:              a.Name,
+					UserType:          a.Usertype,
+					Email:             a.Email,
+					Currency:          a.Currency,
+					ReportingCurrency: a.ReportingCurrency,
+					EmailVerified:     a.EmailVerified,
 				}
 			}
 			return accounts
@@ -199,10 +213,13 @@ func (s *Server) handleAccountByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	util.WriteJSONResponse(w, http.StatusOK, true, "Account retrieved successfully", &Account{
-		ID:       resp.Account.Id,
-		Name:     resp.Account.Name,
-		UserType: resp.Account.Usertype,
-		Email:    resp.Account.Email,
+		ID:                resp.Account.Id,
+		Name:              resp.Account.Name,
+		UserType:          resp.Account.Usertype,
+		Email:             resp.Account.Email,
+		Currency:          resp.Account.Currency,
+		ReportingCurrency: resp.Account.ReportingCurrency,
+		EmailVerified:     resp.Account.EmailVerified,
 	})
 }
 
@@ -219,10 +236,13 @@ func (s *Server) handleGetAccountInfo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	util.WriteJSONResponse(w, http.StatusOK, true, "Account info retrieved successfully", &Account{
-		ID:       resp.Account.Id,
-		Name:     resp.Account.Name,
-		UserType: resp.Account.Usertype,
-		Email:    resp.Account.Email,
+		ID:                resp.Account.Id,
+		Name:              resp.Account.Name,
+		UserType:          resp.Account.Usertype,
+		Email:             resp.Account.Email,
+		Currency:          resp.Account.Currency,
+		ReportingCurrency: resp.Account.ReportingCurrency,
+		EmailVerified:     resp.Account.EmailVerified,
 	})
 }
 
@@ -423,18 +443,21 @@ func (s *Server) handleCreateOrUpdateGrade(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	resp, err := s.controlClient.CreateOrUpdateGrade(s.withAuth(r), req.ID, req.ProductID, req.Name, req.Description, req.Status)
+	resp, err := s.controlClient.CreateOrUpdateGrade(s.withAuth(r), req.ID, req.ProductID, req.Name, req.Description, req.Status, req.ShelfLifeDays, req.Unit, req.KgPerUnit)
 	if err != nil {
 		util.WriteGRPCErrorResponse(w, err)
 		return
 	}
 
 	util.WriteJSONResponse(w, http.StatusOK, true, "Grade created/updated successfully", &Grade{
-		ID:          resp.Grade.Id,
-		ProductID:   resp.Grade.ProductId,
-		Name:        resp.Grade.Name,
-		Description: resp.Grade.Description,
-		Status:      resp.Grade.Status,
+		ID:            resp.Grade.Id,
+		ProductID:     resp.Grade.ProductId,
+		Name:          resp.Grade.Name,
+		Description:   resp.Grade.Description,
+		Status:        resp.Grade.Status,
+		ShelfLifeDays: resp.Grade.ShelfLifeDays,
+		Unit:          resp.Grade.Unit,
+		KgPerUnit:     resp.Grade.KgPerUnit,
 	})
 }
 
@@ -456,11 +479,14 @@ func (s *Server) handleListGradesByProductId(w http.ResponseWriter, r *http.Requ
 			grades := make([]*Grade, len(resp.Grades))
 			for i, g := range resp.Grades {
 				grades[i] = &Grade{
-					ID:          g.Id,
-					ProductID:   g.ProductId,
-					Name:        g.Name,
-					Description: g.Description,
-					Status:      g.Status,
+					ID:            g.Id,
+					ProductID:     g.ProductId,
+					Name:          g.Name,
+					Description:   g.Description,
+					Status:        g.Status,
+					ShelfLifeDays: g.ShelfLifeDays,
+					Unit:          g.Unit,
+					KgPerUnit:     g.KgPerUnit,
 				}
 			}
 			return grades
@@ -480,22 +506,213 @@ func (s *Server) handleCreateOrUpdateDailyPrice(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	resp, err := s.controlClient.CreateOrUpdateDailyPrice(s.withAuth(r), req.ID, req.ProductID, req.GradeID, req.Price, req.Date, req.Time)
+	resp, err := s.controlClient.CreateOrUpdateDailyPrice(s.withAuth(r), req.ID, req.ProductID, req.GradeID, req.Price, req.Currency, req.Date, req.Time, req.Source, req.Draft)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	message := "Price submitted for approval"
+	if req.Draft {
+		message = "Price saved as draft"
+	}
+	util.WriteJSONResponse(w, http.StatusOK, true, message, toPriceTick(resp.Tick))
+}
+
+func (s *Server) handleSubmitPriceTick(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req SubmitPriceTickRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.SubmitPriceTick(s.withAuth(r), req.ID)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Price submitted for approval", toPriceTick(resp.Tick))
+}
+
+func (s *Server) handleReviewPriceTicks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req ReviewPriceTicksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.ReviewPriceTicks(s.withAuth(r), req.IDs, req.Decision, req.Note)
 	if err != nil {
 		util.WriteGRPCErrorResponse(w, err)
 		return
 	}
 
-	util.WriteJSONResponse(w, http.StatusOK, true, "Daily price created/updated successfully", &DailyPrice{
-		ID:        resp.DailyPrice.Id,
-		ProductID: resp.DailyPrice.ProductId,
-		GradeID:   resp.DailyPrice.GradeId,
-		Price:     resp.DailyPrice.Price,
-		Date:      resp.DailyPrice.Date,
-		Time:      resp.DailyPrice.Time,
+	ticks := make([]*PriceTick, len(resp.Ticks))
+	for i, t := range resp.Ticks {
+		ticks[i] = toPriceTick(t)
+	}
+	util.WriteJSONResponse(w, http.StatusOK, true, "Prices reviewed successfully", ReviewPriceTicksResponse{
+		Ticks:       ticks,
+		DailyPrices: toDailyPrices(resp.DailyPrices),
 	})
 }
 
+// maxPriceSheetBytes caps the body of a bulk price import.
+const maxPriceSheetBytes = 5 << 20
+
+// handleImportDailyPrices takes a price sheet as CSV (Content-Type text/csv, with a header row)
+// or as JSON and records it through the batch RPC. Rows are applied all together or not at all;
+// a sheet with rejected rows answers 422 with the report so every problem can be fixed at once.
+func (s *Server) handleImportDailyPrices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxPriceSheetBytes)
+	var req ImportDailyPricesRequest
+	var prices []*pb.CreateOrUpdateDailyPriceRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		sheet, err := parsePriceSheet(body)
+		if err != nil {
+			util.WriteBadRequest(w, err.Error())
+			return
+		}
+		prices = sheet
+	} else {
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			util.WriteBadRequest(w, "invalid request body")
+			return
+		}
+		for _, p := range req.Prices {
+			if p == nil {
+				p = &CreateOrUpdateDailyPriceRequest{}
+			}
+			prices = append(prices, &pb.CreateOrUpdateDailyPriceRequest{
+				Id:        p.ID,
+				ProductId: p.ProductID,
+				GradeId:   p.GradeID,
+				Price:     p.Price.String(),
+				Currency:  p.Currency,
+				Date:      p.Date,
+				Time:      p.Time,
+				Source:    p.Source,
+			})
+		}
+	}
+
+	query := r.URL.Query()
+	if v := query.Get("max_move_percent"); v != "" {
+		req.MaxMovePercent = v
+	}
+	if v := query.Get("dry_run"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			util.WriteBadRequest(w, "dry_run must be true or false")
+			return
+		}
+		req.DryRun = dryRun
+	}
+
+	resp, err := s.controlClient.CreateOrUpdateDailyPrices(s.withAuth(r), prices, req.MaxMovePercent, req.DryRun)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	report := ImportDailyPricesResponse{
+		Applied:        resp.Applied,
+		DryRun:         resp.DryRun,
+		MaxMovePercent: resp.MaxMovePercent,
+		Accepted:       resp.Accepted,
+		Rejected:       resp.Rejected,
+		Rows:           make([]*PriceImportRow, len(resp.Rows)),
+	}
+	for i, row := range resp.Rows {
+		report.Rows[i] = &PriceImportRow{
+			Row:         row.Row,
+			ProductID:   row.ProductId,
+			GradeID:     row.GradeId,
+			Price:       row.Price,
+			Status:      row.Status,
+			Error:       row.Error,
+			MovePercent: row.MovePercent,
+		}
+		if row.Tick != nil {
+			report.Rows[i].Tick = toPriceTick(row.Tick)
+		}
+	}
+
+	switch {
+	case resp.Rejected > 0:
+		util.WriteJSONResponse(w, http.StatusUnprocessableEntity, false,
+			fmt.Sprintf("%d of %d prices were rejected; nothing was imported", resp.Rejected, len(resp.Rows)), report)
+	case resp.DryRun:
+		util.WriteJSONResponse(w, http.StatusOK, true, "Price sheet is valid; nothing was imported (dry run)", report)
+	default:
+		util.WriteJSONResponse(w, http.StatusOK, true, "Daily prices imported and submitted for approval", report)
+	}
+}
+
+// parsePriceSheet reads a CSV price sheet. The header names the columns in any order:
+// product_id, grade_id and price are required; id, date, time and source are optional.
+func parsePriceSheet(body io.Reader) ([]*pb.CreateOrUpdateDailyPriceRequest, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("price sheet needs a header row: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"product_id", "grade_id", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("price sheet is missing the %s column", required)
+		}
+	}
+
+	var prices []*pb.CreateOrUpdateDailyPriceRequest
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid price sheet: %w", err)
+		}
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		prices = append(prices, &pb.CreateOrUpdateDailyPriceRequest{
+			Id:        cell("id"),
+			ProductId: cell("product_id"),
+			GradeId:   cell("grade_id"),
+			Price:     cell("price"),
+			Currency:  cell("currency"),
+			Date:      cell("date"),
+			Time:      cell("time"),
+			Source:    cell("source"),
+		})
+	}
+	return prices, nil
+}
+
 func (s *Server) handleListDailyPricesByGradeId(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		util.WriteMethodNotAllowed(w)
@@ -518,20 +735,7 @@ func (s *Server) handleListDailyPricesByGradeId(w http.ResponseWriter, r *http.R
 	}
 
 	util.WriteJSONResponse(w, http.StatusOK, true, "Daily prices listed successfully", ListDailyPricesResponse{
-		DailyPrices: func() []*DailyPrice {
-			dailyPrices := make([]*DailyPrice, len(resp.DailyPrices))
-			for i, dp := range resp.DailyPrices {
-				dailyPrices[i] = &DailyPrice{
-					ID:        dp.Id,
-					ProductID: dp.ProductId,
-					GradeID:   dp.GradeId,
-					Price:     dp.Price,
-					Date:      dp.Date,
-					Time:      dp.Time,
-				}
-			}
-			return dailyPrices
-		}(),
+		DailyPrices: toDailyPrices(resp.DailyPrices),
 	})
 }
 
@@ -543,28 +747,16 @@ func (s *Server) handleGetTodaysByGradeId(w http.ResponseWriter, r *http.Request
 
 	gradeID := r.URL.Query().Get("grade_id")
 	dateStr := r.URL.Query().Get("date")
+	priceBasis := r.URL.Query().Get("price_basis")
 
-	resp, err := s.controlClient.GetTodaysPrice(s.withAuth(r), gradeID, dateStr)
+	resp, err := s.controlClient.GetTodaysPrice(s.withAuth(r), gradeID, dateStr, priceBasis)
 	if err != nil {
 		util.WriteGRPCErrorResponse(w, err)
 		return
 	}
 
 	util.WriteJSONResponse(w, http.StatusOK, true, "Daily prices for grade listed successfully", GetTodaysPriceResponse{
-		DailyPrices: func() []*DailyPrice {
-			dailyPrices := make([]*DailyPrice, len(resp.DailyPrices))
-			for i, dp := range resp.DailyPrices {
-				dailyPrices[i] = &DailyPrice{
-					ID:        dp.Id,
-					ProductID: dp.ProductId,
-					GradeID:   dp.GradeId,
-					Price:     dp.Price,
-					Date:      dp.Date,
-					Time:      dp.Time,
-				}
-			}
-			return dailyPrices
-		}(),
+		DailyPrices: toDailyPrices(resp.DailyPrices),
 	})
 }
 
@@ -576,51 +768,247 @@ func (s *Server) handleGetTodaysByProductId(w http.ResponseWriter, r *http.Reque
 
 	productID := r.URL.Query().Get("product_id")
 	dateStr := r.URL.Query().Get("date")
+	priceBasis := r.URL.Query().Get("price_basis")
 
-	resp, err := s.controlClient.GetTodaysByProductId(s.withAuth(r), productID, dateStr)
+	resp, err := s.controlClient.GetTodaysByProductId(s.withAuth(r), productID, dateStr, priceBasis)
 	if err != nil {
 		util.WriteGRPCErrorResponse(w, err)
 		return
 	}
 
 	util.WriteJSONResponse(w, http.StatusOK, true, "Daily prices for product listed successfully", GetTodaysPriceByProductIdResponse{
-		DailyPrices: func() []*DailyPrice {
-			dailyPrices := make([]*DailyPrice, len(resp.DailyPrices))
-			for i, dp := range resp.DailyPrices {
-				dailyPrices[i] = &DailyPrice{
-					ID:        dp.Id,
-					ProductID: dp.ProductId,
-					GradeID:   dp.GradeId,
-					Price:     dp.Price,
-					Date:      dp.Date,
-					Time:      dp.Time,
-				}
-			}
-			return dailyPrices
-		}(),
+		DailyPrices: toDailyPrices(resp.DailyPrices),
 	})
 }
 
+func (s *Server) handleListPriceTicks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	gradeID := r.URL.Query().Get("grade_id")
+	dateStr := r.URL.Query().Get("date")
+	status := r.URL.Query().Get("status")
+
+	resp, err := s.controlClient.ListPriceTicks(s.withAuth(r), gradeID, dateStr, status)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	ticks := make([]*PriceTick, len(resp.Ticks))
+	for i, t := range resp.Ticks {
+		ticks[i] = toPriceTick(t)
+	}
+	util.WriteJSONResponse(w, http.StatusOK, true, "Price ticks listed successfully", ListPriceTicksResponse{Ticks: ticks})
+}
+
+func (s *Server) handleFxRates(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.handleSetFxRate(w, r)
+	case http.MethodGet:
+		s.handleListFxRates(w, r)
+	default:
+		util.WriteMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleSetFxRate(w http.ResponseWriter, r *http.Request) {
+	var req SetFxRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.SetFxRate(s.withAuth(r), req.BaseCurrency, req.QuoteCurrency, req.Rate, req.EffectiveDate)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "FX rate saved successfully", toFxRate(resp.Rate))
+}
+
+func (s *Server) handleListFxRates(w http.ResponseWriter, r *http.Request) {
+	base := r.URL.Query().Get("base")
+	quote := r.URL.Query().Get("quote")
+
+	resp, err := s.controlClient.ListFxRates(s.withAuth(r), base, quote)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	rates := make([]*FxRate, len(resp.Rates))
+	for i, rate := range resp.Rates {
+		rates[i] = toFxRate(rate)
+	}
+	util.WriteJSONResponse(w, http.StatusOK, true, "FX rates listed successfully", ListFxRatesResponse{Rates: rates})
+}
+
+func (s *Server) handleFeeSchedules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.handleSetFeeSchedule(w, r)
+	case http.MethodGet:
+		s.handleListFeeSchedules(w, r)
+	default:
+		util.WriteMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleSetFeeSchedule(w http.ResponseWriter, r *http.Request) {
+	var req SetFeeScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.SetFeeSchedule(s.withAuth(r), &pb.SetFeeScheduleRequest{
+		GradeId:       req.GradeID,
+		Category:      req.Category,
+		Code:          req.Code,
+		Kind:          req.Kind,
+		Side:          req.Side,
+		Basis:         req.Basis,
+		Rate:          req.Rate.String(),
+		Currency:      req.Currency,
+		EffectiveDate: req.EffectiveDate,
+	})
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Fee schedule saved successfully", toFeeSchedule(resp.Schedule))
+}
+
+func (s *Server) handleListFeeSchedules(w http.ResponseWriter, r *http.Request) {
+	gradeID := r.URL.Query().Get("grade_id")
+	category := r.URL.Query().Get("category")
+
+	resp, err := s.controlClient.ListFeeSchedules(s.withAuth(r), gradeID, category)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	schedules := make([]*FeeSchedule, len(resp.Schedules))
+	for i, fs := range resp.Schedules {
+		schedules[i] = toFeeSchedule(fs)
+	}
+	util.WriteJSONResponse(w, http.StatusOK, true, "Fee schedules listed successfully", ListFeeSchedulesResponse{Schedules: schedules})
+}
+
+func toFeeSchedule(fs *pb.FeeSchedule) *FeeSchedule {
+	return &FeeSchedule{
+		ID:            fs.Id,
+		GradeID:       fs.GradeId,
+		Category:      fs.Category,
+		Code:          fs.Code,
+		Kind:          fs.Kind,
+		Side:          fs.Side,
+		Basis:         fs.Basis,
+		Rate:          fs.Rate,
+		Currency:      fs.Currency,
+		EffectiveDate: fs.EffectiveDate,
+		UpdatedBy:     fs.UpdatedBy,
+		UpdatedAt:     fs.UpdatedAt,
+	}
+}
+
+func toFxRate(rate *pb.FxRate) *FxRate {
+	return &FxRate{
+		ID:            rate.Id,
+		BaseCurrency:  rate.BaseCurrency,
+		QuoteCurrency: rate.QuoteCurrency,
+		Rate:          rate.Rate,
+		EffectiveDate: rate.EffectiveDate,
+		UpdatedBy:     rate.UpdatedBy,
+		UpdatedAt:     rate.UpdatedAt,
+	}
+}
+
+func toPriceTick(t *pb.PriceTick) *PriceTick {
+	return &PriceTick{
+		ID:          t.Id,
+		ProductID:   t.ProductId,
+		GradeID:     t.GradeId,
+		Price:       t.Price,
+		Currency:    t.Currency,
+		Source:      t.Source,
+		PublishedBy: t.PublishedBy,
+		TickedAt:    t.TickedAt,
+		Status:      t.Status,
+		SubmittedAt: t.SubmittedAt,
+		ReviewedBy:  t.ReviewedBy,
+		ReviewedAt:  t.ReviewedAt,
+		ReviewNote:  t.ReviewNote,
+	}
+}
+
+func toDailyPrice(dp *pb.DailyPrice) *DailyPrice {
+	return &DailyPrice{
+		ID:        dp.Id,
+		ProductID: dp.ProductId,
+		GradeID:   dp.GradeId,
+		Price:     dp.Price,
+		Date:      dp.Date,
+		Time:      dp.Time,
+		Open:      dp.Open,
+		High:      dp.High,
+		Low:       dp.Low,
+		Last:      dp.Last,
+		Close:     dp.Close,
+		TickCount: dp.TickCount,
+		Currency:  dp.Currency,
+	}
+}
+
+func toDailyPrices(prices []*pb.DailyPrice) []*DailyPrice {
+	dailyPrices := make([]*DailyPrice, len(prices))
+	for i, dp := range prices {
+		dailyPrices[i] = toDailyPrice(dp)
+	}
+	return dailyPrices
+}
+
 func toAuthenticatedResponse(resp interface{}) *AuthenticatedResponse {
 	switch r := resp.(type) {
 	case *pb.LoginResponse:
+		// A login waiting for its second factor has no account yet.
+		var account *Account
+		if r.Account != nil {
+			account = &Account{
+				ID:                r.Account.Id,
+				Name:              r.Account.Name,
+				UserType:          r.Account.Usertype,
+				Email:             r.Account.Email,
+				Currency:          r.Account.Currency,
+				ReportingCurrency: r.Account.ReportingCurrency,
+				EmailVerified:     r.Account.EmailVerified,
+			}
+		}
 		return &AuthenticatedResponse{
-			Account: &Account{
-				ID:       r.Account.Id,
-				Name:     r.Account.Name,
-				UserType: r.Account.Usertype,
-				Email:    r.Account.Email,
-			},
-			AccessToken:  r.AccessToken,
-			RefreshToken: r.RefreshToken,
+			Account:            account,
+			AccessToken:        r.AccessToken,
+			RefreshToken:       r.RefreshToken,
+			TwoFactorRequired:  r.TwoFactorRequired,
+			ChallengeToken:     r.ChallengeToken,
+			EnrollmentRequired: r.EnrollmentRequired,
 		}
 	case *pb.RefreshTokenResponse:
 		return &AuthenticatedResponse{
 			Account: &Account{
-				ID:       r.Account.Id,
-				Name:     r.Account.Name,
-				UserType: r.Account.Usertype,
-				Email:    r.Account.Email,
+				ID:                r.Account.Id,
+				Name:              r.Account.Name,
+				UserType:          r.Account.Usertype,
+				Email:             r.Account.Email,
+				Currency:          r.Account.Currency,
+				ReportingCurrency: r.Account.ReportingCurrency,
+				EmailVerified:     r.Account.EmailVerified,
 			},
 			AccessToken:  r.AccessToken,
 			RefreshToken: r.RefreshToken,
@@ -629,3 +1017,284 @@ func toAuthenticatedResponse(resp interface{}) *AuthenticatedResponse {
 		return &AuthenticatedResponse{}
 	}
 }
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	resp, err := s.controlClient.ListSessions(s.withAuth(r))
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	sessions := make([]*Session, len(resp.Sessions))
+	for i, session := range resp.Sessions {
+		sessions[i] = &Session{
+			ID:         session.Id,
+			DeviceID:   session.DeviceId,
+			DeviceName: session.DeviceName,
+			IPAddress:  session.IpAddress,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.Current,
+		}
+	}
+	util.WriteJSONResponse(w, http.StatusOK, true, "Sessions listed successfully", ListSessionsResponse{Sessions: sessions})
+}
+
+// handleRevokeSession signs out one of the caller's sessions: DELETE /accounts/sessions/{id}.
+func (s *Server) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/accounts/sessions/")
+	if id == "" {
+		util.WriteBadRequest(w, "session id is required")
+		return
+	}
+
+	if _, err := s.controlClient.RevokeSession(s.withAuth(r), id); err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Session revoked successfully", nil)
+}
+
+func (s *Server) handleRevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	resp, err := s.controlClient.RevokeAllOtherSessions(s.withAuth(r))
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Other sessions revoked successfully", RevokeSessionsResponse{Revoked: resp.Revoked})
+}
+
+func (s *Server) handleForceLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req ForceLogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.ForceLogout(s.withAuth(r), req.AccountID)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Account signed out everywhere", RevokeSessionsResponse{Revoked: resp.Revoked})
+}
+
+func (s *Server) handleRequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	if _, err := s.controlClient.RequestPasswordReset(s.withAuth(r), req.Email); err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "If the email has an account, a reset link has been sent", nil)
+}
+
+func (s *Server) handleConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req ConfirmPasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.ConfirmPasswordReset(s.withAuth(r), req.Token, req.NewPassword, req.RevokeSessions)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Password reset successfully", ConfirmPasswordResetResponse{SessionsRevoked: resp.SessionsRevoked})
+}
+
+func (s *Server) handleSendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	if _, err := s.controlClient.SendVerificationEmail(s.withAuth(r)); err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Verification email sent", nil)
+}
+
+func (s *Server) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.VerifyEmail(s.withAuth(r), req.Token)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Email verified successfully", &Account{
+		ID:                resp.Account.Id,
+		Name:              resp.Account.Name,
+		UserType:          resp.Account.Usertype,
+		Email:             resp.Account.Email,
+		Currency:          resp.Account.Currency,
+		ReportingCurrency: resp.Account.ReportingCurrency,
+		EmailVerified:     resp.Account.EmailVerified,
+	})
+}
+
+func (s *Server) handleEnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req EnrollTOTPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.EnrollTOTP(s.withAuth(r), req.ChallengeToken, req.DeviceID)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Add the secret to an authenticator app and confirm with a code", EnrollTOTPResponse{
+		Secret:          resp.Secret,
+		ProvisioningURI: resp.ProvisioningUri,
+	})
+}
+
+func (s *Server) handleConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req ConfirmTOTPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.ConfirmTOTP(s.withAuth(r), req.Code, req.ChallengeToken, req.DeviceID, req.DeviceName)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	result := ConfirmTOTPResponse{RecoveryCodes: resp.RecoveryCodes}
+	if resp.Login != nil {
+		result.Login = toAuthenticatedResponse(resp.Login)
+	}
+	util.WriteJSONResponse(w, http.StatusOK, true, "Two-factor authentication enabled; store the recovery codes safely", result)
+}
+
+func (s *Server) handleVerifyLoginChallenge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req VerifyLoginChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.VerifyLoginChallenge(s.withAuth(r), req.ChallengeToken, req.DeviceID, req.DeviceName, req.Code, req.RecoveryCode)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Login successful", toAuthenticatedResponse(resp))
+}
+
+func (s *Server) handleDisableTOTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req DisableTOTPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	if _, err := s.controlClient.DisableTOTP(s.withAuth(r), req.Code, req.RecoveryCode); err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Two-factor authentication disabled", nil)
+}
+
+func (s *Server) handleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.WriteMethodNotAllowed(w)
+		return
+	}
+
+	var req RegenerateRecoveryCodesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteJSONResponse(w, http.StatusBadRequest, false, "invalid request body", nil)
+		return
+	}
+
+	resp, err := s.controlClient.RegenerateRecoveryCodes(s.withAuth(r), req.Code)
+	if err != nil {
+		util.WriteGRPCErrorResponse(w, err)
+		return
+	}
+
+	util.WriteJSONResponse(w, http.StatusOK, true, "Recovery codes replaced", RecoveryCodesResponse{RecoveryCodes: resp.RecoveryCodes})
+}
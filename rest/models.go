@@ -1,9 +1,12 @@
 package rest
 
+import "github.com/shopspring/decimal"
+
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	DeviceID string `json:"device_id"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	DeviceID   string `json:"device_id"`
+	DeviceName string `json:"device_name"` // optional
 }
 
 type RefreshRequest struct {
@@ -17,24 +20,34 @@ type LogoutRequest struct {
 }
 
 type Account struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	UserType string `json:"user_type"`
-	Email    string `json:"email"`
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	UserType          string `json:"user_type"`
+	Email             string `json:"email"`
+	Currency          string `json:"currency"`
+	ReportingCurrency string `json:"reporting_currency"`
+	EmailVerified     bool   `json:"email_verified"`
 }
 
+// AuthenticatedResponse carries the session tokens, or, when the login needs a second factor,
+// only the challenge token to finish it with.
 type AuthenticatedResponse struct {
-	Account      *Account `json:"account"`
-	AccessToken  string   `json:"access_token"`
-	RefreshToken string   `json:"refresh_token"`
+	Account            *Account `json:"account"`
+	AccessToken        string   `json:"access_token"`
+	RefreshToken       string   `json:"refresh_token"`
+	TwoFactorRequired  bool     `json:"two_factor_required,omitempty"`
+	ChallengeToken     string   `json:"challenge_token,omitempty"`
+	EnrollmentRequired bool     `json:"enrollment_required,omitempty"` // admin must enrol first
 }
 
 type CreateOrUpdateAccountRequest struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	UserType string `json:"user_type"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	UserType          string `json:"user_type"`
+	Email             string `json:"email"`
+	Password          string `json:"password"`
+	Currency          string `json:"currency"`           // optional, defaults to INR for a new account
+	ReportingCurrency string `json:"reporting_currency"` // optional, defaults to currency
 }
 
 type CreateOrUpdateMerchantDetailsRequest struct {
@@ -91,19 +104,25 @@ type ListProductsResponse struct {
 }
 
 type Grade struct {
-	ID          string `json:"id"`
-	ProductID   string `json:"product_id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
+	ID            string `json:"id"`
+	ProductID     string `json:"product_id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Status        string `json:"status"`
+	ShelfLifeDays uint32 `json:"shelf_life_days"`
+	Unit          string `json:"unit"`
+	KgPerUnit     string `json:"kg_per_unit"` // decimal string; kilograms in one unit
 }
 
 type CreateOrUpdateGradeRequest struct {
-	ID          string `json:"id"`
-	ProductID   string `json:"product_id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
+	ID            string          `json:"id"`
+	ProductID     string          `json:"product_id"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	Status        string          `json:"status"`
+	ShelfLifeDays uint32          `json:"shelf_life_days"` // optional; 0 = not perishable
+	Unit          string          `json:"unit"`            // optional; KG (default), QUINTAL, TONNE or BAG
+	KgPerUnit     decimal.Decimal `json:"kg_per_unit"`     // required for BAG; fixed for the other units
 }
 
 type ListGradesByProductIdResponse struct {
@@ -111,21 +130,94 @@ type ListGradesByProductIdResponse struct {
 }
 
 type DailyPrice struct {
-	ID        string  `json:"id"`
-	ProductID string  `json:"product_id"`
-	GradeID   string  `json:"grade_id"`
-	Price     float64 `json:"price"`
-	Date      string  `json:"date"`
-	Time      string  `json:"time"`
+	ID        string `json:"id"`
+	ProductID string `json:"product_id"`
+	GradeID   string `json:"grade_id"`
+	Price     string `json:"price"` // decimal string, e.g. "1250.5"
+	Date      string `json:"date"`
+	Time      string `json:"time"`
+	Open      string `json:"open"`
+	High      string `json:"high"`
+	Low       string `json:"low"`
+	Last      string `json:"last"`
+	Close     string `json:"close,omitempty"` // empty until the day has ended
+	TickCount int32  `json:"tick_count"`
+	Currency  string `json:"currency"`
 }
 
 type CreateOrUpdateDailyPriceRequest struct {
-	ID        string  `json:"id"`
-	ProductID string  `json:"product_id"`
-	GradeID   string  `json:"grade_id"`
-	Price     float64 `json:"price"`
-	Date      string  `json:"date"`
-	Time      string  `json:"time"`
+	ID        string          `json:"id"`
+	ProductID string          `json:"product_id"`
+	GradeID   string          `json:"grade_id"`
+	Price     decimal.Decimal `json:"price"`    // accepts a JSON number or a decimal string
+	Currency  string          `json:"currency"` // optional, defaults to INR
+	Date      string          `json:"date"`
+	Time      string          `json:"time"`
+	Source    string          `json:"source"` // optional, defaults to MANUAL
+	Draft     bool            `json:"draft"`  // keep as DRAFT instead of submitting for approval
+}
+
+type SubmitPriceTickRequest struct {
+	ID string `json:"id"`
+}
+
+type ReviewPriceTicksRequest struct {
+	IDs      []string `json:"ids"`
+	Decision string   `json:"decision"` // APPROVE | REJECT
+	Note     string   `json:"note"`
+}
+
+type ReviewPriceTicksResponse struct {
+	Ticks       []*PriceTick  `json:"ticks"`
+	DailyPrices []*DailyPrice `json:"daily_prices"`
+}
+
+// ImportDailyPricesRequest is the JSON form of a bulk price import; the same sheet can be sent
+// as CSV with a header row naming the columns.
+type ImportDailyPricesRequest struct {
+	Prices         []*CreateOrUpdateDailyPriceRequest `json:"prices"`
+	MaxMovePercent string                             `json:"max_move_percent"` // optional; "0" turns the outlier check off
+	DryRun         bool                               `json:"dry_run"`
+}
+
+type PriceImportRow struct {
+	Row         int32      `json:"row"`
+	ProductID   string     `json:"product_id"`
+	GradeID     string     `json:"grade_id"`
+	Price       string     `json:"price"`
+	Status      string     `json:"status"` // OK | ERROR
+	Error       string     `json:"error,omitempty"`
+	MovePercent string     `json:"move_percent,omitempty"`
+	Tick        *PriceTick `json:"tick,omitempty"`
+}
+
+type ImportDailyPricesResponse struct {
+	Applied        bool              `json:"applied"`
+	DryRun         bool              `json:"dry_run"`
+	MaxMovePercent string            `json:"max_move_percent"`
+	Accepted       int32             `json:"accepted"`
+	Rejected       int32             `json:"rejected"`
+	Rows           []*PriceImportRow `json:"rows"`
+}
+
+type PriceTick struct {
+	ID          string `json:"id"`
+	ProductID   string `json:"product_id"`
+	GradeID     string `json:"grade_id"`
+	Price       string `json:"price"`
+	Currency    string `json:"currency"`
+	Source      string `json:"source"`
+	PublishedBy string `json:"published_by,omitempty"`
+	TickedAt    string `json:"ticked_at"`
+	Status      string `json:"status"` // DRAFT | SUBMITTED | APPROVED | REJECTED
+	SubmittedAt string `json:"submitted_at,omitempty"`
+	ReviewedBy  string `json:"reviewed_by,omitempty"`
+	ReviewedAt  string `json:"reviewed_at,omitempty"`
+	ReviewNote  string `json:"review_note,omitempty"`
+}
+
+type ListPriceTicksResponse struct {
+	Ticks []*PriceTick `json:"ticks"`
 }
 
 type ListDailyPricesResponse struct {
@@ -139,3 +231,141 @@ type GetTodaysPriceResponse struct {
 type GetTodaysPriceByProductIdResponse struct {
 	DailyPrices []*DailyPrice `json:"daily_prices"`
 }
+
+type FxRate struct {
+	ID            string `json:"id"`
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	Rate          string `json:"rate"` // 1 base_currency = rate quote_currency
+	EffectiveDate string `json:"effective_date"`
+	UpdatedBy     string `json:"updated_by,omitempty"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type SetFxRateRequest struct {
+	BaseCurrency  string          `json:"base_currency"`
+	QuoteCurrency string          `json:"quote_currency"`
+	Rate          decimal.Decimal `json:"rate"`           // accepts a JSON number or a decimal string
+	EffectiveDate string          `json:"effective_date"` // optional, defaults to today
+}
+
+type ListFxRatesResponse struct {
+	Rates []*FxRate `json:"rates"`
+}
+
+type FeeSchedule struct {
+	ID            string `json:"id"`
+	GradeID       string `json:"grade_id,omitempty"`
+	Category      string `json:"category,omitempty"`
+	Code          string `json:"code"`
+	Kind          string `json:"kind"`  // FEE | TAX
+	Side          string `json:"side"`  // BUY | SELL | BOTH
+	Basis         string `json:"basis"` // PERCENT | PER_KG | FLAT
+	Rate          string `json:"rate"`
+	Currency      string `json:"currency"`
+	EffectiveDate string `json:"effective_date"`
+	UpdatedBy     string `json:"updated_by,omitempty"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type SetFeeScheduleRequest struct {
+	GradeID       string          `json:"grade_id"` // exactly one of grade_id and category
+	Category      string          `json:"category"`
+	Code          string          `json:"code"`
+	Kind          string          `json:"kind"`
+	Side          string          `json:"side"` // optional, defaults to BOTH
+	Basis         string          `json:"basis"`
+	Rate          decimal.Decimal `json:"rate"`           // accepts a JSON number or a decimal string
+	Currency      string          `json:"currency"`       // optional, defaults to INR
+	EffectiveDate string          `json:"effective_date"` // optional, defaults to today
+}
+
+type ListFeeSchedulesResponse struct {
+	Schedules []*FeeSchedule `json:"schedules"`
+}
+
+// Session is a signed-in device of the caller.
+type Session struct {
+	ID         string `json:"id"`
+	DeviceID   string `json:"device_id"`
+	DeviceName string `json:"device_name,omitempty"`
+	IPAddress  string `json:"ip_address,omitempty"`
+	UserAgent  string `json:"user_agent,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	LastSeenAt string `json:"last_seen_at"`
+	ExpiresAt  string `json:"expires_at"`
+	Current    bool   `json:"current"` // the session making the request
+}
+
+type ListSessionsResponse struct {
+	Sessions []*Session `json:"sessions"`
+}
+
+type ForceLogoutRequest struct {
+	AccountID string `json:"account_id"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked uint32 `json:"revoked"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token          string `json:"token"`
+	NewPassword    string `json:"new_password"`
+	RevokeSessions bool   `json:"revoke_sessions"` // optional; also sign out every session
+}
+
+type ConfirmPasswordResetResponse struct {
+	SessionsRevoked uint32 `json:"sessions_revoked"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type EnrollTOTPRequest struct {
+	ChallengeToken string `json:"challenge_token"` // optional; for an admin that must enrol to sign in
+	DeviceID       string `json:"device_id"`       // required with challenge_token
+}
+
+type EnrollTOTPResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type ConfirmTOTPRequest struct {
+	Code           string `json:"code"`
+	ChallengeToken string `json:"challenge_token"` // optional, as for enrolment
+	DeviceID       string `json:"device_id"`
+	DeviceName     string `json:"device_name"` // optional
+}
+
+type ConfirmTOTPResponse struct {
+	RecoveryCodes []string               `json:"recovery_codes"`
+	Login         *AuthenticatedResponse `json:"login,omitempty"` // set when confirmed with a challenge
+}
+
+type VerifyLoginChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	DeviceID       string `json:"device_id"`
+	DeviceName     string `json:"device_name"`   // optional
+	Code           string `json:"code"`          // a TOTP code, or
+	RecoveryCode   string `json:"recovery_code"` // a recovery code
+}
+
+type DisableTOTPRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -13,6 +13,19 @@ func NewHandler(server *Server) http.Handler {
 	mux.HandleFunc("/accounts/login", server.handleLogin)
 	mux.HandleFunc("/accounts/logout", server.handleLogout)
 	mux.HandleFunc("/accounts/refresh", server.handleRefreshToken)
+	mux.HandleFunc("/accounts/sessions", server.handleListSessions)
+	mux.HandleFunc("/accounts/sessions/", server.handleRevokeSession)
+	mux.HandleFunc("/accounts/sessions/revoke-others", server.handleRevokeOtherSessions)
+	mux.HandleFunc("/accounts/force-logout", server.handleForceLogout)
+	mux.HandleFunc("/accounts/password-reset", server.handleRequestPasswordReset)
+	mux.HandleFunc("/accounts/password-reset/confirm", server.handleConfirmPasswordReset)
+	mux.HandleFunc("/accounts/verify-email", server.handleVerifyEmail)
+	mux.HandleFunc("/accounts/verify-email/send", server.handleSendVerificationEmail)
+	mux.HandleFunc("/accounts/2fa/enroll", server.handleEnrollTOTP)
+	mux.HandleFunc("/accounts/2fa/confirm", server.handleConfirmTOTP)
+	mux.HandleFunc("/accounts/2fa/verify", server.handleVerifyLoginChallenge)
+	mux.HandleFunc("/accounts/2fa/disable", server.handleDisableTOTP)
+	mux.HandleFunc("/accounts/2fa/recovery-codes", server.handleRegenerateRecoveryCodes)
 	mux.HandleFunc("/accounts", server.handleAccounts)
 	mux.HandleFunc("/accounts/info", server.handleGetAccountInfo)
 	mux.HandleFunc("/accounts/", server.handleAccountByID)
@@ -24,7 +37,13 @@ func NewHandler(server *Server) http.Handler {
 	mux.HandleFunc("/grades/", server.handleListGradesByProductId)
 	mux.HandleFunc("/daily-prices", server.handleCreateOrUpdateDailyPrice)
 	mux.HandleFunc("/daily-prices/", server.handleListDailyPricesByGradeId)
+	mux.HandleFunc("/daily-prices/import", server.handleImportDailyPrices)
+	mux.HandleFunc("/daily-prices/submit", server.handleSubmitPriceTick)
+	mux.HandleFunc("/daily-prices/review", server.handleReviewPriceTicks)
 	mux.HandleFunc("/daily-prices/product/today/", server.handleGetTodaysByProductId)
 	mux.HandleFunc("/daily-prices/grade/today/", server.handleGetTodaysByGradeId)
+	mux.HandleFunc("/daily-prices/ticks/", server.handleListPriceTicks)
+	mux.HandleFunc("/fx-rates", server.handleFxRates)
+	mux.HandleFunc("/fee-schedules", server.handleFeeSchedules)
 	return util.LoggingMiddleware(server.logger)(mux)
 }
@@ -5,66 +5,102 @@ import (
 	"encoding/base64"
 	"strings"
 
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
-// AuthInterceptor validates the JWT from the Authorization header and injects claims into the context
-func AuthInterceptor(jwtSecret, basicUser, basicPass string) grpc.UnaryServerInterceptor {
+// AuthInterceptor validates the JWT from the Authorization header against the published
+// signing keys and injects claims into the context. Only access tokens are accepted. When
+// sessions is set, the token's session must also still be signed in; control passes nil
+// because its session interceptor checks its own table.
+func AuthInterceptor(keys KeyResolver, sessions SessionVerifier, basicUser, basicPass string) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		newCtx := context.WithValue(ctx, IsAuthenticatedKey, false)
-		newCtx = context.WithValue(newCtx, IsAdminKey, false)
-
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return handler(newCtx, req)
+		newCtx, err := authContext(ctx, keys, sessions, basicUser, basicPass)
+		if err != nil {
+			return nil, err
 		}
+		return handler(newCtx, req)
+	}
+}
 
-		authHeader := md.Get("authorization")
-		if len(authHeader) == 0 {
-			return handler(newCtx, req)
+// StreamAuthInterceptor is the streaming counterpart of AuthInterceptor
+func StreamAuthInterceptor(keys KeyResolver, sessions SessionVerifier, basicUser, basicPass string) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		newCtx, err := authContext(ss.Context(), keys, sessions, basicUser, basicPass)
+		if err != nil {
+			return err
 		}
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = newCtx
+		return handler(srv, wrapped)
+	}
+}
 
-		headerValue := authHeader[0]
+// authContext resolves the caller from the Authorization metadata
+func authContext(ctx context.Context, keys KeyResolver, sessions SessionVerifier, basicUser, basicPass string) (context.Context, error) {
+	newCtx := context.WithValue(ctx, IsAuthenticatedKey, false)
+	newCtx = context.WithValue(newCtx, IsAdminKey, false)
 
-		if strings.HasPrefix(headerValue, "Bearer ") {
-			tokenString := strings.TrimPrefix(headerValue, "Bearer ")
-			claims, err := ValidateToken(tokenString, jwtSecret)
-			if err != nil {
-				return nil, status.Error(codes.Unauthenticated, err.Error())
-			}
-			newCtx = context.WithValue(newCtx, AccountIDKey, claims.AccountID)
-			newCtx = context.WithValue(newCtx, UserTypeKey, claims.UserType)
-			newCtx = context.WithValue(newCtx, EmailKey, claims.Email)
-			newCtx = context.WithValue(newCtx, IsAuthenticatedKey, true)
-			newCtx = context.WithValue(newCtx, AccessTokenKey, tokenString)
-			if claims.UserType == UserTypeAdmin {
-				newCtx = context.WithValue(newCtx, IsAdminKey, true)
-			}
-			if claims.UserType == UserTypeMerchant {
-				newCtx = context.WithValue(newCtx, IsMerchantKey, true)
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return newCtx, nil
+	}
+
+	authHeader := md.Get("authorization")
+	if len(authHeader) == 0 {
+		return newCtx, nil
+	}
+
+	headerValue := authHeader[0]
+
+	if strings.HasPrefix(headerValue, "Bearer ") {
+		tokenString := strings.TrimPrefix(headerValue, "Bearer ")
+		claims, err := ValidateToken(ctx, tokenString, TokenTypeAccess, keys)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if sessions != nil {
+			if err := sessions.VerifySession(ctx, tokenString); err != nil {
+				return nil, err
 			}
-			return handler(newCtx, req)
 		}
+		newCtx = context.WithValue(newCtx, AccountIDKey, claims.AccountID)
+		newCtx = context.WithValue(newCtx, UserTypeKey, claims.UserType)
+		newCtx = context.WithValue(newCtx, EmailKey, claims.Email)
+		newCtx = context.WithValue(newCtx, IsAuthenticatedKey, true)
+		newCtx = context.WithValue(newCtx, AccessTokenKey, tokenString)
+		if claims.UserType == UserTypeAdmin {
+			newCtx = context.WithValue(newCtx, IsAdminKey, true)
+		}
+		if claims.UserType == UserTypeMerchant {
+			newCtx = context.WithValue(newCtx, IsMerchantKey, true)
+		}
+		return newCtx, nil
+	}
 
-		if strings.HasPrefix(headerValue, "Basic ") {
-			encoded := strings.TrimPrefix(headerValue, "Basic ")
-			decoded, err := base64.StdEncoding.DecodeString(encoded)
-			if err == nil {
-				parts := strings.SplitN(string(decoded), ":", 2)
-				if len(parts) == 2 && parts[0] == basicUser && parts[1] == basicPass {
-					newCtx = context.WithValue(newCtx, IsAuthenticatedKey, true)
-				}
+	if strings.HasPrefix(headerValue, "Basic ") {
+		encoded := strings.TrimPrefix(headerValue, "Basic ")
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err == nil {
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) == 2 && parts[0] == basicUser && parts[1] == basicPass {
+				newCtx = context.WithValue(newCtx, IsAuthenticatedKey, true)
 			}
 		}
-
-		return handler(newCtx, req)
 	}
+
+	return newCtx, nil
 }
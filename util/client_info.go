@@ -0,0 +1,64 @@
+package util
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Metadata the gateways add to describe the HTTP client behind a gRPC call.
+const (
+	ClientIPMetadata        = "x-client-ip"
+	ClientUserAgentMetadata = "x-client-user-agent"
+)
+
+// ClientIP returns the address of the HTTP client: the first X-Forwarded-For entry when a proxy
+// set one, otherwise the connection's remote address. It is informational and can be spoofed.
+func ClientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		if ip := strings.TrimSpace(first); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ForwardClient adds the HTTP client's address and user agent to the outgoing gRPC metadata.
+func ForwardClient(ctx context.Context, r *http.Request) context.Context {
+	return metadata.AppendToOutgoingContext(ctx,
+		ClientIPMetadata, ClientIP(r),
+		ClientUserAgentMetadata, r.UserAgent(),
+	)
+}
+
+// ClientFromContext returns the client address and user agent a gateway forwarded. Without
+// them the address is the gRPC peer's and the user agent is empty.
+func ClientFromContext(ctx context.Context) (string, string) {
+	var ip, userAgent string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(ClientIPMetadata); len(values) > 0 {
+			ip = values[0]
+		}
+		if values := md.Get(ClientUserAgentMetadata); len(values) > 0 {
+			userAgent = values[0]
+		}
+	}
+	if ip == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			ip = p.Addr.String()
+			if host, _, err := net.SplitHostPort(ip); err == nil {
+				ip = host
+			}
+		}
+	}
+	return ip, userAgent
+}
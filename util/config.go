@@ -16,7 +16,12 @@ type Config struct {
 	DBUser               string        `envconfig:"DB_USER" default:"root"`
 	DBPass               string        `envconfig:"DB_PASSWORD" default:"1234"`
 	DBName               string        `envconfig:"DB_NAME" default:"spice_ledger"`
-	JWTSecret            string        `envconfig:"JWT_SECRET" default:"supersecretjwtkey123!"`
+	JWTAlgorithm         string        `envconfig:"JWT_ALGORITHM" default:"RS256"`
+	JWTKeyDir            string        `envconfig:"JWT_KEY_DIR" default:"keys"`
+	JWTKeyRotation       time.Duration `envconfig:"JWT_KEY_ROTATION" default:"720h"`
+	JWTKeyGrace          time.Duration `envconfig:"JWT_KEY_GRACE" default:"168h"`
+	JWKSCacheTTL         time.Duration `envconfig:"JWKS_CACHE_TTL" default:"5m"`
+	SessionCacheTTL      time.Duration `envconfig:"SESSION_CACHE_TTL" default:"15s"`
 	AccessTokenDuration  time.Duration `envconfig:"ACCESS_TOKEN_DURATION" default:"30m"`
 	RefreshTokenDuration time.Duration `envconfig:"REFRESH_TOKEN_DURATION" default:"168h"`
 	BasicAuthUser        string        `envconfig:"BASIC_AUTH_USER" default:"admin"`
@@ -27,6 +32,24 @@ type Config struct {
 	LogLevel             string        `envconfig:"LOG_LEVEL" default:"debug"`
 	AccountGrpcURL       string        `envconfig:"ACCOUNT_GRPC_URL"`
 	MarketGrpcURL        string        `envconfig:"MARKET_GRPC_URL"`
+	EventRetention       int           `envconfig:"EVENT_RETENTION" default:"1024"`
+	PriceMaxMovePercent  float64       `envconfig:"PRICE_MAX_MOVE_PERCENT" default:"20"`
+	PriceValuation       string        `envconfig:"PRICE_VALUATION" default:"LAST_AVAILABLE"`
+	PriceMaxAgeDays      int           `envconfig:"PRICE_MAX_AGE_DAYS" default:"7"`
+	AppURL               string        `envconfig:"APP_URL" default:"http://localhost:3000"`
+	PasswordResetTTL     time.Duration `envconfig:"PASSWORD_RESET_TTL" default:"1h"`
+	EmailVerificationTTL time.Duration `envconfig:"EMAIL_VERIFICATION_TTL" default:"48h"`
+	Mailer               string        `envconfig:"MAILER" default:"file"`
+	MailFrom             string        `envconfig:"MAIL_FROM" default:"SpiceLedger <no-reply@spiceledger.local>"`
+	MailDir              string        `envconfig:"MAIL_DIR" default:"mail"`
+	SMTPHost             string        `envconfig:"SMTP_HOST"`
+	SMTPPort             int           `envconfig:"SMTP_PORT" default:"587"`
+	SMTPUser             string        `envconfig:"SMTP_USER"`
+	SMTPPassword         string        `envconfig:"SMTP_PASSWORD"`
+	TOTPRequiredForAdmin bool          `envconfig:"TOTP_REQUIRED_FOR_ADMINS" default:"false"`
+	TOTPIssuer           string        `envconfig:"TOTP_ISSUER" default:"SpiceLedger"`
+	TOTPEncryptionKey    string        `envconfig:"TOTP_ENCRYPTION_KEY" default:"c3BpY2VsZWRnZXItZGV2LXRvdHAta2V5LTMyYnl0ZXM="`
+	LoginChallengeTTL    time.Duration `envconfig:"LOGIN_CHALLENGE_TTL" default:"5m"`
 }
 
 func LoadConfig() *Config {
@@ -40,16 +63,20 @@ func LoadConfig() *Config {
 }
 
 func (c *Config) Validate() {
+	// A retired signing key must stay published for as long as the tokens it signed live.
+	if c.JWTKeyGrace < c.RefreshTokenDuration || c.JWTKeyGrace < c.AccessTokenDuration {
+		log.Fatal("JWT_KEY_GRACE must be at least ACCESS_TOKEN_DURATION and REFRESH_TOKEN_DURATION")
+	}
 	if c.IsProduction() {
-		if c.JWTSecret == "supersecretjwtkey123!" {
-			log.Fatal("JWT_SECRET must be set to a strong value in production")
-		}
 		if c.DBPass == "1234" {
 			log.Fatal("DB_PASSWORD must be changed from the default in production")
 		}
 		if c.BasicAuthPass == "secret123" {
 			log.Fatal("BASIC_AUTH_PASS must be changed from the default in production")
 		}
+		if c.TOTPEncryptionKey == "c3BpY2VsZWRnZXItZGV2LXRvdHAta2V5LTMyYnl0ZXM=" {
+			log.Fatal("TOTP_ENCRYPTION_KEY must be changed from the default in production")
+		}
 	}
 }
 
@@ -15,4 +15,8 @@ const (
 	IsMerchantKey      ContextKey = "is_merchant"
 	IsAuthenticatedKey ContextKey = "is_authenticated"
 	AccessTokenKey     ContextKey = "access_token"
+	IdempotencyKeyKey  ContextKey = "idempotency_key"
+
+	// IdempotencyKeyHeader carries a client retry key for buy/sell at the gateway.
+	IdempotencyKeyHeader = "Idempotency-Key"
 )
@@ -0,0 +1,113 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Ledger scales match the DECIMAL(15,4) columns: quantities and unit prices keep 4 dp.
+const (
+	QuantityScale int32 = 4
+	PriceScale    int32 = 4
+)
+
+// DefaultCurrency is the currency ledger amounts are booked in.
+const DefaultCurrency = "INR"
+
+// currencyScales holds the minor-unit digits (ISO 4217) money amounts are rounded to.
+var currencyScales = map[string]int32{
+	"INR": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"AED": 2,
+	"SGD": 2,
+	"LKR": 2,
+	"JPY": 0,
+	"KWD": 3,
+	"BHD": 3,
+	"OMR": 3,
+}
+
+// CurrencyScale returns the minor-unit digits of a currency code; unknown codes use 2.
+func CurrencyScale(currency string) int32 {
+	if scale, ok := currencyScales[strings.ToUpper(strings.TrimSpace(currency))]; ok {
+		return scale
+	}
+	return 2
+}
+
+// ParseCurrency normalizes a currency code to upper case and checks it is one the ledger
+// knows. Empty stays empty so callers can apply their default.
+func ParseCurrency(value string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(value))
+	if code == "" {
+		return "", nil
+	}
+	if _, ok := currencyScales[code]; !ok {
+		return "", fmt.Errorf("unsupported currency %q", value)
+	}
+	return code, nil
+}
+
+// BaseUnit is the unit ledger quantities are booked in.
+const BaseUnit = "KG"
+
+// UnitBag is a unit whose size is set per grade.
+const UnitBag = "BAG"
+
+// unitKg holds the kilograms in one fixed-size unit. A BAG is not here: its size is the grade's.
+var unitKg = map[string]decimal.Decimal{
+	"KG":      decimal.NewFromInt(1),
+	"QUINTAL": decimal.NewFromInt(100),
+	"TONNE":   decimal.NewFromInt(1000),
+}
+
+// ParseUnit normalizes a unit of measure to upper case and checks it is one the ledger knows.
+// Empty stays empty so callers can apply their default.
+func ParseUnit(value string) (string, error) {
+	unit := strings.ToUpper(strings.TrimSpace(value))
+	if unit == "" {
+		return "", nil
+	}
+	if _, ok := unitKg[unit]; !ok && unit != UnitBag {
+		return "", fmt.Errorf("unsupported unit %q: must be KG, QUINTAL, TONNE or BAG", value)
+	}
+	return unit, nil
+}
+
+// UnitKg returns the kilograms in one unit of a fixed-size unit; ok is false for BAG.
+func UnitKg(unit string) (decimal.Decimal, bool) {
+	kg, ok := unitKg[unit]
+	return kg, ok
+}
+
+// RoundQuantity rounds a quantity to the ledger scale, half away from zero (as MySQL DECIMAL does).
+func RoundQuantity(d decimal.Decimal) decimal.Decimal {
+	return d.Round(QuantityScale)
+}
+
+// RoundPrice rounds a unit price or average cost to the ledger scale.
+func RoundPrice(d decimal.Decimal) decimal.Decimal {
+	return d.Round(PriceScale)
+}
+
+// RoundMoney rounds a money amount (cost, P&L, value) to the currency's minor unit.
+func RoundMoney(d decimal.Decimal, currency string) decimal.Decimal {
+	return d.Round(CurrencyScale(currency))
+}
+
+// ParseDecimal reads a decimal carried as a string on the wire. Empty means zero.
+func ParseDecimal(field, value string) (decimal.Decimal, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid %s %q: must be a decimal number", field, value)
+	}
+	return d, nil
+}
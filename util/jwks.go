@@ -0,0 +1,252 @@
+package util
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// rsaKeyBits is the modulus size of generated RS256 keys.
+const rsaKeyBits = 2048
+
+// keySetFetchInterval is the least time between two fetches, so a flood of tokens with
+// made-up kids, or an unreachable control service, does not turn into a fetch per call.
+const keySetFetchInterval = 10 * time.Second
+
+// JWK is a public key in JSON Web Key form (RFC 7517). RSA keys set N and E, Ed25519 keys
+// set Crv and X.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK describes a public key as a JWK.
+func NewJWK(kid, algorithm string, publicKey crypto.PublicKey) (JWK, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: algorithm,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: algorithm,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		}, nil
+	}
+	return JWK{}, fmt.Errorf("unsupported public key type %T", publicKey)
+}
+
+// PublicKey decodes the key a JWK describes.
+func (key JWK) PublicKey() (crypto.PublicKey, error) {
+	switch key.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: invalid n: %w", key.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: invalid e: %w", key.Kid, err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: invalid x: %w", key.Kid, err)
+		}
+		if key.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("jwk %s: unsupported curve %q", key.Kid, key.Crv)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("jwk %s: unsupported key type %q", key.Kid, key.Kty)
+}
+
+// GenerateSigningKey creates a new private key for the algorithm under a fresh kid.
+func GenerateSigningKey(algorithm string) (*SigningKey, error) {
+	var privateKey crypto.Signer
+	var err error
+	switch algorithm {
+	case AlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKey{KID: ksuid.New().String(), Algorithm: algorithm, PrivateKey: privateKey}, nil
+}
+
+// EncodePrivateKey returns the key as a PKCS #8 PEM block.
+func EncodePrivateKey(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// DecodePrivateKey parses a PKCS #8 PEM block written by EncodePrivateKey.
+func DecodePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// KeySet caches the public keys published by the control service. Keys are refetched once
+// the cache is older than the TTL, and early when a token names a kid the cache does not
+// have, which is how a freshly rotated key is picked up. If a refetch fails the cached keys
+// keep being used.
+type KeySet struct {
+	fetch  func(ctx context.Context) ([]JWK, error)
+	ttl    time.Duration
+	logger Logger
+
+	refreshMu   sync.Mutex
+	mu          sync.RWMutex
+	keys        []JWK
+	parsed      map[string]publishedKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+type publishedKey struct {
+	key       crypto.PublicKey
+	algorithm string
+}
+
+func NewKeySet(fetch func(ctx context.Context) ([]JWK, error), ttl time.Duration, logger Logger) *KeySet {
+	return &KeySet{
+		fetch:  fetch,
+		ttl:    ttl,
+		logger: logger,
+		parsed: map[string]publishedKey{},
+	}
+}
+
+// PublicKey implements KeyResolver.
+func (set *KeySet) PublicKey(ctx context.Context, kid string) (crypto.PublicKey, string, error) {
+	if key, algorithm, ok := set.lookup(kid); ok && !set.stale() {
+		return key, algorithm, nil
+	}
+
+	set.refresh(ctx, kid)
+
+	if key, algorithm, ok := set.lookup(kid); ok {
+		return key, algorithm, nil
+	}
+	return nil, "", fmt.Errorf("%w: %s", ErrUnknownKey, kid)
+}
+
+// Keys returns the cached key set, refetching it when stale.
+func (set *KeySet) Keys(ctx context.Context) ([]JWK, error) {
+	if set.stale() {
+		set.refresh(ctx, "")
+	}
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	if set.fetchedAt.IsZero() {
+		return nil, errors.New("signing keys are not available")
+	}
+	return set.keys, nil
+}
+
+func (set *KeySet) lookup(kid string) (crypto.PublicKey, string, bool) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	published, ok := set.parsed[kid]
+	return published.key, published.algorithm, ok
+}
+
+func (set *KeySet) stale() bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return time.Since(set.fetchedAt) >= set.ttl
+}
+
+// refresh refetches the keys. missingKID is the kid that prompted it, if any.
+func (set *KeySet) refresh(ctx context.Context, missingKID string) {
+	set.refreshMu.Lock()
+	defer set.refreshMu.Unlock()
+
+	// Another caller may have refreshed while this one waited.
+	if _, _, ok := set.lookup(missingKID); ok && !set.stale() {
+		return
+	}
+	set.mu.Lock()
+	throttled := time.Since(set.attemptedAt) < keySetFetchInterval
+	if !throttled {
+		set.attemptedAt = time.Now()
+	}
+	set.mu.Unlock()
+	if throttled {
+		return
+	}
+
+	keys, err := set.fetch(ctx)
+	if err != nil {
+		set.logger.Security().Warn().Err(err).Str("kid", missingKID).Msg("Could not fetch signing keys")
+		return
+	}
+
+	parsed := make(map[string]publishedKey, len(keys))
+	for _, jwk := range keys {
+		key, err := jwk.PublicKey()
+		if err != nil {
+			set.logger.Security().Warn().Err(err).Msg("Skipping unreadable signing key")
+			continue
+		}
+		parsed[jwk.Kid] = publishedKey{key: key, algorithm: jwk.Alg}
+	}
+
+	set.mu.Lock()
+	set.keys = keys
+	set.parsed = parsed
+	set.fetchedAt = time.Now()
+	set.mu.Unlock()
+}
@@ -0,0 +1,184 @@
+package util
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeJWKS serves a key set that tests change to simulate rotation.
+type fakeJWKS struct {
+	keys  []JWK
+	err   error
+	calls int
+}
+
+func (f *fakeJWKS) fetch(ctx context.Context) ([]JWK, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.keys, nil
+}
+
+func testJWK(t *testing.T, kid string) JWK {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := NewJWK(kid, AlgorithmEdDSA, public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jwk
+}
+
+// age moves the key set's last fetch and last attempt into the past.
+func age(set *KeySet, d time.Duration) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.fetchedAt = set.fetchedAt.Add(-d)
+	set.attemptedAt = set.attemptedAt.Add(-d)
+}
+
+func TestKeySetRotation(t *testing.T) {
+	ctx := context.Background()
+	k1, k2 := testJWK(t, "k1"), testJWK(t, "k2")
+	server := &fakeJWKS{keys: []JWK{k1}}
+	set := NewKeySet(server.fetch, time.Hour, NewLogger("error"))
+
+	resolves := func(kid string) bool {
+		t.Helper()
+		_, algorithm, err := set.PublicKey(ctx, kid)
+		if err != nil && !errors.Is(err, ErrUnknownKey) {
+			t.Fatalf("PublicKey(%s): %v", kid, err)
+		}
+		if err == nil && algorithm != AlgorithmEdDSA {
+			t.Errorf("PublicKey(%s) algorithm = %s", kid, algorithm)
+		}
+		return err == nil
+	}
+
+	steps := []struct {
+		name      string
+		before    func()
+		kid       string
+		resolves  bool
+		wantCalls int
+	}{
+		{name: "first lookup fetches", kid: "k1", resolves: true, wantCalls: 1},
+		{name: "cached within the ttl", kid: "k1", resolves: true, wantCalls: 1},
+		{
+			name:   "rotated kid within the fetch interval is not fetched",
+			before: func() { server.keys = []JWK{k2, k1} },
+			kid:    "k2", resolves: false, wantCalls: 1,
+		},
+		{
+			name:   "rotated kid is fetched early once the interval passed",
+			before: func() { age(set, keySetFetchInterval) },
+			kid:    "k2", resolves: true, wantCalls: 2,
+		},
+		{name: "retired key still verifies through its grace period", kid: "k1", resolves: true, wantCalls: 2},
+		{
+			name:   "made-up kids do not refetch on every call",
+			before: func() { age(set, keySetFetchInterval) },
+			kid:    "bogus", resolves: false, wantCalls: 3,
+		},
+		{name: "second made-up kid is throttled", kid: "bogus-2", resolves: false, wantCalls: 3},
+		{
+			name: "failed refetch keeps the cached keys",
+			before: func() {
+				server.err = errors.New("control unavailable")
+				age(set, time.Hour)
+			},
+			kid: "k1", resolves: true, wantCalls: 4,
+		},
+		{
+			name: "expired key drops out at the next refetch",
+			before: func() {
+				server.err = nil
+				server.keys = []JWK{k2}
+				age(set, time.Hour)
+			},
+			kid: "k1", resolves: false, wantCalls: 5,
+		},
+		{name: "signing key still resolves", kid: "k2", resolves: true, wantCalls: 5},
+	}
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		if got := resolves(step.kid); got != step.resolves {
+			t.Errorf("%s: resolved %s = %v, want %v", step.name, step.kid, got, step.resolves)
+		}
+		if server.calls != step.wantCalls {
+			t.Errorf("%s: %d fetches, want %d", step.name, server.calls, step.wantCalls)
+		}
+	}
+
+	keys, err := set.Keys(ctx)
+	if err != nil || !reflect.DeepEqual(keys, []JWK{k2}) {
+		t.Errorf("Keys() = %v, %v; want only k2", keys, err)
+	}
+}
+
+func TestKeySetUnavailable(t *testing.T) {
+	server := &fakeJWKS{err: errors.New("control unavailable")}
+	set := NewKeySet(server.fetch, time.Hour, NewLogger("error"))
+	if _, err := set.Keys(context.Background()); err == nil {
+		t.Error("Keys() succeeded without a fetch")
+	}
+	if _, _, err := set.PublicKey(context.Background(), "k1"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("PublicKey err = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestJWKRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		algorithm string
+		public    any
+	}{
+		{"rsa", AlgorithmRS256, &rsaKey.PublicKey},
+		{"ed25519", AlgorithmEdDSA, edPublic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwk, err := NewJWK("kid", tt.algorithm, tt.public)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := jwk.PublicKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.public) {
+				t.Errorf("decoded %v, want %v", got, tt.public)
+			}
+		})
+	}
+
+	for _, bad := range []JWK{
+		{Kid: "ec", Kty: "EC"},
+		{Kid: "x448", Kty: "OKP", Crv: "X448", X: "AAAA"},
+		{Kid: "bad-x", Kty: "OKP", Crv: "Ed25519", X: "!"},
+	} {
+		if _, err := bad.PublicKey(); err == nil {
+			t.Errorf("jwk %s decoded", bad.Kid)
+		}
+	}
+}
@@ -1,44 +1,117 @@
 package util
 
 import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/segmentio/ksuid"
 )
 
+// Signing algorithms the services issue and accept.
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// Token types carried in the typ claim. Only access tokens are accepted as bearer tokens;
+// refresh tokens are only accepted by RefreshToken.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// ErrUnknownKey is returned when a token names a kid that is not, or is no longer, published.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// ErrWrongTokenType is returned when a token is presented where another type is expected,
+// such as a refresh token sent as a bearer token.
+var ErrWrongTokenType = errors.New("wrong token type")
+
 type JWTClaims struct {
 	AccountID string `json:"account_id"`
 	UserType  string `json:"user_type"`
 	Email     string `json:"email"`
+	TokenType string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(accountID, userType, email, secret string, ttl time.Duration) (string, error) {
+// SigningKey is a private key that mints tokens. Only the control service holds these.
+type SigningKey struct {
+	KID        string
+	Algorithm  string
+	PrivateKey crypto.Signer
+}
+
+// KeyResolver returns the public key and algorithm published under a kid.
+type KeyResolver interface {
+	PublicKey(ctx context.Context, kid string) (crypto.PublicKey, string, error)
+}
+
+func signingMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	}
+	return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+}
+
+func GenerateToken(accountID, userType, email, tokenType string, key *SigningKey, ttl time.Duration) (string, error) {
+	method, err := signingMethod(key.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
 	claims := JWTClaims{
 		AccountID: accountID,
 		UserType:  userType,
 		Email:     email,
+		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// A unique ID keeps two tokens issued in the same second to one account distinct.
+			ID:        ksuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = key.KID
+	return token.SignedString(key.PrivateKey)
 }
 
-func ValidateToken(tokenString, secret string) (*JWTClaims, error) {
+// ValidateToken verifies a token against the key its kid header names. The token's alg must
+// match the algorithm the key is published with, and its typ claim must be tokenType.
+func ValidateToken(ctx context.Context, tokenString, tokenType string, keys KeyResolver) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("token has no kid header")
+		}
+		publicKey, algorithm, err := keys.PublicKey(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != algorithm {
+			return nil, fmt.Errorf("token alg %s does not match key %s", token.Method.Alg(), kid)
+		}
+		return publicKey, nil
+	}, jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA}))
 
 	if err != nil {
 		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+		if claims.TokenType != tokenType {
+			return nil, ErrWrongTokenType
+		}
 		return claims, nil
 	}
 
@@ -0,0 +1,129 @@
+package util
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// staticKeys publishes one key under its kid.
+type staticKeys struct {
+	kid       string
+	algorithm string
+	public    crypto.PublicKey
+}
+
+func (k staticKeys) PublicKey(ctx context.Context, kid string) (crypto.PublicKey, string, error) {
+	if kid != k.kid {
+		return nil, "", ErrUnknownKey
+	}
+	return k.public, k.algorithm, nil
+}
+
+func newTestSigningKey(t *testing.T, kid string) (*SigningKey, staticKeys) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &SigningKey{KID: kid, Algorithm: AlgorithmEdDSA, PrivateKey: private},
+		staticKeys{kid: kid, algorithm: AlgorithmEdDSA, public: public}
+}
+
+func TestValidateTokenType(t *testing.T) {
+	key, keys := newTestSigningKey(t, "k1")
+	tests := []struct {
+		name    string
+		issued  string
+		want    string
+		wantErr error
+	}{
+		{"access as access", TokenTypeAccess, TokenTypeAccess, nil},
+		{"refresh as refresh", TokenTypeRefresh, TokenTypeRefresh, nil},
+		{"refresh as bearer", TokenTypeRefresh, TokenTypeAccess, ErrWrongTokenType},
+		{"access as refresh", TokenTypeAccess, TokenTypeRefresh, ErrWrongTokenType},
+		{"untyped token", "", TokenTypeAccess, ErrWrongTokenType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := GenerateToken("acc-1", UserTypeMerchant, "m@example.com", tt.issued, key, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims, err := ValidateToken(context.Background(), token, tt.want, keys)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateToken error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (claims.AccountID != "acc-1" || claims.TokenType != tt.issued) {
+				t.Errorf("claims = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestValidateTokenRejectsUnknownKeyAndExpiry(t *testing.T) {
+	key, keys := newTestSigningKey(t, "k1")
+	other, _ := newTestSigningKey(t, "k2")
+
+	foreign, err := GenerateToken("acc-1", UserTypeMerchant, "", TokenTypeAccess, other, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateToken(context.Background(), foreign, TokenTypeAccess, keys); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("unknown kid: error = %v, want %v", err, ErrUnknownKey)
+	}
+
+	expired, err := GenerateToken("acc-1", UserTypeMerchant, "", TokenTypeAccess, key, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateToken(context.Background(), expired, TokenTypeAccess, keys); err == nil {
+		t.Error("expired token accepted")
+	}
+}
+
+func TestAuthContextRejectsRefreshBearer(t *testing.T) {
+	key, keys := newTestSigningKey(t, "k1")
+	refresh, err := GenerateToken("acc-1", UserTypeMerchant, "", TokenTypeRefresh, key, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	access, err := GenerateToken("acc-1", UserTypeMerchant, "", TokenTypeAccess, key, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		token  string
+		wantOK bool
+	}{
+		{"access token", access, true},
+		{"refresh token", refresh, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+tt.token))
+			newCtx, err := authContext(ctx, keys, nil, "", "")
+			if tt.wantOK {
+				if err != nil {
+					t.Fatalf("authContext error = %v", err)
+				}
+				if id, _ := newCtx.Value(AccountIDKey).(string); id != "acc-1" {
+					t.Errorf("account id = %q", id)
+				}
+				return
+			}
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("authContext error = %v, want Unauthenticated", err)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ type Logger interface {
 	Transport() *zerolog.Logger
 	Database() *zerolog.Logger
 	Service() *zerolog.Logger
+	// Security logs incidents such as a replayed refresh token, for alerting.
+	Security() *zerolog.Logger
 }
 
 type zerologLogger struct {
@@ -91,6 +93,11 @@ func (l *zerologLogger) Service() *zerolog.Logger {
 	return &sub
 }
 
+func (l *zerologLogger) Security() *zerolog.Logger {
+	sub := l.logger.With().Str("layer", "security").Logger()
+	return &sub
+}
+
 // UnaryServerInterceptor returns a new unary server interceptor that logs gRPC requests
 func UnaryServerInterceptor(logger Logger) grpc.UnaryServerInterceptor {
 	return func(
@@ -126,3 +133,34 @@ func UnaryServerInterceptor(logger Logger) grpc.UnaryServerInterceptor {
 		return resp, err
 	}
 }
+
+// StreamServerInterceptor logs gRPC streams once they end
+func StreamServerInterceptor(logger Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		duration := time.Since(start)
+
+		st, _ := status.FromError(err)
+
+		var logEvent *zerolog.Event
+		if err != nil {
+			logEvent = logger.Transport().Error().Err(err)
+		} else {
+			logEvent = logger.Transport().Info()
+		}
+
+		logEvent.
+			Str("method", info.FullMethod).
+			Str("duration", duration.String()).
+			Str("code", st.Code().String()).
+			Msg("STREAM")
+
+		return err
+	}
+}
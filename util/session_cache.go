@@ -0,0 +1,90 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SessionVerifier confirms that the session behind an access token is still signed in.
+// AuthInterceptor calls it after the token's signature checks out.
+type SessionVerifier interface {
+	VerifySession(ctx context.Context, accessToken string) error
+}
+
+// SessionCache verifies sessions with the control service and remembers each answer for the
+// TTL, so a revoked session is refused within the TTL without a control call per request.
+// Tokens are cached by hash. If control cannot be reached the call is refused: a session the
+// cache cannot vouch for is not trusted.
+type SessionCache struct {
+	check  func(ctx context.Context, accessToken string) (bool, error)
+	ttl    time.Duration
+	logger Logger
+
+	mu      sync.Mutex
+	entries map[string]sessionEntry
+	sweptAt time.Time
+}
+
+type sessionEntry struct {
+	active    bool
+	checkedAt time.Time
+}
+
+func NewSessionCache(check func(ctx context.Context, accessToken string) (bool, error), ttl time.Duration, logger Logger) *SessionCache {
+	return &SessionCache{
+		check:   check,
+		ttl:     ttl,
+		logger:  logger,
+		entries: map[string]sessionEntry{},
+		sweptAt: time.Now(),
+	}
+}
+
+// VerifySession implements SessionVerifier.
+func (cache *SessionCache) VerifySession(ctx context.Context, accessToken string) error {
+	key := HashToken(accessToken)
+	active, ok := cache.lookup(key)
+	if !ok {
+		var err error
+		if active, err = cache.check(ctx, accessToken); err != nil {
+			cache.logger.Security().Warn().Err(err).Msg("Could not check session")
+			return status.Error(codes.Unavailable, "session check unavailable")
+		}
+		cache.store(key, active)
+	}
+	if !active {
+		return status.Error(codes.Unauthenticated, "session revoked or invalid")
+	}
+	return nil
+}
+
+func (cache *SessionCache) lookup(key string) (bool, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	entry, ok := cache.entries[key]
+	if !ok || time.Since(entry.checkedAt) >= cache.ttl {
+		return false, false
+	}
+	return entry.active, true
+}
+
+// store records an answer and drops expired ones at most once per TTL, so the cache holds
+// roughly the tokens seen in the last two TTLs.
+func (cache *SessionCache) store(key string, active bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	now := time.Now()
+	if now.Sub(cache.sweptAt) >= cache.ttl {
+		for k, entry := range cache.entries {
+			if now.Sub(entry.checkedAt) >= cache.ttl {
+				delete(cache.entries, k)
+			}
+		}
+		cache.sweptAt = now
+	}
+	cache.entries[key] = sessionEntry{active: active, checkedAt: now}
+}
@@ -0,0 +1,118 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeSessions answers session checks from a map and counts the calls.
+type fakeSessions struct {
+	active map[string]bool
+	err    error
+	calls  int
+}
+
+func (f *fakeSessions) check(ctx context.Context, accessToken string) (bool, error) {
+	f.calls++
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.active[accessToken], nil
+}
+
+func TestSessionCacheVerifySession(t *testing.T) {
+	tests := []struct {
+		name     string
+		active   map[string]bool
+		err      error
+		token    string
+		wantCode codes.Code
+	}{
+		{"active session", map[string]bool{"t1": true}, nil, "t1", codes.OK},
+		{"revoked session", map[string]bool{"t1": false}, nil, "t1", codes.Unauthenticated},
+		{"unknown session", map[string]bool{}, nil, "t1", codes.Unauthenticated},
+		{"control unreachable", nil, errors.New("connection refused"), "t1", codes.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := &fakeSessions{active: tt.active, err: tt.err}
+			cache := NewSessionCache(sessions.check, time.Minute, NewLogger("error"))
+			err := cache.VerifySession(context.Background(), tt.token)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("VerifySession code = %v, want %v (err %v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestSessionCacheRechecksAfterTTL(t *testing.T) {
+	sessions := &fakeSessions{active: map[string]bool{"t1": true}}
+	cache := NewSessionCache(sessions.check, 20*time.Millisecond, NewLogger("error"))
+	ctx := context.Background()
+
+	if err := cache.VerifySession(ctx, "t1"); err != nil {
+		t.Fatal(err)
+	}
+	// Revoked on control, but the cached answer stands until the TTL passes.
+	sessions.active["t1"] = false
+	if err := cache.VerifySession(ctx, "t1"); err != nil {
+		t.Fatalf("within TTL: %v", err)
+	}
+	if sessions.calls != 1 {
+		t.Errorf("control calls = %d, want 1", sessions.calls)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if err := cache.VerifySession(ctx, "t1"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("after TTL: error = %v, want Unauthenticated", err)
+	}
+	if sessions.calls != 2 {
+		t.Errorf("control calls = %d, want 2", sessions.calls)
+	}
+}
+
+func TestSessionCacheFailureNotCached(t *testing.T) {
+	sessions := &fakeSessions{active: map[string]bool{"t1": true}, err: errors.New("down")}
+	cache := NewSessionCache(sessions.check, time.Minute, NewLogger("error"))
+	ctx := context.Background()
+
+	if err := cache.VerifySession(ctx, "t1"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("error = %v, want Unavailable", err)
+	}
+	sessions.err = nil
+	if err := cache.VerifySession(ctx, "t1"); err != nil {
+		t.Fatalf("after recovery: %v", err)
+	}
+}
+
+func TestAuthContextChecksSession(t *testing.T) {
+	key, keys := newTestSigningKey(t, "k1")
+	token, err := GenerateToken("acc-1", UserTypeMerchant, "", TokenTypeAccess, key, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name     string
+		active   bool
+		wantCode codes.Code
+	}{
+		{"signed in", true, codes.OK},
+		{"force logged out", false, codes.Unauthenticated},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := &fakeSessions{active: map[string]bool{token: tt.active}}
+			cache := NewSessionCache(sessions.check, time.Minute, NewLogger("error"))
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+			_, err := authContext(ctx, keys, cache, "", "")
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("authContext code = %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
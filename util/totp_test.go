@@ -0,0 +1,102 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the RFC 6238 SHA1 test key "12345678901234567890" in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFCVectors(t *testing.T) {
+	// The RFC lists 8-digit codes; these are their last 6 digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := TOTPCode(rfcSecret, TOTPStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+	if got, _ := TOTPCode(strings.ToLower(rfcSecret), TOTPStep(time.Unix(59, 0))); got != "287082" {
+		t.Errorf("lower-case secret gave %s", got)
+	}
+	if _, err := TOTPCode("not base32!", 1); err == nil {
+		t.Error("invalid secret accepted")
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	now := TOTPStep(at)
+	code := func(step int64) string {
+		c, err := TOTPCode(rfcSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name      string
+		code      string
+		afterStep int64
+		wantStep  int64
+		wantOK    bool
+	}{
+		{name: "current step", code: code(now), wantStep: now, wantOK: true},
+		{name: "one step behind is within skew", code: code(now - 1), wantStep: now - 1, wantOK: true},
+		{name: "one step ahead is within skew", code: code(now + 1), wantStep: now + 1, wantOK: true},
+		{name: "two steps behind is outside skew", code: code(now - 2)},
+		{name: "two steps ahead is outside skew", code: code(now + 2)},
+		{name: "spaces are ignored", code: " " + code(now)[:3] + " " + code(now)[3:] + " ", wantStep: now, wantOK: true},
+		{name: "wrong code", code: "000000"},
+		{name: "too short", code: code(now)[:5]},
+		{name: "too long", code: code(now) + "0"},
+		{name: "empty", code: ""},
+		{name: "replay of the recorded step", code: code(now), afterStep: now},
+		{name: "earlier step after a later one was used", code: code(now - 1), afterStep: now},
+		{name: "later step after an earlier one was used", code: code(now + 1), afterStep: now, wantStep: now + 1, wantOK: true},
+		{name: "current step after the previous one was used", code: code(now), afterStep: now - 1, wantStep: now, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(rfcSecret, tt.code, at, tt.afterStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPInvalidSecret(t *testing.T) {
+	if _, ok := ValidateTOTP("not base32!", "123456", time.Now(), 0); ok {
+		t.Error("code accepted for an invalid secret")
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	code, err := GenerateRecoveryCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != 11 || code[5] != '-' {
+		t.Fatalf("recovery code %q is not XXXXX-XXXXX", code)
+	}
+	typed := " " + strings.ToLower(code[:5]) + " " + code[6:] + " "
+	if NormalizeRecoveryCode(typed) != NormalizeRecoveryCode(code) {
+		t.Errorf("%q and %q normalize differently", typed, code)
+	}
+}